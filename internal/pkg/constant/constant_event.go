@@ -4,8 +4,20 @@ type EventType string
 
 const (
 	EventTypeRefundSuccessed EventType = "refund_succeeded"
+
+	// Version schema payload của event refund_succeeded, tăng khi payload thay đổi không tương thích
+	EventVersionRefundSucceeded = 1
+
+	// Domain events của order-service, mọi loại event được publish lên KafkaTopicOrderEvents
+	EventTypeOrderCreated       EventType = "order_created"
+	EventTypeOrderStatusChanged EventType = "order_status_changed"
+	EventTypeOrderCanceled      EventType = "order_canceled"
 )
 
+// KafkaTopicOrderEvents chứa mọi domain event của order-service với key là order_id, nên các event của cùng
+// một đơn nằm trên cùng partition và được consumer nhận đúng thứ tự. Consumer phân biệt loại event qua event_type.
+const KafkaTopicOrderEvents = "order_events"
+
 type KafkaConsumerGroupName string
 
 const (
@@ -2,11 +2,13 @@ package converter
 
 import (
 	"log"
+	"math/big"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 )
 
 // UUID conversions
@@ -169,6 +171,47 @@ func PgNumericToFloat64(numeric pgtype.Numeric) float64 {
 	return *ptr
 }
 
+// Money conversions
+
+// PgNumericToMoney converts a NUMERIC amount in major units to money.Money without going through float64.
+// Digits beyond the currency's minor unit are rounded half away from zero; NULL becomes zero.
+func PgNumericToMoney(numeric pgtype.Numeric, currency string) money.Money {
+	if !numeric.Valid || numeric.Int == nil || numeric.NaN {
+		return money.Zero(currency)
+	}
+
+	value := new(big.Rat).SetInt(numeric.Int)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(numeric.Exp))), nil))
+	if numeric.Exp >= 0 {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	m, err := money.FromRat(value, currency)
+	if err != nil {
+		log.Printf("Error converting pgtype.Numeric to money: %v", err)
+		return money.Zero(currency)
+	}
+	return m
+}
+
+// MoneyToPgNumeric converts money.Money to an exact NUMERIC amount in major units.
+func MoneyToPgNumeric(m money.Money) pgtype.Numeric {
+	return pgtype.Numeric{
+		Int:   big.NewInt(m.Amount),
+		Exp:   int32(-money.Exponent(m.Currency)),
+		Valid: true,
+	}
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // Time pointer conversions
 func PgTimeToTimePtr(t pgtype.Timestamptz) *time.Time {
 	if t.Valid {
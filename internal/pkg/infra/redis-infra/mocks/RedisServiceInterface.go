@@ -1326,6 +1326,64 @@ func (_c *RedisServiceInterface_SetJSON_Call) RunAndReturn(run func(string, inte
 	return _c
 }
 
+// SetNX provides a mock function with given fields: key, value, expiration
+func (_m *RedisServiceInterface) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	ret := _m.Called(key, value, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetNX")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) (bool, error)); ok {
+		return rf(key, value, expiration)
+	}
+	if rf, ok := ret.Get(0).(func(string, interface{}, time.Duration) bool); ok {
+		r0 = rf(key, value, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, interface{}, time.Duration) error); ok {
+		r1 = rf(key, value, expiration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisServiceInterface_SetNX_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetNX'
+type RedisServiceInterface_SetNX_Call struct {
+	*mock.Call
+}
+
+// SetNX is a helper method to define mock.On call
+//   - key string
+//   - value interface{}
+//   - expiration time.Duration
+func (_e *RedisServiceInterface_Expecter) SetNX(key interface{}, value interface{}, expiration interface{}) *RedisServiceInterface_SetNX_Call {
+	return &RedisServiceInterface_SetNX_Call{Call: _e.mock.On("SetNX", key, value, expiration)}
+}
+
+func (_c *RedisServiceInterface_SetNX_Call) Run(run func(key string, value interface{}, expiration time.Duration)) *RedisServiceInterface_SetNX_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(interface{}), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RedisServiceInterface_SetNX_Call) Return(_a0 bool, _a1 error) *RedisServiceInterface_SetNX_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RedisServiceInterface_SetNX_Call) RunAndReturn(run func(string, interface{}, time.Duration) (bool, error)) *RedisServiceInterface_SetNX_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetTTL provides a mock function with given fields: key, expiration
 func (_m *RedisServiceInterface) SetTTL(key string, expiration time.Duration) error {
 	ret := _m.Called(key, expiration)
@@ -18,6 +18,7 @@ type RedisServiceInterface interface {
 
 	// Basic Operations
 	Set(key string, value interface{}, expiration time.Duration) error
+	SetNX(key string, value interface{}, expiration time.Duration) (bool, error)
 	Get(key string) (string, error)
 	Delete(key string) error
 	Exists(key string) (bool, error)
@@ -96,6 +97,12 @@ func (r *RedisService) Set(key string, value interface{}, expiration time.Durati
 	return r.client.Set(r.ctx, key, value, expiration).Err()
 }
 
+// SetNX stores a key-value pair only if the key does not exist yet.
+// Returns true if the key was set.
+func (r *RedisService) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(r.ctx, key, value, expiration).Result()
+}
+
 // Get retrieves a value by key
 func (r *RedisService) Get(key string) (string, error) {
 	return r.client.Get(r.ctx, key).Result()
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	redis_infra "github.com/toji-dev/go-shop/internal/pkg/infra/redis-infra"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	DefaultIdempotencyTTL     = 24 * time.Hour
+	DefaultIdempotencyLockTTL = 1 * time.Minute
+	maxIdempotencyKeyLength   = 255
+
+	idempotencyStatusInProgress = "in_progress"
+	idempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyConfig cấu hình cho IdempotencyMiddleware.
+// TTL là thời gian lưu response đã hoàn thành, LockTTL là thời gian giữ khoá khi request đang xử lý
+// (để khoá tự hết hạn nếu service bị crash giữa chừng).
+type IdempotencyConfig struct {
+	TTL     time.Duration
+	LockTTL time.Duration
+}
+
+type idempotencyRecord struct {
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// idempotencyResponseWriter giữ lại response body để lưu vào Redis sau khi handler chạy xong.
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware cho phép client gửi lại request có cùng header Idempotency-Key mà không tạo ra tác dụng phụ lần hai.
+// Middleware phải chạy sau middleware xác thực vì key được phân biệt theo user.
+//   - Lần đầu: lưu fingerprint (method + path + body) với trạng thái in_progress, chạy handler rồi lưu lại response.
+//   - Request trùng khi lần đầu chưa xong: 409.
+//   - Cùng key nhưng body khác: 422.
+//   - Request trùng khi đã xong: trả lại đúng response đã lưu kèm header Idempotent-Replayed.
+//
+// Request không có header Idempotency-Key được xử lý như bình thường. Response 5xx và lỗi trả qua c.Error
+// không được lưu để client có thể thử lại.
+func IdempotencyMiddleware(store redis_infra.RedisServiceInterface, cfg IdempotencyConfig) gin.HandlerFunc {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultIdempotencyTTL
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = DefaultIdempotencyLockTTL
+	}
+
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid Idempotency-Key header", "Idempotency-Key must not exceed 255 characters")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.BadRequest(c, string(apperror.CodeBadRequest), "Failed to read request body", err.Error())
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		userID := c.GetString(constant.ContextKeyUserID)
+		if userID == "" {
+			userID = "anonymous"
+		}
+		redisKey := "idempotency:" + userID + ":" + idempotencyKey
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		lock, _ := json.Marshal(idempotencyRecord{
+			Status:      idempotencyStatusInProgress,
+			Fingerprint: fingerprint,
+		})
+		acquired, err := store.SetNX(redisKey, lock, cfg.LockTTL)
+		if err != nil {
+			// Redis lỗi thì vẫn xử lý request thay vì chặn toàn bộ luồng đặt hàng/thanh toán
+			log.Printf("[Idempotency] Failed to acquire key %s: %v. Processing request without idempotency.", redisKey, err)
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			handleDuplicateRequest(c, store, redisKey, fingerprint)
+			return
+		}
+
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		// Lỗi được đẩy qua c.Error sẽ do ErrorHandler ghi response sau khi middleware này kết thúc,
+		// nên không có response để lưu: nhả khoá để client có thể thử lại.
+		statusCode := writer.Status()
+		if (len(c.Errors) > 0 && !writer.Written()) || statusCode >= http.StatusInternalServerError {
+			if err := store.Delete(redisKey); err != nil {
+				log.Printf("[Idempotency] Failed to release key %s: %v", redisKey, err)
+			}
+			return
+		}
+
+		record := idempotencyRecord{
+			Status:      idempotencyStatusCompleted,
+			Fingerprint: fingerprint,
+			StatusCode:  statusCode,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := store.SetJSON(redisKey, record, cfg.TTL); err != nil {
+			log.Printf("[Idempotency] Failed to store response for key %s: %v", redisKey, err)
+		}
+	}
+}
+
+func handleDuplicateRequest(c *gin.Context, store redis_infra.RedisServiceInterface, redisKey string, fingerprint string) {
+	var record idempotencyRecord
+	if err := store.GetJSON(redisKey, &record); err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Khoá vừa hết hạn hoặc vừa bị xoá, client có thể thử lại ngay
+			response.Conflict(c, string(apperror.CodeConflict), "A request with this Idempotency-Key is being processed, please retry")
+			c.Abort()
+			return
+		}
+		log.Printf("[Idempotency] Failed to read key %s: %v", redisKey, err)
+		response.InternalServerError(c, string(apperror.CodeInternal), "Failed to check Idempotency-Key")
+		c.Abort()
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		response.UnprocessableEntity(c, string(apperror.CodeBadRequest), "Idempotency-Key has already been used with a different request", "reuse the original request body or use a new Idempotency-Key")
+		c.Abort()
+		return
+	}
+
+	if record.Status != idempotencyStatusCompleted {
+		response.Conflict(c, string(apperror.CodeConflict), "A request with this Idempotency-Key is still being processed")
+		c.Abort()
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.StatusCode, record.ContentType, record.Body)
+	c.Abort()
+}
+
+func requestFingerprint(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,179 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/infra/redis-infra/mocks"
+	"github.com/toji-dev/go-shop/internal/pkg/middleware"
+)
+
+const testIdempotencyKey = "order-create-1"
+
+// newIdempotencyStore trả về mock Redis lưu dữ liệu trong bộ nhớ, SetNX được thực hiện nguyên tử như Redis thật.
+func newIdempotencyStore(t *testing.T) *mocks.RedisServiceInterface {
+	var mu sync.Mutex
+	data := map[string][]byte{}
+
+	store := &mocks.RedisServiceInterface{}
+	store.EXPECT().SetNX(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(key string, value interface{}, expiration time.Duration) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := data[key]; ok {
+				return false, nil
+			}
+			data[key] = value.([]byte)
+			return true, nil
+		}).Maybe()
+	store.EXPECT().GetJSON(mock.Anything, mock.Anything).
+		RunAndReturn(func(key string, dest interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			raw, ok := data[key]
+			if !ok {
+				return redis.Nil
+			}
+			return json.Unmarshal(raw, dest)
+		}).Maybe()
+	store.EXPECT().SetJSON(mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(key string, value interface{}, expiration time.Duration) error {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			data[key] = raw
+			return nil
+		}).Maybe()
+	store.EXPECT().Delete(mock.Anything).
+		RunAndReturn(func(key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(data, key)
+			return nil
+		}).Maybe()
+
+	t.Cleanup(func() { store.AssertExpectations(t) })
+	return store
+}
+
+func newIdempotencyRouter(store *mocks.RedisServiceInterface, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(constant.ContextKeyUserID, "user-1")
+		c.Next()
+	})
+	router.POST("/orders", middleware.IdempotencyMiddleware(store, middleware.IdempotencyConfig{}), handler)
+	return router
+}
+
+func sendIdempotentRequest(router *gin.Engine, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestIdempotencyMiddleware_ReplaysCompletedRequest(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotencyRouter(newIdempotencyStore(t), func(c *gin.Context) {
+		n := calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"order_id": "order-1", "call": n})
+	})
+
+	first := sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`)
+	second := sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`)
+
+	assert.Equal(t, int32(1), calls.Load(), "handler must run only once")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+}
+
+func TestIdempotencyMiddleware_RejectsKeyReusedWithDifferentBody(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotencyRouter(newIdempotencyStore(t), func(c *gin.Context) {
+		calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"order_id": "order-1"})
+	})
+
+	first := sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`)
+	second := sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-2"}`)
+
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+	assert.Empty(t, second.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, int32(1), calls.Load(), "handler must not run for a mismatched body")
+
+	// Key khác với body mới vẫn được xử lý bình thường
+	third := sendIdempotentRequest(router, "order-create-2", `{"cart_id":"cart-2"}`)
+	assert.Equal(t, http.StatusCreated, third.Code)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotencyMiddleware_RejectsConcurrentInFlightRequests(t *testing.T) {
+	const duplicates = 5
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := newIdempotencyRouter(newIdempotencyStore(t), func(c *gin.Context) {
+		calls.Add(1)
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"order_id": "order-1"})
+	})
+
+	firstDone := make(chan *httptest.ResponseRecorder)
+	go func() {
+		firstDone <- sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`)
+	}()
+	<-started
+
+	// Các request trùng đến trong lúc request đầu còn đang xử lý đều bị từ chối
+	var wg sync.WaitGroup
+	codes := make([]int, duplicates)
+	for i := 0; i < duplicates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`).Code
+		}(i)
+	}
+	wg.Wait()
+
+	close(release)
+	first := <-firstDone
+
+	for i, code := range codes {
+		assert.Equal(t, http.StatusConflict, code, "duplicate request %d", i)
+	}
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, int32(1), calls.Load(), "handler must run only once")
+
+	// Sau khi request đầu hoàn thành, request trùng nhận lại response đã lưu
+	replayed := sendIdempotentRequest(router, testIdempotencyKey, `{"cart_id":"cart-1"}`)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get(middleware.IdempotentReplayedHeader))
+}
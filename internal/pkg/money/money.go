@@ -0,0 +1,210 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch được trả về khi cộng / trừ / so sánh hai số tiền khác loại tiền tệ.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// DefaultCurrency là tiền tệ dùng khi dữ liệu cũ không lưu currency.
+const DefaultCurrency = "USD"
+
+// zeroDecimalCurrencies là các tiền tệ không có đơn vị lẻ, 1 đơn vị nhỏ nhất = 1 đơn vị tiền.
+var zeroDecimalCurrencies = map[string]bool{
+	"VND": true,
+	"JPY": true,
+	"KRW": true,
+	"CLP": true,
+	"ISK": true,
+}
+
+// Money là số tiền chính xác, Amount tính bằng đơn vị nhỏ nhất của Currency (xu với USD, đồng với VND).
+// Mọi phép tính tiền trong hệ thống dùng Money thay cho float64 để tổng tiền khớp nhau giữa các service.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New tạo Money từ số đơn vị nhỏ nhất, currency rỗng được thay bằng DefaultCurrency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: normalizeCurrency(currency)}
+}
+
+// Zero trả về số tiền 0 của currency.
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// FromMajor chuyển số tiền dạng thập phân (ví dụ 12.34) sang Money, làm tròn nửa lên (xa số 0) tới đơn vị nhỏ nhất.
+// Chỉ dùng ở biên hệ thống nơi dữ liệu vào vẫn là số thực (JSON, cổng thanh toán).
+func FromMajor(value float64, currency string) Money {
+	currency = normalizeCurrency(currency)
+	scaled := value * math.Pow10(Exponent(currency))
+	return Money{Amount: int64(math.Round(scaled)), Currency: currency}
+}
+
+// Parse chuyển chuỗi thập phân như "12.345" sang Money mà không đi qua float, phần lẻ vượt quá đơn vị nhỏ nhất
+// được làm tròn nửa lên (xa số 0).
+func Parse(value string, currency string) (Money, error) {
+	currency = normalizeCurrency(currency)
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount %q", value)
+	}
+	return FromRat(rat, currency)
+}
+
+// FromRat chuyển số hữu tỉ (đơn vị tiền) sang Money, làm tròn nửa lên (xa số 0) tới đơn vị nhỏ nhất.
+func FromRat(value *big.Rat, currency string) (Money, error) {
+	currency = normalizeCurrency(currency)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(Exponent(currency))), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(scale))
+
+	amount := roundHalfAwayFromZero(scaled.Num(), scaled.Denom())
+	if !amount.IsInt64() {
+		return Money{}, fmt.Errorf("money amount %s overflows int64", value.FloatString(Exponent(currency)))
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+// Exponent trả về số chữ số thập phân của currency theo ISO 4217.
+func Exponent(currency string) int {
+	if zeroDecimalCurrencies[normalizeCurrency(currency)] {
+		return 0
+	}
+	return 2
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency cho biết hai số tiền có cùng tiền tệ.
+func (m Money) SameCurrency(other Money) bool {
+	return normalizeCurrency(m.Currency) == normalizeCurrency(other.Currency)
+}
+
+// Add cộng hai số tiền cùng tiền tệ.
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return New(m.Amount+other.Amount, m.Currency), nil
+}
+
+// Sub trừ hai số tiền cùng tiền tệ.
+func (m Money) Sub(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return New(m.Amount-other.Amount, m.Currency), nil
+}
+
+// Multiply nhân số tiền với số lượng, dùng cho đơn giá x số lượng.
+func (m Money) Multiply(quantity int64) Money {
+	return New(m.Amount*quantity, m.Currency)
+}
+
+// MulRatio trả về m * numerator / denominator làm tròn nửa lên (xa số 0), dùng để chia giảm giá theo tỷ lệ.
+// denominator bằng 0 trả về 0.
+func (m Money) MulRatio(numerator, denominator int64) Money {
+	if denominator == 0 {
+		return Zero(m.Currency)
+	}
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator))
+	return New(roundHalfAwayFromZero(product, big.NewInt(denominator)).Int64(), m.Currency)
+}
+
+// Cmp so sánh hai số tiền cùng tiền tệ, trả về -1, 0 hoặc 1.
+func (m Money) Cmp(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, fmt.Errorf("%w: %s <> %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Equal cho biết hai số tiền bằng nhau và cùng tiền tệ.
+func (m Money) Equal(other Money) bool {
+	return m.SameCurrency(other) && m.Amount == other.Amount
+}
+
+// Min trả về số tiền nhỏ hơn, hai số tiền khác tiền tệ thì trả về m.
+func (m Money) Min(other Money) Money {
+	if m.SameCurrency(other) && other.Amount < m.Amount {
+		return other
+	}
+	return m
+}
+
+// Float64 trả về số tiền theo đơn vị tiền (ví dụ 12.34), chỉ dùng để hiển thị hoặc cho các API cũ còn nhận số thực.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(Exponent(m.Currency))
+}
+
+// Decimal trả về số tiền dạng chuỗi thập phân chính xác, ví dụ "12.34" hoặc "15000".
+func (m Money) Decimal() string {
+	exp := Exponent(m.Currency)
+	if exp == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, exp, amount%unit)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + normalizeCurrency(m.Currency)
+}
+
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// roundHalfAwayFromZero trả về num / denom làm tròn tới số nguyên gần nhất, .5 được làm tròn ra xa số 0.
+func roundHalfAwayFromZero(num, denom *big.Int) *big.Int {
+	if denom.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		denom = new(big.Int).Neg(denom)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(num, denom, new(big.Int))
+	twice := new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2))
+	if twice.Cmp(denom) >= 0 {
+		if num.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
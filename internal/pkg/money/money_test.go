@@ -0,0 +1,203 @@
+package money_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	commonv1 "github.com/toji-dev/go-shop/proto/gen/go/common/v1"
+)
+
+func TestExponent(t *testing.T) {
+	testCases := []struct {
+		currency string
+		expected int
+	}{
+		{currency: "USD", expected: 2},
+		{currency: "EUR", expected: 2},
+		{currency: "VND", expected: 0},
+		{currency: "vnd", expected: 0},
+		{currency: " JPY ", expected: 0},
+		{currency: "KRW", expected: 0},
+		{currency: "", expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.currency, func(t *testing.T) {
+			assert.Equal(t, tc.expected, money.Exponent(tc.currency))
+		})
+	}
+}
+
+func TestNew_NormalizesCurrency(t *testing.T) {
+	assert.Equal(t, money.Money{Amount: 100, Currency: "VND"}, money.New(100, " vnd "))
+	assert.Equal(t, money.Money{Amount: 100, Currency: money.DefaultCurrency}, money.New(100, ""))
+}
+
+func TestFromMajor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		currency string
+		expected money.Money
+	}{
+		{name: "USD cents", value: 12.34, currency: "USD", expected: money.New(1234, "USD")},
+		{name: "USD float error rounds to nearest cent", value: 0.1 + 0.2, currency: "USD", expected: money.New(30, "USD")},
+		{name: "USD half cent rounds away from zero", value: 0.125, currency: "USD", expected: money.New(13, "USD")},
+		{name: "USD negative half cent rounds away from zero", value: -0.125, currency: "USD", expected: money.New(-13, "USD")},
+		{name: "VND has no minor unit", value: 15000, currency: "VND", expected: money.New(15000, "VND")},
+		{name: "VND fraction rounds half up", value: 15000.5, currency: "VND", expected: money.New(15001, "VND")},
+		{name: "VND fraction below half rounds down", value: 15000.4, currency: "VND", expected: money.New(15000, "VND")},
+		{name: "empty currency uses default", value: 1, currency: "", expected: money.New(100, money.DefaultCurrency)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, money.FromMajor(tc.value, tc.currency))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		currency      string
+		expected      money.Money
+		expectedError bool
+	}{
+		{name: "USD exact", value: "12.34", currency: "USD", expected: money.New(1234, "USD")},
+		{name: "USD half rounds away from zero", value: "12.345", currency: "USD", expected: money.New(1235, "USD")},
+		{name: "USD negative half rounds away from zero", value: "-12.345", currency: "USD", expected: money.New(-1235, "USD")},
+		{name: "USD below half rounds down", value: "12.344", currency: "USD", expected: money.New(1234, "USD")},
+		{name: "VND", value: " 15000 ", currency: "VND", expected: money.New(15000, "VND")},
+		{name: "VND half rounds up", value: "15000.5", currency: "VND", expected: money.New(15001, "VND")},
+		{name: "invalid", value: "12,34", currency: "USD", expectedError: true},
+		{name: "overflow", value: "92233720368547758.08", currency: "USD", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := money.Parse(tc.value, tc.currency)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestFromRat(t *testing.T) {
+	m, err := money.FromRat(big.NewRat(1, 3), "USD")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(33, "USD"), m)
+
+	m, err = money.FromRat(big.NewRat(2, 3), "VND")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1, "VND"), m)
+}
+
+func TestFromProto(t *testing.T) {
+	m, ok := money.FromProto(nil)
+	assert.False(t, ok)
+	assert.Equal(t, money.Money{}, m)
+
+	m, ok = money.FromProto(&commonv1.Money{Amount: 1234, Currency: "usd"})
+	assert.True(t, ok)
+	assert.Equal(t, money.New(1234, "USD"), m)
+
+	m, ok = money.FromProto(&commonv1.Money{Amount: 500})
+	assert.True(t, ok)
+	assert.Equal(t, money.New(500, money.DefaultCurrency), m)
+}
+
+func TestToProto_RoundTrip(t *testing.T) {
+	original := money.New(15000, "VND")
+	pb := original.ToProto()
+	assert.Equal(t, int64(15000), pb.GetAmount())
+	assert.Equal(t, "VND", pb.GetCurrency())
+
+	m, ok := money.FromProto(pb)
+	assert.True(t, ok)
+	assert.True(t, original.Equal(m))
+}
+
+func TestAddSub(t *testing.T) {
+	sum, err := money.New(1050, "USD").Add(money.New(250, "usd"))
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1300, "USD"), sum)
+
+	diff, err := money.New(1050, "USD").Sub(money.New(2000, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, money.New(-950, "USD"), diff)
+
+	_, err = money.New(1050, "USD").Add(money.New(1050, "VND"))
+	assert.True(t, errors.Is(err, money.ErrCurrencyMismatch))
+
+	_, err = money.New(1050, "USD").Sub(money.New(1050, "VND"))
+	assert.True(t, errors.Is(err, money.ErrCurrencyMismatch))
+}
+
+func TestEqual(t *testing.T) {
+	assert.True(t, money.New(1234, "USD").Equal(money.New(1234, "usd")))
+	assert.False(t, money.New(1234, "USD").Equal(money.New(1235, "USD")))
+	// Cùng số nhưng khác tiền tệ không bằng nhau: 1234 xu khác 1234 đồng
+	assert.False(t, money.New(1234, "USD").Equal(money.New(1234, "VND")))
+}
+
+func TestCmp(t *testing.T) {
+	cmp, err := money.New(100, "USD").Cmp(money.New(200, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	cmp, err = money.New(200, "USD").Cmp(money.New(100, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	cmp, err = money.New(100, "USD").Cmp(money.New(100, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+
+	_, err = money.New(100, "USD").Cmp(money.New(100, "VND"))
+	assert.True(t, errors.Is(err, money.ErrCurrencyMismatch))
+}
+
+func TestMulRatio(t *testing.T) {
+	assert.Equal(t, money.New(333, "USD"), money.New(1000, "USD").MulRatio(1, 3))
+	assert.Equal(t, money.New(667, "USD"), money.New(1000, "USD").MulRatio(2, 3))
+	assert.Equal(t, money.New(5, "VND"), money.New(9, "VND").MulRatio(1, 2))
+	assert.Equal(t, money.Zero("USD"), money.New(1000, "USD").MulRatio(1, 0))
+}
+
+func TestMin(t *testing.T) {
+	assert.Equal(t, money.New(100, "USD"), money.New(200, "USD").Min(money.New(100, "USD")))
+	assert.Equal(t, money.New(100, "USD"), money.New(100, "USD").Min(money.New(200, "USD")))
+	assert.Equal(t, money.New(200, "USD"), money.New(200, "USD").Min(money.New(100, "VND")))
+}
+
+func TestDecimalAndFloat64(t *testing.T) {
+	testCases := []struct {
+		money    money.Money
+		decimal  string
+		float    float64
+		asString string
+	}{
+		{money: money.New(1234, "USD"), decimal: "12.34", float: 12.34, asString: "12.34 USD"},
+		{money: money.New(5, "USD"), decimal: "0.05", float: 0.05, asString: "0.05 USD"},
+		{money: money.New(-1234, "USD"), decimal: "-12.34", float: -12.34, asString: "-12.34 USD"},
+		{money: money.New(15000, "VND"), decimal: "15000", float: 15000, asString: "15000 VND"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.asString, func(t *testing.T) {
+			assert.Equal(t, tc.decimal, tc.money.Decimal())
+			assert.Equal(t, tc.float, tc.money.Float64())
+			assert.Equal(t, tc.asString, tc.money.String())
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package money
+
+import (
+	commonv1 "github.com/toji-dev/go-shop/proto/gen/go/common/v1"
+)
+
+// ToProto chuyển Money sang message goshop.common.v1.Money.
+func (m Money) ToProto() *commonv1.Money {
+	return &commonv1.Money{
+		Amount:   m.Amount,
+		Currency: normalizeCurrency(m.Currency),
+	}
+}
+
+// FromProto chuyển message goshop.common.v1.Money sang Money, ok = false nếu message không được gửi.
+func FromProto(pb *commonv1.Money) (m Money, ok bool) {
+	if pb == nil {
+		return Money{}, false
+	}
+	return New(pb.GetAmount(), pb.GetCurrency()), true
+}
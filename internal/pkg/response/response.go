@@ -22,12 +22,15 @@ type ErrorInfo struct {
 	Details string `json:"details,omitempty"`
 }
 
-// MetaInfo represents metadata for paginated responses
+// MetaInfo represents metadata for paginated responses.
+// Offset-based endpoints fill Page/Total, cursor-based endpoints fill NextCursor/HasMore.
 type MetaInfo struct {
-	Page       int   `json:"page,omitempty"`
-	PerPage    int   `json:"per_page,omitempty"`
-	Total      int64 `json:"total,omitempty"`
-	TotalPages int   `json:"total_pages,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more,omitempty"`
 }
 
 // Success sends a successful response
@@ -2,9 +2,11 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	_ "net/http/pprof"
@@ -13,7 +15,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/config"
 	dependency_container "github.com/toji-dev/go-shop/internal/services/cart-service/internal/dependency-container"
+	grpc_server "github.com/toji-dev/go-shop/internal/services/cart-service/internal/grpc/server"
 	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/router"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -43,6 +48,8 @@ func main() {
 
 	go startMetricsServer()
 
+	go runGrpcServer(cfg, dependencyContainer)
+
 	g := gin.New()
 	g.Use(gin.Recovery())
 	g.Use(gin.Logger())
@@ -65,6 +72,24 @@ func main() {
 	log.Println("Shutting down shop service...")
 }
 
+func runGrpcServer(cfg *config.Config, dependencyContainer *dependency_container.DependencyContainer) {
+	address := cfg.GRPC.ServiceHost + ":" + strconv.Itoa(cfg.GRPC.ServicePort)
+	log.Printf("Starting gRPC server on %s", address)
+	lis, err := net.Listen("tcp", address)
+
+	if err != nil {
+		log.Fatalf("failed to listen for grpc on port %d: %v", cfg.GRPC.ServicePort, err)
+	}
+
+	s := grpc.NewServer()
+	server := grpc_server.NewCartGRPCServer(dependencyContainer.GetCartUseCase())
+
+	cart_v1.RegisterCartServiceServer(s, server)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("failed to serve gRPC server: %v", err)
+	}
+}
+
 func startMetricsServer() {
 	metricsRouter := gin.New()
 	metricsRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
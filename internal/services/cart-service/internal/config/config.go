@@ -9,12 +9,13 @@ import (
 
 // Các struct Config giữ nguyên
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	App      AppConfig      `mapstructure:"app"`
-	Grpc     GrpcConfig     `mapstructure:"shop"`
-	Jwt      JWTConfig      `mapstructure:"jwt"`
+	Server   ServerConfig     `mapstructure:"server"`
+	Database DatabaseConfig   `mapstructure:"database"`
+	Redis    RedisConfig      `mapstructure:"redis"`
+	App      AppConfig        `mapstructure:"app"`
+	Grpc     GrpcConfig       `mapstructure:"shop"`
+	GRPC     GrpcServerConfig `mapstructure:"grpc"`
+	Jwt      JWTConfig        `mapstructure:"jwt"`
 }
 
 type JWTConfig struct {
@@ -62,6 +63,11 @@ type GrpcConfig struct {
 	ProductServicePort int    `mapstructure:"product_service_port"`
 }
 
+type GrpcServerConfig struct {
+	ServiceHost string `mapstructure:"service_host"`
+	ServicePort int    `mapstructure:"service_port"`
+}
+
 func (a *AppConfig) IsProduction() bool {
 	return a.Environment == "production"
 }
@@ -97,6 +103,10 @@ func Load() (*Config, error) {
 			ProductServiceHost: getEnv("PRODUCT_SERVICE_GRPC_HOST", "localhost"),
 			ProductServicePort: getIntEnv("PRODUCT_SERVICE_GRPC_PORT", 50052),
 		},
+		GRPC: GrpcServerConfig{
+			ServiceHost: getEnv("CART_SERVICE_GRPC_HOST", "localhost"),
+			ServicePort: getIntEnv("CART_SERVICE_GRPC_PORT", 50053),
+		},
 		Jwt: JWTConfig{
 			SecretKey:       getEnv("JWT_SECRET_KEY", "your-secret-key"),
 			AccessTokenTTL:  getDurationEnv("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
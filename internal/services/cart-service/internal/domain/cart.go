@@ -36,7 +36,7 @@ func (c *Cart) findItem(productID uuid.UUID) *CartItem {
 }
 
 // AddItem chứa logic nghiệp vụ thêm sản phẩm
-func (c *Cart) AddItem(productID uuid.UUID, quantity int) error {
+func (c *Cart) AddItem(productID uuid.UUID, shopID uuid.UUID, quantity int) error {
 	if quantity <= 0 {
 		return errors.New("quantity must be positive")
 	}
@@ -49,7 +49,7 @@ func (c *Cart) AddItem(productID uuid.UUID, quantity int) error {
 			return err
 		}
 	} else {
-		newItem, err := NewCartItem(c.ID, productID, quantity)
+		newItem, err := NewCartItem(c.ID, productID, shopID, quantity)
 		if err != nil {
 			return err
 		}
@@ -59,3 +59,26 @@ func (c *Cart) AddItem(productID uuid.UUID, quantity int) error {
 	c.UpdatedAt = time_utils.GetUtcTime()
 	return nil
 }
+
+// RemoveItems xoá các sản phẩm có trong productIDs, trả về số item đã xoá
+func (c *Cart) RemoveItems(productIDs []uuid.UUID) int {
+	toRemove := make(map[uuid.UUID]struct{}, len(productIDs))
+	for _, productID := range productIDs {
+		toRemove[productID] = struct{}{}
+	}
+
+	remaining := c.Items[:0]
+	for _, item := range c.Items {
+		if _, ok := toRemove[item.ProductID]; ok {
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+
+	removed := len(c.Items) - len(remaining)
+	if removed > 0 {
+		c.Items = remaining
+		c.UpdatedAt = time_utils.GetUtcTime()
+	}
+	return removed
+}
@@ -18,7 +18,7 @@ type CartItem struct {
 }
 
 // NewCartItem là một factory function để tạo CartItem mới
-func NewCartItem(cartID, productID uuid.UUID, quantity int) (*CartItem, error) {
+func NewCartItem(cartID, productID, shopID uuid.UUID, quantity int) (*CartItem, error) {
 	if quantity <= 0 {
 		return nil, errors.New("quantity must be positive")
 	}
@@ -26,6 +26,7 @@ func NewCartItem(cartID, productID uuid.UUID, quantity int) (*CartItem, error) {
 		ID:        uuid.New(),
 		CartID:    cartID,
 		ProductID: productID,
+		ShopID:    shopID,
 		Quantity:  quantity,
 	}, nil
 }
@@ -4,3 +4,10 @@ type AddCartItemRequest struct {
 	ProductID string `json:"product_id" binding:"required"`
 	Quantity  int    `json:"quantity" binding:"required,min=1"`
 }
+
+// CartItemInput là một sản phẩm được service khác thêm vào giỏ qua gRPC, shop_id đã được service gọi xác thực.
+type CartItemInput struct {
+	ProductID string
+	ShopID    string
+	Quantity  int
+}
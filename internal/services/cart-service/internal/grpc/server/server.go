@@ -0,0 +1,103 @@
+package grpc_server
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/usecase"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	cart_v1.UnimplementedCartServiceServer
+	cartUseCase usecase.CartUseCase
+}
+
+func NewCartGRPCServer(cartUseCase usecase.CartUseCase) *Server {
+	return &Server{
+		cartUseCase: cartUseCase,
+	}
+}
+
+func (s *Server) GetCart(ctx context.Context, in *cart_v1.GetCartRequest) (*cart_v1.GetCartResponse, error) {
+	userID := in.GetUserId()
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %s", userID)
+	}
+
+	cart, appErr := s.cartUseCase.GetCart(ctx, userID)
+	if appErr != nil {
+		// Chưa có giỏ hàng thì trả về giỏ rỗng
+		if appErr.Type == apperror.TypeNotFound {
+			return &cart_v1.GetCartResponse{OwnerId: userID}, nil
+		}
+		log.Printf("Error retrieving cart of user %s: %v", userID, appErr)
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", appErr)
+	}
+
+	return &cart_v1.GetCartResponse{
+		OwnerId: cart.UserID.String(),
+		Items:   toProtoCartItems(cart.Items),
+	}, nil
+}
+
+func (s *Server) RemoveCartItems(ctx context.Context, in *cart_v1.RemoveCartItemsRequest) (*cart_v1.RemoveCartItemsResponse, error) {
+	userID := in.GetUserId()
+
+	removed, appErr := s.cartUseCase.RemoveItems(ctx, userID, in.GetProductIds())
+	if appErr != nil {
+		log.Printf("Error removing items from cart of user %s: %v", userID, appErr)
+		if appErr.Type == apperror.TypeValidation {
+			return nil, status.Error(codes.InvalidArgument, appErr.Message)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to remove cart items: %v", appErr)
+	}
+
+	return &cart_v1.RemoveCartItemsResponse{
+		RemovedCount: int32(removed),
+	}, nil
+}
+
+func (s *Server) AddCartItems(ctx context.Context, in *cart_v1.AddCartItemsRequest) (*cart_v1.AddCartItemsResponse, error) {
+	userID := in.GetUserId()
+
+	items := make([]dto.CartItemInput, 0, len(in.GetItems()))
+	for _, item := range in.GetItems() {
+		items = append(items, dto.CartItemInput{
+			ProductID: item.GetProductId(),
+			ShopID:    item.GetShopId(),
+			Quantity:  int(item.GetQuantity()),
+		})
+	}
+
+	cart, appErr := s.cartUseCase.AddItems(ctx, userID, items)
+	if appErr != nil {
+		log.Printf("Error adding items to cart of user %s: %v", userID, appErr)
+		if appErr.Type == apperror.TypeValidation {
+			return nil, status.Error(codes.InvalidArgument, appErr.Message)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add cart items: %v", appErr)
+	}
+
+	return &cart_v1.AddCartItemsResponse{
+		Items: toProtoCartItems(cart.Items),
+	}, nil
+}
+
+func toProtoCartItems(cartItems []domain.CartItem) []*cart_v1.CartItem {
+	items := make([]*cart_v1.CartItem, 0, len(cartItems))
+	for _, item := range cartItems {
+		items = append(items, &cart_v1.CartItem{
+			ProductId: item.ProductID.String(),
+			ShopId:    item.ShopID.String(),
+			Quantity:  int32(item.Quantity),
+		})
+	}
+	return items
+}
@@ -1,10 +1,10 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/toji-dev/go-shop/internal/pkg/apperror"
@@ -15,9 +15,9 @@ import (
 )
 
 type CartRepository interface {
-	GetCartByOwnerID(ctx *gin.Context, ownerID uuid.UUID) (*domain.Cart, *apperror.AppError)
-	Save(ctx *gin.Context, cart *domain.Cart) *apperror.AppError
-	DeleteCart(ctx *gin.Context, ownerID uuid.UUID) *apperror.AppError
+	GetCartByOwnerID(ctx context.Context, ownerID uuid.UUID) (*domain.Cart, *apperror.AppError)
+	Save(ctx context.Context, cart *domain.Cart) *apperror.AppError
+	DeleteCart(ctx context.Context, ownerID uuid.UUID) *apperror.AppError
 }
 
 type cartRepository struct {
@@ -37,7 +37,7 @@ func NewCartRepository(db *postgresql_infra.PostgreSQLService) CartRepository {
 	}
 }
 
-func (r *cartRepository) GetCartByOwnerID(ctx *gin.Context, ownerID uuid.UUID) (*domain.Cart, *apperror.AppError) {
+func (r *cartRepository) GetCartByOwnerID(ctx context.Context, ownerID uuid.UUID) (*domain.Cart, *apperror.AppError) {
 	ownerIDpg := converter.UUIDToPgUUID(ownerID)
 
 	cart, err := r.queries.GetCartByOwnerID(ctx, ownerIDpg)
@@ -68,7 +68,7 @@ func (r *cartRepository) GetCartByOwnerID(ctx *gin.Context, ownerID uuid.UUID) (
 	return domainCart, nil
 }
 
-func (r *cartRepository) Save(ctx *gin.Context, cart *domain.Cart) *apperror.AppError {
+func (r *cartRepository) Save(ctx context.Context, cart *domain.Cart) *apperror.AppError {
 	pgCartID := converter.UUIDToPgUUID(cart.ID)
 	pgOwnerID := converter.UUIDToPgUUID(cart.UserID)
 
@@ -138,7 +138,7 @@ func (r *cartRepository) Save(ctx *gin.Context, cart *domain.Cart) *apperror.App
 	return nil
 }
 
-func (r *cartRepository) DeleteCart(ctx *gin.Context, cartID uuid.UUID) *apperror.AppError {
+func (r *cartRepository) DeleteCart(ctx context.Context, cartID uuid.UUID) *apperror.AppError {
 	pgCartID := converter.UUIDToPgUUID(cartID)
 	// Begin transaction
 	tx, err := r.db.BeginTransaction(ctx)
@@ -190,6 +190,7 @@ func toDomainItems(items []sqlc.CartItem) ([]domain.CartItem, error) {
 			ID:        converter.PgUUIDToUUID(item.ID),
 			CartID:    converter.PgUUIDToUUID(item.CartID),
 			ProductID: converter.PgUUIDToUUID(item.ProductID),
+			ShopID:    converter.PgUUIDToUUID(item.ShopID),
 			Quantity:  int(item.Quantity),
 			CreatedAt: *converter.PgTimeToTimePtr(item.CreatedAt),
 			UpdatedAt: *converter.PgTimeToTimePtr(item.UpdatedAt),
@@ -73,7 +73,13 @@ func (uc *cartItemUseCase) AddItemToCart(ctx *gin.Context, req dto.AddCartItemRe
 		}
 	}
 
-	if addItemErr := cart.AddItem(productID, req.Quantity); addItemErr != nil {
+	shopID, err := uuid.Parse(info.Product.GetShopId())
+	if err != nil {
+		log.Printf("Invalid shop ID %q for product %s: %v", info.Product.GetShopId(), req.ProductID, err)
+		return apperror.NewInternal(fmt.Sprintf("invalid shop ID for product %s", req.ProductID))
+	}
+
+	if addItemErr := cart.AddItem(productID, shopID, req.Quantity); addItemErr != nil {
 		log.Printf("Failed to add item to cart: %v", addItemErr)
 		return apperror.NewBadRequest("Failed to add item to cart", addItemErr)
 	}
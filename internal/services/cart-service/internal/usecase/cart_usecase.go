@@ -1,12 +1,15 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/toji-dev/go-shop/internal/pkg/apperror"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/dto"
 	"github.com/toji-dev/go-shop/internal/services/cart-service/internal/repository"
 )
 
@@ -15,15 +18,17 @@ type cartUseCase struct {
 }
 
 type CartUseCase interface {
-	GetCart(ctx *gin.Context, userID string) (*domain.Cart, *apperror.AppError)
+	GetCart(ctx context.Context, userID string) (*domain.Cart, *apperror.AppError)
 	DeleteCartByOwnerID(ctx *gin.Context, ownerID string) *apperror.AppError
+	RemoveItems(ctx context.Context, userID string, productIDs []string) (int, *apperror.AppError)
+	AddItems(ctx context.Context, userID string, items []dto.CartItemInput) (*domain.Cart, *apperror.AppError)
 }
 
 func NewCartUseCase(repo repository.CartRepository) CartUseCase {
 	return &cartUseCase{repo: repo}
 }
 
-func (uc *cartUseCase) GetCart(ctx *gin.Context, userID string) (*domain.Cart, *apperror.AppError) {
+func (uc *cartUseCase) GetCart(ctx context.Context, userID string) (*domain.Cart, *apperror.AppError) {
 	cart, err := uc.repo.GetCartByOwnerID(ctx, converter.StringToUUID(userID))
 	if err != nil {
 		if apperror.GetType(err) == apperror.TypeNotFound {
@@ -43,3 +48,78 @@ func (uc *cartUseCase) DeleteCartByOwnerID(ctx *gin.Context, cartID string) *app
 	}
 	return nil
 }
+
+// RemoveItems xoá các sản phẩm khỏi giỏ hàng của userID, trả về số sản phẩm đã xoá.
+// Giỏ hàng không tồn tại được coi như không có gì để xoá.
+func (uc *cartUseCase) RemoveItems(ctx context.Context, userID string, productIDs []string) (int, *apperror.AppError) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return 0, apperror.NewBadRequest("Invalid user ID format", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(productIDs))
+	for _, productID := range productIDs {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			return 0, apperror.NewBadRequest(fmt.Sprintf("Invalid product ID format: %s", productID), err)
+		}
+		ids = append(ids, id)
+	}
+
+	cart, appErr := uc.repo.GetCartByOwnerID(ctx, ownerID)
+	if appErr != nil {
+		if appErr.Type == apperror.TypeNotFound {
+			return 0, nil
+		}
+		return 0, apperror.NewInternal("Failed to get cart: " + fmt.Sprintf("%v", appErr))
+	}
+
+	removed := cart.RemoveItems(ids)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if saveErr := uc.repo.Save(ctx, cart); saveErr != nil {
+		return 0, apperror.NewInternal("Failed to save cart: " + fmt.Sprintf("%v", saveErr))
+	}
+	return removed, nil
+}
+
+// AddItems thêm nhiều sản phẩm vào giỏ hàng của userID và lưu trong một transaction, tạo giỏ mới nếu chưa có.
+// Một sản phẩm không hợp lệ làm cả yêu cầu thất bại để giỏ hàng không bị thêm dở dang.
+func (uc *cartUseCase) AddItems(ctx context.Context, userID string, items []dto.CartItemInput) (*domain.Cart, *apperror.AppError) {
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, apperror.NewBadRequest("Invalid user ID format", err)
+	}
+
+	cart, appErr := uc.repo.GetCartByOwnerID(ctx, ownerID)
+	if appErr != nil {
+		if appErr.Type != apperror.TypeNotFound {
+			return nil, apperror.NewInternal("Failed to get cart: " + fmt.Sprintf("%v", appErr))
+		}
+		cart = domain.NewCart(ownerID)
+	}
+
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Invalid product ID format: %s", item.ProductID), err)
+		}
+		shopID, err := uuid.Parse(item.ShopID)
+		if err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Invalid shop ID format: %s", item.ShopID), err)
+		}
+		if err := cart.AddItem(productID, shopID, item.Quantity); err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Failed to add product %s to cart", item.ProductID), err)
+		}
+	}
+
+	if len(items) == 0 {
+		return cart, nil
+	}
+	if saveErr := uc.repo.Save(ctx, cart); saveErr != nil {
+		return nil, apperror.NewInternal("Failed to save cart: " + fmt.Sprintf("%v", saveErr))
+	}
+	return cart, nil
+}
@@ -62,6 +62,7 @@ func main() {
 		cfg,
 		dependencyContainer.GetOrderUsecase(),
 		dependencyContainer.GetInboxEventUsecase(),
+		dependencyContainer.GetInboxRegistry(),
 	)
 
 	// Start inbox processing worker
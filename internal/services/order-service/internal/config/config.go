@@ -17,9 +17,16 @@ type Config struct {
 	ShopServiceAdapter    ExternalServiceConfig `mapstructure:"shop_service_adapter"`
 	ProductServiceAdapter ExternalServiceConfig `mapstructure:"product_service_adapter"`
 	UserServiceAdapter    ExternalServiceConfig `mapstructure:"user_service_adapter"`
+	PaymentServiceAdapter ExternalServiceConfig `mapstructure:"payment_service_adapter"`
+	CartServiceAdapter    ExternalServiceConfig `mapstructure:"cart_service_adapter"`
 	GRPC                  GrpcConfig            `mapstructure:"grpc"`
 	Kafka                 KafkaConfig           `mapstructure:"kafka"`
 	Jwt                   JWTConfig             `mapstructure:"jwt"`
+	Shipping              ShippingConfig        `mapstructure:"shipping"`
+	Delivery              DeliveryConfig        `mapstructure:"delivery"`
+	Returns               ReturnConfig          `mapstructure:"returns"`
+	Invoice               InvoiceConfig         `mapstructure:"invoice"`
+	OrderExpiry           OrderExpiryConfig     `mapstructure:"order_expiry"`
 }
 
 type ServerConfig struct {
@@ -76,6 +83,44 @@ type JWTConfig struct {
 	Issuer          string        `json:"issuer"`
 }
 
+// ShippingConfig là bảng phí vận chuyển mặc định cho các shop chưa cấu hình bảng phí riêng.
+// Mỗi mức có dạng "giới_hạn:phí", giới hạn 0 là không giới hạn, ví dụ "5:1.5,20:2.5,0:6"; phí tính theo DefaultCurrency.
+type ShippingConfig struct {
+	DefaultCurrency        string               `mapstructure:"default_currency"`
+	DefaultZones           []ShippingTierConfig `mapstructure:"default_zones"`
+	DefaultWeightTiers     []ShippingTierConfig `mapstructure:"default_weight_tiers"`
+	DefaultItemWeightGrams int                  `mapstructure:"default_item_weight_grams"` // Dùng cho sản phẩm chưa khai báo khối lượng
+}
+
+// DeliveryConfig giới hạn việc tìm đơn chờ giao quanh vị trí của shipper.
+type DeliveryConfig struct {
+	DefaultSearchRadiusKm float64 `mapstructure:"default_search_radius_km"`
+	MaxSearchRadiusKm     float64 `mapstructure:"max_search_radius_km"`
+}
+
+// ReturnConfig: khách chỉ được yêu cầu trả hàng trong khoảng Window kể từ lúc đơn được giao.
+type ReturnConfig struct {
+	Window time.Duration `mapstructure:"window"`
+}
+
+// InvoiceConfig: giá bán đã gồm VAT theo TaxRate, số hoá đơn có dạng NumberPrefix-SHOP-000001.
+type InvoiceConfig struct {
+	TaxRate      float64 `mapstructure:"tax_rate"`
+	NumberPrefix string  `mapstructure:"number_prefix"`
+}
+
+// OrderExpiryConfig: đơn PENDING_PAYMENT quá DefaultPaymentWindow (hoặc thời gian riêng của shop) sẽ bị huỷ tự động,
+// mỗi lần chạy job xử lý tối đa BatchSize đơn.
+type OrderExpiryConfig struct {
+	DefaultPaymentWindow time.Duration `mapstructure:"default_payment_window"`
+	BatchSize            int           `mapstructure:"batch_size"`
+}
+
+type ShippingTierConfig struct {
+	Limit float64 `mapstructure:"limit"`
+	Fee   float64 `mapstructure:"fee"`
+}
+
 func (a *AppConfig) IsProduction() bool {
 	return a.Environment == "production"
 }
@@ -119,6 +164,14 @@ func Load() (*Config, error) {
 			Host: getEnv("USER_SERVICE_GRPC_HOST", "localhost"),
 			Port: getEnv("USER_SERVICE_GRPC_PORT", "8084"),
 		},
+		PaymentServiceAdapter: ExternalServiceConfig{
+			Host: getEnv("PAYMENT_SERVICE_GRPC_HOST", "localhost"),
+			Port: getEnv("PAYMENT_SERVICE_GRPC_PORT", "50055"),
+		},
+		CartServiceAdapter: ExternalServiceConfig{
+			Host: getEnv("CART_SERVICE_GRPC_HOST", "localhost"),
+			Port: getEnv("CART_SERVICE_GRPC_PORT", "50053"),
+		},
 		GRPC: GrpcConfig{
 			ServiceHost: getEnv("ORDER_SERVICE_GRPC_HOST", "localhost"),
 			ServicePort: getIntEnv("ORDER_SERVICE_GRPC_PORT", 50052),
@@ -132,6 +185,27 @@ func Load() (*Config, error) {
 			RefreshTokenTTL: getDurationEnv("JWT_REFRESH_TOKEN_EXPIRY", 24*time.Hour),
 			Issuer:          getEnv("JWT_ISSUER", "go-shop-user-service"),
 		},
+		Shipping: ShippingConfig{
+			DefaultCurrency:        strings.ToUpper(getEnv("SHIPPING_DEFAULT_CURRENCY", "USD")),
+			DefaultZones:           getShippingTiersEnv("SHIPPING_DEFAULT_ZONES", "5:1.5,20:2.5,100:4,0:6"),
+			DefaultWeightTiers:     getShippingTiersEnv("SHIPPING_DEFAULT_WEIGHT_TIERS", "1000:0,3000:1,10000:3,0:6"),
+			DefaultItemWeightGrams: getIntEnv("SHIPPING_DEFAULT_ITEM_WEIGHT_GRAMS", 500),
+		},
+		Delivery: DeliveryConfig{
+			DefaultSearchRadiusKm: getFloatEnv("DELIVERY_DEFAULT_SEARCH_RADIUS_KM", 10),
+			MaxSearchRadiusKm:     getFloatEnv("DELIVERY_MAX_SEARCH_RADIUS_KM", 50),
+		},
+		Returns: ReturnConfig{
+			Window: getDurationEnv("RETURN_WINDOW", 7*24*time.Hour),
+		},
+		Invoice: InvoiceConfig{
+			TaxRate:      getFloatEnv("INVOICE_TAX_RATE", 0.1),
+			NumberPrefix: getEnv("INVOICE_NUMBER_PREFIX", "INV"),
+		},
+		OrderExpiry: OrderExpiryConfig{
+			DefaultPaymentWindow: getDurationEnv("ORDER_PAYMENT_WINDOW", 30*time.Minute),
+			BatchSize:            getIntEnv("ORDER_EXPIRY_BATCH_SIZE", 100),
+		},
 	}
 	return cfg, nil
 }
@@ -152,6 +226,14 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if value, err := time.ParseDuration(valueStr); err == nil {
@@ -167,3 +249,39 @@ func getSliceEnv(key string, defaultValue []string) []string {
 	}
 	return strings.Split(valueStr, ",")
 }
+
+// getShippingTiersEnv đọc danh sách mức phí dạng "giới_hạn:phí,...". Giá trị sai định dạng thì dùng mặc định.
+func getShippingTiersEnv(key string, defaultValue string) []ShippingTierConfig {
+	tiers, err := parseShippingTiers(getEnv(key, defaultValue))
+	if err != nil {
+		tiers, _ = parseShippingTiers(defaultValue)
+	}
+	return tiers
+}
+
+func parseShippingTiers(value string) ([]ShippingTierConfig, error) {
+	var tiers []ShippingTierConfig
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		limitStr, feeStr, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid shipping tier %q", part)
+		}
+
+		limit, err := strconv.ParseFloat(strings.TrimSpace(limitStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shipping tier limit %q: %w", limitStr, err)
+		}
+		fee, err := strconv.ParseFloat(strings.TrimSpace(feeStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shipping tier fee %q: %w", feeStr, err)
+		}
+
+		tiers = append(tiers, ShippingTierConfig{Limit: limit, Fee: fee})
+	}
+	return tiers, nil
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Index phục vụ keyset pagination cho lịch sử đơn hàng của khách hàng
+CREATE INDEX idx_orders_owner_created_at_id ON orders(owner_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_owner_created_at_id;
+-- +goose StatementEnd
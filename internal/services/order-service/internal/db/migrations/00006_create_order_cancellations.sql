@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lưu lại thông tin huỷ đơn hàng do khách hàng chủ động yêu cầu
+CREATE TABLE order_cancellations (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    canceled_by UUID NOT NULL,
+    reason TEXT NOT NULL,
+    previous_status order_status NOT NULL,  -- Trạng thái của đơn hàng ngay trước khi huỷ
+
+    refund_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    refund_id UUID,                         -- ID của yêu cầu hoàn tiền bên payment-service
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_cancellations;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lịch sử chuyển trạng thái của đơn hàng, được ghi cùng transaction với mỗi lần đổi trạng thái
+CREATE TABLE order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    old_status order_status,                -- NULL khi đơn hàng vừa được tạo
+    new_status order_status NOT NULL,
+
+    actor VARCHAR(100) NOT NULL,            -- Service hoặc người dùng thực hiện thay đổi
+    reason TEXT NOT NULL DEFAULT '',
+    trace_id VARCHAR(32),                   -- OpenTelemetry trace id để đối chiếu với log/tracing
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history (order_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_status_history;
+-- +goose StatementEnd
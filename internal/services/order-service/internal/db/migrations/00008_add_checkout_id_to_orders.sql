@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Các đơn hàng được tạo từ cùng một lần checkout giỏ hàng (mỗi shop một đơn) dùng chung checkout_id
+ALTER TABLE orders ADD COLUMN checkout_id UUID;
+
+CREATE INDEX idx_orders_checkout_id ON orders (checkout_id) WHERE checkout_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_checkout_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS checkout_id;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Enum cho trạng thái outbox event
+CREATE TYPE outbox_event_status AS ENUM (
+    'PENDING',
+    'SENT',
+    'FAILED'
+);
+
+-- Bảng outbox events của Order Service, được ghi cùng transaction với việc tạo đơn / đổi trạng thái
+-- và được relay worker publish lên Kafka.
+CREATE TABLE order_outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(), -- Dùng làm event_id để consumer chống duplicate
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+
+    event_type VARCHAR(100) NOT NULL,      -- order_created, order_status_changed, order_canceled
+    payload JSONB NOT NULL,
+
+    event_status outbox_event_status NOT NULL DEFAULT 'PENDING',
+    retry_count INTEGER NOT NULL DEFAULT 0,
+
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_outbox_events_pending ON order_outbox_events (created_at) WHERE event_status = 'PENDING';
+CREATE INDEX idx_order_outbox_events_order_id ON order_outbox_events (order_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_outbox_events;
+DROP TYPE IF EXISTS outbox_event_status;
+-- +goose StatementEnd
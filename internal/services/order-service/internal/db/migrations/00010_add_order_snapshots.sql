@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Snapshot thông tin sản phẩm và địa chỉ giao hàng tại thời điểm đặt hàng,
+-- để đơn hàng cũ không bị thay đổi khi seller đổi tên sản phẩm hoặc user xoá địa chỉ.
+ALTER TABLE order_items
+    ADD COLUMN product_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE orders ADD COLUMN shipping_address JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_address;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS product_name;
+-- +goose StatementEnd
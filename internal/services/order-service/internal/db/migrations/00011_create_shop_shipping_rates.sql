@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Bảng phí vận chuyển riêng của từng shop. Shop không có bản ghi thì dùng bảng phí mặc định trong config.
+-- zones: [{"max_distance_km": 5, "base_fee": 1.5}, ...], weight_tiers: [{"max_weight_grams": 1000, "surcharge": 0}, ...]
+CREATE TABLE shop_shipping_rates (
+    shop_id UUID PRIMARY KEY,
+    zones JSONB NOT NULL,
+    weight_tiers JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_shipping_rates;
+-- +goose StatementEnd
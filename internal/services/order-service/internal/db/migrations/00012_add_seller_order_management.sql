@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Người bán xác nhận đơn đã thanh toán trước khi giao cho đơn vị vận chuyển
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'CONFIRMED' AFTER 'PROCESSING';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Index phục vụ keyset pagination cho danh sách đơn hàng của shop
+CREATE INDEX IF NOT EXISTS idx_orders_shop_created_at_id ON orders(shop_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_shop_created_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE delivery_status AS ENUM (
+    'CLAIMED',    -- Shipper đã nhận đơn, chưa lấy hàng
+    'DELIVERING', -- Shipper đã lấy hàng tại shop
+    'DELIVERED'   -- Đã giao cho khách, có ảnh và toạ độ xác nhận
+);
+
+-- Mỗi đơn hàng chỉ có một shipper: UNIQUE(order_id) đảm bảo việc nhận đơn là nguyên tử.
+CREATE TABLE order_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    shipper_id UUID NOT NULL,
+
+    delivery_status delivery_status NOT NULL DEFAULT 'CLAIMED',
+
+    proof_photo_url TEXT,
+    delivered_lat DOUBLE PRECISION,
+    delivered_long DOUBLE PRECISION,
+
+    claimed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    picked_up_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_deliveries_shipper_id ON order_deliveries (shipper_id, updated_at DESC);
+CREATE INDEX idx_orders_shipped_updated_at ON orders (updated_at) WHERE order_status = 'SHIPPED';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_shipped_updated_at;
+DROP TABLE IF EXISTS order_deliveries;
+DROP TYPE IF EXISTS delivery_status;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE return_status AS ENUM (
+    'REQUESTED', -- Khách gửi yêu cầu trả hàng
+    'APPROVED',  -- Người bán đồng ý, chờ nhận lại hàng
+    'REJECTED',  -- Người bán từ chối
+    'RECEIVED',  -- Người bán đã nhận lại hàng, hàng được nhập lại kho và yêu cầu hoàn tiền
+    'REFUNDED'   -- payment-service đã hoàn tiền xong
+);
+
+CREATE TABLE order_returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL,
+    shop_id UUID NOT NULL,
+
+    return_status return_status NOT NULL DEFAULT 'REQUESTED',
+    reason TEXT NOT NULL,
+    seller_note TEXT,
+
+    refund_amount NUMERIC(10, 2) NOT NULL DEFAULT 0.00,
+    refund_id VARCHAR(255),
+    restocked BOOLEAN NOT NULL DEFAULT FALSE,
+
+    approved_at TIMESTAMP WITH TIME ZONE,
+    rejected_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    refunded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_returns_order_id ON order_returns (order_id);
+CREATE INDEX idx_order_returns_shop_created_at ON order_returns (shop_id, created_at DESC);
+
+-- Giá được chụp lại từ order_items để số tiền hoàn không đổi theo giá sản phẩm hiện tại.
+CREATE TABLE order_return_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    return_id UUID NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (return_id, order_item_id)
+);
+
+CREATE INDEX idx_order_return_items_order_item_id ON order_return_items (order_item_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_return_items;
+DROP TABLE IF EXISTS order_returns;
+DROP TYPE IF EXISTS return_status;
+-- +goose StatementEnd
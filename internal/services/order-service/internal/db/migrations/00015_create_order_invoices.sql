@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Số hoá đơn tăng dần, liên tục theo từng shop. Bộ đếm được tăng trong cùng transaction với việc
+-- tạo hoá đơn nên rollback sẽ không để lại khoảng trống.
+CREATE TABLE shop_invoice_sequences (
+    shop_id UUID PRIMARY KEY,
+    last_number BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Hoá đơn được phát hành một lần cho mỗi đơn hàng. Số tiền và thông tin shop được chụp lại lúc phát hành
+-- để hoá đơn không thay đổi khi shop sửa thông tin hoặc đơn hàng được hoàn tiền sau đó.
+CREATE TABLE order_invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    shop_id UUID NOT NULL,
+    sequence_number BIGINT NOT NULL,
+    invoice_number VARCHAR(64) NOT NULL UNIQUE,
+
+    currency VARCHAR(3) NOT NULL,
+    subtotal NUMERIC(10, 2) NOT NULL,
+    discount_amount NUMERIC(10, 2) NOT NULL DEFAULT 0.00,
+    shipping_fee NUMERIC(10, 2) NOT NULL DEFAULT 0.00,
+    tax_rate NUMERIC(5, 4) NOT NULL,
+    tax_amount NUMERIC(10, 2) NOT NULL,
+    total_amount NUMERIC(10, 2) NOT NULL,
+
+    seller JSONB NOT NULL,
+
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (shop_id, sequence_number)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_invoices;
+DROP TABLE IF EXISTS shop_invoice_sequences;
+-- +goose StatementEnd
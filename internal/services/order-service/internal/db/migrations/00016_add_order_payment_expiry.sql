@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Thời gian chờ thanh toán riêng của từng shop. Shop không có bản ghi thì dùng thời gian mặc định trong config.
+CREATE TABLE shop_payment_windows (
+    shop_id UUID PRIMARY KEY,
+    window_minutes INTEGER NOT NULL CHECK (window_minutes > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Đơn bị huỷ tự động (hết hạn thanh toán) không có người huỷ
+ALTER TABLE order_cancellations ALTER COLUMN canceled_by DROP NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Index phục vụ job quét đơn PENDING / PENDING_PAYMENT quá hạn
+CREATE INDEX IF NOT EXISTS idx_orders_status_updated_at ON orders(order_status, updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_status_updated_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE order_cancellations SET canceled_by = '00000000-0000-0000-0000-000000000000' WHERE canceled_by IS NULL;
+ALTER TABLE order_cancellations ALTER COLUMN canceled_by SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_payment_windows;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tiền của đơn hàng được tính theo đơn vị nhỏ nhất của currency, nên đơn và yêu cầu trả hàng phải lưu currency
+-- thay vì suy ra từ order_items. Đơn cũ lấy currency của sản phẩm đầu tiên.
+ALTER TABLE orders ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+UPDATE orders o
+SET currency = oi.currency
+FROM (
+    SELECT DISTINCT ON (order_id) order_id, currency
+    FROM order_items
+    ORDER BY order_id, created_at
+) oi
+WHERE oi.order_id = o.id;
+
+ALTER TABLE order_returns ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+UPDATE order_returns r
+SET currency = o.currency
+FROM orders o
+WHERE o.id = r.order_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_returns DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- DISCARDED: event đã hết lượt retry và được admin huỷ bỏ, worker không bao giờ xử lý lại.
+-- Giữ lại dòng thay vì xoá để event_id vẫn chặn được message Kafka bị gửi lại.
+ALTER TYPE inbox_event_status ADD VALUE 'DISCARDED';
+
+-- Lỗi của lần xử lý thất bại gần nhất
+ALTER TABLE order_inbox_events ADD COLUMN last_error TEXT;
+
+-- Nhật ký các thao tác của admin trên inbox event (replay, discard)
+CREATE TABLE order_inbox_event_audits (
+    id BIGSERIAL PRIMARY KEY,
+    inbox_event_id UUID REFERENCES order_inbox_events(id) ON DELETE SET NULL,
+    event_id VARCHAR(255) NOT NULL,         -- Giữ lại event_id khi inbox event bị cleanup
+
+    action VARCHAR(20) NOT NULL,            -- REPLAY hoặc DISCARD
+    old_status inbox_event_status NOT NULL,
+    new_status inbox_event_status NOT NULL,
+
+    actor VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    trace_id VARCHAR(32),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_inbox_event_audits_inbox_event_id ON order_inbox_event_audits (inbox_event_id, id);
+CREATE INDEX idx_order_inbox_events_received_at_id ON order_inbox_events (received_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_order_inbox_events_received_at_id;
+DROP TABLE IF EXISTS order_inbox_event_audits;
+UPDATE order_inbox_events SET event_status = 'FAILED' WHERE event_status = 'DISCARDED';
+ALTER TABLE order_inbox_events DROP COLUMN IF EXISTS last_error;
+-- +goose StatementEnd
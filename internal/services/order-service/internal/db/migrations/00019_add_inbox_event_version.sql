@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- PARKED: event không có handler đăng ký cho (source, event_type, version) hoặc thiếu version.
+-- Event được giữ lại để offset Kafka vẫn được commit; admin replay sau khi deploy handler hoặc discard.
+ALTER TYPE inbox_event_status ADD VALUE 'PARKED';
+
+-- Version schema của payload. Các event nhận trước khi có cột này đều là version 1 của payment-service.
+ALTER TABLE order_inbox_events ADD COLUMN event_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE order_inbox_events ALTER COLUMN event_version DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE order_inbox_events SET event_status = 'FAILED' WHERE event_status = 'PARKED';
+ALTER TABLE order_inbox_events DROP COLUMN IF EXISTS event_version;
+-- +goose StatementEnd
@@ -0,0 +1,72 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Thống kê bán hàng theo ngày của shop, được cộng dồn trong cùng transaction với mỗi lần đơn hàng đổi trạng thái.
+-- Đơn được tính vào ngày tạo đơn (UTC); canceled_* gồm các đơn CANCELED, FAILED và PAYMENT_FAILED.
+CREATE TABLE shop_daily_stats (
+    shop_id UUID NOT NULL,
+    stat_date DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+
+    orders_count INTEGER NOT NULL DEFAULT 0,
+    gross_amount NUMERIC(16, 2) NOT NULL DEFAULT 0,
+    canceled_count INTEGER NOT NULL DEFAULT 0,
+    canceled_amount NUMERIC(16, 2) NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, stat_date, currency)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Số lượng và doanh thu theo sản phẩm, không tính sản phẩm của đơn đã huỷ
+CREATE TABLE shop_product_daily_stats (
+    shop_id UUID NOT NULL,
+    stat_date DATE NOT NULL,
+    product_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    quantity INTEGER NOT NULL DEFAULT 0,
+    revenue NUMERIC(16, 2) NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shop_id, stat_date, product_id, currency)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Tính lại thống kê cho các đơn đã có trước khi bảng được tạo
+INSERT INTO shop_daily_stats (shop_id, stat_date, currency, orders_count, gross_amount, canceled_count, canceled_amount)
+SELECT
+    shop_id,
+    (COALESCE(created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    currency,
+    COUNT(*),
+    SUM(final_amount),
+    COUNT(*) FILTER (WHERE order_status IN ('CANCELED', 'FAILED', 'PAYMENT_FAILED')),
+    COALESCE(SUM(final_amount) FILTER (WHERE order_status IN ('CANCELED', 'FAILED', 'PAYMENT_FAILED')), 0)
+FROM orders
+GROUP BY 1, 2, 3;
+
+INSERT INTO shop_product_daily_stats (shop_id, stat_date, product_id, currency, product_name, quantity, revenue)
+SELECT
+    o.shop_id,
+    (COALESCE(o.created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    oi.product_id,
+    oi.currency,
+    MAX(oi.product_name),
+    SUM(oi.quantity),
+    SUM(oi.price * oi.quantity)
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+WHERE o.order_status NOT IN ('CANCELED', 'FAILED', 'PAYMENT_FAILED')
+GROUP BY 1, 2, 3, 4;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shop_product_daily_stats;
+DROP TABLE IF EXISTS shop_daily_stats;
+-- +goose StatementEnd
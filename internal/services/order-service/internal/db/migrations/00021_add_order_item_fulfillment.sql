@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trạng thái xử lý của từng dòng hàng, để người bán có thể đóng gói / giao / huỷ từng sản phẩm
+-- thay vì phải huỷ cả đơn khi chỉ thiếu hàng một phần.
+CREATE TYPE order_item_status AS ENUM (
+    'PENDING',  -- Chờ người bán chuẩn bị
+    'PACKED',   -- Đã đóng gói
+    'SHIPPED',  -- Đã bàn giao cho vận chuyển
+    'CANCELED'  -- Bị huỷ (người bán huỷ dòng hàng hoặc cả đơn bị huỷ)
+);
+
+-- canceled_quantity là số lượng người bán đã huỷ riêng trên dòng hàng (đã trả lại kho và hoàn tiền riêng),
+-- dòng chuyển sang CANCELED khi huỷ hết. Khi cả đơn bị huỷ, các dòng còn mở chuyển sang CANCELED
+-- nhưng canceled_quantity giữ nguyên vì phần còn lại được trả kho và hoàn tiền theo đơn.
+ALTER TABLE order_items
+    ADD COLUMN item_status order_item_status NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN canceled_quantity INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT order_items_canceled_quantity_check CHECK (canceled_quantity >= 0 AND canceled_quantity <= quantity);
+
+-- Đơn cũ: dòng hàng theo trạng thái hiện tại của đơn. Đơn REFUNDED đã từng giao thì dòng hàng đã được giao,
+-- còn lại là đơn bị huỷ rồi hoàn tiền.
+UPDATE order_items oi
+SET item_status = 'SHIPPED'
+FROM orders o
+WHERE o.id = oi.order_id
+  AND (
+      o.order_status IN ('SHIPPED', 'DELIVERING', 'DELIVERED')
+      OR (o.order_status = 'REFUNDED' AND EXISTS (
+          SELECT 1 FROM order_status_history h
+          WHERE h.order_id = o.id AND h.new_status = 'DELIVERED'
+      ))
+  );
+
+UPDATE order_items oi
+SET item_status = 'CANCELED'
+FROM orders o
+WHERE o.id = oi.order_id
+  AND oi.item_status = 'PENDING'
+  AND o.order_status IN ('CANCELED', 'FAILED', 'REFUNDED');
+
+-- Mỗi lần người bán huỷ một phần hoặc toàn bộ dòng hàng. Số tiền hoàn được chụp lại lúc huỷ,
+-- id của dòng này là reference gửi sang product-service (trả kho) và payment-service (hoàn tiền một phần).
+CREATE TABLE order_item_cancellations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+
+    refund_amount NUMERIC(10, 2) NOT NULL DEFAULT 0.00,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    reason TEXT NOT NULL,
+    canceled_by UUID NOT NULL,
+
+    restocked BOOLEAN NOT NULL DEFAULT FALSE,
+    refund_id VARCHAR(255),
+    refunded_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_item_cancellations_order_id ON order_item_cancellations (order_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_item_cancellations;
+ALTER TABLE order_items
+    DROP CONSTRAINT IF EXISTS order_items_canceled_quantity_check,
+    DROP COLUMN IF EXISTS canceled_quantity,
+    DROP COLUMN IF EXISTS item_status;
+DROP TYPE IF EXISTS order_item_status;
+-- +goose StatementEnd
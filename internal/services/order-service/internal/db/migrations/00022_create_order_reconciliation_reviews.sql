@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Các đơn hàng lệch trạng thái với payment-service mà reconciler không tự sửa được (hoặc sửa thất bại),
+-- chờ người kiểm tra. Mỗi đơn chỉ có một dòng chưa xử lý cho mỗi loại lệch; phát hiện lại thì tăng detection_count.
+CREATE TABLE order_reconciliation_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    divergence_type VARCHAR(64) NOT NULL,
+
+    -- Trạng thái hai phía ở lần phát hiện gần nhất, payment_* NULL khi payment-service không có payment của đơn
+    order_status order_status NOT NULL,
+    payment_id UUID,
+    payment_status VARCHAR(32),
+    details TEXT NOT NULL DEFAULT '',
+
+    detection_count INT NOT NULL DEFAULT 1,
+    first_detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Được đóng tự động khi đơn khớp lại với payment, hoặc bởi người xử lý
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution TEXT
+);
+
+CREATE UNIQUE INDEX uq_order_reconciliation_reviews_open
+    ON order_reconciliation_reviews (order_id, divergence_type)
+    WHERE resolved_at IS NULL;
+
+CREATE INDEX idx_order_reconciliation_reviews_open_detected_at
+    ON order_reconciliation_reviews (last_detected_at)
+    WHERE resolved_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_reconciliation_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mỗi lần admin nhận lại tiền mặt shipper đã thu hộ là một đợt quyết toán, gồm mọi khoản thu chưa quyết toán
+-- của shipper theo một currency.
+CREATE TABLE shipper_cash_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shipper_id UUID NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    collection_count INT NOT NULL CHECK (collection_count > 0),
+    settled_by UUID NOT NULL, -- Admin nhận tiền
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shipper_cash_settlements_shipper_id ON shipper_cash_settlements (shipper_id, created_at DESC);
+
+-- Tiền mặt shipper thu của khách cho đơn COD. UNIQUE(order_id) để việc báo thu tiền lặp lại không ghi trùng.
+CREATE TABLE shipper_cash_collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    delivery_id UUID NOT NULL REFERENCES order_deliveries(id) ON DELETE CASCADE,
+    shipper_id UUID NOT NULL,
+    payment_id UUID NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+
+    collected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL khi payment-service chưa xác nhận payment COD đã SUCCESS, reconciler sẽ gọi lại
+    payment_confirmed_at TIMESTAMP WITH TIME ZONE,
+
+    settlement_id UUID REFERENCES shipper_cash_settlements(id),
+    settled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_shipper_cash_collections_unsettled
+    ON shipper_cash_collections (shipper_id, currency, collected_at)
+    WHERE settlement_id IS NULL;
+
+CREATE INDEX idx_shipper_cash_collections_unconfirmed
+    ON shipper_cash_collections (collected_at)
+    WHERE payment_confirmed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shipper_cash_collections;
+DROP TABLE IF EXISTS shipper_cash_settlements;
+-- +goose StatementEnd
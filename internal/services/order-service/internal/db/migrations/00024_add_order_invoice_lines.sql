@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Các dòng sản phẩm được chụp lại lúc phát hành để hoá đơn không đổi khi người bán huỷ bớt hàng hoặc khách trả hàng.
+-- NULL với hoá đơn phát hành trước khi có cột này, khi đó dòng sản phẩm được lấy từ đơn hàng.
+ALTER TABLE order_invoices ADD COLUMN lines JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_invoices DROP COLUMN IF EXISTS lines;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Yêu cầu hoàn tiền lỗi sau khi huỷ đơn được lưu lại để reconciler gửi lại thay vì chỉ ghi log.
+-- refund_error là lỗi của lần gửi gần nhất, NULL khi chưa lỗi hoặc đã gửi được yêu cầu hoàn tiền.
+ALTER TABLE order_cancellations
+    ADD COLUMN refund_error TEXT,
+    ADD COLUMN refund_attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_order_cancellations_refund_retry ON order_cancellations (updated_at)
+    WHERE refund_error IS NOT NULL AND refund_requested = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_order_cancellations_refund_retry;
+ALTER TABLE order_cancellations
+    DROP COLUMN IF EXISTS refund_attempts,
+    DROP COLUMN IF EXISTS refund_error;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Phí trong bảng phí vận chuyển là đơn vị tiền của currency, đơn chỉ dùng được bảng phí cùng currency với giá sản phẩm.
+-- Bảng phí cũ được lưu trước khi có currency nên mặc định là USD như đơn hàng cũ.
+ALTER TABLE shop_shipping_rates ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE shop_shipping_rates DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
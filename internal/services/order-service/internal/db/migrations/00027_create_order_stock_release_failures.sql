@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lần trả tồn kho bị lỗi sau khi đơn bị huỷ (hoặc được sửa sang PAYMENT_FAILED) được lưu lại để reconciler trả lại,
+-- thay vì chỉ ghi log và để product-service giữ hàng mãi. Mỗi đơn có tối đa một dòng, xoá khi đã trả xong.
+CREATE TABLE order_stock_release_failures (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    last_error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_stock_release_failures_updated_at ON order_stock_release_failures (updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_stock_release_failures;
+-- +goose StatementEnd
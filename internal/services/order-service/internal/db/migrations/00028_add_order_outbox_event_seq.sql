@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- created_at là thời điểm bắt đầu transaction và id là uuid ngẫu nhiên nên (created_at, id) không phản ánh thứ tự ghi
+-- của các event trong cùng transaction. seq tăng dần theo thứ tự ghi, event cũ được đánh số theo (created_at, id).
+CREATE SEQUENCE order_outbox_events_seq_seq;
+
+ALTER TABLE order_outbox_events ADD COLUMN seq BIGINT;
+
+UPDATE order_outbox_events e
+SET seq = ordered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS seq
+    FROM order_outbox_events
+) ordered
+WHERE ordered.id = e.id;
+
+SELECT setval('order_outbox_events_seq_seq', COALESCE((SELECT MAX(seq) FROM order_outbox_events), 0) + 1, false);
+
+ALTER TABLE order_outbox_events
+    ALTER COLUMN seq SET DEFAULT nextval('order_outbox_events_seq_seq'),
+    ALTER COLUMN seq SET NOT NULL;
+ALTER SEQUENCE order_outbox_events_seq_seq OWNED BY order_outbox_events.seq;
+
+CREATE UNIQUE INDEX idx_order_outbox_events_seq ON order_outbox_events (seq);
+
+DROP INDEX IF EXISTS idx_order_outbox_events_pending;
+CREATE INDEX idx_order_outbox_events_pending ON order_outbox_events (seq) WHERE event_status = 'PENDING';
+CREATE INDEX idx_order_outbox_events_failed ON order_outbox_events (order_id, seq) WHERE event_status = 'FAILED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_order_outbox_events_failed;
+DROP INDEX IF EXISTS idx_order_outbox_events_pending;
+CREATE INDEX idx_order_outbox_events_pending ON order_outbox_events (created_at) WHERE event_status = 'PENDING';
+ALTER TABLE order_outbox_events DROP COLUMN IF EXISTS seq;
+-- +goose StatementEnd
@@ -4,9 +4,11 @@ INSERT INTO order_inbox_events (
     event_type,
     source_service,
     payload,
-    event_status
+    event_status,
+    event_version,
+    last_error
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: GetPendingInboxEvents :many
@@ -20,16 +22,18 @@ SELECT * FROM order_inbox_events
 WHERE event_id = $1;
 
 -- name: UpdateInboxEventStatus :one
+-- last_error NULL thì giữ lỗi cũ để admin vẫn thấy nguyên nhân của lần thất bại trước
 UPDATE order_inbox_events
 SET
-    event_status = $2,
-    retry_count = $3,
+    event_status = sqlc.arg(event_status),
+    retry_count = sqlc.arg(retry_count),
+    last_error = COALESCE(sqlc.narg(last_error), last_error),
     processed_at = CASE 
-        WHEN $2 = 'PROCESSED' THEN NOW() 
+        WHEN sqlc.arg(event_status) = 'PROCESSED' THEN NOW() 
         ELSE processed_at 
     END,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
 -- name: GetFailedInboxEvents :many
@@ -43,6 +47,8 @@ SELECT
     COUNT(*) FILTER (WHERE event_status = 'PENDING') as pending_count,
     COUNT(*) FILTER (WHERE event_status = 'PROCESSED') as processed_count,
     COUNT(*) FILTER (WHERE event_status = 'FAILED') as failed_count,
+    COUNT(*) FILTER (WHERE event_status = 'DISCARDED') as discarded_count,
+    COUNT(*) FILTER (WHERE event_status = 'PARKED') as parked_count,
     COUNT(*) as total_count
 FROM order_inbox_events;
 
@@ -50,3 +56,68 @@ FROM order_inbox_events;
 DELETE FROM order_inbox_events
 WHERE event_status = 'PROCESSED' 
 AND processed_at < NOW() - INTERVAL '30 days';
+
+-- name: GetInboxEventByID :one
+SELECT * FROM order_inbox_events
+WHERE id = $1;
+
+-- name: ListInboxEvents :many
+SELECT * FROM order_inbox_events
+WHERE (sqlc.narg(status)::inbox_event_status IS NULL OR event_status = sqlc.narg(status)::inbox_event_status)
+  AND (sqlc.narg(event_type)::varchar IS NULL OR event_type = sqlc.narg(event_type)::varchar)
+  AND (sqlc.narg(received_from)::timestamptz IS NULL OR received_at >= sqlc.narg(received_from)::timestamptz)
+  AND (sqlc.narg(received_to)::timestamptz IS NULL OR received_at < sqlc.narg(received_to)::timestamptz)
+  AND (
+    sqlc.narg(cursor_received_at)::timestamptz IS NULL
+    OR (received_at, id) < (sqlc.narg(cursor_received_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY received_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ReplayInboxEvent :one
+-- Đưa event FAILED/PARKED về PENDING với retry_count = 0 để inbox worker xử lý lại từ đầu
+UPDATE order_inbox_events
+SET event_status = 'PENDING', retry_count = 0, updated_at = NOW()
+WHERE id = sqlc.arg(id) AND event_status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: ReplayFailedInboxEvents :many
+-- from_status là FAILED (hết lượt retry) hoặc PARKED (chưa có handler lúc nhận)
+UPDATE order_inbox_events
+SET event_status = 'PENDING', retry_count = 0, updated_at = NOW()
+WHERE id IN (
+    SELECT e.id FROM order_inbox_events e
+    WHERE e.event_status = sqlc.arg(from_status)
+      AND (sqlc.narg(event_type)::varchar IS NULL OR e.event_type = sqlc.narg(event_type)::varchar)
+      AND (sqlc.narg(received_from)::timestamptz IS NULL OR e.received_at >= sqlc.narg(received_from)::timestamptz)
+      AND (sqlc.narg(received_to)::timestamptz IS NULL OR e.received_at < sqlc.narg(received_to)::timestamptz)
+    ORDER BY e.received_at ASC
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DiscardInboxEvent :one
+UPDATE order_inbox_events
+SET event_status = 'DISCARDED', updated_at = NOW()
+WHERE id = sqlc.arg(id) AND event_status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: CreateInboxEventAudit :exec
+INSERT INTO order_inbox_event_audits (
+    inbox_event_id,
+    event_id,
+    action,
+    old_status,
+    new_status,
+    actor,
+    reason,
+    trace_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: GetInboxEventAudits :many
+SELECT * FROM order_inbox_event_audits
+WHERE inbox_event_id = $1
+ORDER BY id ASC;
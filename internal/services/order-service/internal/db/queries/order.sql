@@ -10,11 +10,14 @@ INSERT INTO orders
     discount_amount,
     total_amount,
     final_amount,
-    order_status
+    order_status,
+    checkout_id,
+    shipping_address,
+    currency
 )
 VALUES 
 (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 RETURNING *;
 
@@ -33,6 +36,44 @@ WHERE o.id = $1;
 -- name: GetOrderByID :one
 SELECT * FROM orders WHERE id = $1;
 
+-- name: GetOrderByIDForUpdate :one
+-- Khoá đơn hàng để các thao tác trên dòng hàng của cùng một đơn được thực hiện tuần tự.
+SELECT * FROM orders WHERE id = $1 FOR UPDATE;
+
+-- name: GetOrdersByIDsWithItems :many
+SELECT
+  o.*,
+  COALESCE(
+    (SELECT json_agg(oi.*)
+     FROM order_items oi
+     WHERE oi.order_id = o.id),
+    '[]'::json
+  ) as items
+FROM orders o
+WHERE o.id = ANY(sqlc.arg(ids)::uuid[])
+ORDER BY o.created_at DESC, o.id DESC;
+
+-- name: GetOrdersByShopIDWithItems :many
+SELECT
+  o.*,
+  COALESCE(
+    (SELECT json_agg(oi.*)
+     FROM order_items oi
+     WHERE oi.order_id = o.id),
+    '[]'::json
+  ) as items
+FROM orders o
+WHERE o.shop_id = sqlc.arg(shop_id)
+  AND (sqlc.narg(status)::order_status IS NULL OR o.order_status = sqlc.narg(status)::order_status)
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR o.created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR o.created_at < sqlc.narg(created_to)::timestamptz)
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (o.created_at, o.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY o.created_at DESC, o.id DESC
+LIMIT sqlc.arg(page_size);
+
 -- name: GetOrdersByUserIDWithItems :many
 SELECT
   o.*,
@@ -43,10 +84,35 @@ SELECT
     '[]'::json
   ) as items
 FROM orders o
-WHERE o.owner_id = $1
-ORDER BY o.created_at DESC -- Sắp xếp theo đơn hàng mới nhất
-LIMIT $2 -- Giới hạn số lượng đơn hàng trên mỗi trang
-OFFSET $3; -- Bỏ qua bao nhiêu đơn hàng (để qua trang mới)
+WHERE o.owner_id = sqlc.arg(owner_id)
+  AND (sqlc.narg(status)::order_status IS NULL OR o.order_status = sqlc.narg(status)::order_status)
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR o.created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR o.created_at < sqlc.narg(created_to)::timestamptz)
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (o.created_at, o.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY o.created_at DESC, o.id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetReadyToShipShopIDs :many
+-- Các shop đang có đơn SHIPPED chưa có shipper nhận.
+SELECT DISTINCT shop_id FROM orders
+WHERE order_status = 'SHIPPED'
+  AND NOT EXISTS (
+    SELECT 1 FROM order_deliveries d WHERE d.order_id = orders.id
+  );
+
+-- name: GetReadyToShipOrders :many
+-- shop_ids được sắp xếp theo khoảng cách tới shipper, đơn của shop gần hơn đứng trước.
+SELECT * FROM orders
+WHERE order_status = 'SHIPPED'
+  AND shop_id = ANY(@shop_ids::uuid[])
+  AND NOT EXISTS (
+    SELECT 1 FROM order_deliveries d WHERE d.order_id = orders.id
+  )
+ORDER BY array_position(@shop_ids::uuid[], shop_id), updated_at ASC
+LIMIT sqlc.arg(page_size);
 
 -- name: UpdateOrderStatus :one
 UPDATE orders
@@ -54,8 +120,31 @@ SET order_status = $2, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateOrderStatusIfCurrent :one
+UPDATE orders
+SET order_status = sqlc.arg(new_status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND order_status = sqlc.arg(expected_status)
+RETURNING *;
+
 -- name: GetStaleOrders :many
-SELECT * FROM orders 
-WHERE order_status = 'PENDING' OR order_status = "REFUNDED"
-AND updated_at < $1
-LIMIT $2;
\ No newline at end of file
+SELECT * FROM orders
+WHERE order_status = 'PENDING'
+  AND updated_at < $1
+ORDER BY updated_at ASC
+LIMIT $2;
+
+-- name: GetExpiredPendingPaymentOrders :many
+-- Đơn PENDING_PAYMENT đã chờ thanh toán quá thời gian của shop (tính từ lúc đơn chuyển sang PENDING_PAYMENT, đơn cũ chưa có
+-- lịch sử thì tính từ updated_at), shop chưa cấu hình thì dùng default_window_minutes.
+SELECT orders.* FROM orders
+LEFT JOIN shop_payment_windows w ON w.shop_id = orders.shop_id
+LEFT JOIN LATERAL (
+    SELECT h.created_at FROM order_status_history h
+    WHERE h.order_id = orders.id AND h.new_status = 'PENDING_PAYMENT'
+    ORDER BY h.id DESC
+    LIMIT 1
+) entered ON TRUE
+WHERE orders.order_status = 'PENDING_PAYMENT'
+  AND COALESCE(entered.created_at, orders.updated_at) < NOW() - make_interval(mins => COALESCE(w.window_minutes, sqlc.arg(default_window_minutes)::INT))
+ORDER BY COALESCE(entered.created_at, orders.updated_at) ASC
+LIMIT sqlc.arg(batch_size);
\ No newline at end of file
@@ -0,0 +1,41 @@
+-- name: CreateOrderCancellation :one
+INSERT INTO order_cancellations (
+    order_id,
+    canceled_by,
+    reason,
+    previous_status
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetOrderCancellationByOrderID :one
+SELECT * FROM order_cancellations WHERE order_id = $1;
+
+-- name: UpdateOrderCancellationRefund :one
+UPDATE order_cancellations
+SET refund_requested = $2, refund_id = $3, refund_error = NULL, updated_at = NOW()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: RecordOrderCancellationRefundFailure :one
+-- Lưu lỗi của lần gửi yêu cầu hoàn tiền để reconciler gửi lại, bỏ qua nếu yêu cầu đã được gửi thành công.
+UPDATE order_cancellations
+SET refund_error = $2, refund_attempts = refund_attempts + 1, updated_at = NOW()
+WHERE order_id = $1 AND refund_requested = FALSE
+RETURNING *;
+
+-- name: ClearOrderCancellationRefundFailure :one
+-- Đơn không còn khoản thanh toán nào cần hoàn (chưa thanh toán hoặc đã được hoàn bằng cách khác).
+UPDATE order_cancellations
+SET refund_error = NULL, updated_at = NOW()
+WHERE order_id = $1
+RETURNING *;
+
+-- name: ListOrderCancellationsForRefundRetry :many
+SELECT * FROM order_cancellations
+WHERE refund_requested = FALSE
+  AND refund_error IS NOT NULL
+  AND refund_attempts < sqlc.arg(max_attempts)
+  AND updated_at < sqlc.arg(updated_before)
+ORDER BY updated_at ASC
+LIMIT sqlc.arg(batch_size);
@@ -0,0 +1,45 @@
+-- name: ClaimOrderDelivery :one
+-- Chỉ nhận được đơn đang SHIPPED và chưa có shipper nào nhận.
+INSERT INTO order_deliveries (
+    order_id,
+    shipper_id
+)
+SELECT sqlc.arg(order_id)::uuid, sqlc.arg(shipper_id)::uuid
+WHERE EXISTS (
+    SELECT 1 FROM orders
+    WHERE id = sqlc.arg(order_id)::uuid AND order_status = 'SHIPPED'
+)
+ON CONFLICT (order_id) DO NOTHING
+RETURNING *;
+
+-- name: GetOrderDeliveryByOrderID :one
+SELECT * FROM order_deliveries
+WHERE order_id = $1;
+
+-- name: ListOrderDeliveriesByShipper :many
+SELECT * FROM order_deliveries
+WHERE shipper_id = sqlc.arg(shipper_id)
+  AND (sqlc.narg(status)::delivery_status IS NULL OR delivery_status = sqlc.narg(status)::delivery_status)
+ORDER BY updated_at DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: MarkOrderDeliveryPickedUp :one
+UPDATE order_deliveries
+SET
+    delivery_status = 'DELIVERING',
+    picked_up_at = NOW(),
+    updated_at = NOW()
+WHERE order_id = $1 AND shipper_id = $2 AND delivery_status = 'CLAIMED'
+RETURNING *;
+
+-- name: MarkOrderDeliveryDelivered :one
+UPDATE order_deliveries
+SET
+    delivery_status = 'DELIVERED',
+    proof_photo_url = $3,
+    delivered_lat = $4,
+    delivered_long = $5,
+    delivered_at = NOW(),
+    updated_at = NOW()
+WHERE order_id = $1 AND shipper_id = $2 AND delivery_status = 'DELIVERING'
+RETURNING *;
@@ -0,0 +1,35 @@
+-- name: NextShopInvoiceNumber :one
+-- Khoá bộ đếm của shop tới hết transaction nên các hoá đơn của cùng shop được đánh số lần lượt.
+INSERT INTO shop_invoice_sequences (shop_id, last_number)
+VALUES ($1, 1)
+ON CONFLICT (shop_id) DO UPDATE
+SET
+    last_number = shop_invoice_sequences.last_number + 1,
+    updated_at = NOW()
+RETURNING last_number;
+
+-- name: CreateOrderInvoice :one
+-- Không trả về dòng nào nếu đơn hàng đã có hoá đơn.
+INSERT INTO order_invoices (
+    order_id,
+    shop_id,
+    sequence_number,
+    invoice_number,
+    currency,
+    subtotal,
+    discount_amount,
+    shipping_fee,
+    tax_rate,
+    tax_amount,
+    total_amount,
+    seller,
+    lines
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+)
+ON CONFLICT (order_id) DO NOTHING
+RETURNING *;
+
+-- name: GetOrderInvoiceByOrderID :one
+SELECT * FROM order_invoices
+WHERE order_id = $1;
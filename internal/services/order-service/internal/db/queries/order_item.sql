@@ -4,7 +4,42 @@ INSERT INTO order_items (
     product_id,
     shop_id,
     quantity,
-    price
+    price,
+    product_name,
+    thumbnail_url,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING *;
\ No newline at end of file
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+-- name: ListOrderItemsByOrderID :many
+SELECT * FROM order_items
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateOrderItemStatusIfCurrent :one
+UPDATE order_items
+SET item_status = sqlc.arg(new_status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND order_id = sqlc.arg(order_id) AND item_status = sqlc.arg(expected_status)
+RETURNING *;
+
+-- name: CancelOrderItemQuantity :one
+-- Huỷ thêm quantity sản phẩm trên dòng hàng còn mở, dòng chuyển sang CANCELED khi đã huỷ hết.
+UPDATE order_items
+SET
+    canceled_quantity = canceled_quantity + sqlc.arg(quantity)::int,
+    item_status = CASE
+        WHEN canceled_quantity + sqlc.arg(quantity)::int >= quantity THEN 'CANCELED'::order_item_status
+        ELSE item_status
+    END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND order_id = sqlc.arg(order_id)
+  AND item_status IN ('PENDING', 'PACKED')
+  AND canceled_quantity + sqlc.arg(quantity)::int <= quantity
+RETURNING *;
+
+-- name: UpdateOpenOrderItemsStatus :exec
+-- Đồng bộ các dòng hàng còn mở khi cả đơn được giao cho vận chuyển hoặc bị huỷ.
+UPDATE order_items
+SET item_status = $2, updated_at = NOW()
+WHERE order_id = $1 AND item_status IN ('PENDING', 'PACKED');
@@ -0,0 +1,47 @@
+-- name: CreateOrderItemCancellation :one
+INSERT INTO order_item_cancellations (
+    order_id,
+    order_item_id,
+    product_id,
+    quantity,
+    refund_amount,
+    currency,
+    reason,
+    canceled_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetOrderItemCancellationByID :one
+SELECT * FROM order_item_cancellations
+WHERE id = $1;
+
+-- name: ListOrderItemCancellationsByOrderID :many
+SELECT * FROM order_item_cancellations
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: MarkOrderItemCancellationRestocked :one
+UPDATE order_item_cancellations
+SET
+    restocked = TRUE,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetOrderItemCancellationRefundID :one
+UPDATE order_item_cancellations
+SET
+    refund_id = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOrderItemCancellationRefunded :one
+-- Chỉ cập nhật lần đầu, event hoàn tiền bị gửi lại sẽ không tìm thấy dòng nào.
+UPDATE order_item_cancellations
+SET
+    refunded_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND refunded_at IS NULL
+RETURNING *;
@@ -0,0 +1,48 @@
+-- name: CreateOrderOutboxEvent :one
+INSERT INTO order_outbox_events (
+    order_id,
+    event_type,
+    payload
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetPendingOrderOutboxEvents :many
+-- Event xếp sau một event FAILED của cùng đơn hàng được giữ lại cho tới khi event đó được retry, để giữ thứ tự theo đơn.
+SELECT * FROM order_outbox_events
+WHERE event_status = 'PENDING'
+  AND NOT EXISTS (
+      SELECT 1 FROM order_outbox_events failed
+      WHERE failed.order_id = order_outbox_events.order_id
+        AND failed.event_status = 'FAILED'
+        AND failed.seq < order_outbox_events.seq
+  )
+ORDER BY seq ASC
+LIMIT $1;
+
+-- name: GetOrderOutboxEventByID :one
+SELECT * FROM order_outbox_events
+WHERE id = $1;
+
+-- name: UpdateOrderOutboxEventStatus :one
+UPDATE order_outbox_events
+SET
+    event_status = $2,
+    retry_count = $3,
+    published_at = CASE
+        WHEN $2 = 'SENT' THEN NOW()
+        ELSE published_at
+    END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: RetryFailedOrderOutboxEvent :one
+-- Đưa event FAILED về PENDING với lượt retry mới, các event sau của đơn được publish tiếp sau event này.
+UPDATE order_outbox_events
+SET
+    event_status = 'PENDING',
+    retry_count = 0,
+    updated_at = NOW()
+WHERE id = $1 AND event_status = 'FAILED'
+RETURNING *;
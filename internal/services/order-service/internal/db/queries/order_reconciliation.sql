@@ -0,0 +1,45 @@
+-- name: ListOrdersForPaymentReconciliation :many
+-- Duyệt theo keyset (updated_at, id) các đơn có trạng thái phụ thuộc vào thanh toán và đã đứng yên tới updated_before,
+-- để không tranh với callback / event thanh toán đang được xử lý.
+SELECT
+    o.id,
+    o.order_status,
+    o.updated_at,
+    COALESCE(oc.refund_requested, FALSE)::boolean AS refund_requested
+FROM orders o
+LEFT JOIN order_cancellations oc ON oc.order_id = o.id
+WHERE o.order_status = ANY(@statuses::order_status[])
+  AND o.updated_at < @updated_before
+  AND (o.updated_at, o.id) > (@cursor_updated_at::timestamptz, @cursor_id::uuid)
+ORDER BY o.updated_at, o.id
+LIMIT @page_size;
+
+-- name: UpsertOrderReconciliationReview :one
+-- Phát hiện lại cùng loại lệch của một đơn thì cập nhật trạng thái mới nhất vào dòng đang mở.
+INSERT INTO order_reconciliation_reviews (
+    order_id,
+    divergence_type,
+    order_status,
+    payment_id,
+    payment_status,
+    details
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (order_id, divergence_type) WHERE resolved_at IS NULL
+DO UPDATE SET
+    order_status = EXCLUDED.order_status,
+    payment_id = EXCLUDED.payment_id,
+    payment_status = EXCLUDED.payment_status,
+    details = EXCLUDED.details,
+    detection_count = order_reconciliation_reviews.detection_count + 1,
+    last_detected_at = NOW()
+RETURNING *;
+
+-- name: ResolveOrderReconciliationReviews :execrows
+UPDATE order_reconciliation_reviews
+SET
+    resolved_at = NOW(),
+    resolution = @resolution
+WHERE order_id = ANY(@order_ids::uuid[])
+  AND resolved_at IS NULL;
@@ -0,0 +1,110 @@
+-- name: CreateOrderReturn :one
+INSERT INTO order_returns (
+    order_id,
+    owner_id,
+    shop_id,
+    reason,
+    refund_amount,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CreateOrderReturnItem :one
+INSERT INTO order_return_items (
+    return_id,
+    order_item_id,
+    product_id,
+    quantity,
+    unit_price
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: LockOrderForReturn :exec
+-- Khoá đơn hàng để hai yêu cầu trả hàng đồng thời không vượt quá số lượng đã mua.
+SELECT id FROM orders WHERE id = $1 FOR UPDATE;
+
+-- name: GetReturnedQuantitiesByOrderID :many
+-- Số lượng đã yêu cầu trả của từng sản phẩm trong đơn, không tính các yêu cầu bị từ chối.
+SELECT
+    ri.order_item_id,
+    SUM(ri.quantity)::INT AS returned_quantity
+FROM order_return_items ri
+JOIN order_returns r ON r.id = ri.return_id
+WHERE r.order_id = $1 AND r.return_status <> 'REJECTED'
+GROUP BY ri.order_item_id;
+
+-- name: GetOrderReturnByID :one
+SELECT * FROM order_returns
+WHERE id = $1;
+
+-- name: ListOrderReturnsByOrderID :many
+SELECT * FROM order_returns
+WHERE order_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListOrderReturnsByShopID :many
+SELECT * FROM order_returns
+WHERE shop_id = sqlc.arg(shop_id)
+  AND (sqlc.narg(status)::return_status IS NULL OR return_status = sqlc.narg(status)::return_status)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListOrderReturnItemsByReturnIDs :many
+SELECT * FROM order_return_items
+WHERE return_id = ANY(@return_ids::uuid[])
+ORDER BY created_at ASC, id ASC;
+
+-- name: ApproveOrderReturn :one
+UPDATE order_returns
+SET
+    return_status = 'APPROVED',
+    approved_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND return_status = 'REQUESTED'
+RETURNING *;
+
+-- name: RejectOrderReturn :one
+UPDATE order_returns
+SET
+    return_status = 'REJECTED',
+    seller_note = $2,
+    rejected_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND return_status = 'REQUESTED'
+RETURNING *;
+
+-- name: MarkOrderReturnReceived :one
+UPDATE order_returns
+SET
+    return_status = 'RECEIVED',
+    received_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND return_status = 'APPROVED'
+RETURNING *;
+
+-- name: MarkOrderReturnRestocked :one
+UPDATE order_returns
+SET
+    restocked = TRUE,
+    updated_at = NOW()
+WHERE id = $1 AND return_status IN ('RECEIVED', 'REFUNDED')
+RETURNING *;
+
+-- name: SetOrderReturnRefundID :one
+UPDATE order_returns
+SET
+    refund_id = $2,
+    updated_at = NOW()
+WHERE id = $1 AND return_status IN ('RECEIVED', 'REFUNDED')
+RETURNING *;
+
+-- name: MarkOrderReturnRefunded :one
+UPDATE order_returns
+SET
+    return_status = 'REFUNDED',
+    refunded_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND return_status = 'RECEIVED'
+RETURNING *;
@@ -0,0 +1,16 @@
+-- name: CreateOrderStatusHistory :one
+INSERT INTO order_status_history (
+    order_id,
+    old_status,
+    new_status,
+    actor,
+    reason,
+    trace_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListOrderStatusHistoryByOrderID :many
+SELECT * FROM order_status_history
+WHERE order_id = $1
+ORDER BY id ASC;
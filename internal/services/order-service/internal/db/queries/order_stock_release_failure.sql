@@ -0,0 +1,24 @@
+-- name: RecordOrderStockReleaseFailure :one
+-- Lưu lỗi của lần trả tồn kho gần nhất, mỗi lần lỗi tiếp theo của cùng đơn tăng attempts.
+INSERT INTO order_stock_release_failures (
+    order_id,
+    last_error
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (order_id) DO UPDATE
+SET
+    last_error = EXCLUDED.last_error,
+    attempts = order_stock_release_failures.attempts + 1,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteOrderStockReleaseFailure :exec
+DELETE FROM order_stock_release_failures WHERE order_id = $1;
+
+-- name: ListOrderStockReleaseFailuresForRetry :many
+SELECT * FROM order_stock_release_failures
+WHERE attempts < sqlc.arg(max_attempts)
+  AND updated_at < sqlc.arg(updated_before)
+ORDER BY updated_at ASC
+LIMIT sqlc.arg(batch_size);
@@ -0,0 +1,61 @@
+-- name: CreateShipperCashCollection :one
+-- Báo thu tiền lặp lại cho cùng đơn không tạo thêm dòng mới (không trả về dòng nào).
+INSERT INTO shipper_cash_collections (
+    order_id,
+    delivery_id,
+    shipper_id,
+    payment_id,
+    amount,
+    currency
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (order_id) DO NOTHING
+RETURNING *;
+
+-- name: GetShipperCashCollectionByOrderID :one
+SELECT * FROM shipper_cash_collections
+WHERE order_id = $1;
+
+-- name: MarkShipperCashCollectionConfirmed :one
+UPDATE shipper_cash_collections
+SET payment_confirmed_at = COALESCE(payment_confirmed_at, NOW())
+WHERE id = $1
+RETURNING *;
+
+-- name: ListUnconfirmedShipperCashCollections :many
+SELECT * FROM shipper_cash_collections
+WHERE payment_confirmed_at IS NULL AND collected_at < sqlc.arg(collected_before)
+ORDER BY collected_at
+LIMIT sqlc.arg(page_size);
+
+-- name: ListUnsettledShipperCashCollections :many
+SELECT * FROM shipper_cash_collections
+WHERE shipper_id = $1 AND settlement_id IS NULL
+ORDER BY collected_at;
+
+-- name: LockUnsettledShipperCashCollections :many
+-- Khoá các khoản chưa quyết toán để số tiền admin xác nhận khớp đúng với các khoản được quyết toán.
+SELECT * FROM shipper_cash_collections
+WHERE shipper_id = $1 AND currency = $2 AND settlement_id IS NULL
+ORDER BY collected_at
+FOR UPDATE;
+
+-- name: CreateShipperCashSettlement :one
+INSERT INTO shipper_cash_settlements (
+    shipper_id,
+    amount,
+    currency,
+    collection_count,
+    settled_by,
+    note
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: SettleShipperCashCollections :execrows
+UPDATE shipper_cash_collections
+SET
+    settlement_id = sqlc.arg(settlement_id),
+    settled_at = NOW()
+WHERE id = ANY(sqlc.arg(collection_ids)::uuid[]) AND settlement_id IS NULL;
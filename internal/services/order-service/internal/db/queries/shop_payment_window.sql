@@ -0,0 +1,16 @@
+-- name: GetShopPaymentWindow :one
+SELECT * FROM shop_payment_windows
+WHERE shop_id = $1;
+
+-- name: UpsertShopPaymentWindow :one
+INSERT INTO shop_payment_windows (
+    shop_id,
+    window_minutes
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (shop_id) DO UPDATE
+SET
+    window_minutes = EXCLUDED.window_minutes,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,20 @@
+-- name: GetShopShippingRate :one
+SELECT * FROM shop_shipping_rates
+WHERE shop_id = $1;
+
+-- name: UpsertShopShippingRate :one
+INSERT INTO shop_shipping_rates (
+    shop_id,
+    zones,
+    weight_tiers,
+    currency
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (shop_id) DO UPDATE
+SET
+    zones = EXCLUDED.zones,
+    weight_tiers = EXCLUDED.weight_tiers,
+    currency = EXCLUDED.currency,
+    updated_at = NOW()
+RETURNING *;
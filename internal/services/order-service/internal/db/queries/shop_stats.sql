@@ -0,0 +1,143 @@
+-- name: ApplyShopDailyStatsDelta :exec
+-- Cộng delta (âm khi đơn rời nhóm) vào thống kê ngày tạo đơn của shop.
+-- Giá trị của đơn không tính các dòng hàng người bán đã huỷ riêng (đã được trừ khi huỷ dòng).
+INSERT INTO shop_daily_stats (
+    shop_id,
+    stat_date,
+    currency,
+    orders_count,
+    gross_amount,
+    canceled_count,
+    canceled_amount
+)
+SELECT
+    o.shop_id,
+    (COALESCE(o.created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    o.currency,
+    sqlc.arg(orders_delta)::int,
+    sqlc.arg(orders_delta)::int * (o.final_amount - ic.canceled_amount),
+    sqlc.arg(canceled_delta)::int,
+    sqlc.arg(canceled_delta)::int * (o.final_amount - ic.canceled_amount)
+FROM orders o
+CROSS JOIN LATERAL (
+    SELECT COALESCE(SUM(c.refund_amount), 0) AS canceled_amount
+    FROM order_item_cancellations c
+    WHERE c.order_id = o.id
+) ic
+WHERE o.id = sqlc.arg(order_id)
+ON CONFLICT (shop_id, stat_date, currency) DO UPDATE
+SET
+    orders_count = shop_daily_stats.orders_count + EXCLUDED.orders_count,
+    gross_amount = shop_daily_stats.gross_amount + EXCLUDED.gross_amount,
+    canceled_count = shop_daily_stats.canceled_count + EXCLUDED.canceled_count,
+    canceled_amount = shop_daily_stats.canceled_amount + EXCLUDED.canceled_amount,
+    updated_at = NOW();
+
+-- name: ApplyShopProductDailyStatsDelta :exec
+-- delta = 1 khi sản phẩm của đơn được tính vào doanh thu, -1 khi đơn bị huỷ
+INSERT INTO shop_product_daily_stats (
+    shop_id,
+    stat_date,
+    product_id,
+    currency,
+    product_name,
+    quantity,
+    revenue
+)
+SELECT
+    o.shop_id,
+    (COALESCE(o.created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    oi.product_id,
+    oi.currency,
+    MAX(oi.product_name),
+    sqlc.arg(delta)::int * SUM(oi.quantity - oi.canceled_quantity),
+    sqlc.arg(delta)::int * SUM(oi.price * (oi.quantity - oi.canceled_quantity))
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+WHERE o.id = sqlc.arg(order_id)
+GROUP BY o.shop_id, o.created_at, oi.product_id, oi.currency
+ON CONFLICT (shop_id, stat_date, product_id, currency) DO UPDATE
+SET
+    product_name = EXCLUDED.product_name,
+    quantity = shop_product_daily_stats.quantity + EXCLUDED.quantity,
+    revenue = shop_product_daily_stats.revenue + EXCLUDED.revenue,
+    updated_at = NOW();
+
+-- name: ApplyShopDailyStatsItemCancellation :exec
+-- Dòng hàng bị người bán huỷ khi đơn vẫn được tính: trừ số tiền hoàn của dòng khỏi gross_amount
+INSERT INTO shop_daily_stats (
+    shop_id,
+    stat_date,
+    currency,
+    orders_count,
+    gross_amount,
+    canceled_count,
+    canceled_amount
+)
+SELECT
+    o.shop_id,
+    (COALESCE(o.created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    o.currency,
+    0,
+    -c.refund_amount,
+    0,
+    0
+FROM order_item_cancellations c
+JOIN orders o ON o.id = c.order_id
+WHERE c.id = $1
+ON CONFLICT (shop_id, stat_date, currency) DO UPDATE
+SET
+    gross_amount = shop_daily_stats.gross_amount + EXCLUDED.gross_amount,
+    updated_at = NOW();
+
+-- name: ApplyShopProductDailyStatsItemCancellation :exec
+-- Trừ số lượng và doanh thu của phần dòng hàng bị người bán huỷ
+INSERT INTO shop_product_daily_stats (
+    shop_id,
+    stat_date,
+    product_id,
+    currency,
+    product_name,
+    quantity,
+    revenue
+)
+SELECT
+    o.shop_id,
+    (COALESCE(o.created_at, NOW()) AT TIME ZONE 'UTC')::date,
+    oi.product_id,
+    oi.currency,
+    oi.product_name,
+    -c.quantity,
+    -(oi.price * c.quantity)
+FROM order_item_cancellations c
+JOIN order_items oi ON oi.id = c.order_item_id
+JOIN orders o ON o.id = c.order_id
+WHERE c.id = $1
+ON CONFLICT (shop_id, stat_date, product_id, currency) DO UPDATE
+SET
+    quantity = shop_product_daily_stats.quantity + EXCLUDED.quantity,
+    revenue = shop_product_daily_stats.revenue + EXCLUDED.revenue,
+    updated_at = NOW();
+
+-- name: ListShopDailyStats :many
+SELECT * FROM shop_daily_stats
+WHERE shop_id = $1
+  AND stat_date >= sqlc.arg(from_date)::date
+  AND stat_date < sqlc.arg(to_date)::date
+ORDER BY stat_date ASC, currency ASC;
+
+-- name: ListShopTopProducts :many
+SELECT
+    product_id,
+    currency,
+    (ARRAY_AGG(product_name ORDER BY stat_date DESC))[1]::text AS product_name,
+    SUM(quantity)::bigint AS quantity,
+    SUM(revenue)::numeric AS revenue
+FROM shop_product_daily_stats
+WHERE shop_id = $1
+  AND stat_date >= sqlc.arg(from_date)::date
+  AND stat_date < sqlc.arg(to_date)::date
+GROUP BY product_id, currency
+HAVING SUM(quantity) > 0
+ORDER BY quantity DESC, revenue DESC, product_id ASC
+LIMIT sqlc.arg(top_limit);
@@ -28,10 +28,12 @@ INSERT INTO order_inbox_events (
     event_type,
     source_service,
     payload,
-    event_status
+    event_status,
+    event_version,
+    last_error
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version
 `
 
 type CreateInboxEventParams struct {
@@ -40,6 +42,8 @@ type CreateInboxEventParams struct {
 	SourceService string           `json:"source_service"`
 	Payload       []byte           `json:"payload"`
 	EventStatus   InboxEventStatus `json:"event_status"`
+	EventVersion  int32            `json:"event_version"`
+	LastError     pgtype.Text      `json:"last_error"`
 }
 
 func (q *Queries) CreateInboxEvent(ctx context.Context, arg CreateInboxEventParams) (OrderInboxEvent, error) {
@@ -49,6 +53,8 @@ func (q *Queries) CreateInboxEvent(ctx context.Context, arg CreateInboxEventPara
 		arg.SourceService,
 		arg.Payload,
 		arg.EventStatus,
+		arg.EventVersion,
+		arg.LastError,
 	)
 	var i OrderInboxEvent
 	err := row.Scan(
@@ -64,12 +70,88 @@ func (q *Queries) CreateInboxEvent(ctx context.Context, arg CreateInboxEventPara
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
+	)
+	return i, err
+}
+
+const createInboxEventAudit = `-- name: CreateInboxEventAudit :exec
+INSERT INTO order_inbox_event_audits (
+    inbox_event_id,
+    event_id,
+    action,
+    old_status,
+    new_status,
+    actor,
+    reason,
+    trace_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+`
+
+type CreateInboxEventAuditParams struct {
+	InboxEventID pgtype.UUID      `json:"inbox_event_id"`
+	EventID      string           `json:"event_id"`
+	Action       string           `json:"action"`
+	OldStatus    InboxEventStatus `json:"old_status"`
+	NewStatus    InboxEventStatus `json:"new_status"`
+	Actor        string           `json:"actor"`
+	Reason       string           `json:"reason"`
+	TraceID      pgtype.Text      `json:"trace_id"`
+}
+
+func (q *Queries) CreateInboxEventAudit(ctx context.Context, arg CreateInboxEventAuditParams) error {
+	_, err := q.db.Exec(ctx, createInboxEventAudit,
+		arg.InboxEventID,
+		arg.EventID,
+		arg.Action,
+		arg.OldStatus,
+		arg.NewStatus,
+		arg.Actor,
+		arg.Reason,
+		arg.TraceID,
+	)
+	return err
+}
+
+const discardInboxEvent = `-- name: DiscardInboxEvent :one
+UPDATE order_inbox_events
+SET event_status = 'DISCARDED', updated_at = NOW()
+WHERE id = $1 AND event_status = $2
+RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version
+`
+
+type DiscardInboxEventParams struct {
+	ID         pgtype.UUID      `json:"id"`
+	FromStatus InboxEventStatus `json:"from_status"`
+}
+
+func (q *Queries) DiscardInboxEvent(ctx context.Context, arg DiscardInboxEventParams) (OrderInboxEvent, error) {
+	row := q.db.QueryRow(ctx, discardInboxEvent, arg.ID, arg.FromStatus)
+	var i OrderInboxEvent
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.EventType,
+		&i.SourceService,
+		&i.Payload,
+		&i.EventStatus,
+		&i.RetryCount,
+		&i.MaxRetry,
+		&i.ReceivedAt,
+		&i.ProcessedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
 	)
 	return i, err
 }
 
 const getFailedInboxEvents = `-- name: GetFailedInboxEvents :many
-SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at FROM order_inbox_events
+SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version FROM order_inbox_events
 WHERE event_status = 'FAILED' AND retry_count < max_retry
 ORDER BY received_at ASC
 LIMIT $1
@@ -97,6 +179,45 @@ func (q *Queries) GetFailedInboxEvents(ctx context.Context, limit int32) ([]Orde
 			&i.ProcessedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LastError,
+			&i.EventVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboxEventAudits = `-- name: GetInboxEventAudits :many
+SELECT id, inbox_event_id, event_id, action, old_status, new_status, actor, reason, trace_id, created_at FROM order_inbox_event_audits
+WHERE inbox_event_id = $1
+ORDER BY id ASC
+`
+
+func (q *Queries) GetInboxEventAudits(ctx context.Context, inboxEventID pgtype.UUID) ([]OrderInboxEventAudit, error) {
+	rows, err := q.db.Query(ctx, getInboxEventAudits, inboxEventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderInboxEventAudit{}
+	for rows.Next() {
+		var i OrderInboxEventAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.InboxEventID,
+			&i.EventID,
+			&i.Action,
+			&i.OldStatus,
+			&i.NewStatus,
+			&i.Actor,
+			&i.Reason,
+			&i.TraceID,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -109,7 +230,7 @@ func (q *Queries) GetFailedInboxEvents(ctx context.Context, limit int32) ([]Orde
 }
 
 const getInboxEventByEventId = `-- name: GetInboxEventByEventId :one
-SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at FROM order_inbox_events
+SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version FROM order_inbox_events
 WHERE event_id = $1
 `
 
@@ -129,6 +250,35 @@ func (q *Queries) GetInboxEventByEventId(ctx context.Context, eventID string) (O
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
+	)
+	return i, err
+}
+
+const getInboxEventByID = `-- name: GetInboxEventByID :one
+SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version FROM order_inbox_events
+WHERE id = $1
+`
+
+func (q *Queries) GetInboxEventByID(ctx context.Context, id pgtype.UUID) (OrderInboxEvent, error) {
+	row := q.db.QueryRow(ctx, getInboxEventByID, id)
+	var i OrderInboxEvent
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.EventType,
+		&i.SourceService,
+		&i.Payload,
+		&i.EventStatus,
+		&i.RetryCount,
+		&i.MaxRetry,
+		&i.ReceivedAt,
+		&i.ProcessedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
 	)
 	return i, err
 }
@@ -138,6 +288,8 @@ SELECT
     COUNT(*) FILTER (WHERE event_status = 'PENDING') as pending_count,
     COUNT(*) FILTER (WHERE event_status = 'PROCESSED') as processed_count,
     COUNT(*) FILTER (WHERE event_status = 'FAILED') as failed_count,
+    COUNT(*) FILTER (WHERE event_status = 'DISCARDED') as discarded_count,
+    COUNT(*) FILTER (WHERE event_status = 'PARKED') as parked_count,
     COUNT(*) as total_count
 FROM order_inbox_events
 `
@@ -146,6 +298,8 @@ type GetInboxEventStatsRow struct {
 	PendingCount   int64 `json:"pending_count"`
 	ProcessedCount int64 `json:"processed_count"`
 	FailedCount    int64 `json:"failed_count"`
+	DiscardedCount int64 `json:"discarded_count"`
+	ParkedCount    int64 `json:"parked_count"`
 	TotalCount     int64 `json:"total_count"`
 }
 
@@ -156,13 +310,15 @@ func (q *Queries) GetInboxEventStats(ctx context.Context) (GetInboxEventStatsRow
 		&i.PendingCount,
 		&i.ProcessedCount,
 		&i.FailedCount,
+		&i.DiscardedCount,
+		&i.ParkedCount,
 		&i.TotalCount,
 	)
 	return i, err
 }
 
 const getPendingInboxEvents = `-- name: GetPendingInboxEvents :many
-SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at FROM order_inbox_events
+SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version FROM order_inbox_events
 WHERE event_status = 'PENDING'
 ORDER BY received_at ASC
 LIMIT $1
@@ -190,6 +346,75 @@ func (q *Queries) GetPendingInboxEvents(ctx context.Context, limit int32) ([]Ord
 			&i.ProcessedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LastError,
+			&i.EventVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInboxEvents = `-- name: ListInboxEvents :many
+SELECT id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version FROM order_inbox_events
+WHERE ($1::inbox_event_status IS NULL OR event_status = $1::inbox_event_status)
+  AND ($2::varchar IS NULL OR event_type = $2::varchar)
+  AND ($3::timestamptz IS NULL OR received_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR received_at < $4::timestamptz)
+  AND (
+    $5::timestamptz IS NULL
+    OR (received_at, id) < ($5::timestamptz, $6::uuid)
+  )
+ORDER BY received_at DESC, id DESC
+LIMIT $7
+`
+
+type ListInboxEventsParams struct {
+	Status           NullInboxEventStatus `json:"status"`
+	EventType        pgtype.Text          `json:"event_type"`
+	ReceivedFrom     pgtype.Timestamptz   `json:"received_from"`
+	ReceivedTo       pgtype.Timestamptz   `json:"received_to"`
+	CursorReceivedAt pgtype.Timestamptz   `json:"cursor_received_at"`
+	CursorID         pgtype.UUID          `json:"cursor_id"`
+	PageSize         int32                `json:"page_size"`
+}
+
+func (q *Queries) ListInboxEvents(ctx context.Context, arg ListInboxEventsParams) ([]OrderInboxEvent, error) {
+	rows, err := q.db.Query(ctx, listInboxEvents,
+		arg.Status,
+		arg.EventType,
+		arg.ReceivedFrom,
+		arg.ReceivedTo,
+		arg.CursorReceivedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderInboxEvent{}
+	for rows.Next() {
+		var i OrderInboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.SourceService,
+			&i.Payload,
+			&i.EventStatus,
+			&i.RetryCount,
+			&i.MaxRetry,
+			&i.ReceivedAt,
+			&i.ProcessedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastError,
+			&i.EventVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -201,28 +426,137 @@ func (q *Queries) GetPendingInboxEvents(ctx context.Context, limit int32) ([]Ord
 	return items, nil
 }
 
+const replayFailedInboxEvents = `-- name: ReplayFailedInboxEvents :many
+UPDATE order_inbox_events
+SET event_status = 'PENDING', retry_count = 0, updated_at = NOW()
+WHERE id IN (
+    SELECT e.id FROM order_inbox_events e
+    WHERE e.event_status = $1
+      AND ($2::varchar IS NULL OR e.event_type = $2::varchar)
+      AND ($3::timestamptz IS NULL OR e.received_at >= $3::timestamptz)
+      AND ($4::timestamptz IS NULL OR e.received_at < $4::timestamptz)
+    ORDER BY e.received_at ASC
+    LIMIT $5
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version
+`
+
+type ReplayFailedInboxEventsParams struct {
+	FromStatus   InboxEventStatus   `json:"from_status"`
+	EventType    pgtype.Text        `json:"event_type"`
+	ReceivedFrom pgtype.Timestamptz `json:"received_from"`
+	ReceivedTo   pgtype.Timestamptz `json:"received_to"`
+	BatchSize    int32              `json:"batch_size"`
+}
+
+// from_status là FAILED (hết lượt retry) hoặc PARKED (chưa có handler lúc nhận)
+func (q *Queries) ReplayFailedInboxEvents(ctx context.Context, arg ReplayFailedInboxEventsParams) ([]OrderInboxEvent, error) {
+	rows, err := q.db.Query(ctx, replayFailedInboxEvents,
+		arg.FromStatus,
+		arg.EventType,
+		arg.ReceivedFrom,
+		arg.ReceivedTo,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderInboxEvent{}
+	for rows.Next() {
+		var i OrderInboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.SourceService,
+			&i.Payload,
+			&i.EventStatus,
+			&i.RetryCount,
+			&i.MaxRetry,
+			&i.ReceivedAt,
+			&i.ProcessedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastError,
+			&i.EventVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replayInboxEvent = `-- name: ReplayInboxEvent :one
+UPDATE order_inbox_events
+SET event_status = 'PENDING', retry_count = 0, updated_at = NOW()
+WHERE id = $1 AND event_status = $2
+RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version
+`
+
+type ReplayInboxEventParams struct {
+	ID         pgtype.UUID      `json:"id"`
+	FromStatus InboxEventStatus `json:"from_status"`
+}
+
+// Đưa event FAILED/PARKED về PENDING với retry_count = 0 để inbox worker xử lý lại từ đầu
+func (q *Queries) ReplayInboxEvent(ctx context.Context, arg ReplayInboxEventParams) (OrderInboxEvent, error) {
+	row := q.db.QueryRow(ctx, replayInboxEvent, arg.ID, arg.FromStatus)
+	var i OrderInboxEvent
+	err := row.Scan(
+		&i.ID,
+		&i.EventID,
+		&i.EventType,
+		&i.SourceService,
+		&i.Payload,
+		&i.EventStatus,
+		&i.RetryCount,
+		&i.MaxRetry,
+		&i.ReceivedAt,
+		&i.ProcessedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
+	)
+	return i, err
+}
+
 const updateInboxEventStatus = `-- name: UpdateInboxEventStatus :one
 UPDATE order_inbox_events
 SET
-    event_status = $2,
-    retry_count = $3,
+    event_status = $1,
+    retry_count = $2,
+    last_error = COALESCE($3, last_error),
     processed_at = CASE 
-        WHEN $2 = 'PROCESSED' THEN NOW() 
+        WHEN $1 = 'PROCESSED' THEN NOW() 
         ELSE processed_at 
     END,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at
+WHERE id = $4
+RETURNING id, event_id, event_type, source_service, payload, event_status, retry_count, max_retry, received_at, processed_at, created_at, updated_at, last_error, event_version
 `
 
 type UpdateInboxEventStatusParams struct {
-	ID          pgtype.UUID      `json:"id"`
 	EventStatus InboxEventStatus `json:"event_status"`
 	RetryCount  int32            `json:"retry_count"`
+	LastError   pgtype.Text      `json:"last_error"`
+	ID          pgtype.UUID      `json:"id"`
 }
 
+// last_error NULL thì giữ lỗi cũ để admin vẫn thấy nguyên nhân của lần thất bại trước
 func (q *Queries) UpdateInboxEventStatus(ctx context.Context, arg UpdateInboxEventStatusParams) (OrderInboxEvent, error) {
-	row := q.db.QueryRow(ctx, updateInboxEventStatus, arg.ID, arg.EventStatus, arg.RetryCount)
+	row := q.db.QueryRow(ctx, updateInboxEventStatus,
+		arg.EventStatus,
+		arg.RetryCount,
+		arg.LastError,
+		arg.ID,
+	)
 	var i OrderInboxEvent
 	err := row.Scan(
 		&i.ID,
@@ -237,6 +571,8 @@ func (q *Queries) UpdateInboxEventStatus(ctx context.Context, arg UpdateInboxEve
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LastError,
+		&i.EventVersion,
 	)
 	return i, err
 }
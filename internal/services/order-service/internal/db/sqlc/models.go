@@ -11,12 +11,57 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type DeliveryStatus string
+
+const (
+	DeliveryStatusCLAIMED    DeliveryStatus = "CLAIMED"
+	DeliveryStatusDELIVERING DeliveryStatus = "DELIVERING"
+	DeliveryStatusDELIVERED  DeliveryStatus = "DELIVERED"
+)
+
+func (e *DeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DeliveryStatus(s)
+	case string:
+		*e = DeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDeliveryStatus struct {
+	DeliveryStatus DeliveryStatus `json:"delivery_status"`
+	Valid          bool           `json:"valid"` // Valid is true if DeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DeliveryStatus), nil
+}
+
 type InboxEventStatus string
 
 const (
 	InboxEventStatusPENDING   InboxEventStatus = "PENDING"
 	InboxEventStatusPROCESSED InboxEventStatus = "PROCESSED"
 	InboxEventStatusFAILED    InboxEventStatus = "FAILED"
+	InboxEventStatusDISCARDED InboxEventStatus = "DISCARDED"
+	InboxEventStatusPARKED    InboxEventStatus = "PARKED"
 )
 
 func (e *InboxEventStatus) Scan(src interface{}) error {
@@ -54,6 +99,50 @@ func (ns NullInboxEventStatus) Value() (driver.Value, error) {
 	return string(ns.InboxEventStatus), nil
 }
 
+type OrderItemStatus string
+
+const (
+	OrderItemStatusPENDING  OrderItemStatus = "PENDING"
+	OrderItemStatusPACKED   OrderItemStatus = "PACKED"
+	OrderItemStatusSHIPPED  OrderItemStatus = "SHIPPED"
+	OrderItemStatusCANCELED OrderItemStatus = "CANCELED"
+)
+
+func (e *OrderItemStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderItemStatus(s)
+	case string:
+		*e = OrderItemStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderItemStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOrderItemStatus struct {
+	OrderItemStatus OrderItemStatus `json:"order_item_status"`
+	Valid           bool            `json:"valid"` // Valid is true if OrderItemStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderItemStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderItemStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderItemStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderItemStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderItemStatus), nil
+}
+
 type OrderStatus string
 
 const (
@@ -61,6 +150,7 @@ const (
 	OrderStatusPENDINGPAYMENT OrderStatus = "PENDING_PAYMENT"
 	OrderStatusPAYMENTFAILED  OrderStatus = "PAYMENT_FAILED"
 	OrderStatusPROCESSING     OrderStatus = "PROCESSING"
+	OrderStatusCONFIRMED      OrderStatus = "CONFIRMED"
 	OrderStatusSHIPPED        OrderStatus = "SHIPPED"
 	OrderStatusDELIVERING     OrderStatus = "DELIVERING"
 	OrderStatusDELIVERED      OrderStatus = "DELIVERED"
@@ -104,6 +194,94 @@ func (ns NullOrderStatus) Value() (driver.Value, error) {
 	return string(ns.OrderStatus), nil
 }
 
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPENDING OutboxEventStatus = "PENDING"
+	OutboxEventStatusSENT    OutboxEventStatus = "SENT"
+	OutboxEventStatusFAILED  OutboxEventStatus = "FAILED"
+)
+
+func (e *OutboxEventStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OutboxEventStatus(s)
+	case string:
+		*e = OutboxEventStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OutboxEventStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOutboxEventStatus struct {
+	OutboxEventStatus OutboxEventStatus `json:"outbox_event_status"`
+	Valid             bool              `json:"valid"` // Valid is true if OutboxEventStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOutboxEventStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OutboxEventStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OutboxEventStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOutboxEventStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OutboxEventStatus), nil
+}
+
+type ReturnStatus string
+
+const (
+	ReturnStatusREQUESTED ReturnStatus = "REQUESTED"
+	ReturnStatusAPPROVED  ReturnStatus = "APPROVED"
+	ReturnStatusREJECTED  ReturnStatus = "REJECTED"
+	ReturnStatusRECEIVED  ReturnStatus = "RECEIVED"
+	ReturnStatusREFUNDED  ReturnStatus = "REFUNDED"
+)
+
+func (e *ReturnStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReturnStatus(s)
+	case string:
+		*e = ReturnStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReturnStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReturnStatus struct {
+	ReturnStatus ReturnStatus `json:"return_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReturnStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReturnStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReturnStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReturnStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReturnStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReturnStatus), nil
+}
+
 type Order struct {
 	ID                pgtype.UUID        `json:"id"`
 	OwnerID           pgtype.UUID        `json:"owner_id"`
@@ -117,6 +295,37 @@ type Order struct {
 	OrderStatus       OrderStatus        `json:"order_status"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CheckoutID        pgtype.UUID        `json:"checkout_id"`
+	ShippingAddress   []byte             `json:"shipping_address"`
+	Currency          string             `json:"currency"`
+}
+
+type OrderCancellation struct {
+	OrderID         pgtype.UUID        `json:"order_id"`
+	CanceledBy      pgtype.UUID        `json:"canceled_by"`
+	Reason          string             `json:"reason"`
+	PreviousStatus  OrderStatus        `json:"previous_status"`
+	RefundRequested bool               `json:"refund_requested"`
+	RefundID        pgtype.UUID        `json:"refund_id"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	RefundError     pgtype.Text        `json:"refund_error"`
+	RefundAttempts  int32              `json:"refund_attempts"`
+}
+
+type OrderDelivery struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrderID        pgtype.UUID        `json:"order_id"`
+	ShipperID      pgtype.UUID        `json:"shipper_id"`
+	DeliveryStatus DeliveryStatus     `json:"delivery_status"`
+	ProofPhotoUrl  pgtype.Text        `json:"proof_photo_url"`
+	DeliveredLat   pgtype.Float8      `json:"delivered_lat"`
+	DeliveredLong  pgtype.Float8      `json:"delivered_long"`
+	ClaimedAt      pgtype.Timestamptz `json:"claimed_at"`
+	PickedUpAt     pgtype.Timestamptz `json:"picked_up_at"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
 type OrderInboxEvent struct {
@@ -132,15 +341,219 @@ type OrderInboxEvent struct {
 	ProcessedAt   pgtype.Timestamptz `json:"processed_at"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	EventVersion  int32              `json:"event_version"`
+}
+
+type OrderInboxEventAudit struct {
+	ID           int64              `json:"id"`
+	InboxEventID pgtype.UUID        `json:"inbox_event_id"`
+	EventID      string             `json:"event_id"`
+	Action       string             `json:"action"`
+	OldStatus    InboxEventStatus   `json:"old_status"`
+	NewStatus    InboxEventStatus   `json:"new_status"`
+	Actor        string             `json:"actor"`
+	Reason       string             `json:"reason"`
+	TraceID      pgtype.Text        `json:"trace_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderInvoice struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrderID        pgtype.UUID        `json:"order_id"`
+	ShopID         pgtype.UUID        `json:"shop_id"`
+	SequenceNumber int64              `json:"sequence_number"`
+	InvoiceNumber  string             `json:"invoice_number"`
+	Currency       string             `json:"currency"`
+	Subtotal       pgtype.Numeric     `json:"subtotal"`
+	DiscountAmount pgtype.Numeric     `json:"discount_amount"`
+	ShippingFee    pgtype.Numeric     `json:"shipping_fee"`
+	TaxRate        pgtype.Numeric     `json:"tax_rate"`
+	TaxAmount      pgtype.Numeric     `json:"tax_amount"`
+	TotalAmount    pgtype.Numeric     `json:"total_amount"`
+	Seller         []byte             `json:"seller"`
+	IssuedAt       pgtype.Timestamptz `json:"issued_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Lines          []byte             `json:"lines"`
 }
 
 type OrderItem struct {
-	ID        pgtype.UUID        `json:"id"`
+	ID               pgtype.UUID        `json:"id"`
+	OrderID          pgtype.UUID        `json:"order_id"`
+	ProductID        pgtype.UUID        `json:"product_id"`
+	ShopID           pgtype.UUID        `json:"shop_id"`
+	Quantity         int32              `json:"quantity"`
+	Price            pgtype.Numeric     `json:"price"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	ProductName      string             `json:"product_name"`
+	ThumbnailUrl     string             `json:"thumbnail_url"`
+	Currency         string             `json:"currency"`
+	ItemStatus       OrderItemStatus    `json:"item_status"`
+	CanceledQuantity int32              `json:"canceled_quantity"`
+}
+
+type OrderItemCancellation struct {
+	ID           pgtype.UUID        `json:"id"`
+	OrderID      pgtype.UUID        `json:"order_id"`
+	OrderItemID  pgtype.UUID        `json:"order_item_id"`
+	ProductID    pgtype.UUID        `json:"product_id"`
+	Quantity     int32              `json:"quantity"`
+	RefundAmount pgtype.Numeric     `json:"refund_amount"`
+	Currency     string             `json:"currency"`
+	Reason       string             `json:"reason"`
+	CanceledBy   pgtype.UUID        `json:"canceled_by"`
+	Restocked    bool               `json:"restocked"`
+	RefundID     pgtype.Text        `json:"refund_id"`
+	RefundedAt   pgtype.Timestamptz `json:"refunded_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type OrderOutboxEvent struct {
+	ID          pgtype.UUID        `json:"id"`
+	OrderID     pgtype.UUID        `json:"order_id"`
+	EventType   string             `json:"event_type"`
+	Payload     []byte             `json:"payload"`
+	EventStatus OutboxEventStatus  `json:"event_status"`
+	RetryCount  int32              `json:"retry_count"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Seq         int64              `json:"seq"`
+}
+
+type OrderReconciliationReview struct {
+	ID              pgtype.UUID        `json:"id"`
+	OrderID         pgtype.UUID        `json:"order_id"`
+	DivergenceType  string             `json:"divergence_type"`
+	OrderStatus     OrderStatus        `json:"order_status"`
+	PaymentID       pgtype.UUID        `json:"payment_id"`
+	PaymentStatus   pgtype.Text        `json:"payment_status"`
+	Details         string             `json:"details"`
+	DetectionCount  int32              `json:"detection_count"`
+	FirstDetectedAt pgtype.Timestamptz `json:"first_detected_at"`
+	LastDetectedAt  pgtype.Timestamptz `json:"last_detected_at"`
+	ResolvedAt      pgtype.Timestamptz `json:"resolved_at"`
+	Resolution      pgtype.Text        `json:"resolution"`
+}
+
+type OrderReturn struct {
+	ID           pgtype.UUID        `json:"id"`
+	OrderID      pgtype.UUID        `json:"order_id"`
+	OwnerID      pgtype.UUID        `json:"owner_id"`
+	ShopID       pgtype.UUID        `json:"shop_id"`
+	ReturnStatus ReturnStatus       `json:"return_status"`
+	Reason       string             `json:"reason"`
+	SellerNote   pgtype.Text        `json:"seller_note"`
+	RefundAmount pgtype.Numeric     `json:"refund_amount"`
+	RefundID     pgtype.Text        `json:"refund_id"`
+	Restocked    bool               `json:"restocked"`
+	ApprovedAt   pgtype.Timestamptz `json:"approved_at"`
+	RejectedAt   pgtype.Timestamptz `json:"rejected_at"`
+	ReceivedAt   pgtype.Timestamptz `json:"received_at"`
+	RefundedAt   pgtype.Timestamptz `json:"refunded_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	Currency     string             `json:"currency"`
+}
+
+type OrderReturnItem struct {
+	ID          pgtype.UUID        `json:"id"`
+	ReturnID    pgtype.UUID        `json:"return_id"`
+	OrderItemID pgtype.UUID        `json:"order_item_id"`
+	ProductID   pgtype.UUID        `json:"product_id"`
+	Quantity    int32              `json:"quantity"`
+	UnitPrice   pgtype.Numeric     `json:"unit_price"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderStatusHistory struct {
+	ID        int64              `json:"id"`
 	OrderID   pgtype.UUID        `json:"order_id"`
-	ProductID pgtype.UUID        `json:"product_id"`
-	ShopID    pgtype.UUID        `json:"shop_id"`
-	Quantity  int32              `json:"quantity"`
-	Price     pgtype.Numeric     `json:"price"`
+	OldStatus NullOrderStatus    `json:"old_status"`
+	NewStatus OrderStatus        `json:"new_status"`
+	Actor     string             `json:"actor"`
+	Reason    string             `json:"reason"`
+	TraceID   pgtype.Text        `json:"trace_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type OrderStockReleaseFailure struct {
+	OrderID   pgtype.UUID        `json:"order_id"`
+	LastError string             `json:"last_error"`
+	Attempts  int32              `json:"attempts"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
+
+type ShipperCashCollection struct {
+	ID                 pgtype.UUID        `json:"id"`
+	OrderID            pgtype.UUID        `json:"order_id"`
+	DeliveryID         pgtype.UUID        `json:"delivery_id"`
+	ShipperID          pgtype.UUID        `json:"shipper_id"`
+	PaymentID          pgtype.UUID        `json:"payment_id"`
+	Amount             pgtype.Numeric     `json:"amount"`
+	Currency           string             `json:"currency"`
+	CollectedAt        pgtype.Timestamptz `json:"collected_at"`
+	PaymentConfirmedAt pgtype.Timestamptz `json:"payment_confirmed_at"`
+	SettlementID       pgtype.UUID        `json:"settlement_id"`
+	SettledAt          pgtype.Timestamptz `json:"settled_at"`
+}
+
+type ShipperCashSettlement struct {
+	ID              pgtype.UUID        `json:"id"`
+	ShipperID       pgtype.UUID        `json:"shipper_id"`
+	Amount          pgtype.Numeric     `json:"amount"`
+	Currency        string             `json:"currency"`
+	CollectionCount int32              `json:"collection_count"`
+	SettledBy       pgtype.UUID        `json:"settled_by"`
+	Note            pgtype.Text        `json:"note"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type ShopDailyStat struct {
+	ShopID         pgtype.UUID        `json:"shop_id"`
+	StatDate       pgtype.Date        `json:"stat_date"`
+	Currency       string             `json:"currency"`
+	OrdersCount    int32              `json:"orders_count"`
+	GrossAmount    pgtype.Numeric     `json:"gross_amount"`
+	CanceledCount  int32              `json:"canceled_count"`
+	CanceledAmount pgtype.Numeric     `json:"canceled_amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ShopInvoiceSequence struct {
+	ShopID     pgtype.UUID        `json:"shop_id"`
+	LastNumber int64              `json:"last_number"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ShopPaymentWindow struct {
+	ShopID        pgtype.UUID        `json:"shop_id"`
+	WindowMinutes int32              `json:"window_minutes"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ShopProductDailyStat struct {
+	ShopID      pgtype.UUID        `json:"shop_id"`
+	StatDate    pgtype.Date        `json:"stat_date"`
+	ProductID   pgtype.UUID        `json:"product_id"`
+	Currency    string             `json:"currency"`
+	ProductName string             `json:"product_name"`
+	Quantity    int32              `json:"quantity"`
+	Revenue     pgtype.Numeric     `json:"revenue"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ShopShippingRate struct {
+	ShopID      pgtype.UUID        `json:"shop_id"`
+	Zones       []byte             `json:"zones"`
+	WeightTiers []byte             `json:"weight_tiers"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	Currency    string             `json:"currency"`
+}
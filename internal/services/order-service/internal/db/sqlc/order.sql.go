@@ -23,13 +23,16 @@ INSERT INTO orders
     discount_amount,
     total_amount,
     final_amount,
-    order_status
+    order_status,
+    checkout_id,
+    shipping_address,
+    currency
 )
 VALUES 
 (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
-RETURNING id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at
+RETURNING id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency
 `
 
 type CreateOrderParams struct {
@@ -43,6 +46,9 @@ type CreateOrderParams struct {
 	TotalAmount       pgtype.Numeric `json:"total_amount"`
 	FinalAmount       pgtype.Numeric `json:"final_amount"`
 	OrderStatus       OrderStatus    `json:"order_status"`
+	CheckoutID        pgtype.UUID    `json:"checkout_id"`
+	ShippingAddress   []byte         `json:"shipping_address"`
+	Currency          string         `json:"currency"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -57,6 +63,9 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.TotalAmount,
 		arg.FinalAmount,
 		arg.OrderStatus,
+		arg.CheckoutID,
+		arg.ShippingAddress,
+		arg.Currency,
 	)
 	var i Order
 	err := row.Scan(
@@ -72,12 +81,73 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.OrderStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
 	)
 	return i, err
 }
 
+const getExpiredPendingPaymentOrders = `-- name: GetExpiredPendingPaymentOrders :many
+SELECT orders.id, orders.owner_id, orders.shop_id, orders.shipping_address_id, orders.promotion_id, orders.shipping_fee, orders.discount_amount, orders.total_amount, orders.final_amount, orders.order_status, orders.created_at, orders.updated_at, orders.checkout_id, orders.shipping_address, orders.currency FROM orders
+LEFT JOIN shop_payment_windows w ON w.shop_id = orders.shop_id
+LEFT JOIN LATERAL (
+    SELECT h.created_at FROM order_status_history h
+    WHERE h.order_id = orders.id AND h.new_status = 'PENDING_PAYMENT'
+    ORDER BY h.id DESC
+    LIMIT 1
+) entered ON TRUE
+WHERE orders.order_status = 'PENDING_PAYMENT'
+  AND COALESCE(entered.created_at, orders.updated_at) < NOW() - make_interval(mins => COALESCE(w.window_minutes, $1::INT))
+ORDER BY COALESCE(entered.created_at, orders.updated_at) ASC
+LIMIT $2
+`
+
+type GetExpiredPendingPaymentOrdersParams struct {
+	DefaultWindowMinutes int32 `json:"default_window_minutes"`
+	BatchSize            int32 `json:"batch_size"`
+}
+
+// Đơn PENDING_PAYMENT đã chờ thanh toán quá thời gian của shop (tính từ lúc đơn chuyển sang PENDING_PAYMENT, đơn cũ chưa có
+// lịch sử thì tính từ updated_at), shop chưa cấu hình thì dùng default_window_minutes.
+func (q *Queries) GetExpiredPendingPaymentOrders(ctx context.Context, arg GetExpiredPendingPaymentOrdersParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getExpiredPendingPaymentOrders, arg.DefaultWindowMinutes, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ShopID,
+			&i.ShippingAddressID,
+			&i.PromotionID,
+			&i.ShippingFee,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.FinalAmount,
+			&i.OrderStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrderByID = `-- name: GetOrderByID :one
-SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at FROM orders WHERE id = $1
+SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency FROM orders WHERE id = $1
 `
 
 func (q *Queries) GetOrderByID(ctx context.Context, id pgtype.UUID) (Order, error) {
@@ -96,13 +166,44 @@ func (q *Queries) GetOrderByID(ctx context.Context, id pgtype.UUID) (Order, erro
 		&i.OrderStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getOrderByIDForUpdate = `-- name: GetOrderByIDForUpdate :one
+SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency FROM orders WHERE id = $1 FOR UPDATE
+`
+
+// Khoá đơn hàng để các thao tác trên dòng hàng của cùng một đơn được thực hiện tuần tự.
+func (q *Queries) GetOrderByIDForUpdate(ctx context.Context, id pgtype.UUID) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByIDForUpdate, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ShopID,
+		&i.ShippingAddressID,
+		&i.PromotionID,
+		&i.ShippingFee,
+		&i.DiscountAmount,
+		&i.TotalAmount,
+		&i.FinalAmount,
+		&i.OrderStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getOrderByIDWithItems = `-- name: GetOrderByIDWithItems :one
 SELECT
-  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at,
+  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at, o.checkout_id, o.shipping_address, o.currency,
   COALESCE(
     (SELECT json_agg(oi.*)
      FROM order_items oi
@@ -126,6 +227,9 @@ type GetOrderByIDWithItemsRow struct {
 	OrderStatus       OrderStatus        `json:"order_status"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CheckoutID        pgtype.UUID        `json:"checkout_id"`
+	ShippingAddress   []byte             `json:"shipping_address"`
+	Currency          string             `json:"currency"`
 	Items             interface{}        `json:"items"`
 }
 
@@ -145,14 +249,183 @@ func (q *Queries) GetOrderByIDWithItems(ctx context.Context, id pgtype.UUID) (Ge
 		&i.OrderStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
 		&i.Items,
 	)
 	return i, err
 }
 
+const getOrdersByIDsWithItems = `-- name: GetOrdersByIDsWithItems :many
+SELECT
+  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at, o.checkout_id, o.shipping_address, o.currency,
+  COALESCE(
+    (SELECT json_agg(oi.*)
+     FROM order_items oi
+     WHERE oi.order_id = o.id),
+    '[]'::json
+  ) as items
+FROM orders o
+WHERE o.id = ANY($1::uuid[])
+ORDER BY o.created_at DESC, o.id DESC
+`
+
+type GetOrdersByIDsWithItemsRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	OwnerID           pgtype.UUID        `json:"owner_id"`
+	ShopID            pgtype.UUID        `json:"shop_id"`
+	ShippingAddressID pgtype.UUID        `json:"shipping_address_id"`
+	PromotionID       pgtype.UUID        `json:"promotion_id"`
+	ShippingFee       pgtype.Numeric     `json:"shipping_fee"`
+	DiscountAmount    pgtype.Numeric     `json:"discount_amount"`
+	TotalAmount       pgtype.Numeric     `json:"total_amount"`
+	FinalAmount       pgtype.Numeric     `json:"final_amount"`
+	OrderStatus       OrderStatus        `json:"order_status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CheckoutID        pgtype.UUID        `json:"checkout_id"`
+	ShippingAddress   []byte             `json:"shipping_address"`
+	Currency          string             `json:"currency"`
+	Items             interface{}        `json:"items"`
+}
+
+func (q *Queries) GetOrdersByIDsWithItems(ctx context.Context, ids []pgtype.UUID) ([]GetOrdersByIDsWithItemsRow, error) {
+	rows, err := q.db.Query(ctx, getOrdersByIDsWithItems, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetOrdersByIDsWithItemsRow{}
+	for rows.Next() {
+		var i GetOrdersByIDsWithItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ShopID,
+			&i.ShippingAddressID,
+			&i.PromotionID,
+			&i.ShippingFee,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.FinalAmount,
+			&i.OrderStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
+			&i.Items,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByShopIDWithItems = `-- name: GetOrdersByShopIDWithItems :many
+SELECT
+  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at, o.checkout_id, o.shipping_address, o.currency,
+  COALESCE(
+    (SELECT json_agg(oi.*)
+     FROM order_items oi
+     WHERE oi.order_id = o.id),
+    '[]'::json
+  ) as items
+FROM orders o
+WHERE o.shop_id = $1
+  AND ($2::order_status IS NULL OR o.order_status = $2::order_status)
+  AND ($3::timestamptz IS NULL OR o.created_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR o.created_at < $4::timestamptz)
+  AND (
+    $5::timestamptz IS NULL
+    OR (o.created_at, o.id) < ($5::timestamptz, $6::uuid)
+  )
+ORDER BY o.created_at DESC, o.id DESC
+LIMIT $7
+`
+
+type GetOrdersByShopIDWithItemsParams struct {
+	ShopID          pgtype.UUID        `json:"shop_id"`
+	Status          NullOrderStatus    `json:"status"`
+	CreatedFrom     pgtype.Timestamptz `json:"created_from"`
+	CreatedTo       pgtype.Timestamptz `json:"created_to"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+type GetOrdersByShopIDWithItemsRow struct {
+	ID                pgtype.UUID        `json:"id"`
+	OwnerID           pgtype.UUID        `json:"owner_id"`
+	ShopID            pgtype.UUID        `json:"shop_id"`
+	ShippingAddressID pgtype.UUID        `json:"shipping_address_id"`
+	PromotionID       pgtype.UUID        `json:"promotion_id"`
+	ShippingFee       pgtype.Numeric     `json:"shipping_fee"`
+	DiscountAmount    pgtype.Numeric     `json:"discount_amount"`
+	TotalAmount       pgtype.Numeric     `json:"total_amount"`
+	FinalAmount       pgtype.Numeric     `json:"final_amount"`
+	OrderStatus       OrderStatus        `json:"order_status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CheckoutID        pgtype.UUID        `json:"checkout_id"`
+	ShippingAddress   []byte             `json:"shipping_address"`
+	Currency          string             `json:"currency"`
+	Items             interface{}        `json:"items"`
+}
+
+func (q *Queries) GetOrdersByShopIDWithItems(ctx context.Context, arg GetOrdersByShopIDWithItemsParams) ([]GetOrdersByShopIDWithItemsRow, error) {
+	rows, err := q.db.Query(ctx, getOrdersByShopIDWithItems,
+		arg.ShopID,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetOrdersByShopIDWithItemsRow{}
+	for rows.Next() {
+		var i GetOrdersByShopIDWithItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ShopID,
+			&i.ShippingAddressID,
+			&i.PromotionID,
+			&i.ShippingFee,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.FinalAmount,
+			&i.OrderStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
+			&i.Items,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrdersByUserIDWithItems = `-- name: GetOrdersByUserIDWithItems :many
 SELECT
-  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at,
+  o.id, o.owner_id, o.shop_id, o.shipping_address_id, o.promotion_id, o.shipping_fee, o.discount_amount, o.total_amount, o.final_amount, o.order_status, o.created_at, o.updated_at, o.checkout_id, o.shipping_address, o.currency,
   COALESCE(
     (SELECT json_agg(oi.*)
      FROM order_items oi
@@ -161,15 +434,25 @@ SELECT
   ) as items
 FROM orders o
 WHERE o.owner_id = $1
-ORDER BY o.created_at DESC -- Sắp xếp theo đơn hàng mới nhất
-LIMIT $2 -- Giới hạn số lượng đơn hàng trên mỗi trang
-OFFSET $3
+  AND ($2::order_status IS NULL OR o.order_status = $2::order_status)
+  AND ($3::timestamptz IS NULL OR o.created_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR o.created_at < $4::timestamptz)
+  AND (
+    $5::timestamptz IS NULL
+    OR (o.created_at, o.id) < ($5::timestamptz, $6::uuid)
+  )
+ORDER BY o.created_at DESC, o.id DESC
+LIMIT $7
 `
 
 type GetOrdersByUserIDWithItemsParams struct {
-	OwnerID pgtype.UUID `json:"owner_id"`
-	Limit   int32       `json:"limit"`
-	Offset  int32       `json:"offset"`
+	OwnerID         pgtype.UUID        `json:"owner_id"`
+	Status          NullOrderStatus    `json:"status"`
+	CreatedFrom     pgtype.Timestamptz `json:"created_from"`
+	CreatedTo       pgtype.Timestamptz `json:"created_to"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
 }
 
 type GetOrdersByUserIDWithItemsRow struct {
@@ -185,11 +468,22 @@ type GetOrdersByUserIDWithItemsRow struct {
 	OrderStatus       OrderStatus        `json:"order_status"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	CheckoutID        pgtype.UUID        `json:"checkout_id"`
+	ShippingAddress   []byte             `json:"shipping_address"`
+	Currency          string             `json:"currency"`
 	Items             interface{}        `json:"items"`
 }
 
 func (q *Queries) GetOrdersByUserIDWithItems(ctx context.Context, arg GetOrdersByUserIDWithItemsParams) ([]GetOrdersByUserIDWithItemsRow, error) {
-	rows, err := q.db.Query(ctx, getOrdersByUserIDWithItems, arg.OwnerID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getOrdersByUserIDWithItems,
+		arg.OwnerID,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +504,9 @@ func (q *Queries) GetOrdersByUserIDWithItems(ctx context.Context, arg GetOrdersB
 			&i.OrderStatus,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
 			&i.Items,
 		); err != nil {
 			return nil, err
@@ -222,10 +519,93 @@ func (q *Queries) GetOrdersByUserIDWithItems(ctx context.Context, arg GetOrdersB
 	return items, nil
 }
 
+const getReadyToShipOrders = `-- name: GetReadyToShipOrders :many
+SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency FROM orders
+WHERE order_status = 'SHIPPED'
+  AND shop_id = ANY($1::uuid[])
+  AND NOT EXISTS (
+    SELECT 1 FROM order_deliveries d WHERE d.order_id = orders.id
+  )
+ORDER BY array_position($1::uuid[], shop_id), updated_at ASC
+LIMIT $2
+`
+
+type GetReadyToShipOrdersParams struct {
+	ShopIds  []pgtype.UUID `json:"shop_ids"`
+	PageSize int32         `json:"page_size"`
+}
+
+// shop_ids được sắp xếp theo khoảng cách tới shipper, đơn của shop gần hơn đứng trước.
+func (q *Queries) GetReadyToShipOrders(ctx context.Context, arg GetReadyToShipOrdersParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, getReadyToShipOrders, arg.ShopIds, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ShopID,
+			&i.ShippingAddressID,
+			&i.PromotionID,
+			&i.ShippingFee,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.FinalAmount,
+			&i.OrderStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReadyToShipShopIDs = `-- name: GetReadyToShipShopIDs :many
+SELECT DISTINCT shop_id FROM orders
+WHERE order_status = 'SHIPPED'
+  AND NOT EXISTS (
+    SELECT 1 FROM order_deliveries d WHERE d.order_id = orders.id
+  )
+`
+
+// Các shop đang có đơn SHIPPED chưa có shipper nhận.
+func (q *Queries) GetReadyToShipShopIDs(ctx context.Context) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, getReadyToShipShopIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var shop_id pgtype.UUID
+		if err := rows.Scan(&shop_id); err != nil {
+			return nil, err
+		}
+		items = append(items, shop_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStaleOrders = `-- name: GetStaleOrders :many
-SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at FROM orders 
-WHERE order_status = 'PENDING' OR order_status = "REFUNDED"
-AND updated_at < $1
+SELECT id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency FROM orders
+WHERE order_status = 'PENDING'
+  AND updated_at < $1
+ORDER BY updated_at ASC
 LIMIT $2
 `
 
@@ -256,6 +636,9 @@ func (q *Queries) GetStaleOrders(ctx context.Context, arg GetStaleOrdersParams)
 			&i.OrderStatus,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CheckoutID,
+			&i.ShippingAddress,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -268,11 +651,10 @@ func (q *Queries) GetStaleOrders(ctx context.Context, arg GetStaleOrdersParams)
 }
 
 const updateOrderStatus = `-- name: UpdateOrderStatus :one
-
 UPDATE orders
 SET order_status = $2, updated_at = NOW()
 WHERE id = $1
-RETURNING id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at
+RETURNING id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency
 `
 
 type UpdateOrderStatusParams struct {
@@ -280,7 +662,6 @@ type UpdateOrderStatusParams struct {
 	OrderStatus OrderStatus `json:"order_status"`
 }
 
-// Bỏ qua bao nhiêu đơn hàng (để qua trang mới)
 func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error) {
 	row := q.db.QueryRow(ctx, updateOrderStatus, arg.ID, arg.OrderStatus)
 	var i Order
@@ -297,6 +678,45 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 		&i.OrderStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const updateOrderStatusIfCurrent = `-- name: UpdateOrderStatusIfCurrent :one
+UPDATE orders
+SET order_status = $1, updated_at = NOW()
+WHERE id = $2 AND order_status = $3
+RETURNING id, owner_id, shop_id, shipping_address_id, promotion_id, shipping_fee, discount_amount, total_amount, final_amount, order_status, created_at, updated_at, checkout_id, shipping_address, currency
+`
+
+type UpdateOrderStatusIfCurrentParams struct {
+	NewStatus      OrderStatus `json:"new_status"`
+	ID             pgtype.UUID `json:"id"`
+	ExpectedStatus OrderStatus `json:"expected_status"`
+}
+
+func (q *Queries) UpdateOrderStatusIfCurrent(ctx context.Context, arg UpdateOrderStatusIfCurrentParams) (Order, error) {
+	row := q.db.QueryRow(ctx, updateOrderStatusIfCurrent, arg.NewStatus, arg.ID, arg.ExpectedStatus)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ShopID,
+		&i.ShippingAddressID,
+		&i.PromotionID,
+		&i.ShippingFee,
+		&i.DiscountAmount,
+		&i.TotalAmount,
+		&i.FinalAmount,
+		&i.OrderStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutID,
+		&i.ShippingAddress,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -11,24 +11,74 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelOrderItemQuantity = `-- name: CancelOrderItemQuantity :one
+UPDATE order_items
+SET
+    canceled_quantity = canceled_quantity + $1::int,
+    item_status = CASE
+        WHEN canceled_quantity + $1::int >= quantity THEN 'CANCELED'::order_item_status
+        ELSE item_status
+    END,
+    updated_at = NOW()
+WHERE id = $2
+  AND order_id = $3
+  AND item_status IN ('PENDING', 'PACKED')
+  AND canceled_quantity + $1::int <= quantity
+RETURNING id, order_id, product_id, shop_id, quantity, price, created_at, updated_at, product_name, thumbnail_url, currency, item_status, canceled_quantity
+`
+
+type CancelOrderItemQuantityParams struct {
+	Quantity int32       `json:"quantity"`
+	ID       pgtype.UUID `json:"id"`
+	OrderID  pgtype.UUID `json:"order_id"`
+}
+
+// Huỷ thêm quantity sản phẩm trên dòng hàng còn mở, dòng chuyển sang CANCELED khi đã huỷ hết.
+func (q *Queries) CancelOrderItemQuantity(ctx context.Context, arg CancelOrderItemQuantityParams) (OrderItem, error) {
+	row := q.db.QueryRow(ctx, cancelOrderItemQuantity, arg.Quantity, arg.ID, arg.OrderID)
+	var i OrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.ProductID,
+		&i.ShopID,
+		&i.Quantity,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProductName,
+		&i.ThumbnailUrl,
+		&i.Currency,
+		&i.ItemStatus,
+		&i.CanceledQuantity,
+	)
+	return i, err
+}
+
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
     order_id,
     product_id,
     shop_id,
     quantity,
-    price
+    price,
+    product_name,
+    thumbnail_url,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, order_id, product_id, shop_id, quantity, price, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, order_id, product_id, shop_id, quantity, price, created_at, updated_at, product_name, thumbnail_url, currency, item_status, canceled_quantity
 `
 
 type CreateOrderItemParams struct {
-	OrderID   pgtype.UUID    `json:"order_id"`
-	ProductID pgtype.UUID    `json:"product_id"`
-	ShopID    pgtype.UUID    `json:"shop_id"`
-	Quantity  int32          `json:"quantity"`
-	Price     pgtype.Numeric `json:"price"`
+	OrderID      pgtype.UUID    `json:"order_id"`
+	ProductID    pgtype.UUID    `json:"product_id"`
+	ShopID       pgtype.UUID    `json:"shop_id"`
+	Quantity     int32          `json:"quantity"`
+	Price        pgtype.Numeric `json:"price"`
+	ProductName  string         `json:"product_name"`
+	ThumbnailUrl string         `json:"thumbnail_url"`
+	Currency     string         `json:"currency"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
@@ -38,6 +88,106 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.ShopID,
 		arg.Quantity,
 		arg.Price,
+		arg.ProductName,
+		arg.ThumbnailUrl,
+		arg.Currency,
+	)
+	var i OrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.ProductID,
+		&i.ShopID,
+		&i.Quantity,
+		&i.Price,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProductName,
+		&i.ThumbnailUrl,
+		&i.Currency,
+		&i.ItemStatus,
+		&i.CanceledQuantity,
+	)
+	return i, err
+}
+
+const listOrderItemsByOrderID = `-- name: ListOrderItemsByOrderID :many
+SELECT id, order_id, product_id, shop_id, quantity, price, created_at, updated_at, product_name, thumbnail_url, currency, item_status, canceled_quantity FROM order_items
+WHERE order_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListOrderItemsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error) {
+	rows, err := q.db.Query(ctx, listOrderItemsByOrderID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.ShopID,
+			&i.Quantity,
+			&i.Price,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProductName,
+			&i.ThumbnailUrl,
+			&i.Currency,
+			&i.ItemStatus,
+			&i.CanceledQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateOpenOrderItemsStatus = `-- name: UpdateOpenOrderItemsStatus :exec
+UPDATE order_items
+SET item_status = $2, updated_at = NOW()
+WHERE order_id = $1 AND item_status IN ('PENDING', 'PACKED')
+`
+
+type UpdateOpenOrderItemsStatusParams struct {
+	OrderID    pgtype.UUID     `json:"order_id"`
+	ItemStatus OrderItemStatus `json:"item_status"`
+}
+
+// Đồng bộ các dòng hàng còn mở khi cả đơn được giao cho vận chuyển hoặc bị huỷ.
+func (q *Queries) UpdateOpenOrderItemsStatus(ctx context.Context, arg UpdateOpenOrderItemsStatusParams) error {
+	_, err := q.db.Exec(ctx, updateOpenOrderItemsStatus, arg.OrderID, arg.ItemStatus)
+	return err
+}
+
+const updateOrderItemStatusIfCurrent = `-- name: UpdateOrderItemStatusIfCurrent :one
+UPDATE order_items
+SET item_status = $1, updated_at = NOW()
+WHERE id = $2 AND order_id = $3 AND item_status = $4
+RETURNING id, order_id, product_id, shop_id, quantity, price, created_at, updated_at, product_name, thumbnail_url, currency, item_status, canceled_quantity
+`
+
+type UpdateOrderItemStatusIfCurrentParams struct {
+	NewStatus      OrderItemStatus `json:"new_status"`
+	ID             pgtype.UUID     `json:"id"`
+	OrderID        pgtype.UUID     `json:"order_id"`
+	ExpectedStatus OrderItemStatus `json:"expected_status"`
+}
+
+func (q *Queries) UpdateOrderItemStatusIfCurrent(ctx context.Context, arg UpdateOrderItemStatusIfCurrentParams) (OrderItem, error) {
+	row := q.db.QueryRow(ctx, updateOrderItemStatusIfCurrent,
+		arg.NewStatus,
+		arg.ID,
+		arg.OrderID,
+		arg.ExpectedStatus,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -49,6 +199,11 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.Price,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProductName,
+		&i.ThumbnailUrl,
+		&i.Currency,
+		&i.ItemStatus,
+		&i.CanceledQuantity,
 	)
 	return i, err
 }
@@ -11,21 +11,133 @@ import (
 )
 
 type Querier interface {
+	// Cộng delta (âm khi đơn rời nhóm) vào thống kê ngày tạo đơn của shop.
+	// Giá trị của đơn không tính các dòng hàng người bán đã huỷ riêng (đã được trừ khi huỷ dòng).
+	ApplyShopDailyStatsDelta(ctx context.Context, arg ApplyShopDailyStatsDeltaParams) error
+	// Dòng hàng bị người bán huỷ khi đơn vẫn được tính: trừ số tiền hoàn của dòng khỏi gross_amount
+	ApplyShopDailyStatsItemCancellation(ctx context.Context, id pgtype.UUID) error
+	// delta = 1 khi sản phẩm của đơn được tính vào doanh thu, -1 khi đơn bị huỷ
+	ApplyShopProductDailyStatsDelta(ctx context.Context, arg ApplyShopProductDailyStatsDeltaParams) error
+	// Trừ số lượng và doanh thu của phần dòng hàng bị người bán huỷ
+	ApplyShopProductDailyStatsItemCancellation(ctx context.Context, id pgtype.UUID) error
+	ApproveOrderReturn(ctx context.Context, id pgtype.UUID) (OrderReturn, error)
+	// Huỷ thêm quantity sản phẩm trên dòng hàng còn mở, dòng chuyển sang CANCELED khi đã huỷ hết.
+	CancelOrderItemQuantity(ctx context.Context, arg CancelOrderItemQuantityParams) (OrderItem, error)
+	// Chỉ nhận được đơn đang SHIPPED và chưa có shipper nào nhận.
+	ClaimOrderDelivery(ctx context.Context, arg ClaimOrderDeliveryParams) (OrderDelivery, error)
+	// Đơn không còn khoản thanh toán nào cần hoàn (chưa thanh toán hoặc đã được hoàn bằng cách khác).
+	ClearOrderCancellationRefundFailure(ctx context.Context, orderID pgtype.UUID) (OrderCancellation, error)
 	CleanupOldInboxEvents(ctx context.Context) error
 	CreateInboxEvent(ctx context.Context, arg CreateInboxEventParams) (OrderInboxEvent, error)
+	CreateInboxEventAudit(ctx context.Context, arg CreateInboxEventAuditParams) error
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderCancellation(ctx context.Context, arg CreateOrderCancellationParams) (OrderCancellation, error)
+	CreateOrderInvoice(ctx context.Context, arg CreateOrderInvoiceParams) (OrderInvoice, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreateOrderItemCancellation(ctx context.Context, arg CreateOrderItemCancellationParams) (OrderItemCancellation, error)
+	CreateOrderOutboxEvent(ctx context.Context, arg CreateOrderOutboxEventParams) (OrderOutboxEvent, error)
+	CreateOrderReturn(ctx context.Context, arg CreateOrderReturnParams) (OrderReturn, error)
+	CreateOrderReturnItem(ctx context.Context, arg CreateOrderReturnItemParams) (OrderReturnItem, error)
+	CreateOrderStatusHistory(ctx context.Context, arg CreateOrderStatusHistoryParams) (OrderStatusHistory, error)
+	// Báo thu tiền lặp lại cho cùng đơn không tạo thêm dòng mới (không trả về dòng nào).
+	CreateShipperCashCollection(ctx context.Context, arg CreateShipperCashCollectionParams) (ShipperCashCollection, error)
+	CreateShipperCashSettlement(ctx context.Context, arg CreateShipperCashSettlementParams) (ShipperCashSettlement, error)
+	DeleteOrderStockReleaseFailure(ctx context.Context, orderID pgtype.UUID) error
+	DiscardInboxEvent(ctx context.Context, arg DiscardInboxEventParams) (OrderInboxEvent, error)
+	// Đơn PENDING_PAYMENT đã chờ thanh toán quá thời gian của shop (tính từ lúc đơn chuyển sang PENDING_PAYMENT, đơn cũ chưa có
+	// lịch sử thì tính từ updated_at), shop chưa cấu hình thì dùng default_window_minutes.
+	GetExpiredPendingPaymentOrders(ctx context.Context, arg GetExpiredPendingPaymentOrdersParams) ([]Order, error)
 	GetFailedInboxEvents(ctx context.Context, limit int32) ([]OrderInboxEvent, error)
+	GetInboxEventAudits(ctx context.Context, inboxEventID pgtype.UUID) ([]OrderInboxEventAudit, error)
 	GetInboxEventByEventId(ctx context.Context, eventID string) (OrderInboxEvent, error)
+	GetInboxEventByID(ctx context.Context, id pgtype.UUID) (OrderInboxEvent, error)
 	GetInboxEventStats(ctx context.Context) (GetInboxEventStatsRow, error)
 	GetOrderByID(ctx context.Context, id pgtype.UUID) (Order, error)
+	// Khoá đơn hàng để các thao tác trên dòng hàng của cùng một đơn được thực hiện tuần tự.
+	GetOrderByIDForUpdate(ctx context.Context, id pgtype.UUID) (Order, error)
 	GetOrderByIDWithItems(ctx context.Context, id pgtype.UUID) (GetOrderByIDWithItemsRow, error)
+	GetOrderCancellationByOrderID(ctx context.Context, orderID pgtype.UUID) (OrderCancellation, error)
+	GetOrderDeliveryByOrderID(ctx context.Context, orderID pgtype.UUID) (OrderDelivery, error)
+	GetOrderInvoiceByOrderID(ctx context.Context, orderID pgtype.UUID) (OrderInvoice, error)
+	GetOrderItemCancellationByID(ctx context.Context, id pgtype.UUID) (OrderItemCancellation, error)
+	GetOrderOutboxEventByID(ctx context.Context, id pgtype.UUID) (OrderOutboxEvent, error)
+	GetOrderReturnByID(ctx context.Context, id pgtype.UUID) (OrderReturn, error)
+	GetOrdersByIDsWithItems(ctx context.Context, ids []pgtype.UUID) ([]GetOrdersByIDsWithItemsRow, error)
+	GetOrdersByShopIDWithItems(ctx context.Context, arg GetOrdersByShopIDWithItemsParams) ([]GetOrdersByShopIDWithItemsRow, error)
 	GetOrdersByUserIDWithItems(ctx context.Context, arg GetOrdersByUserIDWithItemsParams) ([]GetOrdersByUserIDWithItemsRow, error)
 	GetPendingInboxEvents(ctx context.Context, limit int32) ([]OrderInboxEvent, error)
+	// Event xếp sau một event FAILED của cùng đơn hàng được giữ lại cho tới khi event đó được retry, để giữ thứ tự theo đơn.
+	GetPendingOrderOutboxEvents(ctx context.Context, limit int32) ([]OrderOutboxEvent, error)
+	// shop_ids được sắp xếp theo khoảng cách tới shipper, đơn của shop gần hơn đứng trước.
+	GetReadyToShipOrders(ctx context.Context, arg GetReadyToShipOrdersParams) ([]Order, error)
+	// Các shop đang có đơn SHIPPED chưa có shipper nhận.
+	GetReadyToShipShopIDs(ctx context.Context) ([]pgtype.UUID, error)
+	// Số lượng đã yêu cầu trả của từng sản phẩm trong đơn, không tính các yêu cầu bị từ chối.
+	GetReturnedQuantitiesByOrderID(ctx context.Context, orderID pgtype.UUID) ([]GetReturnedQuantitiesByOrderIDRow, error)
+	GetShipperCashCollectionByOrderID(ctx context.Context, orderID pgtype.UUID) (ShipperCashCollection, error)
+	GetShopPaymentWindow(ctx context.Context, shopID pgtype.UUID) (ShopPaymentWindow, error)
+	GetShopShippingRate(ctx context.Context, shopID pgtype.UUID) (ShopShippingRate, error)
 	GetStaleOrders(ctx context.Context, arg GetStaleOrdersParams) ([]Order, error)
+	ListInboxEvents(ctx context.Context, arg ListInboxEventsParams) ([]OrderInboxEvent, error)
+	ListOrderCancellationsForRefundRetry(ctx context.Context, arg ListOrderCancellationsForRefundRetryParams) ([]OrderCancellation, error)
+	ListOrderDeliveriesByShipper(ctx context.Context, arg ListOrderDeliveriesByShipperParams) ([]OrderDelivery, error)
+	ListOrderItemCancellationsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]OrderItemCancellation, error)
+	ListOrderItemsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]OrderItem, error)
+	ListOrderReturnItemsByReturnIDs(ctx context.Context, returnIds []pgtype.UUID) ([]OrderReturnItem, error)
+	ListOrderReturnsByOrderID(ctx context.Context, orderID pgtype.UUID) ([]OrderReturn, error)
+	ListOrderReturnsByShopID(ctx context.Context, arg ListOrderReturnsByShopIDParams) ([]OrderReturn, error)
+	ListOrderStatusHistoryByOrderID(ctx context.Context, orderID pgtype.UUID) ([]OrderStatusHistory, error)
+	ListOrderStockReleaseFailuresForRetry(ctx context.Context, arg ListOrderStockReleaseFailuresForRetryParams) ([]OrderStockReleaseFailure, error)
+	// Duyệt theo keyset (updated_at, id) các đơn có trạng thái phụ thuộc vào thanh toán và đã đứng yên tới updated_before,
+	// để không tranh với callback / event thanh toán đang được xử lý.
+	ListOrdersForPaymentReconciliation(ctx context.Context, arg ListOrdersForPaymentReconciliationParams) ([]ListOrdersForPaymentReconciliationRow, error)
+	ListShopDailyStats(ctx context.Context, arg ListShopDailyStatsParams) ([]ShopDailyStat, error)
+	ListShopTopProducts(ctx context.Context, arg ListShopTopProductsParams) ([]ListShopTopProductsRow, error)
+	ListUnconfirmedShipperCashCollections(ctx context.Context, arg ListUnconfirmedShipperCashCollectionsParams) ([]ShipperCashCollection, error)
+	ListUnsettledShipperCashCollections(ctx context.Context, shipperID pgtype.UUID) ([]ShipperCashCollection, error)
+	// Khoá đơn hàng để hai yêu cầu trả hàng đồng thời không vượt quá số lượng đã mua.
+	LockOrderForReturn(ctx context.Context, id pgtype.UUID) error
+	// Khoá các khoản chưa quyết toán để số tiền admin xác nhận khớp đúng với các khoản được quyết toán.
+	LockUnsettledShipperCashCollections(ctx context.Context, arg LockUnsettledShipperCashCollectionsParams) ([]ShipperCashCollection, error)
+	MarkOrderDeliveryDelivered(ctx context.Context, arg MarkOrderDeliveryDeliveredParams) (OrderDelivery, error)
+	MarkOrderDeliveryPickedUp(ctx context.Context, arg MarkOrderDeliveryPickedUpParams) (OrderDelivery, error)
+	// Chỉ cập nhật lần đầu, event hoàn tiền bị gửi lại sẽ không tìm thấy dòng nào.
+	MarkOrderItemCancellationRefunded(ctx context.Context, id pgtype.UUID) (OrderItemCancellation, error)
+	MarkOrderItemCancellationRestocked(ctx context.Context, id pgtype.UUID) (OrderItemCancellation, error)
+	MarkOrderReturnReceived(ctx context.Context, id pgtype.UUID) (OrderReturn, error)
+	MarkOrderReturnRefunded(ctx context.Context, id pgtype.UUID) (OrderReturn, error)
+	MarkOrderReturnRestocked(ctx context.Context, id pgtype.UUID) (OrderReturn, error)
+	MarkShipperCashCollectionConfirmed(ctx context.Context, id pgtype.UUID) (ShipperCashCollection, error)
+	// Khoá bộ đếm của shop tới hết transaction nên các hoá đơn của cùng shop được đánh số lần lượt.
+	NextShopInvoiceNumber(ctx context.Context, shopID pgtype.UUID) (int64, error)
+	// Lưu lỗi của lần gửi yêu cầu hoàn tiền để reconciler gửi lại, bỏ qua nếu yêu cầu đã được gửi thành công.
+	RecordOrderCancellationRefundFailure(ctx context.Context, arg RecordOrderCancellationRefundFailureParams) (OrderCancellation, error)
+	// Lưu lỗi của lần trả tồn kho gần nhất, mỗi lần lỗi tiếp theo của cùng đơn tăng attempts.
+	RecordOrderStockReleaseFailure(ctx context.Context, arg RecordOrderStockReleaseFailureParams) (OrderStockReleaseFailure, error)
+	RejectOrderReturn(ctx context.Context, arg RejectOrderReturnParams) (OrderReturn, error)
+	// from_status là FAILED (hết lượt retry) hoặc PARKED (chưa có handler lúc nhận)
+	ReplayFailedInboxEvents(ctx context.Context, arg ReplayFailedInboxEventsParams) ([]OrderInboxEvent, error)
+	// Đưa event FAILED/PARKED về PENDING với retry_count = 0 để inbox worker xử lý lại từ đầu
+	ReplayInboxEvent(ctx context.Context, arg ReplayInboxEventParams) (OrderInboxEvent, error)
+	ResolveOrderReconciliationReviews(ctx context.Context, arg ResolveOrderReconciliationReviewsParams) (int64, error)
+	// Đưa event FAILED về PENDING với lượt retry mới, các event sau của đơn được publish tiếp sau event này.
+	RetryFailedOrderOutboxEvent(ctx context.Context, id pgtype.UUID) (OrderOutboxEvent, error)
+	SetOrderItemCancellationRefundID(ctx context.Context, arg SetOrderItemCancellationRefundIDParams) (OrderItemCancellation, error)
+	SetOrderReturnRefundID(ctx context.Context, arg SetOrderReturnRefundIDParams) (OrderReturn, error)
+	SettleShipperCashCollections(ctx context.Context, arg SettleShipperCashCollectionsParams) (int64, error)
+	// last_error NULL thì giữ lỗi cũ để admin vẫn thấy nguyên nhân của lần thất bại trước
 	UpdateInboxEventStatus(ctx context.Context, arg UpdateInboxEventStatusParams) (OrderInboxEvent, error)
-	// Bỏ qua bao nhiêu đơn hàng (để qua trang mới)
+	UpdateOrderCancellationRefund(ctx context.Context, arg UpdateOrderCancellationRefundParams) (OrderCancellation, error)
+	// Đồng bộ các dòng hàng còn mở khi cả đơn được giao cho vận chuyển hoặc bị huỷ.
+	UpdateOpenOrderItemsStatus(ctx context.Context, arg UpdateOpenOrderItemsStatusParams) error
+	UpdateOrderItemStatusIfCurrent(ctx context.Context, arg UpdateOrderItemStatusIfCurrentParams) (OrderItem, error)
+	UpdateOrderOutboxEventStatus(ctx context.Context, arg UpdateOrderOutboxEventStatusParams) (OrderOutboxEvent, error)
 	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	UpdateOrderStatusIfCurrent(ctx context.Context, arg UpdateOrderStatusIfCurrentParams) (Order, error)
+	// Phát hiện lại cùng loại lệch của một đơn thì cập nhật trạng thái mới nhất vào dòng đang mở.
+	UpsertOrderReconciliationReview(ctx context.Context, arg UpsertOrderReconciliationReviewParams) (OrderReconciliationReview, error)
+	UpsertShopPaymentWindow(ctx context.Context, arg UpsertShopPaymentWindowParams) (ShopPaymentWindow, error)
+	UpsertShopShippingRate(ctx context.Context, arg UpsertShopShippingRateParams) (ShopShippingRate, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -4,27 +4,61 @@ import (
 	"fmt"
 	"log"
 
+	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	redis_infra "github.com/toji-dev/go-shop/internal/pkg/infra/redis-infra"
 	"github.com/toji-dev/go-shop/internal/pkg/jwt"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/config"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/handler"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
 )
 
 type DependencyContainer struct {
-	config            *config.Config
-	postgreSQL        *postgresql_infra.PostgreSQLService
-	orderRepo         repository.OrderRepository
-	inboxEventRepo    repository.InboxEventRepository // New inbox repository
-	orderUsecase      usecase.OrderUsecase
-	inboxEventUsecase usecase.InboxEventUseCase // New inbox usecase
-	orderHandler      handler.OrderHandler
+	config               *config.Config
+	postgreSQL           *postgresql_infra.PostgreSQLService
+	redis                *redis_infra.RedisService
+	kafkaProducer        kafka_infra.Producer
+	inboxRegistry        *inbox.Registry
+	orderRepo            repository.OrderRepository
+	inboxEventRepo       repository.InboxEventRepository // New inbox repository
+	outboxEventRepo      repository.OutboxEventRepository
+	shippingRateRepo     repository.ShippingRateRepository
+	deliveryRepo         repository.DeliveryRepository
+	returnRepo           repository.ReturnRepository
+	invoiceRepo          repository.InvoiceRepository
+	paymentWindowRepo    repository.PaymentWindowRepository
+	shopStatsRepo        repository.ShopStatsRepository
+	reconciliationRepo   repository.ReconciliationRepository
+	shipperCashRepo      repository.ShipperCashRepository
+	orderUsecase         usecase.OrderUsecase
+	shippingUsecase      usecase.ShippingUseCase
+	deliveryUsecase      usecase.DeliveryUseCase
+	returnUsecase        usecase.ReturnUseCase
+	invoiceUsecase       usecase.InvoiceUseCase
+	paymentWindowUsecase usecase.PaymentWindowUseCase
+	shopAnalyticsUsecase usecase.ShopAnalyticsUseCase
+	orderExpiryUsecase   usecase.OrderExpiryUseCase
+	inboxEventUsecase    usecase.InboxEventUseCase // New inbox usecase
+	orderEventUsecase    usecase.OrderEventUseCase
+	orderHandler         handler.OrderHandler
+	shippingHandler      handler.ShippingHandler
+	deliveryHandler      handler.DeliveryHandler
+	returnHandler        handler.ReturnHandler
+	invoiceHandler       handler.InvoiceHandler
+	paymentWindowHandler handler.PaymentWindowHandler
+	shopAnalyticsHandler handler.ShopAnalyticsHandler
+	inboxHandler         *handler.InboxHandler
+	outboxHandler        handler.OutboxHandler
 
 	shopServiceAdapter    adapter.ShopServiceAdapter
 	productServiceAdapter adapter.ProductServiceAdapter
 	userAdapter           adapter.UserServiceAdapter
+	paymentServiceAdapter adapter.PaymentServiceAdapter
+	cartServiceAdapter    adapter.CartServiceAdapter
 
 	jwt jwt.JwtService
 }
@@ -38,6 +72,12 @@ func NewDependencyContainer(cfg *config.Config) *DependencyContainer {
 		log.Fatalf("failed to initialize PostgreSQL: %v", err)
 	}
 
+	if err := container.initRedis(); err != nil {
+		log.Fatalf("failed to initialize Redis: %v", err)
+	}
+
+	container.initKafkaProducer()
+
 	container.initRepositories()
 
 	container.initShopServiceAdapter()
@@ -46,6 +86,10 @@ func NewDependencyContainer(cfg *config.Config) *DependencyContainer {
 
 	container.initUserServiceAdapter()
 
+	container.initPaymentServiceAdapter()
+
+	container.initCartServiceAdapter()
+
 	container.initUseCases()
 
 	container.initOrderHandler()
@@ -55,6 +99,23 @@ func NewDependencyContainer(cfg *config.Config) *DependencyContainer {
 	return container
 }
 
+func (sc *DependencyContainer) initRedis() error {
+	redisService := redis_infra.NewRedisService(sc.config.Redis.Host, sc.config.Redis.Port, sc.config.Redis.Password, sc.config.Redis.DB)
+
+	if err := redisService.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	sc.redis = redisService
+	log.Println("Redis service initialized")
+	return nil
+}
+
+func (sc *DependencyContainer) initKafkaProducer() {
+	sc.kafkaProducer = kafka_infra.NewProducer(sc.config.Kafka.Brokers)
+	log.Println("Kafka producer initialized")
+}
+
 func (sc *DependencyContainer) initPostgreSQL() error {
 	pgConfig := &postgresql_infra.DatabaseConfig{
 		Host:         sc.config.Database.Host,
@@ -82,27 +143,125 @@ func (sc *DependencyContainer) initPostgreSQL() error {
 func (sc *DependencyContainer) initRepositories() {
 	sc.orderRepo = repository.NewOrderRepository(sc.postgreSQL)
 	sc.inboxEventRepo = repository.NewInboxEventRepository(sc.postgreSQL)
-	log.Println("Order and inbox event repositories initialized")
+	sc.outboxEventRepo = repository.NewOutboxEventRepository(sc.postgreSQL)
+	sc.shippingRateRepo = repository.NewShippingRateRepository(sc.postgreSQL)
+	sc.deliveryRepo = repository.NewDeliveryRepository(sc.postgreSQL)
+	sc.returnRepo = repository.NewReturnRepository(sc.postgreSQL)
+	sc.invoiceRepo = repository.NewInvoiceRepository(sc.postgreSQL)
+	sc.paymentWindowRepo = repository.NewPaymentWindowRepository(sc.postgreSQL)
+	sc.shopStatsRepo = repository.NewShopStatsRepository(sc.postgreSQL)
+	sc.reconciliationRepo = repository.NewReconciliationRepository(sc.postgreSQL)
+	sc.shipperCashRepo = repository.NewShipperCashRepository(sc.postgreSQL)
+	log.Println("Order, inbox, outbox event, shipping rate, delivery, return, invoice, payment window, shop stats, reconciliation and shipper cash repositories initialized")
 }
 
 func (sc *DependencyContainer) initUseCases() {
+	sc.shippingUsecase = usecase.NewShippingUseCase(
+		sc.shippingRateRepo,
+		sc.shopServiceAdapter,
+		sc.productServiceAdapter,
+		sc.userAdapter,
+		sc.defaultShippingRates(),
+		sc.config.Shipping.DefaultItemWeightGrams,
+	)
+
 	sc.orderUsecase = usecase.NewOrderUsecase(
 		sc.orderRepo,
 		sc.shopServiceAdapter,
 		sc.productServiceAdapter,
 		sc.userAdapter,
+		sc.paymentServiceAdapter,
+		sc.cartServiceAdapter,
+		sc.shippingUsecase,
+	)
+
+	sc.deliveryUsecase = usecase.NewDeliveryUseCase(
+		sc.deliveryRepo,
+		sc.orderRepo,
+		sc.shipperCashRepo,
+		sc.shopServiceAdapter,
+		sc.paymentServiceAdapter,
+		sc.config.Delivery.DefaultSearchRadiusKm,
+		sc.config.Delivery.MaxSearchRadiusKm,
+	)
+
+	sc.returnUsecase = usecase.NewReturnUseCase(
+		sc.returnRepo,
+		sc.orderRepo,
+		sc.shopServiceAdapter,
+		sc.productServiceAdapter,
+		sc.paymentServiceAdapter,
+		sc.config.Returns.Window,
+	)
+
+	sc.invoiceUsecase = usecase.NewInvoiceUseCase(
+		sc.invoiceRepo,
+		sc.orderRepo,
+		sc.shopServiceAdapter,
+		sc.config.Invoice.TaxRate,
+		sc.config.Invoice.NumberPrefix,
+	)
+
+	sc.paymentWindowUsecase = usecase.NewPaymentWindowUseCase(
+		sc.paymentWindowRepo,
+		sc.shopServiceAdapter,
+		sc.config.OrderExpiry.DefaultPaymentWindow,
+	)
+
+	sc.shopAnalyticsUsecase = usecase.NewShopAnalyticsUseCase(
+		sc.shopStatsRepo,
+		sc.shopServiceAdapter,
+	)
+
+	sc.orderExpiryUsecase = usecase.NewOrderExpiryUseCase(
+		sc.orderRepo,
+		sc.productServiceAdapter,
+		sc.paymentServiceAdapter,
+		sc.config.OrderExpiry.DefaultPaymentWindow,
+		sc.config.OrderExpiry.BatchSize,
 	)
 
+	sc.inboxRegistry = inbox.NewRegistry()
 	sc.inboxEventUsecase = usecase.NewInboxEventUseCase(
+		sc.inboxRegistry,
 		sc.inboxEventRepo,
 		sc.orderRepo,
+		sc.returnRepo,
+	)
+
+	sc.orderEventUsecase = usecase.NewOrderEventUseCase(
+		sc.outboxEventRepo,
+		sc.kafkaProducer,
 	)
-	log.Println("Order and inbox event use cases initialized")
+	log.Println("Order, shipping, delivery, return, invoice, payment window, shop analytics, order expiry, inbox and order event use cases initialized")
+}
+
+func (sc *DependencyContainer) defaultShippingRates() domain.ShippingRateTable {
+	rates := domain.ShippingRateTable{
+		Currency:    sc.config.Shipping.DefaultCurrency,
+		Zones:       make([]domain.ShippingZoneTier, 0, len(sc.config.Shipping.DefaultZones)),
+		WeightTiers: make([]domain.ShippingWeightTier, 0, len(sc.config.Shipping.DefaultWeightTiers)),
+	}
+	for _, tier := range sc.config.Shipping.DefaultZones {
+		rates.Zones = append(rates.Zones, domain.ShippingZoneTier{MaxDistanceKm: tier.Limit, BaseFee: tier.Fee})
+	}
+	for _, tier := range sc.config.Shipping.DefaultWeightTiers {
+		rates.WeightTiers = append(rates.WeightTiers, domain.ShippingWeightTier{MaxWeightGrams: int(tier.Limit), Surcharge: tier.Fee})
+	}
+	return rates
 }
 
 func (sc *DependencyContainer) initOrderHandler() {
 	sc.orderHandler = handler.NewOrderHandler(sc.orderUsecase)
-	log.Println("Order handler initialized")
+	sc.shippingHandler = handler.NewShippingHandler(sc.shippingUsecase)
+	sc.deliveryHandler = handler.NewDeliveryHandler(sc.deliveryUsecase)
+	sc.returnHandler = handler.NewReturnHandler(sc.returnUsecase)
+	sc.invoiceHandler = handler.NewInvoiceHandler(sc.invoiceUsecase)
+	sc.paymentWindowHandler = handler.NewPaymentWindowHandler(sc.paymentWindowUsecase)
+	sc.shopAnalyticsHandler = handler.NewShopAnalyticsHandler(sc.shopAnalyticsUsecase)
+	sc.inboxHandler = handler.NewInboxHandler(sc.inboxEventUsecase)
+	sc.outboxHandler = handler.NewOutboxHandler(sc.orderEventUsecase)
+	log.Println("Order, shipping, delivery, return, invoice and payment window handlers initialized")
 }
 
 func (sc *DependencyContainer) initShopServiceAdapter() error {
@@ -153,6 +312,38 @@ func (sc *DependencyContainer) initUserServiceAdapter() error {
 	return nil
 }
 
+func (sc *DependencyContainer) initPaymentServiceAdapter() error {
+	paymentServiceAddr := fmt.Sprintf("%s:%s", sc.config.PaymentServiceAdapter.Host, sc.config.PaymentServiceAdapter.Port)
+	if paymentServiceAddr == "" {
+		return fmt.Errorf("payment service address is not configured")
+	}
+
+	adapter, err := adapter.NewGrpcPaymentAdapter(paymentServiceAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create payment service adapter: %w", err)
+	}
+
+	sc.paymentServiceAdapter = adapter
+	log.Println("Payment service adapter initialized")
+	return nil
+}
+
+func (sc *DependencyContainer) initCartServiceAdapter() error {
+	cartServiceAddr := fmt.Sprintf("%s:%s", sc.config.CartServiceAdapter.Host, sc.config.CartServiceAdapter.Port)
+	if cartServiceAddr == "" {
+		return fmt.Errorf("cart service address is not configured")
+	}
+
+	adapter, err := adapter.NewGrpcCartAdapter(cartServiceAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create cart service adapter: %w", err)
+	}
+
+	sc.cartServiceAdapter = adapter
+	log.Println("Cart service adapter initialized")
+	return nil
+}
+
 func (sc *DependencyContainer) initJwtService() error {
 	jwtCfg := jwt.JWTConfig{
 		SecretKey:       sc.config.Jwt.SecretKey,
@@ -169,18 +360,66 @@ func (sc *DependencyContainer) GetOrderHandler() handler.OrderHandler {
 	return sc.orderHandler
 }
 
+func (sc *DependencyContainer) GetShippingHandler() handler.ShippingHandler {
+	return sc.shippingHandler
+}
+
+func (sc *DependencyContainer) GetDeliveryHandler() handler.DeliveryHandler {
+	return sc.deliveryHandler
+}
+
+func (sc *DependencyContainer) GetReturnHandler() handler.ReturnHandler {
+	return sc.returnHandler
+}
+
+func (sc *DependencyContainer) GetInvoiceHandler() handler.InvoiceHandler {
+	return sc.invoiceHandler
+}
+
+func (sc *DependencyContainer) GetPaymentWindowHandler() handler.PaymentWindowHandler {
+	return sc.paymentWindowHandler
+}
+
+func (sc *DependencyContainer) GetShopAnalyticsHandler() handler.ShopAnalyticsHandler {
+	return sc.shopAnalyticsHandler
+}
+
+func (sc *DependencyContainer) GetInboxHandler() *handler.InboxHandler {
+	return sc.inboxHandler
+}
+
+func (sc *DependencyContainer) GetOutboxHandler() handler.OutboxHandler {
+	return sc.outboxHandler
+}
+
 func (sc *DependencyContainer) GetConfig() *config.Config {
 	return sc.config
 }
 
+func (sc *DependencyContainer) GetRedisService() *redis_infra.RedisService {
+	return sc.redis
+}
+
 func (sc *DependencyContainer) GetOrderRepository() repository.OrderRepository {
 	return sc.orderRepo
 }
 
+func (sc *DependencyContainer) GetReconciliationRepository() repository.ReconciliationRepository {
+	return sc.reconciliationRepo
+}
+
+func (sc *DependencyContainer) GetShipperCashRepository() repository.ShipperCashRepository {
+	return sc.shipperCashRepo
+}
+
 func (sc *DependencyContainer) GetProductServiceAdapter() adapter.ProductServiceAdapter {
 	return sc.productServiceAdapter
 }
 
+func (sc *DependencyContainer) GetPaymentServiceAdapter() adapter.PaymentServiceAdapter {
+	return sc.paymentServiceAdapter
+}
+
 func (sc *DependencyContainer) GetOrderUsecase() usecase.OrderUsecase {
 	return sc.orderUsecase
 }
@@ -189,6 +428,22 @@ func (sc *DependencyContainer) GetInboxEventUsecase() usecase.InboxEventUseCase
 	return sc.inboxEventUsecase
 }
 
+func (sc *DependencyContainer) GetOrderEventUsecase() usecase.OrderEventUseCase {
+	return sc.orderEventUsecase
+}
+
+func (sc *DependencyContainer) GetOrderExpiryUsecase() usecase.OrderExpiryUseCase {
+	return sc.orderExpiryUsecase
+}
+
+func (sc *DependencyContainer) GetInboxRegistry() *inbox.Registry {
+	return sc.inboxRegistry
+}
+
+func (sc *DependencyContainer) GetKafkaProducer() kafka_infra.Producer {
+	return sc.kafkaProducer
+}
+
 func (sc *DependencyContainer) GetInboxEventRepository() repository.InboxEventRepository {
 	return sc.inboxEventRepo
 }
@@ -0,0 +1,14 @@
+package domain
+
+// CheckoutResult là kết quả checkout giỏ hàng: mỗi shop một đơn hàng, dùng chung CheckoutID.
+type CheckoutResult struct {
+	CheckoutID string
+	Orders     []*Order
+	Failures   []CheckoutFailure
+}
+
+// CheckoutFailure ghi lại shop không tạo được đơn hàng trong lần checkout.
+type CheckoutFailure struct {
+	ShopID string
+	Reason string
+}
@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+// ErrOrderStatusChanged được trả về khi trạng thái đơn hàng đã bị thay đổi bởi một tiến trình khác
+// trong lúc đang cập nhật (optimistic concurrency).
+var ErrOrderStatusChanged = errors.New("order status has been changed concurrently")
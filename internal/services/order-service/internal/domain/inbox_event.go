@@ -1,6 +1,13 @@
 package domain
 
-import "time"
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // InboxEventStatus represents the status of an inbox event
 type InboxEventStatus string
@@ -9,8 +16,27 @@ const (
 	InboxEventStatusPending   InboxEventStatus = "PENDING"
 	InboxEventStatusProcessed InboxEventStatus = "PROCESSED"
 	InboxEventStatusFailed    InboxEventStatus = "FAILED"
+	InboxEventStatusDiscarded InboxEventStatus = "DISCARDED" // Admin đã huỷ bỏ event hết lượt retry, không xử lý lại nữa
+	InboxEventStatusParked    InboxEventStatus = "PARKED"    // Không có handler cho (source, event_type, version), chờ admin xử lý
 )
 
+// ErrInboxEventStatusChanged: event đã đổi trạng thái trước khi admin replay hoặc discard.
+var ErrInboxEventStatusChanged = errors.New("inbox event status has changed")
+
+// IsValid kiểm tra status có thuộc tập trạng thái inbox event đã biết hay không.
+func (s InboxEventStatus) IsValid() bool {
+	switch s {
+	case InboxEventStatusPending, InboxEventStatusProcessed, InboxEventStatusFailed, InboxEventStatusDiscarded, InboxEventStatusParked:
+		return true
+	}
+	return false
+}
+
+// IsDeadLetter: event FAILED (hết lượt retry) hoặc PARKED (chưa có handler) chỉ được xử lý tiếp khi admin replay.
+func (s InboxEventStatus) IsDeadLetter() bool {
+	return s == InboxEventStatusFailed || s == InboxEventStatusParked
+}
+
 // InboxEventType represents different types of events that can be received
 type InboxEventType string
 
@@ -25,6 +51,7 @@ type InboxEvent struct {
 	ID            string           `json:"id"`
 	EventID       string           `json:"event_id"`       // UUID từ hệ thống gửi
 	EventType     string           `json:"event_type"`     // Loại event
+	EventVersion  int              `json:"event_version"`  // Version schema của payload, 0 nếu bên gửi không khai báo
 	SourceService string           `json:"source_service"` // Service gửi event
 	Payload       string           `json:"payload"`        // Dữ liệu event dạng JSON
 	EventStatus   InboxEventStatus `json:"event_status"`   // Trạng thái xử lý
@@ -32,6 +59,7 @@ type InboxEvent struct {
 	MaxRetry      int              `json:"max_retry"`      // Số lần retry tối đa
 	ReceivedAt    time.Time        `json:"received_at"`    // Thời gian nhận event
 	ProcessedAt   *time.Time       `json:"processed_at"`   // Thời gian xử lý xong
+	LastError     *string          `json:"last_error"`     // Lỗi của lần xử lý thất bại gần nhất
 	CreatedAt     time.Time        `json:"created_at"`
 	UpdatedAt     time.Time        `json:"updated_at"`
 }
@@ -43,3 +71,95 @@ type EventPayload struct {
 	EventSource string            `json:"event_source,omitempty"`
 	Data        map[string]string `json:"data,omitempty"`
 }
+
+// RefundEventPayload là payload của event REFUND_SUCCEEDED từ payment-service. Partial là true khi đơn hàng
+// mới chỉ được hoàn một phần (ví dụ hoàn tiền cho yêu cầu trả hàng), ReferenceID là reference gửi kèm
+// lúc yêu cầu hoàn tiền.
+type RefundEventPayload struct {
+	OrderID     string  `json:"order_id"`
+	PaymentID   string  `json:"payment_id,omitempty"`
+	RefundID    string  `json:"refund_id,omitempty"`
+	ReferenceID string  `json:"reference_id,omitempty"`
+	Amount      float64 `json:"amount,omitempty"`
+	Partial     bool    `json:"partial,omitempty"`
+}
+
+// Các thao tác của admin trên inbox event, được ghi vào order_inbox_event_audits.
+const (
+	InboxEventActionReplay  = "REPLAY"
+	InboxEventActionDiscard = "DISCARD"
+)
+
+// InboxEventAudit là một dòng nhật ký thao tác của admin trên inbox event.
+type InboxEventAudit struct {
+	ID        int64            `json:"id"`
+	EventID   string           `json:"event_id"`
+	Action    string           `json:"action"`
+	OldStatus InboxEventStatus `json:"old_status"`
+	NewStatus InboxEventStatus `json:"new_status"`
+	Actor     string           `json:"actor"`
+	Reason    string           `json:"reason"`
+	TraceID   *string          `json:"trace_id,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// InboxEventCursor là vị trí keyset (received_at, id) của inbox event cuối cùng trong trang trước.
+type InboxEventCursor struct {
+	ReceivedAt time.Time
+	ID         string
+}
+
+// Encode trả về cursor dạng opaque để client gửi lại ở trang tiếp theo.
+func (c InboxEventCursor) Encode() string {
+	raw := c.ReceivedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeInboxEventCursor parse cursor được tạo bởi InboxEventCursor.Encode.
+func DecodeInboxEventCursor(s string) (*InboxEventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("cursor is not valid base64")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("cursor has invalid format")
+	}
+
+	receivedAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errors.New("cursor has invalid timestamp")
+	}
+
+	if _, err := uuid.Parse(parts[1]); err != nil {
+		return nil, errors.New("cursor has invalid event id")
+	}
+
+	return &InboxEventCursor{ReceivedAt: receivedAt, ID: parts[1]}, nil
+}
+
+// InboxEventListFilter chứa các điều kiện lọc khi admin liệt kê inbox event, phân trang keyset theo (received_at, id).
+type InboxEventListFilter struct {
+	Status       *InboxEventStatus
+	EventType    string
+	ReceivedFrom *time.Time
+	ReceivedTo   *time.Time
+	Cursor       *InboxEventCursor
+	Limit        int
+}
+
+// InboxEventPage là một trang kết quả; NextCursor là nil khi đã hết dữ liệu.
+type InboxEventPage struct {
+	Events     []*InboxEvent
+	NextCursor *InboxEventCursor
+}
+
+// InboxEventReplayFilter chọn các event FAILED hoặc PARKED (theo Status) được replay hàng loạt, tối đa Limit event mỗi lần.
+type InboxEventReplayFilter struct {
+	Status       InboxEventStatus
+	EventType    string
+	ReceivedFrom *time.Time
+	ReceivedTo   *time.Time
+	Limit        int
+}
@@ -0,0 +1,48 @@
+package domain_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestInboxEventCursor_RoundTrip(t *testing.T) {
+	cursor := domain.InboxEventCursor{
+		ReceivedAt: time.Date(2025, 3, 1, 8, 30, 15, 123456789, time.FixedZone("ICT", 7*3600)),
+		ID:         "0b0f6f2e-8d8e-4c4f-9a57-0d6f0a5b2c11",
+	}
+
+	decoded, err := domain.DecodeInboxEventCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.ReceivedAt.Equal(cursor.ReceivedAt) || decoded.ID != cursor.ID {
+		t.Errorf("decoded = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeInboxEventCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	testCases := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Not base64", cursor: "%%%"},
+		{name: "Missing separator", cursor: encode("2025-03-01T08:30:15Z")},
+		{name: "Invalid timestamp", cursor: encode("yesterday|0b0f6f2e-8d8e-4c4f-9a57-0d6f0a5b2c11")},
+		{name: "Invalid event id", cursor: encode("2025-03-01T08:30:15Z|evt-1")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := domain.DecodeInboxEventCursor(tc.cursor); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+// Invoice là hoá đơn VAT của một đơn hàng. Số hoá đơn, số tiền, thông tin người bán và các dòng sản phẩm được chụp lại
+// lúc phát hành; người mua lấy từ snapshot của đơn hàng nên cũng không thay đổi theo thời gian.
+type Invoice struct {
+	ID             string           `json:"id"`
+	OrderID        string           `json:"order_id"`
+	ShopID         string           `json:"shop_id"`
+	SequenceNumber int64            `json:"sequence_number"`
+	InvoiceNumber  string           `json:"invoice_number"`
+	Currency       string           `json:"currency"`
+	Subtotal       money.Money      `json:"subtotal"` // Tổng tiền hàng trước giảm giá
+	DiscountAmount money.Money      `json:"discount_amount"`
+	ShippingFee    money.Money      `json:"shipping_fee"`
+	TaxRate        float64          `json:"tax_rate"`   // Ví dụ 0.1 cho VAT 10%
+	TaxAmount      money.Money      `json:"tax_amount"` // Phần thuế đã bao gồm trong TotalAmount
+	TotalAmount    money.Money      `json:"total_amount"`
+	Seller         InvoiceSeller    `json:"seller"`
+	Buyer          *ShippingAddress `json:"buyer,omitempty"`
+	Lines          []InvoiceLine    `json:"lines"`
+	IssuedAt       time.Time        `json:"issued_at"`
+}
+
+// InvoiceSeller là thông tin shop tại thời điểm phát hành hoá đơn.
+type InvoiceSeller struct {
+	ShopID   string `json:"shop_id"`
+	Name     string `json:"name"`
+	Phone    string `json:"phone,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Street   string `json:"street,omitempty"`
+	Ward     string `json:"ward,omitempty"`
+	District string `json:"district,omitempty"`
+	City     string `json:"city,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+type InvoiceLine struct {
+	ProductName string      `json:"product_name"`
+	Quantity    int         `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"`
+	Amount      money.Money `json:"amount"`
+}
+
+// IsInvoiceable: chỉ phát hành hoá đơn cho đơn đã thanh toán và chưa bị huỷ / hoàn tiền.
+func (o *Order) IsInvoiceable() bool {
+	switch o.Status {
+	case OrderStatusPROCESSING, OrderStatusCONFIRMED, OrderStatusSHIPPED, OrderStatusDELIVERING, OrderStatusDELIVERED:
+		return true
+	}
+	return false
+}
+
+// NewInvoice tính các khoản của hoá đơn từ đơn hàng. Giá bán đã bao gồm VAT nên thuế được tách ra từ
+// số tiền khách phải trả thay vì cộng thêm, tổng hoá đơn luôn khớp với số tiền đã thanh toán.
+func NewInvoice(order *Order, seller InvoiceSeller, taxRate float64) *Invoice {
+	invoice := &Invoice{
+		OrderID:        order.ID,
+		ShopID:         order.ShopID,
+		Currency:       order.Currency(),
+		Subtotal:       order.TotalAmount,
+		DiscountAmount: order.DiscountAmount,
+		ShippingFee:    order.ShippingFee,
+		TaxRate:        taxRate,
+		TaxAmount:      money.Zero(order.Currency()),
+		TotalAmount:    order.FinalPrice,
+		Seller:         seller,
+	}
+	if taxRate > 0 {
+		// Làm tròn phần chưa thuế tới đơn vị tiền nhỏ nhất, thuế là phần còn lại để thuế + chưa thuế = tổng
+		net := int64(math.Round(float64(order.FinalPrice.Amount) / (1 + taxRate)))
+		invoice.TaxAmount = money.New(order.FinalPrice.Amount-net, order.Currency())
+	}
+	invoice.Lines = invoiceLinesFromOrder(order)
+	invoice.AttachOrder(order)
+	return invoice
+}
+
+// AttachOrder gắn người mua từ snapshot của đơn hàng vào hoá đơn. Dòng sản phẩm được chụp lại lúc phát hành,
+// chỉ hoá đơn phát hành trước khi lưu dòng sản phẩm mới lấy dòng từ đơn hàng.
+func (i *Invoice) AttachOrder(order *Order) {
+	i.Buyer = order.ShippingAddress
+	if i.Lines == nil {
+		i.Lines = invoiceLinesFromOrder(order)
+	}
+}
+
+func invoiceLinesFromOrder(order *Order) []InvoiceLine {
+	lines := make([]InvoiceLine, len(order.Items))
+	for idx, item := range order.Items {
+		lines[idx] = InvoiceLine{
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			Amount:      item.LineTotal(),
+		}
+	}
+	return lines
+}
+
+// NetAmount là tổng tiền chưa gồm thuế.
+func (i *Invoice) NetAmount() money.Money {
+	return money.New(i.TotalAmount.Amount-i.TaxAmount.Amount, i.Currency)
+}
+
+// FormatInvoiceNumber tạo số hoá đơn dạng PREFIX-SHOP-000001, phần SHOP là 8 ký tự đầu của shop ID
+// để số hoá đơn của các shop không trùng nhau.
+func FormatInvoiceNumber(prefix string, shopID string, sequence int64) string {
+	shopCode := strings.ToUpper(strings.ReplaceAll(shopID, "-", ""))
+	if len(shopCode) > 8 {
+		shopCode = shopCode[:8]
+	}
+	return fmt.Sprintf("%s-%s-%06d", prefix, shopCode, sequence)
+}
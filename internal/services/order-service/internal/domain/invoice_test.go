@@ -0,0 +1,147 @@
+package domain_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func newInvoiceOrder() *domain.Order {
+	return &domain.Order{
+		ID:              "order-1",
+		ShopID:          "shop-1",
+		TotalAmount:     money.New(200000, "VND"),
+		DiscountAmount:  money.New(20000, "VND"),
+		ShippingFee:     money.New(30000, "VND"),
+		FinalPrice:      money.New(210000, "VND"),
+		ShippingAddress: &domain.ShippingAddress{RecipientName: "Nguyen Van A"},
+		Items: []domain.OrderItem{
+			{ID: "a", ProductName: "Ao thun", Quantity: 2, Price: money.New(50000, "VND")},
+			{ID: "b", ProductName: "Quan jean", Quantity: 1, Price: money.New(100000, "VND")},
+		},
+	}
+}
+
+func TestNewInvoice(t *testing.T) {
+	seller := domain.InvoiceSeller{ShopID: "shop-1", Name: "Shop A"}
+
+	testCases := []struct {
+		name        string
+		taxRate     float64
+		expectedTax money.Money
+	}{
+		{
+			name:        "VAT is extracted from the paid amount",
+			taxRate:     0.1,
+			expectedTax: money.New(19091, "VND"),
+		},
+		{
+			name:        "No VAT",
+			taxRate:     0,
+			expectedTax: money.Zero("VND"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := newInvoiceOrder()
+			invoice := domain.NewInvoice(order, seller, tc.taxRate)
+
+			if !invoice.TaxAmount.Equal(tc.expectedTax) {
+				t.Errorf("tax = %s, want %s", invoice.TaxAmount, tc.expectedTax)
+			}
+			if !invoice.TotalAmount.Equal(order.FinalPrice) {
+				t.Errorf("total = %s, want paid amount %s", invoice.TotalAmount, order.FinalPrice)
+			}
+			if net := invoice.NetAmount(); net.Amount+invoice.TaxAmount.Amount != invoice.TotalAmount.Amount {
+				t.Errorf("net %s + tax %s != total %s", net, invoice.TaxAmount, invoice.TotalAmount)
+			}
+			if !invoice.Subtotal.Equal(order.TotalAmount) || !invoice.DiscountAmount.Equal(order.DiscountAmount) || !invoice.ShippingFee.Equal(order.ShippingFee) {
+				t.Errorf("invoice amounts = %+v, want amounts of order", invoice)
+			}
+			if invoice.Currency != "VND" || invoice.Seller != seller || invoice.Buyer != order.ShippingAddress {
+				t.Errorf("invoice = %+v, want VND invoice of seller and buyer of order", invoice)
+			}
+
+			expectedLines := []domain.InvoiceLine{
+				{ProductName: "Ao thun", Quantity: 2, UnitPrice: money.New(50000, "VND"), Amount: money.New(100000, "VND")},
+				{ProductName: "Quan jean", Quantity: 1, UnitPrice: money.New(100000, "VND"), Amount: money.New(100000, "VND")},
+			}
+			if !reflect.DeepEqual(invoice.Lines, expectedLines) {
+				t.Errorf("lines = %+v, want %+v", invoice.Lines, expectedLines)
+			}
+		})
+	}
+}
+
+func TestInvoice_AttachOrder(t *testing.T) {
+	issued := domain.NewInvoice(newInvoiceOrder(), domain.InvoiceSeller{Name: "Shop A"}, 0.1)
+	issuedLines := append([]domain.InvoiceLine(nil), issued.Lines...)
+
+	// Đơn hàng thay đổi sau khi phát hành (người bán huỷ bớt một dòng)
+	order := newInvoiceOrder()
+	order.Items = order.Items[:1]
+
+	t.Run("Stored lines are kept", func(t *testing.T) {
+		invoice := &domain.Invoice{Lines: issuedLines}
+		invoice.AttachOrder(order)
+
+		if !reflect.DeepEqual(invoice.Lines, issuedLines) {
+			t.Errorf("lines = %+v, want lines at issue time %+v", invoice.Lines, issuedLines)
+		}
+		if invoice.Buyer != order.ShippingAddress {
+			t.Errorf("buyer = %+v, want buyer of order", invoice.Buyer)
+		}
+	})
+
+	t.Run("Invoice without stored lines uses the order", func(t *testing.T) {
+		invoice := &domain.Invoice{}
+		invoice.AttachOrder(order)
+
+		if len(invoice.Lines) != 1 || invoice.Lines[0].ProductName != "Ao thun" {
+			t.Errorf("lines = %+v, want lines of order", invoice.Lines)
+		}
+	})
+}
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		prefix   string
+		shopID   string
+		sequence int64
+		expected string
+	}{
+		{
+			name:     "Shop code is the first 8 characters of shop ID",
+			prefix:   "INV",
+			shopID:   "3f2b1c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+			sequence: 42,
+			expected: "INV-3F2B1C4D-000042",
+		},
+		{
+			name:     "Sequence wider than padding",
+			prefix:   "GS",
+			shopID:   "3f2b1c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+			sequence: 1234567,
+			expected: "GS-3F2B1C4D-1234567",
+		},
+		{
+			name:     "Short shop ID is kept",
+			prefix:   "INV",
+			shopID:   "ab-c",
+			sequence: 1,
+			expected: "INV-ABC-000001",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.FormatInvoiceNumber(tc.prefix, tc.shopID, tc.sequence); got != tc.expected {
+				t.Errorf("FormatInvoiceNumber() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "github.com/toji-dev/go-shop/internal/pkg/money"
+
 type OrderStatus string
 
 const (
@@ -7,25 +9,71 @@ const (
 	OrderStatusPENDINGPAYMENT OrderStatus = "PENDING_PAYMENT"
 	OrderStatusPAYMENTFAILED  OrderStatus = "PAYMENT_FAILED"
 	OrderStatusPROCESSING     OrderStatus = "PROCESSING"
+	OrderStatusCONFIRMED      OrderStatus = "CONFIRMED" // Người bán đã xác nhận đơn, đang chuẩn bị hàng
 	OrderStatusSHIPPED        OrderStatus = "SHIPPED"
 	OrderStatusDELIVERING     OrderStatus = "DELIVERING"
 	OrderStatusDELIVERED      OrderStatus = "DELIVERED"
 	OrderStatusCANCELED       OrderStatus = "CANCELED"
 	OrderStatusREFUNDED       OrderStatus = "REFUNDED"
+	OrderStatusFAILED         OrderStatus = "FAILED"
 )
 
 type Order struct {
-	ID                string      `json:"id"`
-	OwnerID           string      `json:"customer_id"`
-	ShopID            string      `json:"shop_id"`
-	ShippingAddressID string      `json:"shipping_address_id"`
-	PromotionCode     *string     `json:"promotion_code,omitempty"`
-	ShippingFee       float64     `json:"shipping_fee"`
-	DiscountAmount    float64     `json:"discount_amount"`
-	TotalAmount       float64     `json:"total_amount"`
-	FinalPrice        float64     `json:"final_price"`
-	Status            OrderStatus `json:"status"`
-	Items             []OrderItem `json:"items"`
-	CreatedAt         string      `json:"created_at"`
-	UpdatedAt         string      `json:"updated_at"`
+	ID                string           `json:"id"`
+	OwnerID           string           `json:"customer_id"`
+	ShopID            string           `json:"shop_id"`
+	ShippingAddressID string           `json:"shipping_address_id"`
+	ShippingAddress   *ShippingAddress `json:"shipping_address,omitempty"`
+	PromotionCode     *string          `json:"promotion_code,omitempty"`
+	CheckoutID        *string          `json:"checkout_id,omitempty"`
+	ShippingFee       money.Money      `json:"shipping_fee"`
+	DiscountAmount    money.Money      `json:"discount_amount"`
+	TotalAmount       money.Money      `json:"total_amount"` // Tổng tiền hàng trước giảm giá và phí vận chuyển
+	FinalPrice        money.Money      `json:"final_price"`  // Số tiền khách phải trả
+	Status            OrderStatus      `json:"status"`
+	Items             []OrderItem      `json:"items"`
+	CreatedAt         string           `json:"created_at"`
+	UpdatedAt         string           `json:"updated_at"`
+}
+
+// Currency trả về tiền tệ của đơn hàng, mọi khoản tiền của đơn cùng một tiền tệ.
+func (o *Order) Currency() string {
+	return o.FinalPrice.Currency
+}
+
+// netOfDiscount trừ khỏi amount phần giảm giá của đơn được chia theo tỉ lệ amount trên tổng tiền hàng
+// (làm tròn tới đơn vị tiền nhỏ nhất).
+func (o *Order) netOfDiscount(amount money.Money) money.Money {
+	if o.TotalAmount.IsPositive() && o.DiscountAmount.IsPositive() {
+		discount := o.DiscountAmount.Min(o.TotalAmount)
+		return amount.MulRatio(o.TotalAmount.Amount-discount.Amount, o.TotalAmount.Amount)
+	}
+	return amount
+}
+
+// IsValid kiểm tra status có thuộc tập trạng thái đơn hàng đã biết hay không.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPENDING, OrderStatusPENDINGPAYMENT, OrderStatusPAYMENTFAILED,
+		OrderStatusPROCESSING, OrderStatusCONFIRMED, OrderStatusSHIPPED, OrderStatusDELIVERING,
+		OrderStatusDELIVERED, OrderStatusCANCELED, OrderStatusREFUNDED, OrderStatusFAILED:
+		return true
+	}
+	return false
+}
+
+// IsCancelableByCustomer: khách hàng huỷ được đơn khi bảng chuyển trạng thái cho phép huỷ, trừ khi đã có dòng hàng
+// được giao cho vận chuyển.
+func (o *Order) IsCancelableByCustomer() bool {
+	return o.Status.CanTransitionTo(OrderStatusCANCELED) && !o.HasShippedItems()
+}
+
+// IsRejectableBySeller: người bán chỉ được từ chối đơn khi chưa giao cho vận chuyển. Đơn đã có dòng hàng
+// được giao thì chỉ huỷ được từng dòng còn lại.
+func (o *Order) IsRejectableBySeller() bool {
+	switch o.Status {
+	case OrderStatusPENDINGPAYMENT, OrderStatusPROCESSING, OrderStatusCONFIRMED:
+		return !o.HasShippedItems() && o.Status.CanTransitionTo(OrderStatusCANCELED)
+	}
+	return false
 }
@@ -0,0 +1,28 @@
+package domain
+
+type OrderCancellation struct {
+	OrderID         string      `json:"order_id"`
+	CanceledBy      string      `json:"canceled_by"`
+	Reason          string      `json:"reason"`
+	PreviousStatus  OrderStatus `json:"previous_status"`
+	RefundRequested bool        `json:"refund_requested"`
+	RefundID        *string     `json:"refund_id,omitempty"`
+	RefundError     *string     `json:"refund_error,omitempty"` // Lỗi của lần yêu cầu hoàn tiền gần nhất, reconciler sẽ gửi lại
+	RefundAttempts  int         `json:"refund_attempts"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+}
+
+// NeedsRefundRetry: yêu cầu hoàn tiền sau khi huỷ đơn bị lỗi và chưa được gửi lại thành công.
+func (c *OrderCancellation) NeedsRefundRetry() bool {
+	return !c.RefundRequested && c.RefundError != nil
+}
+
+// StockReleaseFailure là lần trả tồn kho bị lỗi của đơn đã huỷ hoặc thanh toán thất bại, reconciler sẽ trả lại.
+type StockReleaseFailure struct {
+	OrderID   string `json:"order_id"`
+	LastError string `json:"last_error"` // Lỗi của lần trả tồn kho gần nhất
+	Attempts  int    `json:"attempts"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrOrderNotClaimable được trả về khi đơn hàng không ở trạng thái SHIPPED hoặc đã có shipper khác nhận.
+	ErrOrderNotClaimable = errors.New("order is not ready to ship or has already been claimed")
+	// ErrDeliveryStatusChanged được trả về khi trạng thái giao hàng đã bị thay đổi bởi một request khác.
+	ErrDeliveryStatusChanged = errors.New("delivery status has been changed concurrently")
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusClaimed    DeliveryStatus = "CLAIMED"
+	DeliveryStatusDelivering DeliveryStatus = "DELIVERING"
+	DeliveryStatusDelivered  DeliveryStatus = "DELIVERED"
+)
+
+// IsValid kiểm tra status có thuộc tập trạng thái giao hàng đã biết hay không.
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryStatusClaimed, DeliveryStatusDelivering, DeliveryStatusDelivered:
+		return true
+	}
+	return false
+}
+
+// OrderDelivery là việc giao một đơn hàng của một shipper.
+// Luồng: CLAIMED (order SHIPPED) -> DELIVERING (order DELIVERING) -> DELIVERED (order DELIVERED).
+type OrderDelivery struct {
+	ID             string         `json:"id"`
+	OrderID        string         `json:"order_id"`
+	ShipperID      string         `json:"shipper_id"`
+	DeliveryStatus DeliveryStatus `json:"delivery_status"`
+	ProofPhotoURL  *string        `json:"proof_photo_url,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	DeliveredPoint *GeoPoint      `json:"delivered_point,omitempty"`
+	ClaimedAt      time.Time      `json:"claimed_at"`
+	PickedUpAt     *time.Time     `json:"picked_up_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// DeliveryProof là bằng chứng giao hàng: ảnh chụp và vị trí GPS của shipper lúc giao.
+type DeliveryProof struct {
+	PhotoURL string
+	Location GeoPoint
+}
+
+// AvailableDelivery là một đơn đang chờ shipper nhận, kèm khoảng cách từ shipper tới điểm lấy hàng (shop).
+// DistanceKm là nil khi shop chưa có toạ độ.
+type AvailableDelivery struct {
+	Order         *Order
+	PickupAddress *PickupAddress
+	DistanceKm    *float64
+}
+
+// PickupAddress là địa chỉ lấy hàng của shop.
+type PickupAddress struct {
+	Street   string
+	Ward     string
+	District string
+	City     string
+	Country  string
+	Location *GeoPoint
+}
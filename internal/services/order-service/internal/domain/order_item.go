@@ -1,11 +1,143 @@
 package domain
 
+import (
+	"errors"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+var (
+	// ErrInvalidItemStatusTransition được trả về khi chuyển trạng thái dòng hàng không hợp lệ.
+	ErrInvalidItemStatusTransition = errors.New("invalid order item status transition")
+	// ErrItemCancelQuantityExceeded được trả về khi số lượng huỷ vượt quá số lượng còn lại của dòng hàng.
+	ErrItemCancelQuantityExceeded = errors.New("cancel quantity exceeds remaining item quantity")
+	// ErrOrderItemNotFound được trả về khi dòng hàng không thuộc đơn hàng.
+	ErrOrderItemNotFound = errors.New("order item not found")
+)
+
+// OrderItemStatus là trạng thái xử lý của một dòng hàng.
+// Luồng: PENDING -> PACKED -> SHIPPED, PENDING | PACKED -> CANCELED.
+type OrderItemStatus string
+
+const (
+	OrderItemStatusPending  OrderItemStatus = "PENDING"
+	OrderItemStatusPacked   OrderItemStatus = "PACKED"
+	OrderItemStatusShipped  OrderItemStatus = "SHIPPED"
+	OrderItemStatusCanceled OrderItemStatus = "CANCELED"
+)
+
+var orderItemStatusTransitions = map[OrderItemStatus][]OrderItemStatus{
+	OrderItemStatusPending:  {OrderItemStatusPacked, OrderItemStatusCanceled},
+	OrderItemStatusPacked:   {OrderItemStatusShipped, OrderItemStatusCanceled},
+	OrderItemStatusShipped:  {},
+	OrderItemStatusCanceled: {},
+}
+
+// CanTransitionTo kiểm tra dòng hàng có được chuyển từ trạng thái s sang next hay không.
+func (s OrderItemStatus) CanTransitionTo(next OrderItemStatus) bool {
+	for _, allowed := range orderItemStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen: dòng hàng chưa được giao cho vận chuyển và chưa bị huỷ.
+func (s OrderItemStatus) IsOpen() bool {
+	return s == OrderItemStatusPending || s == OrderItemStatusPacked
+}
+
 type OrderItem struct {
-	ID        string  `json:"id"`
-	OrderID   string  `json:"order_id"`
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID               string          `json:"id"`
+	OrderID          string          `json:"order_id"`
+	ProductID        string          `json:"product_id"`
+	ProductName      string          `json:"product_name"`  // Snapshot tên sản phẩm lúc đặt hàng
+	ThumbnailURL     string          `json:"thumbnail_url"` // Snapshot ảnh đại diện lúc đặt hàng
+	Quantity         int             `json:"quantity"`
+	Price            money.Money     `json:"price"`
+	Status           OrderItemStatus `json:"status"`
+	CanceledQuantity int             `json:"canceled_quantity"` // Số lượng người bán đã huỷ riêng trên dòng hàng
+	CreatedAt        string          `json:"created_at"`
+	UpdatedAt        string          `json:"updated_at"`
+}
+
+// LineTotal là thành tiền của dòng hàng (đơn giá x số lượng).
+func (i OrderItem) LineTotal() money.Money {
+	return i.Price.Multiply(int64(i.Quantity))
+}
+
+// RemainingQuantity là số lượng chưa bị người bán huỷ riêng, cũng là số lượng product-service còn đang giữ cho dòng hàng.
+func (i OrderItem) RemainingQuantity() int {
+	return i.Quantity - i.CanceledQuantity
+}
+
+// FindItem trả về dòng hàng có id itemID của đơn hàng.
+func (o *Order) FindItem(itemID string) (*OrderItem, bool) {
+	for i := range o.Items {
+		if o.Items[i].ID == itemID {
+			return &o.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// HasShippedItems cho biết đã có dòng hàng nào được giao cho vận chuyển hay chưa.
+func (o *Order) HasShippedItems() bool {
+	for _, item := range o.Items {
+		if item.Status == OrderItemStatusShipped {
+			return true
+		}
+	}
+	return false
+}
+
+// IsItemFulfillmentOpen: người bán chỉ xử lý từng dòng hàng khi đơn đã thanh toán và chưa giao cho vận chuyển.
+func (o *Order) IsItemFulfillmentOpen() bool {
+	return o.Status == OrderStatusPROCESSING || o.Status == OrderStatusCONFIRMED
+}
+
+// DeriveStatusFromItems tính trạng thái đơn hàng từ các dòng hàng khi người bán đang xử lý đơn:
+// huỷ hết các dòng thì đơn bị huỷ, các dòng còn lại đều đã giao cho vận chuyển thì đơn SHIPPED,
+// đã có dòng được đóng gói thì đơn được coi là đã xác nhận. Ngoài ra giữ nguyên trạng thái hiện tại.
+func (o *Order) DeriveStatusFromItems() OrderStatus {
+	if !o.IsItemFulfillmentOpen() || len(o.Items) == 0 {
+		return o.Status
+	}
+
+	active, shipped, started := 0, 0, false
+	for _, item := range o.Items {
+		switch item.Status {
+		case OrderItemStatusCanceled:
+			continue
+		case OrderItemStatusShipped:
+			shipped++
+			started = true
+		case OrderItemStatusPacked:
+			started = true
+		}
+		active++
+	}
+
+	switch {
+	case active == 0:
+		return OrderStatusCANCELED
+	case shipped == active && o.Status == OrderStatusCONFIRMED:
+		return OrderStatusSHIPPED
+	case started && o.Status == OrderStatusPROCESSING:
+		return OrderStatusCONFIRMED
+	}
+	return o.Status
+}
+
+// ItemStatusForOrderStatus trả về trạng thái mà các dòng hàng còn mở phải chuyển sang khi cả đơn đổi trạng thái:
+// đơn giao cho vận chuyển thì các dòng được giao theo, đơn bị huỷ hoặc hoàn tiền trước khi giao thì các dòng bị huỷ.
+func ItemStatusForOrderStatus(status OrderStatus) (OrderItemStatus, bool) {
+	switch status {
+	case OrderStatusSHIPPED:
+		return OrderItemStatusShipped, true
+	case OrderStatusCANCELED, OrderStatusFAILED, OrderStatusREFUNDED:
+		return OrderItemStatusCanceled, true
+	}
+	return "", false
 }
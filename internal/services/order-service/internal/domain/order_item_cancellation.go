@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+// ErrItemCancellationAlreadyRefunded được trả về khi lần huỷ dòng hàng đã được ghi nhận hoàn tiền trước đó.
+var ErrItemCancellationAlreadyRefunded = errors.New("item cancellation already refunded")
+
+// itemCancellationReferencePrefix đánh dấu các lần trả kho và hoàn tiền một phần xuất phát từ việc người bán huỷ dòng hàng.
+const itemCancellationReferencePrefix = "item-cancel:"
+
+// OrderItemCancellation là một lần người bán huỷ một phần hoặc toàn bộ dòng hàng của đơn đã thanh toán.
+// Số lượng bị huỷ được trả lại kho và số tiền tương ứng được hoàn riêng; khi dòng cuối cùng bị huỷ
+// thì cả đơn bị huỷ và phần tiền còn lại (kể cả phí vận chuyển) được hoàn theo đơn.
+type OrderItemCancellation struct {
+	ID           string      `json:"id"`
+	OrderID      string      `json:"order_id"`
+	OrderItemID  string      `json:"order_item_id"`
+	ProductID    string      `json:"product_id"`
+	Quantity     int         `json:"quantity"`
+	RefundAmount money.Money `json:"refund_amount"`
+	Reason       string      `json:"reason"`
+	CanceledBy   string      `json:"canceled_by"`
+	Restocked    bool        `json:"restocked"`
+	RefundID     *string     `json:"refund_id,omitempty"`
+	RefundedAt   *time.Time  `json:"refunded_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// ItemCancellationReferenceID là reference_id gửi sang product-service và payment-service cho lần huỷ dòng hàng,
+// dùng làm idempotency key ở cả hai phía.
+func ItemCancellationReferenceID(cancellationID string) string {
+	return itemCancellationReferencePrefix + cancellationID
+}
+
+// ParseItemCancellationReferenceID trả về id lần huỷ nếu reference thuộc về một lần huỷ dòng hàng.
+func ParseItemCancellationReferenceID(referenceID string) (string, bool) {
+	if !strings.HasPrefix(referenceID, itemCancellationReferencePrefix) {
+		return "", false
+	}
+	cancellationID := strings.TrimPrefix(referenceID, itemCancellationReferencePrefix)
+	return cancellationID, cancellationID != ""
+}
+
+// CalculateItemCancellationRefundAmount tính số tiền hoàn khi huỷ quantity sản phẩm của dòng hàng,
+// giảm giá của đơn được chia theo tỉ lệ giống như khi trả hàng.
+func CalculateItemCancellationRefundAmount(order *Order, item OrderItem, quantity int) money.Money {
+	return order.netOfDiscount(item.Price.Multiply(int64(quantity)))
+}
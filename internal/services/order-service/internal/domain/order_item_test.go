@@ -0,0 +1,188 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestOrderItemStatus_CanTransitionTo(t *testing.T) {
+	statuses := []domain.OrderItemStatus{
+		domain.OrderItemStatusPending,
+		domain.OrderItemStatusPacked,
+		domain.OrderItemStatusShipped,
+		domain.OrderItemStatusCanceled,
+	}
+	allowed := map[domain.OrderItemStatus]map[domain.OrderItemStatus]bool{
+		domain.OrderItemStatusPending: {domain.OrderItemStatusPacked: true, domain.OrderItemStatusCanceled: true},
+		domain.OrderItemStatusPacked:  {domain.OrderItemStatusShipped: true, domain.OrderItemStatusCanceled: true},
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[from][to]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s = %t, want %t", from, to, got, want)
+			}
+		}
+	}
+}
+
+func newFulfillmentOrder(status domain.OrderStatus, itemStatuses ...domain.OrderItemStatus) *domain.Order {
+	order := &domain.Order{ID: "order-1", Status: status}
+	for i, itemStatus := range itemStatuses {
+		order.Items = append(order.Items, domain.OrderItem{
+			ID:       string(rune('a' + i)),
+			Quantity: 1,
+			Price:    money.New(1000, "USD"),
+			Status:   itemStatus,
+		})
+	}
+	return order
+}
+
+func TestOrder_DeriveStatusFromItems(t *testing.T) {
+	testCases := []struct {
+		name     string
+		order    *domain.Order
+		expected domain.OrderStatus
+	}{
+		{
+			name:     "Processing order with no packed item stays processing",
+			order:    newFulfillmentOrder(domain.OrderStatusPROCESSING, domain.OrderItemStatusPending, domain.OrderItemStatusPending),
+			expected: domain.OrderStatusPROCESSING,
+		},
+		{
+			name:     "First packed item confirms the order",
+			order:    newFulfillmentOrder(domain.OrderStatusPROCESSING, domain.OrderItemStatusPacked, domain.OrderItemStatusPending),
+			expected: domain.OrderStatusCONFIRMED,
+		},
+		{
+			name:     "Confirmed order with an open item stays confirmed",
+			order:    newFulfillmentOrder(domain.OrderStatusCONFIRMED, domain.OrderItemStatusShipped, domain.OrderItemStatusPacked),
+			expected: domain.OrderStatusCONFIRMED,
+		},
+		{
+			name:     "All remaining items shipped ships the order",
+			order:    newFulfillmentOrder(domain.OrderStatusCONFIRMED, domain.OrderItemStatusShipped, domain.OrderItemStatusCanceled),
+			expected: domain.OrderStatusSHIPPED,
+		},
+		{
+			name:     "Shipping from processing goes through confirmed first",
+			order:    newFulfillmentOrder(domain.OrderStatusPROCESSING, domain.OrderItemStatusShipped),
+			expected: domain.OrderStatusCONFIRMED,
+		},
+		{
+			name:     "Every item canceled cancels the order",
+			order:    newFulfillmentOrder(domain.OrderStatusCONFIRMED, domain.OrderItemStatusCanceled, domain.OrderItemStatusCanceled),
+			expected: domain.OrderStatusCANCELED,
+		},
+		{
+			name:     "Canceled items do not count as started",
+			order:    newFulfillmentOrder(domain.OrderStatusPROCESSING, domain.OrderItemStatusCanceled, domain.OrderItemStatusPending),
+			expected: domain.OrderStatusPROCESSING,
+		},
+		{
+			name:     "Order outside fulfillment keeps its status",
+			order:    newFulfillmentOrder(domain.OrderStatusPENDINGPAYMENT, domain.OrderItemStatusCanceled),
+			expected: domain.OrderStatusPENDINGPAYMENT,
+		},
+		{
+			name:     "Order without items keeps its status",
+			order:    newFulfillmentOrder(domain.OrderStatusPROCESSING),
+			expected: domain.OrderStatusPROCESSING,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.order.DeriveStatusFromItems(); got != tc.expected {
+				t.Errorf("DeriveStatusFromItems() = %s, want %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOrderItem_RemainingQuantity(t *testing.T) {
+	item := domain.OrderItem{Quantity: 5, CanceledQuantity: 2}
+	if got := item.RemainingQuantity(); got != 3 {
+		t.Errorf("RemainingQuantity() = %d, want 3", got)
+	}
+}
+
+func TestCalculateItemCancellationRefundAmount(t *testing.T) {
+	item := domain.OrderItem{ID: "a", Quantity: 3, Price: money.New(1000, "USD")}
+
+	testCases := []struct {
+		name     string
+		order    *domain.Order
+		quantity int
+		expected money.Money
+	}{
+		{
+			name: "No discount refunds the line price",
+			order: &domain.Order{
+				TotalAmount: money.New(5000, "USD"),
+				FinalPrice:  money.New(5500, "USD"),
+			},
+			quantity: 2,
+			expected: money.New(2000, "USD"),
+		},
+		{
+			name: "Discount is shared by value",
+			order: &domain.Order{
+				TotalAmount:    money.New(5000, "USD"),
+				DiscountAmount: money.New(1000, "USD"),
+				FinalPrice:     money.New(4500, "USD"),
+			},
+			quantity: 2,
+			expected: money.New(1600, "USD"),
+		},
+		{
+			name: "Discount share rounds half away from zero",
+			order: &domain.Order{
+				TotalAmount:    money.New(3000, "USD"),
+				DiscountAmount: money.New(1000, "USD"),
+				FinalPrice:     money.New(2500, "USD"),
+			},
+			quantity: 1,
+			expected: money.New(667, "USD"),
+		},
+		{
+			name: "Discount larger than subtotal refunds nothing",
+			order: &domain.Order{
+				TotalAmount:    money.New(3000, "USD"),
+				DiscountAmount: money.New(5000, "USD"),
+				FinalPrice:     money.New(500, "USD"),
+			},
+			quantity: 1,
+			expected: money.Zero("USD"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := domain.CalculateItemCancellationRefundAmount(tc.order, item, tc.quantity)
+			if !got.Equal(tc.expected) {
+				t.Errorf("refund = %s, want %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestItemCancellationReferenceID(t *testing.T) {
+	const cancellationID = "3f2b1c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+
+	referenceID := domain.ItemCancellationReferenceID(cancellationID)
+	parsed, ok := domain.ParseItemCancellationReferenceID(referenceID)
+	if !ok || parsed != cancellationID {
+		t.Errorf("ParseItemCancellationReferenceID(%q) = %q, %t, want %q, true", referenceID, parsed, ok, cancellationID)
+	}
+
+	for _, referenceID := range []string{"", "item-cancel:", cancellationID, "return:" + cancellationID} {
+		if parsed, ok := domain.ParseItemCancellationReferenceID(referenceID); ok {
+			t.Errorf("ParseItemCancellationReferenceID(%q) = %q, true, want false", referenceID, parsed)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderCursor là vị trí keyset (created_at, id) của đơn hàng cuối cùng trong trang trước.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode trả về cursor dạng opaque để client gửi lại ở trang tiếp theo.
+func (c OrderCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeOrderCursor parse cursor được tạo bởi OrderCursor.Encode.
+func DecodeOrderCursor(s string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("cursor is not valid base64")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("cursor has invalid format")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errors.New("cursor has invalid timestamp")
+	}
+
+	if _, err := uuid.Parse(parts[1]); err != nil {
+		return nil, errors.New("cursor has invalid order id")
+	}
+
+	return &OrderCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// OrderListFilter chứa các điều kiện lọc khi liệt kê đơn hàng của một khách hàng (OwnerID)
+// hoặc của một shop (ShopID).
+type OrderListFilter struct {
+	OwnerID     string
+	ShopID      string
+	Status      *OrderStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Cursor      *OrderCursor
+	Limit       int
+}
+
+// OrderPage là một trang kết quả; NextCursor là nil khi đã hết dữ liệu.
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor *OrderCursor
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestOrderCursor_RoundTrip(t *testing.T) {
+	cursor := domain.OrderCursor{
+		CreatedAt: time.Date(2025, 3, 1, 8, 30, 15, 123456789, time.FixedZone("ICT", 7*3600)),
+		ID:        "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11",
+	}
+
+	decoded, err := domain.DecodeOrderCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Cursor giữ đủ nano giây để không bỏ sót đơn tạo cùng giây ở trang sau
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Errorf("decoded = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeOrderCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	testCases := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Not base64", cursor: "%%%"},
+		{name: "Missing separator", cursor: encode("2025-03-01T08:30:15Z")},
+		{name: "Invalid timestamp", cursor: encode("yesterday|7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11")},
+		{name: "Invalid order id", cursor: encode("2025-03-01T08:30:15Z|order-1")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := domain.DecodeOrderCursor(tc.cursor); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
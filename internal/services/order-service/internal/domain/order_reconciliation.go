@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PaymentStatus là trạng thái payment của đơn hàng phía payment-service, dùng khi đối soát.
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "PENDING"
+	PaymentStatusProcessing PaymentStatus = "PROCESSING"
+	PaymentStatusSuccess    PaymentStatus = "SUCCESS"
+	PaymentStatusFailed     PaymentStatus = "FAILED"
+	PaymentStatusRefunded   PaymentStatus = "REFUNDED" // Đã hoàn toàn bộ số tiền
+)
+
+// PaymentMethodCOD là payment_method của payment thanh toán khi nhận hàng: payment giữ PENDING tới khi shipper thu tiền.
+const PaymentMethodCOD = "COD"
+
+// PaymentDivergenceType là loại lệch giữa trạng thái đơn hàng và payment của đơn.
+type PaymentDivergenceType string
+
+const (
+	// Payment đã thành công nhưng đơn vẫn chờ thanh toán (mất lần cập nhật PROCESSING từ payment-service).
+	DivergencePaidOrderPendingPayment PaymentDivergenceType = "PAID_ORDER_PENDING_PAYMENT"
+	// Payment đã thất bại nhưng đơn vẫn chờ thanh toán (mất lần cập nhật PAYMENT_FAILED).
+	DivergenceFailedPaymentOrderPending PaymentDivergenceType = "FAILED_PAYMENT_ORDER_PENDING"
+	// Đơn đã huỷ và payment đã hoàn toàn bộ nhưng đơn chưa REFUNDED (mất event hoàn tiền).
+	DivergenceRefundedPaymentCanceledOrder PaymentDivergenceType = "REFUNDED_PAYMENT_CANCELED_ORDER"
+	// Payment đã hoàn toàn bộ trong khi đơn vẫn đang được xử lý.
+	DivergenceRefundedPaymentActiveOrder PaymentDivergenceType = "REFUNDED_PAYMENT_ACTIVE_ORDER"
+	// Đơn đang được xử lý nhưng không có payment thành công.
+	DivergenceUnpaidActiveOrder PaymentDivergenceType = "UNPAID_ACTIVE_ORDER"
+	// Đơn đã huỷ, payment thành công nhưng chưa từng yêu cầu hoàn tiền.
+	DivergencePaidOrderCanceledWithoutRefund PaymentDivergenceType = "PAID_ORDER_CANCELED_WITHOUT_REFUND"
+	// Đơn đã bị đánh dấu thanh toán thất bại nhưng payment lại thành công.
+	DivergencePaidOrderPaymentFailed PaymentDivergenceType = "PAID_ORDER_PAYMENT_FAILED"
+	// Payment đã hoàn toàn bộ trong khi đơn vẫn chờ thanh toán.
+	DivergenceRefundedPaymentPendingOrder PaymentDivergenceType = "REFUNDED_PAYMENT_PENDING_ORDER"
+	// Khách đã chọn COD nhưng đơn vẫn chờ thanh toán (mất event COD_PLACED).
+	DivergenceCODOrderPendingPayment PaymentDivergenceType = "COD_ORDER_PENDING_PAYMENT"
+)
+
+// PaymentReconciliationStatuses là các trạng thái đơn phụ thuộc vào payment nên cần được đối soát.
+// Từ SHIPPED trở đi trạng thái đơn do vận chuyển và trả hàng quyết định.
+var PaymentReconciliationStatuses = []OrderStatus{
+	OrderStatusPENDINGPAYMENT,
+	OrderStatusPAYMENTFAILED,
+	OrderStatusPROCESSING,
+	OrderStatusCONFIRMED,
+	OrderStatusCANCELED,
+}
+
+// PaymentReconciliationCandidate là một đơn hàng cần đối soát cùng các thông tin phía order-service.
+type PaymentReconciliationCandidate struct {
+	OrderID         string
+	Status          OrderStatus
+	RefundRequested bool // Đơn đã huỷ và đã gửi yêu cầu hoàn tiền sang payment-service
+	UpdatedAt       time.Time
+}
+
+// PaymentDivergence là kết quả đối soát một đơn hàng với payment của đơn.
+type PaymentDivergence struct {
+	Type PaymentDivergenceType
+	// RepairStatus khác rỗng khi lệch có thể tự sửa bằng cách chuyển đơn sang trạng thái này,
+	// tức là đúng việc mà lần cập nhật / event bị mất lẽ ra đã làm.
+	RepairStatus OrderStatus
+}
+
+// CanRepair cho biết lệch có thể được reconciler tự sửa hay phải chờ người kiểm tra.
+func (d PaymentDivergence) CanRepair() bool {
+	return d.RepairStatus != ""
+}
+
+// DetectPaymentDivergence so trạng thái đơn với payment của đơn, paymentStatus và paymentMethod rỗng khi
+// payment-service không có payment nào của đơn. Trả về false khi hai bên khớp nhau.
+func DetectPaymentDivergence(candidate PaymentReconciliationCandidate, paymentStatus PaymentStatus, paymentMethod string) (PaymentDivergence, bool) {
+	switch candidate.Status {
+	case OrderStatusPENDINGPAYMENT:
+		switch paymentStatus {
+		case PaymentStatusSuccess:
+			return PaymentDivergence{Type: DivergencePaidOrderPendingPayment, RepairStatus: OrderStatusPROCESSING}, true
+		case PaymentStatusFailed:
+			return PaymentDivergence{Type: DivergenceFailedPaymentOrderPending, RepairStatus: OrderStatusPAYMENTFAILED}, true
+		case PaymentStatusRefunded:
+			return PaymentDivergence{Type: DivergenceRefundedPaymentPendingOrder}, true
+		case PaymentStatusPending:
+			if paymentMethod == PaymentMethodCOD {
+				return PaymentDivergence{Type: DivergenceCODOrderPendingPayment, RepairStatus: OrderStatusPROCESSING}, true
+			}
+		}
+	case OrderStatusPAYMENTFAILED:
+		// Không tự sửa: PAYMENT_FAILED không được chuyển thẳng sang PROCESSING
+		if paymentStatus == PaymentStatusSuccess {
+			return PaymentDivergence{Type: DivergencePaidOrderPaymentFailed}, true
+		}
+	case OrderStatusPROCESSING, OrderStatusCONFIRMED:
+		switch paymentStatus {
+		case PaymentStatusSuccess:
+		case PaymentStatusRefunded:
+			return PaymentDivergence{Type: DivergenceRefundedPaymentActiveOrder}, true
+		case PaymentStatusPending:
+			// Đơn COD được xử lý trước, payment chỉ SUCCESS khi shipper thu tiền
+			if paymentMethod != PaymentMethodCOD {
+				return PaymentDivergence{Type: DivergenceUnpaidActiveOrder}, true
+			}
+		default:
+			return PaymentDivergence{Type: DivergenceUnpaidActiveOrder}, true
+		}
+	case OrderStatusCANCELED:
+		switch paymentStatus {
+		case PaymentStatusRefunded:
+			return PaymentDivergence{Type: DivergenceRefundedPaymentCanceledOrder, RepairStatus: OrderStatusREFUNDED}, true
+		case PaymentStatusSuccess:
+			// Đã yêu cầu hoàn tiền thì việc hoàn tiền đang được payment-service xử lý
+			if !candidate.RefundRequested {
+				return PaymentDivergence{Type: DivergencePaidOrderCanceledWithoutRefund}, true
+			}
+		}
+	}
+	return PaymentDivergence{}, false
+}
+
+// PaymentReconciliationReview là một lệch chưa tự sửa được, chờ người kiểm tra.
+type PaymentReconciliationReview struct {
+	ID              string                `json:"id"`
+	OrderID         string                `json:"order_id"`
+	DivergenceType  PaymentDivergenceType `json:"divergence_type"`
+	OrderStatus     OrderStatus           `json:"order_status"`
+	PaymentID       *string               `json:"payment_id,omitempty"`
+	PaymentStatus   *PaymentStatus        `json:"payment_status,omitempty"`
+	Details         string                `json:"details"`
+	DetectionCount  int                   `json:"detection_count"`
+	FirstDetectedAt time.Time             `json:"first_detected_at"`
+	LastDetectedAt  time.Time             `json:"last_detected_at"`
+	ResolvedAt      *time.Time            `json:"resolved_at,omitempty"`
+	Resolution      *string               `json:"resolution,omitempty"`
+}
+
+// DescribePaymentDivergence mô tả ngắn gọn trạng thái hai phía để người kiểm tra đọc trong bảng review.
+func DescribePaymentDivergence(candidate PaymentReconciliationCandidate, paymentStatus PaymentStatus) string {
+	if paymentStatus == "" {
+		return fmt.Sprintf("order is %s but payment-service has no payment for it", candidate.Status)
+	}
+	details := fmt.Sprintf("order is %s but payment is %s", candidate.Status, paymentStatus)
+	if candidate.Status == OrderStatusCANCELED && !candidate.RefundRequested {
+		details += ", no refund was requested on cancellation"
+	}
+	return details
+}
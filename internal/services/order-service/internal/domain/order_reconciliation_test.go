@@ -0,0 +1,308 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+type paymentDivergenceTestCase struct {
+	name            string
+	orderStatus     domain.OrderStatus
+	refundRequested bool
+	paymentStatus   domain.PaymentStatus
+	paymentMethod   string
+	expectDiverged  bool
+	expectedType    domain.PaymentDivergenceType
+	expectedRepair  domain.OrderStatus
+}
+
+func runPaymentDivergenceTests(t *testing.T, testCases []paymentDivergenceTestCase) {
+	t.Helper()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			candidate := domain.PaymentReconciliationCandidate{
+				OrderID:         "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11",
+				Status:          tc.orderStatus,
+				RefundRequested: tc.refundRequested,
+			}
+
+			divergence, diverged := domain.DetectPaymentDivergence(candidate, tc.paymentStatus, tc.paymentMethod)
+
+			if diverged != tc.expectDiverged {
+				t.Fatalf("diverged = %t, want %t (divergence %+v)", diverged, tc.expectDiverged, divergence)
+			}
+			if divergence.Type != tc.expectedType {
+				t.Errorf("type = %q, want %q", divergence.Type, tc.expectedType)
+			}
+			if divergence.RepairStatus != tc.expectedRepair {
+				t.Errorf("repair status = %q, want %q", divergence.RepairStatus, tc.expectedRepair)
+			}
+			if divergence.CanRepair() != (tc.expectedRepair != "") {
+				t.Errorf("CanRepair() = %t, want %t", divergence.CanRepair(), tc.expectedRepair != "")
+			}
+		})
+	}
+}
+
+func TestDetectPaymentDivergence_COD(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:           "COD order still pending payment is moved to processing",
+			orderStatus:    domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus:  domain.PaymentStatusPending,
+			paymentMethod:  domain.PaymentMethodCOD,
+			expectDiverged: true,
+			expectedType:   domain.DivergenceCODOrderPendingPayment,
+			expectedRepair: domain.OrderStatusPROCESSING,
+		},
+		{
+			name:          "E-wallet payment still pending matches pending order",
+			orderStatus:   domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus: domain.PaymentStatusPending,
+			paymentMethod: "EWALLET",
+		},
+		{
+			name:          "Processing COD order awaiting cash collection",
+			orderStatus:   domain.OrderStatusPROCESSING,
+			paymentStatus: domain.PaymentStatusPending,
+			paymentMethod: domain.PaymentMethodCOD,
+		},
+		{
+			name:          "Confirmed COD order awaiting cash collection",
+			orderStatus:   domain.OrderStatusCONFIRMED,
+			paymentStatus: domain.PaymentStatusPending,
+			paymentMethod: domain.PaymentMethodCOD,
+		},
+		{
+			name:           "Processing e-wallet order with pending payment",
+			orderStatus:    domain.OrderStatusPROCESSING,
+			paymentStatus:  domain.PaymentStatusPending,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceUnpaidActiveOrder,
+		},
+		{
+			name:           "Processing COD order whose payment failed",
+			orderStatus:    domain.OrderStatusPROCESSING,
+			paymentStatus:  domain.PaymentStatusFailed,
+			paymentMethod:  domain.PaymentMethodCOD,
+			expectDiverged: true,
+			expectedType:   domain.DivergenceUnpaidActiveOrder,
+		},
+		{
+			name:          "Collected COD order",
+			orderStatus:   domain.OrderStatusCONFIRMED,
+			paymentStatus: domain.PaymentStatusSuccess,
+			paymentMethod: domain.PaymentMethodCOD,
+		},
+	})
+}
+
+func TestDetectPaymentDivergence_PendingPayment(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:          "No payment yet",
+			orderStatus:   domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus: "",
+		},
+		{
+			name:           "Successful payment whose update was lost",
+			orderStatus:    domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus:  domain.PaymentStatusSuccess,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergencePaidOrderPendingPayment,
+			expectedRepair: domain.OrderStatusPROCESSING,
+		},
+		{
+			name:           "Failed payment whose update was lost",
+			orderStatus:    domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus:  domain.PaymentStatusFailed,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceFailedPaymentOrderPending,
+			expectedRepair: domain.OrderStatusPAYMENTFAILED,
+		},
+		{
+			name:           "Refunded payment needs review",
+			orderStatus:    domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus:  domain.PaymentStatusRefunded,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceRefundedPaymentPendingOrder,
+		},
+		{
+			name:          "Processing payment is left to the provider",
+			orderStatus:   domain.OrderStatusPENDINGPAYMENT,
+			paymentStatus: domain.PaymentStatusProcessing,
+			paymentMethod: "EWALLET",
+		},
+	})
+}
+
+func TestDetectPaymentDivergence_PaymentFailed(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:           "Payment succeeded after order was marked failed needs review",
+			orderStatus:    domain.OrderStatusPAYMENTFAILED,
+			paymentStatus:  domain.PaymentStatusSuccess,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergencePaidOrderPaymentFailed,
+		},
+		{
+			name:          "Failed payment matches",
+			orderStatus:   domain.OrderStatusPAYMENTFAILED,
+			paymentStatus: domain.PaymentStatusFailed,
+			paymentMethod: "EWALLET",
+		},
+		{
+			name:          "No payment",
+			orderStatus:   domain.OrderStatusPAYMENTFAILED,
+			paymentStatus: "",
+		},
+	})
+}
+
+func TestDetectPaymentDivergence_ActiveOrder(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:          "Paid processing order",
+			orderStatus:   domain.OrderStatusPROCESSING,
+			paymentStatus: domain.PaymentStatusSuccess,
+			paymentMethod: "EWALLET",
+		},
+		{
+			name:           "Refunded payment of processing order",
+			orderStatus:    domain.OrderStatusPROCESSING,
+			paymentStatus:  domain.PaymentStatusRefunded,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceRefundedPaymentActiveOrder,
+		},
+		{
+			name:           "Refunded payment of confirmed order",
+			orderStatus:    domain.OrderStatusCONFIRMED,
+			paymentStatus:  domain.PaymentStatusRefunded,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceRefundedPaymentActiveOrder,
+		},
+		{
+			name:           "Processing order without payment",
+			orderStatus:    domain.OrderStatusPROCESSING,
+			paymentStatus:  "",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceUnpaidActiveOrder,
+		},
+		{
+			name:           "Confirmed order with failed payment",
+			orderStatus:    domain.OrderStatusCONFIRMED,
+			paymentStatus:  domain.PaymentStatusFailed,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceUnpaidActiveOrder,
+		},
+		{
+			name:           "Processing order with payment still processing",
+			orderStatus:    domain.OrderStatusPROCESSING,
+			paymentStatus:  domain.PaymentStatusProcessing,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceUnpaidActiveOrder,
+		},
+	})
+}
+
+func TestDetectPaymentDivergence_CanceledOrder(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:           "Refund completed but refund event was lost",
+			orderStatus:    domain.OrderStatusCANCELED,
+			paymentStatus:  domain.PaymentStatusRefunded,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergenceRefundedPaymentCanceledOrder,
+			expectedRepair: domain.OrderStatusREFUNDED,
+		},
+		{
+			name:           "Paid canceled order without refund request",
+			orderStatus:    domain.OrderStatusCANCELED,
+			paymentStatus:  domain.PaymentStatusSuccess,
+			paymentMethod:  "EWALLET",
+			expectDiverged: true,
+			expectedType:   domain.DivergencePaidOrderCanceledWithoutRefund,
+		},
+		{
+			name:            "Paid canceled order with refund in progress",
+			orderStatus:     domain.OrderStatusCANCELED,
+			refundRequested: true,
+			paymentStatus:   domain.PaymentStatusSuccess,
+			paymentMethod:   "EWALLET",
+		},
+		{
+			name:          "Canceled order whose payment was canceled",
+			orderStatus:   domain.OrderStatusCANCELED,
+			paymentStatus: domain.PaymentStatusFailed,
+			paymentMethod: "EWALLET",
+		},
+		{
+			name:          "Canceled order without payment",
+			orderStatus:   domain.OrderStatusCANCELED,
+			paymentStatus: "",
+		},
+	})
+}
+
+func TestDetectPaymentDivergence_LaterStatusesAreNotReconciled(t *testing.T) {
+	runPaymentDivergenceTests(t, []paymentDivergenceTestCase{
+		{
+			name:          "Shipped order",
+			orderStatus:   domain.OrderStatusSHIPPED,
+			paymentStatus: domain.PaymentStatusRefunded,
+			paymentMethod: "EWALLET",
+		},
+		{
+			name:          "Delivered order",
+			orderStatus:   domain.OrderStatusDELIVERED,
+			paymentStatus: "",
+		},
+	})
+}
+
+func TestDescribePaymentDivergence(t *testing.T) {
+	testCases := []struct {
+		name          string
+		candidate     domain.PaymentReconciliationCandidate
+		paymentStatus domain.PaymentStatus
+		expected      string
+	}{
+		{
+			name:      "No payment",
+			candidate: domain.PaymentReconciliationCandidate{Status: domain.OrderStatusPROCESSING},
+			expected:  "order is PROCESSING but payment-service has no payment for it",
+		},
+		{
+			name:          "Canceled without refund request",
+			candidate:     domain.PaymentReconciliationCandidate{Status: domain.OrderStatusCANCELED},
+			paymentStatus: domain.PaymentStatusSuccess,
+			expected:      "order is CANCELED but payment is SUCCESS, no refund was requested on cancellation",
+		},
+		{
+			name:          "Refunded active order",
+			candidate:     domain.PaymentReconciliationCandidate{Status: domain.OrderStatusCONFIRMED},
+			paymentStatus: domain.PaymentStatusRefunded,
+			expected:      "order is CONFIRMED but payment is REFUNDED",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.DescribePaymentDivergence(tc.candidate, tc.paymentStatus); got != tc.expected {
+				t.Errorf("DescribePaymentDivergence() = %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+var (
+	// ErrReturnStatusChanged được trả về khi trạng thái yêu cầu trả hàng đã bị thay đổi bởi một request khác.
+	ErrReturnStatusChanged = errors.New("return status has been changed concurrently")
+	// ErrReturnQuantityExceeded được trả về khi số lượng yêu cầu trả vượt quá số lượng đã mua còn lại.
+	ErrReturnQuantityExceeded = errors.New("return quantity exceeds purchased quantity")
+)
+
+// returnReferencePrefix đánh dấu các lần hoàn tiền một phần xuất phát từ yêu cầu trả hàng,
+// để khi nhận event refund từ payment-service có thể tìm lại yêu cầu tương ứng.
+const returnReferencePrefix = "return:"
+
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "REQUESTED"
+	ReturnStatusApproved  ReturnStatus = "APPROVED"
+	ReturnStatusRejected  ReturnStatus = "REJECTED"
+	ReturnStatusReceived  ReturnStatus = "RECEIVED"
+	ReturnStatusRefunded  ReturnStatus = "REFUNDED"
+)
+
+// IsValid kiểm tra status có thuộc tập trạng thái trả hàng đã biết hay không.
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnStatusRequested, ReturnStatusApproved, ReturnStatusRejected, ReturnStatusReceived, ReturnStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// OrderReturn là một yêu cầu trả hàng của khách cho đơn đã giao.
+// Luồng: REQUESTED -> APPROVED | REJECTED, APPROVED -> RECEIVED (nhập lại kho, yêu cầu hoàn tiền) -> REFUNDED.
+type OrderReturn struct {
+	ID           string            `json:"id"`
+	OrderID      string            `json:"order_id"`
+	OwnerID      string            `json:"owner_id"`
+	ShopID       string            `json:"shop_id"`
+	Status       ReturnStatus      `json:"status"`
+	Reason       string            `json:"reason"`
+	SellerNote   *string           `json:"seller_note,omitempty"`
+	RefundAmount money.Money       `json:"refund_amount"`
+	RefundID     *string           `json:"refund_id,omitempty"`
+	Restocked    bool              `json:"restocked"`
+	Items        []OrderReturnItem `json:"items"`
+	ApprovedAt   *time.Time        `json:"approved_at,omitempty"`
+	RejectedAt   *time.Time        `json:"rejected_at,omitempty"`
+	ReceivedAt   *time.Time        `json:"received_at,omitempty"`
+	RefundedAt   *time.Time        `json:"refunded_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type OrderReturnItem struct {
+	ID          string      `json:"id"`
+	ReturnID    string      `json:"return_id"`
+	OrderItemID string      `json:"order_item_id"`
+	ProductID   string      `json:"product_id"`
+	Quantity    int         `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"` // Snapshot giá của order item lúc đặt hàng
+}
+
+// ReturnReferenceID là reference_id gửi sang payment-service cho lần hoàn tiền của yêu cầu trả hàng,
+// dùng làm idempotency key phía payment.
+func ReturnReferenceID(returnID string) string {
+	return returnReferencePrefix + returnID
+}
+
+// ParseReturnReferenceID trả về return ID nếu reference thuộc về một yêu cầu trả hàng.
+func ParseReturnReferenceID(referenceID string) (string, bool) {
+	if !strings.HasPrefix(referenceID, returnReferencePrefix) {
+		return "", false
+	}
+	returnID := strings.TrimPrefix(referenceID, returnReferencePrefix)
+	return returnID, returnID != ""
+}
+
+// CalculateReturnRefundAmount tính số tiền hoàn cho các sản phẩm được trả. Giảm giá của đơn được chia
+// theo tỉ lệ giá trị sản phẩm (làm tròn tới đơn vị tiền nhỏ nhất); phí vận chuyển không được hoàn.
+func CalculateReturnRefundAmount(order *Order, items []OrderReturnItem) money.Money {
+	refund := money.Zero(order.Currency())
+	for _, item := range items {
+		refund.Amount += item.UnitPrice.Multiply(int64(item.Quantity)).Amount
+	}
+	return order.netOfDiscount(refund)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidStatusTransition được trả về khi chuyển trạng thái đơn hàng không nằm trong bảng chuyển trạng thái.
+var ErrInvalidStatusTransition = errors.New("invalid order status transition")
+
+// orderStatusTransitions là bảng chuyển trạng thái hợp lệ của đơn hàng.
+// Luồng chính: PENDING -> PENDING_PAYMENT -> PROCESSING -> CONFIRMED -> SHIPPED -> DELIVERING -> DELIVERED.
+// REFUNDED và FAILED là trạng thái kết thúc.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPENDING: {
+		OrderStatusPENDINGPAYMENT,
+		OrderStatusFAILED,   // Giữ hàng thất bại
+		OrderStatusCANCELED, // Khách huỷ hoặc reconciler dọn đơn treo
+	},
+	OrderStatusPENDINGPAYMENT: {
+		OrderStatusPROCESSING,
+		OrderStatusPAYMENTFAILED,
+		OrderStatusCANCELED,
+	},
+	OrderStatusPAYMENTFAILED: {
+		OrderStatusPENDINGPAYMENT, // Khách thanh toán lại
+		OrderStatusCANCELED,
+	},
+	OrderStatusPROCESSING: {
+		OrderStatusCONFIRMED, // Người bán chấp nhận đơn
+		OrderStatusCANCELED,
+		OrderStatusREFUNDED,
+	},
+	OrderStatusCONFIRMED: {
+		OrderStatusSHIPPED,
+		OrderStatusCANCELED, // Người bán từ chối đơn trước khi giao hàng
+	},
+	OrderStatusSHIPPED: {
+		OrderStatusDELIVERING,
+	},
+	OrderStatusDELIVERING: {
+		OrderStatusDELIVERED,
+	},
+	OrderStatusDELIVERED: {
+		OrderStatusREFUNDED, // Hoàn tiền sau khi trả hàng
+	},
+	OrderStatusCANCELED: {
+		OrderStatusREFUNDED, // Đơn đã thanh toán bị huỷ, payment-service hoàn tiền xong
+	},
+	OrderStatusREFUNDED: {},
+	OrderStatusFAILED:   {},
+}
+
+// CanTransitionTo kiểm tra có được phép chuyển từ trạng thái s sang next hay không.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal cho biết trạng thái không thể chuyển tiếp được nữa.
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderStatusTransitions[s]) == 0
+}
+
+// TransitionTo chuyển đơn hàng sang trạng thái next nếu hợp lệ.
+func (o *Order) TransitionTo(next OrderStatus) error {
+	if !next.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidStatusTransition, next)
+	}
+	if !o.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, o.Status, next)
+	}
+	o.Status = next
+	return nil
+}
@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+var orderStatuses = []domain.OrderStatus{
+	domain.OrderStatusPENDING,
+	domain.OrderStatusPENDINGPAYMENT,
+	domain.OrderStatusPAYMENTFAILED,
+	domain.OrderStatusPROCESSING,
+	domain.OrderStatusCONFIRMED,
+	domain.OrderStatusSHIPPED,
+	domain.OrderStatusDELIVERING,
+	domain.OrderStatusDELIVERED,
+	domain.OrderStatusCANCELED,
+	domain.OrderStatusREFUNDED,
+	domain.OrderStatusFAILED,
+}
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	allowed := map[domain.OrderStatus][]domain.OrderStatus{
+		domain.OrderStatusPENDING:        {domain.OrderStatusPENDINGPAYMENT, domain.OrderStatusFAILED, domain.OrderStatusCANCELED},
+		domain.OrderStatusPENDINGPAYMENT: {domain.OrderStatusPROCESSING, domain.OrderStatusPAYMENTFAILED, domain.OrderStatusCANCELED},
+		domain.OrderStatusPAYMENTFAILED:  {domain.OrderStatusPENDINGPAYMENT, domain.OrderStatusCANCELED},
+		domain.OrderStatusPROCESSING:     {domain.OrderStatusCONFIRMED, domain.OrderStatusCANCELED, domain.OrderStatusREFUNDED},
+		domain.OrderStatusCONFIRMED:      {domain.OrderStatusSHIPPED, domain.OrderStatusCANCELED},
+		domain.OrderStatusSHIPPED:        {domain.OrderStatusDELIVERING},
+		domain.OrderStatusDELIVERING:     {domain.OrderStatusDELIVERED},
+		domain.OrderStatusDELIVERED:      {domain.OrderStatusREFUNDED},
+		domain.OrderStatusCANCELED:       {domain.OrderStatusREFUNDED},
+	}
+
+	for _, from := range orderStatuses {
+		for _, to := range orderStatuses {
+			want := false
+			for _, next := range allowed[from] {
+				want = want || next == to
+			}
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				if got := from.CanTransitionTo(to); got != want {
+					t.Errorf("CanTransitionTo() = %t, want %t", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderStatus_IsTerminal(t *testing.T) {
+	for _, status := range orderStatuses {
+		want := status == domain.OrderStatusREFUNDED || status == domain.OrderStatusFAILED
+		if got := status.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %t, want %t", status, got, want)
+		}
+	}
+}
+
+func TestOrder_TransitionTo(t *testing.T) {
+	testCases := []struct {
+		name        string
+		from        domain.OrderStatus
+		to          domain.OrderStatus
+		expectError bool
+	}{
+		{name: "Payment received", from: domain.OrderStatusPENDINGPAYMENT, to: domain.OrderStatusPROCESSING},
+		{name: "Retry after failed payment", from: domain.OrderStatusPAYMENTFAILED, to: domain.OrderStatusPENDINGPAYMENT},
+		{name: "Canceled order is refunded", from: domain.OrderStatusCANCELED, to: domain.OrderStatusREFUNDED},
+		{name: "Delivered order cannot be canceled", from: domain.OrderStatusDELIVERED, to: domain.OrderStatusCANCELED, expectError: true},
+		{name: "Shipped order cannot go back to processing", from: domain.OrderStatusSHIPPED, to: domain.OrderStatusPROCESSING, expectError: true},
+		{name: "Same status is not a transition", from: domain.OrderStatusPROCESSING, to: domain.OrderStatusPROCESSING, expectError: true},
+		{name: "Refunded order is terminal", from: domain.OrderStatusREFUNDED, to: domain.OrderStatusPROCESSING, expectError: true},
+		{name: "Unknown target status", from: domain.OrderStatusPENDING, to: domain.OrderStatus("LOST"), expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := &domain.Order{ID: "order-1", Status: tc.from}
+			err := order.TransitionTo(tc.to)
+
+			if tc.expectError {
+				if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+					t.Fatalf("error = %v, want %v", err, domain.ErrInvalidStatusTransition)
+				}
+				if order.Status != tc.from {
+					t.Errorf("status = %s, want unchanged %s", order.Status, tc.from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.Status != tc.to {
+				t.Errorf("status = %s, want %s", order.Status, tc.to)
+			}
+		})
+	}
+}
+
+func TestOrder_IsCancelableByCustomer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		order    *domain.Order
+		expected bool
+	}{
+		{name: "Pending order", order: newFulfillmentOrder(domain.OrderStatusPENDING), expected: true},
+		{name: "Order awaiting payment", order: newFulfillmentOrder(domain.OrderStatusPENDINGPAYMENT), expected: true},
+		{name: "Order with failed payment", order: newFulfillmentOrder(domain.OrderStatusPAYMENTFAILED), expected: true},
+		{name: "Paid order", order: newFulfillmentOrder(domain.OrderStatusPROCESSING, domain.OrderItemStatusPending), expected: true},
+		{name: "Confirmed order before shipping", order: newFulfillmentOrder(domain.OrderStatusCONFIRMED, domain.OrderItemStatusPacked), expected: true},
+		{name: "Confirmed order with a shipped item", order: newFulfillmentOrder(domain.OrderStatusCONFIRMED, domain.OrderItemStatusShipped, domain.OrderItemStatusPacked)},
+		{name: "Shipped order", order: newFulfillmentOrder(domain.OrderStatusSHIPPED, domain.OrderItemStatusShipped)},
+		{name: "Delivered order", order: newFulfillmentOrder(domain.OrderStatusDELIVERED, domain.OrderItemStatusShipped)},
+		{name: "Canceled order", order: newFulfillmentOrder(domain.OrderStatusCANCELED)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.order.IsCancelableByCustomer(); got != tc.expected {
+				t.Errorf("IsCancelableByCustomer() = %t, want %t", got, tc.expected)
+			}
+		})
+	}
+}
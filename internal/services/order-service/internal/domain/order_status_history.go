@@ -0,0 +1,49 @@
+package domain
+
+// Các actor hệ thống ghi vào lịch sử trạng thái, actor là khách hàng dùng CustomerActor, người bán dùng SellerActor,
+// shipper dùng ShipperActor.
+const (
+	StatusActorOrderService = "order-service"
+	StatusActorReconciler   = "order-service.reconciler"
+	StatusActorExpiryJob    = "order-service.expiry-job"
+	StatusActorInboxWorker  = "order-service.inbox-worker"
+	StatusActorKafka        = "order-service.kafka-consumer"
+	StatusActorGRPCClient   = "grpc-client"
+)
+
+// StatusChange mô tả ai và vì sao đổi trạng thái đơn hàng.
+type StatusChange struct {
+	Actor  string
+	Reason string
+}
+
+// AdminActor trả về actor đại diện cho admin thực hiện thay đổi.
+func AdminActor(userID string) string {
+	return "admin:" + userID
+}
+
+// CustomerActor trả về actor đại diện cho khách hàng thực hiện thay đổi.
+func CustomerActor(userID string) string {
+	return "customer:" + userID
+}
+
+// SellerActor trả về actor đại diện cho chủ shop thực hiện thay đổi.
+func SellerActor(userID string) string {
+	return "seller:" + userID
+}
+
+// ShipperActor trả về actor đại diện cho shipper thực hiện thay đổi.
+func ShipperActor(userID string) string {
+	return "shipper:" + userID
+}
+
+type OrderStatusHistory struct {
+	ID        int64        `json:"id"`
+	OrderID   string       `json:"order_id"`
+	OldStatus *OrderStatus `json:"old_status,omitempty"`
+	NewStatus OrderStatus  `json:"new_status"`
+	Actor     string       `json:"actor"`
+	Reason    string       `json:"reason,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+	CreatedAt string       `json:"created_at"`
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// OutboxEventStatus represents the status of an outbox event
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending OutboxEventStatus = "PENDING"
+	OutboxEventStatusSent    OutboxEventStatus = "SENT"
+	OutboxEventStatusFailed  OutboxEventStatus = "FAILED"
+)
+
+// ErrOutboxEventNotFailed: chỉ event FAILED mới được admin retry.
+var ErrOutboxEventNotFailed = errors.New("outbox event is not failed")
+
+// OutboxEvent là domain event của đơn hàng đang chờ relay worker publish lên Kafka.
+type OutboxEvent struct {
+	ID          string            `json:"id"`
+	OrderID     string            `json:"order_id"`
+	EventType   string            `json:"event_type"`
+	Payload     string            `json:"payload"` // Dữ liệu event dạng JSON
+	EventStatus OutboxEventStatus `json:"event_status"`
+	RetryCount  int               `json:"retry_count"`
+	PublishedAt *time.Time        `json:"published_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Seq         int64             `json:"seq"` // Thứ tự ghi event, event của cùng đơn được publish theo seq tăng dần
+}
+
+// OrderEventMessage là message được publish lên Kafka cho mọi domain event của đơn hàng.
+// EventID là id của outbox event, consumer dùng để chống xử lý trùng.
+type OrderEventMessage struct {
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	OrderID    string          `json:"order_id"`
+	Source     string          `json:"source"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type OrderCreatedEventItem struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"`
+}
+
+type OrderCreatedEvent struct {
+	OrderID        string                  `json:"order_id"`
+	OwnerID        string                  `json:"owner_id"`
+	ShopID         string                  `json:"shop_id"`
+	CheckoutID     *string                 `json:"checkout_id,omitempty"`
+	Status         OrderStatus             `json:"status"`
+	ShippingFee    float64                 `json:"shipping_fee"`
+	DiscountAmount float64                 `json:"discount_amount"`
+	TotalAmount    float64                 `json:"total_amount"`
+	FinalAmount    float64                 `json:"final_amount"`
+	Currency       string                  `json:"currency"`
+	Items          []OrderCreatedEventItem `json:"items"`
+}
+
+type OrderStatusChangedEvent struct {
+	OrderID   string      `json:"order_id"`
+	OwnerID   string      `json:"owner_id"`
+	ShopID    string      `json:"shop_id"`
+	OldStatus OrderStatus `json:"old_status"`
+	NewStatus OrderStatus `json:"new_status"`
+	Actor     string      `json:"actor"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+type OrderCanceledEvent struct {
+	OrderID        string      `json:"order_id"`
+	OwnerID        string      `json:"owner_id"`
+	ShopID         string      `json:"shop_id"`
+	PreviousStatus OrderStatus `json:"previous_status"`
+	Actor          string      `json:"actor"`
+	Reason         string      `json:"reason,omitempty"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// OrderExpiryReason là lý do huỷ được ghi vào lịch sử trạng thái và order_cancellations khi đơn hết hạn thanh toán.
+const OrderExpiryReason = "payment window expired"
+
+// ShopPaymentWindow là thời gian đơn PENDING_PAYMENT được giữ hàng chờ khách thanh toán trước khi bị huỷ tự động.
+type ShopPaymentWindow struct {
+	ShopID    string        `json:"shop_id"`
+	Window    time.Duration `json:"window"`
+	IsDefault bool          `json:"is_default"` // Shop chưa cấu hình, dùng thời gian mặc định trong config
+}
+
+// PaymentExpiryAction là việc job huỷ đơn hết hạn thanh toán cần làm với payment của đơn trước khi huỷ đơn.
+type PaymentExpiryAction string
+
+const (
+	// Huỷ payment chưa thanh toán ở payment-service trước, chỉ huỷ đơn khi payment-service xác nhận đã huỷ được.
+	PaymentExpiryCancelPayment PaymentExpiryAction = "CANCEL_PAYMENT"
+	// Payment không còn thu tiền được nữa (đã thất bại hoặc đã hoàn), huỷ đơn luôn.
+	PaymentExpiryCancelOrder PaymentExpiryAction = "CANCEL_ORDER"
+	// Khách đã thanh toán hoặc chọn COD, để payment event chuyển đơn sang PROCESSING như bình thường.
+	PaymentExpirySkip PaymentExpiryAction = "SKIP"
+)
+
+// DecidePaymentExpiry quyết định cách xử lý payment của đơn hết hạn thanh toán, paymentStatus và paymentMethod rỗng khi
+// đơn chưa có payment. Đơn chưa có payment vẫn phải huỷ qua payment-service vì khách có thể đang khởi tạo thanh toán.
+func DecidePaymentExpiry(paymentStatus PaymentStatus, paymentMethod string) PaymentExpiryAction {
+	if paymentMethod == PaymentMethodCOD {
+		return PaymentExpirySkip
+	}
+
+	switch paymentStatus {
+	case "", PaymentStatusPending:
+		return PaymentExpiryCancelPayment
+	case PaymentStatusFailed, PaymentStatusRefunded:
+		return PaymentExpiryCancelOrder
+	default:
+		return PaymentExpirySkip
+	}
+}
@@ -0,0 +1,65 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestDecidePaymentExpiry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		paymentStatus domain.PaymentStatus
+		paymentMethod string
+		expected      domain.PaymentExpiryAction
+	}{
+		{
+			name:     "No payment yet cancels through payment-service",
+			expected: domain.PaymentExpiryCancelPayment,
+		},
+		{
+			name:          "Pending e-wallet payment is canceled first",
+			paymentStatus: domain.PaymentStatusPending,
+			paymentMethod: "E_WALLET",
+			expected:      domain.PaymentExpiryCancelPayment,
+		},
+		{
+			name:          "Failed payment cancels the order",
+			paymentStatus: domain.PaymentStatusFailed,
+			paymentMethod: "E_WALLET",
+			expected:      domain.PaymentExpiryCancelOrder,
+		},
+		{
+			name:          "Refunded payment cancels the order",
+			paymentStatus: domain.PaymentStatusRefunded,
+			paymentMethod: "E_WALLET",
+			expected:      domain.PaymentExpiryCancelOrder,
+		},
+		{
+			name:          "Successful payment is skipped",
+			paymentStatus: domain.PaymentStatusSuccess,
+			paymentMethod: "E_WALLET",
+			expected:      domain.PaymentExpirySkip,
+		},
+		{
+			name:          "Processing payment is skipped",
+			paymentStatus: domain.PaymentStatusProcessing,
+			paymentMethod: "E_WALLET",
+			expected:      domain.PaymentExpirySkip,
+		},
+		{
+			name:          "Pending COD payment is skipped",
+			paymentStatus: domain.PaymentStatusPending,
+			paymentMethod: domain.PaymentMethodCOD,
+			expected:      domain.PaymentExpirySkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := domain.DecidePaymentExpiry(tc.paymentStatus, tc.paymentMethod); got != tc.expected {
+				t.Errorf("DecidePaymentExpiry(%q, %q) = %s, want %s", tc.paymentStatus, tc.paymentMethod, got, tc.expected)
+			}
+		})
+	}
+}
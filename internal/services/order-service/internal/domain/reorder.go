@@ -0,0 +1,32 @@
+package domain
+
+import "github.com/toji-dev/go-shop/internal/pkg/money"
+
+// Lý do một sản phẩm của đơn cũ không được thêm lại vào giỏ hàng.
+const (
+	ReorderReasonProductNotFound = "PRODUCT_NOT_FOUND" // Sản phẩm đã bị xoá hoặc ngừng bán
+	ReorderReasonOutOfStock      = "OUT_OF_STOCK"
+)
+
+// ReorderResult là kết quả đặt lại một đơn cũ: các sản phẩm đã thêm vào giỏ hàng và các sản phẩm không còn mua được.
+type ReorderResult struct {
+	OrderID     string
+	Added       []ReorderItem
+	Unavailable []ReorderItem
+}
+
+// ReorderItem so sánh một sản phẩm của đơn cũ với thông tin hiện tại của sản phẩm.
+type ReorderItem struct {
+	ProductID         string
+	ProductName       string
+	RequestedQuantity int         // Số lượng trong đơn cũ
+	Quantity          int         // Số lượng thực tế thêm vào giỏ, bị giới hạn bởi tồn kho hiện tại
+	PreviousPrice     money.Money // Đơn giá lúc đặt đơn cũ
+	CurrentPrice      money.Money // Đơn giá hiện tại, bằng 0 khi sản phẩm không còn tồn tại
+	UnavailableReason string
+}
+
+// PriceChanged: giá hiện tại khác giá trong đơn cũ (kể cả khi sản phẩm đổi tiền tệ).
+func (i ReorderItem) PriceChanged() bool {
+	return i.UnavailableReason == "" && !i.CurrentPrice.Equal(i.PreviousPrice)
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+var (
+	// ErrNoCashToSettle được trả về khi shipper không còn khoản tiền mặt nào chưa quyết toán theo currency yêu cầu.
+	ErrNoCashToSettle = errors.New("shipper has no unsettled cash in this currency")
+	// ErrCashSettlementMismatch được trả về khi số tiền admin nhận khác tổng các khoản shipper chưa quyết toán.
+	ErrCashSettlementMismatch = errors.New("settled amount does not match unsettled cash of shipper")
+)
+
+// CashCollection là tiền mặt shipper đã thu của khách cho một đơn COD. Tiền nằm ở shipper tới khi được
+// quyết toán (SettlementID khác nil).
+type CashCollection struct {
+	ID         string      `json:"id"`
+	OrderID    string      `json:"order_id"`
+	DeliveryID string      `json:"delivery_id"`
+	ShipperID  string      `json:"shipper_id"`
+	PaymentID  string      `json:"payment_id"`
+	Amount     money.Money `json:"amount"`
+	// PaymentConfirmedAt là nil khi payment-service chưa chuyển payment COD sang SUCCESS
+	PaymentConfirmedAt *time.Time `json:"payment_confirmed_at,omitempty"`
+	SettlementID       *string    `json:"settlement_id,omitempty"`
+	CollectedAt        time.Time  `json:"collected_at"`
+	SettledAt          *time.Time `json:"settled_at,omitempty"`
+}
+
+// IsPaymentConfirmed cho biết payment COD của đơn đã được payment-service ghi nhận là SUCCESS hay chưa.
+func (c *CashCollection) IsPaymentConfirmed() bool {
+	return c.PaymentConfirmedAt != nil
+}
+
+// CODAmountDue trả về số tiền shipper phải thu của đơn COD: số tiền của payment trừ số tiền các dòng hàng người bán
+// đã huỷ. Payment COD chưa SUCCESS nên các lần huỷ này không được hoàn qua payment-service mà được trừ khi thu tiền.
+func CODAmountDue(paymentAmount money.Money, cancellations []*OrderItemCancellation) (money.Money, error) {
+	due := paymentAmount
+	for _, cancellation := range cancellations {
+		var err error
+		if due, err = due.Sub(cancellation.RefundAmount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	if due.IsNegative() {
+		return money.Zero(due.Currency), nil
+	}
+	return due, nil
+}
+
+// CashSettlement là một lần admin nhận lại từ shipper toàn bộ tiền mặt chưa quyết toán theo một currency.
+type CashSettlement struct {
+	ID              string      `json:"id"`
+	ShipperID       string      `json:"shipper_id"`
+	Amount          money.Money `json:"amount"`
+	CollectionCount int         `json:"collection_count"`
+	SettledBy       string      `json:"settled_by"`
+	Note            *string     `json:"note,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// CashSettlementTotal cộng các khoản thu chưa quyết toán sẽ được quyết toán bằng amount. Trả về ErrNoCashToSettle khi
+// không có khoản nào, ErrCashSettlementMismatch khi tổng khác amount (kể cả khác currency).
+func CashSettlementTotal(amount money.Money, collections []*CashCollection) (money.Money, error) {
+	if len(collections) == 0 {
+		return money.Money{}, ErrNoCashToSettle
+	}
+
+	total := money.Zero(amount.Currency)
+	for _, collection := range collections {
+		var err error
+		if total, err = total.Add(collection.Amount); err != nil {
+			return money.Money{}, fmt.Errorf("%w: %v", ErrCashSettlementMismatch, err)
+		}
+	}
+	if !total.Equal(amount) {
+		return money.Money{}, fmt.Errorf("%w: unsettled %s, settled %s", ErrCashSettlementMismatch, total, amount)
+	}
+	return total, nil
+}
+
+// ShipperCashBalance là tiền mặt shipper đang giữ: tổng theo từng currency và các khoản thu chưa quyết toán.
+type ShipperCashBalance struct {
+	ShipperID   string
+	Totals      []money.Money
+	Collections []*CashCollection
+}
+
+// NewShipperCashBalance cộng các khoản thu chưa quyết toán theo currency, giữ thứ tự currency xuất hiện đầu tiên.
+func NewShipperCashBalance(shipperID string, collections []*CashCollection) *ShipperCashBalance {
+	balance := &ShipperCashBalance{
+		ShipperID:   shipperID,
+		Totals:      []money.Money{},
+		Collections: collections,
+	}
+
+	index := make(map[string]int)
+	for _, collection := range collections {
+		i, ok := index[collection.Amount.Currency]
+		if !ok {
+			index[collection.Amount.Currency] = len(balance.Totals)
+			balance.Totals = append(balance.Totals, collection.Amount)
+			continue
+		}
+		// Cùng currency nên Add không lỗi
+		balance.Totals[i], _ = balance.Totals[i].Add(collection.Amount)
+	}
+	return balance
+}
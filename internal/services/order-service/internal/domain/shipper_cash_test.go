@@ -0,0 +1,171 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+const testShipperID = "5b1e7c2a-8f43-4d6e-9a0b-2c3d4e5f6a7b"
+
+func TestNewShipperCashBalance(t *testing.T) {
+	collections := []*domain.CashCollection{
+		{ID: "c1", Amount: money.New(150000, "VND")},
+		{ID: "c2", Amount: money.New(1234, "USD")},
+		{ID: "c3", Amount: money.New(50000, "VND")},
+		{ID: "c4", Amount: money.New(66, "USD")},
+	}
+
+	balance := domain.NewShipperCashBalance(testShipperID, collections)
+
+	if balance.ShipperID != testShipperID {
+		t.Errorf("ShipperID = %s, want %s", balance.ShipperID, testShipperID)
+	}
+	want := []money.Money{money.New(200000, "VND"), money.New(1300, "USD")}
+	if len(balance.Totals) != len(want) {
+		t.Fatalf("Totals = %v, want %v", balance.Totals, want)
+	}
+	for i := range want {
+		if !balance.Totals[i].Equal(want[i]) {
+			t.Errorf("Totals[%d] = %s, want %s", i, balance.Totals[i], want[i])
+		}
+	}
+	if len(balance.Collections) != len(collections) {
+		t.Errorf("Collections = %d, want %d", len(balance.Collections), len(collections))
+	}
+}
+
+func TestNewShipperCashBalance_Empty(t *testing.T) {
+	balance := domain.NewShipperCashBalance(testShipperID, nil)
+
+	if balance.Totals == nil || len(balance.Totals) != 0 {
+		t.Errorf("Totals = %v, want empty non-nil slice", balance.Totals)
+	}
+}
+
+func TestCashSettlementTotal(t *testing.T) {
+	collections := []*domain.CashCollection{
+		{ID: "c1", Amount: money.New(150000, "VND")},
+		{ID: "c2", Amount: money.New(50000, "VND")},
+	}
+
+	testCases := []struct {
+		name          string
+		amount        money.Money
+		collections   []*domain.CashCollection
+		expectedTotal money.Money
+		expectedError error
+	}{
+		{
+			name:          "Success - amount equals unsettled total",
+			amount:        money.New(200000, "VND"),
+			collections:   collections,
+			expectedTotal: money.New(200000, "VND"),
+		},
+		{
+			name:          "Error - no unsettled cash",
+			amount:        money.New(200000, "VND"),
+			collections:   nil,
+			expectedError: domain.ErrNoCashToSettle,
+		},
+		{
+			name:          "Error - amount lower than unsettled total",
+			amount:        money.New(150000, "VND"),
+			collections:   collections,
+			expectedError: domain.ErrCashSettlementMismatch,
+		},
+		{
+			name:          "Error - amount higher than unsettled total",
+			amount:        money.New(250000, "VND"),
+			collections:   collections,
+			expectedError: domain.ErrCashSettlementMismatch,
+		},
+		{
+			name:          "Error - collection in another currency",
+			amount:        money.New(200000, "VND"),
+			collections:   append([]*domain.CashCollection{{ID: "c3", Amount: money.New(100, "USD")}}, collections...),
+			expectedError: domain.ErrCashSettlementMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			total, err := domain.CashSettlementTotal(tc.amount, tc.collections)
+
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !total.Equal(tc.expectedTotal) {
+				t.Errorf("total = %s, want %s", total, tc.expectedTotal)
+			}
+		})
+	}
+}
+
+func TestCODAmountDue(t *testing.T) {
+	testCases := []struct {
+		name          string
+		paymentAmount money.Money
+		cancellations []*domain.OrderItemCancellation
+		expected      money.Money
+		expectError   bool
+	}{
+		{
+			name:          "No cancellations",
+			paymentAmount: money.New(230000, "VND"),
+			expected:      money.New(230000, "VND"),
+		},
+		{
+			name:          "Canceled lines are not collected",
+			paymentAmount: money.New(230000, "VND"),
+			cancellations: []*domain.OrderItemCancellation{
+				{ID: "ic1", RefundAmount: money.New(50000, "VND")},
+				{ID: "ic2", RefundAmount: money.New(30000, "VND")},
+			},
+			expected: money.New(150000, "VND"),
+		},
+		{
+			name:          "Never below zero",
+			paymentAmount: money.New(1000, "USD"),
+			cancellations: []*domain.OrderItemCancellation{
+				{ID: "ic1", RefundAmount: money.New(1200, "USD")},
+			},
+			expected: money.Zero("USD"),
+		},
+		{
+			name:          "Currency mismatch",
+			paymentAmount: money.New(1000, "USD"),
+			cancellations: []*domain.OrderItemCancellation{
+				{ID: "ic1", RefundAmount: money.New(1000, "VND")},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			due, err := domain.CODAmountDue(tc.paymentAmount, tc.cancellations)
+
+			if tc.expectError {
+				if !errors.Is(err, money.ErrCurrencyMismatch) {
+					t.Fatalf("error = %v, want %v", err, money.ErrCurrencyMismatch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !due.Equal(tc.expected) {
+				t.Errorf("due = %s, want %s", due, tc.expected)
+			}
+		})
+	}
+}
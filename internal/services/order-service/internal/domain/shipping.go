@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const earthRadiusKm = 6371.0
+
+var (
+	// ErrOutOfShippingRange được trả về khi khoảng cách giao hàng vượt quá vùng xa nhất của shop.
+	ErrOutOfShippingRange = errors.New("shipping address is out of the shop's delivery range")
+	// ErrParcelTooHeavy được trả về khi tổng khối lượng vượt quá mức khối lượng cao nhất của shop.
+	ErrParcelTooHeavy = errors.New("parcel exceeds the shop's maximum shipping weight")
+)
+
+// GeoPoint là toạ độ địa lý (độ thập phân).
+type GeoPoint struct {
+	Lat  float64 `json:"lat"`
+	Long float64 `json:"long"`
+}
+
+// DistanceKm tính khoảng cách đường chim bay giữa hai điểm theo công thức haversine.
+func (p GeoPoint) DistanceKm(other GeoPoint) float64 {
+	lat1 := p.Lat * math.Pi / 180
+	lat2 := other.Lat * math.Pi / 180
+	deltaLat := (other.Lat - p.Lat) * math.Pi / 180
+	deltaLong := (other.Long - p.Long) * math.Pi / 180
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLong/2)*math.Sin(deltaLong/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// ShippingZoneTier là phí cơ bản cho các đơn giao trong bán kính MaxDistanceKm.
+// MaxDistanceKm = 0 nghĩa là không giới hạn (chỉ dùng cho vùng cuối cùng).
+type ShippingZoneTier struct {
+	MaxDistanceKm float64 `json:"max_distance_km"`
+	BaseFee       float64 `json:"base_fee"`
+}
+
+// ShippingWeightTier là phụ phí cho các kiện hàng có tổng khối lượng tới MaxWeightGrams.
+// MaxWeightGrams = 0 nghĩa là không giới hạn (chỉ dùng cho mức cuối cùng).
+type ShippingWeightTier struct {
+	MaxWeightGrams int     `json:"max_weight_grams"`
+	Surcharge      float64 `json:"surcharge"`
+}
+
+// ShippingRateTable là bảng phí vận chuyển của một shop. Shop chưa cấu hình thì dùng bảng mặc định từ config.
+// Các mức phí là đơn vị tiền (không phải đơn vị nhỏ nhất) của Currency.
+type ShippingRateTable struct {
+	ShopID      string               `json:"shop_id"`
+	Currency    string               `json:"currency"`
+	Zones       []ShippingZoneTier   `json:"zones"`
+	WeightTiers []ShippingWeightTier `json:"weight_tiers"`
+	IsDefault   bool                 `json:"is_default"`
+}
+
+// ShippingParcelItem là một dòng hàng trong kiện, WeightGrams = 0 nghĩa là sản phẩm chưa khai báo khối lượng.
+type ShippingParcelItem struct {
+	ProductID   string
+	WeightGrams int
+	Quantity    int
+}
+
+// ShippingQuote là kết quả tính phí vận chuyển. DistanceKm là nil khi thiếu toạ độ của shop hoặc người mua,
+// khi đó phí được tính theo vùng xa nhất.
+type ShippingQuote struct {
+	ShopID           string
+	Currency         string
+	DistanceKm       *float64
+	TotalWeightGrams int
+	ZoneFee          float64
+	WeightSurcharge  float64
+	ShippingFee      float64
+}
+
+// Validate kiểm tra các mức của bảng phí: mỗi danh sách không rỗng, tăng dần, chỉ mức cuối được để không giới hạn.
+func (t *ShippingRateTable) Validate() error {
+	if len(t.Currency) != 3 {
+		return errors.New("shipping rate currency must be a 3-letter ISO 4217 code")
+	}
+	if len(t.Zones) == 0 {
+		return errors.New("at least one shipping zone is required")
+	}
+	for i, zone := range t.Zones {
+		if zone.BaseFee < 0 {
+			return fmt.Errorf("zone %d: base fee cannot be negative", i+1)
+		}
+		if zone.MaxDistanceKm < 0 {
+			return fmt.Errorf("zone %d: max distance cannot be negative", i+1)
+		}
+		if zone.MaxDistanceKm == 0 && i != len(t.Zones)-1 {
+			return fmt.Errorf("zone %d: only the last zone can be unbounded", i+1)
+		}
+		if i > 0 && zone.MaxDistanceKm != 0 && zone.MaxDistanceKm <= t.Zones[i-1].MaxDistanceKm {
+			return fmt.Errorf("zone %d: max distance must be greater than the previous zone", i+1)
+		}
+	}
+
+	for i, tier := range t.WeightTiers {
+		if tier.Surcharge < 0 {
+			return fmt.Errorf("weight tier %d: surcharge cannot be negative", i+1)
+		}
+		if tier.MaxWeightGrams < 0 {
+			return fmt.Errorf("weight tier %d: max weight cannot be negative", i+1)
+		}
+		if tier.MaxWeightGrams == 0 && i != len(t.WeightTiers)-1 {
+			return fmt.Errorf("weight tier %d: only the last tier can be unbounded", i+1)
+		}
+		if i > 0 && tier.MaxWeightGrams != 0 && tier.MaxWeightGrams <= t.WeightTiers[i-1].MaxWeightGrams {
+			return fmt.Errorf("weight tier %d: max weight must be greater than the previous tier", i+1)
+		}
+	}
+
+	return nil
+}
+
+// Normalize sắp xếp các mức theo thứ tự tăng dần, mức không giới hạn luôn đứng cuối.
+func (t *ShippingRateTable) Normalize() {
+	sort.SliceStable(t.Zones, func(i, j int) bool {
+		return boundedLess(t.Zones[i].MaxDistanceKm, t.Zones[j].MaxDistanceKm)
+	})
+	sort.SliceStable(t.WeightTiers, func(i, j int) bool {
+		return boundedLess(float64(t.WeightTiers[i].MaxWeightGrams), float64(t.WeightTiers[j].MaxWeightGrams))
+	})
+}
+
+// Quote tính phí vận chuyển = phí vùng theo khoảng cách + phụ phí theo tổng khối lượng.
+func (t *ShippingRateTable) Quote(distanceKm *float64, totalWeightGrams int) (*ShippingQuote, error) {
+	zone, err := t.zoneFor(distanceKm)
+	if err != nil {
+		return nil, err
+	}
+
+	surcharge, err := t.weightSurchargeFor(totalWeightGrams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShippingQuote{
+		ShopID:           t.ShopID,
+		Currency:         t.Currency,
+		DistanceKm:       distanceKm,
+		TotalWeightGrams: totalWeightGrams,
+		ZoneFee:          zone.BaseFee,
+		WeightSurcharge:  surcharge,
+		ShippingFee:      zone.BaseFee + surcharge,
+	}, nil
+}
+
+func (t *ShippingRateTable) zoneFor(distanceKm *float64) (ShippingZoneTier, error) {
+	if len(t.Zones) == 0 {
+		return ShippingZoneTier{}, errors.New("shipping rate table has no zones")
+	}
+
+	// Không có toạ độ thì không đo được khoảng cách, tính theo vùng xa nhất
+	if distanceKm == nil {
+		return t.Zones[len(t.Zones)-1], nil
+	}
+
+	for _, zone := range t.Zones {
+		if zone.MaxDistanceKm == 0 || *distanceKm <= zone.MaxDistanceKm {
+			return zone, nil
+		}
+	}
+
+	return ShippingZoneTier{}, ErrOutOfShippingRange
+}
+
+func (t *ShippingRateTable) weightSurchargeFor(totalWeightGrams int) (float64, error) {
+	if len(t.WeightTiers) == 0 {
+		return 0, nil
+	}
+
+	for _, tier := range t.WeightTiers {
+		if tier.MaxWeightGrams == 0 || totalWeightGrams <= tier.MaxWeightGrams {
+			return tier.Surcharge, nil
+		}
+	}
+
+	return 0, ErrParcelTooHeavy
+}
+
+// boundedLess so sánh hai giới hạn, trong đó 0 là không giới hạn (lớn nhất).
+func boundedLess(a, b float64) bool {
+	if a == 0 {
+		return false
+	}
+	if b == 0 {
+		return true
+	}
+	return a < b
+}
+
+// TotalParcelWeightGrams tính tổng khối lượng kiện hàng, sản phẩm chưa khai báo khối lượng dùng defaultItemWeightGrams.
+func TotalParcelWeightGrams(items []ShippingParcelItem, defaultItemWeightGrams int) int {
+	total := 0
+	for _, item := range items {
+		weight := item.WeightGrams
+		if weight <= 0 {
+			weight = defaultItemWeightGrams
+		}
+		total += weight * item.Quantity
+	}
+	return total
+}
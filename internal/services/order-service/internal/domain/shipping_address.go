@@ -0,0 +1,15 @@
+package domain
+
+// ShippingAddress là snapshot địa chỉ giao hàng lúc đặt hàng, lưu dạng JSON trong orders.shipping_address.
+// Địa chỉ gốc bên user-service có thể bị sửa hoặc xoá mà không ảnh hưởng tới đơn hàng đã tạo.
+type ShippingAddress struct {
+	RecipientName  string  `json:"recipient_name"`
+	RecipientPhone string  `json:"recipient_phone"`
+	Street         string  `json:"street"`
+	Ward           string  `json:"ward"`
+	District       string  `json:"district"`
+	City           string  `json:"city"`
+	Country        string  `json:"country"`
+	Lat            float64 `json:"lat"`
+	Long           float64 `json:"long"`
+}
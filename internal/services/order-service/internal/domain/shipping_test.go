@@ -0,0 +1,251 @@
+package domain_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func newTestRateTable() *domain.ShippingRateTable {
+	return &domain.ShippingRateTable{
+		ShopID:   "shop-1",
+		Currency: "VND",
+		Zones: []domain.ShippingZoneTier{
+			{MaxDistanceKm: 5, BaseFee: 15000},
+			{MaxDistanceKm: 20, BaseFee: 25000},
+			{MaxDistanceKm: 0, BaseFee: 40000},
+		},
+		WeightTiers: []domain.ShippingWeightTier{
+			{MaxWeightGrams: 1000, Surcharge: 0},
+			{MaxWeightGrams: 5000, Surcharge: 10000},
+			{MaxWeightGrams: 20000, Surcharge: 30000},
+		},
+	}
+}
+
+func distance(km float64) *float64 {
+	return &km
+}
+
+func TestShippingRateTable_Quote(t *testing.T) {
+	testCases := []struct {
+		name          string
+		distanceKm    *float64
+		weightGrams   int
+		expectedZone  float64
+		expectedFee   float64
+		expectedError error
+	}{
+		{
+			name:         "Nearest zone, lightest tier",
+			distanceKm:   distance(3),
+			weightGrams:  800,
+			expectedZone: 15000,
+			expectedFee:  15000,
+		},
+		{
+			name:         "Zone boundary is inclusive",
+			distanceKm:   distance(5),
+			weightGrams:  1000,
+			expectedZone: 15000,
+			expectedFee:  15000,
+		},
+		{
+			name:         "Middle zone with weight surcharge",
+			distanceKm:   distance(12.5),
+			weightGrams:  1001,
+			expectedZone: 25000,
+			expectedFee:  35000,
+		},
+		{
+			name:         "Beyond bounded zones uses unbounded zone",
+			distanceKm:   distance(300),
+			weightGrams:  15000,
+			expectedZone: 40000,
+			expectedFee:  70000,
+		},
+		{
+			name:         "Missing coordinates uses farthest zone",
+			weightGrams:  500,
+			expectedZone: 40000,
+			expectedFee:  40000,
+		},
+		{
+			name:          "Parcel heavier than last tier",
+			distanceKm:    distance(3),
+			weightGrams:   20001,
+			expectedError: domain.ErrParcelTooHeavy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := newTestRateTable().Quote(tc.distanceKm, tc.weightGrams)
+
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if quote.ZoneFee != tc.expectedZone {
+				t.Errorf("zone fee = %v, want %v", quote.ZoneFee, tc.expectedZone)
+			}
+			if quote.ShippingFee != tc.expectedFee {
+				t.Errorf("shipping fee = %v, want %v", quote.ShippingFee, tc.expectedFee)
+			}
+			if quote.ShopID != "shop-1" || quote.Currency != "VND" || quote.TotalWeightGrams != tc.weightGrams {
+				t.Errorf("quote = %+v, want shop-1 in VND with %d grams", quote, tc.weightGrams)
+			}
+		})
+	}
+}
+
+func TestShippingRateTable_Quote_OutOfRange(t *testing.T) {
+	table := &domain.ShippingRateTable{
+		Zones: []domain.ShippingZoneTier{{MaxDistanceKm: 10, BaseFee: 20000}},
+	}
+
+	if _, err := table.Quote(distance(10.1), 500); !errors.Is(err, domain.ErrOutOfShippingRange) {
+		t.Errorf("error = %v, want %v", err, domain.ErrOutOfShippingRange)
+	}
+
+	// Không có mức khối lượng thì không có phụ phí
+	quote, err := table.Quote(distance(10), 50000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quote.WeightSurcharge != 0 || quote.ShippingFee != 20000 {
+		t.Errorf("quote = %+v, want fee 20000 without surcharge", quote)
+	}
+}
+
+func TestShippingRateTable_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		table       domain.ShippingRateTable
+		expectError bool
+	}{
+		{
+			name:  "Valid table",
+			table: *newTestRateTable(),
+		},
+		{
+			name: "Missing currency",
+			table: domain.ShippingRateTable{
+				Zones: []domain.ShippingZoneTier{{MaxDistanceKm: 0, BaseFee: 10000}},
+			},
+			expectError: true,
+		},
+		{
+			name:        "No zones",
+			table:       domain.ShippingRateTable{Currency: "VND"},
+			expectError: true,
+		},
+		{
+			name: "Negative base fee",
+			table: domain.ShippingRateTable{
+				Currency: "VND",
+				Zones:    []domain.ShippingZoneTier{{MaxDistanceKm: 5, BaseFee: -1}},
+			},
+			expectError: true,
+		},
+		{
+			name: "Unbounded zone before the last",
+			table: domain.ShippingRateTable{
+				Currency: "VND",
+				Zones:    []domain.ShippingZoneTier{{MaxDistanceKm: 0, BaseFee: 10000}, {MaxDistanceKm: 5, BaseFee: 15000}},
+			},
+			expectError: true,
+		},
+		{
+			name: "Zones not increasing",
+			table: domain.ShippingRateTable{
+				Currency: "VND",
+				Zones:    []domain.ShippingZoneTier{{MaxDistanceKm: 10, BaseFee: 10000}, {MaxDistanceKm: 10, BaseFee: 15000}},
+			},
+			expectError: true,
+		},
+		{
+			name: "Weight tiers not increasing",
+			table: domain.ShippingRateTable{
+				Currency:    "VND",
+				Zones:       []domain.ShippingZoneTier{{MaxDistanceKm: 0, BaseFee: 10000}},
+				WeightTiers: []domain.ShippingWeightTier{{MaxWeightGrams: 5000}, {MaxWeightGrams: 1000}},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.table.Validate()
+			if tc.expectError && err == nil {
+				t.Error("expected validation error")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestShippingRateTable_Normalize(t *testing.T) {
+	table := &domain.ShippingRateTable{
+		Currency: "VND",
+		Zones: []domain.ShippingZoneTier{
+			{MaxDistanceKm: 0, BaseFee: 40000},
+			{MaxDistanceKm: 20, BaseFee: 25000},
+			{MaxDistanceKm: 5, BaseFee: 15000},
+		},
+		WeightTiers: []domain.ShippingWeightTier{
+			{MaxWeightGrams: 5000, Surcharge: 10000},
+			{MaxWeightGrams: 0, Surcharge: 30000},
+			{MaxWeightGrams: 1000, Surcharge: 0},
+		},
+	}
+
+	table.Normalize()
+
+	if err := table.Validate(); err != nil {
+		t.Fatalf("normalized table is invalid: %v", err)
+	}
+	if got := table.Zones[0].MaxDistanceKm; got != 5 {
+		t.Errorf("first zone = %v km, want 5", got)
+	}
+	if got := table.WeightTiers[len(table.WeightTiers)-1].MaxWeightGrams; got != 0 {
+		t.Errorf("last weight tier = %d grams, want unbounded", got)
+	}
+}
+
+func TestTotalParcelWeightGrams(t *testing.T) {
+	items := []domain.ShippingParcelItem{
+		{ProductID: "a", WeightGrams: 250, Quantity: 2},
+		{ProductID: "b", WeightGrams: 0, Quantity: 3}, // Chưa khai báo khối lượng
+	}
+
+	if got := domain.TotalParcelWeightGrams(items, 400); got != 1700 {
+		t.Errorf("TotalParcelWeightGrams() = %d, want 1700", got)
+	}
+}
+
+func TestGeoPoint_DistanceKm(t *testing.T) {
+	hanoi := domain.GeoPoint{Lat: 21.0285, Long: 105.8542}
+	hoChiMinhCity := domain.GeoPoint{Lat: 10.8231, Long: 106.6297}
+
+	if got := hanoi.DistanceKm(hanoi); got != 0 {
+		t.Errorf("distance to itself = %v, want 0", got)
+	}
+	// Khoảng cách đường chim bay Hà Nội - TP.HCM khoảng 1138 km
+	if got := hanoi.DistanceKm(hoChiMinhCity); math.Abs(got-1138) > 5 {
+		t.Errorf("distance = %.1f km, want about 1138 km", got)
+	}
+	if a, b := hanoi.DistanceKm(hoChiMinhCity), hoChiMinhCity.DistanceKm(hanoi); math.Abs(a-b) > 1e-9 {
+		t.Errorf("distance is not symmetric: %v != %v", a, b)
+	}
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+)
+
+// StatsGranularity là độ dài mỗi mốc của chuỗi thống kê bán hàng. Tuần bắt đầu từ thứ Hai (UTC).
+type StatsGranularity string
+
+const (
+	StatsGranularityDay  StatsGranularity = "day"
+	StatsGranularityWeek StatsGranularity = "week"
+)
+
+// IsCanceledForStats: đơn kết thúc không thành (huỷ, lỗi, thanh toán thất bại) không được tính vào GMV và doanh thu sản phẩm.
+func (s OrderStatus) IsCanceledForStats() bool {
+	switch s {
+	case OrderStatusCANCELED, OrderStatusFAILED, OrderStatusPAYMENTFAILED:
+		return true
+	}
+	return false
+}
+
+// ShopStatsDelta là thay đổi cần cộng vào thống kê ngày của shop khi một đơn đổi trạng thái.
+type ShopStatsDelta struct {
+	Orders   int // 1 khi đơn vừa được tạo
+	Canceled int // 1 khi đơn vào nhóm huỷ, -1 khi rời nhóm huỷ
+	Products int // 1 khi sản phẩm của đơn bắt đầu được tính doanh thu, -1 khi thôi được tính
+}
+
+func (d ShopStatsDelta) IsZero() bool {
+	return d.Orders == 0 && d.Canceled == 0 && d.Products == 0
+}
+
+// ShopStatsDeltaForTransition tính delta cho lần chuyển từ oldStatus (nil khi đơn vừa được tạo) sang newStatus.
+// Delta chỉ phụ thuộc vào việc đơn có thuộc nhóm huỷ hay không nên cộng dồn mọi lần chuyển luôn ra đúng trạng thái cuối.
+func ShopStatsDeltaForTransition(oldStatus *OrderStatus, newStatus OrderStatus) ShopStatsDelta {
+	var delta ShopStatsDelta
+	oldCounted, oldCanceled := false, false
+	if oldStatus == nil {
+		delta.Orders = 1
+	} else {
+		oldCanceled = oldStatus.IsCanceledForStats()
+		oldCounted = !oldCanceled
+	}
+	newCanceled := newStatus.IsCanceledForStats()
+
+	delta.Canceled = boolToInt(newCanceled) - boolToInt(oldCanceled)
+	delta.Products = boolToInt(!newCanceled) - boolToInt(oldCounted)
+	return delta
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ShopSalesBucket là số liệu bán hàng của shop trong một mốc thời gian, tách theo tiền tệ.
+// Đơn được tính vào mốc chứa ngày tạo đơn.
+type ShopSalesBucket struct {
+	Start          time.Time
+	Currency       string
+	OrdersCount    int
+	CanceledCount  int
+	GrossAmount    money.Money
+	CanceledAmount money.Money
+}
+
+// GMV là tổng giá trị các đơn không bị huỷ.
+func (b ShopSalesBucket) GMV() money.Money {
+	gmv, err := b.GrossAmount.Sub(b.CanceledAmount)
+	if err != nil {
+		return money.Zero(b.Currency)
+	}
+	return gmv
+}
+
+// AverageOrderValue là GMV chia cho số đơn không bị huỷ.
+func (b ShopSalesBucket) AverageOrderValue() money.Money {
+	completed := b.OrdersCount - b.CanceledCount
+	if completed <= 0 {
+		return money.Zero(b.Currency)
+	}
+	return b.GMV().MulRatio(1, int64(completed))
+}
+
+// CancellationRate là tỉ lệ đơn bị huỷ trên tổng số đơn, từ 0 đến 1.
+func (b ShopSalesBucket) CancellationRate() float64 {
+	if b.OrdersCount <= 0 {
+		return 0
+	}
+	return float64(b.CanceledCount) / float64(b.OrdersCount)
+}
+
+func (b *ShopSalesBucket) add(other ShopSalesBucket) {
+	b.OrdersCount += other.OrdersCount
+	b.CanceledCount += other.CanceledCount
+	if gross, err := b.GrossAmount.Add(other.GrossAmount); err == nil {
+		b.GrossAmount = gross
+	}
+	if canceled, err := b.CanceledAmount.Add(other.CanceledAmount); err == nil {
+		b.CanceledAmount = canceled
+	}
+}
+
+// ShopTopProduct là sản phẩm bán chạy của shop trong khoảng thời gian thống kê.
+type ShopTopProduct struct {
+	ProductID   string
+	ProductName string
+	Currency    string
+	Quantity    int64
+	Revenue     money.Money
+}
+
+// ShopAnalytics là kết quả thống kê bán hàng của shop trong [From, To).
+type ShopAnalytics struct {
+	ShopID      string
+	From        time.Time
+	To          time.Time
+	Granularity StatsGranularity
+	Series      []ShopSalesBucket // Theo thứ tự thời gian, mốc không có đơn vẫn có mặt với số liệu bằng 0
+	Totals      []ShopSalesBucket // Tổng cả khoảng thời gian, mỗi tiền tệ một dòng
+	TopProducts []ShopTopProduct
+}
+
+// BucketStart trả về thời điểm bắt đầu mốc chứa t.
+func (g StatsGranularity) BucketStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if g != StatsGranularityWeek {
+		return day
+	}
+	offset := (int(day.Weekday()) + 6) % 7 // Thứ Hai = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+func (g StatsGranularity) next(t time.Time) time.Time {
+	if g == StatsGranularityWeek {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// BuildShopSalesSeries gom số liệu theo ngày thành các mốc theo granularity trong [from, to), điền mốc trống bằng 0
+// cho mỗi tiền tệ có phát sinh, và tính tổng theo tiền tệ. Mốc đầu tiên bắt đầu từ from kể cả khi from không phải
+// đầu tuần, để mốc không bao gồm những ngày nằm ngoài khoảng thống kê.
+func BuildShopSalesSeries(daily []ShopSalesBucket, from, to time.Time, granularity StatsGranularity) ([]ShopSalesBucket, []ShopSalesBucket) {
+	type bucketKey struct {
+		start    time.Time
+		currency string
+	}
+
+	bucketStart := func(t time.Time) time.Time {
+		start := granularity.BucketStart(t)
+		if start.Before(from) {
+			return from
+		}
+		return start
+	}
+
+	buckets := make(map[bucketKey]*ShopSalesBucket)
+	totals := make(map[string]*ShopSalesBucket)
+	for _, day := range daily {
+		key := bucketKey{start: bucketStart(day.Start), currency: day.Currency}
+		if _, ok := buckets[key]; !ok {
+			buckets[key] = emptyShopSalesBucket(key.start, key.currency)
+		}
+		buckets[key].add(day)
+
+		if _, ok := totals[day.Currency]; !ok {
+			totals[day.Currency] = emptyShopSalesBucket(from, day.Currency)
+		}
+		totals[day.Currency].add(day)
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	var series []ShopSalesBucket
+	for start := bucketStart(from); start.Before(to); start = granularity.next(granularity.BucketStart(start)) {
+		for _, currency := range currencies {
+			if bucket, ok := buckets[bucketKey{start: start, currency: currency}]; ok {
+				series = append(series, *bucket)
+				continue
+			}
+			series = append(series, *emptyShopSalesBucket(start, currency))
+		}
+	}
+
+	totalList := make([]ShopSalesBucket, 0, len(currencies))
+	for _, currency := range currencies {
+		totalList = append(totalList, *totals[currency])
+	}
+	return series, totalList
+}
+
+func emptyShopSalesBucket(start time.Time, currency string) *ShopSalesBucket {
+	return &ShopSalesBucket{
+		Start:          start,
+		Currency:       currency,
+		GrossAmount:    money.Zero(currency),
+		CanceledAmount: money.Zero(currency),
+	}
+}
@@ -0,0 +1,197 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestShopStatsDeltaForTransition(t *testing.T) {
+	status := func(s domain.OrderStatus) *domain.OrderStatus { return &s }
+
+	testCases := []struct {
+		name      string
+		oldStatus *domain.OrderStatus
+		newStatus domain.OrderStatus
+		expected  domain.ShopStatsDelta
+	}{
+		{
+			name:      "New order counts order and products",
+			newStatus: domain.OrderStatusPENDING,
+			expected:  domain.ShopStatsDelta{Orders: 1, Products: 1},
+		},
+		{
+			name:      "Order created already canceled",
+			newStatus: domain.OrderStatusCANCELED,
+			expected:  domain.ShopStatsDelta{Orders: 1, Canceled: 1},
+		},
+		{
+			name:      "Progress between counted statuses changes nothing",
+			oldStatus: status(domain.OrderStatusPENDINGPAYMENT),
+			newStatus: domain.OrderStatusPROCESSING,
+			expected:  domain.ShopStatsDelta{},
+		},
+		{
+			name:      "Cancellation moves order out of products",
+			oldStatus: status(domain.OrderStatusPROCESSING),
+			newStatus: domain.OrderStatusCANCELED,
+			expected:  domain.ShopStatsDelta{Canceled: 1, Products: -1},
+		},
+		{
+			name:      "Payment failure counts as canceled",
+			oldStatus: status(domain.OrderStatusPENDINGPAYMENT),
+			newStatus: domain.OrderStatusPAYMENTFAILED,
+			expected:  domain.ShopStatsDelta{Canceled: 1, Products: -1},
+		},
+		{
+			name:      "Leaving canceled group counts products again",
+			oldStatus: status(domain.OrderStatusPAYMENTFAILED),
+			newStatus: domain.OrderStatusPROCESSING,
+			expected:  domain.ShopStatsDelta{Canceled: -1, Products: 1},
+		},
+		{
+			name:      "Between canceled statuses changes nothing",
+			oldStatus: status(domain.OrderStatusPAYMENTFAILED),
+			newStatus: domain.OrderStatusCANCELED,
+			expected:  domain.ShopStatsDelta{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := domain.ShopStatsDeltaForTransition(tc.oldStatus, tc.newStatus)
+			if got != tc.expected {
+				t.Errorf("delta = %+v, want %+v", got, tc.expected)
+			}
+			if got.IsZero() != (tc.expected == domain.ShopStatsDelta{}) {
+				t.Errorf("IsZero() = %t for %+v", got.IsZero(), got)
+			}
+		})
+	}
+}
+
+func TestShopStatsDeltaForTransition_Accumulates(t *testing.T) {
+	// Cộng dồn mọi lần chuyển của một đơn luôn ra đúng trạng thái cuối
+	path := []domain.OrderStatus{
+		domain.OrderStatusPENDING,
+		domain.OrderStatusPENDINGPAYMENT,
+		domain.OrderStatusPAYMENTFAILED,
+		domain.OrderStatusPROCESSING,
+		domain.OrderStatusCANCELED,
+	}
+
+	var total domain.ShopStatsDelta
+	var previous *domain.OrderStatus
+	for i := range path {
+		delta := domain.ShopStatsDeltaForTransition(previous, path[i])
+		total.Orders += delta.Orders
+		total.Canceled += delta.Canceled
+		total.Products += delta.Products
+		previous = &path[i]
+	}
+
+	if expected := (domain.ShopStatsDelta{Orders: 1, Canceled: 1}); total != expected {
+		t.Errorf("total = %+v, want %+v", total, expected)
+	}
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func dailyBucket(start time.Time, currency string, orders, canceled int, gross, canceledAmount int64) domain.ShopSalesBucket {
+	return domain.ShopSalesBucket{
+		Start:          start,
+		Currency:       currency,
+		OrdersCount:    orders,
+		CanceledCount:  canceled,
+		GrossAmount:    money.New(gross, currency),
+		CanceledAmount: money.New(canceledAmount, currency),
+	}
+}
+
+func TestBuildShopSalesSeries_Daily(t *testing.T) {
+	from, to := day(2026, 3, 2), day(2026, 3, 5)
+	daily := []domain.ShopSalesBucket{
+		dailyBucket(day(2026, 3, 2), "VND", 2, 1, 300000, 100000),
+		dailyBucket(day(2026, 3, 4), "USD", 1, 0, 1500, 0),
+		dailyBucket(day(2026, 3, 4), "VND", 1, 0, 50000, 0),
+	}
+
+	series, totals := domain.BuildShopSalesSeries(daily, from, to, domain.StatsGranularityDay)
+
+	// 3 ngày x 2 tiền tệ, mốc trống bằng 0
+	if len(series) != 6 {
+		t.Fatalf("series has %d buckets, want 6", len(series))
+	}
+	if series[0].Currency != "USD" || series[1].Currency != "VND" || !series[0].Start.Equal(from) {
+		t.Errorf("first buckets = %+v, %+v, want USD then VND at %s", series[0], series[1], from)
+	}
+	if empty := series[2]; empty.OrdersCount != 0 || !empty.GrossAmount.Equal(money.Zero("USD")) || !empty.Start.Equal(day(2026, 3, 3)) {
+		t.Errorf("empty bucket = %+v, want zero USD bucket on 2026-03-03", empty)
+	}
+
+	if len(totals) != 2 {
+		t.Fatalf("totals has %d currencies, want 2", len(totals))
+	}
+	vnd := totals[1]
+	if vnd.OrdersCount != 3 || vnd.CanceledCount != 1 || !vnd.GMV().Equal(money.New(250000, "VND")) {
+		t.Errorf("VND totals = %+v, want 3 orders, 1 canceled, GMV 250000", vnd)
+	}
+	if got := vnd.AverageOrderValue(); !got.Equal(money.New(125000, "VND")) {
+		t.Errorf("average order value = %s, want 125000 VND", got)
+	}
+	if got := vnd.CancellationRate(); got != 1.0/3 {
+		t.Errorf("cancellation rate = %v, want 1/3", got)
+	}
+}
+
+func TestBuildShopSalesSeries_WeeklyClampsFirstBucket(t *testing.T) {
+	// 2026-03-04 là thứ Tư, tuần của nó bắt đầu từ thứ Hai 2026-03-02
+	from, to := day(2026, 3, 4), day(2026, 3, 18)
+	daily := []domain.ShopSalesBucket{
+		dailyBucket(day(2026, 3, 4), "VND", 1, 0, 100000, 0),
+		dailyBucket(day(2026, 3, 8), "VND", 1, 0, 200000, 0),
+		dailyBucket(day(2026, 3, 9), "VND", 1, 0, 400000, 0),
+		dailyBucket(day(2026, 3, 17), "VND", 1, 0, 800000, 0),
+	}
+
+	series, _ := domain.BuildShopSalesSeries(daily, from, to, domain.StatsGranularityWeek)
+
+	expected := []struct {
+		start time.Time
+		gross int64
+	}{
+		{start: day(2026, 3, 4), gross: 300000},
+		{start: day(2026, 3, 9), gross: 400000},
+		{start: day(2026, 3, 16), gross: 800000},
+	}
+	if len(series) != len(expected) {
+		t.Fatalf("series has %d buckets, want %d: %+v", len(series), len(expected), series)
+	}
+	for i, want := range expected {
+		if !series[i].Start.Equal(want.start) || series[i].GrossAmount.Amount != want.gross {
+			t.Errorf("bucket %d = %s %s, want %s %d", i, series[i].Start.Format("2006-01-02"), series[i].GrossAmount, want.start.Format("2006-01-02"), want.gross)
+		}
+	}
+}
+
+func TestBuildShopSalesSeries_NoSales(t *testing.T) {
+	series, totals := domain.BuildShopSalesSeries(nil, day(2026, 3, 1), day(2026, 3, 8), domain.StatsGranularityDay)
+	if len(series) != 0 || len(totals) != 0 {
+		t.Errorf("series = %+v, totals = %+v, want both empty without sales", series, totals)
+	}
+}
+
+func TestStatsGranularity_BucketStart(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 15, 30, 0, 0, time.UTC)
+
+	if got := domain.StatsGranularityDay.BucketStart(sunday); !got.Equal(day(2026, 3, 8)) {
+		t.Errorf("day bucket = %s, want 2026-03-08", got)
+	}
+	if got := domain.StatsGranularityWeek.BucketStart(sunday); !got.Equal(day(2026, 3, 2)) {
+		t.Errorf("week bucket = %s, want Monday 2026-03-02", got)
+	}
+}
@@ -0,0 +1,116 @@
+package dto
+
+// AvailableDeliveriesQuery là query string của GET /deliveries/available: vị trí hiện tại của shipper và bán kính tìm kiếm.
+type AvailableDeliveriesQuery struct {
+	Lat      *float64 `form:"lat" binding:"required,gte=-90,lte=90"`
+	Long     *float64 `form:"long" binding:"required,gte=-180,lte=180"`
+	RadiusKm float64  `form:"radius_km" binding:"omitempty,gt=0"`
+	Limit    int      `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// ListDeliveriesQuery là query string của GET /deliveries (các đơn shipper đã nhận).
+type ListDeliveriesQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=CLAIMED DELIVERING DELIVERED"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// DeliverOrderRequest là bằng chứng giao hàng: ảnh chụp đã upload và vị trí GPS lúc giao.
+type DeliverOrderRequest struct {
+	ProofPhotoURL string   `json:"proof_photo_url" binding:"required,url,max=2048"`
+	Lat           *float64 `json:"lat" binding:"required,gte=-90,lte=90"`
+	Long          *float64 `json:"long" binding:"required,gte=-180,lte=180"`
+}
+
+type PickupAddressResponse struct {
+	Street   string   `json:"street"`
+	Ward     string   `json:"ward,omitempty"`
+	District string   `json:"district,omitempty"`
+	City     string   `json:"city,omitempty"`
+	Country  string   `json:"country,omitempty"`
+	Lat      *float64 `json:"lat,omitempty"`
+	Long     *float64 `json:"long,omitempty"`
+}
+
+// DeliveryAreaResponse là khu vực giao hàng, không có tên, số điện thoại và số nhà của người nhận.
+type DeliveryAreaResponse struct {
+	Ward     string `json:"ward,omitempty"`
+	District string `json:"district,omitempty"`
+	City     string `json:"city,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// AvailableOrderResponse là đơn hàng shipper thấy trước khi nhận đơn. Thông tin người nhận chỉ được trả về
+// sau khi shipper đã nhận đơn (xem DeliveryResponse).
+type AvailableOrderResponse struct {
+	ID           string                `json:"id"`
+	ShopID       string                `json:"shop_id"`
+	DeliveryArea *DeliveryAreaResponse `json:"delivery_area,omitempty"`
+	FinalAmount  float64               `json:"final_amount"`
+	Currency     string                `json:"currency"`
+	CreatedAt    string                `json:"created_at"`
+}
+
+type AvailableDeliveryResponse struct {
+	Order         *AvailableOrderResponse `json:"order"`
+	PickupAddress *PickupAddressResponse  `json:"pickup_address,omitempty"`
+	DistanceKm    *float64                `json:"distance_km"` // null khi shop chưa có toạ độ
+}
+
+type DeliveryResponse struct {
+	ID             string         `json:"id"`
+	OrderID        string         `json:"order_id"`
+	ShipperID      string         `json:"shipper_id"`
+	DeliveryStatus string         `json:"delivery_status"`
+	ProofPhotoURL  *string        `json:"proof_photo_url,omitempty"`
+	DeliveredLat   *float64       `json:"delivered_lat,omitempty"`
+	DeliveredLong  *float64       `json:"delivered_long,omitempty"`
+	ClaimedAt      string         `json:"claimed_at"`
+	PickedUpAt     *string        `json:"picked_up_at,omitempty"`
+	DeliveredAt    *string        `json:"delivered_at,omitempty"`
+	Order          *OrderResponse `json:"order,omitempty"`
+}
+
+// SettleShipperCashRequest là số tiền mặt admin đã nhận lại từ shipper, phải bằng tổng các khoản
+// shipper chưa quyết toán theo currency.
+type SettleShipperCashRequest struct {
+	Amount   float64 `json:"amount" binding:"required,gt=0"`
+	Currency string  `json:"currency" binding:"required,len=3"`
+	Note     *string `json:"note" binding:"omitempty,max=500"`
+}
+
+type CashCollectionResponse struct {
+	ID               string  `json:"id"`
+	OrderID          string  `json:"order_id"`
+	DeliveryID       string  `json:"delivery_id"`
+	ShipperID        string  `json:"shipper_id"`
+	PaymentID        string  `json:"payment_id"`
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	PaymentConfirmed bool    `json:"payment_confirmed"` // false khi payment-service chưa ghi nhận, sẽ được gửi lại
+	CollectedAt      string  `json:"collected_at"`
+	SettlementID     *string `json:"settlement_id,omitempty"`
+	SettledAt        *string `json:"settled_at,omitempty"`
+}
+
+type CashTotalResponse struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// ShipperCashBalanceResponse là tiền mặt shipper đang giữ, chưa nộp lại cho admin.
+type ShipperCashBalanceResponse struct {
+	ShipperID   string                   `json:"shipper_id"`
+	Totals      []CashTotalResponse      `json:"totals"`
+	Collections []CashCollectionResponse `json:"collections"`
+}
+
+type CashSettlementResponse struct {
+	ID              string  `json:"id"`
+	ShipperID       string  `json:"shipper_id"`
+	Amount          float64 `json:"amount"`
+	Currency        string  `json:"currency"`
+	CollectionCount int     `json:"collection_count"`
+	SettledBy       string  `json:"settled_by"`
+	Note            *string `json:"note,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "encoding/json"
+
+// ListInboxEventsQuery là query string của GET /admin/inbox-events.
+// from/to lọc theo received_at, nhận định dạng RFC3339 hoặc YYYY-MM-DD; to là mốc kết thúc (không bao gồm).
+type ListInboxEventsQuery struct {
+	Status    string `form:"status" binding:"omitempty"`
+	EventType string `form:"event_type" binding:"omitempty"`
+	From      string `form:"from" binding:"omitempty"`
+	To        string `form:"to" binding:"omitempty"`
+	Cursor    string `form:"cursor" binding:"omitempty"`
+	Limit     int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// InboxEventActionRequest là body của replay/discard một inbox event, reason được ghi vào audit.
+type InboxEventActionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ReplayInboxEventsRequest replay hàng loạt các event FAILED (mặc định) hoặc PARKED khớp bộ lọc, tối đa limit event mỗi lần gọi.
+type ReplayInboxEventsRequest struct {
+	Status    string `json:"status" binding:"omitempty,oneof=FAILED PARKED"`
+	EventType string `json:"event_type" binding:"omitempty"`
+	From      string `json:"from" binding:"omitempty"`
+	To        string `json:"to" binding:"omitempty"`
+	Limit     int    `json:"limit" binding:"omitempty,gte=1,lte=500"`
+	Reason    string `json:"reason" binding:"required,max=500"`
+}
+
+type InboxEventResponse struct {
+	ID            string  `json:"id"`
+	EventID       string  `json:"event_id"`
+	EventType     string  `json:"event_type"`
+	EventVersion  int     `json:"event_version"`
+	SourceService string  `json:"source_service"`
+	Status        string  `json:"status"`
+	RetryCount    int     `json:"retry_count"`
+	MaxRetry      int     `json:"max_retry"`
+	LastError     *string `json:"last_error,omitempty"`
+	ReceivedAt    string  `json:"received_at"`
+	ProcessedAt   *string `json:"processed_at,omitempty"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+// InboxEventDetailResponse kèm payload gốc và nhật ký thao tác của admin.
+type InboxEventDetailResponse struct {
+	InboxEventResponse
+	Payload json.RawMessage           `json:"payload"`
+	Audits  []InboxEventAuditResponse `json:"audits"`
+}
+
+type InboxEventAuditResponse struct {
+	Action    string  `json:"action"`
+	OldStatus string  `json:"old_status"`
+	NewStatus string  `json:"new_status"`
+	Actor     string  `json:"actor"`
+	Reason    string  `json:"reason"`
+	TraceID   *string `json:"trace_id,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
+
+type ReplayInboxEventsResponse struct {
+	ReplayedCount int                  `json:"replayed_count"`
+	Events        []InboxEventResponse `json:"events"`
+}
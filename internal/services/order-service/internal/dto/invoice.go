@@ -0,0 +1,6 @@
+package dto
+
+// InvoiceQuery là query string của GET /orders/:order_id/invoice, mặc định trả về PDF.
+type InvoiceQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=html pdf"`
+}
@@ -12,24 +12,155 @@ type CreateOrderRequest struct {
 	Items             []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive"`
 }
 
+// ListOrdersQuery là query string của GET /orders.
+// from/to nhận định dạng RFC3339 hoặc YYYY-MM-DD; to là mốc kết thúc (không bao gồm).
+type ListOrdersQuery struct {
+	Status string `form:"status" binding:"omitempty"`
+	From   string `form:"from" binding:"omitempty"`
+	To     string `form:"to" binding:"omitempty"`
+	Cursor string `form:"cursor" binding:"omitempty"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// ExportOrdersQuery là query string của GET /shops/:shop_id/orders/export, mặc định xuất CSV.
+// from/to bắt buộc và cùng định dạng với ListOrdersQuery.
+type ExportOrdersQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	Status string `form:"status" binding:"omitempty"`
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+}
+
 type OrderResponse struct {
-	ID                string              `json:"id"`
-	ShopID            string              `json:"shop_id"`
-	ShippingAddressID string              `json:"shipping_address_id"`
-	PromotionID       *string             `json:"promotion_id,omitempty"`
-	ShippingFee       float64             `json:"shipping_fee"`
-	DiscountAmount    float64             `json:"discount_amount"`
-	TotalAmount       float64             `json:"total_amount"`
-	FinalAmount       float64             `json:"final_amount"`
-	Status            string              `json:"status"`
-	CreatedAt         string              `json:"created_at"`
-	UpdatedAt         string              `json:"updated_at"`
-	Items             []OrderItemResponse `json:"items"`
+	ID                string                   `json:"id"`
+	ShopID            string                   `json:"shop_id"`
+	ShippingAddressID string                   `json:"shipping_address_id"`
+	ShippingAddress   *ShippingAddressResponse `json:"shipping_address,omitempty"`
+	PromotionID       *string                  `json:"promotion_id,omitempty"`
+	CheckoutID        *string                  `json:"checkout_id,omitempty"`
+	ShippingFee       float64                  `json:"shipping_fee"`
+	DiscountAmount    float64                  `json:"discount_amount"`
+	TotalAmount       float64                  `json:"total_amount"`
+	FinalAmount       float64                  `json:"final_amount"`
+	Currency          string                   `json:"currency"`
+	Status            string                   `json:"status"`
+	CreatedAt         string                   `json:"created_at"`
+	UpdatedAt         string                   `json:"updated_at"`
+	Items             []OrderItemResponse      `json:"items"`
 }
 
 type OrderItemResponse struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID                string  `json:"id"`
+	ProductID         string  `json:"product_id"`
+	ProductName       string  `json:"product_name"`
+	ThumbnailURL      string  `json:"thumbnail_url,omitempty"`
+	Quantity          int     `json:"quantity"`
+	Price             float64 `json:"price"`
+	Currency          string  `json:"currency"`
+	Status            string  `json:"status"`
+	CanceledQuantity  int     `json:"canceled_quantity"`
+	RemainingQuantity int     `json:"remaining_quantity"`
+}
+
+type ShippingAddressResponse struct {
+	RecipientName  string  `json:"recipient_name"`
+	RecipientPhone string  `json:"recipient_phone"`
+	Street         string  `json:"street"`
+	Ward           string  `json:"ward,omitempty"`
+	District       string  `json:"district,omitempty"`
+	City           string  `json:"city,omitempty"`
+	Country        string  `json:"country,omitempty"`
+	Lat            float64 `json:"lat,omitempty"`
+	Long           float64 `json:"long,omitempty"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// RejectOrderRequest là body khi người bán từ chối đơn hàng, lý do được gửi lại cho khách hàng.
+type RejectOrderRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// CancelOrderItemRequest là body khi người bán huỷ một dòng hàng, bỏ trống quantity để huỷ toàn bộ số lượng còn lại.
+type CancelOrderItemRequest struct {
+	Quantity int    `json:"quantity,omitempty" binding:"omitempty,min=1"`
+	Reason   string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+type CancelOrderItemResponse struct {
+	Order        *OrderResponse `json:"order"`
+	ID           string         `json:"id"`
+	OrderItemID  string         `json:"order_item_id"`
+	ProductID    string         `json:"product_id"`
+	Quantity     int            `json:"quantity"`
+	RefundAmount float64        `json:"refund_amount"`
+	Currency     string         `json:"currency"`
+	Reason       string         `json:"reason"`
+	Restocked    bool           `json:"restocked"`
+	RefundID     *string        `json:"refund_id,omitempty"`
+	CreatedAt    string         `json:"created_at"`
+}
+
+type CancelOrderResponse struct {
+	Order           *OrderResponse `json:"order"`
+	Reason          string         `json:"reason"`
+	PreviousStatus  string         `json:"previous_status"`
+	RefundRequested bool           `json:"refund_requested"`
+	RefundID        *string        `json:"refund_id,omitempty"`
+}
+
+type OrderTimelineResponse struct {
+	OrderID       string                       `json:"order_id"`
+	CurrentStatus string                       `json:"current_status"`
+	Entries       []OrderTimelineEntryResponse `json:"entries"`
+}
+
+type OrderTimelineEntryResponse struct {
+	OldStatus *string `json:"old_status,omitempty"`
+	NewStatus string  `json:"new_status"`
+	Actor     string  `json:"actor"`
+	Reason    string  `json:"reason,omitempty"`
+	TraceID   string  `json:"trace_id,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// CheckoutRequest checkout giỏ hàng hiện tại. ProductIDs rỗng nghĩa là checkout toàn bộ giỏ hàng.
+// Promotions map shop_id -> promotion_id.
+type CheckoutRequest struct {
+	ShippingAddressID string            `json:"shipping_address_id" binding:"required,uuid"`
+	ProductIDs        []string          `json:"product_ids,omitempty" binding:"omitempty,dive,uuid"`
+	Promotions        map[string]string `json:"promotions,omitempty"`
+}
+
+type CheckoutResponse struct {
+	CheckoutID  string                    `json:"checkout_id"`
+	Orders      []*OrderResponse          `json:"orders"`
+	FailedShops []CheckoutFailureResponse `json:"failed_shops,omitempty"`
+}
+
+type CheckoutFailureResponse struct {
+	ShopID string `json:"shop_id"`
+	Reason string `json:"reason"`
+}
+
+// ReorderResponse là kết quả POST /orders/:order_id/reorder. PriceChanged là các sản phẩm đã thêm vào giỏ
+// nhưng có giá khác với đơn cũ.
+type ReorderResponse struct {
+	OrderID      string                `json:"order_id"`
+	Added        []ReorderItemResponse `json:"added"`
+	PriceChanged []ReorderItemResponse `json:"price_changed"`
+	Unavailable  []ReorderItemResponse `json:"unavailable"`
+}
+
+type ReorderItemResponse struct {
+	ProductID         string  `json:"product_id"`
+	ProductName       string  `json:"product_name"`
+	RequestedQuantity int     `json:"requested_quantity"`
+	Quantity          int     `json:"quantity"`
+	PreviousPrice     float64 `json:"previous_price"`
+	CurrentPrice      float64 `json:"current_price"`
+	Currency          string  `json:"currency"`
+	Reason            string  `json:"reason,omitempty"`
 }
@@ -0,0 +1,13 @@
+package dto
+
+// OutboxEventResponse là outbox event của đơn hàng trả về cho admin.
+type OutboxEventResponse struct {
+	ID          string  `json:"id"`
+	OrderID     string  `json:"order_id"`
+	EventType   string  `json:"event_type"`
+	Status      string  `json:"status"`
+	RetryCount  int     `json:"retry_count"`
+	PublishedAt *string `json:"published_at,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
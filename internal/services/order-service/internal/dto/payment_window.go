@@ -0,0 +1,12 @@
+package dto
+
+// UpdatePaymentWindowRequest là body của PUT /payment-windows/:shop_id, tối thiểu 5 phút và tối đa 7 ngày.
+type UpdatePaymentWindowRequest struct {
+	WindowMinutes int `json:"window_minutes" binding:"required,min=5,max=10080"`
+}
+
+type PaymentWindowResponse struct {
+	ShopID        string `json:"shop_id"`
+	IsDefault     bool   `json:"is_default"`
+	WindowMinutes int    `json:"window_minutes"`
+}
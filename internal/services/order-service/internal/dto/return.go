@@ -0,0 +1,51 @@
+package dto
+
+type CreateReturnItemRequest struct {
+	OrderItemID string `json:"order_item_id" binding:"required,uuid"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateReturnRequest là yêu cầu trả hàng của khách cho đơn đã giao, mỗi sản phẩm có thể trả một phần số lượng.
+type CreateReturnRequest struct {
+	Reason string                    `json:"reason" binding:"required,min=3,max=1000"`
+	Items  []CreateReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// RejectReturnRequest là body khi người bán từ chối yêu cầu trả hàng, ghi chú được gửi lại cho khách.
+type RejectReturnRequest struct {
+	Note string `json:"note" binding:"required,min=3,max=500"`
+}
+
+// ListReturnsQuery là query string của GET /shops/:shop_id/returns.
+type ListReturnsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=REQUESTED APPROVED REJECTED RECEIVED REFUNDED"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+type ReturnItemResponse struct {
+	ID          string  `json:"id"`
+	OrderItemID string  `json:"order_item_id"`
+	ProductID   string  `json:"product_id"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+type ReturnResponse struct {
+	ID           string               `json:"id"`
+	OrderID      string               `json:"order_id"`
+	ShopID       string               `json:"shop_id"`
+	Status       string               `json:"status"`
+	Reason       string               `json:"reason"`
+	SellerNote   *string              `json:"seller_note,omitempty"`
+	RefundAmount float64              `json:"refund_amount"`
+	Currency     string               `json:"currency"`
+	RefundID     *string              `json:"refund_id,omitempty"`
+	Restocked    bool                 `json:"restocked"`
+	Items        []ReturnItemResponse `json:"items"`
+	ApprovedAt   *string              `json:"approved_at,omitempty"`
+	RejectedAt   *string              `json:"rejected_at,omitempty"`
+	ReceivedAt   *string              `json:"received_at,omitempty"`
+	RefundedAt   *string              `json:"refunded_at,omitempty"`
+	CreatedAt    string               `json:"created_at"`
+	UpdatedAt    string               `json:"updated_at"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+// ShippingQuoteRequest là body của POST /orders/shipping-quote, dùng để xem trước phí vận chuyển trước khi đặt hàng.
+type ShippingQuoteRequest struct {
+	ShopID            string                   `json:"shop_id" binding:"required,uuid"`
+	ShippingAddressID string                   `json:"shipping_address_id" binding:"required,uuid"`
+	Items             []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type ShippingQuoteResponse struct {
+	ShopID           string   `json:"shop_id"`
+	Currency         string   `json:"currency"`
+	DistanceKm       *float64 `json:"distance_km"` // null khi thiếu toạ độ, phí được tính theo vùng xa nhất
+	TotalWeightGrams int      `json:"total_weight_grams"`
+	ZoneFee          float64  `json:"zone_fee"`
+	WeightSurcharge  float64  `json:"weight_surcharge"`
+	ShippingFee      float64  `json:"shipping_fee"`
+}
+
+type ShippingZoneTier struct {
+	MaxDistanceKm float64 `json:"max_distance_km" binding:"gte=0"`
+	BaseFee       float64 `json:"base_fee" binding:"gte=0"`
+}
+
+type ShippingWeightTier struct {
+	MaxWeightGrams int     `json:"max_weight_grams" binding:"gte=0"`
+	Surcharge      float64 `json:"surcharge" binding:"gte=0"`
+}
+
+// UpdateShippingRatesRequest là body của PUT /shipping-rates/:shop_id.
+// max_distance_km / max_weight_grams bằng 0 nghĩa là không giới hạn và chỉ được dùng cho mức cuối.
+// Phí tính theo currency, đơn có giá sản phẩm khác currency này sẽ không đặt được.
+type UpdateShippingRatesRequest struct {
+	Currency    string               `json:"currency" binding:"required,len=3"`
+	Zones       []ShippingZoneTier   `json:"zones" binding:"required,min=1,dive"`
+	WeightTiers []ShippingWeightTier `json:"weight_tiers" binding:"omitempty,dive"`
+}
+
+type ShippingRatesResponse struct {
+	ShopID      string               `json:"shop_id"`
+	IsDefault   bool                 `json:"is_default"`
+	Currency    string               `json:"currency"`
+	Zones       []ShippingZoneTier   `json:"zones"`
+	WeightTiers []ShippingWeightTier `json:"weight_tiers"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+// ShopAnalyticsQuery là query string của GET /shops/:shop_id/analytics. from/to là ngày (UTC) dạng YYYY-MM-DD,
+// to không bao gồm; mặc định là 30 ngày gần nhất tính cả hôm nay.
+type ShopAnalyticsQuery struct {
+	From        string `form:"from" binding:"omitempty"`
+	To          string `form:"to" binding:"omitempty"`
+	Granularity string `form:"granularity" binding:"omitempty,oneof=day week"`
+	Top         int    `form:"top" binding:"omitempty,gte=1,lte=50"`
+}
+
+type ShopAnalyticsResponse struct {
+	ShopID      string                    `json:"shop_id"`
+	From        string                    `json:"from"`
+	To          string                    `json:"to"`
+	Granularity string                    `json:"granularity"`
+	Series      []ShopSalesBucketResponse `json:"series"`
+	Totals      []ShopSalesBucketResponse `json:"totals"`
+	TopProducts []ShopTopProductResponse  `json:"top_products"`
+}
+
+// ShopSalesBucketResponse: gmv và average_order_value không tính đơn bị huỷ, cancellation_rate từ 0 đến 1.
+type ShopSalesBucketResponse struct {
+	Start             string  `json:"start,omitempty"`
+	Currency          string  `json:"currency"`
+	OrdersCount       int     `json:"orders_count"`
+	CanceledCount     int     `json:"canceled_count"`
+	GrossAmount       float64 `json:"gross_amount"`
+	GMV               float64 `json:"gmv"`
+	AverageOrderValue float64 `json:"average_order_value"`
+	CancellationRate  float64 `json:"cancellation_rate"`
+}
+
+type ShopTopProductResponse struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Currency    string  `json:"currency"`
+	Quantity    int64   `json:"quantity"`
+	Revenue     float64 `json:"revenue"`
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"strconv"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// orderColumns là tiêu đề của file export đơn hàng. Mỗi dòng là một sản phẩm trong đơn; các khoản tiền cấp đơn
+// (tạm tính, giảm giá, phí vận chuyển, tổng) được lặp lại trên mọi dòng của cùng đơn để lọc/pivot theo đơn.
+var orderColumns = []string{
+	"Order ID",
+	"Created At",
+	"Status",
+	"Customer ID",
+	"Promotion",
+	"Recipient",
+	"City",
+	"Product ID",
+	"Product Name",
+	"Quantity",
+	"Unit Price",
+	"Line Total",
+	"Order Subtotal",
+	"Order Discount",
+	"Order Shipping Fee",
+	"Order Total",
+	"Currency",
+}
+
+// WriteOrderHeader ghi dòng tiêu đề của file export đơn hàng.
+func WriteOrderHeader(w Writer) error {
+	header := make([]Cell, len(orderColumns))
+	for i, column := range orderColumns {
+		header[i] = Text(column)
+	}
+	return w.WriteRow(header)
+}
+
+// WriteOrder ghi các dòng sản phẩm của một đơn hàng.
+func WriteOrder(w Writer, order *domain.Order) error {
+	promotion := ""
+	if order.PromotionCode != nil {
+		promotion = *order.PromotionCode
+	}
+	recipient, city := "", ""
+	if order.ShippingAddress != nil {
+		recipient, city = order.ShippingAddress.RecipientName, order.ShippingAddress.City
+	}
+
+	for _, item := range order.Items {
+		row := []Cell{
+			Text(order.ID),
+			Text(order.CreatedAt),
+			Text(string(order.Status)),
+			Text(order.OwnerID),
+			Text(promotion),
+			Text(recipient),
+			Text(city),
+			Text(item.ProductID),
+			Text(item.ProductName),
+			Number(strconv.Itoa(item.Quantity)),
+			Number(item.Price.Decimal()),
+			Number(item.LineTotal().Decimal()),
+			Number(order.TotalAmount.Decimal()),
+			Number(order.DiscountAmount.Decimal()),
+			Number(order.ShippingFee.Decimal()),
+			Number(order.FinalPrice.Decimal()),
+			Text(order.Currency()),
+		}
+		if err := w.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/export"
+)
+
+func TestCSVWriter_Escaping(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cell     export.Cell
+		expected string
+	}{
+		{name: "Plain text", cell: export.Text("Keyboard"), expected: "Keyboard"},
+		{name: "Empty text", cell: export.Text(""), expected: ""},
+		{name: "Formula", cell: export.Text(`=HYPERLINK("http://evil","x")`), expected: `'=HYPERLINK("http://evil","x")`},
+		{name: "Plus sign", cell: export.Text("+84 912 345 678"), expected: "'+84 912 345 678"},
+		{name: "Minus sign", cell: export.Text("-2+3"), expected: "'-2+3"},
+		{name: "At sign", cell: export.Text("@SUM(A1)"), expected: "'@SUM(A1)"},
+		{name: "Leading tab", cell: export.Text("\t=1"), expected: "'\t=1"},
+		{name: "Formula character inside text", cell: export.Text("A=B"), expected: "A=B"},
+		{name: "Comma, quote and newline", cell: export.Text("Nguyen, \"Bob\"\nFloor 2"), expected: "Nguyen, \"Bob\"\nFloor 2"},
+		{name: "Negative number is not escaped", cell: export.Number("-12.50"), expected: "-12.50"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := export.NewCSVWriter(&buf)
+			if err := w.WriteRow([]export.Cell{export.Text("before"), tc.cell, export.Text("after")}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("output is not valid CSV: %v", err)
+			}
+			if len(records) != 1 || len(records[0]) != 3 {
+				t.Fatalf("records = %q, want one row of 3 cells", records)
+			}
+			if records[0][1] != tc.expected {
+				t.Errorf("cell = %q, want %q", records[0][1], tc.expected)
+			}
+		})
+	}
+}
+
+func TestWriteOrder_CSV(t *testing.T) {
+	promotion := "SPRING10"
+	order := &domain.Order{
+		ID:             "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11",
+		OwnerID:        "c4b3a2d1-6e5f-4a7b-9c8d-1e2f3a4b5c6d",
+		PromotionCode:  &promotion,
+		ShippingFee:    money.New(300, "USD"),
+		DiscountAmount: money.New(125, "USD"),
+		TotalAmount:    money.New(3250, "USD"),
+		FinalPrice:     money.New(3425, "USD"),
+		Status:         domain.OrderStatusDELIVERED,
+		CreatedAt:      "2025-03-01T08:30:15Z",
+		ShippingAddress: &domain.ShippingAddress{
+			RecipientName: "=cmd|' /C calc'!A0",
+			City:          "Ha Noi, Viet Nam",
+		},
+		Items: []domain.OrderItem{
+			{ProductID: "product-1", ProductName: "Keyboard", Quantity: 2, Price: money.New(1250, "USD")},
+			{ProductID: "product-2", ProductName: "Mouse \"Pro\"", Quantity: 1, Price: money.New(750, "USD")},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := export.NewCSVWriter(&buf)
+	if err := export.WriteOrderHeader(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := export.WriteOrder(w, order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("rows = %d, want header and one row per item", len(records))
+	}
+	if records[0][0] != "Order ID" || records[0][len(records[0])-1] != "Currency" {
+		t.Errorf("header = %q", records[0])
+	}
+
+	expected := [][]string{
+		{order.ID, "2025-03-01T08:30:15Z", "DELIVERED", order.OwnerID, "SPRING10", "'=cmd|' /C calc'!A0", "Ha Noi, Viet Nam",
+			"product-1", "Keyboard", "2", "12.50", "25.00", "32.50", "1.25", "3.00", "34.25", "USD"},
+		{order.ID, "2025-03-01T08:30:15Z", "DELIVERED", order.OwnerID, "SPRING10", "'=cmd|' /C calc'!A0", "Ha Noi, Viet Nam",
+			"product-2", "Mouse \"Pro\"", "1", "7.50", "7.50", "32.50", "1.25", "3.00", "34.25", "USD"},
+	}
+	for i, want := range expected {
+		if got := strings.Join(records[i+1], "|"); got != strings.Join(want, "|") {
+			t.Errorf("row %d = %q, want %q", i+1, records[i+1], want)
+		}
+	}
+}
+
+func TestWriteOrder_WithoutItems(t *testing.T) {
+	var buf bytes.Buffer
+	w := export.NewCSVWriter(&buf)
+
+	if err := export.WriteOrder(w, &domain.Order{ID: "order-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output = %q, want no rows", buf.String())
+	}
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	if _, err := export.NewWriter("pdf", &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
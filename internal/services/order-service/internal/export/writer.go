@@ -0,0 +1,104 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Cell là một ô của file export. Numeric đánh dấu ô số để XLSX lưu dạng số (cộng/lọc được trong bảng tính);
+// Value của ô số phải là số thập phân dạng 1234.50.
+type Cell struct {
+	Value   string
+	Numeric bool
+}
+
+func Text(value string) Cell {
+	return Cell{Value: value}
+}
+
+func Number(value string) Cell {
+	return Cell{Value: value, Numeric: true}
+}
+
+// Writer ghi lần lượt từng dòng ra output ngay khi nhận được, không giữ lại các dòng đã ghi.
+// Close hoàn tất file (flush CSV, đóng sheet và zip của XLSX) nhưng không đóng output.
+type Writer interface {
+	WriteRow(cells []Cell) error
+	Close() error
+}
+
+// NewWriter tạo Writer theo định dạng csv hoặc xlsx.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// ContentType trả về MIME type của định dạng export.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// csvFlushEvery: số dòng giữa hai lần flush để client nhận dữ liệu dần thay vì đợi hết file.
+const csvFlushEvery = 100
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+// NewCSVWriter ghi CSV chuẩn RFC 4180; CSV không phân biệt kiểu nên ô số được ghi nguyên giá trị.
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells []Cell) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = cell.Value
+		if !cell.Numeric {
+			record[i] = escapeFormula(cell.Value)
+		}
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+
+	c.rows++
+	if c.rows%csvFlushEvery == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula thêm dấu ' trước ô chữ bắt đầu bằng ký tự công thức để bảng tính không thực thi nội dung
+// do người dùng nhập (tên sản phẩm, tên người nhận) khi mở file CSV.
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
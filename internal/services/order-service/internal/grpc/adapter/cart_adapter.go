@@ -0,0 +1,72 @@
+package adapter
+
+import (
+	"context"
+	"log"
+
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type CartServiceAdapter interface {
+	GetCart(ctx context.Context, userID string) (*cart_v1.GetCartResponse, error)
+	RemoveCartItems(ctx context.Context, userID string, productIDs []string) (*cart_v1.RemoveCartItemsResponse, error)
+	AddCartItems(ctx context.Context, userID string, items []*cart_v1.CartItem) (*cart_v1.AddCartItemsResponse, error)
+	Close() error
+}
+
+type grpcCartAdapter struct {
+	conn   *grpc.ClientConn
+	client cart_v1.CartServiceClient
+}
+
+func NewGrpcCartAdapter(cartServiceAddr string) (CartServiceAdapter, error) {
+	log.Printf("Connecting to cart service at %s", cartServiceAddr)
+	conn, err := grpc.NewClient(
+		cartServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		log.Printf("Failed to connect to cart service: %v", err)
+		return nil, err
+	}
+
+	client := cart_v1.NewCartServiceClient(conn)
+
+	log.Printf("Successfully connected to cart service at %s", cartServiceAddr)
+
+	return &grpcCartAdapter{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+func (a *grpcCartAdapter) GetCart(ctx context.Context, userID string) (*cart_v1.GetCartResponse, error) {
+	return a.client.GetCart(ctx, &cart_v1.GetCartRequest{
+		UserId: userID,
+	})
+}
+
+func (a *grpcCartAdapter) RemoveCartItems(ctx context.Context, userID string, productIDs []string) (*cart_v1.RemoveCartItemsResponse, error) {
+	return a.client.RemoveCartItems(ctx, &cart_v1.RemoveCartItemsRequest{
+		UserId:     userID,
+		ProductIds: productIDs,
+	})
+}
+
+func (a *grpcCartAdapter) AddCartItems(ctx context.Context, userID string, items []*cart_v1.CartItem) (*cart_v1.AddCartItemsResponse, error) {
+	return a.client.AddCartItems(ctx, &cart_v1.AddCartItemsRequest{
+		UserId: userID,
+		Items:  items,
+	})
+}
+
+func (a *grpcCartAdapter) Close() error {
+	if a.conn != nil {
+		return a.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package adapter
+
+import (
+	"context"
+	"log"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type PaymentServiceAdapter interface {
+	GetPaymentByOrder(ctx context.Context, orderID string) (*payment_v1.GetPaymentByOrderResponse, error)
+	// GetPaymentsByOrders tra cứu payment của nhiều đơn hàng (tối đa 100), đơn chưa có payment không có trong kết quả.
+	GetPaymentsByOrders(ctx context.Context, orderIDs []string) (*payment_v1.GetPaymentsByOrdersResponse, error)
+	RequestRefund(ctx context.Context, orderID string, reason string) (*payment_v1.RequestRefundResponse, error)
+	// RequestPartialRefund hoàn một phần số tiền đã thanh toán, idempotent theo referenceID.
+	RequestPartialRefund(ctx context.Context, orderID string, referenceID string, amount money.Money, reason string) (*payment_v1.RequestRefundResponse, error)
+	// ConfirmCashCollected báo payment-service shipper đã thu tiền mặt của đơn COD, idempotent theo đơn hàng.
+	ConfirmCashCollected(ctx context.Context, orderID string, collectionID string, amount money.Money) (*payment_v1.ConfirmCashCollectedResponse, error)
+	// CancelCODPayment huỷ payment COD chưa thu tiền của đơn bị huỷ, idempotent theo đơn hàng.
+	CancelCODPayment(ctx context.Context, orderID string, reason string) (*payment_v1.CancelCODPaymentResponse, error)
+	// CancelPendingPayment huỷ payment chưa thanh toán của đơn hết hạn thanh toán, idempotent theo đơn hàng.
+	// Trả về lỗi codes.FailedPrecondition khi khách đã thanh toán, codes.NotFound khi đơn chưa có payment.
+	CancelPendingPayment(ctx context.Context, orderID string, reason string) (*payment_v1.CancelPendingPaymentResponse, error)
+	Close() error
+}
+
+type grpcPaymentAdapter struct {
+	conn   *grpc.ClientConn
+	client payment_v1.PaymentServiceClient
+}
+
+func NewGrpcPaymentAdapter(paymentServiceAddr string) (PaymentServiceAdapter, error) {
+	log.Printf("Connecting to payment service at %s", paymentServiceAddr)
+	conn, err := grpc.NewClient(
+		paymentServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		log.Printf("Failed to connect to payment service: %v", err)
+		return nil, err
+	}
+
+	client := payment_v1.NewPaymentServiceClient(conn)
+
+	log.Printf("Successfully connected to payment service at %s", paymentServiceAddr)
+
+	return &grpcPaymentAdapter{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+func (a *grpcPaymentAdapter) GetPaymentByOrder(ctx context.Context, orderID string) (*payment_v1.GetPaymentByOrderResponse, error) {
+	return a.client.GetPaymentByOrder(ctx, &payment_v1.GetPaymentByOrderRequest{
+		OrderId: orderID,
+	})
+}
+
+func (a *grpcPaymentAdapter) GetPaymentsByOrders(ctx context.Context, orderIDs []string) (*payment_v1.GetPaymentsByOrdersResponse, error) {
+	return a.client.GetPaymentsByOrders(ctx, &payment_v1.GetPaymentsByOrdersRequest{
+		OrderIds: orderIDs,
+	})
+}
+
+func (a *grpcPaymentAdapter) RequestRefund(ctx context.Context, orderID string, reason string) (*payment_v1.RequestRefundResponse, error) {
+	return a.client.RequestRefund(ctx, &payment_v1.RequestRefundRequest{
+		OrderId: orderID,
+		Reason:  reason,
+	})
+}
+
+func (a *grpcPaymentAdapter) RequestPartialRefund(ctx context.Context, orderID string, referenceID string, amount money.Money, reason string) (*payment_v1.RequestRefundResponse, error) {
+	return a.client.RequestRefund(ctx, &payment_v1.RequestRefundRequest{
+		OrderId:      orderID,
+		Reason:       reason,
+		Amount:       amount.Float64(),
+		ReferenceId:  referenceID,
+		RefundAmount: amount.ToProto(),
+	})
+}
+
+func (a *grpcPaymentAdapter) ConfirmCashCollected(ctx context.Context, orderID string, collectionID string, amount money.Money) (*payment_v1.ConfirmCashCollectedResponse, error) {
+	return a.client.ConfirmCashCollected(ctx, &payment_v1.ConfirmCashCollectedRequest{
+		OrderId:         orderID,
+		CollectionId:    collectionID,
+		CollectedAmount: amount.ToProto(),
+	})
+}
+
+func (a *grpcPaymentAdapter) CancelCODPayment(ctx context.Context, orderID string, reason string) (*payment_v1.CancelCODPaymentResponse, error) {
+	return a.client.CancelCODPayment(ctx, &payment_v1.CancelCODPaymentRequest{
+		OrderId: orderID,
+		Reason:  reason,
+	})
+}
+
+func (a *grpcPaymentAdapter) CancelPendingPayment(ctx context.Context, orderID string, reason string) (*payment_v1.CancelPendingPaymentResponse, error) {
+	return a.client.CancelPendingPayment(ctx, &payment_v1.CancelPendingPaymentRequest{
+		OrderId: orderID,
+		Reason:  reason,
+	})
+}
+
+func (a *grpcPaymentAdapter) Close() error {
+	if a.conn != nil {
+		return a.conn.Close()
+	}
+	return nil
+}
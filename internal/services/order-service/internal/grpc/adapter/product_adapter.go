@@ -16,6 +16,7 @@ type ProductServiceAdapter interface {
 	GetOrderReservationStatus(ctx context.Context, req *product_v1.GetOrderReservationStatusRequest) (*product_v1.GetOrderReservationStatusResponse, error)
 	GetOrdersReservationStatus(ctx context.Context, req *product_v1.GetOrdersReservationStatusRequest) (*product_v1.GetOrdersReservationStatusResponse, error)
 	UnreserveOrders(ctx context.Context, req *product_v1.UnreserveOrdersRequest) (*product_v1.UnreserveOrdersResponse, error)
+	RestockProducts(ctx context.Context, req *product_v1.RestockProductsRequest) (*product_v1.RestockProductsResponse, error)
 	Close() error
 }
 
@@ -67,6 +68,10 @@ func (a *grpcProductAdapter) UnreserveOrders(ctx context.Context, req *product_v
 	return a.client.UnreserveOrders(ctx, req)
 }
 
+func (a *grpcProductAdapter) RestockProducts(ctx context.Context, req *product_v1.RestockProductsRequest) (*product_v1.RestockProductsResponse, error) {
+	return a.client.RestockProducts(ctx, req)
+}
+
 func (a *grpcProductAdapter) Close() error {
 	if a.conn != nil {
 		return a.conn.Close()
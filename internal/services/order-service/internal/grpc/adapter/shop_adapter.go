@@ -12,6 +12,10 @@ import (
 
 type ShopServiceAdapter interface {
 	CheckShopExists(ctx context.Context, shopID string) (bool, error)
+	CheckShopOwnership(ctx context.Context, shopID string, userID string) (bool, error)
+	GetShopAddress(ctx context.Context, shopID string) (*shop_v1.ShopAddress, error)
+	GetShopAddresses(ctx context.Context, shopIDs []string) (map[string]*shop_v1.ShopAddress, error)
+	GetShopInfo(ctx context.Context, shopID string) (*shop_v1.ShopInfo, error)
 	CalculatePromotion(ctx context.Context, req *shop_v1.CalculatePromotionRequest) (*shop_v1.CalculatePromotionResponse, error)
 	Close() error
 }
@@ -55,6 +59,74 @@ func (a *grpcShopAdapter) CheckShopExists(ctx context.Context, shopID string) (b
 	return res.GetExists(), nil
 }
 
+func (a *grpcShopAdapter) CheckShopOwnership(ctx context.Context, shopID string, userID string) (bool, error) {
+	req := &shop_v1.CheckShopOwnershipRequest{
+		ShopId: shopID,
+		UserId: userID,
+	}
+	res, err := a.client.CheckShopOwnership(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	return res.GetIsOwner(), nil
+}
+
+// GetShopAddress trả về nil nếu shop hoặc địa chỉ của shop không tồn tại.
+func (a *grpcShopAdapter) GetShopAddress(ctx context.Context, shopID string) (*shop_v1.ShopAddress, error) {
+	req := &shop_v1.GetShopAddressRequest{
+		ShopId: shopID,
+	}
+	res, err := a.client.GetShopAddress(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.GetFound() {
+		return nil, nil
+	}
+
+	return res.GetAddress(), nil
+}
+
+// GetShopAddresses trả về địa chỉ của nhiều shop theo shop_id, shop không tồn tại hoặc chưa có địa chỉ thì không có trong map.
+func (a *grpcShopAdapter) GetShopAddresses(ctx context.Context, shopIDs []string) (map[string]*shop_v1.ShopAddress, error) {
+	addresses := make(map[string]*shop_v1.ShopAddress, len(shopIDs))
+	if len(shopIDs) == 0 {
+		return addresses, nil
+	}
+
+	req := &shop_v1.GetShopAddressesRequest{
+		ShopIds: shopIDs,
+	}
+	res, err := a.client.GetShopAddresses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pickup := range res.GetAddresses() {
+		addresses[pickup.GetShopId()] = pickup.GetAddress()
+	}
+	return addresses, nil
+}
+
+// GetShopInfo trả về nil nếu shop không tồn tại.
+func (a *grpcShopAdapter) GetShopInfo(ctx context.Context, shopID string) (*shop_v1.ShopInfo, error) {
+	req := &shop_v1.GetShopInfoRequest{
+		ShopId: shopID,
+	}
+	res, err := a.client.GetShopInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !res.GetFound() {
+		return nil, nil
+	}
+
+	return res.GetShop(), nil
+}
+
 func (a *grpcShopAdapter) CalculatePromotion(ctx context.Context, req *shop_v1.CalculatePromotionRequest) (*shop_v1.CalculatePromotionResponse, error) {
 	return a.client.CalculatePromotion(ctx, req)
 }
@@ -2,12 +2,18 @@ package grpc_server
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
 	order_v1 "github.com/toji-dev/go-shop/proto/gen/go/order/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -21,41 +27,140 @@ func NewOrderGRPCServer(orderRepo repository.OrderRepository) *Server {
 	}
 }
 
+const (
+	maxBatchOrderIDs    = 100
+	defaultListPageSize = 20
+	maxListPageSize     = 100
+)
+
 func (s *Server) GetOrder(ctx context.Context, in *order_v1.GetOrderRequest) (*order_v1.GetOrderResponse, error) {
 	orderId := in.GetOrderId()
+	if _, err := uuid.Parse(orderId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order id: %s", orderId)
+	}
 
 	order, err := s.orderRepo.GetOrderByID(ctx, orderId)
-
 	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return &order_v1.GetOrderResponse{Exists: false}, nil
+		}
 		log.Printf("Error retrieving order with ID %s: %v", orderId, err)
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
-	responseOrder := &order_v1.Order{
-		Id:             order.ID,
-		CustomerId:     order.OwnerID,
-		ShopId:         order.ShopID,
-		ShippingFee:    float32(order.ShippingFee),
-		DiscountAmount: float32(order.DiscountAmount),
-		TotalAmount:    float32(order.TotalAmount),
-		FinalAmount:    float32(order.FinalPrice),
-		OrderStatus:    toProtoOrderStatus(string(order.Status)),
+	return &order_v1.GetOrderResponse{
+		Exists: true,
+		Order:  toProtoOrder(order),
+	}, nil
+}
+
+// GetOrders lấy nhiều đơn hàng trong một lần gọi, id trùng lặp chỉ được tính một lần.
+func (s *Server) GetOrders(ctx context.Context, in *order_v1.GetOrdersRequest) (*order_v1.GetOrdersResponse, error) {
+	orderIDs := make([]string, 0, len(in.GetOrderIds()))
+	seen := make(map[string]bool, len(in.GetOrderIds()))
+	for _, orderID := range in.GetOrderIds() {
+		if _, err := uuid.Parse(orderID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid order id: %s", orderID)
+		}
+		if !seen[orderID] {
+			seen[orderID] = true
+			orderIDs = append(orderIDs, orderID)
+		}
 	}
 
-	return &order_v1.GetOrderResponse{
-		Order: responseOrder,
+	if len(orderIDs) > maxBatchOrderIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d order ids are allowed per request", maxBatchOrderIDs)
+	}
+	if len(orderIDs) == 0 {
+		return &order_v1.GetOrdersResponse{}, nil
+	}
+
+	orders, err := s.orderRepo.GetOrdersByIDs(ctx, orderIDs)
+	if err != nil {
+		log.Printf("Error retrieving orders %v: %v", orderIDs, err)
+		return nil, toGRPCError(err)
+	}
+
+	found := make(map[string]bool, len(orders))
+	protoOrders := make([]*order_v1.Order, 0, len(orders))
+	for _, order := range orders {
+		found[order.ID] = true
+		protoOrders = append(protoOrders, toProtoOrder(order))
+	}
+
+	missing := make([]string, 0)
+	for _, orderID := range orderIDs {
+		if !found[orderID] {
+			missing = append(missing, orderID)
+		}
+	}
+
+	return &order_v1.GetOrdersResponse{
+		Orders:          protoOrders,
+		MissingOrderIds: missing,
 	}, nil
 }
 
+func (s *Server) ListOrdersByShop(ctx context.Context, in *order_v1.ListOrdersByShopRequest) (*order_v1.ListOrdersResponse, error) {
+	if _, err := uuid.Parse(in.GetShopId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid shop id: %s", in.GetShopId())
+	}
+
+	filter, err := toOrderListFilter(in.GetStatus(), in.GetLimit(), in.GetCursor())
+	if err != nil {
+		return nil, err
+	}
+	filter.ShopID = in.GetShopId()
+
+	page, err := s.orderRepo.ListOrdersByShop(ctx, filter)
+	if err != nil {
+		log.Printf("Error listing orders of shop %s: %v", in.GetShopId(), err)
+		return nil, toGRPCError(err)
+	}
+
+	return toListOrdersResponse(page), nil
+}
+
+func (s *Server) ListOrdersByCustomer(ctx context.Context, in *order_v1.ListOrdersByCustomerRequest) (*order_v1.ListOrdersResponse, error) {
+	if _, err := uuid.Parse(in.GetCustomerId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid customer id: %s", in.GetCustomerId())
+	}
+
+	filter, err := toOrderListFilter(in.GetStatus(), in.GetLimit(), in.GetCursor())
+	if err != nil {
+		return nil, err
+	}
+	filter.OwnerID = in.GetCustomerId()
+
+	page, err := s.orderRepo.ListOrdersByOwner(ctx, filter)
+	if err != nil {
+		log.Printf("Error listing orders of customer %s: %v", in.GetCustomerId(), err)
+		return nil, toGRPCError(err)
+	}
+
+	return toListOrdersResponse(page), nil
+}
+
 func (s *Server) UpdateOrderStatus(ctx context.Context, in *order_v1.UpdateOrderStatusRequest) (*order_v1.UpdateOrderStatusResponse, error) {
 	orderId := in.GetOrderId()
 	statusEnum := in.GetNewStatus()
 	statusString := fromProtoOrderStatus(statusEnum)
+	if statusEnum == order_v1.OrderStatus_ORDER_STATUS_UNSPECIFIED || !domain.OrderStatus(statusString).IsValid() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order status: %s", statusEnum.String())
+	}
 
-	_, err := s.orderRepo.UpdateOrderStatus(ctx, orderId, sqlc.OrderStatus(statusString))
+	change := domain.StatusChange{
+		Actor:  in.GetActor(),
+		Reason: in.GetReason(),
+	}
+	if change.Actor == "" {
+		change.Actor = domain.StatusActorGRPCClient
+	}
+
+	_, err := s.orderRepo.UpdateOrderStatus(ctx, orderId, sqlc.OrderStatus(statusString), change)
 	if err != nil {
 		log.Printf("Error updating order status for ID %s: %v", orderId, err)
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return &order_v1.UpdateOrderStatusResponse{
@@ -64,6 +169,151 @@ func (s *Server) UpdateOrderStatus(ctx context.Context, in *order_v1.UpdateOrder
 	}, nil
 }
 
+func (s *Server) GetOrderTimeline(ctx context.Context, in *order_v1.GetOrderTimelineRequest) (*order_v1.GetOrderTimelineResponse, error) {
+	orderId := in.GetOrderId()
+
+	if _, err := s.orderRepo.GetOrderByID(ctx, orderId); err != nil {
+		log.Printf("Error retrieving order with ID %s: %v", orderId, err)
+		return nil, toGRPCError(err)
+	}
+
+	history, err := s.orderRepo.GetOrderStatusHistory(ctx, orderId)
+	if err != nil {
+		log.Printf("Error retrieving timeline of order %s: %v", orderId, err)
+		return nil, toGRPCError(err)
+	}
+
+	entries := make([]*order_v1.OrderStatusChange, 0, len(history))
+	for _, entry := range history {
+		change := &order_v1.OrderStatusChange{
+			Id:        entry.ID,
+			OrderId:   entry.OrderID,
+			NewStatus: toProtoOrderStatus(string(entry.NewStatus)),
+			Actor:     entry.Actor,
+			Reason:    entry.Reason,
+			TraceId:   entry.TraceID,
+			CreatedAt: entry.CreatedAt,
+		}
+		if entry.OldStatus != nil {
+			change.OldStatus = toProtoOrderStatus(string(*entry.OldStatus))
+		}
+		entries = append(entries, change)
+	}
+
+	return &order_v1.GetOrderTimelineResponse{
+		Entries: entries,
+	}, nil
+}
+
+// toOrderListFilter kiểm tra các tham số phân trang chung của ListOrdersByShop và ListOrdersByCustomer.
+func toOrderListFilter(orderStatus order_v1.OrderStatus, limit int32, cursor string) (domain.OrderListFilter, error) {
+	filter := domain.OrderListFilter{Limit: defaultListPageSize}
+
+	if limit < 0 || limit > maxListPageSize {
+		return filter, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxListPageSize)
+	}
+	if limit > 0 {
+		filter.Limit = int(limit)
+	}
+
+	if orderStatus != order_v1.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		statusFilter := domain.OrderStatus(fromProtoOrderStatus(orderStatus))
+		if !statusFilter.IsValid() {
+			return filter, status.Errorf(codes.InvalidArgument, "invalid order status: %s", orderStatus.String())
+		}
+		filter.Status = &statusFilter
+	}
+
+	if cursor != "" {
+		decoded, err := domain.DecodeOrderCursor(cursor)
+		if err != nil {
+			return filter, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		filter.Cursor = decoded
+	}
+
+	return filter, nil
+}
+
+func toListOrdersResponse(page *domain.OrderPage) *order_v1.ListOrdersResponse {
+	resp := &order_v1.ListOrdersResponse{
+		Orders: make([]*order_v1.Order, 0, len(page.Orders)),
+	}
+	for _, order := range page.Orders {
+		resp.Orders = append(resp.Orders, toProtoOrder(order))
+	}
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
+	}
+	return resp
+}
+
+func toProtoOrder(order *domain.Order) *order_v1.Order {
+	protoOrder := &order_v1.Order{
+		Id:             order.ID,
+		CustomerId:     order.OwnerID,
+		ShopId:         order.ShopID,
+		ShippingFee:    float32(order.ShippingFee.Float64()),
+		DiscountAmount: float32(order.DiscountAmount.Float64()),
+		TotalAmount:    float32(order.TotalAmount.Float64()),
+		FinalAmount:    float32(order.FinalPrice.Float64()),
+		Shipping:       order.ShippingFee.ToProto(),
+		Discount:       order.DiscountAmount.ToProto(),
+		Subtotal:       order.TotalAmount.ToProto(),
+		Total:          order.FinalPrice.ToProto(),
+		OrderStatus:    toProtoOrderStatus(string(order.Status)),
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+		Items:          make([]*order_v1.OrderItem, 0, len(order.Items)),
+	}
+	if order.PromotionCode != nil {
+		protoOrder.PromotionId = *order.PromotionCode
+	}
+	if order.CheckoutID != nil {
+		protoOrder.CheckoutId = *order.CheckoutID
+	}
+	if address := order.ShippingAddress; address != nil {
+		protoOrder.ShippingAddress = &order_v1.ShippingAddress{
+			RecipientName:  address.RecipientName,
+			RecipientPhone: address.RecipientPhone,
+			Street:         address.Street,
+			Ward:           address.Ward,
+			District:       address.District,
+			City:           address.City,
+			Country:        address.Country,
+			Lat:            address.Lat,
+			Long:           address.Long,
+		}
+	}
+	for _, item := range order.Items {
+		protoOrder.Items = append(protoOrder.Items, &order_v1.OrderItem{
+			Id:           item.ID,
+			ProductId:    item.ProductID,
+			ProductName:  item.ProductName,
+			ThumbnailUrl: item.ThumbnailURL,
+			Quantity:     int32(item.Quantity),
+			Price:        item.Price.Float64(),
+			Currency:     item.Price.Currency,
+			UnitPrice:    item.Price.ToProto(),
+		})
+	}
+	return protoOrder
+}
+
+// toGRPCError ánh xạ lỗi domain/repository sang gRPC status code để client phân biệt được nguyên nhân.
+func toGRPCError(err error) error {
+	var appErr *apperror.AppError
+	switch {
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrOrderStatusChanged):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &appErr) && appErr.Type == apperror.TypeNotFound:
+		return status.Error(codes.NotFound, appErr.Message)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
 func fromProtoOrderStatus(status order_v1.OrderStatus) string {
 	return strings.TrimPrefix(status.String(), "ORDER_STATUS_")
 }
@@ -0,0 +1,479 @@
+package grpc_server_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	grpc_server "github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/server"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	order_v1 "github.com/toji-dev/go-shop/proto/gen/go/order/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testOrderID    = "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"
+	testShopID     = "3a7d9c2b-1e4f-4b6a-8c5d-2f1e0a9b8c7d"
+	testCustomerID = "c4b3a2d1-6e5f-4a7b-9c8d-1e2f3a4b5c6d"
+)
+
+// fakeOrderRepository giữ các đơn hàng theo ID, các method không dùng tới sẽ panic qua interface nhúng.
+type fakeOrderRepository struct {
+	repository.OrderRepository
+	orders  map[string]*domain.Order
+	history []*domain.OrderStatusHistory
+	// list là kết quả liệt kê đã sắp xếp, được phân trang theo cursor và limit của filter
+	list       []*domain.Order
+	filters    []domain.OrderListFilter
+	batchCalls [][]string
+}
+
+func (f *fakeOrderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	f.batchCalls = append(f.batchCalls, orderIDs)
+	orders := make([]*domain.Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if order, ok := f.orders[orderID]; ok {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (f *fakeOrderRepository) ListOrdersByShop(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	return f.page(filter), nil
+}
+
+func (f *fakeOrderRepository) ListOrdersByOwner(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	return f.page(filter), nil
+}
+
+func (f *fakeOrderRepository) page(filter domain.OrderListFilter) *domain.OrderPage {
+	f.filters = append(f.filters, filter)
+
+	start := 0
+	if filter.Cursor != nil {
+		for i, order := range f.list {
+			if order.ID == filter.Cursor.ID {
+				start = i + 1
+			}
+		}
+	}
+	end := min(start+filter.Limit, len(f.list))
+
+	page := &domain.OrderPage{Orders: f.list[start:end]}
+	if end < len(f.list) {
+		page.NextCursor = &domain.OrderCursor{CreatedAt: testCreatedAt(end - 1), ID: f.list[end-1].ID}
+	}
+	return page
+}
+
+func testCreatedAt(i int) time.Time {
+	return time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Minute)
+}
+
+// newTestOrders tạo n đơn hàng có ID dạng UUID, theo thứ tự mới nhất trước.
+func newTestOrders(n int) []*domain.Order {
+	orders := make([]*domain.Order, 0, n)
+	for i := range n {
+		orders = append(orders, &domain.Order{
+			ID:        fmt.Sprintf("00000000-0000-4000-8000-%012d", i),
+			OwnerID:   testCustomerID,
+			ShopID:    testShopID,
+			Status:    domain.OrderStatusCONFIRMED,
+			CreatedAt: testCreatedAt(i).Format(time.RFC3339),
+		})
+	}
+	return orders
+}
+
+func (f *fakeOrderRepository) GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error) {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	return order, nil
+}
+
+func (f *fakeOrderRepository) GetOrderStatusHistory(ctx context.Context, orderID string) ([]*domain.OrderStatusHistory, error) {
+	return f.history, nil
+}
+
+func TestServer_GetOrderTimeline(t *testing.T) {
+	pending := domain.OrderStatusPENDING
+	repo := &fakeOrderRepository{
+		orders: map[string]*domain.Order{testOrderID: {ID: testOrderID, Status: domain.OrderStatusPENDINGPAYMENT}},
+		history: []*domain.OrderStatusHistory{
+			{ID: 1, OrderID: testOrderID, NewStatus: domain.OrderStatusPENDING, Actor: domain.StatusActorOrderService, CreatedAt: "2025-03-01T08:30:15Z"},
+			{ID: 2, OrderID: testOrderID, OldStatus: &pending, NewStatus: domain.OrderStatusPENDINGPAYMENT, Actor: domain.StatusActorOrderService, Reason: "product stock reserved", TraceID: "trace-1"},
+		},
+	}
+	server := grpc_server.NewOrderGRPCServer(repo)
+
+	res, err := server.GetOrderTimeline(context.Background(), &order_v1.GetOrderTimelineRequest{OrderId: testOrderID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := res.GetEntries()
+	if len(entries) != 2 {
+		t.Fatalf("entries = %v, want 2 entries", entries)
+	}
+	if entries[0].GetOldStatus() != order_v1.OrderStatus_ORDER_STATUS_UNSPECIFIED || entries[0].GetNewStatus() != order_v1.OrderStatus_ORDER_STATUS_PENDING {
+		t.Errorf("first entry = %v, want creation as PENDING", entries[0])
+	}
+	if entries[0].GetCreatedAt() != "2025-03-01T08:30:15Z" {
+		t.Errorf("created at = %s, want 2025-03-01T08:30:15Z", entries[0].GetCreatedAt())
+	}
+	second := entries[1]
+	if second.GetOldStatus() != order_v1.OrderStatus_ORDER_STATUS_PENDING || second.GetNewStatus() != order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT {
+		t.Errorf("second entry = %v, want PENDING -> PENDING_PAYMENT", second)
+	}
+	if second.GetId() != 2 || second.GetOrderId() != testOrderID || second.GetReason() != "product stock reserved" || second.GetTraceId() != "trace-1" {
+		t.Errorf("second entry = %v, want id 2 with reason and trace id", second)
+	}
+}
+
+func TestServer_GetOrderTimeline_NotFound(t *testing.T) {
+	server := grpc_server.NewOrderGRPCServer(&fakeOrderRepository{})
+
+	_, err := server.GetOrderTimeline(context.Background(), &order_v1.GetOrderTimelineRequest{OrderId: testOrderID})
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("error = %v, want %s", err, codes.NotFound)
+	}
+}
+
+func TestServer_GetOrder(t *testing.T) {
+	promotion := "SPRING10"
+	repo := &fakeOrderRepository{
+		orders: map[string]*domain.Order{testOrderID: {
+			ID:             testOrderID,
+			OwnerID:        testCustomerID,
+			ShopID:         testShopID,
+			PromotionCode:  &promotion,
+			ShippingFee:    money.New(300, "USD"),
+			DiscountAmount: money.New(125, "USD"),
+			TotalAmount:    money.New(2500, "USD"),
+			FinalPrice:     money.New(2675, "USD"),
+			Status:         domain.OrderStatusSHIPPED,
+			ShippingAddress: &domain.ShippingAddress{
+				RecipientName: "Nguyen Van A",
+				Street:        "12 Ly Thuong Kiet",
+				City:          "Ha Noi",
+			},
+			Items: []domain.OrderItem{
+				{ID: "item-1", ProductID: "product-1", ProductName: "Keyboard", Quantity: 2, Price: money.New(1250, "USD")},
+			},
+		}},
+	}
+	server := grpc_server.NewOrderGRPCServer(repo)
+
+	res, err := server.GetOrder(context.Background(), &order_v1.GetOrderRequest{OrderId: testOrderID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := res.GetOrder()
+	if !res.GetExists() || order.GetId() != testOrderID {
+		t.Fatalf("response = %v, want existing order %s", res, testOrderID)
+	}
+	if order.GetOrderStatus() != order_v1.OrderStatus_ORDER_STATUS_SHIPPED || order.GetPromotionId() != promotion {
+		t.Errorf("order = %v, want SHIPPED with promotion %s", order, promotion)
+	}
+	if total := order.GetTotal(); total.GetAmount() != 2675 || total.GetCurrency() != "USD" {
+		t.Errorf("total = %v, want 2675 USD", total)
+	}
+	if order.GetShippingAddress().GetRecipientName() != "Nguyen Van A" || order.GetShippingAddress().GetCity() != "Ha Noi" {
+		t.Errorf("shipping address = %v, want the order snapshot", order.GetShippingAddress())
+	}
+	items := order.GetItems()
+	if len(items) != 1 || items[0].GetQuantity() != 2 || items[0].GetUnitPrice().GetAmount() != 1250 {
+		t.Errorf("items = %v, want 2 x 1250 USD", items)
+	}
+}
+
+func TestServer_GetOrder_NotFoundAndInvalidID(t *testing.T) {
+	server := grpc_server.NewOrderGRPCServer(&fakeOrderRepository{})
+
+	res, err := server.GetOrder(context.Background(), &order_v1.GetOrderRequest{OrderId: testOrderID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.GetExists() || res.GetOrder() != nil {
+		t.Errorf("response = %v, want missing order", res)
+	}
+
+	_, err = server.GetOrder(context.Background(), &order_v1.GetOrderRequest{OrderId: "order-1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error = %v, want %s", err, codes.InvalidArgument)
+	}
+}
+
+func TestServer_GetOrders(t *testing.T) {
+	orders := newTestOrders(2)
+	missingID := "00000000-0000-4000-8000-999999999999"
+	tooMany := make([]string, 0, 101)
+	for _, order := range newTestOrders(101) {
+		tooMany = append(tooMany, order.ID)
+	}
+
+	testCases := []struct {
+		name            string
+		orderIDs        []string
+		expectedIDs     []string
+		expectedMissing []string
+		expectedCalls   int
+		expectedCode    codes.Code
+	}{
+		{
+			name:            "Found and missing orders",
+			orderIDs:        []string{orders[0].ID, missingID, orders[1].ID},
+			expectedIDs:     []string{orders[0].ID, orders[1].ID},
+			expectedMissing: []string{missingID},
+			expectedCalls:   1,
+		},
+		{
+			name:          "Duplicate ids are fetched once",
+			orderIDs:      []string{orders[0].ID, orders[0].ID, orders[0].ID},
+			expectedIDs:   []string{orders[0].ID},
+			expectedCalls: 1,
+		},
+		{
+			name:     "Empty request does not query the repository",
+			orderIDs: nil,
+		},
+		{
+			name:          "Exactly the batch limit after dedup",
+			orderIDs:      append(append([]string{}, tooMany[:100]...), tooMany[0]),
+			expectedCalls: 1,
+		},
+		{
+			name:         "More than the batch limit",
+			orderIDs:     tooMany,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid order id",
+			orderIDs:     []string{orders[0].ID, "order-1"},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeOrderRepository{orders: map[string]*domain.Order{orders[0].ID: orders[0], orders[1].ID: orders[1]}}
+			server := grpc_server.NewOrderGRPCServer(repo)
+
+			res, err := server.GetOrders(context.Background(), &order_v1.GetOrdersRequest{OrderIds: tc.orderIDs})
+
+			if len(repo.batchCalls) != tc.expectedCalls {
+				t.Errorf("repository calls = %d, want %d", len(repo.batchCalls), tc.expectedCalls)
+			}
+			if tc.expectedCode != codes.OK {
+				if status.Code(err) != tc.expectedCode {
+					t.Errorf("error = %v, want %s", err, tc.expectedCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.expectedCalls > 0 && len(repo.batchCalls[0]) != len(uniqueIDs(tc.orderIDs)) {
+				t.Errorf("queried ids = %d, want %d unique ids", len(repo.batchCalls[0]), len(uniqueIDs(tc.orderIDs)))
+			}
+			if tc.expectedIDs == nil {
+				return
+			}
+			if got := protoOrderIDs(res.GetOrders()); strings.Join(got, ",") != strings.Join(tc.expectedIDs, ",") {
+				t.Errorf("orders = %v, want %v", got, tc.expectedIDs)
+			}
+			if strings.Join(res.GetMissingOrderIds(), ",") != strings.Join(tc.expectedMissing, ",") {
+				t.Errorf("missing = %v, want %v", res.GetMissingOrderIds(), tc.expectedMissing)
+			}
+		})
+	}
+}
+
+func TestServer_ListOrders_Paging(t *testing.T) {
+	type listFunc func(server *grpc_server.Server, limit int32, cursor string) (*order_v1.ListOrdersResponse, error)
+	byShop := func(server *grpc_server.Server, limit int32, cursor string) (*order_v1.ListOrdersResponse, error) {
+		return server.ListOrdersByShop(context.Background(), &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Limit: limit, Cursor: cursor})
+	}
+	byCustomer := func(server *grpc_server.Server, limit int32, cursor string) (*order_v1.ListOrdersResponse, error) {
+		return server.ListOrdersByCustomer(context.Background(), &order_v1.ListOrdersByCustomerRequest{CustomerId: testCustomerID, Limit: limit, Cursor: cursor})
+	}
+
+	for name, list := range map[string]listFunc{"ByShop": byShop, "ByCustomer": byCustomer} {
+		t.Run(name, func(t *testing.T) {
+			orders := newTestOrders(5)
+			repo := &fakeOrderRepository{list: orders}
+			server := grpc_server.NewOrderGRPCServer(repo)
+
+			var (
+				got    []string
+				cursor string
+				pages  int
+			)
+			for {
+				res, err := list(server, 2, cursor)
+				if err != nil {
+					t.Fatalf("page %d: unexpected error: %v", pages+1, err)
+				}
+				pages++
+				got = append(got, protoOrderIDs(res.GetOrders())...)
+				if res.GetNextCursor() == "" {
+					break
+				}
+				if pages > len(orders) {
+					t.Fatalf("paging did not terminate, ids = %v", got)
+				}
+				cursor = res.GetNextCursor()
+			}
+
+			if pages != 3 {
+				t.Errorf("pages = %d, want 3", pages)
+			}
+			if want := domainOrderIDs(orders); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("ids = %v, want %v", got, want)
+			}
+			for _, filter := range repo.filters {
+				if filter.Limit != 2 {
+					t.Errorf("limit = %d, want 2", filter.Limit)
+				}
+			}
+			if last := repo.filters[len(repo.filters)-1]; last.Cursor == nil || last.Cursor.ID != orders[3].ID || !last.Cursor.CreatedAt.Equal(testCreatedAt(3)) {
+				t.Errorf("last cursor = %+v, want after order %s", last.Cursor, orders[3].ID)
+			}
+		})
+	}
+}
+
+func TestServer_ListOrders_Filter(t *testing.T) {
+	validCursor := domain.OrderCursor{CreatedAt: testCreatedAt(0), ID: testOrderID}.Encode()
+
+	testCases := []struct {
+		name           string
+		req            *order_v1.ListOrdersByShopRequest
+		expectedLimit  int
+		expectedStatus domain.OrderStatus
+		expectedCursor bool
+		expectedCode   codes.Code
+	}{
+		{
+			name:          "Default page size",
+			req:           &order_v1.ListOrdersByShopRequest{ShopId: testShopID},
+			expectedLimit: 20,
+		},
+		{
+			name:          "Maximum page size",
+			req:           &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Limit: 100},
+			expectedLimit: 100,
+		},
+		{
+			name:           "Status and cursor",
+			req:            &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Status: order_v1.OrderStatus_ORDER_STATUS_DELIVERED, Cursor: validCursor},
+			expectedLimit:  20,
+			expectedStatus: domain.OrderStatusDELIVERED,
+			expectedCursor: true,
+		},
+		{
+			name:         "Page size above the limit",
+			req:          &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Limit: 101},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Negative page size",
+			req:          &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Limit: -1},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid cursor",
+			req:          &order_v1.ListOrdersByShopRequest{ShopId: testShopID, Cursor: "not-a-cursor"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid shop id",
+			req:          &order_v1.ListOrdersByShopRequest{ShopId: "shop-1"},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeOrderRepository{}
+			server := grpc_server.NewOrderGRPCServer(repo)
+
+			_, err := server.ListOrdersByShop(context.Background(), tc.req)
+
+			if tc.expectedCode != codes.OK {
+				if status.Code(err) != tc.expectedCode {
+					t.Errorf("error = %v, want %s", err, tc.expectedCode)
+				}
+				if len(repo.filters) != 0 {
+					t.Errorf("repository was queried with %+v", repo.filters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			filter := repo.filters[0]
+			if filter.ShopID != testShopID || filter.OwnerID != "" {
+				t.Errorf("filter = %+v, want shop %s only", filter, testShopID)
+			}
+			if filter.Limit != tc.expectedLimit {
+				t.Errorf("limit = %d, want %d", filter.Limit, tc.expectedLimit)
+			}
+			if tc.expectedStatus == "" && filter.Status != nil || tc.expectedStatus != "" && (filter.Status == nil || *filter.Status != tc.expectedStatus) {
+				t.Errorf("status = %v, want %q", filter.Status, tc.expectedStatus)
+			}
+			if (filter.Cursor != nil) != tc.expectedCursor {
+				t.Errorf("cursor = %+v, want set: %t", filter.Cursor, tc.expectedCursor)
+			}
+		})
+	}
+}
+
+func TestServer_ListOrdersByCustomer_InvalidCustomerID(t *testing.T) {
+	repo := &fakeOrderRepository{}
+	server := grpc_server.NewOrderGRPCServer(repo)
+
+	_, err := server.ListOrdersByCustomer(context.Background(), &order_v1.ListOrdersByCustomerRequest{CustomerId: "customer-1"})
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error = %v, want %s", err, codes.InvalidArgument)
+	}
+	if len(repo.filters) != 0 {
+		t.Errorf("repository was queried with %+v", repo.filters)
+	}
+}
+
+func uniqueIDs(ids []string) map[string]bool {
+	unique := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	return unique
+}
+
+func domainOrderIDs(orders []*domain.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	return ids
+}
+
+func protoOrderIDs(orders []*order_v1.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.GetId())
+	}
+	return ids
+}
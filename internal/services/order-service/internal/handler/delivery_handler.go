@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type DeliveryHandler interface {
+	GetAvailableDeliveries(c *gin.Context)
+	GetMyDeliveries(c *gin.Context)
+	ClaimOrder(c *gin.Context)
+	PickUpOrder(c *gin.Context)
+	DeliverOrder(c *gin.Context)
+	CollectCash(c *gin.Context)
+	GetMyCashBalance(c *gin.Context)
+	GetShipperCashBalance(c *gin.Context)
+	SettleShipperCash(c *gin.Context)
+}
+
+type deliveryHandler struct {
+	deliveryUsecase usecase.DeliveryUseCase
+}
+
+func NewDeliveryHandler(deliveryUsecase usecase.DeliveryUseCase) DeliveryHandler {
+	return &deliveryHandler{deliveryUsecase: deliveryUsecase}
+}
+
+func (h *deliveryHandler) GetAvailableDeliveries(c *gin.Context) {
+	var query dto.AvailableDeliveriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	shipperID, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	deliveries, err := h.deliveryUsecase.ListAvailableDeliveries(c.Request.Context(), shipperID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveriesResponse := make([]dto.AvailableDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveriesResponse[i] = dto.AvailableDeliveryResponse{
+			Order:         toAvailableOrderResponse(delivery.Order),
+			PickupAddress: toPickupAddressResponse(delivery.PickupAddress),
+			DistanceKm:    delivery.DistanceKm,
+		}
+	}
+
+	response.Success(c, "Available deliveries retrieved successfully", deliveriesResponse)
+}
+
+func (h *deliveryHandler) GetMyDeliveries(c *gin.Context) {
+	var query dto.ListDeliveriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	shipperID, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	deliveries, err := h.deliveryUsecase.ListMyDeliveries(c.Request.Context(), shipperID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	deliveriesResponse := make([]*dto.DeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveriesResponse[i] = toDeliveryResponse(delivery, nil)
+	}
+
+	response.Success(c, "Deliveries retrieved successfully", deliveriesResponse)
+}
+
+func (h *deliveryHandler) ClaimOrder(c *gin.Context) {
+	shipperID, orderID, ok := bindDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, order, err := h.deliveryUsecase.ClaimOrder(c.Request.Context(), shipperID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order claimed successfully", toDeliveryResponse(delivery, order))
+}
+
+func (h *deliveryHandler) PickUpOrder(c *gin.Context) {
+	shipperID, orderID, ok := bindDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, order, err := h.deliveryUsecase.PickUpOrder(c.Request.Context(), shipperID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order picked up successfully", toDeliveryResponse(delivery, order))
+}
+
+func (h *deliveryHandler) DeliverOrder(c *gin.Context) {
+	var request dto.DeliverOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	shipperID, orderID, ok := bindDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, order, err := h.deliveryUsecase.DeliverOrder(c.Request.Context(), shipperID, orderID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order delivered successfully", toDeliveryResponse(delivery, order))
+}
+
+func (h *deliveryHandler) CollectCash(c *gin.Context) {
+	shipperID, orderID, ok := bindDeliveryParams(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.deliveryUsecase.CollectCash(c.Request.Context(), shipperID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Cash collection recorded successfully", toCashCollectionResponse(collection))
+}
+
+func (h *deliveryHandler) GetMyCashBalance(c *gin.Context) {
+	shipperID, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	balance, err := h.deliveryUsecase.GetCashBalance(c.Request.Context(), shipperID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Cash balance retrieved successfully", toShipperCashBalanceResponse(balance))
+}
+
+func (h *deliveryHandler) GetShipperCashBalance(c *gin.Context) {
+	shipperID := c.Param("shipper_id")
+	if _, err := uuid.Parse(shipperID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shipper ID", err.Error())
+		return
+	}
+
+	balance, err := h.deliveryUsecase.GetCashBalance(c.Request.Context(), shipperID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Cash balance retrieved successfully", toShipperCashBalanceResponse(balance))
+}
+
+func (h *deliveryHandler) SettleShipperCash(c *gin.Context) {
+	var request dto.SettleShipperCashRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	adminID, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shipperID := c.Param("shipper_id")
+	if _, err := uuid.Parse(shipperID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shipper ID", err.Error())
+		return
+	}
+
+	settlement, err := h.deliveryUsecase.SettleCash(c.Request.Context(), adminID.(string), shipperID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shipper cash settled successfully", toCashSettlementResponse(settlement))
+}
+
+// bindDeliveryParams lấy shipper từ context và order_id từ path, tự ghi response lỗi nếu không hợp lệ.
+func bindDeliveryParams(c *gin.Context) (string, string, bool) {
+	shipperID, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return "", "", false
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return "", "", false
+	}
+
+	return shipperID.(string), orderID, true
+}
+
+func toDeliveryResponse(delivery *domain.OrderDelivery, order *domain.Order) *dto.DeliveryResponse {
+	if delivery == nil {
+		return nil
+	}
+
+	deliveryResponse := &dto.DeliveryResponse{
+		ID:             delivery.ID,
+		OrderID:        delivery.OrderID,
+		ShipperID:      delivery.ShipperID,
+		DeliveryStatus: string(delivery.DeliveryStatus),
+		ProofPhotoURL:  delivery.ProofPhotoURL,
+		ClaimedAt:      delivery.ClaimedAt.Format(time.RFC3339),
+		PickedUpAt:     formatTimePtr(delivery.PickedUpAt),
+		DeliveredAt:    formatTimePtr(delivery.DeliveredAt),
+		Order:          toOrderResponse(order),
+	}
+	if delivery.DeliveredPoint != nil {
+		deliveryResponse.DeliveredLat = &delivery.DeliveredPoint.Lat
+		deliveryResponse.DeliveredLong = &delivery.DeliveredPoint.Long
+	}
+	return deliveryResponse
+}
+
+func toCashCollectionResponse(collection *domain.CashCollection) dto.CashCollectionResponse {
+	return dto.CashCollectionResponse{
+		ID:               collection.ID,
+		OrderID:          collection.OrderID,
+		DeliveryID:       collection.DeliveryID,
+		ShipperID:        collection.ShipperID,
+		PaymentID:        collection.PaymentID,
+		Amount:           collection.Amount.Float64(),
+		Currency:         collection.Amount.Currency,
+		PaymentConfirmed: collection.IsPaymentConfirmed(),
+		CollectedAt:      collection.CollectedAt.Format(time.RFC3339),
+		SettlementID:     collection.SettlementID,
+		SettledAt:        formatTimePtr(collection.SettledAt),
+	}
+}
+
+func toShipperCashBalanceResponse(balance *domain.ShipperCashBalance) dto.ShipperCashBalanceResponse {
+	balanceResponse := dto.ShipperCashBalanceResponse{
+		ShipperID:   balance.ShipperID,
+		Totals:      make([]dto.CashTotalResponse, len(balance.Totals)),
+		Collections: make([]dto.CashCollectionResponse, len(balance.Collections)),
+	}
+	for i, total := range balance.Totals {
+		balanceResponse.Totals[i] = dto.CashTotalResponse{
+			Amount:   total.Float64(),
+			Currency: total.Currency,
+		}
+	}
+	for i, collection := range balance.Collections {
+		balanceResponse.Collections[i] = toCashCollectionResponse(collection)
+	}
+	return balanceResponse
+}
+
+func toCashSettlementResponse(settlement *domain.CashSettlement) dto.CashSettlementResponse {
+	return dto.CashSettlementResponse{
+		ID:              settlement.ID,
+		ShipperID:       settlement.ShipperID,
+		Amount:          settlement.Amount.Float64(),
+		Currency:        settlement.Amount.Currency,
+		CollectionCount: settlement.CollectionCount,
+		SettledBy:       settlement.SettledBy,
+		Note:            settlement.Note,
+		CreatedAt:       settlement.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toPickupAddressResponse(address *domain.PickupAddress) *dto.PickupAddressResponse {
+	if address == nil {
+		return nil
+	}
+
+	pickupResponse := &dto.PickupAddressResponse{
+		Street:   address.Street,
+		Ward:     address.Ward,
+		District: address.District,
+		City:     address.City,
+		Country:  address.Country,
+	}
+	if address.Location != nil {
+		pickupResponse.Lat = &address.Location.Lat
+		pickupResponse.Long = &address.Location.Long
+	}
+	return pickupResponse
+}
+
+// toAvailableOrderResponse chỉ trả về khu vực giao hàng, đơn chưa có shipper nhận nên không lộ thông tin người nhận.
+func toAvailableOrderResponse(order *domain.Order) *dto.AvailableOrderResponse {
+	if order == nil {
+		return nil
+	}
+
+	orderResponse := &dto.AvailableOrderResponse{
+		ID:          order.ID,
+		ShopID:      order.ShopID,
+		FinalAmount: order.FinalPrice.Float64(),
+		Currency:    order.Currency(),
+		CreatedAt:   order.CreatedAt,
+	}
+	if address := order.ShippingAddress; address != nil {
+		orderResponse.DeliveryArea = &dto.DeliveryAreaResponse{
+			Ward:     address.Ward,
+			District: address.District,
+			City:     address.City,
+			Country:  address.Country,
+		}
+	}
+	return orderResponse
+}
+
+func formatTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
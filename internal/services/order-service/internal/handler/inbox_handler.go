@@ -1,9 +1,17 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
 )
 
@@ -84,3 +92,187 @@ func (h *InboxHandler) CleanupOldEvents(c *gin.Context) {
 		"message": "Old events cleaned up successfully",
 	})
 }
+
+// ListInboxEvents - Admin liệt kê inbox event (mặc định mọi trạng thái), lọc theo status/event_type/from/to
+func (h *InboxHandler) ListInboxEvents(c *gin.Context) {
+	var query dto.ListInboxEventsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	page, err := h.inboxEventUsecase.ListInboxEvents(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	events := make([]dto.InboxEventResponse, len(page.Events))
+	for i, event := range page.Events {
+		events[i] = toInboxEventResponse(event)
+	}
+
+	meta := response.MetaInfo{
+		PerPage: len(events),
+	}
+	if page.NextCursor != nil {
+		meta.NextCursor = page.NextCursor.Encode()
+		meta.HasMore = true
+	}
+
+	response.SuccessWithMeta(c, "Inbox events retrieved successfully", events, &meta)
+}
+
+// GetInboxEvent - Admin xem chi tiết một inbox event gồm payload gốc, lỗi gần nhất và nhật ký thao tác
+func (h *InboxHandler) GetInboxEvent(c *gin.Context) {
+	eventID, ok := bindInboxEventID(c)
+	if !ok {
+		return
+	}
+
+	event, audits, err := h.inboxEventUsecase.GetInboxEvent(c.Request.Context(), eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	detail := dto.InboxEventDetailResponse{
+		InboxEventResponse: toInboxEventResponse(event),
+		Payload:            json.RawMessage(event.Payload),
+		Audits:             make([]dto.InboxEventAuditResponse, len(audits)),
+	}
+	if !json.Valid(detail.Payload) {
+		// Payload không phải JSON hợp lệ (event hỏng từ phía gửi), trả về dạng chuỗi để admin vẫn xem được
+		detail.Payload, _ = json.Marshal(event.Payload)
+	}
+	for i, audit := range audits {
+		detail.Audits[i] = dto.InboxEventAuditResponse{
+			Action:    audit.Action,
+			OldStatus: string(audit.OldStatus),
+			NewStatus: string(audit.NewStatus),
+			Actor:     audit.Actor,
+			Reason:    audit.Reason,
+			TraceID:   audit.TraceID,
+			CreatedAt: audit.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	response.Success(c, "Inbox event retrieved successfully", detail)
+}
+
+// ReplayInboxEvent - Admin đưa một event FAILED về PENDING để worker xử lý lại
+func (h *InboxHandler) ReplayInboxEvent(c *gin.Context) {
+	var request dto.InboxEventActionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	adminID, ok := bindAdminID(c)
+	if !ok {
+		return
+	}
+	eventID, ok := bindInboxEventID(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.inboxEventUsecase.ReplayInboxEvent(c.Request.Context(), adminID, eventID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Inbox event queued for replay", toInboxEventResponse(event))
+}
+
+// ReplayFailedInboxEvents - Admin replay hàng loạt các event FAILED theo bộ lọc
+func (h *InboxHandler) ReplayFailedInboxEvents(c *gin.Context) {
+	var request dto.ReplayInboxEventsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	adminID, ok := bindAdminID(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.inboxEventUsecase.ReplayFailedInboxEvents(c.Request.Context(), adminID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	replayResponse := dto.ReplayInboxEventsResponse{
+		ReplayedCount: len(events),
+		Events:        make([]dto.InboxEventResponse, len(events)),
+	}
+	for i, event := range events {
+		replayResponse.Events[i] = toInboxEventResponse(event)
+	}
+
+	response.Success(c, "Failed inbox events queued for replay", replayResponse)
+}
+
+// DiscardInboxEvent - Admin huỷ bỏ một event FAILED, event không bao giờ được xử lý lại
+func (h *InboxHandler) DiscardInboxEvent(c *gin.Context) {
+	var request dto.InboxEventActionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	adminID, ok := bindAdminID(c)
+	if !ok {
+		return
+	}
+	eventID, ok := bindInboxEventID(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.inboxEventUsecase.DiscardInboxEvent(c.Request.Context(), adminID, eventID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Inbox event discarded successfully", toInboxEventResponse(event))
+}
+
+func bindAdminID(c *gin.Context) (string, bool) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return "", false
+	}
+	return userId.(string), true
+}
+
+func bindInboxEventID(c *gin.Context) (string, bool) {
+	eventID := c.Param("event_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid inbox event ID", err.Error())
+		return "", false
+	}
+	return eventID, true
+}
+
+func toInboxEventResponse(event *domain.InboxEvent) dto.InboxEventResponse {
+	return dto.InboxEventResponse{
+		ID:            event.ID,
+		EventID:       event.EventID,
+		EventType:     event.EventType,
+		EventVersion:  event.EventVersion,
+		SourceService: event.SourceService,
+		Status:        string(event.EventStatus),
+		RetryCount:    event.RetryCount,
+		MaxRetry:      event.MaxRetry,
+		LastError:     event.LastError,
+		ReceivedAt:    event.ReceivedAt.Format(time.RFC3339),
+		ProcessedAt:   formatTimePtr(event.ProcessedAt),
+		UpdatedAt:     event.UpdatedAt.Format(time.RFC3339),
+	}
+}
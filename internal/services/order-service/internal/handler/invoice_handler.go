@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/invoice"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type InvoiceHandler interface {
+	GetOrderInvoice(c *gin.Context)
+}
+
+type invoiceHandler struct {
+	invoiceUsecase usecase.InvoiceUseCase
+}
+
+func NewInvoiceHandler(invoiceUsecase usecase.InvoiceUseCase) InvoiceHandler {
+	return &invoiceHandler{invoiceUsecase: invoiceUsecase}
+}
+
+// GetOrderInvoice trả về hoá đơn của đơn hàng dạng PDF (mặc định) hoặc HTML qua ?format=html.
+func (h *invoiceHandler) GetOrderInvoice(c *gin.Context) {
+	var query dto.InvoiceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, orderID, ok := bindOrderReturnParams(c)
+	if !ok {
+		return
+	}
+
+	inv, err := h.invoiceUsecase.GetOrderInvoice(c.Request.Context(), userId, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	contentType, extension := "application/pdf", "pdf"
+	render := invoice.RenderPDF
+	if query.Format == "html" {
+		contentType, extension = "text/html; charset=utf-8", "html"
+		render = invoice.RenderHTML
+	}
+
+	body, err := render(inv)
+	if err != nil {
+		c.Error(apperror.NewInternal(fmt.Sprintf("Failed to render invoice: %s", err.Error())))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", inv.InvoiceNumber+"."+extension))
+	c.Data(http.StatusOK, contentType, body)
+}
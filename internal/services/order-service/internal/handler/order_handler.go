@@ -1,11 +1,10 @@
 package handler
 
 import (
-	"fmt"
-
 	"github.com/toji-dev/go-shop/internal/pkg/constant"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/toji-dev/go-shop/internal/pkg/apperror"
 	"github.com/toji-dev/go-shop/internal/pkg/response"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
@@ -16,7 +15,19 @@ import (
 type OrderHandler interface {
 	GetOrdersByOwnerID(c *gin.Context)
 	CreateOrder(c *gin.Context)
+	Checkout(c *gin.Context)
+	Reorder(c *gin.Context)
 	GetOrderByID(c *gin.Context)
+	CancelOrder(c *gin.Context)
+	GetOrderTimeline(c *gin.Context)
+	GetShopOrders(c *gin.Context)
+	AcceptShopOrder(c *gin.Context)
+	RejectShopOrder(c *gin.Context)
+	ShipShopOrder(c *gin.Context)
+	PackShopOrderItem(c *gin.Context)
+	ShipShopOrderItem(c *gin.Context)
+	CancelShopOrderItem(c *gin.Context)
+	ExportShopOrders(c *gin.Context)
 }
 
 type orderHandler struct {
@@ -28,6 +39,38 @@ func NewOrderHandler(orderUsecase usecase.OrderUsecase) OrderHandler {
 }
 
 func (h *orderHandler) GetOrdersByOwnerID(c *gin.Context) {
+	var query dto.ListOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	page, err := h.orderUsecase.ListOrdersByOwner(c.Request.Context(), userId.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	orders := make([]*dto.OrderResponse, len(page.Orders))
+	for i, order := range page.Orders {
+		orders[i] = toOrderResponse(order)
+	}
+
+	meta := response.MetaInfo{
+		PerPage: len(orders),
+	}
+	if page.NextCursor != nil {
+		meta.NextCursor = page.NextCursor.Encode()
+		meta.HasMore = true
+	}
+
+	response.SuccessWithMeta(c, "Orders retrieved successfully", orders, &meta)
 }
 
 func (h *orderHandler) CreateOrder(c *gin.Context) {
@@ -45,7 +88,7 @@ func (h *orderHandler) CreateOrder(c *gin.Context) {
 
 	order, err := h.orderUsecase.CreateOrder(c.Request.Context(), userId.(string), request)
 	if err != nil {
-		response.InternalServerError(c, string(apperror.CodeInternal), fmt.Sprintf("Failed to create order: %s", err.Error()))
+		c.Error(err)
 		return
 	}
 
@@ -54,8 +97,181 @@ func (h *orderHandler) CreateOrder(c *gin.Context) {
 	response.Created(c, "Order created successfully", orderResponse)
 }
 
+func (h *orderHandler) Checkout(c *gin.Context) {
+	var request dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	result, err := h.orderUsecase.Checkout(c.Request.Context(), userId.(string), request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	checkoutResponse := dto.CheckoutResponse{
+		CheckoutID: result.CheckoutID,
+		Orders:     make([]*dto.OrderResponse, 0, len(result.Orders)),
+	}
+	for _, order := range result.Orders {
+		checkoutResponse.Orders = append(checkoutResponse.Orders, toOrderResponse(order))
+	}
+	for _, failure := range result.Failures {
+		checkoutResponse.FailedShops = append(checkoutResponse.FailedShops, dto.CheckoutFailureResponse{
+			ShopID: failure.ShopID,
+			Reason: failure.Reason,
+		})
+	}
+
+	response.Created(c, "Checkout completed successfully", checkoutResponse)
+}
+
+// Reorder thêm lại các sản phẩm còn bán của một đơn cũ vào giỏ hàng.
+func (h *orderHandler) Reorder(c *gin.Context) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return
+	}
+
+	result, err := h.orderUsecase.Reorder(c.Request.Context(), userId.(string), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	reorderResponse := dto.ReorderResponse{
+		OrderID:      result.OrderID,
+		Added:        make([]dto.ReorderItemResponse, 0, len(result.Added)),
+		PriceChanged: []dto.ReorderItemResponse{},
+		Unavailable:  make([]dto.ReorderItemResponse, 0, len(result.Unavailable)),
+	}
+	for _, item := range result.Added {
+		reorderResponse.Added = append(reorderResponse.Added, toReorderItemResponse(item))
+		if item.PriceChanged() {
+			reorderResponse.PriceChanged = append(reorderResponse.PriceChanged, toReorderItemResponse(item))
+		}
+	}
+	for _, item := range result.Unavailable {
+		reorderResponse.Unavailable = append(reorderResponse.Unavailable, toReorderItemResponse(item))
+	}
+
+	response.Success(c, "Order items added to cart successfully", reorderResponse)
+}
+
 func (h *orderHandler) GetOrderByID(c *gin.Context) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return
+	}
+
+	order, err := h.orderUsecase.GetOrderByID(c.Request.Context(), userId.(string), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order retrieved successfully", toOrderResponse(order))
+}
+
+func (h *orderHandler) CancelOrder(c *gin.Context) {
+	var request dto.CancelOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return
+	}
+
+	order, cancellation, err := h.orderUsecase.CancelOrder(c.Request.Context(), userId.(string), orderID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
+	cancelResponse := dto.CancelOrderResponse{
+		Order: toOrderResponse(order),
+	}
+	if cancellation != nil {
+		cancelResponse.Reason = cancellation.Reason
+		cancelResponse.PreviousStatus = string(cancellation.PreviousStatus)
+		cancelResponse.RefundRequested = cancellation.RefundRequested
+		cancelResponse.RefundID = cancellation.RefundID
+	}
+
+	response.Success(c, "Order canceled successfully", cancelResponse)
+}
+
+func (h *orderHandler) GetOrderTimeline(c *gin.Context) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return
+	}
+
+	order, history, err := h.orderUsecase.GetOrderTimeline(c.Request.Context(), userId.(string), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	timelineResponse := dto.OrderTimelineResponse{
+		OrderID:       order.ID,
+		CurrentStatus: string(order.Status),
+		Entries:       make([]dto.OrderTimelineEntryResponse, 0, len(history)),
+	}
+	for _, entry := range history {
+		entryResponse := dto.OrderTimelineEntryResponse{
+			NewStatus: string(entry.NewStatus),
+			Actor:     entry.Actor,
+			Reason:    entry.Reason,
+			TraceID:   entry.TraceID,
+			CreatedAt: entry.CreatedAt,
+		}
+		if entry.OldStatus != nil {
+			oldStatus := string(*entry.OldStatus)
+			entryResponse.OldStatus = &oldStatus
+		}
+		timelineResponse.Entries = append(timelineResponse.Entries, entryResponse)
+	}
+
+	response.Success(c, "Order timeline retrieved successfully", timelineResponse)
 }
 
 func toOrderResponse(order *domain.Order) *dto.OrderResponse {
@@ -72,11 +288,14 @@ func toOrderResponse(order *domain.Order) *dto.OrderResponse {
 		ID:                order.ID,
 		ShopID:            order.ShopID,
 		ShippingAddressID: order.ShippingAddressID,
+		ShippingAddress:   toShippingAddressResponse(order.ShippingAddress),
 		PromotionID:       order.PromotionCode,
-		ShippingFee:       order.ShippingFee,
-		DiscountAmount:    order.DiscountAmount,
-		TotalAmount:       order.TotalAmount,
-		FinalAmount:       order.FinalPrice,
+		CheckoutID:        order.CheckoutID,
+		ShippingFee:       order.ShippingFee.Float64(),
+		DiscountAmount:    order.DiscountAmount.Float64(),
+		TotalAmount:       order.TotalAmount.Float64(),
+		FinalAmount:       order.FinalPrice.Float64(),
+		Currency:          order.Currency(),
 		Status:            string(order.Status),
 		CreatedAt:         order.CreatedAt,
 		UpdatedAt:         order.UpdatedAt,
@@ -86,9 +305,46 @@ func toOrderResponse(order *domain.Order) *dto.OrderResponse {
 
 func toOrderItemResponse(item *domain.OrderItem) dto.OrderItemResponse {
 	return dto.OrderItemResponse{
-		ID:        item.ID,
-		ProductID: item.ProductID,
-		Quantity:  item.Quantity,
-		Price:     item.Price,
+		ID:                item.ID,
+		ProductID:         item.ProductID,
+		ProductName:       item.ProductName,
+		ThumbnailURL:      item.ThumbnailURL,
+		Quantity:          item.Quantity,
+		Price:             item.Price.Float64(),
+		Currency:          item.Price.Currency,
+		Status:            string(item.Status),
+		CanceledQuantity:  item.CanceledQuantity,
+		RemainingQuantity: item.RemainingQuantity(),
+	}
+}
+
+func toReorderItemResponse(item domain.ReorderItem) dto.ReorderItemResponse {
+	return dto.ReorderItemResponse{
+		ProductID:         item.ProductID,
+		ProductName:       item.ProductName,
+		RequestedQuantity: item.RequestedQuantity,
+		Quantity:          item.Quantity,
+		PreviousPrice:     item.PreviousPrice.Float64(),
+		CurrentPrice:      item.CurrentPrice.Float64(),
+		Currency:          item.CurrentPrice.Currency,
+		Reason:            item.UnavailableReason,
+	}
+}
+
+func toShippingAddressResponse(address *domain.ShippingAddress) *dto.ShippingAddressResponse {
+	if address == nil {
+		return nil
+	}
+
+	return &dto.ShippingAddressResponse{
+		RecipientName:  address.RecipientName,
+		RecipientPhone: address.RecipientPhone,
+		Street:         address.Street,
+		Ward:           address.Ward,
+		District:       address.District,
+		City:           address.City,
+		Country:        address.Country,
+		Lat:            address.Lat,
+		Long:           address.Long,
 	}
 }
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type OutboxHandler interface {
+	RetryOutboxEvent(c *gin.Context)
+}
+
+type outboxHandler struct {
+	orderEventUsecase usecase.OrderEventUseCase
+}
+
+func NewOutboxHandler(orderEventUsecase usecase.OrderEventUseCase) OutboxHandler {
+	return &outboxHandler{orderEventUsecase: orderEventUsecase}
+}
+
+// RetryOutboxEvent - Admin đưa một outbox event FAILED về PENDING, mở lại luồng event của đơn hàng
+func (h *outboxHandler) RetryOutboxEvent(c *gin.Context) {
+	adminID, ok := bindAdminID(c)
+	if !ok {
+		return
+	}
+
+	eventID := c.Param("event_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid outbox event ID", err.Error())
+		return
+	}
+
+	event, err := h.orderEventUsecase.RetryFailedOrderEvent(c.Request.Context(), adminID, eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Outbox event queued for retry", toOutboxEventResponse(event))
+}
+
+func toOutboxEventResponse(event *domain.OutboxEvent) dto.OutboxEventResponse {
+	return dto.OutboxEventResponse{
+		ID:          event.ID,
+		OrderID:     event.OrderID,
+		EventType:   event.EventType,
+		Status:      string(event.EventStatus),
+		RetryCount:  event.RetryCount,
+		PublishedAt: formatTimePtr(event.PublishedAt),
+		CreatedAt:   event.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   event.UpdatedAt.Format(time.RFC3339),
+	}
+}
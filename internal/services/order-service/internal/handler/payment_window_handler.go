@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type PaymentWindowHandler interface {
+	GetShopPaymentWindow(c *gin.Context)
+	UpdateShopPaymentWindow(c *gin.Context)
+}
+
+type paymentWindowHandler struct {
+	paymentWindowUsecase usecase.PaymentWindowUseCase
+}
+
+func NewPaymentWindowHandler(paymentWindowUsecase usecase.PaymentWindowUseCase) PaymentWindowHandler {
+	return &paymentWindowHandler{paymentWindowUsecase: paymentWindowUsecase}
+}
+
+func (h *paymentWindowHandler) GetShopPaymentWindow(c *gin.Context) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	window, err := h.paymentWindowUsecase.GetShopPaymentWindow(c.Request.Context(), userId.(string), shopID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Payment window retrieved successfully", toPaymentWindowResponse(window))
+}
+
+func (h *paymentWindowHandler) UpdateShopPaymentWindow(c *gin.Context) {
+	var request dto.UpdatePaymentWindowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	window, err := h.paymentWindowUsecase.UpdateShopPaymentWindow(c.Request.Context(), userId.(string), shopID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Payment window updated successfully", toPaymentWindowResponse(window))
+}
+
+func toPaymentWindowResponse(window *domain.ShopPaymentWindow) dto.PaymentWindowResponse {
+	return dto.PaymentWindowResponse{
+		ShopID:        window.ShopID,
+		IsDefault:     window.IsDefault,
+		WindowMinutes: int(window.Window.Minutes()),
+	}
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type ReturnHandler interface {
+	// Khách hàng
+	CreateReturn(c *gin.Context)
+	GetOrderReturns(c *gin.Context)
+	GetOrderReturn(c *gin.Context)
+
+	// Người bán
+	GetShopReturns(c *gin.Context)
+	ApproveReturn(c *gin.Context)
+	RejectReturn(c *gin.Context)
+	ReceiveReturn(c *gin.Context)
+}
+
+type returnHandler struct {
+	returnUsecase usecase.ReturnUseCase
+}
+
+func NewReturnHandler(returnUsecase usecase.ReturnUseCase) ReturnHandler {
+	return &returnHandler{returnUsecase: returnUsecase}
+}
+
+func (h *returnHandler) CreateReturn(c *gin.Context) {
+	var request dto.CreateReturnRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, orderID, ok := bindOrderReturnParams(c)
+	if !ok {
+		return
+	}
+
+	orderReturn, err := h.returnUsecase.RequestReturn(c.Request.Context(), userId, orderID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Created(c, "Return requested successfully", toReturnResponse(orderReturn))
+}
+
+func (h *returnHandler) GetOrderReturns(c *gin.Context) {
+	userId, orderID, ok := bindOrderReturnParams(c)
+	if !ok {
+		return
+	}
+
+	returns, err := h.returnUsecase.ListOrderReturns(c.Request.Context(), userId, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Returns retrieved successfully", toReturnResponses(returns))
+}
+
+func (h *returnHandler) GetOrderReturn(c *gin.Context) {
+	userId, orderID, ok := bindOrderReturnParams(c)
+	if !ok {
+		return
+	}
+
+	returnID, ok := bindReturnID(c)
+	if !ok {
+		return
+	}
+
+	orderReturn, err := h.returnUsecase.GetOrderReturn(c.Request.Context(), userId, orderID, returnID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Return retrieved successfully", toReturnResponse(orderReturn))
+}
+
+func (h *returnHandler) GetShopReturns(c *gin.Context) {
+	var query dto.ListReturnsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	returns, err := h.returnUsecase.ListShopReturns(c.Request.Context(), userId.(string), shopID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shop returns retrieved successfully", toReturnResponses(returns))
+}
+
+func (h *returnHandler) ApproveReturn(c *gin.Context) {
+	userId, shopID, returnID, ok := bindShopReturnParams(c)
+	if !ok {
+		return
+	}
+
+	orderReturn, err := h.returnUsecase.ApproveReturn(c.Request.Context(), userId, shopID, returnID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Return approved successfully", toReturnResponse(orderReturn))
+}
+
+func (h *returnHandler) RejectReturn(c *gin.Context) {
+	var request dto.RejectReturnRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, shopID, returnID, ok := bindShopReturnParams(c)
+	if !ok {
+		return
+	}
+
+	orderReturn, err := h.returnUsecase.RejectReturn(c.Request.Context(), userId, shopID, returnID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Return rejected successfully", toReturnResponse(orderReturn))
+}
+
+func (h *returnHandler) ReceiveReturn(c *gin.Context) {
+	userId, shopID, returnID, ok := bindShopReturnParams(c)
+	if !ok {
+		return
+	}
+
+	orderReturn, err := h.returnUsecase.ReceiveReturn(c.Request.Context(), userId, shopID, returnID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Return received successfully", toReturnResponse(orderReturn))
+}
+
+// bindOrderReturnParams lấy user từ context và order_id từ path, tự ghi response lỗi nếu không hợp lệ.
+func bindOrderReturnParams(c *gin.Context) (string, string, bool) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return "", "", false
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return "", "", false
+	}
+
+	return userId.(string), orderID, true
+}
+
+// bindShopReturnParams lấy user từ context, shop_id và return_id từ path.
+func bindShopReturnParams(c *gin.Context) (string, string, string, bool) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return "", "", "", false
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return "", "", "", false
+	}
+
+	returnID, ok := bindReturnID(c)
+	if !ok {
+		return "", "", "", false
+	}
+
+	return userId.(string), shopID, returnID, true
+}
+
+func bindReturnID(c *gin.Context) (string, bool) {
+	returnID := c.Param("return_id")
+	if _, err := uuid.Parse(returnID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid return ID", err.Error())
+		return "", false
+	}
+	return returnID, true
+}
+
+func toReturnResponses(returns []*domain.OrderReturn) []*dto.ReturnResponse {
+	returnsResponse := make([]*dto.ReturnResponse, len(returns))
+	for i, orderReturn := range returns {
+		returnsResponse[i] = toReturnResponse(orderReturn)
+	}
+	return returnsResponse
+}
+
+func toReturnResponse(orderReturn *domain.OrderReturn) *dto.ReturnResponse {
+	if orderReturn == nil {
+		return nil
+	}
+
+	items := make([]dto.ReturnItemResponse, len(orderReturn.Items))
+	for i, item := range orderReturn.Items {
+		items[i] = dto.ReturnItemResponse{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice.Float64(),
+		}
+	}
+
+	return &dto.ReturnResponse{
+		ID:           orderReturn.ID,
+		OrderID:      orderReturn.OrderID,
+		ShopID:       orderReturn.ShopID,
+		Status:       string(orderReturn.Status),
+		Reason:       orderReturn.Reason,
+		SellerNote:   orderReturn.SellerNote,
+		RefundAmount: orderReturn.RefundAmount.Float64(),
+		Currency:     orderReturn.RefundAmount.Currency,
+		RefundID:     orderReturn.RefundID,
+		Restocked:    orderReturn.Restocked,
+		Items:        items,
+		ApprovedAt:   formatTimePtr(orderReturn.ApprovedAt),
+		RejectedAt:   formatTimePtr(orderReturn.RejectedAt),
+		ReceivedAt:   formatTimePtr(orderReturn.ReceivedAt),
+		RefundedAt:   formatTimePtr(orderReturn.RefundedAt),
+		CreatedAt:    orderReturn.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    orderReturn.UpdatedAt.Format(time.RFC3339),
+	}
+}
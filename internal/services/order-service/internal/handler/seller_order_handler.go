@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/export"
+)
+
+func (h *orderHandler) GetShopOrders(c *gin.Context) {
+	var query dto.ListOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	page, err := h.orderUsecase.ListShopOrders(c.Request.Context(), userId.(string), shopID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	orders := make([]*dto.OrderResponse, len(page.Orders))
+	for i, order := range page.Orders {
+		orders[i] = toOrderResponse(order)
+	}
+
+	meta := response.MetaInfo{
+		PerPage: len(orders),
+	}
+	if page.NextCursor != nil {
+		meta.NextCursor = page.NextCursor.Encode()
+		meta.HasMore = true
+	}
+
+	response.SuccessWithMeta(c, "Shop orders retrieved successfully", orders, &meta)
+}
+
+func (h *orderHandler) AcceptShopOrder(c *gin.Context) {
+	userId, shopID, orderID, ok := bindShopOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUsecase.AcceptShopOrder(c.Request.Context(), userId, shopID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order accepted successfully", toOrderResponse(order))
+}
+
+func (h *orderHandler) RejectShopOrder(c *gin.Context) {
+	var request dto.RejectOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, shopID, orderID, ok := bindShopOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, cancellation, err := h.orderUsecase.RejectShopOrder(c.Request.Context(), userId, shopID, orderID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	rejectResponse := dto.CancelOrderResponse{
+		Order: toOrderResponse(order),
+	}
+	if cancellation != nil {
+		rejectResponse.Reason = cancellation.Reason
+		rejectResponse.PreviousStatus = string(cancellation.PreviousStatus)
+		rejectResponse.RefundRequested = cancellation.RefundRequested
+		rejectResponse.RefundID = cancellation.RefundID
+	}
+
+	response.Success(c, "Order rejected successfully", rejectResponse)
+}
+
+func (h *orderHandler) ShipShopOrder(c *gin.Context) {
+	userId, shopID, orderID, ok := bindShopOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUsecase.ShipShopOrder(c.Request.Context(), userId, shopID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order marked as shipped successfully", toOrderResponse(order))
+}
+
+func (h *orderHandler) PackShopOrderItem(c *gin.Context) {
+	userId, shopID, orderID, itemID, ok := bindShopOrderItemParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUsecase.PackShopOrderItem(c.Request.Context(), userId, shopID, orderID, itemID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order item packed successfully", toOrderResponse(order))
+}
+
+func (h *orderHandler) ShipShopOrderItem(c *gin.Context) {
+	userId, shopID, orderID, itemID, ok := bindShopOrderItemParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUsecase.ShipShopOrderItem(c.Request.Context(), userId, shopID, orderID, itemID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order item marked as shipped successfully", toOrderResponse(order))
+}
+
+func (h *orderHandler) CancelShopOrderItem(c *gin.Context) {
+	var request dto.CancelOrderItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, shopID, orderID, itemID, ok := bindShopOrderItemParams(c)
+	if !ok {
+		return
+	}
+
+	order, cancellation, err := h.orderUsecase.CancelShopOrderItem(c.Request.Context(), userId, shopID, orderID, itemID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Order item canceled successfully", dto.CancelOrderItemResponse{
+		Order:        toOrderResponse(order),
+		ID:           cancellation.ID,
+		OrderItemID:  cancellation.OrderItemID,
+		ProductID:    cancellation.ProductID,
+		Quantity:     cancellation.Quantity,
+		RefundAmount: cancellation.RefundAmount.Float64(),
+		Currency:     cancellation.RefundAmount.Currency,
+		Reason:       cancellation.Reason,
+		Restocked:    cancellation.Restocked,
+		RefundID:     cancellation.RefundID,
+		CreatedAt:    cancellation.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// ExportShopOrders xuất đơn hàng của shop trong khoảng from-to ra CSV (mặc định) hoặc XLSX qua ?format=xlsx,
+// mỗi dòng là một sản phẩm. File được ghi dần theo từng trang đơn hàng nên lỗi xảy ra giữa chừng chỉ được ghi log.
+func (h *orderHandler) ExportShopOrders(c *gin.Context) {
+	var query dto.ExportOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	orderExport, err := h.orderUsecase.ExportShopOrders(c.Request.Context(), userId.(string), shopID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	format := query.Format
+	if format == "" {
+		format = export.FormatCSV
+	}
+	filename := fmt.Sprintf("orders-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(format, c.Writer)
+	if err == nil {
+		err = export.WriteOrderHeader(writer)
+	}
+	if err == nil {
+		err = orderExport.ForEach(c.Request.Context(), func(order *domain.Order) error {
+			return export.WriteOrder(writer, order)
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Printf("Failed to export orders of shop %s: %v", shopID, err)
+		c.Abort()
+	}
+}
+
+// bindShopOrderParams đọc user hiện tại cùng shop_id, order_id trên path; tự ghi response lỗi khi không hợp lệ.
+func bindShopOrderParams(c *gin.Context) (string, string, string, bool) {
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return "", "", "", false
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return "", "", "", false
+	}
+
+	orderID := c.Param("order_id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order ID", err.Error())
+		return "", "", "", false
+	}
+
+	return userId.(string), shopID, orderID, true
+}
+
+// bindShopOrderItemParams giống bindShopOrderParams, đọc thêm item_id trên path.
+func bindShopOrderItemParams(c *gin.Context) (string, string, string, string, bool) {
+	userId, shopID, orderID, ok := bindShopOrderParams(c)
+	if !ok {
+		return "", "", "", "", false
+	}
+
+	itemID := c.Param("item_id")
+	if _, err := uuid.Parse(itemID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid order item ID", err.Error())
+		return "", "", "", "", false
+	}
+
+	return userId, shopID, orderID, itemID, true
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type ShippingHandler interface {
+	QuoteShippingFee(c *gin.Context)
+	GetShopShippingRates(c *gin.Context)
+	UpdateShopShippingRates(c *gin.Context)
+}
+
+type shippingHandler struct {
+	shippingUsecase usecase.ShippingUseCase
+}
+
+func NewShippingHandler(shippingUsecase usecase.ShippingUseCase) ShippingHandler {
+	return &shippingHandler{shippingUsecase: shippingUsecase}
+}
+
+func (h *shippingHandler) QuoteShippingFee(c *gin.Context) {
+	var request dto.ShippingQuoteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	quote, err := h.shippingUsecase.QuoteShippingFee(c.Request.Context(), userId.(string), request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shipping fee calculated successfully", dto.ShippingQuoteResponse{
+		ShopID:           quote.ShopID,
+		Currency:         quote.Currency,
+		DistanceKm:       quote.DistanceKm,
+		TotalWeightGrams: quote.TotalWeightGrams,
+		ZoneFee:          quote.ZoneFee,
+		WeightSurcharge:  quote.WeightSurcharge,
+		ShippingFee:      quote.ShippingFee,
+	})
+}
+
+func (h *shippingHandler) GetShopShippingRates(c *gin.Context) {
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	rates, err := h.shippingUsecase.GetShopShippingRates(c.Request.Context(), shopID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shipping rates retrieved successfully", toShippingRatesResponse(rates))
+}
+
+func (h *shippingHandler) UpdateShopShippingRates(c *gin.Context) {
+	var request dto.UpdateShippingRatesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid request body", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	rates, err := h.shippingUsecase.UpdateShopShippingRates(c.Request.Context(), userId.(string), shopID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shipping rates updated successfully", toShippingRatesResponse(rates))
+}
+
+func toShippingRatesResponse(rates *domain.ShippingRateTable) dto.ShippingRatesResponse {
+	ratesResponse := dto.ShippingRatesResponse{
+		ShopID:      rates.ShopID,
+		IsDefault:   rates.IsDefault,
+		Currency:    rates.Currency,
+		Zones:       make([]dto.ShippingZoneTier, 0, len(rates.Zones)),
+		WeightTiers: make([]dto.ShippingWeightTier, 0, len(rates.WeightTiers)),
+	}
+	for _, zone := range rates.Zones {
+		ratesResponse.Zones = append(ratesResponse.Zones, dto.ShippingZoneTier{
+			MaxDistanceKm: zone.MaxDistanceKm,
+			BaseFee:       zone.BaseFee,
+		})
+	}
+	for _, tier := range rates.WeightTiers {
+		ratesResponse.WeightTiers = append(ratesResponse.WeightTiers, dto.ShippingWeightTier{
+			MaxWeightGrams: tier.MaxWeightGrams,
+			Surcharge:      tier.Surcharge,
+		})
+	}
+	return ratesResponse
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/pkg/response"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+type ShopAnalyticsHandler interface {
+	GetShopAnalytics(c *gin.Context)
+}
+
+type shopAnalyticsHandler struct {
+	shopAnalyticsUsecase usecase.ShopAnalyticsUseCase
+}
+
+func NewShopAnalyticsHandler(shopAnalyticsUsecase usecase.ShopAnalyticsUseCase) ShopAnalyticsHandler {
+	return &shopAnalyticsHandler{shopAnalyticsUsecase: shopAnalyticsUsecase}
+}
+
+func (h *shopAnalyticsHandler) GetShopAnalytics(c *gin.Context) {
+	var query dto.ShopAnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid query parameters", err.Error())
+		return
+	}
+
+	userId, exists := c.Get(constant.ContextKeyUserID)
+	if !exists {
+		response.Unauthorized(c, string(apperror.CodeUnauthorized), "User not authenticated")
+		return
+	}
+
+	shopID := c.Param("shop_id")
+	if _, err := uuid.Parse(shopID); err != nil {
+		response.BadRequest(c, string(apperror.CodeBadRequest), "Invalid shop ID", err.Error())
+		return
+	}
+
+	analytics, err := h.shopAnalyticsUsecase.GetShopAnalytics(c.Request.Context(), userId.(string), shopID, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, "Shop analytics retrieved successfully", toShopAnalyticsResponse(analytics))
+}
+
+func toShopAnalyticsResponse(analytics *domain.ShopAnalytics) dto.ShopAnalyticsResponse {
+	analyticsResponse := dto.ShopAnalyticsResponse{
+		ShopID:      analytics.ShopID,
+		From:        analytics.From.Format("2006-01-02"),
+		To:          analytics.To.Format("2006-01-02"),
+		Granularity: string(analytics.Granularity),
+		Series:      make([]dto.ShopSalesBucketResponse, len(analytics.Series)),
+		Totals:      make([]dto.ShopSalesBucketResponse, len(analytics.Totals)),
+		TopProducts: make([]dto.ShopTopProductResponse, len(analytics.TopProducts)),
+	}
+	for i, bucket := range analytics.Series {
+		analyticsResponse.Series[i] = toShopSalesBucketResponse(bucket)
+		analyticsResponse.Series[i].Start = bucket.Start.Format("2006-01-02")
+	}
+	for i, bucket := range analytics.Totals {
+		analyticsResponse.Totals[i] = toShopSalesBucketResponse(bucket)
+	}
+	for i, product := range analytics.TopProducts {
+		analyticsResponse.TopProducts[i] = dto.ShopTopProductResponse{
+			ProductID:   product.ProductID,
+			ProductName: product.ProductName,
+			Currency:    product.Currency,
+			Quantity:    product.Quantity,
+			Revenue:     product.Revenue.Float64(),
+		}
+	}
+	return analyticsResponse
+}
+
+func toShopSalesBucketResponse(bucket domain.ShopSalesBucket) dto.ShopSalesBucketResponse {
+	return dto.ShopSalesBucketResponse{
+		Currency:          bucket.Currency,
+		OrdersCount:       bucket.OrdersCount,
+		CanceledCount:     bucket.CanceledCount,
+		GrossAmount:       bucket.GrossAmount.Float64(),
+		GMV:               bucket.GMV().Float64(),
+		AverageOrderValue: bucket.AverageOrderValue().Float64(),
+		CancellationRate:  bucket.CancellationRate(),
+	}
+}
@@ -0,0 +1,45 @@
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrMissingVersion: message không khai báo event_version, bên nhận quyết định có coi là version mặc định hay không.
+var ErrMissingVersion = errors.New("event_version is missing")
+
+// Envelope là các trường metadata chung mà bên gửi phải đặt ở cấp ngoài cùng của message Kafka.
+// EventVersion = 0 nghĩa là message không khai báo version.
+type Envelope struct {
+	EventID      string `json:"event_id"`
+	EventType    string `json:"event_type"`
+	Source       string `json:"source"`
+	EventVersion int    `json:"event_version"`
+}
+
+// ParseEnvelope đọc metadata của message. Lỗi trả về là lý do message phải được park, Envelope vẫn chứa
+// những trường đọc được.
+func ParseEnvelope(value []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return envelope, fmt.Errorf("invalid event envelope: %w", err)
+	}
+
+	switch {
+	case envelope.EventType == "":
+		return envelope, fmt.Errorf("event_type is missing")
+	case envelope.Source == "":
+		return envelope, fmt.Errorf("source is missing")
+	case envelope.EventVersion == 0:
+		return envelope, ErrMissingVersion
+	case envelope.EventVersion < 0:
+		return envelope, fmt.Errorf("invalid event_version %d", envelope.EventVersion)
+	}
+	return envelope, nil
+}
+
+// Key trả về key dùng để tìm handler của message.
+func (e Envelope) Key() Key {
+	return Key{Source: e.Source, EventType: e.EventType, Version: e.EventVersion}
+}
@@ -0,0 +1,92 @@
+package inbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
+)
+
+func TestParseEnvelope(t *testing.T) {
+	testCases := []struct {
+		name           string
+		value          string
+		expected       inbox.Envelope
+		expectedError  error
+		expectAnyError bool
+	}{
+		{
+			name:  "Success - full envelope",
+			value: `{"event_id":"evt-1","event_type":"REFUND_SUCCEEDED","source":"payment-service","event_version":2,"order_id":"o-1"}`,
+			expected: inbox.Envelope{
+				EventID:      "evt-1",
+				EventType:    "REFUND_SUCCEEDED",
+				Source:       "payment-service",
+				EventVersion: 2,
+			},
+		},
+		{
+			name:  "Error - version missing keeps the other fields",
+			value: `{"event_type":"REFUND_SUCCEEDED","source":"payment-service"}`,
+			expected: inbox.Envelope{
+				EventType: "REFUND_SUCCEEDED",
+				Source:    "payment-service",
+			},
+			expectedError: inbox.ErrMissingVersion,
+		},
+		{
+			name:           "Error - negative version",
+			value:          `{"event_type":"REFUND_SUCCEEDED","source":"payment-service","event_version":-1}`,
+			expected:       inbox.Envelope{EventType: "REFUND_SUCCEEDED", Source: "payment-service", EventVersion: -1},
+			expectAnyError: true,
+		},
+		{
+			name:           "Error - event type missing",
+			value:          `{"source":"payment-service","event_version":1}`,
+			expected:       inbox.Envelope{Source: "payment-service", EventVersion: 1},
+			expectAnyError: true,
+		},
+		{
+			name:           "Error - source missing",
+			value:          `{"event_type":"REFUND_SUCCEEDED","event_version":1}`,
+			expected:       inbox.Envelope{EventType: "REFUND_SUCCEEDED", EventVersion: 1},
+			expectAnyError: true,
+		},
+		{
+			name:           "Error - not JSON",
+			value:          `not json`,
+			expectAnyError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envelope, err := inbox.ParseEnvelope([]byte(tc.value))
+
+			switch {
+			case tc.expectedError != nil:
+				if !errors.Is(err, tc.expectedError) {
+					t.Errorf("error = %v, want %v", err, tc.expectedError)
+				}
+			case tc.expectAnyError:
+				if err == nil || errors.Is(err, inbox.ErrMissingVersion) {
+					t.Errorf("error = %v, want an envelope error other than missing version", err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if envelope != tc.expected {
+				t.Errorf("envelope = %+v, want %+v", envelope, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEnvelope_Key(t *testing.T) {
+	envelope := inbox.Envelope{EventID: "evt-1", EventType: "REFUND_SUCCEEDED", Source: "payment-service", EventVersion: 1}
+	expected := inbox.Key{Source: "payment-service", EventType: "REFUND_SUCCEEDED", Version: 1}
+
+	if got := envelope.Key(); got != expected {
+		t.Errorf("Key() = %+v, want %+v", got, expected)
+	}
+}
@@ -0,0 +1,88 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ErrNoHandler: không có handler nào đăng ký cho (source, event_type, version) của event.
+var ErrNoHandler = errors.New("no inbox handler registered")
+
+// Handler xử lý một inbox event đã được lưu. Handler phải idempotent vì event có thể được xử lý lại khi retry hoặc replay.
+type Handler func(ctx context.Context, event *domain.InboxEvent) error
+
+// Key định danh loại event mà một handler xử lý. Version là version schema của payload, bắt đầu từ 1.
+type Key struct {
+	Source    string
+	EventType string
+	Version   int
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s v%d", k.Source, k.EventType, k.Version)
+}
+
+// Registry giữ các handler theo Key cùng các Kafka topic cần subscribe. Handler được đăng ký lúc khởi động,
+// trước khi KafkaConsumer lấy danh sách topic.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[Key]Handler
+	topics   []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[Key]Handler)}
+}
+
+// Register đăng ký handler cho key, event của key được nhận từ topic. Đăng ký trùng key là lỗi lập trình nên panic.
+func (r *Registry) Register(topic string, key Key, handler Handler) {
+	if topic == "" || key.Source == "" || key.EventType == "" || key.Version < 1 || handler == nil {
+		panic(fmt.Sprintf("inbox: invalid handler registration for %s on topic %q", key, topic))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[key]; exists {
+		panic(fmt.Sprintf("inbox: handler for %s registered twice", key))
+	}
+	r.handlers[key] = handler
+
+	for _, t := range r.topics {
+		if t == topic {
+			return
+		}
+	}
+	r.topics = append(r.topics, topic)
+}
+
+// Handler trả về handler của key, ok = false nếu chưa có handler nào đăng ký.
+func (r *Registry) Handler(key Key) (Handler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[key]
+	return handler, ok
+}
+
+// Dispatch gọi handler của event, trả về lỗi bọc ErrNoHandler khi không có handler phù hợp.
+func (r *Registry) Dispatch(ctx context.Context, event *domain.InboxEvent) error {
+	key := Key{Source: event.SourceService, EventType: event.EventType, Version: event.EventVersion}
+	handler, ok := r.Handler(key)
+	if !ok {
+		return fmt.Errorf("%w for %s", ErrNoHandler, key)
+	}
+	return handler(ctx, event)
+}
+
+// Topics trả về các topic đã đăng ký theo thứ tự đăng ký.
+func (r *Registry) Topics() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.topics...)
+}
@@ -0,0 +1,115 @@
+package inbox_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
+)
+
+var refundSucceededV1 = inbox.Key{Source: "payment-service", EventType: "REFUND_SUCCEEDED", Version: 1}
+
+func TestRegistry_Handler(t *testing.T) {
+	registry := inbox.NewRegistry()
+	registry.Register("REFUND_SUCCEEDED", refundSucceededV1, func(ctx context.Context, event *domain.InboxEvent) error {
+		return nil
+	})
+
+	testCases := []struct {
+		name     string
+		key      inbox.Key
+		expected bool
+	}{
+		{name: "Registered key", key: refundSucceededV1, expected: true},
+		{name: "Other version", key: inbox.Key{Source: "payment-service", EventType: "REFUND_SUCCEEDED", Version: 2}},
+		{name: "Other source", key: inbox.Key{Source: "shop-service", EventType: "REFUND_SUCCEEDED", Version: 1}},
+		{name: "Other event type", key: inbox.Key{Source: "payment-service", EventType: "REFUND_FAILED", Version: 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := registry.Handler(tc.key); ok != tc.expected {
+				t.Errorf("Handler(%s) ok = %t, want %t", tc.key, ok, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry_Dispatch(t *testing.T) {
+	registry := inbox.NewRegistry()
+	var handled []string
+	registry.Register("REFUND_SUCCEEDED", refundSucceededV1, func(ctx context.Context, event *domain.InboxEvent) error {
+		handled = append(handled, event.EventID)
+		return nil
+	})
+
+	event := &domain.InboxEvent{EventID: "evt-1", SourceService: "payment-service", EventType: "REFUND_SUCCEEDED", EventVersion: 1}
+	if err := registry.Dispatch(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(handled, []string{"evt-1"}) {
+		t.Errorf("handled = %v, want [evt-1]", handled)
+	}
+
+	unknown := &domain.InboxEvent{EventID: "evt-2", SourceService: "payment-service", EventType: "REFUND_SUCCEEDED", EventVersion: 2}
+	if err := registry.Dispatch(context.Background(), unknown); !errors.Is(err, inbox.ErrNoHandler) {
+		t.Errorf("error = %v, want %v", err, inbox.ErrNoHandler)
+	}
+}
+
+func TestRegistry_Topics(t *testing.T) {
+	registry := inbox.NewRegistry()
+	noop := func(ctx context.Context, event *domain.InboxEvent) error { return nil }
+
+	registry.Register("REFUND_SUCCEEDED", refundSucceededV1, noop)
+	registry.Register("REFUND_SUCCEEDED", inbox.Key{Source: "payment-service", EventType: "REFUND_SUCCEEDED", Version: 2}, noop)
+	registry.Register("PAYMENT_SUCCEEDED", inbox.Key{Source: "payment-service", EventType: "PAYMENT_SUCCEEDED", Version: 1}, noop)
+
+	expected := []string{"REFUND_SUCCEEDED", "PAYMENT_SUCCEEDED"}
+	if got := registry.Topics(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Topics() = %v, want %v", got, expected)
+	}
+}
+
+func TestRegistry_Register_Panics(t *testing.T) {
+	noop := func(ctx context.Context, event *domain.InboxEvent) error { return nil }
+
+	testCases := []struct {
+		name     string
+		register func(registry *inbox.Registry)
+	}{
+		{
+			name: "Duplicate key",
+			register: func(registry *inbox.Registry) {
+				registry.Register("REFUND_SUCCEEDED", refundSucceededV1, noop)
+				registry.Register("REFUND_SUCCEEDED", refundSucceededV1, noop)
+			},
+		},
+		{
+			name: "Version below 1",
+			register: func(registry *inbox.Registry) {
+				registry.Register("REFUND_SUCCEEDED", inbox.Key{Source: "payment-service", EventType: "REFUND_SUCCEEDED"}, noop)
+			},
+		},
+		{
+			name: "Missing handler",
+			register: func(registry *inbox.Registry) {
+				registry.Register("REFUND_SUCCEEDED", refundSucceededV1, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected Register to panic")
+				}
+			}()
+			tc.register(inbox.NewRegistry())
+		})
+	}
+}
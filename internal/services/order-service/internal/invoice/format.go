@@ -0,0 +1,66 @@
+package invoice
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// formatAmount định dạng số tiền kèm tiền tệ, ví dụ 1,234.50 USD hoặc 150,000 VND.
+func formatAmount(amount money.Money) string {
+	return formatNumber(amount) + " " + amount.Currency
+}
+
+// formatNumber định dạng số tiền có dấu phân cách hàng nghìn theo số chữ số thập phân của tiền tệ, ví dụ 1,234.50.
+func formatNumber(amount money.Money) string {
+	decimal := amount.Decimal()
+	sign := ""
+	if strings.HasPrefix(decimal, "-") {
+		sign = "-"
+		decimal = decimal[1:]
+	}
+
+	whole, fraction, hasFraction := strings.Cut(decimal, ".")
+
+	var grouped strings.Builder
+	grouped.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasFraction {
+		grouped.WriteString("." + fraction)
+	}
+	return grouped.String()
+}
+
+// formatTaxRate hiển thị thuế suất dạng phần trăm, ví dụ 0.1 -> 10%.
+func formatTaxRate(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', -1, 64) + "%"
+}
+
+// joinAddress nối các phần địa chỉ khác rỗng thành một dòng.
+func joinAddress(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+func sellerAddress(seller domain.InvoiceSeller) string {
+	return joinAddress(seller.Street, seller.Ward, seller.District, seller.City, seller.Country)
+}
+
+func buyerAddress(buyer *domain.ShippingAddress) string {
+	if buyer == nil {
+		return ""
+	}
+	return joinAddress(buyer.Street, buyer.Ward, buyer.District, buyer.City, buyer.Country)
+}
@@ -0,0 +1,145 @@
+package invoice
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+func TestFormatNumber(t *testing.T) {
+	testCases := []struct {
+		amount   money.Money
+		expected string
+	}{
+		{amount: money.New(0, "VND"), expected: "0"},
+		{amount: money.New(999, "VND"), expected: "999"},
+		{amount: money.New(1234567, "VND"), expected: "1,234,567"},
+		{amount: money.New(123450, "USD"), expected: "1,234.50"},
+		{amount: money.New(-100000, "USD"), expected: "-1,000.00"},
+	}
+
+	for _, tc := range testCases {
+		if got := formatNumber(tc.amount); got != tc.expected {
+			t.Errorf("formatNumber(%s) = %q, want %q", tc.amount, got, tc.expected)
+		}
+	}
+}
+
+func TestFormatAmountAndTaxRate(t *testing.T) {
+	if got := formatAmount(money.New(250000, "VND")); got != "250,000 VND" {
+		t.Errorf("formatAmount() = %q, want %q", got, "250,000 VND")
+	}
+	if got := formatTaxRate(0.1); got != "10%" {
+		t.Errorf("formatTaxRate(0.1) = %q, want 10%%", got)
+	}
+	if got := formatTaxRate(0.085); got != "8.5%" {
+		t.Errorf("formatTaxRate(0.085) = %q, want 8.5%%", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	short := "Ao thun"
+	if got := truncateText(short, 10, false, 200); got != short {
+		t.Errorf("truncateText(%q) = %q, want unchanged", short, got)
+	}
+
+	long := "Ao thun cotton co tron tay ngan mau trang size XL ban gioi han"
+	got := truncateText(long, 10, false, 100)
+	if got == long || len(got) < 4 || got[len(got)-3:] != "..." {
+		t.Fatalf("truncateText(%q) = %q, want truncated with ...", long, got)
+	}
+	if width := textWidth(got, 10, false); width > 100 {
+		t.Errorf("truncated width = %.1f, want <= 100", width)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	address := "123 Nguyen Trai, Phuong Ben Thanh, Quan 1, Thanh pho Ho Chi Minh, Viet Nam"
+	lines := wrapText(address, 10, false, 120)
+
+	if len(lines) < 2 {
+		t.Fatalf("wrapText() = %q, want several lines", lines)
+	}
+	for _, line := range lines {
+		if textWidth(line, 10, false) > 120 {
+			t.Errorf("line %q is wider than 120", line)
+		}
+	}
+	if got := wrapText("   ", 10, false, 120); got != nil {
+		t.Errorf("wrapText(blank) = %q, want nil", got)
+	}
+}
+
+func TestToWinAnsi(t *testing.T) {
+	testCases := map[string]string{
+		"Nguyễn Văn Đức":   "Nguyen Van Duc",
+		"Áo thun (size M)": "Ao thun (size M)",
+		"Giá 10€":          "Gia 10?",
+	}
+	for input, expected := range testCases {
+		if got := toWinAnsi(input); got != expected {
+			t.Errorf("toWinAnsi(%q) = %q, want %q", input, got, expected)
+		}
+	}
+
+	if got := escapePDFText(`a\b (c)`); got != `a\\b \(c\)` {
+		t.Errorf("escapePDFText() = %q", got)
+	}
+}
+
+func TestPartyLines(t *testing.T) {
+	lines := partyLines("Shop A", "1 Le Loi, Quan 1", "0901234567", "")
+	expected := []partyLine{
+		{text: "Shop A", bold: true},
+		{text: "1 Le Loi, Quan 1"},
+		{text: "Phone: 0901234567"},
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("partyLines() = %+v, want %+v", lines, expected)
+	}
+
+	seller := domain.InvoiceSeller{Street: "1 Le Loi", Ward: " ", District: "Quan 1", City: "HCM"}
+	if got := sellerAddress(seller); got != "1 Le Loi, Quan 1, HCM" {
+		t.Errorf("sellerAddress() = %q", got)
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	invoice := &domain.Invoice{
+		OrderID:        "order-1",
+		InvoiceNumber:  "INV-3F2B1C4D-000001",
+		Currency:       "VND",
+		Subtotal:       money.New(200000, "VND"),
+		DiscountAmount: money.Zero("VND"),
+		ShippingFee:    money.New(10000, "VND"),
+		TaxRate:        0.1,
+		TaxAmount:      money.New(19091, "VND"),
+		TotalAmount:    money.New(210000, "VND"),
+		Seller:         domain.InvoiceSeller{Name: "Shop A"},
+		IssuedAt:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// Đủ nhiều dòng để sang trang thứ hai
+	for i := 0; i < 60; i++ {
+		invoice.Lines = append(invoice.Lines, domain.InvoiceLine{
+			ProductName: "Ao thun",
+			Quantity:    1,
+			UnitPrice:   money.New(1000, "VND"),
+			Amount:      money.New(1000, "VND"),
+		})
+	}
+
+	pdf, err := RenderPDF(invoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Errorf("output is not a PDF document")
+	}
+	if pages := bytes.Count(pdf, []byte("/Type /Page ")); pages < 2 {
+		t.Errorf("pages = %d, want at least 2", pages)
+	}
+}
@@ -0,0 +1,99 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount":        formatAmount,
+	"taxRate":       formatTaxRate,
+	"date":          func(t time.Time) string { return t.Format("2006-01-02") },
+	"sellerAddress": sellerAddress,
+	"buyerAddress":  buyerAddress,
+	"inc":           func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.InvoiceNumber}}</title>
+<style>
+  body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; margin: 40px; }
+  h1 { font-size: 24px; margin: 0 0 4px; }
+  .meta { color: #555; margin-bottom: 24px; }
+  .parties { display: flex; gap: 48px; margin-bottom: 24px; }
+  .parties div { flex: 1; }
+  .parties h2 { font-size: 13px; text-transform: uppercase; color: #555; margin: 0 0 6px; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { padding: 6px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+  th.num, td.num { text-align: right; }
+  .totals { width: 320px; margin-left: auto; margin-top: 16px; }
+  .totals td { border: none; padding: 3px 8px; }
+  .totals tr.grand td { font-weight: bold; border-top: 1px solid #222; }
+  .note { margin-top: 24px; color: #555; }
+  @media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>INVOICE</h1>
+<div class="meta">
+  No. <strong>{{.InvoiceNumber}}</strong> &middot; Issued {{date .IssuedAt}} &middot; Order {{.OrderID}}
+</div>
+<div class="parties">
+  <div>
+    <h2>Seller</h2>
+    <strong>{{.Seller.Name}}</strong><br>
+    {{with sellerAddress .Seller}}{{.}}<br>{{end}}
+    {{with .Seller.Phone}}Phone: {{.}}<br>{{end}}
+    {{with .Seller.Email}}Email: {{.}}{{end}}
+  </div>
+  <div>
+    <h2>Bill to</h2>
+    {{with .Buyer}}
+    <strong>{{.RecipientName}}</strong><br>
+    {{with buyerAddress .}}{{.}}<br>{{end}}
+    {{with .RecipientPhone}}Phone: {{.}}{{end}}
+    {{end}}
+  </div>
+</div>
+<table>
+  <thead>
+    <tr><th>#</th><th>Item</th><th class="num">Qty</th><th class="num">Unit price</th><th class="num">Amount</th></tr>
+  </thead>
+  <tbody>
+    {{range $i, $line := .Lines}}
+    <tr>
+      <td>{{inc $i}}</td>
+      <td>{{$line.ProductName}}</td>
+      <td class="num">{{$line.Quantity}}</td>
+      <td class="num">{{amount $line.UnitPrice}}</td>
+      <td class="num">{{amount $line.Amount}}</td>
+    </tr>
+    {{end}}
+  </tbody>
+</table>
+<table class="totals">
+  <tr><td>Subtotal</td><td class="num">{{amount .Subtotal}}</td></tr>
+  {{if .DiscountAmount.IsPositive}}<tr><td>Discount</td><td class="num">-{{amount .DiscountAmount}}</td></tr>{{end}}
+  <tr><td>Shipping fee</td><td class="num">{{amount .ShippingFee}}</td></tr>
+  <tr><td>Amount before tax</td><td class="num">{{amount .NetAmount}}</td></tr>
+  <tr><td>VAT ({{taxRate .TaxRate}})</td><td class="num">{{amount .TaxAmount}}</td></tr>
+  <tr class="grand"><td>Total</td><td class="num">{{amount .TotalAmount}}</td></tr>
+</table>
+<p class="note">Prices include VAT.</p>
+</body>
+</html>
+`))
+
+// RenderHTML trả về hoá đơn dạng trang HTML có thể in trực tiếp từ trình duyệt.
+func RenderHTML(invoice *domain.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, invoice); err != nil {
+		return nil, fmt.Errorf("failed to render invoice %s as HTML: %w", invoice.InvoiceNumber, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,171 @@
+package invoice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+const (
+	pdfMarginLeft   = 50.0
+	pdfMarginRight  = pdfPageWidth - 50
+	pdfMarginTop    = pdfPageHeight - 50
+	pdfMarginBottom = 60.0
+
+	pdfBuyerColumn = 310.0
+	pdfColumnWidth = 230.0
+	pdfRowHeight   = 18.0
+
+	colIndex     = pdfMarginLeft
+	colItem      = pdfMarginLeft + 25
+	colQuantity  = 350.0
+	colUnitPrice = 450.0
+	colAmount    = pdfMarginRight
+	colTotals    = 330.0
+)
+
+// RenderPDF trả về hoá đơn dạng PDF khổ A4, tự sang trang khi đơn hàng có nhiều sản phẩm.
+func RenderPDF(invoice *domain.Invoice) ([]byte, error) {
+	w := newPDFWriter()
+	y := pdfMarginTop
+
+	w.text(pdfMarginLeft, y-20, 22, true, "INVOICE")
+	w.textRight(pdfMarginRight, y-8, 11, true, "No. "+invoice.InvoiceNumber)
+	w.textRight(pdfMarginRight, y-22, 9, false, "Issued: "+invoice.IssuedAt.Format("2006-01-02"))
+	w.textRight(pdfMarginRight, y-34, 9, false, "Order: "+invoice.OrderID)
+	y -= 70
+
+	sellerLines := partyLines(invoice.Seller.Name, sellerAddress(invoice.Seller), invoice.Seller.Phone, invoice.Seller.Email)
+	var buyerLines []partyLine
+	if invoice.Buyer != nil {
+		buyerLines = partyLines(invoice.Buyer.RecipientName, buyerAddress(invoice.Buyer), invoice.Buyer.RecipientPhone, "")
+	}
+	sellerBottom := drawParty(w, pdfMarginLeft, y, "SELLER", sellerLines)
+	buyerBottom := drawParty(w, pdfBuyerColumn, y, "BILL TO", buyerLines)
+	y = min(sellerBottom, buyerBottom) - 20
+
+	y = drawTableHeader(w, y)
+	for i, line := range invoice.Lines {
+		if y < pdfMarginBottom+pdfRowHeight {
+			w.addPage()
+			y = drawTableHeader(w, pdfMarginTop)
+		}
+
+		w.text(colIndex, y, 10, false, strconv.Itoa(i+1))
+		w.text(colItem, y, 10, false, truncateText(line.ProductName, 10, false, colQuantity-colItem-40))
+		w.textRight(colQuantity, y, 10, false, strconv.Itoa(line.Quantity))
+		w.textRight(colUnitPrice, y, 10, false, formatNumber(line.UnitPrice))
+		w.textRight(colAmount, y, 10, false, formatNumber(line.Amount))
+		w.line(pdfMarginLeft, y-6, pdfMarginRight, y-6, 0.3)
+		y -= pdfRowHeight
+	}
+
+	totals := [][2]string{
+		{"Subtotal", formatAmount(invoice.Subtotal)},
+	}
+	if invoice.DiscountAmount.IsPositive() {
+		totals = append(totals, [2]string{"Discount", "-" + formatAmount(invoice.DiscountAmount)})
+	}
+	totals = append(totals,
+		[2]string{"Shipping fee", formatAmount(invoice.ShippingFee)},
+		[2]string{"Amount before tax", formatAmount(invoice.NetAmount())},
+		[2]string{fmt.Sprintf("VAT (%s)", formatTaxRate(invoice.TaxRate)), formatAmount(invoice.TaxAmount)},
+	)
+
+	// Phần tổng cộng không bị tách sang hai trang
+	if y-float64(len(totals)+3)*pdfRowHeight < pdfMarginBottom {
+		w.addPage()
+		y = pdfMarginTop
+	}
+
+	y -= 8
+	for _, total := range totals {
+		w.text(colTotals, y, 10, false, total[0])
+		w.textRight(colAmount, y, 10, false, total[1])
+		y -= pdfRowHeight - 2
+	}
+	w.line(colTotals, y+10, colAmount, y+10, 0.8)
+	y -= 4
+	w.text(colTotals, y, 11, true, "Total")
+	w.textRight(colAmount, y, 11, true, formatAmount(invoice.TotalAmount))
+
+	w.text(pdfMarginLeft, pdfMarginBottom-20, 8, false, "Prices include VAT.")
+
+	return w.bytes()
+}
+
+type partyLine struct {
+	text string
+	bold bool
+}
+
+func partyLines(name, address, phone, email string) []partyLine {
+	lines := []partyLine{{text: name, bold: true}}
+	for _, wrapped := range wrapText(address, 10, false, pdfColumnWidth) {
+		lines = append(lines, partyLine{text: wrapped})
+	}
+	if phone != "" {
+		lines = append(lines, partyLine{text: "Phone: " + phone})
+	}
+	if email != "" {
+		lines = append(lines, partyLine{text: "Email: " + email})
+	}
+	return lines
+}
+
+// drawParty vẽ khối thông tin người bán / người mua và trả về toạ độ y bên dưới khối.
+func drawParty(w *pdfWriter, x, y float64, title string, lines []partyLine) float64 {
+	w.text(x, y, 9, true, title)
+	y -= 16
+	for _, line := range lines {
+		w.text(x, y, 10, line.bold, line.text)
+		y -= 14
+	}
+	return y
+}
+
+func drawTableHeader(w *pdfWriter, y float64) float64 {
+	w.text(colIndex, y, 9, true, "#")
+	w.text(colItem, y, 9, true, "Item")
+	w.textRight(colQuantity, y, 9, true, "Qty")
+	w.textRight(colUnitPrice, y, 9, true, "Unit price")
+	w.textRight(colAmount, y, 9, true, "Amount")
+	w.line(pdfMarginLeft, y-6, pdfMarginRight, y-6, 0.8)
+	return y - pdfRowHeight - 2
+}
+
+// wrapText xuống dòng theo từ để mỗi dòng không vượt quá maxWidth.
+func wrapText(s string, size float64, bold bool, maxWidth float64) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+
+	lines := []string{}
+	current := words[0]
+	for _, word := range words[1:] {
+		candidate := current + " " + word
+		if textWidth(candidate, size, bold) > maxWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	return append(lines, current)
+}
+
+// truncateText cắt chuỗi và thêm "..." nếu chuỗi rộng hơn maxWidth.
+func truncateText(s string, size float64, bold bool, maxWidth float64) string {
+	if textWidth(s, size, bold) <= maxWidth {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size, bold) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
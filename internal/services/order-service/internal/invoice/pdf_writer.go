@@ -0,0 +1,185 @@
+package invoice
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// pdfWriter là bộ ghi PDF tối giản: khổ A4, chỉ dùng hai font chuẩn Helvetica / Helvetica-Bold (có sẵn trong
+// mọi trình đọc PDF nên không cần nhúng font), text và đường kẻ. Đủ cho hoá đơn mà không cần thư viện ngoài.
+type pdfWriter struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+)
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.addPage()
+	return w
+}
+
+func (w *pdfWriter) addPage() {
+	w.current = &bytes.Buffer{}
+	w.pages = append(w.pages, w.current)
+}
+
+// text vẽ chuỗi với góc trái dưới tại (x, y), gốc toạ độ ở góc trái dưới trang.
+func (w *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(w.current, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFText(toWinAnsi(s)))
+}
+
+// textRight vẽ chuỗi căn phải tại xRight.
+func (w *pdfWriter) textRight(xRight, y, size float64, bold bool, s string) {
+	w.text(xRight-textWidth(s, size, bold), y, size, bold, s)
+}
+
+func (w *pdfWriter) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(w.current, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// bytes ghép các object của tài liệu và bảng xref.
+func (w *pdfWriter) bytes() ([]byte, error) {
+	var out bytes.Buffer
+	offsets := []int{}
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: pages, 3-4: fonts, sau đó mỗi trang gồm page object và content stream
+	const firstPageObject = 5
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range w.pages {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress page %d: %w", i+1, err)
+		}
+
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPageObject+i*2+1,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+	return out.Bytes(), nil
+}
+
+// escapePDFText escape các ký tự đặc biệt trong string literal của PDF.
+func escapePDFText(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", "", "\n", " ")
+	return replacer.Replace(s)
+}
+
+// toWinAnsi chuyển chuỗi về bảng mã của font chuẩn. Chữ tiếng Việt được bỏ dấu vì WinAnsi không có đủ ký tự,
+// các ký tự khác ngoài ASCII được thay bằng '?'.
+func toWinAnsi(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x80 {
+			b.WriteRune(r)
+			continue
+		}
+		if base, ok := vietnameseBase[r]; ok {
+			b.WriteRune(base)
+			continue
+		}
+		b.WriteByte('?')
+	}
+	return b.String()
+}
+
+var vietnameseBase = func() map[rune]rune {
+	groups := map[rune]string{
+		'a': "àáảãạăằắẳẵặâầấẩẫậ",
+		'A': "ÀÁẢÃẠĂẰẮẲẴẶÂẦẤẨẪẬ",
+		'e': "èéẻẽẹêềếểễệ",
+		'E': "ÈÉẺẼẸÊỀẾỂỄỆ",
+		'i': "ìíỉĩị",
+		'I': "ÌÍỈĨỊ",
+		'o': "òóỏõọôồốổỗộơờớởỡợ",
+		'O': "ÒÓỎÕỌÔỒỐỔỖỘƠỜỚỞỠỢ",
+		'u': "ùúủũụưừứửữự",
+		'U': "ÙÚỦŨỤƯỪỨỬỮỰ",
+		'y': "ỳýỷỹỵ",
+		'Y': "ỲÝỶỸỴ",
+		'd': "đ",
+		'D': "Đ",
+	}
+	m := make(map[rune]rune)
+	for base, variants := range groups {
+		for _, r := range variants {
+			m[r] = base
+		}
+	}
+	return m
+}()
+
+// textWidth đo độ rộng chuỗi theo bảng độ rộng chuẩn (AFM) của Helvetica, đơn vị 1/1000 cỡ chữ.
+func textWidth(s string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+
+	var total int
+	for _, r := range toWinAnsi(s) {
+		if r >= 32 && r <= 126 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Độ rộng các ký tự ASCII 32..126.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// DeliveryRepository quản lý việc giao hàng của shipper. Mỗi bước lấy hàng / giao hàng cập nhật
+// order_deliveries và trạng thái đơn hàng trong cùng một transaction.
+type DeliveryRepository interface {
+	// ListReadyToShipShopIDs trả về các shop đang có đơn SHIPPED chưa có shipper nhận.
+	ListReadyToShipShopIDs(ctx context.Context) ([]string, error)
+	// ListReadyToShipOrders trả về các đơn SHIPPED chưa có shipper nhận của các shop, theo thứ tự của shopIDs.
+	ListReadyToShipOrders(ctx context.Context, shopIDs []string, limit int) ([]*domain.Order, error)
+	ClaimOrder(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, error)
+	GetByOrderID(ctx context.Context, orderID string) (*domain.OrderDelivery, error)
+	ListByShipper(ctx context.Context, shipperID string, status *domain.DeliveryStatus, limit int) ([]*domain.OrderDelivery, error)
+	MarkPickedUp(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, *domain.Order, error)
+	MarkDelivered(ctx context.Context, orderID string, shipperID string, proof domain.DeliveryProof) (*domain.OrderDelivery, *domain.Order, error)
+}
+
+type deliveryRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewDeliveryRepository(db *postgresql_infra.PostgreSQLService) DeliveryRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &deliveryRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *deliveryRepository) ListReadyToShipShopIDs(ctx context.Context) ([]string, error) {
+	ids, err := r.queries.GetReadyToShipShopIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shops with ready to ship orders: %w", err)
+	}
+
+	shopIDs := make([]string, len(ids))
+	for i, id := range ids {
+		shopIDs[i] = converter.PgUUIDToString(id)
+	}
+	return shopIDs, nil
+}
+
+// ListReadyToShipOrders: đơn của shop đứng trước trong shopIDs được trả về trước, cùng shop thì đơn chờ lâu nhất trước.
+func (r *deliveryRepository) ListReadyToShipOrders(ctx context.Context, shopIDs []string, limit int) ([]*domain.Order, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+	if len(shopIDs) == 0 {
+		return []*domain.Order{}, nil
+	}
+
+	ids := make([]pgtype.UUID, len(shopIDs))
+	for i, shopID := range shopIDs {
+		ids[i] = converter.StringToPgUUID(shopID)
+	}
+
+	orders, err := r.queries.GetReadyToShipOrders(ctx, sqlc.GetReadyToShipOrdersParams{
+		ShopIds:  ids,
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready to ship orders: %w", err)
+	}
+
+	domainOrders := make([]*domain.Order, len(orders))
+	for i := range orders {
+		domainOrders[i] = toDomainOrder(&orders[i])
+	}
+	return domainOrders, nil
+}
+
+// ClaimOrder gán đơn hàng cho shipper bằng một câu INSERT duy nhất, trả về domain.ErrOrderNotClaimable
+// nếu đơn không còn SHIPPED hoặc đã có shipper khác nhận.
+func (r *deliveryRepository) ClaimOrder(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, error) {
+	delivery, err := r.queries.ClaimOrderDelivery(ctx, sqlc.ClaimOrderDeliveryParams{
+		OrderID:   converter.StringToPgUUID(orderID),
+		ShipperID: converter.StringToPgUUID(shipperID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrderNotClaimable
+		}
+		return nil, fmt.Errorf("failed to claim order %s: %w", orderID, err)
+	}
+	return toDomainOrderDelivery(&delivery), nil
+}
+
+func (r *deliveryRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.OrderDelivery, error) {
+	delivery, err := r.queries.GetOrderDeliveryByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order delivery", orderID)
+		}
+		return nil, fmt.Errorf("failed to get delivery of order %s: %w", orderID, err)
+	}
+	return toDomainOrderDelivery(&delivery), nil
+}
+
+func (r *deliveryRepository) ListByShipper(ctx context.Context, shipperID string, status *domain.DeliveryStatus, limit int) ([]*domain.OrderDelivery, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	params := sqlc.ListOrderDeliveriesByShipperParams{
+		ShipperID: converter.StringToPgUUID(shipperID),
+		PageSize:  int32(limit),
+	}
+	if status != nil {
+		params.Status = sqlc.NullDeliveryStatus{DeliveryStatus: sqlc.DeliveryStatus(*status), Valid: true}
+	}
+
+	deliveries, err := r.queries.ListOrderDeliveriesByShipper(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries of shipper %s: %w", shipperID, err)
+	}
+
+	result := make([]*domain.OrderDelivery, len(deliveries))
+	for i := range deliveries {
+		result[i] = toDomainOrderDelivery(&deliveries[i])
+	}
+	return result, nil
+}
+
+// MarkPickedUp: shipper đã lấy hàng tại shop, đơn hàng chuyển SHIPPED -> DELIVERING.
+func (r *deliveryRepository) MarkPickedUp(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, *domain.Order, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	delivery, err := qtx.MarkOrderDeliveryPickedUp(ctx, sqlc.MarkOrderDeliveryPickedUpParams{
+		OrderID:   converter.StringToPgUUID(orderID),
+		ShipperID: converter.StringToPgUUID(shipperID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, domain.ErrDeliveryStatusChanged
+		}
+		return nil, nil, fmt.Errorf("failed to mark delivery of order %s as picked up: %w", orderID, err)
+	}
+
+	order, err := transitionOrderForDelivery(ctx, qtx, delivery.OrderID, sqlc.OrderStatusSHIPPED, sqlc.OrderStatusDELIVERING, domain.StatusChange{
+		Actor:  domain.ShipperActor(shipperID),
+		Reason: "picked up by shipper",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainOrderDelivery(&delivery), toDomainOrder(order), nil
+}
+
+// MarkDelivered: shipper đã giao hàng kèm ảnh và toạ độ xác nhận, đơn hàng chuyển DELIVERING -> DELIVERED.
+func (r *deliveryRepository) MarkDelivered(ctx context.Context, orderID string, shipperID string, proof domain.DeliveryProof) (*domain.OrderDelivery, *domain.Order, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	delivery, err := qtx.MarkOrderDeliveryDelivered(ctx, sqlc.MarkOrderDeliveryDeliveredParams{
+		OrderID:       converter.StringToPgUUID(orderID),
+		ShipperID:     converter.StringToPgUUID(shipperID),
+		ProofPhotoUrl: converter.StringToPgText(&proof.PhotoURL),
+		DeliveredLat:  converter.Float64ToPgFloat8(&proof.Location.Lat),
+		DeliveredLong: converter.Float64ToPgFloat8(&proof.Location.Long),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, domain.ErrDeliveryStatusChanged
+		}
+		return nil, nil, fmt.Errorf("failed to mark delivery of order %s as delivered: %w", orderID, err)
+	}
+
+	order, err := transitionOrderForDelivery(ctx, qtx, delivery.OrderID, sqlc.OrderStatusDELIVERING, sqlc.OrderStatusDELIVERED, domain.StatusChange{
+		Actor:  domain.ShipperActor(shipperID),
+		Reason: "delivered by shipper",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainOrderDelivery(&delivery), toDomainOrder(order), nil
+}
+
+// transitionOrderForDelivery đổi trạng thái đơn hàng trong transaction giao hàng, kèm lịch sử trạng thái và outbox event.
+func transitionOrderForDelivery(ctx context.Context, q *sqlc.Queries, orderID pgtype.UUID, from sqlc.OrderStatus, to sqlc.OrderStatus, change domain.StatusChange) (*sqlc.Order, error) {
+	order, err := q.UpdateOrderStatusIfCurrent(ctx, sqlc.UpdateOrderStatusIfCurrentParams{
+		NewStatus:      to,
+		ID:             orderID,
+		ExpectedStatus: from,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrOrderStatusChanged
+		}
+		return nil, fmt.Errorf("failed to update status of order %s: %w", converter.PgUUIDToString(orderID), err)
+	}
+
+	if err := recordStatusChange(ctx, q, orderID, &from, to, change); err != nil {
+		return nil, err
+	}
+
+	if err := recordStatusEvents(ctx, q, &order, from, change); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func toDomainOrderDelivery(dbDelivery *sqlc.OrderDelivery) *domain.OrderDelivery {
+	if dbDelivery == nil {
+		return nil
+	}
+
+	delivery := &domain.OrderDelivery{
+		ID:             converter.PgUUIDToString(dbDelivery.ID),
+		OrderID:        converter.PgUUIDToString(dbDelivery.OrderID),
+		ShipperID:      converter.PgUUIDToString(dbDelivery.ShipperID),
+		DeliveryStatus: domain.DeliveryStatus(dbDelivery.DeliveryStatus),
+		ProofPhotoURL:  converter.PgTextToStringPtr(dbDelivery.ProofPhotoUrl),
+		PickedUpAt:     converter.PgTimeToTimePtr(dbDelivery.PickedUpAt),
+		DeliveredAt:    converter.PgTimeToTimePtr(dbDelivery.DeliveredAt),
+		ClaimedAt:      dbDelivery.ClaimedAt.Time,
+		CreatedAt:      dbDelivery.CreatedAt.Time,
+		UpdatedAt:      dbDelivery.UpdatedAt.Time,
+	}
+	if dbDelivery.DeliveredLat.Valid && dbDelivery.DeliveredLong.Valid {
+		delivery.DeliveredPoint = &domain.GeoPoint{
+			Lat:  dbDelivery.DeliveredLat.Float64,
+			Long: dbDelivery.DeliveredLong.Float64,
+		}
+	}
+	return delivery
+}
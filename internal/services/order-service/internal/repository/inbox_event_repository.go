@@ -2,11 +2,18 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type InboxEventRepository interface {
@@ -17,12 +24,23 @@ type InboxEventRepository interface {
 	GetFailedInboxEvents(ctx context.Context, limit int) ([]*domain.InboxEvent, error)
 	GetInboxEventStats(ctx context.Context) (*InboxEventStats, error)
 	CleanupOldInboxEvents(ctx context.Context) error
+
+	// Quản trị dead-letter: replay và discard chỉ áp dụng cho event FAILED hoặc PARKED, mỗi thao tác được ghi audit cùng transaction.
+	// fromStatus là trạng thái admin nhìn thấy; event đã đổi trạng thái trước đó trả về domain.ErrInboxEventStatusChanged.
+	ListInboxEvents(ctx context.Context, filter domain.InboxEventListFilter) (*domain.InboxEventPage, error)
+	GetInboxEventByID(ctx context.Context, id string) (*domain.InboxEvent, error)
+	GetInboxEventAudits(ctx context.Context, id string) ([]*domain.InboxEventAudit, error)
+	ReplayInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error)
+	ReplayFailedInboxEvents(ctx context.Context, filter domain.InboxEventReplayFilter, change domain.StatusChange) ([]*domain.InboxEvent, error)
+	DiscardInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error)
 }
 
 type InboxEventStats struct {
 	PendingCount   int64 `json:"pending_count"`
 	ProcessedCount int64 `json:"processed_count"`
 	FailedCount    int64 `json:"failed_count"`
+	DiscardedCount int64 `json:"discarded_count"`
+	ParkedCount    int64 `json:"parked_count"`
 	TotalCount     int64 `json:"total_count"`
 }
 
@@ -51,6 +69,8 @@ func (r *inboxEventRepository) CreateInboxEvent(ctx context.Context, event *doma
 		SourceService: event.SourceService,
 		Payload:       []byte(event.Payload),
 		EventStatus:   sqlc.InboxEventStatus(event.EventStatus),
+		EventVersion:  int32(event.EventVersion),
+		LastError:     converter.StringToPgText(event.LastError),
 	}
 
 	result, err := r.queries.CreateInboxEvent(ctx, params)
@@ -89,6 +109,7 @@ func (r *inboxEventRepository) UpdateInboxEventStatus(ctx context.Context, event
 		ID:          converter.StringToPgUUID(event.ID),
 		EventStatus: sqlc.InboxEventStatus(event.EventStatus),
 		RetryCount:  int32(event.RetryCount),
+		LastError:   converter.StringToPgText(event.LastError),
 	}
 
 	result, err := r.queries.UpdateInboxEventStatus(ctx, params)
@@ -123,6 +144,8 @@ func (r *inboxEventRepository) GetInboxEventStats(ctx context.Context) (*InboxEv
 		PendingCount:   result.PendingCount,
 		ProcessedCount: result.ProcessedCount,
 		FailedCount:    result.FailedCount,
+		DiscardedCount: result.DiscardedCount,
+		ParkedCount:    result.ParkedCount,
 		TotalCount:     result.TotalCount,
 	}, nil
 }
@@ -131,6 +154,182 @@ func (r *inboxEventRepository) CleanupOldInboxEvents(ctx context.Context) error
 	return r.queries.CleanupOldInboxEvents(ctx)
 }
 
+func (r *inboxEventRepository) ListInboxEvents(ctx context.Context, filter domain.InboxEventListFilter) (*domain.InboxEventPage, error) {
+	if filter.Limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	// Lấy dư 1 bản ghi để biết còn trang tiếp theo hay không
+	params := sqlc.ListInboxEventsParams{
+		ReceivedFrom: converter.TimePtrToPgTime(filter.ReceivedFrom),
+		ReceivedTo:   converter.TimePtrToPgTime(filter.ReceivedTo),
+		PageSize:     int32(filter.Limit + 1),
+	}
+	if filter.Status != nil {
+		params.Status = sqlc.NullInboxEventStatus{InboxEventStatus: sqlc.InboxEventStatus(*filter.Status), Valid: true}
+	}
+	if filter.EventType != "" {
+		params.EventType = pgtype.Text{String: filter.EventType, Valid: true}
+	}
+	if filter.Cursor != nil {
+		params.CursorReceivedAt = converter.TimeToPgTime(filter.Cursor.ReceivedAt)
+		params.CursorID = converter.StringToPgUUID(filter.Cursor.ID)
+	}
+
+	results, err := r.queries.ListInboxEvents(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inbox events: %w", err)
+	}
+
+	page := &domain.InboxEventPage{}
+	if len(results) > filter.Limit {
+		results = results[:filter.Limit]
+		last := results[len(results)-1]
+		page.NextCursor = &domain.InboxEventCursor{
+			ReceivedAt: last.ReceivedAt.Time,
+			ID:         converter.PgUUIDToString(last.ID),
+		}
+	}
+
+	page.Events = make([]*domain.InboxEvent, 0, len(results))
+	for i := range results {
+		page.Events = append(page.Events, inboxEventToDomain(&results[i]))
+	}
+	return page, nil
+}
+
+func (r *inboxEventRepository) GetInboxEventByID(ctx context.Context, id string) (*domain.InboxEvent, error) {
+	result, err := r.queries.GetInboxEventByID(ctx, converter.StringToPgUUID(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Inbox event", id)
+		}
+		return nil, fmt.Errorf("failed to get inbox event %s: %w", id, err)
+	}
+
+	return inboxEventToDomain(&result), nil
+}
+
+func (r *inboxEventRepository) GetInboxEventAudits(ctx context.Context, id string) ([]*domain.InboxEventAudit, error) {
+	results, err := r.queries.GetInboxEventAudits(ctx, converter.StringToPgUUID(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audits of inbox event %s: %w", id, err)
+	}
+
+	audits := make([]*domain.InboxEventAudit, 0, len(results))
+	for _, result := range results {
+		audits = append(audits, &domain.InboxEventAudit{
+			ID:        result.ID,
+			EventID:   result.EventID,
+			Action:    result.Action,
+			OldStatus: domain.InboxEventStatus(result.OldStatus),
+			NewStatus: domain.InboxEventStatus(result.NewStatus),
+			Actor:     result.Actor,
+			Reason:    result.Reason,
+			TraceID:   converter.PgTextToStringPtr(result.TraceID),
+			CreatedAt: *converter.PgTimeToTimePtr(result.CreatedAt),
+		})
+	}
+	return audits, nil
+}
+
+func (r *inboxEventRepository) ReplayInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error) {
+	events, err := r.auditedTransition(ctx, domain.InboxEventActionReplay, fromStatus, change, func(q *sqlc.Queries) ([]sqlc.OrderInboxEvent, error) {
+		event, err := q.ReplayInboxEvent(ctx, sqlc.ReplayInboxEventParams{
+			ID:         converter.StringToPgUUID(id),
+			FromStatus: sqlc.InboxEventStatus(fromStatus),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []sqlc.OrderInboxEvent{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+func (r *inboxEventRepository) ReplayFailedInboxEvents(ctx context.Context, filter domain.InboxEventReplayFilter, change domain.StatusChange) ([]*domain.InboxEvent, error) {
+	params := sqlc.ReplayFailedInboxEventsParams{
+		FromStatus:   sqlc.InboxEventStatus(filter.Status),
+		ReceivedFrom: converter.TimePtrToPgTime(filter.ReceivedFrom),
+		ReceivedTo:   converter.TimePtrToPgTime(filter.ReceivedTo),
+		BatchSize:    int32(filter.Limit),
+	}
+	if filter.EventType != "" {
+		params.EventType = pgtype.Text{String: filter.EventType, Valid: true}
+	}
+
+	return r.auditedTransition(ctx, domain.InboxEventActionReplay, filter.Status, change, func(q *sqlc.Queries) ([]sqlc.OrderInboxEvent, error) {
+		return q.ReplayFailedInboxEvents(ctx, params)
+	})
+}
+
+func (r *inboxEventRepository) DiscardInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error) {
+	events, err := r.auditedTransition(ctx, domain.InboxEventActionDiscard, fromStatus, change, func(q *sqlc.Queries) ([]sqlc.OrderInboxEvent, error) {
+		event, err := q.DiscardInboxEvent(ctx, sqlc.DiscardInboxEventParams{
+			ID:         converter.StringToPgUUID(id),
+			FromStatus: sqlc.InboxEventStatus(fromStatus),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []sqlc.OrderInboxEvent{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+// auditedTransition chạy update và ghi một dòng audit cho mỗi event bị thay đổi trong cùng transaction.
+// Update không trả về dòng nào (pgx.ErrNoRows) nghĩa là event không còn ở oldStatus.
+func (r *inboxEventRepository) auditedTransition(ctx context.Context, action string, oldStatus domain.InboxEventStatus, change domain.StatusChange, update func(q *sqlc.Queries) ([]sqlc.OrderInboxEvent, error)) ([]*domain.InboxEvent, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	results, err := update(qtx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInboxEventStatusChanged
+		}
+		return nil, fmt.Errorf("failed to %s inbox events: %w", strings.ToLower(action), err)
+	}
+
+	traceID := pgtype.Text{}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		traceID = pgtype.Text{String: spanCtx.TraceID().String(), Valid: true}
+	}
+
+	events := make([]*domain.InboxEvent, 0, len(results))
+	for i := range results {
+		if err := qtx.CreateInboxEventAudit(ctx, sqlc.CreateInboxEventAuditParams{
+			InboxEventID: results[i].ID,
+			EventID:      results[i].EventID,
+			Action:       action,
+			OldStatus:    sqlc.InboxEventStatus(oldStatus),
+			NewStatus:    results[i].EventStatus,
+			Actor:        change.Actor,
+			Reason:       change.Reason,
+			TraceID:      traceID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to audit %s of inbox event %s: %w", action, results[i].EventID, err)
+		}
+		events = append(events, inboxEventToDomain(&results[i]))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return events, nil
+}
+
 // Helper function to convert SQLC model to domain model
 func inboxEventToDomain(sqlcEvent *sqlc.OrderInboxEvent) *domain.InboxEvent {
 	if sqlcEvent == nil {
@@ -148,6 +347,8 @@ func inboxEventToDomain(sqlcEvent *sqlc.OrderInboxEvent) *domain.InboxEvent {
 		MaxRetry:      int(sqlcEvent.MaxRetry),
 		ReceivedAt:    *converter.PgTimeToTimePtr(sqlcEvent.ReceivedAt),
 		ProcessedAt:   converter.PgTimeToTimePtr(sqlcEvent.ProcessedAt),
+		LastError:     converter.PgTextToStringPtr(sqlcEvent.LastError),
+		EventVersion:  int(sqlcEvent.EventVersion),
 		CreatedAt:     *converter.PgTimeToTimePtr(sqlcEvent.CreatedAt),
 		UpdatedAt:     *converter.PgTimeToTimePtr(sqlcEvent.UpdatedAt),
 	}
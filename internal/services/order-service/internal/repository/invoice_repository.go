@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// InvoiceRepository lưu hoá đơn của đơn hàng. Mỗi đơn chỉ có một hoá đơn, số hoá đơn tăng dần liên tục theo shop.
+type InvoiceRepository interface {
+	GetByOrderID(ctx context.Context, orderID string) (*domain.Invoice, error)
+	IssueInvoice(ctx context.Context, invoice *domain.Invoice, numberPrefix string) (*domain.Invoice, error)
+}
+
+type invoiceRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewInvoiceRepository(db *postgresql_infra.PostgreSQLService) InvoiceRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &invoiceRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *invoiceRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Invoice, error) {
+	dbInvoice, err := r.queries.GetOrderInvoiceByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Invoice of order", orderID)
+		}
+		return nil, fmt.Errorf("failed to get invoice of order %s: %w", orderID, err)
+	}
+	return toDomainInvoice(&dbInvoice)
+}
+
+// IssueInvoice lấy số tiếp theo của shop và lưu hoá đơn trong cùng transaction. Nếu đơn hàng đã có hoá đơn
+// (hai request phát hành cùng lúc) thì transaction được rollback để không tiêu tốn số, và hoá đơn đã có được trả về.
+func (r *invoiceRepository) IssueInvoice(ctx context.Context, invoice *domain.Invoice, numberPrefix string) (*domain.Invoice, error) {
+	seller, err := json.Marshal(invoice.Seller)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal invoice seller: %w", err)
+	}
+	lines, err := json.Marshal(invoice.Lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal invoice lines: %w", err)
+	}
+
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	shopID := converter.StringToPgUUID(invoice.ShopID)
+
+	sequence, err := qtx.NextShopInvoiceNumber(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next invoice number of shop %s: %w", invoice.ShopID, err)
+	}
+
+	dbInvoice, err := qtx.CreateOrderInvoice(ctx, sqlc.CreateOrderInvoiceParams{
+		OrderID:        converter.StringToPgUUID(invoice.OrderID),
+		ShopID:         shopID,
+		SequenceNumber: sequence,
+		InvoiceNumber:  domain.FormatInvoiceNumber(numberPrefix, invoice.ShopID, sequence),
+		Currency:       invoice.Currency,
+		Subtotal:       converter.MoneyToPgNumeric(invoice.Subtotal),
+		DiscountAmount: converter.MoneyToPgNumeric(invoice.DiscountAmount),
+		ShippingFee:    converter.MoneyToPgNumeric(invoice.ShippingFee),
+		TaxRate:        converter.Float64ToPgNumeric(invoice.TaxRate),
+		TaxAmount:      converter.MoneyToPgNumeric(invoice.TaxAmount),
+		TotalAmount:    converter.MoneyToPgNumeric(invoice.TotalAmount),
+		Seller:         seller,
+		Lines:          lines,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			tx.Rollback(ctx)
+			return r.GetByOrderID(ctx, invoice.OrderID)
+		}
+		return nil, fmt.Errorf("failed to create invoice of order %s: %w", invoice.OrderID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainInvoice(&dbInvoice)
+}
+
+func toDomainInvoice(dbInvoice *sqlc.OrderInvoice) (*domain.Invoice, error) {
+	invoice := &domain.Invoice{
+		ID:             converter.PgUUIDToString(dbInvoice.ID),
+		OrderID:        converter.PgUUIDToString(dbInvoice.OrderID),
+		ShopID:         converter.PgUUIDToString(dbInvoice.ShopID),
+		SequenceNumber: dbInvoice.SequenceNumber,
+		InvoiceNumber:  dbInvoice.InvoiceNumber,
+		Currency:       dbInvoice.Currency,
+		Subtotal:       converter.PgNumericToMoney(dbInvoice.Subtotal, dbInvoice.Currency),
+		DiscountAmount: converter.PgNumericToMoney(dbInvoice.DiscountAmount, dbInvoice.Currency),
+		ShippingFee:    converter.PgNumericToMoney(dbInvoice.ShippingFee, dbInvoice.Currency),
+		TaxRate:        converter.PgNumericToFloat64(dbInvoice.TaxRate),
+		TaxAmount:      converter.PgNumericToMoney(dbInvoice.TaxAmount, dbInvoice.Currency),
+		TotalAmount:    converter.PgNumericToMoney(dbInvoice.TotalAmount, dbInvoice.Currency),
+		IssuedAt:       dbInvoice.IssuedAt.Time,
+	}
+	if err := json.Unmarshal(dbInvoice.Seller, &invoice.Seller); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal seller of invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	// Hoá đơn phát hành trước khi lưu dòng sản phẩm giữ Lines = nil để lấy dòng từ đơn hàng
+	if dbInvoice.Lines != nil {
+		if err := json.Unmarshal(dbInvoice.Lines, &invoice.Lines); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal lines of invoice %s: %w", invoice.InvoiceNumber, err)
+		}
+	}
+	return invoice, nil
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// UpdateOrderItemStatus chuyển dòng hàng itemID sang next rồi cập nhật trạng thái đơn theo các dòng hàng,
+// tất cả trong một transaction có khoá đơn hàng. Trả về domain.ErrOrderStatusChanged nếu đơn đã đổi trạng thái
+// so với order.Status.
+func (r *orderRepository) UpdateOrderItemStatus(ctx context.Context, order *domain.Order, itemID string, next domain.OrderItemStatus, change domain.StatusChange) (*domain.Order, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	lockedOrder, err := lockOrderWithItems(ctx, qtx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	item, ok := lockedOrder.FindItem(itemID)
+	if !ok {
+		return nil, domain.ErrOrderItemNotFound
+	}
+	if !item.Status.CanTransitionTo(next) {
+		return nil, domain.ErrInvalidItemStatusTransition
+	}
+
+	updatedItem, err := qtx.UpdateOrderItemStatusIfCurrent(ctx, sqlc.UpdateOrderItemStatusIfCurrentParams{
+		NewStatus:      sqlc.OrderItemStatus(next),
+		ID:             converter.StringToPgUUID(itemID),
+		OrderID:        converter.StringToPgUUID(order.ID),
+		ExpectedStatus: sqlc.OrderItemStatus(item.Status),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidItemStatusTransition
+		}
+		return nil, fmt.Errorf("failed to update status of item %s: %w", itemID, err)
+	}
+	*item = toDomainOrderItem(&updatedItem)
+
+	if err := syncOrderStatusWithItems(ctx, qtx, lockedOrder, "", change); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return lockedOrder, nil
+}
+
+// CancelOrderItem huỷ cancellation.Quantity sản phẩm của dòng hàng cancellation.OrderItemID và ghi lại lần huỷ
+// trong cùng transaction, thống kê bán hàng của shop được trừ phần bị huỷ. Huỷ hết các dòng hàng thì cả đơn
+// chuyển sang CANCELED (có ghi order_cancellations như khi huỷ cả đơn).
+func (r *orderRepository) CancelOrderItem(ctx context.Context, order *domain.Order, cancellation *domain.OrderItemCancellation, change domain.StatusChange) (*domain.Order, *domain.OrderItemCancellation, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	lockedOrder, err := lockOrderWithItems(ctx, qtx, order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	item, ok := lockedOrder.FindItem(cancellation.OrderItemID)
+	if !ok {
+		return nil, nil, domain.ErrOrderItemNotFound
+	}
+	if !item.Status.IsOpen() {
+		return nil, nil, domain.ErrInvalidItemStatusTransition
+	}
+
+	updatedItem, err := qtx.CancelOrderItemQuantity(ctx, sqlc.CancelOrderItemQuantityParams{
+		Quantity: int32(cancellation.Quantity),
+		ID:       converter.StringToPgUUID(item.ID),
+		OrderID:  converter.StringToPgUUID(order.ID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, domain.ErrItemCancelQuantityExceeded
+		}
+		return nil, nil, fmt.Errorf("failed to cancel item %s: %w", item.ID, err)
+	}
+	*item = toDomainOrderItem(&updatedItem)
+
+	dbCancellation, err := qtx.CreateOrderItemCancellation(ctx, sqlc.CreateOrderItemCancellationParams{
+		OrderID:      converter.StringToPgUUID(order.ID),
+		OrderItemID:  updatedItem.ID,
+		ProductID:    updatedItem.ProductID,
+		Quantity:     int32(cancellation.Quantity),
+		RefundAmount: converter.MoneyToPgNumeric(cancellation.RefundAmount),
+		Currency:     cancellation.RefundAmount.Currency,
+		Reason:       cancellation.Reason,
+		CanceledBy:   converter.StringToPgUUID(cancellation.CanceledBy),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record cancellation of item %s: %w", item.ID, err)
+	}
+
+	// Đơn đang PROCESSING/CONFIRMED đã được tính vào thống kê, trừ phần bị huỷ trước khi đơn (có thể) đổi trạng thái
+	if err := qtx.ApplyShopDailyStatsItemCancellation(ctx, dbCancellation.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to update shop stats of order %s: %w", order.ID, err)
+	}
+	if err := qtx.ApplyShopProductDailyStatsItemCancellation(ctx, dbCancellation.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to update shop product stats of order %s: %w", order.ID, err)
+	}
+
+	if err := syncOrderStatusWithItems(ctx, qtx, lockedOrder, cancellation.CanceledBy, change); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return lockedOrder, toDomainOrderItemCancellation(&dbCancellation), nil
+}
+
+func (r *orderRepository) GetItemCancellation(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error) {
+	cancellation, err := r.queries.GetOrderItemCancellationByID(ctx, converter.StringToPgUUID(cancellationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order item cancellation", cancellationID)
+		}
+		return nil, fmt.Errorf("failed to get item cancellation %s: %w", cancellationID, err)
+	}
+	return toDomainOrderItemCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) ListItemCancellations(ctx context.Context, orderID string) ([]*domain.OrderItemCancellation, error) {
+	dbCancellations, err := r.queries.ListOrderItemCancellationsByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list item cancellations of order %s: %w", orderID, err)
+	}
+
+	cancellations := make([]*domain.OrderItemCancellation, len(dbCancellations))
+	for i := range dbCancellations {
+		cancellations[i] = toDomainOrderItemCancellation(&dbCancellations[i])
+	}
+	return cancellations, nil
+}
+
+// MarkItemCancellationRestocked ghi nhận product-service đã nhận lại phần hàng bị huỷ.
+func (r *orderRepository) MarkItemCancellationRestocked(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error) {
+	return r.updateItemCancellation(ctx, cancellationID, "mark as restocked", func(id pgtype.UUID) (sqlc.OrderItemCancellation, error) {
+		return r.queries.MarkOrderItemCancellationRestocked(ctx, id)
+	})
+}
+
+// SetItemCancellationRefundID lưu ID lần hoàn tiền một phần mà payment-service đã tạo cho lần huỷ dòng hàng.
+func (r *orderRepository) SetItemCancellationRefundID(ctx context.Context, cancellationID string, refundID string) (*domain.OrderItemCancellation, error) {
+	return r.updateItemCancellation(ctx, cancellationID, "set refund id", func(id pgtype.UUID) (sqlc.OrderItemCancellation, error) {
+		return r.queries.SetOrderItemCancellationRefundID(ctx, sqlc.SetOrderItemCancellationRefundIDParams{
+			ID:       id,
+			RefundID: converter.StringToPgText(&refundID),
+		})
+	})
+}
+
+// MarkItemCancellationRefunded: payment-service đã hoàn tiền xong. Trả về domain.ErrItemCancellationAlreadyRefunded
+// nếu lần huỷ đã được ghi nhận hoàn tiền từ event trước (hoặc không tồn tại).
+func (r *orderRepository) MarkItemCancellationRefunded(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error) {
+	cancellation, err := r.queries.MarkOrderItemCancellationRefunded(ctx, converter.StringToPgUUID(cancellationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrItemCancellationAlreadyRefunded
+		}
+		return nil, fmt.Errorf("failed to mark as refunded item cancellation %s: %w", cancellationID, err)
+	}
+	return toDomainOrderItemCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) updateItemCancellation(ctx context.Context, cancellationID string, action string, update func(id pgtype.UUID) (sqlc.OrderItemCancellation, error)) (*domain.OrderItemCancellation, error) {
+	cancellation, err := update(converter.StringToPgUUID(cancellationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order item cancellation", cancellationID)
+		}
+		return nil, fmt.Errorf("failed to %s item cancellation %s: %w", action, cancellationID, err)
+	}
+	return toDomainOrderItemCancellation(&cancellation), nil
+}
+
+// lockOrderWithItems khoá đơn hàng tới hết transaction và đọc lại các dòng hàng. Trả về domain.ErrOrderStatusChanged
+// nếu trạng thái đơn đã khác order.Status hoặc đơn không còn ở giai đoạn người bán xử lý từng dòng hàng.
+func lockOrderWithItems(ctx context.Context, q *sqlc.Queries, order *domain.Order) (*domain.Order, error) {
+	dbOrder, err := q.GetOrderByIDForUpdate(ctx, converter.StringToPgUUID(order.ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order", order.ID)
+		}
+		return nil, fmt.Errorf("failed to lock order %s: %w", order.ID, err)
+	}
+
+	lockedOrder := toDomainOrder(&dbOrder)
+	if lockedOrder.Status != order.Status || !lockedOrder.IsItemFulfillmentOpen() {
+		return nil, domain.ErrOrderStatusChanged
+	}
+
+	dbItems, err := q.ListOrderItemsByOrderID(ctx, dbOrder.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items of order %s: %w", order.ID, err)
+	}
+	lockedOrder.Items = make([]domain.OrderItem, len(dbItems))
+	for i := range dbItems {
+		lockedOrder.Items[i] = toDomainOrderItem(&dbItems[i])
+	}
+	return lockedOrder, nil
+}
+
+// syncOrderStatusWithItems chuyển đơn sang trạng thái tính từ các dòng hàng (nếu khác trạng thái hiện tại)
+// trong transaction đang khoá đơn. Đơn bị huỷ vì hết dòng hàng thì ghi thêm order_cancellations như khi huỷ cả đơn.
+func syncOrderStatusWithItems(ctx context.Context, q *sqlc.Queries, order *domain.Order, canceledBy string, change domain.StatusChange) error {
+	next := order.DeriveStatusFromItems()
+	if next == order.Status {
+		return nil
+	}
+	if !order.Status.CanTransitionTo(next) {
+		return domain.ErrInvalidStatusTransition
+	}
+
+	previousStatus := sqlc.OrderStatus(order.Status)
+	updatedOrder, err := q.UpdateOrderStatusIfCurrent(ctx, sqlc.UpdateOrderStatusIfCurrentParams{
+		NewStatus:      sqlc.OrderStatus(next),
+		ID:             converter.StringToPgUUID(order.ID),
+		ExpectedStatus: previousStatus,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrOrderStatusChanged
+		}
+		return fmt.Errorf("failed to update status of order %s: %w", order.ID, err)
+	}
+
+	if next == domain.OrderStatusCANCELED {
+		if _, err := q.CreateOrderCancellation(ctx, sqlc.CreateOrderCancellationParams{
+			OrderID:        updatedOrder.ID,
+			CanceledBy:     converter.StringToPgUUID(canceledBy),
+			Reason:         change.Reason,
+			PreviousStatus: previousStatus,
+		}); err != nil {
+			return fmt.Errorf("failed to record cancellation of order %s: %w", order.ID, err)
+		}
+	}
+
+	if err := recordStatusChange(ctx, q, updatedOrder.ID, &previousStatus, updatedOrder.OrderStatus, change); err != nil {
+		return err
+	}
+	if err := recordStatusEvents(ctx, q, &updatedOrder, previousStatus, change); err != nil {
+		return err
+	}
+
+	items := order.Items
+	*order = *toDomainOrder(&updatedOrder)
+	order.Items = items
+	return nil
+}
+
+func toDomainOrderItemCancellation(dbCancellation *sqlc.OrderItemCancellation) *domain.OrderItemCancellation {
+	if dbCancellation == nil {
+		return nil
+	}
+
+	return &domain.OrderItemCancellation{
+		ID:           converter.PgUUIDToString(dbCancellation.ID),
+		OrderID:      converter.PgUUIDToString(dbCancellation.OrderID),
+		OrderItemID:  converter.PgUUIDToString(dbCancellation.OrderItemID),
+		ProductID:    converter.PgUUIDToString(dbCancellation.ProductID),
+		Quantity:     int(dbCancellation.Quantity),
+		RefundAmount: converter.PgNumericToMoney(dbCancellation.RefundAmount, dbCancellation.Currency),
+		Reason:       dbCancellation.Reason,
+		CanceledBy:   converter.PgUUIDToString(dbCancellation.CanceledBy),
+		Restocked:    dbCancellation.Restocked,
+		RefundID:     converter.PgTextToStringPtr(dbCancellation.RefundID),
+		RefundedAt:   converter.PgTimeToTimePtr(dbCancellation.RefundedAt),
+		CreatedAt:    dbCancellation.CreatedAt.Time,
+		UpdatedAt:    dbCancellation.UpdatedAt.Time,
+	}
+}
@@ -2,23 +2,61 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
-	UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus) (*domain.Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus, change domain.StatusChange) (*domain.Order, error)
 	GetStaleOrders(ctx context.Context, olderThan time.Time, limit int) ([]*domain.Order, error)
+	// GetExpiredPendingPaymentOrders trả về các đơn PENDING_PAYMENT đã quá thời gian chờ thanh toán của shop,
+	// defaultWindow áp dụng cho shop chưa cấu hình. Đơn trả về không kèm danh sách sản phẩm.
+	GetExpiredPendingPaymentOrders(ctx context.Context, defaultWindow time.Duration, limit int) ([]*domain.Order, error)
 	GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error)
+	// GetOrdersByIDs trả về các đơn hàng kèm sản phẩm theo danh sách id, id không tồn tại bị bỏ qua.
+	GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error)
+	ListOrdersByOwner(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error)
+	ListOrdersByShop(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error)
+	CancelOrder(ctx context.Context, order *domain.Order, canceledBy string, change domain.StatusChange) (*domain.Order, *domain.OrderCancellation, error)
+	GetOrderCancellation(ctx context.Context, orderID string) (*domain.OrderCancellation, error)
+	MarkCancellationRefundRequested(ctx context.Context, orderID string, refundID string) (*domain.OrderCancellation, error)
+	// RecordCancellationRefundFailure lưu lỗi yêu cầu hoàn tiền sau khi huỷ đơn để reconciler gửi lại.
+	RecordCancellationRefundFailure(ctx context.Context, orderID string, refundErr string) (*domain.OrderCancellation, error)
+	ClearCancellationRefundFailure(ctx context.Context, orderID string) (*domain.OrderCancellation, error)
+	// ListCancellationsForRefundRetry trả về các lần huỷ có yêu cầu hoàn tiền lỗi, chưa vượt quá maxAttempts lần gửi
+	// và lần gửi gần nhất trước updatedBefore, cũ nhất trước.
+	ListCancellationsForRefundRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.OrderCancellation, error)
+	// RecordStockReleaseFailure lưu lỗi trả tồn kho của đơn để reconciler trả lại, lỗi lặp lại tăng số lần thử.
+	RecordStockReleaseFailure(ctx context.Context, orderID string, releaseErr string) (*domain.StockReleaseFailure, error)
+	ClearStockReleaseFailure(ctx context.Context, orderID string) error
+	// ListStockReleaseFailuresForRetry trả về các đơn trả tồn kho bị lỗi, chưa vượt quá maxAttempts lần thử
+	// và lần thử gần nhất trước updatedBefore, cũ nhất trước.
+	ListStockReleaseFailuresForRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.StockReleaseFailure, error)
+	GetOrderStatusHistory(ctx context.Context, orderID string) ([]*domain.OrderStatusHistory, error)
+	// UpdateOrderItemStatus chuyển một dòng hàng sang next và cập nhật trạng thái đơn tính từ các dòng hàng.
+	UpdateOrderItemStatus(ctx context.Context, order *domain.Order, itemID string, next domain.OrderItemStatus, change domain.StatusChange) (*domain.Order, error)
+	// CancelOrderItem huỷ một phần hoặc toàn bộ dòng hàng, đơn bị huỷ theo khi không còn dòng hàng nào.
+	CancelOrderItem(ctx context.Context, order *domain.Order, cancellation *domain.OrderItemCancellation, change domain.StatusChange) (*domain.Order, *domain.OrderItemCancellation, error)
+	GetItemCancellation(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error)
+	ListItemCancellations(ctx context.Context, orderID string) ([]*domain.OrderItemCancellation, error)
+	MarkItemCancellationRestocked(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error)
+	SetItemCancellationRefundID(ctx context.Context, cancellationID string, refundID string) (*domain.OrderItemCancellation, error)
+	MarkItemCancellationRefunded(ctx context.Context, cancellationID string) (*domain.OrderItemCancellation, error)
 }
 
 type orderRepository struct {
@@ -54,15 +92,26 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 		OwnerID:           converter.StringToPgUUID(order.OwnerID),
 		ShopID:            converter.StringToPgUUID(order.ShopID),
 		ShippingAddressID: converter.StringToPgUUID(order.ShippingAddressID),
-		ShippingFee:       converter.Float64ToPgNumeric(order.ShippingFee),
-		DiscountAmount:    converter.Float64ToPgNumeric(order.DiscountAmount),
-		TotalAmount:       converter.Float64ToPgNumeric(order.TotalAmount),
-		FinalAmount:       converter.Float64ToPgNumeric(order.FinalPrice),
+		ShippingFee:       converter.MoneyToPgNumeric(order.ShippingFee),
+		DiscountAmount:    converter.MoneyToPgNumeric(order.DiscountAmount),
+		TotalAmount:       converter.MoneyToPgNumeric(order.TotalAmount),
+		FinalAmount:       converter.MoneyToPgNumeric(order.FinalPrice),
 		OrderStatus:       sqlc.OrderStatus(order.Status),
+		Currency:          order.Currency(),
 	}
 	if order.PromotionCode != nil {
 		orderParams.PromotionID = converter.StringToPgUUID(*order.PromotionCode)
 	}
+	if order.CheckoutID != nil {
+		orderParams.CheckoutID = converter.StringToPgUUID(*order.CheckoutID)
+	}
+	if order.ShippingAddress != nil {
+		shippingAddress, err := json.Marshal(order.ShippingAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal shipping address snapshot: %w", err)
+		}
+		orderParams.ShippingAddress = shippingAddress
+	}
 
 	createdOrder, err := qtx.CreateOrder(ctx, orderParams)
 	if err != nil {
@@ -73,11 +122,14 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 	createdItems := make([]domain.OrderItem, len(order.Items))
 	for i, item := range order.Items {
 		itemParams := sqlc.CreateOrderItemParams{
-			OrderID:   createdOrder.ID,
-			ProductID: converter.StringToPgUUID(item.ProductID),
-			ShopID:    converter.StringToPgUUID(order.ShopID), // All items belong to the same shop
-			Quantity:  int32(item.Quantity),
-			Price:     converter.Float64ToPgNumeric(item.Price),
+			OrderID:      createdOrder.ID,
+			ProductID:    converter.StringToPgUUID(item.ProductID),
+			ShopID:       converter.StringToPgUUID(order.ShopID), // All items belong to the same shop
+			Quantity:     int32(item.Quantity),
+			Price:        converter.MoneyToPgNumeric(item.Price),
+			ProductName:  item.ProductName,
+			ThumbnailUrl: item.ThumbnailURL,
+			Currency:     item.Price.Currency,
 		}
 		createdItem, err := qtx.CreateOrderItem(ctx, itemParams)
 		if err != nil {
@@ -86,7 +138,42 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 		createdItems[i] = toDomainOrderItem(&createdItem)
 	}
 
-	// Step 3: Commit transaction
+	// Step 3: Record the initial status in the order timeline
+	if err := recordStatusChange(ctx, qtx, createdOrder.ID, nil, createdOrder.OrderStatus, domain.StatusChange{
+		Actor:  domain.CustomerActor(order.OwnerID),
+		Reason: "order created",
+	}); err != nil {
+		return nil, err
+	}
+
+	// Step 4: Publish OrderCreated through the outbox
+	createdEvent := domain.OrderCreatedEvent{
+		OrderID:        converter.PgUUIDToString(createdOrder.ID),
+		OwnerID:        order.OwnerID,
+		ShopID:         order.ShopID,
+		CheckoutID:     order.CheckoutID,
+		Status:         domain.OrderStatus(createdOrder.OrderStatus),
+		ShippingFee:    order.ShippingFee.Float64(),
+		DiscountAmount: order.DiscountAmount.Float64(),
+		TotalAmount:    order.TotalAmount.Float64(),
+		FinalAmount:    order.FinalPrice.Float64(),
+		Currency:       order.Currency(),
+		Items:          make([]domain.OrderCreatedEventItem, len(createdItems)),
+	}
+	for i, item := range createdItems {
+		createdEvent.Items[i] = domain.OrderCreatedEventItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price.Float64(),
+			Currency:    item.Price.Currency,
+		}
+	}
+	if err := recordOutboxEvent(ctx, qtx, createdOrder.ID, constant.EventTypeOrderCreated, createdEvent); err != nil {
+		return nil, err
+	}
+
+	// Step 5: Commit transaction
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -97,27 +184,78 @@ func (r *orderRepository) CreateOrder(ctx context.Context, order *domain.Order)
 	return finalOrder, nil
 }
 
-func (r *orderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus) (*domain.Order, error) {
-	currentOrder, err := r.queries.GetOrderByID(ctx, converter.StringToPgUUID(orderID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get order: %w", err)
+// maxStatusUpdateAttempts giới hạn số lần đọc lại đơn hàng khi trạng thái bị thay đổi đồng thời.
+const maxStatusUpdateAttempts = 3
+
+// UpdateOrderStatus chuyển đơn hàng sang status theo bảng chuyển trạng thái của domain.
+// Việc cập nhật dùng UPDATE ... WHERE order_status = $expected nên không ghi đè thay đổi đồng thời,
+// và lịch sử trạng thái được ghi trong cùng transaction.
+func (r *orderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus, change domain.StatusChange) (*domain.Order, error) {
+	for attempt := 1; attempt <= maxStatusUpdateAttempts; attempt++ {
+		currentOrder, err := r.queries.GetOrderByID(ctx, converter.StringToPgUUID(orderID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.NewNotFound("Order", orderID)
+			}
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+
+		// IDEMPOTENCY CHECK
+		if currentOrder.OrderStatus == status {
+			log.Printf("Order %s is already in status %s. Idempotency check passed.", orderID, status)
+			return toDomainOrder(&currentOrder), nil
+		}
+
+		order := toDomainOrder(&currentOrder)
+		if err := order.TransitionTo(domain.OrderStatus(status)); err != nil {
+			log.Printf("Rejected status transition for order %s: %v", orderID, err)
+			return nil, err
+		}
+
+		updatedOrder, err := r.updateOrderStatusTx(ctx, currentOrder, status, change)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				log.Printf("Order %s status changed concurrently (attempt %d/%d). Retrying.", orderID, attempt, maxStatusUpdateAttempts)
+				continue
+			}
+			return nil, fmt.Errorf("failed to update order status: %w", err)
+		}
+		return toDomainOrder(updatedOrder), nil
 	}
 
-	// IDEMPOTENCY CHECK
-	if currentOrder.OrderStatus == status {
-		log.Printf("Order %s is already in status %s. Idempotency check passed.", orderID, status)
-		return toDomainOrder(&currentOrder), nil
+	return nil, domain.ErrOrderStatusChanged
+}
+
+func (r *orderRepository) updateOrderStatusTx(ctx context.Context, currentOrder sqlc.Order, status sqlc.OrderStatus, change domain.StatusChange) (*sqlc.Order, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
 	}
+	defer tx.Rollback(ctx)
 
-	// If status is not the same, update the order status
-	updatedOrder, err := r.queries.UpdateOrderStatus(ctx, sqlc.UpdateOrderStatusParams{
-		ID:          converter.StringToPgUUID(orderID),
-		OrderStatus: status,
+	qtx := r.queries.WithTx(tx)
+
+	updatedOrder, err := qtx.UpdateOrderStatusIfCurrent(ctx, sqlc.UpdateOrderStatusIfCurrentParams{
+		NewStatus:      status,
+		ID:             currentOrder.ID,
+		ExpectedStatus: currentOrder.OrderStatus,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order status: %w", err)
+		return nil, err
+	}
+
+	if err := recordStatusChange(ctx, qtx, currentOrder.ID, &currentOrder.OrderStatus, status, change); err != nil {
+		return nil, err
 	}
-	return toDomainOrder(&updatedOrder), nil
+
+	if err := recordStatusEvents(ctx, qtx, &updatedOrder, currentOrder.OrderStatus, change); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &updatedOrder, nil
 }
 
 func (r *orderRepository) GetStaleOrders(ctx context.Context, olderThan time.Time, limit int) ([]*domain.Order, error) {
@@ -146,8 +284,32 @@ func (r *orderRepository) GetStaleOrders(ctx context.Context, olderThan time.Tim
 	return domainOrders, nil
 }
 
+func (r *orderRepository) GetExpiredPendingPaymentOrders(ctx context.Context, defaultWindow time.Duration, limit int) ([]*domain.Order, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	if defaultWindow < time.Minute {
+		return nil, fmt.Errorf("default payment window must be at least one minute")
+	}
+
+	orders, err := r.queries.GetExpiredPendingPaymentOrders(ctx, sqlc.GetExpiredPendingPaymentOrdersParams{
+		DefaultWindowMinutes: int32(defaultWindow / time.Minute),
+		BatchSize:            int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired pending payment orders: %w", err)
+	}
+
+	domainOrders := make([]*domain.Order, len(orders))
+	for i, order := range orders {
+		domainOrders[i] = toDomainOrder(&order)
+	}
+	return domainOrders, nil
+}
+
 func (r *orderRepository) GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error) {
-	order, err := r.queries.GetOrderByID(ctx, converter.StringToPgUUID(orderID))
+	row, err := r.queries.GetOrderByIDWithItems(ctx, converter.StringToPgUUID(orderID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, apperror.NewNotFound("Order", orderID)
@@ -155,7 +317,541 @@ func (r *orderRepository) GetOrderByID(ctx context.Context, orderID string) (*do
 		return nil, fmt.Errorf("failed to get order by ID %s: %w", orderID, err)
 	}
 
-	return toDomainOrder(&order), nil
+	order := toDomainOrder(&sqlc.Order{
+		ID:                row.ID,
+		OwnerID:           row.OwnerID,
+		ShopID:            row.ShopID,
+		ShippingAddressID: row.ShippingAddressID,
+		PromotionID:       row.PromotionID,
+		ShippingFee:       row.ShippingFee,
+		DiscountAmount:    row.DiscountAmount,
+		TotalAmount:       row.TotalAmount,
+		FinalAmount:       row.FinalAmount,
+		OrderStatus:       row.OrderStatus,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		CheckoutID:        row.CheckoutID,
+		ShippingAddress:   row.ShippingAddress,
+		Currency:          row.Currency,
+	})
+
+	items, err := decodeAggregatedItems(row.Items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode items of order %s: %w", orderID, err)
+	}
+	order.Items = items
+
+	return order, nil
+}
+
+func (r *orderRepository) GetOrdersByIDs(ctx context.Context, orderIDs []string) ([]*domain.Order, error) {
+	ids := make([]pgtype.UUID, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		ids = append(ids, converter.StringToPgUUID(orderID))
+	}
+
+	rows, err := r.queries.GetOrdersByIDsWithItems(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders by IDs: %w", err)
+	}
+
+	orders := make([]*domain.Order, len(rows))
+	for i, row := range rows {
+		order := toDomainOrder(&sqlc.Order{
+			ID:                row.ID,
+			OwnerID:           row.OwnerID,
+			ShopID:            row.ShopID,
+			ShippingAddressID: row.ShippingAddressID,
+			PromotionID:       row.PromotionID,
+			ShippingFee:       row.ShippingFee,
+			DiscountAmount:    row.DiscountAmount,
+			TotalAmount:       row.TotalAmount,
+			FinalAmount:       row.FinalAmount,
+			OrderStatus:       row.OrderStatus,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			CheckoutID:        row.CheckoutID,
+			ShippingAddress:   row.ShippingAddress,
+			Currency:          row.Currency,
+		})
+
+		items, err := decodeAggregatedItems(row.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode items of order %s: %w", order.ID, err)
+		}
+		order.Items = items
+		orders[i] = order
+	}
+	return orders, nil
+}
+
+// CancelOrder chuyển đơn hàng sang CANCELED chỉ khi trạng thái hiện tại vẫn là order.Status,
+// đồng thời ghi lại lý do huỷ trong cùng một transaction. canceledBy là user id của khách hàng hoặc chủ shop,
+// để rỗng khi đơn bị hệ thống huỷ tự động.
+func (r *orderRepository) CancelOrder(ctx context.Context, order *domain.Order, canceledBy string, change domain.StatusChange) (*domain.Order, *domain.OrderCancellation, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	canceledOrder, err := qtx.UpdateOrderStatusIfCurrent(ctx, sqlc.UpdateOrderStatusIfCurrentParams{
+		NewStatus:      sqlc.OrderStatusCANCELED,
+		ID:             converter.StringToPgUUID(order.ID),
+		ExpectedStatus: sqlc.OrderStatus(order.Status),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, domain.ErrOrderStatusChanged
+		}
+		return nil, nil, fmt.Errorf("failed to cancel order %s: %w", order.ID, err)
+	}
+
+	cancellation, err := qtx.CreateOrderCancellation(ctx, sqlc.CreateOrderCancellationParams{
+		OrderID:        canceledOrder.ID,
+		CanceledBy:     converter.StringToPgUUID(canceledBy),
+		Reason:         change.Reason,
+		PreviousStatus: sqlc.OrderStatus(order.Status),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record cancellation of order %s: %w", order.ID, err)
+	}
+
+	previousStatus := sqlc.OrderStatus(order.Status)
+	if err := recordStatusChange(ctx, qtx, canceledOrder.ID, &previousStatus, canceledOrder.OrderStatus, change); err != nil {
+		return nil, nil, err
+	}
+
+	if err := recordStatusEvents(ctx, qtx, &canceledOrder, previousStatus, change); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	finalOrder := toDomainOrder(&canceledOrder)
+	finalOrder.Items = order.Items
+	return finalOrder, toDomainOrderCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) GetOrderCancellation(ctx context.Context, orderID string) (*domain.OrderCancellation, error) {
+	cancellation, err := r.queries.GetOrderCancellationByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order cancellation", orderID)
+		}
+		return nil, fmt.Errorf("failed to get cancellation of order %s: %w", orderID, err)
+	}
+	return toDomainOrderCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) MarkCancellationRefundRequested(ctx context.Context, orderID string, refundID string) (*domain.OrderCancellation, error) {
+	params := sqlc.UpdateOrderCancellationRefundParams{
+		OrderID:         converter.StringToPgUUID(orderID),
+		RefundRequested: true,
+	}
+	if refundID != "" {
+		params.RefundID = converter.StringToPgUUID(refundID)
+	}
+
+	cancellation, err := r.queries.UpdateOrderCancellationRefund(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order cancellation", orderID)
+		}
+		return nil, fmt.Errorf("failed to update cancellation refund of order %s: %w", orderID, err)
+	}
+	return toDomainOrderCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) RecordCancellationRefundFailure(ctx context.Context, orderID string, refundErr string) (*domain.OrderCancellation, error) {
+	cancellation, err := r.queries.RecordOrderCancellationRefundFailure(ctx, sqlc.RecordOrderCancellationRefundFailureParams{
+		OrderID:     converter.StringToPgUUID(orderID),
+		RefundError: converter.StringToPgText(&refundErr),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order cancellation", orderID)
+		}
+		return nil, fmt.Errorf("failed to record refund failure of order %s: %w", orderID, err)
+	}
+	return toDomainOrderCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) ClearCancellationRefundFailure(ctx context.Context, orderID string) (*domain.OrderCancellation, error) {
+	cancellation, err := r.queries.ClearOrderCancellationRefundFailure(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order cancellation", orderID)
+		}
+		return nil, fmt.Errorf("failed to clear refund failure of order %s: %w", orderID, err)
+	}
+	return toDomainOrderCancellation(&cancellation), nil
+}
+
+func (r *orderRepository) ListCancellationsForRefundRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.OrderCancellation, error) {
+	results, err := r.queries.ListOrderCancellationsForRefundRetry(ctx, sqlc.ListOrderCancellationsForRefundRetryParams{
+		MaxAttempts:   int32(maxAttempts),
+		UpdatedBefore: converter.TimeToPgTime(updatedBefore),
+		BatchSize:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cancellations for refund retry: %w", err)
+	}
+
+	cancellations := make([]*domain.OrderCancellation, 0, len(results))
+	for i := range results {
+		cancellations = append(cancellations, toDomainOrderCancellation(&results[i]))
+	}
+	return cancellations, nil
+}
+
+func (r *orderRepository) RecordStockReleaseFailure(ctx context.Context, orderID string, releaseErr string) (*domain.StockReleaseFailure, error) {
+	failure, err := r.queries.RecordOrderStockReleaseFailure(ctx, sqlc.RecordOrderStockReleaseFailureParams{
+		OrderID:   converter.StringToPgUUID(orderID),
+		LastError: releaseErr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record stock release failure of order %s: %w", orderID, err)
+	}
+	return toDomainStockReleaseFailure(&failure), nil
+}
+
+func (r *orderRepository) ClearStockReleaseFailure(ctx context.Context, orderID string) error {
+	if err := r.queries.DeleteOrderStockReleaseFailure(ctx, converter.StringToPgUUID(orderID)); err != nil {
+		return fmt.Errorf("failed to clear stock release failure of order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+func (r *orderRepository) ListStockReleaseFailuresForRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.StockReleaseFailure, error) {
+	results, err := r.queries.ListOrderStockReleaseFailuresForRetry(ctx, sqlc.ListOrderStockReleaseFailuresForRetryParams{
+		MaxAttempts:   int32(maxAttempts),
+		UpdatedBefore: converter.TimeToPgTime(updatedBefore),
+		BatchSize:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock release failures for retry: %w", err)
+	}
+
+	failures := make([]*domain.StockReleaseFailure, 0, len(results))
+	for i := range results {
+		failures = append(failures, toDomainStockReleaseFailure(&results[i]))
+	}
+	return failures, nil
+}
+
+func (r *orderRepository) GetOrderStatusHistory(ctx context.Context, orderID string) ([]*domain.OrderStatusHistory, error) {
+	entries, err := r.queries.ListOrderStatusHistoryByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history of order %s: %w", orderID, err)
+	}
+
+	history := make([]*domain.OrderStatusHistory, 0, len(entries))
+	for i := range entries {
+		history = append(history, toDomainOrderStatusHistory(&entries[i]))
+	}
+	return history, nil
+}
+
+// recordStatusChange ghi một dòng vào order_status_history, trace id lấy từ span hiện tại trong ctx,
+// đồng thời cập nhật thống kê bán hàng của shop và trạng thái các dòng hàng còn mở cho lần chuyển trạng thái này.
+func recordStatusChange(ctx context.Context, q *sqlc.Queries, orderID pgtype.UUID, oldStatus *sqlc.OrderStatus, newStatus sqlc.OrderStatus, change domain.StatusChange) error {
+	params := sqlc.CreateOrderStatusHistoryParams{
+		OrderID:   orderID,
+		NewStatus: newStatus,
+		Actor:     change.Actor,
+		Reason:    change.Reason,
+	}
+	if params.Actor == "" {
+		params.Actor = domain.StatusActorOrderService
+	}
+	if oldStatus != nil {
+		params.OldStatus = sqlc.NullOrderStatus{OrderStatus: *oldStatus, Valid: true}
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.HasTraceID() {
+		params.TraceID = pgtype.Text{String: spanCtx.TraceID().String(), Valid: true}
+	}
+
+	if _, err := q.CreateOrderStatusHistory(ctx, params); err != nil {
+		return fmt.Errorf("failed to record status history of order %s: %w", converter.PgUUIDToString(orderID), err)
+	}
+	if err := recordShopStats(ctx, q, orderID, oldStatus, newStatus); err != nil {
+		return err
+	}
+
+	itemStatus, ok := domain.ItemStatusForOrderStatus(domain.OrderStatus(newStatus))
+	if !ok {
+		return nil
+	}
+	if err := q.UpdateOpenOrderItemsStatus(ctx, sqlc.UpdateOpenOrderItemsStatusParams{
+		OrderID:    orderID,
+		ItemStatus: sqlc.OrderItemStatus(itemStatus),
+	}); err != nil {
+		return fmt.Errorf("failed to update item status of order %s: %w", converter.PgUUIDToString(orderID), err)
+	}
+	return nil
+}
+
+// recordStatusEvents ghi outbox event OrderStatusChanged cho một lần đổi trạng thái,
+// kèm OrderCanceled nếu đơn hàng chuyển sang CANCELED.
+func recordStatusEvents(ctx context.Context, q *sqlc.Queries, order *sqlc.Order, oldStatus sqlc.OrderStatus, change domain.StatusChange) error {
+	actor := change.Actor
+	if actor == "" {
+		actor = domain.StatusActorOrderService
+	}
+
+	orderID := converter.PgUUIDToString(order.ID)
+	ownerID := converter.PgUUIDToString(order.OwnerID)
+	shopID := converter.PgUUIDToString(order.ShopID)
+
+	if err := recordOutboxEvent(ctx, q, order.ID, constant.EventTypeOrderStatusChanged, domain.OrderStatusChangedEvent{
+		OrderID:   orderID,
+		OwnerID:   ownerID,
+		ShopID:    shopID,
+		OldStatus: domain.OrderStatus(oldStatus),
+		NewStatus: domain.OrderStatus(order.OrderStatus),
+		Actor:     actor,
+		Reason:    change.Reason,
+	}); err != nil {
+		return err
+	}
+
+	if order.OrderStatus != sqlc.OrderStatusCANCELED {
+		return nil
+	}
+	return recordOutboxEvent(ctx, q, order.ID, constant.EventTypeOrderCanceled, domain.OrderCanceledEvent{
+		OrderID:        orderID,
+		OwnerID:        ownerID,
+		ShopID:         shopID,
+		PreviousStatus: domain.OrderStatus(oldStatus),
+		Actor:          actor,
+		Reason:         change.Reason,
+	})
+}
+
+// recordOutboxEvent ghi một domain event vào order_outbox_events, relay worker sẽ publish lên Kafka sau khi transaction commit.
+func recordOutboxEvent(ctx context.Context, q *sqlc.Queries, orderID pgtype.UUID, eventType constant.EventType, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event of order %s: %w", eventType, converter.PgUUIDToString(orderID), err)
+	}
+
+	if _, err := q.CreateOrderOutboxEvent(ctx, sqlc.CreateOrderOutboxEventParams{
+		OrderID:   orderID,
+		EventType: string(eventType),
+		Payload:   payload,
+	}); err != nil {
+		return fmt.Errorf("failed to record %s event of order %s: %w", eventType, converter.PgUUIDToString(orderID), err)
+	}
+	return nil
+}
+
+func (r *orderRepository) ListOrdersByOwner(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	if filter.Limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	// Lấy dư 1 bản ghi để biết còn trang tiếp theo hay không
+	params := sqlc.GetOrdersByUserIDWithItemsParams{
+		OwnerID:  converter.StringToPgUUID(filter.OwnerID),
+		PageSize: int32(filter.Limit + 1),
+	}
+	if filter.Status != nil {
+		params.Status = sqlc.NullOrderStatus{OrderStatus: sqlc.OrderStatus(*filter.Status), Valid: true}
+	}
+	if filter.CreatedFrom != nil {
+		params.CreatedFrom = converter.TimeToPgTime(*filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		params.CreatedTo = converter.TimeToPgTime(*filter.CreatedTo)
+	}
+	if filter.Cursor != nil {
+		params.CursorCreatedAt = converter.TimeToPgTime(filter.Cursor.CreatedAt)
+		params.CursorID = converter.StringToPgUUID(filter.Cursor.ID)
+	}
+
+	rows, err := r.queries.GetOrdersByUserIDWithItems(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders of owner %s: %w", filter.OwnerID, err)
+	}
+
+	page := &domain.OrderPage{}
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = &domain.OrderCursor{
+			CreatedAt: last.CreatedAt.Time,
+			ID:        converter.PgUUIDToString(last.ID),
+		}
+	}
+
+	page.Orders = make([]*domain.Order, len(rows))
+	for i, row := range rows {
+		order := toDomainOrder(&sqlc.Order{
+			ID:                row.ID,
+			OwnerID:           row.OwnerID,
+			ShopID:            row.ShopID,
+			ShippingAddressID: row.ShippingAddressID,
+			PromotionID:       row.PromotionID,
+			ShippingFee:       row.ShippingFee,
+			DiscountAmount:    row.DiscountAmount,
+			TotalAmount:       row.TotalAmount,
+			FinalAmount:       row.FinalAmount,
+			OrderStatus:       row.OrderStatus,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			CheckoutID:        row.CheckoutID,
+			ShippingAddress:   row.ShippingAddress,
+			Currency:          row.Currency,
+		})
+
+		items, err := decodeAggregatedItems(row.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode items of order %s: %w", order.ID, err)
+		}
+		order.Items = items
+		page.Orders[i] = order
+	}
+
+	return page, nil
+}
+
+// ListOrdersByShop liệt kê đơn hàng của một shop cho người bán, dùng cùng cách phân trang keyset với ListOrdersByOwner.
+func (r *orderRepository) ListOrdersByShop(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	if filter.Limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	// Lấy dư 1 bản ghi để biết còn trang tiếp theo hay không
+	params := sqlc.GetOrdersByShopIDWithItemsParams{
+		ShopID:   converter.StringToPgUUID(filter.ShopID),
+		PageSize: int32(filter.Limit + 1),
+	}
+	if filter.Status != nil {
+		params.Status = sqlc.NullOrderStatus{OrderStatus: sqlc.OrderStatus(*filter.Status), Valid: true}
+	}
+	if filter.CreatedFrom != nil {
+		params.CreatedFrom = converter.TimeToPgTime(*filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		params.CreatedTo = converter.TimeToPgTime(*filter.CreatedTo)
+	}
+	if filter.Cursor != nil {
+		params.CursorCreatedAt = converter.TimeToPgTime(filter.Cursor.CreatedAt)
+		params.CursorID = converter.StringToPgUUID(filter.Cursor.ID)
+	}
+
+	rows, err := r.queries.GetOrdersByShopIDWithItems(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders of shop %s: %w", filter.ShopID, err)
+	}
+
+	page := &domain.OrderPage{}
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = &domain.OrderCursor{
+			CreatedAt: last.CreatedAt.Time,
+			ID:        converter.PgUUIDToString(last.ID),
+		}
+	}
+
+	page.Orders = make([]*domain.Order, len(rows))
+	for i, row := range rows {
+		order := toDomainOrder(&sqlc.Order{
+			ID:                row.ID,
+			OwnerID:           row.OwnerID,
+			ShopID:            row.ShopID,
+			ShippingAddressID: row.ShippingAddressID,
+			PromotionID:       row.PromotionID,
+			ShippingFee:       row.ShippingFee,
+			DiscountAmount:    row.DiscountAmount,
+			TotalAmount:       row.TotalAmount,
+			FinalAmount:       row.FinalAmount,
+			OrderStatus:       row.OrderStatus,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			CheckoutID:        row.CheckoutID,
+			ShippingAddress:   row.ShippingAddress,
+			Currency:          row.Currency,
+		})
+
+		items, err := decodeAggregatedItems(row.Items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode items of order %s: %w", order.ID, err)
+		}
+		order.Items = items
+		page.Orders[i] = order
+	}
+
+	return page, nil
+}
+
+// aggregatedOrderItem khớp với các cột của order_items khi được json_agg trong query.
+type aggregatedOrderItem struct {
+	ID               string      `json:"id"`
+	OrderID          string      `json:"order_id"`
+	ProductID        string      `json:"product_id"`
+	Quantity         int         `json:"quantity"`
+	Price            json.Number `json:"price"`
+	CreatedAt        string      `json:"created_at"`
+	UpdatedAt        string      `json:"updated_at"`
+	ProductName      string      `json:"product_name"`
+	ThumbnailURL     string      `json:"thumbnail_url"`
+	Currency         string      `json:"currency"`
+	ItemStatus       string      `json:"item_status"`
+	CanceledQuantity int         `json:"canceled_quantity"`
+}
+
+// decodeAggregatedItems chuyển cột items (json_agg) thành danh sách domain.OrderItem.
+// pgx có thể trả về []byte, string hoặc giá trị đã được decode tuỳ vào kiểu cột.
+func decodeAggregatedItems(raw interface{}) ([]domain.OrderItem, error) {
+	var data []byte
+	switch v := raw.(type) {
+	case nil:
+		return []domain.OrderItem{}, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		data = encoded
+	}
+
+	var dbItems []aggregatedOrderItem
+	if err := json.Unmarshal(data, &dbItems); err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.OrderItem, len(dbItems))
+	for i, item := range dbItems {
+		price, err := money.Parse(item.Price.String(), item.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q for item %s: %w", item.Price, item.ID, err)
+		}
+		items[i] = domain.OrderItem{
+			ID:               item.ID,
+			OrderID:          item.OrderID,
+			ProductID:        item.ProductID,
+			ProductName:      item.ProductName,
+			ThumbnailURL:     item.ThumbnailURL,
+			Quantity:         item.Quantity,
+			Price:            price,
+			Status:           domain.OrderItemStatus(item.ItemStatus),
+			CanceledQuantity: item.CanceledQuantity,
+			CreatedAt:        item.CreatedAt,
+			UpdatedAt:        item.UpdatedAt,
+		}
+	}
+	return items, nil
 }
 
 func toDomainOrderItem(dbItem *sqlc.OrderItem) domain.OrderItem {
@@ -164,9 +860,17 @@ func toDomainOrderItem(dbItem *sqlc.OrderItem) domain.OrderItem {
 	}
 
 	return domain.OrderItem{
-		ProductID: converter.PgUUIDToString(dbItem.ProductID),
-		Quantity:  int(dbItem.Quantity),
-		Price:     converter.PgNumericToFloat64(dbItem.Price),
+		ID:               converter.PgUUIDToString(dbItem.ID),
+		OrderID:          converter.PgUUIDToString(dbItem.OrderID),
+		ProductID:        converter.PgUUIDToString(dbItem.ProductID),
+		ProductName:      dbItem.ProductName,
+		ThumbnailURL:     dbItem.ThumbnailUrl,
+		Quantity:         int(dbItem.Quantity),
+		Price:            converter.PgNumericToMoney(dbItem.Price, dbItem.Currency),
+		Status:           domain.OrderItemStatus(dbItem.ItemStatus),
+		CanceledQuantity: int(dbItem.CanceledQuantity),
+		CreatedAt:        converter.PgTimeToString(dbItem.CreatedAt),
+		UpdatedAt:        converter.PgTimeToString(dbItem.UpdatedAt),
 	}
 }
 
@@ -177,16 +881,86 @@ func toDomainOrder(dbOrder *sqlc.Order) *domain.Order {
 
 	promotionCode := converter.PgUUIDToString(dbOrder.PromotionID)
 
-	return &domain.Order{
+	order := &domain.Order{
 		ID:                converter.PgUUIDToString(dbOrder.ID),
 		OwnerID:           converter.PgUUIDToString(dbOrder.OwnerID),
 		ShopID:            converter.PgUUIDToString(dbOrder.ShopID),
 		ShippingAddressID: converter.PgUUIDToString(dbOrder.ShippingAddressID),
 		PromotionCode:     &promotionCode,
-		ShippingFee:       converter.PgNumericToFloat64(dbOrder.ShippingFee),
-		DiscountAmount:    converter.PgNumericToFloat64(dbOrder.DiscountAmount),
-		TotalAmount:       converter.PgNumericToFloat64(dbOrder.TotalAmount),
-		FinalPrice:        converter.PgNumericToFloat64(dbOrder.FinalAmount),
+		ShippingFee:       converter.PgNumericToMoney(dbOrder.ShippingFee, dbOrder.Currency),
+		DiscountAmount:    converter.PgNumericToMoney(dbOrder.DiscountAmount, dbOrder.Currency),
+		TotalAmount:       converter.PgNumericToMoney(dbOrder.TotalAmount, dbOrder.Currency),
+		FinalPrice:        converter.PgNumericToMoney(dbOrder.FinalAmount, dbOrder.Currency),
 		Status:            domain.OrderStatus(dbOrder.OrderStatus),
+		CreatedAt:         converter.PgTimeToString(dbOrder.CreatedAt),
+		UpdatedAt:         converter.PgTimeToString(dbOrder.UpdatedAt),
+	}
+	if dbOrder.CheckoutID.Valid {
+		checkoutID := converter.PgUUIDToString(dbOrder.CheckoutID)
+		order.CheckoutID = &checkoutID
+	}
+	// Đơn hàng tạo trước khi có snapshot không có shipping_address
+	if len(dbOrder.ShippingAddress) > 0 {
+		var shippingAddress domain.ShippingAddress
+		if err := json.Unmarshal(dbOrder.ShippingAddress, &shippingAddress); err != nil {
+			log.Printf("Failed to decode shipping address snapshot of order %s: %v", order.ID, err)
+		} else {
+			order.ShippingAddress = &shippingAddress
+		}
+	}
+	return order
+}
+
+func toDomainStockReleaseFailure(dbFailure *sqlc.OrderStockReleaseFailure) *domain.StockReleaseFailure {
+	return &domain.StockReleaseFailure{
+		OrderID:   converter.PgUUIDToString(dbFailure.OrderID),
+		LastError: dbFailure.LastError,
+		Attempts:  int(dbFailure.Attempts),
+		CreatedAt: converter.PgTimeToString(dbFailure.CreatedAt),
+		UpdatedAt: converter.PgTimeToString(dbFailure.UpdatedAt),
+	}
+}
+
+func toDomainOrderCancellation(dbCancellation *sqlc.OrderCancellation) *domain.OrderCancellation {
+	if dbCancellation == nil {
+		return nil
+	}
+
+	cancellation := &domain.OrderCancellation{
+		OrderID:         converter.PgUUIDToString(dbCancellation.OrderID),
+		CanceledBy:      converter.PgUUIDToString(dbCancellation.CanceledBy),
+		Reason:          dbCancellation.Reason,
+		PreviousStatus:  domain.OrderStatus(dbCancellation.PreviousStatus),
+		RefundRequested: dbCancellation.RefundRequested,
+		RefundError:     converter.PgTextToStringPtr(dbCancellation.RefundError),
+		RefundAttempts:  int(dbCancellation.RefundAttempts),
+		CreatedAt:       converter.PgTimeToString(dbCancellation.CreatedAt),
+		UpdatedAt:       converter.PgTimeToString(dbCancellation.UpdatedAt),
+	}
+	if dbCancellation.RefundID.Valid {
+		refundID := converter.PgUUIDToString(dbCancellation.RefundID)
+		cancellation.RefundID = &refundID
+	}
+	return cancellation
+}
+
+func toDomainOrderStatusHistory(dbEntry *sqlc.OrderStatusHistory) *domain.OrderStatusHistory {
+	if dbEntry == nil {
+		return nil
+	}
+
+	entry := &domain.OrderStatusHistory{
+		ID:        dbEntry.ID,
+		OrderID:   converter.PgUUIDToString(dbEntry.OrderID),
+		NewStatus: domain.OrderStatus(dbEntry.NewStatus),
+		Actor:     dbEntry.Actor,
+		Reason:    dbEntry.Reason,
+		TraceID:   dbEntry.TraceID.String,
+		CreatedAt: converter.PgTimeToString(dbEntry.CreatedAt),
+	}
+	if dbEntry.OldStatus.Valid {
+		oldStatus := domain.OrderStatus(dbEntry.OldStatus.OrderStatus)
+		entry.OldStatus = &oldStatus
 	}
+	return entry
 }
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+const aggregatedItemsJSON = `[{"id":"item-1","order_id":"order-1","product_id":"product-1","quantity":2,"price":12.50,` +
+	`"created_at":"2025-03-01T08:30:15Z","updated_at":"2025-03-01T08:30:15Z","product_name":"Tea","thumbnail_url":"tea.png",` +
+	`"currency":"USD","item_status":"PACKED","canceled_quantity":1}]`
+
+func TestDecodeAggregatedItems(t *testing.T) {
+	var decoded []interface{}
+	if err := json.Unmarshal([]byte(aggregatedItemsJSON), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		raw  interface{}
+	}{
+		{name: "Bytes", raw: []byte(aggregatedItemsJSON)},
+		{name: "String", raw: aggregatedItemsJSON},
+		{name: "Already decoded by pgx", raw: decoded},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := decodeAggregatedItems(tc.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != 1 {
+				t.Fatalf("items = %+v, want one item", items)
+			}
+
+			item := items[0]
+			if item.ID != "item-1" || item.ProductID != "product-1" || item.ProductName != "Tea" || item.ThumbnailURL != "tea.png" {
+				t.Errorf("item = %+v, want item-1 of product-1", item)
+			}
+			if item.Quantity != 2 || item.CanceledQuantity != 1 || item.Status != domain.OrderItemStatusPacked {
+				t.Errorf("item = %+v, want 2 PACKED with 1 canceled", item)
+			}
+			if want := money.New(1250, "USD"); !item.Price.Equal(want) {
+				t.Errorf("price = %s, want %s", item.Price, want)
+			}
+		})
+	}
+}
+
+func TestDecodeAggregatedItems_Empty(t *testing.T) {
+	items, err := decodeAggregatedItems(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Đơn không có dòng hàng vẫn trả về mảng rỗng để response JSON là [] thay vì null
+	if items == nil || len(items) != 0 {
+		t.Errorf("items = %#v, want empty slice", items)
+	}
+}
+
+func TestDecodeAggregatedItems_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		raw  interface{}
+	}{
+		{name: "Not JSON", raw: "items"},
+		{name: "Invalid price", raw: `[{"id":"item-1","price":"abc","currency":"VND"}]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := decodeAggregatedItems(tc.raw); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestToDomainOrderStatusHistory(t *testing.T) {
+	orderID := "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"
+	createdAt := time.Date(2025, 3, 1, 8, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		name              string
+		entry             sqlc.OrderStatusHistory
+		expectedOldStatus *domain.OrderStatus
+		expectedTraceID   string
+	}{
+		{
+			name: "First entry has no old status",
+			entry: sqlc.OrderStatusHistory{
+				ID:        1,
+				OrderID:   converter.StringToPgUUID(orderID),
+				NewStatus: sqlc.OrderStatusPENDING,
+				Actor:     domain.StatusActorOrderService,
+				CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+			},
+		},
+		{
+			name: "Transition keeps old status and trace id",
+			entry: sqlc.OrderStatusHistory{
+				ID:        2,
+				OrderID:   converter.StringToPgUUID(orderID),
+				OldStatus: sqlc.NullOrderStatus{OrderStatus: sqlc.OrderStatusPENDING, Valid: true},
+				NewStatus: sqlc.OrderStatusPENDINGPAYMENT,
+				Actor:     domain.StatusActorOrderService,
+				Reason:    "product stock reserved",
+				TraceID:   pgtype.Text{String: "4bf92f3577b34da6a3ce929d0e0e4736", Valid: true},
+				CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+			},
+			expectedOldStatus: statusPtr(domain.OrderStatusPENDING),
+			expectedTraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := toDomainOrderStatusHistory(&tc.entry)
+
+			if entry.ID != tc.entry.ID || entry.OrderID != orderID || entry.NewStatus != domain.OrderStatus(tc.entry.NewStatus) {
+				t.Errorf("entry = %+v, want %d of order %s to %s", entry, tc.entry.ID, orderID, tc.entry.NewStatus)
+			}
+			if (entry.OldStatus == nil) != (tc.expectedOldStatus == nil) || entry.OldStatus != nil && *entry.OldStatus != *tc.expectedOldStatus {
+				t.Errorf("old status = %v, want %v", entry.OldStatus, tc.expectedOldStatus)
+			}
+			if entry.Actor != tc.entry.Actor || entry.Reason != tc.entry.Reason || entry.TraceID != tc.expectedTraceID {
+				t.Errorf("entry = %+v, want actor %s, reason %q, trace %q", entry, tc.entry.Actor, tc.entry.Reason, tc.expectedTraceID)
+			}
+			if entry.CreatedAt != "2025-03-01T08:30:15Z" {
+				t.Errorf("created at = %s, want 2025-03-01T08:30:15Z", entry.CreatedAt)
+			}
+		})
+	}
+}
+
+func statusPtr(status domain.OrderStatus) *domain.OrderStatus {
+	return &status
+}
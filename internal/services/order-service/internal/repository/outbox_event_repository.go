@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// OutboxEventRepository đọc và cập nhật trạng thái outbox event cho relay worker.
+// Việc ghi event được thực hiện trong transaction của OrderRepository.
+type OutboxEventRepository interface {
+	// GetPendingOutboxEvents trả về các event PENDING theo seq, bỏ qua các event xếp sau một event FAILED của cùng đơn hàng.
+	GetPendingOutboxEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	UpdateOutboxEventStatus(ctx context.Context, event *domain.OutboxEvent) (*domain.OutboxEvent, error)
+	// RetryFailedOutboxEvent đưa event FAILED về PENDING; event ở trạng thái khác trả về domain.ErrOutboxEventNotFailed.
+	RetryFailedOutboxEvent(ctx context.Context, id string) (*domain.OutboxEvent, error)
+}
+
+type outboxEventRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewOutboxEventRepository(db *postgresql_infra.PostgreSQLService) OutboxEventRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &outboxEventRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *outboxEventRepository) GetPendingOutboxEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	results, err := r.queries.GetPendingOrderOutboxEvents(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.OutboxEvent, 0, len(results))
+	for _, result := range results {
+		events = append(events, outboxEventToDomain(&result))
+	}
+
+	return events, nil
+}
+
+func (r *outboxEventRepository) UpdateOutboxEventStatus(ctx context.Context, event *domain.OutboxEvent) (*domain.OutboxEvent, error) {
+	params := sqlc.UpdateOrderOutboxEventStatusParams{
+		ID:          converter.StringToPgUUID(event.ID),
+		EventStatus: sqlc.OutboxEventStatus(event.EventStatus),
+		RetryCount:  int32(event.RetryCount),
+	}
+
+	result, err := r.queries.UpdateOrderOutboxEventStatus(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return outboxEventToDomain(&result), nil
+}
+
+func (r *outboxEventRepository) RetryFailedOutboxEvent(ctx context.Context, id string) (*domain.OutboxEvent, error) {
+	result, err := r.queries.RetryFailedOrderOutboxEvent(ctx, converter.StringToPgUUID(id))
+	if err == nil {
+		return outboxEventToDomain(&result), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to retry outbox event %s: %w", id, err)
+	}
+
+	// Không có dòng nào được cập nhật: event không tồn tại hoặc không ở trạng thái FAILED
+	current, err := r.queries.GetOrderOutboxEventByID(ctx, converter.StringToPgUUID(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Outbox event", id)
+		}
+		return nil, fmt.Errorf("failed to get outbox event %s: %w", id, err)
+	}
+	return outboxEventToDomain(&current), domain.ErrOutboxEventNotFailed
+}
+
+func outboxEventToDomain(sqlcEvent *sqlc.OrderOutboxEvent) *domain.OutboxEvent {
+	if sqlcEvent == nil {
+		return nil
+	}
+
+	return &domain.OutboxEvent{
+		ID:          converter.PgUUIDToString(sqlcEvent.ID),
+		OrderID:     converter.PgUUIDToString(sqlcEvent.OrderID),
+		EventType:   sqlcEvent.EventType,
+		Payload:     string(sqlcEvent.Payload),
+		EventStatus: domain.OutboxEventStatus(sqlcEvent.EventStatus),
+		RetryCount:  int(sqlcEvent.RetryCount),
+		PublishedAt: converter.PgTimeToTimePtr(sqlcEvent.PublishedAt),
+		CreatedAt:   *converter.PgTimeToTimePtr(sqlcEvent.CreatedAt),
+		UpdatedAt:   *converter.PgTimeToTimePtr(sqlcEvent.UpdatedAt),
+		Seq:         sqlcEvent.Seq,
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// PaymentWindowRepository lưu thời gian chờ thanh toán riêng của từng shop.
+type PaymentWindowRepository interface {
+	// GetByShopID trả về nil nếu shop chưa cấu hình thời gian chờ thanh toán riêng.
+	GetByShopID(ctx context.Context, shopID string) (*domain.ShopPaymentWindow, error)
+	Upsert(ctx context.Context, window *domain.ShopPaymentWindow) (*domain.ShopPaymentWindow, error)
+}
+
+type paymentWindowRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewPaymentWindowRepository(db *postgresql_infra.PostgreSQLService) PaymentWindowRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &paymentWindowRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *paymentWindowRepository) GetByShopID(ctx context.Context, shopID string) (*domain.ShopPaymentWindow, error) {
+	result, err := r.queries.GetShopPaymentWindow(ctx, converter.StringToPgUUID(shopID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get payment window of shop %s: %w", shopID, err)
+	}
+
+	return paymentWindowToDomain(&result), nil
+}
+
+func (r *paymentWindowRepository) Upsert(ctx context.Context, window *domain.ShopPaymentWindow) (*domain.ShopPaymentWindow, error) {
+	result, err := r.queries.UpsertShopPaymentWindow(ctx, sqlc.UpsertShopPaymentWindowParams{
+		ShopID:        converter.StringToPgUUID(window.ShopID),
+		WindowMinutes: int32(window.Window / time.Minute),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save payment window of shop %s: %w", window.ShopID, err)
+	}
+
+	return paymentWindowToDomain(&result), nil
+}
+
+func paymentWindowToDomain(window *sqlc.ShopPaymentWindow) *domain.ShopPaymentWindow {
+	return &domain.ShopPaymentWindow{
+		ShopID: converter.PgUUIDToString(window.ShopID),
+		Window: time.Duration(window.WindowMinutes) * time.Minute,
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ReconciliationRepository phục vụ việc đối soát đơn hàng với payment-service: duyệt các đơn cần kiểm tra
+// và ghi lại các lệch không tự sửa được vào bảng order_reconciliation_reviews.
+type ReconciliationRepository interface {
+	// ListPaymentReconciliationCandidates trả về tối đa limit đơn có trạng thái thuộc statuses và không đổi từ
+	// settledBefore, sắp theo (updated_at, id) và nằm sau con trỏ (afterUpdatedAt, afterID). afterID rỗng là trang đầu.
+	ListPaymentReconciliationCandidates(ctx context.Context, statuses []domain.OrderStatus, settledBefore, afterUpdatedAt time.Time, afterID string, limit int) ([]domain.PaymentReconciliationCandidate, error)
+	// RecordReview ghi lệch vào dòng đang mở của đơn (tạo mới nếu chưa có), phát hiện lại thì tăng detection_count.
+	RecordReview(ctx context.Context, review *domain.PaymentReconciliationReview) (*domain.PaymentReconciliationReview, error)
+	// ResolveReviews đóng mọi dòng đang mở của các đơn đã khớp lại với payment.
+	ResolveReviews(ctx context.Context, orderIDs []string, resolution string) (int64, error)
+}
+
+type reconciliationRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewReconciliationRepository(db *postgresql_infra.PostgreSQLService) ReconciliationRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &reconciliationRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *reconciliationRepository) ListPaymentReconciliationCandidates(ctx context.Context, statuses []domain.OrderStatus, settledBefore, afterUpdatedAt time.Time, afterID string, limit int) ([]domain.PaymentReconciliationCandidate, error) {
+	sqlcStatuses := make([]sqlc.OrderStatus, 0, len(statuses))
+	for _, status := range statuses {
+		sqlcStatuses = append(sqlcStatuses, sqlc.OrderStatus(status))
+	}
+
+	// So sánh bộ (updated_at, id) với NULL luôn ra NULL nên trang đầu dùng uuid rỗng thay cho id
+	cursorID := converter.UUIDToPgUUID(uuid.Nil)
+	if afterID != "" {
+		cursorID = converter.StringToPgUUID(afterID)
+	}
+
+	results, err := r.queries.ListOrdersForPaymentReconciliation(ctx, sqlc.ListOrdersForPaymentReconciliationParams{
+		Statuses:        sqlcStatuses,
+		UpdatedBefore:   converter.TimeToPgTime(settledBefore),
+		CursorUpdatedAt: converter.TimeToPgTime(afterUpdatedAt),
+		CursorID:        cursorID,
+		PageSize:        int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders for payment reconciliation: %w", err)
+	}
+
+	candidates := make([]domain.PaymentReconciliationCandidate, 0, len(results))
+	for _, result := range results {
+		candidates = append(candidates, domain.PaymentReconciliationCandidate{
+			OrderID:         converter.PgUUIDToString(result.ID),
+			Status:          domain.OrderStatus(result.OrderStatus),
+			RefundRequested: result.RefundRequested,
+			UpdatedAt:       result.UpdatedAt.Time,
+		})
+	}
+	return candidates, nil
+}
+
+func (r *reconciliationRepository) RecordReview(ctx context.Context, review *domain.PaymentReconciliationReview) (*domain.PaymentReconciliationReview, error) {
+	paymentID := converter.NullPgUUID()
+	if review.PaymentID != nil {
+		paymentID = converter.StringToPgUUID(*review.PaymentID)
+	}
+
+	var paymentStatus pgtype.Text
+	if review.PaymentStatus != nil {
+		paymentStatus = pgtype.Text{String: string(*review.PaymentStatus), Valid: true}
+	}
+
+	result, err := r.queries.UpsertOrderReconciliationReview(ctx, sqlc.UpsertOrderReconciliationReviewParams{
+		OrderID:        converter.StringToPgUUID(review.OrderID),
+		DivergenceType: string(review.DivergenceType),
+		OrderStatus:    sqlc.OrderStatus(review.OrderStatus),
+		PaymentID:      paymentID,
+		PaymentStatus:  paymentStatus,
+		Details:        review.Details,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record reconciliation review of order %s: %w", review.OrderID, err)
+	}
+
+	return toDomainReconciliationReview(result), nil
+}
+
+func (r *reconciliationRepository) ResolveReviews(ctx context.Context, orderIDs []string, resolution string) (int64, error) {
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]pgtype.UUID, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		ids = append(ids, converter.StringToPgUUID(orderID))
+	}
+
+	resolved, err := r.queries.ResolveOrderReconciliationReviews(ctx, sqlc.ResolveOrderReconciliationReviewsParams{
+		Resolution: converter.StringToPgText(&resolution),
+		OrderIds:   ids,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve reconciliation reviews: %w", err)
+	}
+	return resolved, nil
+}
+
+func toDomainReconciliationReview(review sqlc.OrderReconciliationReview) *domain.PaymentReconciliationReview {
+	var paymentID *string
+	if review.PaymentID.Valid {
+		id := converter.PgUUIDToString(review.PaymentID)
+		paymentID = &id
+	}
+
+	var paymentStatus *domain.PaymentStatus
+	if review.PaymentStatus.Valid {
+		status := domain.PaymentStatus(review.PaymentStatus.String)
+		paymentStatus = &status
+	}
+
+	return &domain.PaymentReconciliationReview{
+		ID:              converter.PgUUIDToString(review.ID),
+		OrderID:         converter.PgUUIDToString(review.OrderID),
+		DivergenceType:  domain.PaymentDivergenceType(review.DivergenceType),
+		OrderStatus:     domain.OrderStatus(review.OrderStatus),
+		PaymentID:       paymentID,
+		PaymentStatus:   paymentStatus,
+		Details:         review.Details,
+		DetectionCount:  int(review.DetectionCount),
+		FirstDetectedAt: review.FirstDetectedAt.Time,
+		LastDetectedAt:  review.LastDetectedAt.Time,
+		ResolvedAt:      converter.PgTimeToTimePtr(review.ResolvedAt),
+		Resolution:      converter.PgTextToStringPtr(review.Resolution),
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ReturnRepository quản lý các yêu cầu trả hàng. Mỗi bước chuyển trạng thái chỉ thành công khi yêu cầu
+// còn ở trạng thái mong đợi, ngược lại trả về domain.ErrReturnStatusChanged.
+type ReturnRepository interface {
+	CreateReturn(ctx context.Context, order *domain.Order, orderReturn *domain.OrderReturn) (*domain.OrderReturn, error)
+	GetByID(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	ListByOrder(ctx context.Context, orderID string) ([]*domain.OrderReturn, error)
+	ListByShop(ctx context.Context, shopID string, status *domain.ReturnStatus, limit int) ([]*domain.OrderReturn, error)
+	Approve(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	Reject(ctx context.Context, returnID string, sellerNote *string) (*domain.OrderReturn, error)
+	MarkReceived(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	MarkRestocked(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+	SetRefundID(ctx context.Context, returnID string, refundID string) (*domain.OrderReturn, error)
+	MarkRefunded(ctx context.Context, returnID string) (*domain.OrderReturn, error)
+}
+
+type returnRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewReturnRepository(db *postgresql_infra.PostgreSQLService) ReturnRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &returnRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+// CreateReturn lưu yêu cầu trả hàng cùng các sản phẩm. Đơn hàng được khoá trong transaction để tổng số lượng
+// đã yêu cầu trả (trừ các yêu cầu bị từ chối) không vượt quá số lượng đã mua.
+func (r *returnRepository) CreateReturn(ctx context.Context, order *domain.Order, orderReturn *domain.OrderReturn) (*domain.OrderReturn, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+	orderID := converter.StringToPgUUID(order.ID)
+
+	if err := qtx.LockOrderForReturn(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("failed to lock order %s: %w", order.ID, err)
+	}
+
+	returned, err := qtx.GetReturnedQuantitiesByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returned quantities of order %s: %w", order.ID, err)
+	}
+
+	remaining := make(map[string]int, len(order.Items))
+	for _, item := range order.Items {
+		remaining[item.ID] += item.RemainingQuantity()
+	}
+	for _, row := range returned {
+		remaining[converter.PgUUIDToString(row.OrderItemID)] -= int(row.ReturnedQuantity)
+	}
+	for _, item := range orderReturn.Items {
+		if item.Quantity > remaining[item.OrderItemID] {
+			return nil, domain.ErrReturnQuantityExceeded
+		}
+		remaining[item.OrderItemID] -= item.Quantity
+	}
+
+	dbReturn, err := qtx.CreateOrderReturn(ctx, sqlc.CreateOrderReturnParams{
+		OrderID:      orderID,
+		OwnerID:      converter.StringToPgUUID(orderReturn.OwnerID),
+		ShopID:       converter.StringToPgUUID(orderReturn.ShopID),
+		Reason:       orderReturn.Reason,
+		RefundAmount: converter.MoneyToPgNumeric(orderReturn.RefundAmount),
+		Currency:     orderReturn.RefundAmount.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create return of order %s: %w", order.ID, err)
+	}
+
+	items := make([]sqlc.OrderReturnItem, 0, len(orderReturn.Items))
+	for _, item := range orderReturn.Items {
+		dbItem, err := qtx.CreateOrderReturnItem(ctx, sqlc.CreateOrderReturnItemParams{
+			ReturnID:    dbReturn.ID,
+			OrderItemID: converter.StringToPgUUID(item.OrderItemID),
+			ProductID:   converter.StringToPgUUID(item.ProductID),
+			Quantity:    int32(item.Quantity),
+			UnitPrice:   converter.MoneyToPgNumeric(item.UnitPrice),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create return item for order item %s: %w", item.OrderItemID, err)
+		}
+		items = append(items, dbItem)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainOrderReturn(&dbReturn, items), nil
+}
+
+func (r *returnRepository) GetByID(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	dbReturn, err := r.queries.GetOrderReturnByID(ctx, converter.StringToPgUUID(returnID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Order return", returnID)
+		}
+		return nil, fmt.Errorf("failed to get return %s: %w", returnID, err)
+	}
+	return r.withItems(ctx, &dbReturn)
+}
+
+func (r *returnRepository) ListByOrder(ctx context.Context, orderID string) ([]*domain.OrderReturn, error) {
+	dbReturns, err := r.queries.ListOrderReturnsByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list returns of order %s: %w", orderID, err)
+	}
+	return r.toDomainOrderReturns(ctx, dbReturns)
+}
+
+func (r *returnRepository) ListByShop(ctx context.Context, shopID string, status *domain.ReturnStatus, limit int) ([]*domain.OrderReturn, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	params := sqlc.ListOrderReturnsByShopIDParams{
+		ShopID:   converter.StringToPgUUID(shopID),
+		PageSize: int32(limit),
+	}
+	if status != nil {
+		params.Status = sqlc.NullReturnStatus{ReturnStatus: sqlc.ReturnStatus(*status), Valid: true}
+	}
+
+	dbReturns, err := r.queries.ListOrderReturnsByShopID(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list returns of shop %s: %w", shopID, err)
+	}
+	return r.toDomainOrderReturns(ctx, dbReturns)
+}
+
+// Approve: người bán đồng ý nhận lại hàng, REQUESTED -> APPROVED.
+func (r *returnRepository) Approve(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "approve", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.ApproveOrderReturn(ctx, id)
+	})
+}
+
+// Reject: người bán từ chối yêu cầu, REQUESTED -> REJECTED. Số lượng của yêu cầu bị từ chối được trả lại
+// cho các yêu cầu sau.
+func (r *returnRepository) Reject(ctx context.Context, returnID string, sellerNote *string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "reject", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.RejectOrderReturn(ctx, sqlc.RejectOrderReturnParams{
+			ID:         id,
+			SellerNote: converter.StringToPgText(sellerNote),
+		})
+	})
+}
+
+// MarkReceived: người bán đã nhận lại hàng, APPROVED -> RECEIVED.
+func (r *returnRepository) MarkReceived(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "mark as received", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.MarkOrderReturnReceived(ctx, id)
+	})
+}
+
+// MarkRestocked ghi nhận hàng trả đã được nhập lại kho ở product-service.
+func (r *returnRepository) MarkRestocked(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "mark as restocked", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.MarkOrderReturnRestocked(ctx, id)
+	})
+}
+
+// SetRefundID lưu ID lần hoàn tiền một phần mà payment-service đã tạo cho yêu cầu trả hàng.
+func (r *returnRepository) SetRefundID(ctx context.Context, returnID string, refundID string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "set refund id", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.SetOrderReturnRefundID(ctx, sqlc.SetOrderReturnRefundIDParams{
+			ID:       id,
+			RefundID: converter.StringToPgText(&refundID),
+		})
+	})
+}
+
+// MarkRefunded: payment-service đã hoàn tiền xong, RECEIVED -> REFUNDED.
+func (r *returnRepository) MarkRefunded(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	return r.transition(ctx, returnID, "mark as refunded", func(id pgtype.UUID) (sqlc.OrderReturn, error) {
+		return r.queries.MarkOrderReturnRefunded(ctx, id)
+	})
+}
+
+func (r *returnRepository) transition(ctx context.Context, returnID string, action string, update func(id pgtype.UUID) (sqlc.OrderReturn, error)) (*domain.OrderReturn, error) {
+	dbReturn, err := update(converter.StringToPgUUID(returnID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrReturnStatusChanged
+		}
+		return nil, fmt.Errorf("failed to %s return %s: %w", action, returnID, err)
+	}
+	return r.withItems(ctx, &dbReturn)
+}
+
+func (r *returnRepository) withItems(ctx context.Context, dbReturn *sqlc.OrderReturn) (*domain.OrderReturn, error) {
+	items, err := r.queries.ListOrderReturnItemsByReturnIDs(ctx, []pgtype.UUID{dbReturn.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items of return %s: %w", converter.PgUUIDToString(dbReturn.ID), err)
+	}
+	return toDomainOrderReturn(dbReturn, items), nil
+}
+
+// toDomainOrderReturns nạp sản phẩm của tất cả yêu cầu trong một query.
+func (r *returnRepository) toDomainOrderReturns(ctx context.Context, dbReturns []sqlc.OrderReturn) ([]*domain.OrderReturn, error) {
+	if len(dbReturns) == 0 {
+		return []*domain.OrderReturn{}, nil
+	}
+
+	returnIDs := make([]pgtype.UUID, len(dbReturns))
+	for i := range dbReturns {
+		returnIDs[i] = dbReturns[i].ID
+	}
+
+	items, err := r.queries.ListOrderReturnItemsByReturnIDs(ctx, returnIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get return items: %w", err)
+	}
+
+	itemsByReturn := make(map[string][]sqlc.OrderReturnItem, len(dbReturns))
+	for _, item := range items {
+		returnID := converter.PgUUIDToString(item.ReturnID)
+		itemsByReturn[returnID] = append(itemsByReturn[returnID], item)
+	}
+
+	result := make([]*domain.OrderReturn, len(dbReturns))
+	for i := range dbReturns {
+		result[i] = toDomainOrderReturn(&dbReturns[i], itemsByReturn[converter.PgUUIDToString(dbReturns[i].ID)])
+	}
+	return result, nil
+}
+
+func toDomainOrderReturn(dbReturn *sqlc.OrderReturn, dbItems []sqlc.OrderReturnItem) *domain.OrderReturn {
+	if dbReturn == nil {
+		return nil
+	}
+
+	items := make([]domain.OrderReturnItem, len(dbItems))
+	for i, dbItem := range dbItems {
+		items[i] = domain.OrderReturnItem{
+			ID:          converter.PgUUIDToString(dbItem.ID),
+			ReturnID:    converter.PgUUIDToString(dbItem.ReturnID),
+			OrderItemID: converter.PgUUIDToString(dbItem.OrderItemID),
+			ProductID:   converter.PgUUIDToString(dbItem.ProductID),
+			Quantity:    int(dbItem.Quantity),
+			UnitPrice:   converter.PgNumericToMoney(dbItem.UnitPrice, dbReturn.Currency),
+		}
+	}
+
+	return &domain.OrderReturn{
+		ID:           converter.PgUUIDToString(dbReturn.ID),
+		OrderID:      converter.PgUUIDToString(dbReturn.OrderID),
+		OwnerID:      converter.PgUUIDToString(dbReturn.OwnerID),
+		ShopID:       converter.PgUUIDToString(dbReturn.ShopID),
+		Status:       domain.ReturnStatus(dbReturn.ReturnStatus),
+		Reason:       dbReturn.Reason,
+		SellerNote:   converter.PgTextToStringPtr(dbReturn.SellerNote),
+		RefundAmount: converter.PgNumericToMoney(dbReturn.RefundAmount, dbReturn.Currency),
+		RefundID:     converter.PgTextToStringPtr(dbReturn.RefundID),
+		Restocked:    dbReturn.Restocked,
+		Items:        items,
+		ApprovedAt:   converter.PgTimeToTimePtr(dbReturn.ApprovedAt),
+		RejectedAt:   converter.PgTimeToTimePtr(dbReturn.RejectedAt),
+		ReceivedAt:   converter.PgTimeToTimePtr(dbReturn.ReceivedAt),
+		RefundedAt:   converter.PgTimeToTimePtr(dbReturn.RefundedAt),
+		CreatedAt:    dbReturn.CreatedAt.Time,
+		UpdatedAt:    dbReturn.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ShipperCashRepository quản lý tiền mặt shipper thu hộ cho đơn COD và các lần quyết toán với admin.
+type ShipperCashRepository interface {
+	// RecordCollection ghi nhận khoản thu của đơn, gọi lại cho cùng đơn trả về khoản đã ghi.
+	RecordCollection(ctx context.Context, collection *domain.CashCollection) (*domain.CashCollection, error)
+	GetCollectionByOrderID(ctx context.Context, orderID string) (*domain.CashCollection, error)
+	MarkCollectionConfirmed(ctx context.Context, collectionID string) (*domain.CashCollection, error)
+	// ListUnconfirmedCollections trả về các khoản thu trước collectedBefore mà payment COD chưa được xác nhận.
+	ListUnconfirmedCollections(ctx context.Context, collectedBefore time.Time, limit int) ([]*domain.CashCollection, error)
+	ListUnsettledCollections(ctx context.Context, shipperID string) ([]*domain.CashCollection, error)
+	// Settle quyết toán mọi khoản chưa quyết toán của shipper theo currency của amount. Trả về
+	// domain.ErrNoCashToSettle khi không có khoản nào, domain.ErrCashSettlementMismatch khi tổng khác amount.
+	Settle(ctx context.Context, shipperID string, amount money.Money, settledBy string, note *string) (*domain.CashSettlement, error)
+}
+
+type shipperCashRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewShipperCashRepository(db *postgresql_infra.PostgreSQLService) ShipperCashRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &shipperCashRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *shipperCashRepository) RecordCollection(ctx context.Context, collection *domain.CashCollection) (*domain.CashCollection, error) {
+	created, err := r.queries.CreateShipperCashCollection(ctx, sqlc.CreateShipperCashCollectionParams{
+		OrderID:    converter.StringToPgUUID(collection.OrderID),
+		DeliveryID: converter.StringToPgUUID(collection.DeliveryID),
+		ShipperID:  converter.StringToPgUUID(collection.ShipperID),
+		PaymentID:  converter.StringToPgUUID(collection.PaymentID),
+		Amount:     converter.MoneyToPgNumeric(collection.Amount),
+		Currency:   collection.Amount.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Đơn đã được ghi nhận thu tiền từ trước
+			return r.GetCollectionByOrderID(ctx, collection.OrderID)
+		}
+		return nil, fmt.Errorf("failed to record cash collection of order %s: %w", collection.OrderID, err)
+	}
+	return toDomainCashCollection(&created), nil
+}
+
+func (r *shipperCashRepository) GetCollectionByOrderID(ctx context.Context, orderID string) (*domain.CashCollection, error) {
+	collection, err := r.queries.GetShipperCashCollectionByOrderID(ctx, converter.StringToPgUUID(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFound("Cash collection", orderID)
+		}
+		return nil, fmt.Errorf("failed to get cash collection of order %s: %w", orderID, err)
+	}
+	return toDomainCashCollection(&collection), nil
+}
+
+func (r *shipperCashRepository) MarkCollectionConfirmed(ctx context.Context, collectionID string) (*domain.CashCollection, error) {
+	collection, err := r.queries.MarkShipperCashCollectionConfirmed(ctx, converter.StringToPgUUID(collectionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark cash collection %s as confirmed: %w", collectionID, err)
+	}
+	return toDomainCashCollection(&collection), nil
+}
+
+func (r *shipperCashRepository) ListUnconfirmedCollections(ctx context.Context, collectedBefore time.Time, limit int) ([]*domain.CashCollection, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+
+	collections, err := r.queries.ListUnconfirmedShipperCashCollections(ctx, sqlc.ListUnconfirmedShipperCashCollectionsParams{
+		CollectedBefore: converter.TimeToPgTime(collectedBefore),
+		PageSize:        int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unconfirmed cash collections: %w", err)
+	}
+	return toDomainCashCollections(collections), nil
+}
+
+func (r *shipperCashRepository) ListUnsettledCollections(ctx context.Context, shipperID string) ([]*domain.CashCollection, error) {
+	collections, err := r.queries.ListUnsettledShipperCashCollections(ctx, converter.StringToPgUUID(shipperID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unsettled cash of shipper %s: %w", shipperID, err)
+	}
+	return toDomainCashCollections(collections), nil
+}
+
+func (r *shipperCashRepository) Settle(ctx context.Context, shipperID string, amount money.Money, settledBy string, note *string) (*domain.CashSettlement, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	collections, err := qtx.LockUnsettledShipperCashCollections(ctx, sqlc.LockUnsettledShipperCashCollectionsParams{
+		ShipperID: converter.StringToPgUUID(shipperID),
+		Currency:  amount.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock unsettled cash of shipper %s: %w", shipperID, err)
+	}
+	total, err := domain.CashSettlementTotal(amount, toDomainCashCollections(collections))
+	if err != nil {
+		return nil, err
+	}
+
+	collectionIDs := make([]pgtype.UUID, len(collections))
+	for i, collection := range collections {
+		collectionIDs[i] = collection.ID
+	}
+
+	settlement, err := qtx.CreateShipperCashSettlement(ctx, sqlc.CreateShipperCashSettlementParams{
+		ShipperID:       converter.StringToPgUUID(shipperID),
+		Amount:          converter.MoneyToPgNumeric(total),
+		Currency:        total.Currency,
+		CollectionCount: int32(len(collections)),
+		SettledBy:       converter.StringToPgUUID(settledBy),
+		Note:            converter.StringToPgText(note),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cash settlement of shipper %s: %w", shipperID, err)
+	}
+
+	if _, err := qtx.SettleShipperCashCollections(ctx, sqlc.SettleShipperCashCollectionsParams{
+		SettlementID:  settlement.ID,
+		CollectionIds: collectionIDs,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to settle cash collections of shipper %s: %w", shipperID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainCashSettlement(&settlement), nil
+}
+
+func toDomainCashCollections(collections []sqlc.ShipperCashCollection) []*domain.CashCollection {
+	result := make([]*domain.CashCollection, len(collections))
+	for i := range collections {
+		result[i] = toDomainCashCollection(&collections[i])
+	}
+	return result
+}
+
+func toDomainCashCollection(collection *sqlc.ShipperCashCollection) *domain.CashCollection {
+	var settlementID *string
+	if collection.SettlementID.Valid {
+		id := converter.PgUUIDToString(collection.SettlementID)
+		settlementID = &id
+	}
+
+	return &domain.CashCollection{
+		ID:                 converter.PgUUIDToString(collection.ID),
+		OrderID:            converter.PgUUIDToString(collection.OrderID),
+		DeliveryID:         converter.PgUUIDToString(collection.DeliveryID),
+		ShipperID:          converter.PgUUIDToString(collection.ShipperID),
+		PaymentID:          converter.PgUUIDToString(collection.PaymentID),
+		Amount:             converter.PgNumericToMoney(collection.Amount, collection.Currency),
+		PaymentConfirmedAt: converter.PgTimeToTimePtr(collection.PaymentConfirmedAt),
+		SettlementID:       settlementID,
+		CollectedAt:        collection.CollectedAt.Time,
+		SettledAt:          converter.PgTimeToTimePtr(collection.SettledAt),
+	}
+}
+
+func toDomainCashSettlement(settlement *sqlc.ShipperCashSettlement) *domain.CashSettlement {
+	return &domain.CashSettlement{
+		ID:              converter.PgUUIDToString(settlement.ID),
+		ShipperID:       converter.PgUUIDToString(settlement.ShipperID),
+		Amount:          converter.PgNumericToMoney(settlement.Amount, settlement.Currency),
+		CollectionCount: int(settlement.CollectionCount),
+		SettledBy:       converter.PgUUIDToString(settlement.SettledBy),
+		Note:            converter.PgTextToStringPtr(settlement.Note),
+		CreatedAt:       settlement.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ShippingRateRepository lưu bảng phí vận chuyển riêng của từng shop.
+type ShippingRateRepository interface {
+	// GetByShopID trả về nil nếu shop chưa cấu hình bảng phí riêng.
+	GetByShopID(ctx context.Context, shopID string) (*domain.ShippingRateTable, error)
+	Upsert(ctx context.Context, table *domain.ShippingRateTable) (*domain.ShippingRateTable, error)
+}
+
+type shippingRateRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewShippingRateRepository(db *postgresql_infra.PostgreSQLService) ShippingRateRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &shippingRateRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *shippingRateRepository) GetByShopID(ctx context.Context, shopID string) (*domain.ShippingRateTable, error) {
+	result, err := r.queries.GetShopShippingRate(ctx, converter.StringToPgUUID(shopID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get shipping rates of shop %s: %w", shopID, err)
+	}
+
+	return shippingRateToDomain(&result)
+}
+
+func (r *shippingRateRepository) Upsert(ctx context.Context, table *domain.ShippingRateTable) (*domain.ShippingRateTable, error) {
+	zones, err := json.Marshal(table.Zones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal shipping zones: %w", err)
+	}
+
+	weightTiers := table.WeightTiers
+	if weightTiers == nil {
+		weightTiers = []domain.ShippingWeightTier{}
+	}
+	weightTiersJSON, err := json.Marshal(weightTiers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal shipping weight tiers: %w", err)
+	}
+
+	result, err := r.queries.UpsertShopShippingRate(ctx, sqlc.UpsertShopShippingRateParams{
+		ShopID:      converter.StringToPgUUID(table.ShopID),
+		Zones:       zones,
+		WeightTiers: weightTiersJSON,
+		Currency:    table.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save shipping rates of shop %s: %w", table.ShopID, err)
+	}
+
+	return shippingRateToDomain(&result)
+}
+
+func shippingRateToDomain(rate *sqlc.ShopShippingRate) (*domain.ShippingRateTable, error) {
+	table := &domain.ShippingRateTable{
+		ShopID:   converter.PgUUIDToString(rate.ShopID),
+		Currency: rate.Currency,
+	}
+
+	if err := json.Unmarshal(rate.Zones, &table.Zones); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping zones: %w", err)
+	}
+
+	if len(rate.WeightTiers) > 0 {
+		if err := json.Unmarshal(rate.WeightTiers, &table.WeightTiers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal shipping weight tiers: %w", err)
+		}
+	}
+
+	return table, nil
+}
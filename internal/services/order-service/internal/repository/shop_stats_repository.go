@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+)
+
+// ShopStatsRepository đọc thống kê bán hàng theo ngày của shop. Số liệu được ghi bởi recordShopStats
+// trong cùng transaction với mỗi lần đơn hàng đổi trạng thái.
+type ShopStatsRepository interface {
+	// GetShopDailyStats trả về số liệu từng ngày có phát sinh đơn trong [from, to), mỗi tiền tệ một dòng.
+	GetShopDailyStats(ctx context.Context, shopID string, from, to time.Time) ([]domain.ShopSalesBucket, error)
+	GetShopTopProducts(ctx context.Context, shopID string, from, to time.Time, limit int) ([]domain.ShopTopProduct, error)
+}
+
+type shopStatsRepository struct {
+	db      *postgresql_infra.PostgreSQLService
+	queries *sqlc.Queries
+}
+
+func NewShopStatsRepository(db *postgresql_infra.PostgreSQLService) ShopStatsRepository {
+	if db == nil {
+		return nil
+	}
+
+	queries := sqlc.New(db.GetPool())
+
+	return &shopStatsRepository{
+		db:      db,
+		queries: queries,
+	}
+}
+
+func (r *shopStatsRepository) GetShopDailyStats(ctx context.Context, shopID string, from, to time.Time) ([]domain.ShopSalesBucket, error) {
+	results, err := r.queries.ListShopDailyStats(ctx, sqlc.ListShopDailyStatsParams{
+		ShopID:   converter.StringToPgUUID(shopID),
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily stats of shop %s: %w", shopID, err)
+	}
+
+	stats := make([]domain.ShopSalesBucket, 0, len(results))
+	for _, result := range results {
+		stats = append(stats, domain.ShopSalesBucket{
+			Start:          result.StatDate.Time,
+			Currency:       result.Currency,
+			OrdersCount:    int(result.OrdersCount),
+			CanceledCount:  int(result.CanceledCount),
+			GrossAmount:    converter.PgNumericToMoney(result.GrossAmount, result.Currency),
+			CanceledAmount: converter.PgNumericToMoney(result.CanceledAmount, result.Currency),
+		})
+	}
+	return stats, nil
+}
+
+func (r *shopStatsRepository) GetShopTopProducts(ctx context.Context, shopID string, from, to time.Time, limit int) ([]domain.ShopTopProduct, error) {
+	results, err := r.queries.ListShopTopProducts(ctx, sqlc.ListShopTopProductsParams{
+		ShopID:   converter.StringToPgUUID(shopID),
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+		TopLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top products of shop %s: %w", shopID, err)
+	}
+
+	products := make([]domain.ShopTopProduct, 0, len(results))
+	for _, result := range results {
+		products = append(products, domain.ShopTopProduct{
+			ProductID:   converter.PgUUIDToString(result.ProductID),
+			ProductName: result.ProductName,
+			Currency:    result.Currency,
+			Quantity:    result.Quantity,
+			Revenue:     converter.PgNumericToMoney(result.Revenue, result.Currency),
+		})
+	}
+	return products, nil
+}
+
+// recordShopStats cộng thay đổi của một lần chuyển trạng thái vào thống kê ngày của shop. Phải được gọi trong
+// transaction đổi trạng thái, sau khi order_items đã được ghi.
+func recordShopStats(ctx context.Context, q *sqlc.Queries, orderID pgtype.UUID, oldStatus *sqlc.OrderStatus, newStatus sqlc.OrderStatus) error {
+	var old *domain.OrderStatus
+	if oldStatus != nil {
+		status := domain.OrderStatus(*oldStatus)
+		old = &status
+	}
+
+	delta := domain.ShopStatsDeltaForTransition(old, domain.OrderStatus(newStatus))
+	if delta.IsZero() {
+		return nil
+	}
+
+	if delta.Orders != 0 || delta.Canceled != 0 {
+		if err := q.ApplyShopDailyStatsDelta(ctx, sqlc.ApplyShopDailyStatsDeltaParams{
+			OrdersDelta:   int32(delta.Orders),
+			CanceledDelta: int32(delta.Canceled),
+			OrderID:       orderID,
+		}); err != nil {
+			return fmt.Errorf("failed to update shop stats of order %s: %w", converter.PgUUIDToString(orderID), err)
+		}
+	}
+
+	if delta.Products != 0 {
+		if err := q.ApplyShopProductDailyStatsDelta(ctx, sqlc.ApplyShopProductDailyStatsDeltaParams{
+			Delta:   int32(delta.Products),
+			OrderID: orderID,
+		}); err != nil {
+			return fmt.Errorf("failed to update product stats of order %s: %w", converter.PgUUIDToString(orderID), err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
 	common_middleware "github.com/toji-dev/go-shop/internal/pkg/middleware"
 	dependency_container "github.com/toji-dev/go-shop/internal/services/order-service/internal/dependency-container"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/middleware"
@@ -34,6 +35,15 @@ func Init(router *gin.Engine, dependencyContainer *dependency_container.Dependen
 	router.Use(common_middleware.AuthTokenMiddleware(dependencyContainer.GetJwtService()))
 
 	orderHandler := dependencyContainer.GetOrderHandler()
+	shippingHandler := dependencyContainer.GetShippingHandler()
+	deliveryHandler := dependencyContainer.GetDeliveryHandler()
+	returnHandler := dependencyContainer.GetReturnHandler()
+	invoiceHandler := dependencyContainer.GetInvoiceHandler()
+	paymentWindowHandler := dependencyContainer.GetPaymentWindowHandler()
+	shopAnalyticsHandler := dependencyContainer.GetShopAnalyticsHandler()
+	inboxHandler := dependencyContainer.GetInboxHandler()
+	outboxHandler := dependencyContainer.GetOutboxHandler()
+	idempotency := common_middleware.IdempotencyMiddleware(dependencyContainer.GetRedisService(), common_middleware.IdempotencyConfig{})
 
 	v1 := router.Group("/api/v1")
 	{
@@ -41,8 +51,95 @@ func Init(router *gin.Engine, dependencyContainer *dependency_container.Dependen
 		orders.Use(middleware.AuthHeaderMiddleware())
 		{
 			orders.GET("", orderHandler.GetOrdersByOwnerID)
-			orders.POST("", orderHandler.CreateOrder)
+			orders.POST("", idempotency, orderHandler.CreateOrder)
+			orders.POST("/checkout", idempotency, orderHandler.Checkout)
+			orders.POST("/shipping-quote", shippingHandler.QuoteShippingFee)
 			orders.GET("/:order_id", orderHandler.GetOrderByID)
+			orders.POST("/:order_id/cancel", idempotency, orderHandler.CancelOrder)
+			orders.POST("/:order_id/reorder", idempotency, orderHandler.Reorder)
+			orders.GET("/:order_id/timeline", orderHandler.GetOrderTimeline)
+			orders.POST("/:order_id/returns", idempotency, returnHandler.CreateReturn)
+			orders.GET("/:order_id/returns", returnHandler.GetOrderReturns)
+			orders.GET("/:order_id/returns/:return_id", returnHandler.GetOrderReturn)
+			orders.GET("/:order_id/invoice", invoiceHandler.GetOrderInvoice)
+		}
+
+		shopOrders := v1.Group("/shops/:shop_id/orders")
+		shopOrders.Use(middleware.AuthHeaderMiddleware())
+		{
+			shopOrders.GET("", orderHandler.GetShopOrders)
+			shopOrders.GET("/export", orderHandler.ExportShopOrders)
+			shopOrders.POST("/:order_id/accept", idempotency, orderHandler.AcceptShopOrder)
+			shopOrders.POST("/:order_id/reject", idempotency, orderHandler.RejectShopOrder)
+			shopOrders.POST("/:order_id/ship", idempotency, orderHandler.ShipShopOrder)
+			shopOrders.POST("/:order_id/items/:item_id/pack", idempotency, orderHandler.PackShopOrderItem)
+			shopOrders.POST("/:order_id/items/:item_id/ship", idempotency, orderHandler.ShipShopOrderItem)
+			shopOrders.POST("/:order_id/items/:item_id/cancel", idempotency, orderHandler.CancelShopOrderItem)
+		}
+
+		shopReturns := v1.Group("/shops/:shop_id/returns")
+		shopReturns.Use(middleware.AuthHeaderMiddleware())
+		{
+			shopReturns.GET("", returnHandler.GetShopReturns)
+			shopReturns.POST("/:return_id/approve", idempotency, returnHandler.ApproveReturn)
+			shopReturns.POST("/:return_id/reject", idempotency, returnHandler.RejectReturn)
+			shopReturns.POST("/:return_id/receive", idempotency, returnHandler.ReceiveReturn)
+		}
+
+		shopAnalytics := v1.Group("/shops/:shop_id/analytics")
+		shopAnalytics.Use(middleware.AuthHeaderMiddleware())
+		{
+			shopAnalytics.GET("", shopAnalyticsHandler.GetShopAnalytics)
+		}
+
+		shippingRates := v1.Group("/shipping-rates")
+		shippingRates.Use(middleware.AuthHeaderMiddleware())
+		{
+			shippingRates.GET("/:shop_id", shippingHandler.GetShopShippingRates)
+			shippingRates.PUT("/:shop_id", shippingHandler.UpdateShopShippingRates)
+		}
+
+		paymentWindows := v1.Group("/payment-windows")
+		paymentWindows.Use(middleware.AuthHeaderMiddleware())
+		{
+			paymentWindows.GET("/:shop_id", paymentWindowHandler.GetShopPaymentWindow)
+			paymentWindows.PUT("/:shop_id", paymentWindowHandler.UpdateShopPaymentWindow)
+		}
+
+		deliveries := v1.Group("/deliveries")
+		deliveries.Use(middleware.AuthHeaderMiddleware(), middleware.AuthorizationMiddleware(string(constant.UserRoleShipper)))
+		{
+			deliveries.GET("", deliveryHandler.GetMyDeliveries)
+			deliveries.GET("/available", deliveryHandler.GetAvailableDeliveries)
+			deliveries.GET("/cash", deliveryHandler.GetMyCashBalance)
+			deliveries.POST("/:order_id/claim", idempotency, deliveryHandler.ClaimOrder)
+			deliveries.POST("/:order_id/pick-up", idempotency, deliveryHandler.PickUpOrder)
+			deliveries.POST("/:order_id/deliver", idempotency, deliveryHandler.DeliverOrder)
+			deliveries.POST("/:order_id/collect-cash", idempotency, deliveryHandler.CollectCash)
+		}
+
+		shipperCash := v1.Group("/admin/shippers/:shipper_id")
+		shipperCash.Use(middleware.AuthHeaderMiddleware(), middleware.AuthorizationMiddleware(string(constant.UserRoleAdmin)))
+		{
+			shipperCash.GET("/cash", deliveryHandler.GetShipperCashBalance)
+			shipperCash.POST("/cash-settlements", idempotency, deliveryHandler.SettleShipperCash)
+		}
+
+		inboxEvents := v1.Group("/admin/inbox-events")
+		inboxEvents.Use(middleware.AuthHeaderMiddleware(), middleware.AuthorizationMiddleware(string(constant.UserRoleAdmin)))
+		{
+			inboxEvents.GET("", inboxHandler.ListInboxEvents)
+			inboxEvents.GET("/stats", inboxHandler.GetInboxStats)
+			inboxEvents.POST("/replay", idempotency, inboxHandler.ReplayFailedInboxEvents)
+			inboxEvents.GET("/:event_id", inboxHandler.GetInboxEvent)
+			inboxEvents.POST("/:event_id/replay", idempotency, inboxHandler.ReplayInboxEvent)
+			inboxEvents.POST("/:event_id/discard", idempotency, inboxHandler.DiscardInboxEvent)
+		}
+
+		outboxEvents := v1.Group("/admin/outbox-events")
+		outboxEvents.Use(middleware.AuthHeaderMiddleware(), middleware.AuthorizationMiddleware(string(constant.UserRoleAdmin)))
+		{
+			outboxEvents.POST("/:event_id/retry", idempotency, outboxHandler.RetryOutboxEvent)
 		}
 	}
 }
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// CollectCash ghi nhận khoản thu trước rồi mới báo payment-service: tiền đã nằm ở shipper nên khoản thu phải được
+// lưu lại kể cả khi payment-service tạm thời không gọi được, reconciler sẽ gửi lại xác nhận sau.
+// Gọi lại cho đơn đã thu trả về khoản thu cũ.
+func (u *deliveryUseCase) CollectCash(ctx context.Context, shipperID string, orderID string) (*domain.CashCollection, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "CollectCash.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.String("order.id", orderID),
+	)
+
+	delivery, err := u.getShipperDelivery(ctx, shipperID, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// Chỉ thu tiền khi hàng đã ở chỗ shipper
+	if delivery.DeliveryStatus == domain.DeliveryStatusClaimed {
+		return nil, apperror.New(apperror.CodeConflict, "Order must be picked up before collecting cash", apperror.TypeConflict)
+	}
+
+	collection, err := u.shipperCashRepo.GetCollectionByOrderID(ctx, orderID)
+	if err != nil && apperror.GetType(err) != apperror.TypeNotFound {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get cash collection: %s", err.Error()))
+	}
+
+	if collection == nil {
+		paymentResp, err := u.paymentAdapter.GetPaymentByOrder(ctx, orderID)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, apperror.NewInternal(fmt.Sprintf("Failed to get payment of order: %s", err.Error()))
+		}
+		payment := paymentResp.GetPayment()
+		if !paymentResp.GetExists() || payment.GetPaymentMethod() != domain.PaymentMethodCOD {
+			return nil, apperror.New(apperror.CodeConflict, "Order is not paid by cash on delivery", apperror.TypeConflict)
+		}
+
+		amountDue, err := u.codAmountDue(ctx, orderID, payment)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		collection, err = u.shipperCashRepo.RecordCollection(ctx, &domain.CashCollection{
+			OrderID:    orderID,
+			DeliveryID: delivery.ID,
+			ShipperID:  shipperID,
+			PaymentID:  payment.GetId(),
+			Amount:     amountDue,
+		})
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, apperror.NewInternal(fmt.Sprintf("Failed to record cash collection: %s", err.Error()))
+		}
+		span.AddEvent("Cash collection recorded")
+	}
+
+	if collection.IsPaymentConfirmed() {
+		return collection, nil
+	}
+
+	confirmed, err := confirmCashCollection(ctx, u.paymentAdapter, u.shipperCashRepo, collection)
+	if err != nil {
+		log.Printf("Cash of order %s collected but payment confirmation failed, will retry: %v", orderID, err)
+		span.AddEvent("Payment confirmation deferred")
+		return collection, nil
+	}
+
+	span.AddEvent("Payment confirmed")
+	return confirmed, nil
+}
+
+// codAmountDue tính số tiền shipper cần thu của đơn COD sau khi trừ các dòng hàng người bán đã huỷ.
+func (u *deliveryUseCase) codAmountDue(ctx context.Context, orderID string, payment *payment_v1.Payment) (money.Money, error) {
+	cancellations, err := u.orderRepo.ListItemCancellations(ctx, orderID)
+	if err != nil {
+		return money.Money{}, apperror.NewInternal(fmt.Sprintf("Failed to get item cancellations of order: %s", err.Error()))
+	}
+
+	amountDue, err := domain.CODAmountDue(paymentTotal(payment), cancellations)
+	if err != nil {
+		return money.Money{}, apperror.NewInternal(fmt.Sprintf("Failed to calculate cash amount due: %s", err.Error()))
+	}
+	if !amountDue.IsPositive() {
+		return money.Money{}, apperror.New(apperror.CodeConflict, "Order has no cash left to collect", apperror.TypeConflict)
+	}
+	return amountDue, nil
+}
+
+func (u *deliveryUseCase) GetCashBalance(ctx context.Context, shipperID string) (*domain.ShipperCashBalance, error) {
+	collections, err := u.shipperCashRepo.ListUnsettledCollections(ctx, shipperID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get cash balance: %s", err.Error()))
+	}
+	return domain.NewShipperCashBalance(shipperID, collections), nil
+}
+
+func (u *deliveryUseCase) SettleCash(ctx context.Context, adminID string, shipperID string, req dto.SettleShipperCashRequest) (*domain.CashSettlement, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "SettleCash.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.String("admin.id", adminID),
+	)
+
+	amount := money.FromMajor(req.Amount, strings.ToUpper(strings.TrimSpace(req.Currency)))
+	settlement, err := u.shipperCashRepo.Settle(ctx, shipperID, amount, adminID, req.Note)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		switch {
+		case errors.Is(err, domain.ErrNoCashToSettle):
+			return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Shipper has no unsettled cash in %s", amount.Currency), apperror.TypeConflict)
+		case errors.Is(err, domain.ErrCashSettlementMismatch):
+			return nil, apperror.New(apperror.CodeConflict, err.Error(), apperror.TypeConflict)
+		default:
+			return nil, apperror.NewInternal(fmt.Sprintf("Failed to settle cash: %s", err.Error()))
+		}
+	}
+
+	span.AddEvent("Shipper cash settled")
+	return settlement, nil
+}
+
+// confirmCashCollection báo payment-service chuyển payment COD của khoản thu sang SUCCESS rồi đánh dấu đã xác nhận.
+func confirmCashCollection(ctx context.Context, paymentAdapter adapter.PaymentServiceAdapter, shipperCashRepo repository.ShipperCashRepository, collection *domain.CashCollection) (*domain.CashCollection, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := paymentAdapter.ConfirmCashCollected(ctxWithTimeout, collection.OrderID, collection.ID, collection.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm cash collection %s with payment service: %w", collection.ID, err)
+	}
+	if resp.GetAlreadyConfirmed() {
+		log.Printf("Payment of order %s was already confirmed as collected", collection.OrderID)
+	}
+
+	return shipperCashRepo.MarkCollectionConfirmed(ctx, collection.ID)
+}
+
+// paymentTotal đọc số tiền chính xác của payment, payment-service cũ chỉ gửi amount dạng số thực.
+func paymentTotal(payment *payment_v1.Payment) money.Money {
+	if total, ok := money.FromProto(payment.GetTotal()); ok {
+		return total
+	}
+	return money.FromMajor(payment.GetAmount(), payment.GetCurrency())
+}
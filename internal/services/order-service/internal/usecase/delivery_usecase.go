@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const defaultAvailableDeliveriesLimit = 20
+
+// DeliveryUseCase điều phối việc giao hàng của shipper: tìm đơn gần, nhận đơn, lấy hàng và giao hàng.
+// Trạng thái đơn hàng SHIPPED -> DELIVERING -> DELIVERED được đổi theo các thao tác này.
+type DeliveryUseCase interface {
+	ListAvailableDeliveries(ctx context.Context, shipperID string, query dto.AvailableDeliveriesQuery) ([]*domain.AvailableDelivery, error)
+	ListMyDeliveries(ctx context.Context, shipperID string, query dto.ListDeliveriesQuery) ([]*domain.OrderDelivery, error)
+	ClaimOrder(ctx context.Context, shipperID string, orderID string) (*domain.OrderDelivery, *domain.Order, error)
+	PickUpOrder(ctx context.Context, shipperID string, orderID string) (*domain.OrderDelivery, *domain.Order, error)
+	DeliverOrder(ctx context.Context, shipperID string, orderID string, req dto.DeliverOrderRequest) (*domain.OrderDelivery, *domain.Order, error)
+	// CollectCash: shipper báo đã thu tiền mặt của đơn COD, payment của đơn chuyển sang SUCCESS.
+	CollectCash(ctx context.Context, shipperID string, orderID string) (*domain.CashCollection, error)
+	// GetCashBalance trả về tiền mặt shipper đã thu nhưng chưa nộp lại cho admin.
+	GetCashBalance(ctx context.Context, shipperID string) (*domain.ShipperCashBalance, error)
+	// SettleCash: admin nhận lại toàn bộ tiền mặt chưa quyết toán của shipper theo một currency.
+	SettleCash(ctx context.Context, adminID string, shipperID string, req dto.SettleShipperCashRequest) (*domain.CashSettlement, error)
+}
+
+type deliveryUseCase struct {
+	deliveryRepo          repository.DeliveryRepository
+	orderRepo             repository.OrderRepository
+	shipperCashRepo       repository.ShipperCashRepository
+	shopServiceAdapter    adapter.ShopServiceAdapter
+	paymentAdapter        adapter.PaymentServiceAdapter
+	defaultSearchRadiusKm float64
+	maxSearchRadiusKm     float64
+}
+
+func NewDeliveryUseCase(
+	deliveryRepo repository.DeliveryRepository,
+	orderRepo repository.OrderRepository,
+	shipperCashRepo repository.ShipperCashRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+	paymentAdapter adapter.PaymentServiceAdapter,
+	defaultSearchRadiusKm float64,
+	maxSearchRadiusKm float64,
+) DeliveryUseCase {
+	return &deliveryUseCase{
+		deliveryRepo:          deliveryRepo,
+		orderRepo:             orderRepo,
+		shipperCashRepo:       shipperCashRepo,
+		shopServiceAdapter:    shopServiceAdapter,
+		paymentAdapter:        paymentAdapter,
+		defaultSearchRadiusKm: defaultSearchRadiusKm,
+		maxSearchRadiusKm:     maxSearchRadiusKm,
+	}
+}
+
+// ListAvailableDeliveries trả về các đơn SHIPPED chưa có người nhận, có điểm lấy hàng (shop) nằm trong bán kính
+// tìm kiếm, sắp xếp theo khoảng cách gần nhất. Shop chưa có toạ độ thì không thể đo khoảng cách nên bị bỏ qua.
+func (u *deliveryUseCase) ListAvailableDeliveries(ctx context.Context, shipperID string, query dto.AvailableDeliveriesQuery) ([]*domain.AvailableDelivery, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ListAvailableDeliveries.UseCase")
+	defer span.End()
+
+	if _, err := uuid.Parse(shipperID); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	radiusKm := query.RadiusKm
+	if radiusKm <= 0 {
+		radiusKm = u.defaultSearchRadiusKm
+	}
+	if u.maxSearchRadiusKm > 0 && radiusKm > u.maxSearchRadiusKm {
+		radiusKm = u.maxSearchRadiusKm
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAvailableDeliveriesLimit
+	}
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.Float64("delivery.radius_km", radiusKm),
+	)
+
+	shopIDs, err := u.deliveryRepo.ListReadyToShipShopIDs(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list shops with ready to ship orders: %s", err.Error()))
+	}
+
+	pickupAddresses, err := u.getPickupAddresses(ctx, shopIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get pickup addresses: %s", err.Error()))
+	}
+
+	// Lọc theo vị trí shop trước, chỉ đọc đơn của các shop trong bán kính, shop gần nhất trước
+	shipperLocation := domain.GeoPoint{Lat: *query.Lat, Long: *query.Long}
+	distances := make(map[string]float64, len(pickupAddresses))
+	nearbyShopIDs := make([]string, 0, len(pickupAddresses))
+	for shopID, pickup := range pickupAddresses {
+		if pickup.Location == nil {
+			continue
+		}
+		distance := math.Round(shipperLocation.DistanceKm(*pickup.Location)*100) / 100
+		if distance > radiusKm {
+			continue
+		}
+		distances[shopID] = distance
+		nearbyShopIDs = append(nearbyShopIDs, shopID)
+	}
+	sort.Slice(nearbyShopIDs, func(i, j int) bool {
+		if distances[nearbyShopIDs[i]] != distances[nearbyShopIDs[j]] {
+			return distances[nearbyShopIDs[i]] < distances[nearbyShopIDs[j]]
+		}
+		return nearbyShopIDs[i] < nearbyShopIDs[j]
+	})
+
+	orders, err := u.deliveryRepo.ListReadyToShipOrders(ctx, nearbyShopIDs, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list ready to ship orders: %s", err.Error()))
+	}
+
+	deliveries := make([]*domain.AvailableDelivery, 0, len(orders))
+	for _, order := range orders {
+		distance := distances[order.ShopID]
+		deliveries = append(deliveries, &domain.AvailableDelivery{
+			Order:         order,
+			PickupAddress: pickupAddresses[order.ShopID],
+			DistanceKm:    &distance,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("delivery.result_count", len(deliveries)))
+	return deliveries, nil
+}
+
+func (u *deliveryUseCase) ListMyDeliveries(ctx context.Context, shipperID string, query dto.ListDeliveriesQuery) ([]*domain.OrderDelivery, error) {
+	if _, err := uuid.Parse(shipperID); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	var status *domain.DeliveryStatus
+	if query.Status != "" {
+		s := domain.DeliveryStatus(query.Status)
+		if !s.IsValid() {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Invalid delivery status: %s", query.Status), nil)
+		}
+		status = &s
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAvailableDeliveriesLimit
+	}
+
+	deliveries, err := u.deliveryRepo.ListByShipper(ctx, shipperID, status, limit)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list deliveries: %s", err.Error()))
+	}
+	return deliveries, nil
+}
+
+// ClaimOrder: shipper nhận giao một đơn SHIPPED. Chỉ một shipper nhận được mỗi đơn,
+// nhận lại đơn mình đã nhận trả về kết quả cũ.
+func (u *deliveryUseCase) ClaimOrder(ctx context.Context, shipperID string, orderID string) (*domain.OrderDelivery, *domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ClaimOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.String("order.id", orderID),
+	)
+
+	if _, err := uuid.Parse(shipperID); err != nil {
+		return nil, nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	order, err := u.getOrder(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	delivery, err := u.deliveryRepo.ClaimOrder(ctx, orderID, shipperID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if !errors.Is(err, domain.ErrOrderNotClaimable) {
+			return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to claim order: %s", err.Error()))
+		}
+
+		existing, getErr := u.deliveryRepo.GetByOrderID(ctx, orderID)
+		if getErr == nil && existing.ShipperID == shipperID {
+			return existing, order, nil
+		}
+		if getErr == nil {
+			return nil, nil, apperror.New(apperror.CodeConflict, "Order has already been claimed by another shipper", apperror.TypeConflict)
+		}
+		return nil, nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order cannot be claimed in status %s", order.Status), apperror.TypeConflict)
+	}
+
+	span.AddEvent("Order claimed by shipper")
+	return delivery, order, nil
+}
+
+// PickUpOrder: shipper đã lấy hàng tại shop (delivery CLAIMED -> DELIVERING, order SHIPPED -> DELIVERING).
+func (u *deliveryUseCase) PickUpOrder(ctx context.Context, shipperID string, orderID string) (*domain.OrderDelivery, *domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "PickUpOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.String("order.id", orderID),
+	)
+
+	delivery, err := u.getShipperDelivery(ctx, shipperID, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	// Idempotency: báo lấy hàng lại trả về trạng thái hiện tại
+	if delivery.DeliveryStatus == domain.DeliveryStatusDelivering {
+		order, err := u.getOrder(ctx, orderID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return delivery, order, nil
+	}
+	if delivery.DeliveryStatus != domain.DeliveryStatusClaimed {
+		return nil, nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Delivery cannot be picked up in status %s", delivery.DeliveryStatus), apperror.TypeConflict)
+	}
+
+	delivery, order, err := u.deliveryRepo.MarkPickedUp(ctx, orderID, shipperID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, toDeliveryError(err, "Failed to mark order as picked up")
+	}
+
+	span.AddEvent("Order picked up by shipper")
+	return delivery, order, nil
+}
+
+// DeliverOrder: shipper giao hàng thành công kèm ảnh và toạ độ xác nhận
+// (delivery DELIVERING -> DELIVERED, order DELIVERING -> DELIVERED).
+func (u *deliveryUseCase) DeliverOrder(ctx context.Context, shipperID string, orderID string, req dto.DeliverOrderRequest) (*domain.OrderDelivery, *domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "DeliverOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("shipper.id", shipperID),
+		attribute.String("order.id", orderID),
+	)
+
+	delivery, err := u.getShipperDelivery(ctx, shipperID, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	if delivery.DeliveryStatus == domain.DeliveryStatusDelivered {
+		order, err := u.getOrder(ctx, orderID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return delivery, order, nil
+	}
+	if delivery.DeliveryStatus != domain.DeliveryStatusDelivering {
+		return nil, nil, apperror.New(apperror.CodeConflict, "Order must be picked up before it can be delivered", apperror.TypeConflict)
+	}
+
+	proof := domain.DeliveryProof{
+		PhotoURL: req.ProofPhotoURL,
+		Location: domain.GeoPoint{Lat: *req.Lat, Long: *req.Long},
+	}
+
+	delivery, order, err := u.deliveryRepo.MarkDelivered(ctx, orderID, shipperID, proof)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, toDeliveryError(err, "Failed to mark order as delivered")
+	}
+
+	span.AddEvent("Order delivered by shipper")
+	return delivery, order, nil
+}
+
+// getShipperDelivery trả về Forbidden nếu đơn hàng do shipper khác nhận.
+func (u *deliveryUseCase) getShipperDelivery(ctx context.Context, shipperID string, orderID string) (*domain.OrderDelivery, error) {
+	if _, err := uuid.Parse(shipperID); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	delivery, err := u.deliveryRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get delivery: %s", err.Error()))
+	}
+
+	if delivery.ShipperID != shipperID {
+		return nil, apperror.NewForbidden("This order is being delivered by another shipper")
+	}
+	return delivery, nil
+}
+
+func (u *deliveryUseCase) getOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+	return order, nil
+}
+
+// getPickupAddresses đọc địa chỉ lấy hàng của các shop trong một lần gọi shop-service.
+func (u *deliveryUseCase) getPickupAddresses(ctx context.Context, shopIDs []string) (map[string]*domain.PickupAddress, error) {
+	addresses, err := u.shopServiceAdapter.GetShopAddresses(ctx, shopIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pickups := make(map[string]*domain.PickupAddress, len(addresses))
+	for shopID, address := range addresses {
+		if address == nil {
+			continue
+		}
+		pickup := &domain.PickupAddress{
+			Street:   address.GetStreet(),
+			Ward:     address.GetWard(),
+			District: address.GetDistrict(),
+			City:     address.GetCity(),
+			Country:  address.GetCountry(),
+		}
+		if address.GetHasCoordinates() {
+			pickup.Location = &domain.GeoPoint{Lat: address.GetLat(), Long: address.GetLong()}
+		}
+		pickups[shopID] = pickup
+	}
+	return pickups, nil
+}
+
+func toDeliveryError(err error, message string) error {
+	if errors.Is(err, domain.ErrDeliveryStatusChanged) || errors.Is(err, domain.ErrOrderStatusChanged) {
+		return apperror.New(apperror.CodeConflict, "Delivery status has changed, please reload the order and try again", apperror.TypeConflict)
+	}
+	return apperror.NewInternal(fmt.Sprintf("%s: %s", message, err.Error()))
+}
@@ -0,0 +1,327 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+const (
+	testShipperID      = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+	testOtherShipperID = "6d5c4b3a-2f1e-4d0c-8b9a-7f6e5d4c3b2a"
+)
+
+// fakeDeliveryRepository giữ việc giao một đơn hàng, các thao tác được khoá như câu lệnh UPDATE có điều kiện trong DB.
+type fakeDeliveryRepository struct {
+	repository.DeliveryRepository
+
+	mu       sync.Mutex
+	order    *domain.Order
+	delivery *domain.OrderDelivery
+	// statusChanged mô phỏng một request khác đã đổi trạng thái giao hàng ngay trước lần cập nhật này
+	statusChanged bool
+}
+
+func (f *fakeDeliveryRepository) ClaimOrder(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if orderID != f.order.ID || f.order.Status != domain.OrderStatusSHIPPED || f.delivery != nil {
+		return nil, domain.ErrOrderNotClaimable
+	}
+	f.delivery = &domain.OrderDelivery{ID: "delivery-1", OrderID: orderID, ShipperID: shipperID, DeliveryStatus: domain.DeliveryStatusClaimed}
+	delivery := *f.delivery
+	return &delivery, nil
+}
+
+func (f *fakeDeliveryRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.OrderDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.delivery == nil || orderID != f.delivery.OrderID {
+		return nil, apperror.NewNotFound("Delivery", orderID)
+	}
+	delivery := *f.delivery
+	return &delivery, nil
+}
+
+func (f *fakeDeliveryRepository) MarkPickedUp(ctx context.Context, orderID string, shipperID string) (*domain.OrderDelivery, *domain.Order, error) {
+	return f.advance(shipperID, domain.DeliveryStatusClaimed, domain.DeliveryStatusDelivering, domain.OrderStatusDELIVERING, nil)
+}
+
+func (f *fakeDeliveryRepository) MarkDelivered(ctx context.Context, orderID string, shipperID string, proof domain.DeliveryProof) (*domain.OrderDelivery, *domain.Order, error) {
+	return f.advance(shipperID, domain.DeliveryStatusDelivering, domain.DeliveryStatusDelivered, domain.OrderStatusDELIVERED, &proof)
+}
+
+func (f *fakeDeliveryRepository) advance(shipperID string, from, to domain.DeliveryStatus, orderStatus domain.OrderStatus, proof *domain.DeliveryProof) (*domain.OrderDelivery, *domain.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.statusChanged || f.delivery.ShipperID != shipperID || f.delivery.DeliveryStatus != from {
+		return nil, nil, domain.ErrDeliveryStatusChanged
+	}
+	if err := f.order.TransitionTo(orderStatus); err != nil {
+		return nil, nil, err
+	}
+	f.delivery.DeliveryStatus = to
+	if proof != nil {
+		f.delivery.ProofPhotoURL = &proof.PhotoURL
+		f.delivery.DeliveredPoint = &proof.Location
+	}
+	delivery, order := *f.delivery, *f.order
+	return &delivery, &order, nil
+}
+
+func newTestDelivery(shipperID string, status domain.DeliveryStatus) *domain.OrderDelivery {
+	return &domain.OrderDelivery{ID: "delivery-1", OrderID: testOrderID, ShipperID: shipperID, DeliveryStatus: status}
+}
+
+func newDeliveryUseCase(repo *fakeDeliveryRepository) usecase.DeliveryUseCase {
+	return usecase.NewDeliveryUseCase(repo, &fakeOrderRepository{order: repo.order}, nil, nil, nil, 5, 50)
+}
+
+func TestDeliveryUseCase_Transitions(t *testing.T) {
+	lat, long := 10.7769, 106.7009
+	claim := func(uc usecase.DeliveryUseCase, shipperID string) (*domain.OrderDelivery, *domain.Order, error) {
+		return uc.ClaimOrder(context.Background(), shipperID, testOrderID)
+	}
+	pickUp := func(uc usecase.DeliveryUseCase, shipperID string) (*domain.OrderDelivery, *domain.Order, error) {
+		return uc.PickUpOrder(context.Background(), shipperID, testOrderID)
+	}
+	deliver := func(uc usecase.DeliveryUseCase, shipperID string) (*domain.OrderDelivery, *domain.Order, error) {
+		return uc.DeliverOrder(context.Background(), shipperID, testOrderID, dto.DeliverOrderRequest{
+			ProofPhotoURL: "https://cdn.example/proof.jpg",
+			Lat:           &lat,
+			Long:          &long,
+		})
+	}
+
+	testCases := []struct {
+		name                   string
+		run                    func(uc usecase.DeliveryUseCase, shipperID string) (*domain.OrderDelivery, *domain.Order, error)
+		shipperID              string
+		orderStatus            domain.OrderStatus
+		delivery               *domain.OrderDelivery
+		statusChanged          bool
+		expectedDeliveryStatus domain.DeliveryStatus
+		expectedOrderStatus    domain.OrderStatus
+		expectedType           apperror.ErrorType
+		expectError            bool
+	}{
+		{
+			name:                   "Claim shipped order",
+			run:                    claim,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusSHIPPED,
+			expectedDeliveryStatus: domain.DeliveryStatusClaimed,
+			expectedOrderStatus:    domain.OrderStatusSHIPPED,
+		},
+		{
+			name:                   "Claim again by the same shipper is idempotent",
+			run:                    claim,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusSHIPPED,
+			delivery:               newTestDelivery(testShipperID, domain.DeliveryStatusClaimed),
+			expectedDeliveryStatus: domain.DeliveryStatusClaimed,
+			expectedOrderStatus:    domain.OrderStatusSHIPPED,
+		},
+		{
+			name:         "Claim order claimed by another shipper",
+			run:          claim,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusSHIPPED,
+			delivery:     newTestDelivery(testOtherShipperID, domain.DeliveryStatusClaimed),
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:         "Claim order not handed to shipping yet",
+			run:          claim,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusCONFIRMED,
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:         "Claim with invalid shipper ID",
+			run:          claim,
+			shipperID:    "shipper-1",
+			orderStatus:  domain.OrderStatusSHIPPED,
+			expectedType: apperror.TypeUnauthorized,
+			expectError:  true,
+		},
+		{
+			name:                   "Pick up claimed order",
+			run:                    pickUp,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusSHIPPED,
+			delivery:               newTestDelivery(testShipperID, domain.DeliveryStatusClaimed),
+			expectedDeliveryStatus: domain.DeliveryStatusDelivering,
+			expectedOrderStatus:    domain.OrderStatusDELIVERING,
+		},
+		{
+			name:                   "Pick up again is idempotent",
+			run:                    pickUp,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusDELIVERING,
+			delivery:               newTestDelivery(testShipperID, domain.DeliveryStatusDelivering),
+			expectedDeliveryStatus: domain.DeliveryStatusDelivering,
+			expectedOrderStatus:    domain.OrderStatusDELIVERING,
+		},
+		{
+			name:         "Pick up order of another shipper",
+			run:          pickUp,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusSHIPPED,
+			delivery:     newTestDelivery(testOtherShipperID, domain.DeliveryStatusClaimed),
+			expectedType: apperror.TypeForbidden,
+			expectError:  true,
+		},
+		{
+			name:         "Pick up unclaimed order",
+			run:          pickUp,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusSHIPPED,
+			expectedType: apperror.TypeNotFound,
+			expectError:  true,
+		},
+		{
+			name:         "Pick up delivered order",
+			run:          pickUp,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusDELIVERED,
+			delivery:     newTestDelivery(testShipperID, domain.DeliveryStatusDelivered),
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:          "Pick up while the delivery changed concurrently",
+			run:           pickUp,
+			shipperID:     testShipperID,
+			orderStatus:   domain.OrderStatusSHIPPED,
+			delivery:      newTestDelivery(testShipperID, domain.DeliveryStatusClaimed),
+			statusChanged: true,
+			expectedType:  apperror.TypeConflict,
+			expectError:   true,
+		},
+		{
+			name:                   "Deliver picked up order",
+			run:                    deliver,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusDELIVERING,
+			delivery:               newTestDelivery(testShipperID, domain.DeliveryStatusDelivering),
+			expectedDeliveryStatus: domain.DeliveryStatusDelivered,
+			expectedOrderStatus:    domain.OrderStatusDELIVERED,
+		},
+		{
+			name:                   "Deliver again is idempotent",
+			run:                    deliver,
+			shipperID:              testShipperID,
+			orderStatus:            domain.OrderStatusDELIVERED,
+			delivery:               newTestDelivery(testShipperID, domain.DeliveryStatusDelivered),
+			expectedDeliveryStatus: domain.DeliveryStatusDelivered,
+			expectedOrderStatus:    domain.OrderStatusDELIVERED,
+		},
+		{
+			name:         "Deliver before pick up",
+			run:          deliver,
+			shipperID:    testShipperID,
+			orderStatus:  domain.OrderStatusSHIPPED,
+			delivery:     newTestDelivery(testShipperID, domain.DeliveryStatusClaimed),
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:         "Deliver order of another shipper",
+			run:          deliver,
+			shipperID:    testOtherShipperID,
+			orderStatus:  domain.OrderStatusDELIVERING,
+			delivery:     newTestDelivery(testShipperID, domain.DeliveryStatusDelivering),
+			expectedType: apperror.TypeForbidden,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeDeliveryRepository{
+				order:         &domain.Order{ID: testOrderID, Status: tc.orderStatus},
+				delivery:      tc.delivery,
+				statusChanged: tc.statusChanged,
+			}
+			uc := newDeliveryUseCase(repo)
+
+			delivery, order, err := tc.run(uc, tc.shipperID)
+
+			if tc.expectError {
+				if apperror.GetType(err) != tc.expectedType {
+					t.Fatalf("error = %v, want type %v", err, tc.expectedType)
+				}
+				if repo.order.Status != tc.orderStatus {
+					t.Errorf("order status = %s, want unchanged %s", repo.order.Status, tc.orderStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if delivery.ShipperID != tc.shipperID || delivery.DeliveryStatus != tc.expectedDeliveryStatus {
+				t.Errorf("delivery = %s by %s, want %s by %s", delivery.DeliveryStatus, delivery.ShipperID, tc.expectedDeliveryStatus, tc.shipperID)
+			}
+			if order.Status != tc.expectedOrderStatus || repo.order.Status != tc.expectedOrderStatus {
+				t.Errorf("order status = %s (stored %s), want %s", order.Status, repo.order.Status, tc.expectedOrderStatus)
+			}
+			if tc.expectedDeliveryStatus == domain.DeliveryStatusDelivered && tc.delivery.DeliveryStatus != domain.DeliveryStatusDelivered {
+				if delivery.ProofPhotoURL == nil || *delivery.ProofPhotoURL != "https://cdn.example/proof.jpg" {
+					t.Errorf("proof photo = %v, want the uploaded photo", delivery.ProofPhotoURL)
+				}
+				if delivery.DeliveredPoint == nil || delivery.DeliveredPoint.Lat != lat || delivery.DeliveredPoint.Long != long {
+					t.Errorf("delivered point = %v, want %v,%v", delivery.DeliveredPoint, lat, long)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliveryUseCase_ClaimOrder_Concurrent(t *testing.T) {
+	const shippers = 8
+
+	repo := &fakeDeliveryRepository{order: &domain.Order{ID: testOrderID, Status: domain.OrderStatusSHIPPED}}
+	uc := newDeliveryUseCase(repo)
+
+	var wg sync.WaitGroup
+	errs := make([]error, shippers)
+	for i := 0; i < shippers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			shipperID := fmt.Sprintf("2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c%02d", i)
+			_, _, errs[i] = uc.ClaimOrder(context.Background(), shipperID, testOrderID)
+		}(i)
+	}
+	wg.Wait()
+
+	claimed := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			claimed++
+		case apperror.GetType(err) != apperror.TypeConflict:
+			t.Errorf("shipper %d error = %v, want conflict", i, err)
+		}
+	}
+	// Mỗi đơn chỉ có đúng một shipper nhận được, các shipper còn lại nhận lỗi conflict
+	if claimed != 1 {
+		t.Errorf("claimed = %d, want exactly one shipper", claimed)
+	}
+	if repo.delivery == nil || repo.delivery.DeliveryStatus != domain.DeliveryStatusClaimed {
+		t.Errorf("delivery = %+v, want CLAIMED", repo.delivery)
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	DEFAULT_INBOX_EVENT_PAGE_SIZE = 20
+	MAX_INBOX_EVENT_PAGE_SIZE     = 100
+	DEFAULT_INBOX_REPLAY_BATCH    = 100
+)
+
+// ListInboxEvents liệt kê inbox event cho admin theo trạng thái, loại event và thời gian nhận.
+func (uc *inboxEventUseCase) ListInboxEvents(ctx context.Context, query dto.ListInboxEventsQuery) (*domain.InboxEventPage, error) {
+	filter := domain.InboxEventListFilter{
+		EventType: strings.TrimSpace(query.EventType),
+		Limit:     query.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DEFAULT_INBOX_EVENT_PAGE_SIZE
+	}
+	if filter.Limit > MAX_INBOX_EVENT_PAGE_SIZE {
+		filter.Limit = MAX_INBOX_EVENT_PAGE_SIZE
+	}
+
+	if query.Status != "" {
+		status := domain.InboxEventStatus(strings.ToUpper(query.Status))
+		if !status.IsValid() {
+			return nil, apperror.NewBadRequest("Invalid inbox event status filter", fmt.Errorf("unknown status %q", query.Status))
+		}
+		filter.Status = &status
+	}
+
+	from, to, err := parseReceivedRange(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	filter.ReceivedFrom, filter.ReceivedTo = from, to
+
+	if query.Cursor != "" {
+		cursor, err := domain.DecodeInboxEventCursor(query.Cursor)
+		if err != nil {
+			return nil, apperror.NewBadRequest("Invalid cursor", err)
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := uc.inboxRepo.ListInboxEvents(ctx, filter)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list inbox events: %s", err.Error()))
+	}
+	return page, nil
+}
+
+// GetInboxEvent trả về inbox event cùng nhật ký các thao tác admin đã thực hiện trên event.
+func (uc *inboxEventUseCase) GetInboxEvent(ctx context.Context, id string) (*domain.InboxEvent, []*domain.InboxEventAudit, error) {
+	event, err := uc.inboxRepo.GetInboxEventByID(ctx, id)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, nil, err
+		}
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to get inbox event: %s", err.Error()))
+	}
+
+	audits, err := uc.inboxRepo.GetInboxEventAudits(ctx, id)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to get inbox event audits: %s", err.Error()))
+	}
+	return event, audits, nil
+}
+
+// ReplayInboxEvent đưa một event FAILED hoặc PARKED về PENDING để inbox worker xử lý lại ở lượt chạy kế tiếp.
+func (uc *inboxEventUseCase) ReplayInboxEvent(ctx context.Context, adminID string, id string, req dto.InboxEventActionRequest) (*domain.InboxEvent, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ReplayInboxEvent.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", adminID),
+		attribute.String("inbox_event.id", id),
+	)
+
+	current, err := uc.deadLetterEvent(ctx, id, "replayed")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	event, err := uc.inboxRepo.ReplayInboxEvent(ctx, id, current.EventStatus, domain.StatusChange{
+		Actor:  domain.AdminActor(adminID),
+		Reason: strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, uc.inboxTransitionError(ctx, id, "replayed", err)
+	}
+
+	log.Printf("[InboxAdmin] Event %s replayed by admin %s", event.EventID, adminID)
+	return event, nil
+}
+
+// ReplayFailedInboxEvents replay hàng loạt các event FAILED (hoặc PARKED khi req.Status = PARKED) khớp bộ lọc, cũ nhất trước.
+func (uc *inboxEventUseCase) ReplayFailedInboxEvents(ctx context.Context, adminID string, req dto.ReplayInboxEventsRequest) ([]*domain.InboxEvent, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ReplayFailedInboxEvents.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", adminID),
+		attribute.String("inbox_event.type_filter", req.EventType),
+	)
+
+	from, to, err := parseReceivedRange(req.From, req.To)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	filter := domain.InboxEventReplayFilter{
+		Status:       domain.InboxEventStatusFailed,
+		EventType:    strings.TrimSpace(req.EventType),
+		ReceivedFrom: from,
+		ReceivedTo:   to,
+		Limit:        req.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DEFAULT_INBOX_REPLAY_BATCH
+	}
+	if req.Status != "" {
+		filter.Status = domain.InboxEventStatus(req.Status)
+	}
+
+	events, err := uc.inboxRepo.ReplayFailedInboxEvents(ctx, filter, domain.StatusChange{
+		Actor:  domain.AdminActor(adminID),
+		Reason: strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to replay inbox events: %s", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int("inbox_event.replayed_count", len(events)))
+	log.Printf("[InboxAdmin] %d failed events replayed by admin %s", len(events), adminID)
+	return events, nil
+}
+
+// DiscardInboxEvent huỷ bỏ vĩnh viễn một event FAILED hoặc PARKED, event được giữ lại với trạng thái DISCARDED để chống nhận trùng.
+func (uc *inboxEventUseCase) DiscardInboxEvent(ctx context.Context, adminID string, id string, req dto.InboxEventActionRequest) (*domain.InboxEvent, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "DiscardInboxEvent.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", adminID),
+		attribute.String("inbox_event.id", id),
+	)
+
+	current, err := uc.deadLetterEvent(ctx, id, "discarded")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	event, err := uc.inboxRepo.DiscardInboxEvent(ctx, id, current.EventStatus, domain.StatusChange{
+		Actor:  domain.AdminActor(adminID),
+		Reason: strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, uc.inboxTransitionError(ctx, id, "discarded", err)
+	}
+
+	log.Printf("[InboxAdmin] Event %s discarded by admin %s", event.EventID, adminID)
+	return event, nil
+}
+
+// deadLetterEvent lấy event admin muốn thao tác, chỉ event FAILED hoặc PARKED mới được replay/discard.
+func (uc *inboxEventUseCase) deadLetterEvent(ctx context.Context, id string, action string) (*domain.InboxEvent, error) {
+	event, err := uc.inboxRepo.GetInboxEventByID(ctx, id)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get inbox event: %s", err.Error()))
+	}
+	if !event.EventStatus.IsDeadLetter() {
+		return nil, deadLetterConflict(action, event.EventStatus)
+	}
+	return event, nil
+}
+
+func deadLetterConflict(action string, status domain.InboxEventStatus) error {
+	return apperror.New(apperror.CodeConflict, fmt.Sprintf("Only FAILED or PARKED inbox events can be %s, event is %s", action, status), apperror.TypeConflict)
+}
+
+// inboxTransitionError phân biệt event không tồn tại với event đã đổi trạng thái trong lúc admin thao tác.
+func (uc *inboxEventUseCase) inboxTransitionError(ctx context.Context, id string, action string, err error) error {
+	if !errors.Is(err, domain.ErrInboxEventStatusChanged) {
+		return apperror.NewInternal(fmt.Sprintf("Failed to update inbox event: %s", err.Error()))
+	}
+
+	event, getErr := uc.inboxRepo.GetInboxEventByID(ctx, id)
+	if getErr != nil {
+		if apperror.GetType(getErr) == apperror.TypeNotFound {
+			return getErr
+		}
+		return apperror.NewInternal(fmt.Sprintf("Failed to get inbox event: %s", getErr.Error()))
+	}
+	return deadLetterConflict(action, event.EventStatus)
+}
+
+// parseReceivedRange parse khoảng thời gian nhận event [from, to), cùng định dạng ngày với danh sách đơn hàng.
+func parseReceivedRange(fromParam string, toParam string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromParam != "" {
+		t, err := parseDateParam(fromParam)
+		if err != nil {
+			return nil, nil, apperror.NewBadRequest("Invalid 'from' date", err)
+		}
+		from = &t
+	}
+	if toParam != "" {
+		t, err := parseDateParam(toParam)
+		if err != nil {
+			return nil, nil, apperror.NewBadRequest("Invalid 'to' date", err)
+		}
+		to = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, apperror.NewBadRequest("Invalid date range", errors.New("'from' must be before 'to'"))
+	}
+	return from, to, nil
+}
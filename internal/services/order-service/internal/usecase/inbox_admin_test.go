@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+const testInboxEventID = "0b0f6f2e-8d8e-4c4f-9a57-0d6f0a5b2c11"
+
+type fakeInboxEventRepository struct {
+	repository.InboxEventRepository
+	event     *domain.InboxEvent
+	replayed  bool
+	discarded bool
+}
+
+func (f *fakeInboxEventRepository) GetInboxEventByID(ctx context.Context, id string) (*domain.InboxEvent, error) {
+	if f.event == nil || id != testInboxEventID {
+		return nil, apperror.NewNotFound("Inbox event", id)
+	}
+	event := *f.event
+	return &event, nil
+}
+
+func (f *fakeInboxEventRepository) ReplayInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error) {
+	if f.event.EventStatus != fromStatus {
+		return nil, domain.ErrInboxEventStatusChanged
+	}
+	f.replayed = true
+	f.event.EventStatus = domain.InboxEventStatusPending
+	event := *f.event
+	return &event, nil
+}
+
+func (f *fakeInboxEventRepository) DiscardInboxEvent(ctx context.Context, id string, fromStatus domain.InboxEventStatus, change domain.StatusChange) (*domain.InboxEvent, error) {
+	if f.event.EventStatus != fromStatus {
+		return nil, domain.ErrInboxEventStatusChanged
+	}
+	f.discarded = true
+	f.event.EventStatus = domain.InboxEventStatusDiscarded
+	event := *f.event
+	return &event, nil
+}
+
+type inboxAdminAction struct {
+	name     string
+	run      func(uc usecase.InboxEventUseCase) (*domain.InboxEvent, error)
+	applied  func(repo *fakeInboxEventRepository) bool
+	expected domain.InboxEventStatus
+}
+
+var inboxAdminActions = []inboxAdminAction{
+	{
+		name: "Replay",
+		run: func(uc usecase.InboxEventUseCase) (*domain.InboxEvent, error) {
+			return uc.ReplayInboxEvent(context.Background(), "admin-1", testInboxEventID, dto.InboxEventActionRequest{Reason: "handler fixed"})
+		},
+		applied:  func(repo *fakeInboxEventRepository) bool { return repo.replayed },
+		expected: domain.InboxEventStatusPending,
+	},
+	{
+		name: "Discard",
+		run: func(uc usecase.InboxEventUseCase) (*domain.InboxEvent, error) {
+			return uc.DiscardInboxEvent(context.Background(), "admin-1", testInboxEventID, dto.InboxEventActionRequest{Reason: "duplicate"})
+		},
+		applied:  func(repo *fakeInboxEventRepository) bool { return repo.discarded },
+		expected: domain.InboxEventStatusDiscarded,
+	},
+}
+
+func TestInboxEventUseCase_DeadLetterActions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		status       domain.InboxEventStatus
+		expectedType apperror.ErrorType
+		expectError  bool
+	}{
+		{name: "Failed event", status: domain.InboxEventStatusFailed},
+		{name: "Parked event", status: domain.InboxEventStatusParked},
+		{name: "Pending event is rejected", status: domain.InboxEventStatusPending, expectedType: apperror.TypeConflict, expectError: true},
+		{name: "Processed event is rejected", status: domain.InboxEventStatusProcessed, expectedType: apperror.TypeConflict, expectError: true},
+		{name: "Discarded event is rejected", status: domain.InboxEventStatusDiscarded, expectedType: apperror.TypeConflict, expectError: true},
+	}
+
+	for _, action := range inboxAdminActions {
+		for _, tc := range testCases {
+			t.Run(action.name+"/"+tc.name, func(t *testing.T) {
+				repo := &fakeInboxEventRepository{
+					event: &domain.InboxEvent{ID: testInboxEventID, EventID: "evt-1", EventStatus: tc.status},
+				}
+				uc := usecase.NewInboxEventUseCase(inbox.NewRegistry(), repo, nil, nil)
+
+				event, err := action.run(uc)
+
+				if tc.expectError {
+					if err == nil {
+						t.Fatalf("expected error, got event %+v", event)
+					}
+					if got := apperror.GetType(err); got != tc.expectedType {
+						t.Errorf("error type = %v, want %v", got, tc.expectedType)
+					}
+					if action.applied(repo) {
+						t.Error("repository was updated for a rejected event")
+					}
+					if repo.event.EventStatus != tc.status {
+						t.Errorf("status = %s, want unchanged %s", repo.event.EventStatus, tc.status)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if event.EventStatus != action.expected {
+					t.Errorf("status = %s, want %s", event.EventStatus, action.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestInboxEventUseCase_DeadLetterActions_NotFound(t *testing.T) {
+	for _, action := range inboxAdminActions {
+		t.Run(action.name, func(t *testing.T) {
+			uc := usecase.NewInboxEventUseCase(inbox.NewRegistry(), &fakeInboxEventRepository{}, nil, nil)
+
+			if _, err := action.run(uc); apperror.GetType(err) != apperror.TypeNotFound {
+				t.Errorf("error = %v, want not found", err)
+			}
+		})
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
 )
 
@@ -17,8 +21,16 @@ const (
 	INBOX_BATCH_SIZE       = 100
 	INBOX_MAX_RETRY        = 5
 	SOURCE_PAYMENT_SERVICE = "payment-service"
+	// LEGACY_EVENT_VERSION là version của event gửi trước khi bên gửi khai báo event_version (xem migration 00019).
+	LEGACY_EVENT_VERSION = 1
 )
 
+// isLegacyVersionSource cho biết service có gửi event trước khi có event_version hay không,
+// message không khai báo version của các service này là version 1 thay vì bị park.
+func isLegacyVersionSource(source string) bool {
+	return source == SOURCE_PAYMENT_SERVICE
+}
+
 type InboxEventUseCase interface {
 	// Kafka message handler - idempotent processing
 	HandleIncomingEvent(ctx context.Context, key, value []byte) error
@@ -30,75 +42,128 @@ type InboxEventUseCase interface {
 
 	// Stats and monitoring
 	GetInboxStats(ctx context.Context) (*repository.InboxEventStats, error)
+
+	// Admin dead-letter management
+	ListInboxEvents(ctx context.Context, query dto.ListInboxEventsQuery) (*domain.InboxEventPage, error)
+	GetInboxEvent(ctx context.Context, id string) (*domain.InboxEvent, []*domain.InboxEventAudit, error)
+	ReplayInboxEvent(ctx context.Context, adminID string, id string, req dto.InboxEventActionRequest) (*domain.InboxEvent, error)
+	ReplayFailedInboxEvents(ctx context.Context, adminID string, req dto.ReplayInboxEventsRequest) ([]*domain.InboxEvent, error)
+	DiscardInboxEvent(ctx context.Context, adminID string, id string, req dto.InboxEventActionRequest) (*domain.InboxEvent, error)
 }
 
 type inboxEventUseCase struct {
-	inboxRepo repository.InboxEventRepository
-	orderRepo repository.OrderRepository
+	registry   *inbox.Registry
+	inboxRepo  repository.InboxEventRepository
+	orderRepo  repository.OrderRepository
+	returnRepo repository.ReturnRepository
 }
 
+// NewInboxEventUseCase tạo usecase và đăng ký các handler inbox của order-service vào registry.
 func NewInboxEventUseCase(
+	registry *inbox.Registry,
 	inboxRepo repository.InboxEventRepository,
 	orderRepo repository.OrderRepository,
+	returnRepo repository.ReturnRepository,
 ) InboxEventUseCase {
-	return &inboxEventUseCase{
-		inboxRepo: inboxRepo,
-		orderRepo: orderRepo,
+	uc := &inboxEventUseCase{
+		registry:   registry,
+		inboxRepo:  inboxRepo,
+		orderRepo:  orderRepo,
+		returnRepo: returnRepo,
 	}
+	uc.registerHandlers()
+	return uc
+}
+
+// registerHandlers khai báo các event order-service xử lý. Thêm loại event hoặc version mới chỉ cần đăng ký
+// thêm handler ở đây, KafkaConsumer tự subscribe topic tương ứng.
+func (uc *inboxEventUseCase) registerHandlers() {
+	uc.registry.Register(
+		string(constant.EventTypeRefundSuccessed),
+		inbox.Key{
+			Source:    SOURCE_PAYMENT_SERVICE,
+			EventType: string(constant.EventTypeRefundSuccessed),
+			Version:   constant.EventVersionRefundSucceeded,
+		},
+		uc.handleRefundSucceededEvent,
+	)
+	// PAYMENT_SUCCESS được payment-service gửi trên topic của nó mà không khai báo event_version
+	uc.registry.Register(
+		string(constant.EventTypeRefundSuccessed),
+		inbox.Key{
+			Source:    SOURCE_PAYMENT_SERVICE,
+			EventType: string(domain.InboxEventTypePaymentSuccess),
+			Version:   LEGACY_EVENT_VERSION,
+		},
+		uc.handlePaymentSuccessEvent,
+	)
 }
 
-// HandleIncomingEvent - Main Kafka message handler with idempotency
+// HandleIncomingEvent - Main Kafka message handler with idempotency. Message không đọc được envelope hoặc
+// không có handler đăng ký được lưu với trạng thái PARKED thay vì trả lỗi, để offset vẫn được commit và
+// message lỗi không chặn cả partition.
 func (uc *inboxEventUseCase) HandleIncomingEvent(ctx context.Context, key, value []byte) error {
 	log.Printf("[InboxHandler] Received message with key: %s", string(key))
 
-	// Parse the incoming event
-	var kafkaPayload map[string]interface{}
-	if err := json.Unmarshal(value, &kafkaPayload); err != nil {
-		log.Printf("[InboxHandler] Failed to unmarshal message: %v", err)
-		return fmt.Errorf("failed to unmarshal kafka message: %w", err)
+	envelope, envelopeErr := inbox.ParseEnvelope(value)
+	if errors.Is(envelopeErr, inbox.ErrMissingVersion) && isLegacyVersionSource(envelope.Source) {
+		envelope.EventVersion = LEGACY_EVENT_VERSION
+		envelopeErr = nil
 	}
 
 	// Extract event metadata
-	eventID := generateEventID(key, value) // Generate deterministic ID for idempotency
-	eventType := determineEventType(kafkaPayload)
-
-	if eventType == "" {
-		log.Printf("[InboxHandler] Unknown event type from payload: %v", kafkaPayload)
-		return fmt.Errorf("unknown event type")
-	}
+	eventID := generateEventID(envelope.EventID, key, value) // Generate deterministic ID for idempotency
 
 	// Check if event already exists (idempotency check)
 	existingEvent, err := uc.inboxRepo.GetInboxEventByEventID(ctx, eventID)
 	if err == nil && existingEvent != nil {
 		log.Printf("[InboxHandler] Event %s already exists with status %s", eventID, existingEvent.EventStatus)
 
-		// If already processed, just return success
-		if existingEvent.EventStatus == domain.InboxEventStatusProcessed {
-			return nil
-		}
-
-		// If pending or failed, let it be processed by worker
+		// Event đã được lưu (kể cả PARKED/FAILED) thì để worker hoặc admin xử lý tiếp
 		return nil
 	}
 
 	// Create new inbox event
 	inboxEvent := &domain.InboxEvent{
 		EventID:       eventID,
-		EventType:     eventType,
-		SourceService: SOURCE_PAYMENT_SERVICE,
+		EventType:     envelope.EventType,
+		SourceService: envelope.Source,
+		EventVersion:  envelope.EventVersion,
 		Payload:       string(value),
 		EventStatus:   domain.InboxEventStatusPending,
 		RetryCount:    0,
 		MaxRetry:      INBOX_MAX_RETRY,
 	}
 
+	parkErr := envelopeErr
+	if parkErr == nil {
+		if _, ok := uc.registry.Handler(envelope.Key()); !ok {
+			parkErr = fmt.Errorf("%w for %s", inbox.ErrNoHandler, envelope.Key())
+		}
+	}
+	if parkErr != nil {
+		reason := parkErr.Error()
+		inboxEvent.EventStatus = domain.InboxEventStatusParked
+		inboxEvent.LastError = &reason
+		if !json.Valid(value) {
+			// Cột payload là JSONB nên message không phải JSON được lưu dưới dạng chuỗi JSON
+			quoted, _ := json.Marshal(string(value))
+			inboxEvent.Payload = string(quoted)
+		}
+	}
+
 	_, err = uc.inboxRepo.CreateInboxEvent(ctx, inboxEvent)
 	if err != nil {
 		log.Printf("[InboxHandler] Failed to create inbox event: %v", err)
 		return fmt.Errorf("failed to create inbox event: %w", err)
 	}
 
-	log.Printf("[InboxHandler] Successfully created inbox event %s of type %s", eventID, eventType)
+	if parkErr != nil {
+		log.Printf("[InboxHandler] Parked inbox event %s: %v", eventID, parkErr)
+		return nil
+	}
+
+	log.Printf("[InboxHandler] Successfully created inbox event %s of type %s v%d", eventID, envelope.EventType, envelope.EventVersion)
 	return nil
 }
 
@@ -119,7 +184,9 @@ func (uc *inboxEventUseCase) ProcessPendingInboxEvents(ctx context.Context) erro
 
 	for _, event := range events {
 		err := uc.processEvent(ctx, event)
-		if err != nil {
+		if errors.Is(err, inbox.ErrNoHandler) {
+			uc.markEventAsParked(ctx, event, err)
+		} else if err != nil {
 			log.Printf("[InboxWorker] Failed to process event %s: %v", event.EventID, err)
 			uc.markEventAsFailed(ctx, event, err)
 		} else {
@@ -148,7 +215,9 @@ func (uc *inboxEventUseCase) RetryFailedInboxEvents(ctx context.Context) error {
 
 	for _, event := range events {
 		err := uc.processEvent(ctx, event)
-		if err != nil {
+		if errors.Is(err, inbox.ErrNoHandler) {
+			uc.markEventAsParked(ctx, event, err)
+		} else if err != nil {
 			log.Printf("[InboxRetryWorker] Failed to retry event %s (attempt %d): %v",
 				event.EventID, event.RetryCount+1, err)
 			uc.markEventAsFailed(ctx, event, err)
@@ -160,23 +229,19 @@ func (uc *inboxEventUseCase) RetryFailedInboxEvents(ctx context.Context) error {
 	return nil
 }
 
-// processEvent - Core business logic for processing different event types
+// processEvent - Gọi handler đã đăng ký cho (source, event_type, version) của event
 func (uc *inboxEventUseCase) processEvent(ctx context.Context, event *domain.InboxEvent) error {
-	log.Printf("[InboxProcessor] Processing event %s of type %s", event.EventID, event.EventType)
-
-	switch event.EventType {
-	case string(domain.InboxEventTypeRefundSucceeded):
-		return uc.handleRefundSucceededEvent(ctx, event)
-	case string(domain.InboxEventTypePaymentSuccess):
-		return uc.handlePaymentSuccessEvent(ctx, event)
-	default:
-		return fmt.Errorf("unsupported event type: %s", event.EventType)
+	// Event thiếu version bị park trước khi payment-service được coi là nguồn cũ vẫn replay được
+	if event.EventVersion == 0 && isLegacyVersionSource(event.SourceService) {
+		event.EventVersion = LEGACY_EVENT_VERSION
 	}
+	log.Printf("[InboxProcessor] Processing event %s of type %s v%d from %s", event.EventID, event.EventType, event.EventVersion, event.SourceService)
+	return uc.registry.Dispatch(ctx, event)
 }
 
 // handleRefundSucceededEvent - Process refund succeeded events
 func (uc *inboxEventUseCase) handleRefundSucceededEvent(ctx context.Context, event *domain.InboxEvent) error {
-	var payload domain.EventPayload
+	var payload domain.RefundEventPayload
 	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal refund succeeded payload: %w", err)
 	}
@@ -185,10 +250,37 @@ func (uc *inboxEventUseCase) handleRefundSucceededEvent(ctx context.Context, eve
 		return fmt.Errorf("order_id is required in refund succeeded event")
 	}
 
-	log.Printf("[InboxProcessor] Processing refund succeeded for OrderID: %s", payload.OrderID)
+	log.Printf("[InboxProcessor] Processing refund succeeded for OrderID: %s (refund: %s, partial: %t)", payload.OrderID, payload.RefundID, payload.Partial)
+
+	// Hoàn tiền cho yêu cầu trả hàng
+	if returnID, ok := domain.ParseReturnReferenceID(payload.ReferenceID); ok {
+		if err := uc.markReturnRefunded(ctx, returnID); err != nil {
+			return err
+		}
+	}
+
+	// Hoàn tiền cho phần dòng hàng bị người bán huỷ
+	if cancellationID, ok := domain.ParseItemCancellationReferenceID(payload.ReferenceID); ok {
+		if err := uc.markItemCancellationRefunded(ctx, cancellationID); err != nil {
+			return err
+		}
+	}
+
+	// Hoàn một phần thì đơn hàng giữ nguyên trạng thái
+	if payload.Partial {
+		return nil
+	}
 
 	// Update order status to REFUNDED
-	_, err := uc.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatus(domain.OrderStatusREFUNDED))
+	_, err := uc.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatus(domain.OrderStatusREFUNDED), domain.StatusChange{
+		Actor:  domain.StatusActorInboxWorker,
+		Reason: fmt.Sprintf("%s event %s", event.EventType, event.EventID),
+	})
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		// Trạng thái hiện tại không cho phép chuyển sang REFUNDED, retry cũng không giúp được
+		log.Printf("[InboxProcessor] Skipping status update for OrderID %s: %v", payload.OrderID, err)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update order status to REFUNDED: %w", err)
 	}
@@ -211,7 +303,15 @@ func (uc *inboxEventUseCase) handlePaymentSuccessEvent(ctx context.Context, even
 	log.Printf("[InboxProcessor] Processing payment success for OrderID: %s", payload.OrderID)
 
 	// Update order status to PROCESSING
-	_, err := uc.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatusPROCESSING)
+	_, err := uc.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatusPROCESSING, domain.StatusChange{
+		Actor:  domain.StatusActorInboxWorker,
+		Reason: fmt.Sprintf("%s event %s", event.EventType, event.EventID),
+	})
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		// Đơn đã được chuyển sang PROCESSING qua gRPC của payment-service hoặc đã bị huỷ, retry cũng không giúp được
+		log.Printf("[InboxProcessor] Skipping status update for OrderID %s: %v", payload.OrderID, err)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update order status to PROCESSING: %w", err)
 	}
@@ -220,6 +320,43 @@ func (uc *inboxEventUseCase) handlePaymentSuccessEvent(ctx context.Context, even
 	return nil
 }
 
+// markReturnRefunded chuyển yêu cầu trả hàng sang REFUNDED, bỏ qua nếu đã REFUNDED từ event trước.
+func (uc *inboxEventUseCase) markReturnRefunded(ctx context.Context, returnID string) error {
+	_, err := uc.returnRepo.MarkRefunded(ctx, returnID)
+	if err == nil {
+		log.Printf("[InboxProcessor] Successfully marked return %s as REFUNDED", returnID)
+		return nil
+	}
+	if !errors.Is(err, domain.ErrReturnStatusChanged) {
+		return fmt.Errorf("failed to mark return %s as refunded: %w", returnID, err)
+	}
+
+	orderReturn, getErr := uc.returnRepo.GetByID(ctx, returnID)
+	if getErr != nil {
+		return fmt.Errorf("failed to get return %s: %w", returnID, getErr)
+	}
+	if orderReturn.Status != domain.ReturnStatusRefunded {
+		// Chỉ yêu cầu đã RECEIVED mới được hoàn tiền, retry cũng không giúp được
+		log.Printf("[InboxProcessor] CRITICAL: Refund succeeded for return %s in status %s", returnID, orderReturn.Status)
+	}
+	return nil
+}
+
+// markItemCancellationRefunded ghi nhận lần huỷ dòng hàng đã được hoàn tiền, bỏ qua nếu đã ghi nhận từ event trước.
+func (uc *inboxEventUseCase) markItemCancellationRefunded(ctx context.Context, cancellationID string) error {
+	_, err := uc.orderRepo.MarkItemCancellationRefunded(ctx, cancellationID)
+	if errors.Is(err, domain.ErrItemCancellationAlreadyRefunded) {
+		log.Printf("[InboxProcessor] Item cancellation %s has already been marked as refunded", cancellationID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark item cancellation %s as refunded: %w", cancellationID, err)
+	}
+
+	log.Printf("[InboxProcessor] Successfully marked item cancellation %s as refunded", cancellationID)
+	return nil
+}
+
 // markEventAsProcessed - Mark event as successfully processed
 func (uc *inboxEventUseCase) markEventAsProcessed(ctx context.Context, event *domain.InboxEvent) {
 	event.EventStatus = domain.InboxEventStatusProcessed
@@ -234,6 +371,8 @@ func (uc *inboxEventUseCase) markEventAsProcessed(ctx context.Context, event *do
 // markEventAsFailed - Mark event as failed and increment retry count
 func (uc *inboxEventUseCase) markEventAsFailed(ctx context.Context, event *domain.InboxEvent, processingErr error) {
 	event.RetryCount++
+	lastError := processingErr.Error()
+	event.LastError = &lastError
 	if event.RetryCount >= event.MaxRetry {
 		event.EventStatus = domain.InboxEventStatusFailed
 		log.Printf("[InboxProcessor] CRITICAL: Event %s failed permanently after %d retries. Error: %v",
@@ -246,6 +385,19 @@ func (uc *inboxEventUseCase) markEventAsFailed(ctx context.Context, event *domai
 	}
 }
 
+// markEventAsParked - Event không còn handler phù hợp (ví dụ admin replay trước khi deploy handler) thì park lại
+func (uc *inboxEventUseCase) markEventAsParked(ctx context.Context, event *domain.InboxEvent, processingErr error) {
+	log.Printf("[InboxProcessor] Parking event %s: %v", event.EventID, processingErr)
+	lastError := processingErr.Error()
+	event.EventStatus = domain.InboxEventStatusParked
+	event.LastError = &lastError
+
+	_, err := uc.inboxRepo.UpdateInboxEventStatus(ctx, event)
+	if err != nil {
+		log.Printf("[InboxProcessor] CRITICAL: Failed to park event %s: %v", event.EventID, err)
+	}
+}
+
 // CleanupOldEvents - Clean up old processed events
 func (uc *inboxEventUseCase) CleanupOldEvents(ctx context.Context) error {
 	log.Println("[InboxCleanup] Starting cleanup of old processed events")
@@ -265,37 +417,15 @@ func (uc *inboxEventUseCase) GetInboxStats(ctx context.Context) (*repository.Inb
 
 // Helper functions
 
-// generateEventID - Generate deterministic event ID for idempotency
-func generateEventID(key, value []byte) string {
-	// Try to extract event_id from payload first
-	var kafkaPayload map[string]interface{}
-	if err := json.Unmarshal(value, &kafkaPayload); err == nil {
-		if eventID, ok := kafkaPayload["event_id"].(string); ok && eventID != "" {
-			return eventID
-		}
+// generateEventID - Dùng event_id bên gửi khai báo, không có thì hash key + value để có ID cố định cho idempotency
+func generateEventID(envelopeEventID string, key, value []byte) string {
+	if envelopeEventID != "" {
+		return envelopeEventID
 	}
 
-	// Fallback: generate deterministic hash from key + value
 	hasher := sha256.New()
 	hasher.Write(key)
 	hasher.Write(value)
 	hash := hasher.Sum(nil)
 	return hex.EncodeToString(hash)
 }
-
-// determineEventType - Determine event type from Kafka payload
-func determineEventType(payload map[string]interface{}) string {
-	// Check for explicit event_type field
-	if eventType, ok := payload["event_type"].(string); ok {
-		return eventType
-	}
-
-	// Fallback: try to infer from other fields
-	if _, hasOrderID := payload["order_id"]; hasOrderID {
-		if _, hasPaymentID := payload["payment_id"]; hasPaymentID {
-			return string(domain.InboxEventTypeRefundSucceeded) // Default assumption
-		}
-	}
-
-	return "" // Unknown event type
-}
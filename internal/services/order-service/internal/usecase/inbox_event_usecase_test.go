@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+func TestInboxEventUseCase_PaymentSuccess(t *testing.T) {
+	testCases := []struct {
+		name            string
+		status          domain.OrderStatus
+		payload         string
+		expectedStatus  domain.OrderStatus
+		expectedUpdates int
+		expectError     bool
+	}{
+		{
+			name:            "Success - order awaiting payment moves to processing",
+			status:          domain.OrderStatusPENDINGPAYMENT,
+			payload:         fmt.Sprintf(`{"order_id":%q,"payment_id":"payment-1"}`, testOrderID),
+			expectedStatus:  domain.OrderStatusPROCESSING,
+			expectedUpdates: 1,
+		},
+		{
+			name:           "Order already processing is skipped",
+			status:         domain.OrderStatusPROCESSING,
+			payload:        fmt.Sprintf(`{"order_id":%q}`, testOrderID),
+			expectedStatus: domain.OrderStatusPROCESSING,
+		},
+		{
+			name:           "Canceled order is skipped",
+			status:         domain.OrderStatusCANCELED,
+			payload:        fmt.Sprintf(`{"order_id":%q}`, testOrderID),
+			expectedStatus: domain.OrderStatusCANCELED,
+		},
+		{
+			name:           "Missing order ID",
+			status:         domain.OrderStatusPENDINGPAYMENT,
+			payload:        `{"payment_id":"payment-1"}`,
+			expectedStatus: domain.OrderStatusPENDINGPAYMENT,
+			expectError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := inbox.NewRegistry()
+			orderRepo := &fakeOrderRepository{order: &domain.Order{ID: testOrderID, Status: tc.status}}
+			usecase.NewInboxEventUseCase(registry, &fakeInboxEventRepository{}, orderRepo, nil)
+
+			// payment-service không khai báo event_version, processEvent coi đó là version 1
+			err := registry.Dispatch(context.Background(), &domain.InboxEvent{
+				EventID:       testInboxEventID,
+				EventType:     string(domain.InboxEventTypePaymentSuccess),
+				EventVersion:  usecase.LEGACY_EVENT_VERSION,
+				SourceService: usecase.SOURCE_PAYMENT_SERVICE,
+				Payload:       tc.payload,
+			})
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if orderRepo.order.Status != tc.expectedStatus {
+				t.Errorf("order status = %s, want %s", orderRepo.order.Status, tc.expectedStatus)
+			}
+			if orderRepo.updates != tc.expectedUpdates {
+				t.Errorf("updates = %d, want %d", orderRepo.updates, tc.expectedUpdates)
+			}
+			if topics := registry.Topics(); fmt.Sprint(topics) != fmt.Sprint([]string{string(constant.EventTypeRefundSuccessed)}) {
+				t.Errorf("topics = %v, want only the payment-service topic", topics)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// InvoiceUseCase phát hành và trả về hoá đơn của đơn hàng cho người mua hoặc chủ shop.
+type InvoiceUseCase interface {
+	GetOrderInvoice(ctx context.Context, userId string, orderID string) (*domain.Invoice, error)
+}
+
+type invoiceUseCase struct {
+	invoiceRepo        repository.InvoiceRepository
+	orderRepo          repository.OrderRepository
+	shopServiceAdapter adapter.ShopServiceAdapter
+	taxRate            float64
+	numberPrefix       string
+}
+
+func NewInvoiceUseCase(
+	invoiceRepo repository.InvoiceRepository,
+	orderRepo repository.OrderRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+	taxRate float64,
+	numberPrefix string,
+) InvoiceUseCase {
+	return &invoiceUseCase{
+		invoiceRepo:        invoiceRepo,
+		orderRepo:          orderRepo,
+		shopServiceAdapter: shopServiceAdapter,
+		taxRate:            taxRate,
+		numberPrefix:       numberPrefix,
+	}
+}
+
+// GetOrderInvoice trả về hoá đơn đã phát hành của đơn hàng, hoặc phát hành hoá đơn ở lần xem đầu tiên
+// nếu đơn đã được thanh toán. Hoá đơn đã phát hành vẫn xem được sau khi đơn bị hoàn tiền.
+func (u *invoiceUseCase) GetOrderInvoice(ctx context.Context, userId string, orderID string) (*domain.Invoice, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetOrderInvoice.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.getAccessibleOrder(ctx, userId, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	invoice, err := u.invoiceRepo.GetByOrderID(ctx, orderID)
+	if err == nil {
+		invoice.AttachOrder(order)
+		span.SetAttributes(attribute.String("invoice.number", invoice.InvoiceNumber))
+		return invoice, nil
+	}
+	if apperror.GetType(err) != apperror.TypeNotFound {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get invoice: %s", err.Error()))
+	}
+
+	if !order.IsInvoiceable() {
+		span.SetStatus(codes.Error, "order is not invoiceable")
+		return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Invoice is not available for orders in status %s", order.Status), apperror.TypeConflict)
+	}
+
+	seller, err := u.getInvoiceSeller(ctx, order.ShopID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	invoice, err = u.invoiceRepo.IssueInvoice(ctx, domain.NewInvoice(order, *seller, u.taxRate), u.numberPrefix)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to issue invoice: %s", err.Error()))
+	}
+	invoice.AttachOrder(order)
+
+	span.SetAttributes(attribute.String("invoice.number", invoice.InvoiceNumber))
+	span.AddEvent("Invoice issued")
+	return invoice, nil
+}
+
+// getAccessibleOrder cho phép người mua và chủ shop xem hoá đơn, người khác nhận NotFound để không lộ đơn hàng.
+func (u *invoiceUseCase) getAccessibleOrder(ctx context.Context, userId string, orderID string) (*domain.Order, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID == userId {
+		return order, nil
+	}
+
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, order.ShopID, userId)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+	if !isOwner {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	return order, nil
+}
+
+func (u *invoiceUseCase) getInvoiceSeller(ctx context.Context, shopID string) (*domain.InvoiceSeller, error) {
+	shop, err := u.shopServiceAdapter.GetShopInfo(ctx, shopID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get shop info: %s", err.Error()))
+	}
+	if shop == nil {
+		return nil, apperror.NewNotFound("Shop", shopID)
+	}
+
+	seller := &domain.InvoiceSeller{
+		ShopID: shop.GetShopId(),
+		Name:   shop.GetShopName(),
+		Phone:  shop.GetPhone(),
+		Email:  shop.GetEmail(),
+	}
+	if address := shop.GetAddress(); address != nil {
+		seller.Street = address.GetStreet()
+		seller.Ward = address.GetWard()
+		seller.District = address.GetDistrict()
+		seller.City = address.GetCity()
+		seller.Country = address.GetCountry()
+	}
+	return seller, nil
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (u *orderUsecase) CancelOrder(ctx context.Context, userId string, orderID string, req dto.CancelOrderRequest) (*domain.Order, *domain.OrderCancellation, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "CancelOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, nil, err
+		}
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID != userId {
+		log.Printf("User %s is not allowed to cancel order %s", userId, orderID)
+		return nil, nil, apperror.NewForbidden("You are not allowed to cancel this order")
+	}
+
+	// Idempotency: huỷ lại một đơn đã huỷ sẽ trả về thông tin huỷ trước đó
+	if order.Status == domain.OrderStatusCANCELED {
+		cancellation, err := u.orderRepo.GetOrderCancellation(ctx, orderID)
+		if err == nil {
+			return order, cancellation, nil
+		}
+	}
+
+	if !order.IsCancelableByCustomer() {
+		span.SetStatus(codes.Error, "order is not cancelable")
+		return nil, nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order cannot be canceled in status %s", order.Status), apperror.TypeConflict)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	canceledOrder, cancellation, err := u.orderRepo.CancelOrder(ctx, order, userId, domain.StatusChange{
+		Actor:  domain.CustomerActor(userId),
+		Reason: reason,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if errors.Is(err, domain.ErrOrderStatusChanged) {
+			return nil, nil, apperror.New(apperror.CodeConflict, "Order status has changed, please reload the order and try again", apperror.TypeConflict)
+		}
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to cancel order: %s", err.Error()))
+	}
+
+	// --- Sau khi đơn đã được huỷ: trả lại tồn kho và yêu cầu hoàn tiền nếu cần ---
+	releaseOrderStock(ctx, u.orderRepo, u.productServiceAdapter, canceledOrder)
+
+	if refunded, err := u.requestRefundIfPaid(ctx, canceledOrder.ID, reason); err != nil {
+		log.Printf("CRITICAL: Order %s canceled but refund request failed. Reconciler will retry. Error: %v", canceledOrder.ID, err)
+		span.AddEvent("Refund request failed")
+	} else if refunded != nil {
+		cancellation = refunded
+	}
+
+	span.AddEvent("Order canceled by customer")
+	return canceledOrder, cancellation, nil
+}
+
+// releaseOrderStock trả lại tồn kho của đơn vừa huỷ hoặc thanh toán thất bại. Lỗi được lưu vào order_stock_release_failures để
+// reconciler trả lại (RetryStockReleases) thay vì chỉ ghi log.
+func releaseOrderStock(ctx context.Context, orderRepo repository.OrderRepository, productServiceAdapter adapter.ProductServiceAdapter, order *domain.Order) {
+	if err := releaseReservedStock(ctx, productServiceAdapter, order); err != nil {
+		log.Printf("CRITICAL: Failed to release stock of order %s. Reconciler will retry. Error: %v", order.ID, err)
+		recordStockReleaseFailure(ctx, orderRepo, order.ID, err)
+	}
+}
+
+// recordStockReleaseFailure lưu lỗi trả tồn kho, lỗi khi lưu chỉ được log lại vì đơn đã huỷ xong.
+func recordStockReleaseFailure(ctx context.Context, orderRepo repository.OrderRepository, orderID string, releaseErr error) *domain.StockReleaseFailure {
+	failure, err := orderRepo.RecordStockReleaseFailure(ctx, orderID, releaseErr.Error())
+	if err != nil {
+		log.Printf("CRITICAL: Failed to record stock release failure of order %s. Manual intervention required. Error: %v", orderID, err)
+		return nil
+	}
+	return failure
+}
+
+// releaseReservedStock trả lại tồn kho cho product-service nếu đơn hàng vẫn đang giữ hàng.
+// Phần dòng hàng người bán đã huỷ riêng đã được trả kho lúc huỷ nên không tính lại.
+func releaseReservedStock(ctx context.Context, productServiceAdapter adapter.ProductServiceAdapter, order *domain.Order) error {
+	reservation, err := productServiceAdapter.GetOrderReservationStatus(ctx, &product_v1.GetOrderReservationStatusRequest{
+		OrderId: order.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get reservation status of order %s: %w", order.ID, err)
+	}
+
+	if reservation == nil || !reservation.Founded || reservation.Status != product_v1.GetOrderReservationStatusResponse_RESERVED.String() {
+		log.Printf("Order %s has no active reservation. Nothing to release.", order.ID)
+		return nil
+	}
+
+	products := make([]*product_v1.UnreserveProduct, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.RemainingQuantity() <= 0 {
+			continue
+		}
+		products = append(products, &product_v1.UnreserveProduct{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.RemainingQuantity()),
+		})
+	}
+
+	resp, err := productServiceAdapter.UnreserveOrders(ctx, &product_v1.UnreserveOrdersRequest{
+		Orders: []*product_v1.UnreserveOrder{
+			{
+				OrderId:  order.ID,
+				ShopId:   order.ShopID,
+				Products: products,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unreserve stock of order %s: %w", order.ID, err)
+	}
+
+	if len(resp.Results) == 0 || !resp.Results[0].Success {
+		return fmt.Errorf("product service could not unreserve stock of order %s", order.ID)
+	}
+
+	log.Printf("Released reserved stock of order %s", order.ID)
+	return nil
+}
+
+// requestRefundIfPaid yêu cầu hoàn tiền cho đơn vừa huỷ. Lỗi được lưu vào order_cancellations để
+// reconciler gửi lại (RetryCancellationRefunds) thay vì chỉ ghi log.
+func (u *orderUsecase) requestRefundIfPaid(ctx context.Context, orderID string, reason string) (*domain.OrderCancellation, error) {
+	cancellation, err := requestCancellationRefund(ctx, u.orderRepo, u.paymentAdapter, orderID, reason)
+	if err != nil {
+		recordCancellationRefundFailure(ctx, u.orderRepo, orderID, err)
+	}
+	return cancellation, err
+}
+
+// recordCancellationRefundFailure lưu lỗi yêu cầu hoàn tiền, lỗi khi lưu chỉ được log lại vì đơn đã huỷ xong.
+func recordCancellationRefundFailure(ctx context.Context, orderRepo repository.OrderRepository, orderID string, refundErr error) *domain.OrderCancellation {
+	cancellation, err := orderRepo.RecordCancellationRefundFailure(ctx, orderID, refundErr.Error())
+	if err != nil {
+		log.Printf("CRITICAL: Failed to record refund failure of canceled order %s. Manual intervention required. Error: %v", orderID, err)
+		return nil
+	}
+	return cancellation
+}
+
+// requestCancellationRefund yêu cầu payment-service hoàn tiền khi đơn hàng đã được thanh toán thành công.
+// Payment chưa thanh toán (mọi phương thức) được huỷ ở payment-service để khoản thanh toán đến muộn được hoàn tự động.
+// Trả về nil, nil khi không có khoản thanh toán nào cần hoàn.
+func requestCancellationRefund(ctx context.Context, orderRepo repository.OrderRepository, paymentAdapter adapter.PaymentServiceAdapter, orderID string, reason string) (*domain.OrderCancellation, error) {
+	paymentResp, err := paymentAdapter.GetPaymentByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+	}
+
+	if paymentResp.GetExists() && paymentResp.GetPayment().GetStatus() == payment_v1.PaymentStatus_PAYMENT_STATUS_PENDING {
+		_, err := paymentAdapter.CancelPendingPayment(ctx, orderID, reason)
+		switch status.Code(err) {
+		case grpccodes.OK, grpccodes.NotFound:
+			log.Printf("Pending payment of canceled order %s canceled", orderID)
+			return nil, nil
+		case grpccodes.FailedPrecondition:
+			// Khách vừa thanh toán xong trước khi payment bị huỷ, đọc lại payment để hoàn tiền
+			log.Printf("Order %s was paid while canceling, requesting refund: %v", orderID, err)
+			paymentResp, err = paymentAdapter.GetPaymentByOrder(ctx, orderID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+			}
+		default:
+			return nil, fmt.Errorf("failed to cancel pending payment of order %s: %w", orderID, err)
+		}
+	}
+
+	payment := paymentResp.GetPayment()
+	if !paymentResp.GetExists() || payment.GetStatus() != payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS {
+		return nil, nil
+	}
+
+	refundResp, err := paymentAdapter.RequestRefund(ctx, orderID, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request refund for order %s: %w", orderID, err)
+	}
+
+	if !refundResp.GetAccepted() {
+		return nil, fmt.Errorf("payment service rejected refund for order %s: %s", orderID, refundResp.GetMessage())
+	}
+
+	log.Printf("Refund %s requested for canceled order %s", refundResp.GetRefundId(), orderID)
+	return orderRepo.MarkCancellationRefundRequested(ctx, orderID, refundResp.GetRefundId())
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Checkout đọc giỏ hàng từ cart-service, tách thành một đơn hàng cho mỗi shop với cùng checkout_id,
+// giữ hàng cho từng đơn và xoá các sản phẩm đã đặt thành công khỏi giỏ hàng.
+// Shop nào đặt hàng thất bại thì sản phẩm của shop đó vẫn được giữ lại trong giỏ hàng.
+func (u *orderUsecase) Checkout(ctx context.Context, userId string, req dto.CheckoutRequest) (*domain.CheckoutResult, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "Checkout.UseCase")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("user.id", userId))
+
+	_, cartSpan := tracer.Start(ctx, "GetCart_gRPC")
+	cart, err := u.cartAdapter.GetCart(ctx, userId)
+	if err != nil {
+		cartSpan.SetStatus(codes.Error, err.Error())
+		cartSpan.End()
+		log.Printf("Failed to get cart of user %s: %v", userId, err)
+		return nil, apperror.NewDependencyFailure(fmt.Sprintf("Failed to get cart: %s", err.Error()))
+	}
+	cartSpan.End()
+
+	shopOrders, err := groupCartItemsByShop(cart.GetItems(), req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	checkoutID := uuid.New().String()
+	span.SetAttributes(
+		attribute.String("checkout.id", checkoutID),
+		attribute.Int("checkout.shop_count", len(shopOrders)),
+	)
+
+	result := &domain.CheckoutResult{CheckoutID: checkoutID}
+	var checkedOutProductIDs []string
+	var firstErr error
+
+	for _, orderReq := range shopOrders {
+		order, err := u.placeOrder(ctx, userId, orderReq, &checkoutID)
+		if err != nil {
+			log.Printf("Checkout %s: failed to place order for shop %s: %v", checkoutID, orderReq.ShopID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Failures = append(result.Failures, domain.CheckoutFailure{
+				ShopID: orderReq.ShopID,
+				Reason: checkoutFailureReason(err),
+			})
+			continue
+		}
+
+		result.Orders = append(result.Orders, order)
+		for _, item := range orderReq.Items {
+			checkedOutProductIDs = append(checkedOutProductIDs, item.ProductID)
+		}
+	}
+
+	if len(result.Orders) == 0 {
+		span.SetStatus(codes.Error, "no order could be placed")
+		return nil, firstErr
+	}
+
+	// Đơn hàng đã được tạo nên lỗi khi dọn giỏ hàng không làm checkout thất bại
+	if _, err := u.cartAdapter.RemoveCartItems(ctx, userId, checkedOutProductIDs); err != nil {
+		log.Printf("CRITICAL: Checkout %s placed %d orders but failed to remove items from cart of user %s: %v", checkoutID, len(result.Orders), userId, err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("checkout.order_count", len(result.Orders)),
+		attribute.Int("checkout.failure_count", len(result.Failures)),
+	)
+	return result, nil
+}
+
+// groupCartItemsByShop tách các sản phẩm được chọn trong giỏ hàng thành một CreateOrderRequest cho mỗi shop,
+// giữ nguyên thứ tự xuất hiện của shop trong giỏ hàng.
+func groupCartItemsByShop(items []*cart_v1.CartItem, req dto.CheckoutRequest) ([]dto.CreateOrderRequest, error) {
+	selected := make(map[string]bool, len(req.ProductIDs))
+	for _, productID := range req.ProductIDs {
+		selected[productID] = true
+	}
+
+	var orders []dto.CreateOrderRequest
+	shopIndex := make(map[string]int)
+	found := make(map[string]bool, len(req.ProductIDs))
+
+	for _, item := range items {
+		if len(selected) > 0 && !selected[item.GetProductId()] {
+			continue
+		}
+		found[item.GetProductId()] = true
+
+		if _, err := uuid.Parse(item.GetShopId()); err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Cart item %s has no valid shop", item.GetProductId()), err)
+		}
+
+		idx, ok := shopIndex[item.GetShopId()]
+		if !ok {
+			orderReq := dto.CreateOrderRequest{
+				ShopID:            item.GetShopId(),
+				ShippingAddressID: req.ShippingAddressID,
+			}
+			if promotionID, ok := req.Promotions[item.GetShopId()]; ok && promotionID != "" {
+				if _, err := uuid.Parse(promotionID); err != nil {
+					return nil, apperror.NewBadRequest(fmt.Sprintf("Invalid promotion for shop %s", item.GetShopId()), err)
+				}
+				orderReq.PromotionID = &promotionID
+			}
+			orders = append(orders, orderReq)
+			idx = len(orders) - 1
+			shopIndex[item.GetShopId()] = idx
+		}
+
+		orders[idx].Items = append(orders[idx].Items, dto.CreateOrderItemRequest{
+			ProductID: item.GetProductId(),
+			Quantity:  int(item.GetQuantity()),
+		})
+	}
+
+	for _, productID := range req.ProductIDs {
+		if !found[productID] {
+			return nil, apperror.NewBadRequest("Product is not in cart", fmt.Errorf("product %s is not in cart", productID))
+		}
+	}
+
+	if len(orders) == 0 {
+		return nil, apperror.NewBadRequest("Cart is empty", errors.New("no items to checkout"))
+	}
+
+	return orders, nil
+}
+
+func checkoutFailureReason(err error) string {
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,242 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+)
+
+const testOtherShopID = "9d2e3f4a-5b6c-4d7e-8f9a-1b2c3d4e5f6a"
+
+// fakeCartAdapter trả về giỏ hàng cố định và ghi lại các sản phẩm được xoá khỏi giỏ.
+type fakeCartAdapter struct {
+	adapter.CartServiceAdapter
+	items   []*cart_v1.CartItem
+	removed []string
+	added   []*cart_v1.CartItem
+	addErr  error
+}
+
+func (f *fakeCartAdapter) GetCart(ctx context.Context, userID string) (*cart_v1.GetCartResponse, error) {
+	return &cart_v1.GetCartResponse{OwnerId: userID, Items: f.items}, nil
+}
+
+func (f *fakeCartAdapter) RemoveCartItems(ctx context.Context, userID string, productIDs []string) (*cart_v1.RemoveCartItemsResponse, error) {
+	f.removed = append(f.removed, productIDs...)
+	return &cart_v1.RemoveCartItemsResponse{}, nil
+}
+
+func (f *fakeCartAdapter) AddCartItems(ctx context.Context, userID string, items []*cart_v1.CartItem) (*cart_v1.AddCartItemsResponse, error) {
+	if f.addErr != nil {
+		return nil, f.addErr
+	}
+	f.added = append(f.added, items...)
+	return &cart_v1.AddCartItemsResponse{}, nil
+}
+
+// fakeCheckoutCatalog giữ hàng thành công cho mọi shop trừ các shop trong outOfStock.
+type fakeCheckoutCatalog struct {
+	*fakeProductCatalog
+	outOfStock map[string]bool
+}
+
+func (f *fakeCheckoutCatalog) ReserveProducts(ctx context.Context, req *product_v1.ReserveProductsRequest) (*product_v1.ReserveProductsResponse, error) {
+	if !f.outOfStock[req.GetShopId()] {
+		return &product_v1.ReserveProductsResponse{Success: true}, nil
+	}
+	res := &product_v1.ReserveProductsResponse{}
+	for _, product := range req.GetProducts() {
+		res.ProductStatuses = append(res.ProductStatuses, &product_v1.ProductReservationStatus{
+			ProductId: product.GetProductId(),
+			Message:   "out of stock",
+		})
+	}
+	return res, nil
+}
+
+// fakeCheckoutOrderRepository lưu các đơn được tạo theo ID.
+type fakeCheckoutOrderRepository struct {
+	repository.OrderRepository
+	orders map[string]*domain.Order
+}
+
+func (f *fakeCheckoutOrderRepository) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	created := *order
+	f.orders[order.ID] = &created
+	return order, nil
+}
+
+func (f *fakeCheckoutOrderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus, change domain.StatusChange) (*domain.Order, error) {
+	order := f.orders[orderID]
+	if err := order.TransitionTo(domain.OrderStatus(status)); err != nil {
+		return nil, err
+	}
+	updated := *order
+	return &updated, nil
+}
+
+func newShopProduct(id, shopID string) *product_v1.ProductInfo {
+	product := newTestProduct(id, money.New(100000, "VND"))
+	product.ShopId = shopID
+	return product
+}
+
+func TestOrderUsecase_Checkout(t *testing.T) {
+	cartItems := []*cart_v1.CartItem{
+		{ProductId: "product-1", ShopId: testShopID, Quantity: 2},
+		{ProductId: "product-2", ShopId: testOtherShopID, Quantity: 1},
+		{ProductId: "product-3", ShopId: testShopID, Quantity: 1},
+	}
+	products := func() map[string]*product_v1.ProductInfo {
+		return map[string]*product_v1.ProductInfo{
+			"product-1": newShopProduct("product-1", testShopID),
+			"product-2": newShopProduct("product-2", testOtherShopID),
+			"product-3": newShopProduct("product-3", testShopID),
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		productIDs       []string
+		products         map[string]*product_v1.ProductInfo
+		outOfStock       map[string]bool
+		expectedShops    []string
+		expectedFailures []string
+		expectedRemoved  []string
+		expectedType     apperror.ErrorType
+		expectError      bool
+	}{
+		{
+			name:            "Success - one order per shop",
+			products:        products(),
+			expectedShops:   []string{testShopID, testOtherShopID},
+			expectedRemoved: []string{"product-1", "product-3", "product-2"},
+		},
+		{
+			name:            "Success - only selected products",
+			productIDs:      []string{"product-2"},
+			products:        products(),
+			expectedShops:   []string{testOtherShopID},
+			expectedRemoved: []string{"product-2"},
+		},
+		{
+			name:             "Partial failure keeps items of the failed shop in cart",
+			products:         products(),
+			outOfStock:       map[string]bool{testShopID: true},
+			expectedShops:    []string{testOtherShopID},
+			expectedFailures: []string{testShopID},
+			expectedRemoved:  []string{"product-2"},
+		},
+		{
+			name: "Product moved to another shop fails that shop only",
+			products: func() map[string]*product_v1.ProductInfo {
+				moved := products()
+				moved["product-3"] = newShopProduct("product-3", testOtherShopID)
+				return moved
+			}(),
+			expectedShops:    []string{testOtherShopID},
+			expectedFailures: []string{testShopID},
+			expectedRemoved:  []string{"product-2"},
+		},
+		{
+			name:         "Every shop fails",
+			products:     products(),
+			outOfStock:   map[string]bool{testShopID: true, testOtherShopID: true},
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:         "Selected product not in cart",
+			productIDs:   []string{"product-9"},
+			products:     products(),
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cart := &fakeCartAdapter{items: cartItems}
+			catalog := &fakeCheckoutCatalog{fakeProductCatalog: &fakeProductCatalog{products: tc.products}, outOfStock: tc.outOfStock}
+			orderRepo := &fakeCheckoutOrderRepository{orders: make(map[string]*domain.Order)}
+			shipping := &fakeShippingUseCase{quote: &domain.ShippingQuote{Currency: "VND", ShippingFee: 15000}}
+			uc := usecase.NewOrderUsecase(orderRepo, &fakeShopServiceAdapter{}, catalog, &fakeUserServiceAdapter{}, nil, cart, shipping)
+
+			result, err := uc.Checkout(context.Background(), testCustomerID, dto.CheckoutRequest{
+				ShippingAddressID: testAddressID,
+				ProductIDs:        tc.productIDs,
+			})
+
+			if tc.expectError {
+				if apperror.GetType(err) != tc.expectedType {
+					t.Fatalf("error = %v, want type %v", err, tc.expectedType)
+				}
+				if len(cart.removed) != 0 {
+					t.Errorf("removed %v from cart, want nothing removed", cart.removed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var shops []string
+			for _, order := range result.Orders {
+				shops = append(shops, order.ShopID)
+				if order.CheckoutID == nil || *order.CheckoutID != result.CheckoutID {
+					t.Errorf("order %s checkout ID = %v, want %s", order.ID, order.CheckoutID, result.CheckoutID)
+				}
+				if order.Status != domain.OrderStatusPENDINGPAYMENT {
+					t.Errorf("order %s status = %s, want %s", order.ID, order.Status, domain.OrderStatusPENDINGPAYMENT)
+				}
+			}
+			var failures []string
+			for _, failure := range result.Failures {
+				failures = append(failures, failure.ShopID)
+			}
+
+			if fmt.Sprint(shops) != fmt.Sprint(tc.expectedShops) {
+				t.Errorf("order shops = %v, want %v", shops, tc.expectedShops)
+			}
+			if fmt.Sprint(failures) != fmt.Sprint(tc.expectedFailures) {
+				t.Errorf("failed shops = %v, want %v", failures, tc.expectedFailures)
+			}
+			if fmt.Sprint(cart.removed) != fmt.Sprint(tc.expectedRemoved) {
+				t.Errorf("removed from cart = %v, want %v", cart.removed, tc.expectedRemoved)
+			}
+		})
+	}
+}
+
+func TestOrderUsecase_CreateOrder_RejectsProductOfAnotherShop(t *testing.T) {
+	catalog := &fakeProductCatalog{products: map[string]*product_v1.ProductInfo{
+		"product-1": newShopProduct("product-1", testShopID),
+		"product-2": newShopProduct("product-2", testOtherShopID),
+	}}
+	shipping := &fakeShippingUseCase{quote: &domain.ShippingQuote{Currency: "VND", ShippingFee: 15000}}
+	uc := usecase.NewOrderUsecase(nil, &fakeShopServiceAdapter{}, catalog, &fakeUserServiceAdapter{}, nil, nil, shipping)
+
+	_, err := uc.CreateOrder(context.Background(), testCustomerID, dto.CreateOrderRequest{
+		ShopID:            testShopID,
+		ShippingAddressID: testAddressID,
+		Items: []dto.CreateOrderItemRequest{
+			{ProductID: "product-1", Quantity: 1},
+			{ProductID: "product-2", Quantity: 1},
+		},
+	})
+
+	if apperror.GetType(err) != apperror.TypeValidation {
+		t.Errorf("error = %v, want bad request", err)
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+)
+
+const (
+	OUTBOX_BATCH_SIZE      = 100
+	OUTBOX_MAX_RETRY       = 5 // Số lần publish thất bại tối đa trước khi đánh dấu là FAILED
+	SOURCE_ORDER_SERVICE   = "order-service"
+	OUTBOX_PUBLISH_TIMEOUT = 30 * time.Second
+)
+
+// OrderEventUseCase là relay worker của transactional outbox: đọc các domain event đã được commit
+// cùng với thay đổi đơn hàng và publish lên topic constant.KafkaTopicOrderEvents với key là order_id.
+// Event FAILED chặn các event sau của cùng đơn hàng cho tới khi admin retry event đó.
+type OrderEventUseCase interface {
+	PublishPendingOrderEvents()
+	RetryFailedOrderEvent(ctx context.Context, adminID string, eventID string) (*domain.OutboxEvent, error)
+}
+
+type orderEventUseCase struct {
+	outboxRepo    repository.OutboxEventRepository
+	kafkaProducer kafka_infra.Producer
+}
+
+func NewOrderEventUseCase(outboxRepo repository.OutboxEventRepository, kafkaProducer kafka_infra.Producer) OrderEventUseCase {
+	return &orderEventUseCase{
+		outboxRepo:    outboxRepo,
+		kafkaProducer: kafkaProducer,
+	}
+}
+
+func (uc *orderEventUseCase) PublishPendingOrderEvents() {
+	ctx, cancel := context.WithTimeout(context.Background(), OUTBOX_PUBLISH_TIMEOUT)
+	defer cancel()
+
+	events, err := uc.outboxRepo.GetPendingOutboxEvents(ctx, OUTBOX_BATCH_SIZE)
+	if err != nil {
+		log.Printf("[OrderEventPublisher] Error fetching pending outbox events: %v", err)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	log.Printf("[OrderEventPublisher] Found %d pending outbox events to publish.", len(events))
+
+	// Event của cùng một đơn hàng phải được publish theo thứ tự, nên khi một event publish lỗi
+	// thì các event sau của đơn đó được để lại cho lần chạy tiếp theo.
+	blockedOrders := make(map[string]bool)
+	sentCount := 0
+
+	for _, event := range events {
+		if blockedOrders[event.OrderID] {
+			continue
+		}
+
+		message := domain.OrderEventMessage{
+			EventID:    event.ID,
+			EventType:  event.EventType,
+			OrderID:    event.OrderID,
+			Source:     SOURCE_ORDER_SERVICE,
+			OccurredAt: event.CreatedAt.UTC().Format(time.RFC3339),
+			Data:       json.RawMessage(event.Payload),
+		}
+
+		err := uc.kafkaProducer.Publish(ctx, constant.KafkaTopicOrderEvents, event.OrderID, message)
+		if err != nil {
+			log.Printf("[OrderEventPublisher] Error publishing event ID %s (%s): %v. Updating retry count.", event.ID, event.EventType, err)
+			blockedOrders[event.OrderID] = true
+			event.RetryCount++
+			if event.RetryCount >= OUTBOX_MAX_RETRY {
+				log.Printf("CRITICAL: [EVENT_FAILURE] Outbox event ID %s for Order ID %s has failed permanently after %d retries. Later events of this order are held until it is retried.", event.ID, event.OrderID, OUTBOX_MAX_RETRY)
+				event.EventStatus = domain.OutboxEventStatusFailed
+			}
+		} else {
+			event.EventStatus = domain.OutboxEventStatusSent
+			sentCount++
+		}
+
+		if _, updateErr := uc.outboxRepo.UpdateOutboxEventStatus(ctx, event); updateErr != nil {
+			log.Printf("[OrderEventPublisher] CRITICAL: Failed to update status of outbox event ID %s: %v", event.ID, updateErr)
+			// Không cập nhật được trạng thái thì event sẽ bị publish lại, dừng các event sau của đơn này để giữ thứ tự
+			blockedOrders[event.OrderID] = true
+		}
+	}
+
+	log.Printf("[OrderEventPublisher] Published %d/%d outbox events.", sentCount, len(events))
+}
+
+// RetryFailedOrderEvent đưa một event FAILED về PENDING để relay worker publish lại,
+// sau đó các event đang bị chặn của cùng đơn hàng được publish tiếp theo đúng thứ tự.
+func (uc *orderEventUseCase) RetryFailedOrderEvent(ctx context.Context, adminID string, eventID string) (*domain.OutboxEvent, error) {
+	event, err := uc.outboxRepo.RetryFailedOutboxEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOutboxEventNotFailed) {
+			return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Only FAILED outbox events can be retried, event is %s", event.EventStatus), apperror.TypeConflict)
+		}
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to retry outbox event: %s", err.Error()))
+	}
+
+	log.Printf("[OrderEventPublisher] Outbox event %s of order %s queued for retry by admin %s", event.ID, event.OrderID, adminID)
+	return event, nil
+}
@@ -0,0 +1,180 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/constant"
+	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+// fakeOutboxEventRepository mô phỏng GetPendingOutboxEvents: event được đọc theo seq, event PENDING xếp sau
+// một event FAILED của cùng đơn bị bỏ qua.
+type fakeOutboxEventRepository struct {
+	repository.OutboxEventRepository
+	events []*domain.OutboxEvent
+}
+
+func (f *fakeOutboxEventRepository) GetPendingOutboxEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	ordered := append([]*domain.OutboxEvent{}, f.events...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Seq < ordered[j].Seq })
+
+	failedOrders := make(map[string]bool)
+	pending := []*domain.OutboxEvent{}
+	for _, event := range ordered {
+		switch {
+		case event.EventStatus == domain.OutboxEventStatusFailed:
+			failedOrders[event.OrderID] = true
+		case event.EventStatus == domain.OutboxEventStatusPending && !failedOrders[event.OrderID]:
+			copied := *event
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeOutboxEventRepository) UpdateOutboxEventStatus(ctx context.Context, event *domain.OutboxEvent) (*domain.OutboxEvent, error) {
+	for _, stored := range f.events {
+		if stored.ID == event.ID {
+			stored.EventStatus = event.EventStatus
+			stored.RetryCount = event.RetryCount
+			return stored, nil
+		}
+	}
+	return nil, errors.New("outbox event not found")
+}
+
+func (f *fakeOutboxEventRepository) RetryFailedOutboxEvent(ctx context.Context, id string) (*domain.OutboxEvent, error) {
+	for _, stored := range f.events {
+		if stored.ID != id {
+			continue
+		}
+		if stored.EventStatus != domain.OutboxEventStatusFailed {
+			copied := *stored
+			return &copied, domain.ErrOutboxEventNotFailed
+		}
+		stored.EventStatus = domain.OutboxEventStatusPending
+		stored.RetryCount = 0
+		copied := *stored
+		return &copied, nil
+	}
+	return nil, apperror.NewNotFound("Outbox event", id)
+}
+
+type fakeProducer struct {
+	kafka_infra.Producer
+	failing   map[string]bool // event ID publish lỗi
+	published []string
+	topics    map[string]bool
+}
+
+func (f *fakeProducer) Publish(ctx context.Context, topic string, key string, value interface{}) error {
+	message := value.(domain.OrderEventMessage)
+	if f.topics == nil {
+		f.topics = make(map[string]bool)
+	}
+	f.topics[topic] = true
+	if key != message.OrderID {
+		return errors.New("message must be keyed by order ID")
+	}
+	if f.failing[message.EventID] {
+		return errors.New("broker unavailable")
+	}
+	f.published = append(f.published, message.EventID)
+	return nil
+}
+
+func newOutboxEvent(seq int64, id string, orderID string, status domain.OutboxEventStatus, retryCount int) *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		Seq:         seq,
+		ID:          id,
+		OrderID:     orderID,
+		EventType:   "order_status_changed",
+		Payload:     `{}`,
+		EventStatus: status,
+		RetryCount:  retryCount,
+	}
+}
+
+func TestOrderEventUseCase_PublishPendingOrderEvents_HoldsEventsAfterFailure(t *testing.T) {
+	repo := &fakeOutboxEventRepository{events: []*domain.OutboxEvent{
+		newOutboxEvent(1, "a-1", "order-a", domain.OutboxEventStatusPending, usecase.OUTBOX_MAX_RETRY-1),
+		newOutboxEvent(2, "b-1", "order-b", domain.OutboxEventStatusPending, 0),
+		// Ghi sau a-1 trong cùng transaction nên có thể cùng created_at, seq vẫn giữ đúng thứ tự
+		newOutboxEvent(4, "a-2", "order-a", domain.OutboxEventStatusPending, 0),
+		newOutboxEvent(3, "b-2", "order-b", domain.OutboxEventStatusPending, 0),
+	}}
+	producer := &fakeProducer{failing: map[string]bool{"a-1": true}}
+	uc := usecase.NewOrderEventUseCase(repo, producer)
+
+	// Lần chạy đầu: a-1 hết lượt retry và chuyển FAILED, a-2 không được publish trước a-1
+	uc.PublishPendingOrderEvents()
+	assertPublished(t, producer.published, "b-1", "b-2")
+	if status := repo.events[0].EventStatus; status != domain.OutboxEventStatusFailed {
+		t.Fatalf("a-1 status = %s, want FAILED", status)
+	}
+
+	// Các lần chạy sau vẫn giữ a-2 khi a-1 còn FAILED
+	uc.PublishPendingOrderEvents()
+	assertPublished(t, producer.published, "b-1", "b-2")
+	if status := repo.events[2].EventStatus; status != domain.OutboxEventStatusPending {
+		t.Errorf("a-2 status = %s, want PENDING", status)
+	}
+
+	// Admin retry a-1 sau khi Kafka ổn định lại: a-1 rồi a-2 được publish đúng thứ tự
+	delete(producer.failing, "a-1")
+	if _, err := uc.RetryFailedOrderEvent(context.Background(), "admin-1", "a-1"); err != nil {
+		t.Fatalf("unexpected retry error: %v", err)
+	}
+	uc.PublishPendingOrderEvents()
+	assertPublished(t, producer.published, "b-1", "b-2", "a-1", "a-2")
+
+	// Mọi loại event của đơn được publish lên cùng một topic để giữ thứ tự theo order_id
+	if len(producer.topics) != 1 || !producer.topics[constant.KafkaTopicOrderEvents] {
+		t.Errorf("topics = %v, want only %s", producer.topics, constant.KafkaTopicOrderEvents)
+	}
+}
+
+func TestOrderEventUseCase_RetryFailedOrderEvent(t *testing.T) {
+	testCases := []struct {
+		name         string
+		eventID      string
+		expectedType apperror.ErrorType
+	}{
+		{name: "Pending event", eventID: "pending", expectedType: apperror.TypeConflict},
+		{name: "Sent event", eventID: "sent", expectedType: apperror.TypeConflict},
+		{name: "Unknown event", eventID: "unknown", expectedType: apperror.TypeNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeOutboxEventRepository{events: []*domain.OutboxEvent{
+				newOutboxEvent(1, "pending", "order-a", domain.OutboxEventStatusPending, 0),
+				newOutboxEvent(2, "sent", "order-a", domain.OutboxEventStatusSent, 0),
+			}}
+			uc := usecase.NewOrderEventUseCase(repo, &fakeProducer{})
+
+			if _, err := uc.RetryFailedOrderEvent(context.Background(), "admin-1", tc.eventID); apperror.GetType(err) != tc.expectedType {
+				t.Errorf("error = %v, want type %v", err, tc.expectedType)
+			}
+		})
+	}
+}
+
+func assertPublished(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("published = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("published = %v, want %v", got, want)
+		}
+	}
+}
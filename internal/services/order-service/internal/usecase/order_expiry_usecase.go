@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ORDER_EXPIRY_TIMEOUT = 2 * time.Minute
+
+// OrderExpiryUseCase huỷ các đơn PENDING_PAYMENT đã quá thời gian chờ thanh toán của shop để trả lại tồn kho đang giữ.
+type OrderExpiryUseCase interface {
+	ExpireUnpaidOrders()
+}
+
+type orderExpiryUseCase struct {
+	orderRepo             repository.OrderRepository
+	productServiceAdapter adapter.ProductServiceAdapter
+	paymentAdapter        adapter.PaymentServiceAdapter
+	defaultWindow         time.Duration
+	batchSize             int
+}
+
+func NewOrderExpiryUseCase(
+	orderRepo repository.OrderRepository,
+	productServiceAdapter adapter.ProductServiceAdapter,
+	paymentAdapter adapter.PaymentServiceAdapter,
+	defaultWindow time.Duration,
+	batchSize int,
+) OrderExpiryUseCase {
+	return &orderExpiryUseCase{
+		orderRepo:             orderRepo,
+		productServiceAdapter: productServiceAdapter,
+		paymentAdapter:        paymentAdapter,
+		defaultWindow:         defaultWindow,
+		batchSize:             batchSize,
+	}
+}
+
+func (uc *orderExpiryUseCase) ExpireUnpaidOrders() {
+	ctx, cancel := context.WithTimeout(context.Background(), ORDER_EXPIRY_TIMEOUT)
+	defer cancel()
+
+	orders, err := uc.orderRepo.GetExpiredPendingPaymentOrders(ctx, uc.defaultWindow, uc.batchSize)
+	if err != nil {
+		log.Printf("[OrderExpiry] Error fetching expired pending payment orders: %v", err)
+		return
+	}
+
+	if len(orders) == 0 {
+		return
+	}
+
+	log.Printf("[OrderExpiry] Found %d orders past their payment window.", len(orders))
+
+	expiredCount := 0
+	for _, order := range orders {
+		if uc.expireOrder(ctx, order.ID) {
+			expiredCount++
+		}
+	}
+
+	log.Printf("[OrderExpiry] Expired %d/%d orders.", expiredCount, len(orders))
+}
+
+// expireOrder huỷ một đơn hết hạn thanh toán. Payment chưa thanh toán được huỷ ở payment-service trước khi huỷ đơn,
+// để khoản thanh toán đến sau bị payment-service hoàn lại thay vì đơn đã huỷ lại có payment thành công.
+// Khách đã thanh toán thì để payment event chuyển đơn sang PROCESSING như bình thường.
+func (uc *orderExpiryUseCase) expireOrder(ctx context.Context, orderID string) bool {
+	paymentResp, err := uc.paymentAdapter.GetPaymentByOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("[OrderExpiry] Could not verify payment of order %s, skipping: %v", orderID, err)
+		return false
+	}
+
+	var payment *payment_v1.Payment
+	if paymentResp.GetExists() {
+		payment = paymentResp.GetPayment()
+	}
+
+	switch domain.DecidePaymentExpiry(toDomainPaymentStatus(payment), payment.GetPaymentMethod()) {
+	case domain.PaymentExpirySkip:
+		log.Printf("[OrderExpiry] Order %s has %s payment in status %s, skipping.", orderID, payment.GetPaymentMethod(), payment.GetStatus())
+		return false
+	case domain.PaymentExpiryCancelPayment:
+		if !uc.cancelPendingPayment(ctx, orderID) {
+			return false
+		}
+	}
+
+	// Lấy lại đơn kèm sản phẩm để trả tồn kho
+	order, err := uc.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[OrderExpiry] Error fetching order %s: %v", orderID, err)
+		return false
+	}
+
+	if order.Status != domain.OrderStatusPENDINGPAYMENT {
+		log.Printf("[OrderExpiry] Order %s is now %s, skipping.", orderID, order.Status)
+		return false
+	}
+
+	canceledOrder, _, err := uc.orderRepo.CancelOrder(ctx, order, "", domain.StatusChange{
+		Actor:  domain.StatusActorExpiryJob,
+		Reason: domain.OrderExpiryReason,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderStatusChanged) {
+			log.Printf("[OrderExpiry] Order %s changed status while expiring, skipping.", orderID)
+			return false
+		}
+		log.Printf("[OrderExpiry] Error canceling order %s: %v", orderID, err)
+		return false
+	}
+
+	releaseOrderStock(ctx, uc.orderRepo, uc.productServiceAdapter, canceledOrder)
+
+	log.Printf("[OrderExpiry] Order %s canceled: %s", orderID, domain.OrderExpiryReason)
+	return true
+}
+
+// cancelPendingPayment huỷ payment chưa thanh toán của đơn, trả về false khi khách đã thanh toán hoặc chưa huỷ được.
+func (uc *orderExpiryUseCase) cancelPendingPayment(ctx context.Context, orderID string) bool {
+	_, err := uc.paymentAdapter.CancelPendingPayment(ctx, orderID, domain.OrderExpiryReason)
+	switch status.Code(err) {
+	case codes.OK, codes.NotFound:
+		return true
+	case codes.FailedPrecondition:
+		log.Printf("[OrderExpiry] Order %s was paid while expiring, skipping: %v", orderID, err)
+		return false
+	default:
+		log.Printf("[OrderExpiry] Could not cancel payment of order %s, skipping: %v", orderID, err)
+		return false
+	}
+}
@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+// fakeExportRepository trả lần lượt từng trang trong pages và ghi lại filter của mỗi lần gọi.
+type fakeExportRepository struct {
+	repository.OrderRepository
+	pages   [][]*domain.Order
+	filters []domain.OrderListFilter
+}
+
+func (f *fakeExportRepository) ListOrdersByShop(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	f.filters = append(f.filters, filter)
+
+	index := len(f.filters) - 1
+	if index >= len(f.pages) {
+		return &domain.OrderPage{}, nil
+	}
+	page := &domain.OrderPage{Orders: f.pages[index]}
+	if index < len(f.pages)-1 {
+		last := f.pages[index][len(f.pages[index])-1]
+		page.NextCursor = &domain.OrderCursor{CreatedAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), ID: last.ID}
+	}
+	return page, nil
+}
+
+func TestOrderUsecase_ExportShopOrders_Filter(t *testing.T) {
+	delivered := domain.OrderStatusDELIVERED
+
+	testCases := []struct {
+		name           string
+		userID         string
+		shopID         string
+		query          dto.ExportOrdersQuery
+		expectedFilter domain.OrderListFilter
+		expectedType   apperror.ErrorType
+		expectError    bool
+	}{
+		{
+			name:   "Status and date range",
+			userID: testSellerID,
+			shopID: testShopID,
+			query:  dto.ExportOrdersQuery{Format: "csv", Status: "delivered", From: "2025-01-01", To: "2025-02-01T12:00:00+07:00"},
+			expectedFilter: domain.OrderListFilter{
+				ShopID:      testShopID,
+				Status:      &delivered,
+				CreatedFrom: timePtr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CreatedTo:   timePtr(time.Date(2025, 2, 1, 5, 0, 0, 0, time.UTC)),
+				Limit:       usecase.ORDER_EXPORT_BATCH_SIZE,
+			},
+		},
+		{
+			name:   "Range of exactly 366 days",
+			userID: testSellerID,
+			shopID: testShopID,
+			query:  dto.ExportOrdersQuery{From: "2024-01-01", To: "2025-01-01"},
+			expectedFilter: domain.OrderListFilter{
+				ShopID:      testShopID,
+				CreatedFrom: timePtr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CreatedTo:   timePtr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Limit:       usecase.ORDER_EXPORT_BATCH_SIZE,
+			},
+		},
+		{
+			name:         "Range longer than 366 days",
+			userID:       testSellerID,
+			shopID:       testShopID,
+			query:        dto.ExportOrdersQuery{From: "2024-01-01", To: "2025-01-02"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "From not before to",
+			userID:       testSellerID,
+			shopID:       testShopID,
+			query:        dto.ExportOrdersQuery{From: "2025-02-01", To: "2025-01-01"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Unknown status",
+			userID:       testSellerID,
+			shopID:       testShopID,
+			query:        dto.ExportOrdersQuery{Status: "LOST", From: "2025-01-01", To: "2025-02-01"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid date",
+			userID:       testSellerID,
+			shopID:       testShopID,
+			query:        dto.ExportOrdersQuery{From: "01/01/2025", To: "2025-02-01"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Not the shop owner",
+			userID:       testCustomerID,
+			shopID:       testShopID,
+			query:        dto.ExportOrdersQuery{From: "2025-01-01", To: "2025-02-01"},
+			expectedType: apperror.TypeForbidden,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeExportRepository{}
+			uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+
+			orderExport, err := uc.ExportShopOrders(context.Background(), tc.userID, tc.shopID, tc.query)
+
+			if tc.expectError {
+				if apperror.GetType(err) != tc.expectedType {
+					t.Fatalf("error = %v, want type %v", err, tc.expectedType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.filters) != 0 {
+				t.Fatalf("repository was queried before the export started: %+v", repo.filters)
+			}
+
+			if err := orderExport.ForEach(context.Background(), func(order *domain.Order) error { return nil }); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(normalizeFilterTimes(repo.filters[0]), normalizeFilterTimes(tc.expectedFilter)) {
+				t.Errorf("filter = %+v, want %+v", repo.filters[0], tc.expectedFilter)
+			}
+		})
+	}
+}
+
+func TestShopOrderExport_ForEach(t *testing.T) {
+	pages := [][]*domain.Order{
+		{{ID: "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"}, {ID: "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a12"}},
+		{{ID: "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a13"}},
+	}
+	query := dto.ExportOrdersQuery{From: "2025-01-01", To: "2025-02-01"}
+	errStop := errors.New("client disconnected")
+
+	t.Run("Walks every page", func(t *testing.T) {
+		repo := &fakeExportRepository{pages: pages}
+		uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+		orderExport, err := uc.ExportShopOrders(context.Background(), testSellerID, testShopID, query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var ids []string
+		err = orderExport.ForEach(context.Background(), func(order *domain.Order) error {
+			ids = append(ids, order.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{pages[0][0].ID, pages[0][1].ID, pages[1][0].ID}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("ids = %v, want %v", ids, want)
+		}
+		if len(repo.filters) != 2 {
+			t.Fatalf("pages requested = %d, want 2", len(repo.filters))
+		}
+		if repo.filters[0].Cursor != nil {
+			t.Errorf("first page cursor = %+v, want none", repo.filters[0].Cursor)
+		}
+		if cursor := repo.filters[1].Cursor; cursor == nil || cursor.ID != pages[0][1].ID {
+			t.Errorf("second page cursor = %+v, want after %s", cursor, pages[0][1].ID)
+		}
+	})
+
+	t.Run("Stops at the first write error", func(t *testing.T) {
+		repo := &fakeExportRepository{pages: pages}
+		uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+		orderExport, err := uc.ExportShopOrders(context.Background(), testSellerID, testShopID, query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		written := 0
+		err = orderExport.ForEach(context.Background(), func(order *domain.Order) error {
+			written++
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("error = %v, want %v", err, errStop)
+		}
+		if written != 1 || len(repo.filters) != 1 {
+			t.Errorf("written = %d, pages = %d, want 1 and 1", written, len(repo.filters))
+		}
+	})
+
+	t.Run("Canceled context", func(t *testing.T) {
+		repo := &fakeExportRepository{pages: pages}
+		uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+		orderExport, err := uc.ExportShopOrders(context.Background(), testSellerID, testShopID, query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = orderExport.ForEach(ctx, func(order *domain.Order) error { return nil })
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+		if len(repo.filters) != 0 {
+			t.Errorf("pages requested = %d, want 0", len(repo.filters))
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	time_utils "github.com/toji-dev/go-shop/internal/pkg/time"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+)
+
+const (
+	// Đơn và payment phải đứng yên lâu hơn khoảng này mới được đối soát, để callback / event thanh toán
+	// đang được retry kịp đến trước khi reconciler can thiệp
+	PAYMENT_RECONCILE_SETTLE_TIME = 10 * time.Minute
+	// Chỉ đối soát các đơn đổi trạng thái gần đây, lệch cũ hơn đã nằm trong bảng review
+	PAYMENT_RECONCILE_LOOKBACK = 7 * 24 * time.Hour
+	// Bằng giới hạn số đơn mỗi lần gọi GetPaymentsByOrders của payment-service
+	PAYMENT_RECONCILE_PAGE_SIZE = 100
+	// Khoản thu tiền mặt vừa ghi nhận đang được CollectCash xác nhận, chỉ gửi lại các khoản cũ hơn khoảng này
+	CASH_CONFIRM_SETTLE_TIME = time.Minute
+	CASH_CONFIRM_BATCH_SIZE  = 100
+
+	paymentReconcileResolution = "order and payment are consistent again"
+
+	divergenceOutcomeRepaired     = "repaired"
+	divergenceOutcomeRepairFailed = "repair_failed"
+	divergenceOutcomeReview       = "review"
+)
+
+var paymentDivergencesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "order_service",
+	Subsystem: "reconciler",
+	Name:      "payment_divergences_total",
+	Help:      "Orders whose status diverged from payment-service, by divergence type and how the reconciler handled it.",
+}, []string{"type", "outcome"})
+
+type paymentReconcileSummary struct {
+	checked  int
+	repaired int
+	review   int
+}
+
+// ReconcilePayments so trạng thái các đơn phụ thuộc vào thanh toán với payment tương ứng ở payment-service.
+// Lệch do mất callback / event (payment đã có kết quả nhưng đơn chưa cập nhật) được sửa bằng đúng lần chuyển
+// trạng thái bị mất; các lệch còn lại được ghi vào bảng review để người kiểm tra.
+func (r *OrderReconciler) ReconcilePayments() {
+	ctx := context.Background()
+	log.Println("[OrderReconciler] Starting payment reconciliation...")
+
+	now := time_utils.GetUtcTime()
+	settledBefore := now.Add(-PAYMENT_RECONCILE_SETTLE_TIME)
+	afterUpdatedAt, afterID := now.Add(-PAYMENT_RECONCILE_LOOKBACK), ""
+
+	var summary paymentReconcileSummary
+	for {
+		candidates, err := r.reconciliationRepo.ListPaymentReconciliationCandidates(ctx, domain.PaymentReconciliationStatuses, settledBefore, afterUpdatedAt, afterID, PAYMENT_RECONCILE_PAGE_SIZE)
+		if err != nil {
+			log.Printf("[OrderReconciler] Error fetching orders for payment reconciliation: %v", err)
+			break
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		r.reconcilePaymentPage(ctx, candidates, settledBefore, &summary)
+
+		// Đơn vừa được sửa có updated_at mới nên không quay lại ở các trang sau
+		last := candidates[len(candidates)-1]
+		afterUpdatedAt, afterID = last.UpdatedAt, last.OrderID
+		if len(candidates) < PAYMENT_RECONCILE_PAGE_SIZE {
+			break
+		}
+	}
+
+	log.Printf("[OrderReconciler] Payment reconciliation finished: checked=%d repaired=%d review=%d",
+		summary.checked, summary.repaired, summary.review)
+}
+
+func (r *OrderReconciler) reconcilePaymentPage(ctx context.Context, candidates []domain.PaymentReconciliationCandidate, settledBefore time.Time, summary *paymentReconcileSummary) {
+	orderIDs := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		orderIDs = append(orderIDs, candidate.OrderID)
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	resp, err := r.paymentAdapter.GetPaymentsByOrders(ctxWithTimeout, orderIDs)
+	cancel()
+	if err != nil {
+		// Bỏ qua trang này, lần chạy sau sẽ đối soát lại
+		log.Printf("[OrderReconciler] Error fetching payments of %d orders: %v", len(orderIDs), err)
+		return
+	}
+
+	payments := make(map[string]*payment_v1.Payment, len(resp.GetPayments()))
+	for _, payment := range resp.GetPayments() {
+		payments[payment.GetOrderId()] = payment
+	}
+
+	consistent := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		payment := payments[candidate.OrderID]
+		if payment != nil && paymentChangedAfter(payment, settledBefore) {
+			continue
+		}
+		summary.checked++
+
+		paymentStatus := toDomainPaymentStatus(payment)
+		divergence, found := domain.DetectPaymentDivergence(candidate, paymentStatus, payment.GetPaymentMethod())
+		if !found {
+			consistent = append(consistent, candidate.OrderID)
+			continue
+		}
+
+		if r.handlePaymentDivergence(ctx, candidate, payment, paymentStatus, divergence) {
+			summary.repaired++
+			consistent = append(consistent, candidate.OrderID)
+		} else {
+			summary.review++
+		}
+	}
+
+	if _, err := r.reconciliationRepo.ResolveReviews(ctx, consistent, paymentReconcileResolution); err != nil {
+		log.Printf("[OrderReconciler] Error resolving reconciliation reviews: %v", err)
+	}
+}
+
+// handlePaymentDivergence sửa lệch nếu được, ngược lại ghi vào bảng review. Trả về true khi đã sửa xong.
+func (r *OrderReconciler) handlePaymentDivergence(ctx context.Context, candidate domain.PaymentReconciliationCandidate, payment *payment_v1.Payment, paymentStatus domain.PaymentStatus, divergence domain.PaymentDivergence) bool {
+	details := domain.DescribePaymentDivergence(candidate, paymentStatus)
+	outcome := divergenceOutcomeReview
+
+	if divergence.CanRepair() {
+		_, err := r.orderRepo.UpdateOrderStatus(ctx, candidate.OrderID, sqlc.OrderStatus(divergence.RepairStatus), domain.StatusChange{
+			Actor:  domain.StatusActorReconciler,
+			Reason: fmt.Sprintf("payment reconciliation: %s", divergence.Type),
+		})
+		if err == nil {
+			log.Printf("[OrderReconciler] Repaired order %s (%s): %s -> %s", candidate.OrderID, divergence.Type, candidate.Status, divergence.RepairStatus)
+			if divergence.RepairStatus == domain.OrderStatusPAYMENTFAILED {
+				r.releaseFailedOrderStock(ctx, candidate.OrderID)
+			}
+			paymentDivergencesTotal.WithLabelValues(string(divergence.Type), divergenceOutcomeRepaired).Inc()
+			return true
+		}
+
+		log.Printf("[OrderReconciler] Error repairing order %s (%s): %v", candidate.OrderID, divergence.Type, err)
+		outcome = divergenceOutcomeRepairFailed
+		details = fmt.Sprintf("%s; repair to %s failed: %v", details, divergence.RepairStatus, err)
+	}
+
+	review := &domain.PaymentReconciliationReview{
+		OrderID:        candidate.OrderID,
+		DivergenceType: divergence.Type,
+		OrderStatus:    candidate.Status,
+		Details:        details,
+	}
+	if payment != nil {
+		paymentID := payment.GetId()
+		review.PaymentID = &paymentID
+		review.PaymentStatus = &paymentStatus
+	}
+
+	if _, err := r.reconciliationRepo.RecordReview(ctx, review); err != nil {
+		log.Printf("[OrderReconciler] CRITICAL: Error recording reconciliation review of order %s (%s): %v", candidate.OrderID, divergence.Type, err)
+	} else {
+		log.Printf("[OrderReconciler] Order %s needs review (%s): %s", candidate.OrderID, divergence.Type, details)
+	}
+	paymentDivergencesTotal.WithLabelValues(string(divergence.Type), outcome).Inc()
+	return false
+}
+
+// releaseFailedOrderStock trả lại tồn kho của đơn vừa được sửa sang PAYMENT_FAILED, giống như khi đơn bị huỷ.
+func (r *OrderReconciler) releaseFailedOrderStock(ctx context.Context, orderID string) {
+	order, err := r.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[OrderReconciler] CRITICAL: Error fetching order %s to release its stock: %v", orderID, err)
+		return
+	}
+	releaseOrderStock(ctx, r.orderRepo, r.productAdapter, order)
+}
+
+// paymentChangedAfter cho biết payment vừa đổi trạng thái, khi đó event tương ứng có thể vẫn đang trên đường tới.
+func paymentChangedAfter(payment *payment_v1.Payment, settledBefore time.Time) bool {
+	updatedAt, err := time.Parse(time.RFC3339, payment.GetUpdatedAt())
+	if err != nil {
+		return false
+	}
+	return updatedAt.After(settledBefore)
+}
+
+func toDomainPaymentStatus(payment *payment_v1.Payment) domain.PaymentStatus {
+	if payment == nil {
+		return ""
+	}
+
+	switch payment.GetStatus() {
+	case payment_v1.PaymentStatus_PAYMENT_STATUS_PENDING:
+		return domain.PaymentStatusPending
+	case payment_v1.PaymentStatus_PAYMENT_STATUS_PROCESSING:
+		return domain.PaymentStatusProcessing
+	case payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS:
+		return domain.PaymentStatusSuccess
+	case payment_v1.PaymentStatus_PAYMENT_STATUS_FAILED:
+		return domain.PaymentStatusFailed
+	case payment_v1.PaymentStatus_PAYMENT_STATUS_REFUNDED:
+		return domain.PaymentStatusRefunded
+	default:
+		return domain.PaymentStatus(payment.GetStatus().String())
+	}
+}
+
+// ReconcileCashCollections gửi lại xác nhận thu tiền cho các khoản thu COD mà payment-service chưa ghi nhận,
+// ví dụ khi payment-service không gọi được lúc shipper báo thu tiền.
+func (r *OrderReconciler) ReconcileCashCollections() {
+	ctx := context.Background()
+	log.Println("[OrderReconciler] Starting cash collection reconciliation...")
+
+	collectedBefore := time_utils.GetUtcTime().Add(-CASH_CONFIRM_SETTLE_TIME)
+	collections, err := r.shipperCashRepo.ListUnconfirmedCollections(ctx, collectedBefore, CASH_CONFIRM_BATCH_SIZE)
+	if err != nil {
+		log.Printf("[OrderReconciler] Error fetching unconfirmed cash collections: %v", err)
+		return
+	}
+
+	confirmed := 0
+	for _, collection := range collections {
+		if _, err := confirmCashCollection(ctx, r.paymentAdapter, r.shipperCashRepo, collection); err != nil {
+			log.Printf("[OrderReconciler] Error confirming cash collection %s of order %s: %v", collection.ID, collection.OrderID, err)
+			continue
+		}
+		confirmed++
+	}
+
+	log.Printf("[OrderReconciler] Cash collection reconciliation finished: confirmed=%d/%d", confirmed, len(collections))
+}
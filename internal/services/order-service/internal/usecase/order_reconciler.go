@@ -8,6 +8,7 @@ import (
 
 	time_utils "github.com/toji-dev/go-shop/internal/pkg/time"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
 	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
@@ -20,14 +21,26 @@ const (
 )
 
 type OrderReconciler struct {
-	orderRepo      repository.OrderRepository
-	productAdapter adapter.ProductServiceAdapter
+	orderRepo          repository.OrderRepository
+	reconciliationRepo repository.ReconciliationRepository
+	shipperCashRepo    repository.ShipperCashRepository
+	productAdapter     adapter.ProductServiceAdapter
+	paymentAdapter     adapter.PaymentServiceAdapter
 }
 
-func NewOrderReconciler(orderRepo repository.OrderRepository, productAdapter adapter.ProductServiceAdapter) *OrderReconciler {
+func NewOrderReconciler(
+	orderRepo repository.OrderRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	shipperCashRepo repository.ShipperCashRepository,
+	productAdapter adapter.ProductServiceAdapter,
+	paymentAdapter adapter.PaymentServiceAdapter,
+) *OrderReconciler {
 	return &OrderReconciler{
-		orderRepo:      orderRepo,
-		productAdapter: productAdapter,
+		orderRepo:          orderRepo,
+		reconciliationRepo: reconciliationRepo,
+		shipperCashRepo:    shipperCashRepo,
+		productAdapter:     productAdapter,
+		paymentAdapter:     paymentAdapter,
 	}
 }
 
@@ -99,7 +112,10 @@ func (r *OrderReconciler) HandleUnreservationOrders(ctx context.Context, getStat
 		if !status.Founded {
 			log.Printf("[OrderReconciler] Order ID: %s not found. Updating status to CANCELLED.", status.OrderId)
 			// Update the order status to CANCELLED
-			_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED)
+			_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED, domain.StatusChange{
+				Actor:  domain.StatusActorReconciler,
+				Reason: "stale order without stock reservation",
+			})
 			if err != nil {
 				log.Printf("[OrderReconciler] Error updating order status: %v", err)
 			}
@@ -110,7 +126,10 @@ func (r *OrderReconciler) HandleUnreservationOrders(ctx context.Context, getStat
 		case product_v1.GetOrderReservationStatusResponse_UNRESERVED.String():
 			log.Printf("[OrderReconciler] Order ID: %s is unreserved. No action needed.", status.OrderId)
 			// Update the order status to CANCELED
-			_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED)
+			_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED, domain.StatusChange{
+				Actor:  domain.StatusActorReconciler,
+				Reason: "stale order with unreserved stock",
+			})
 			if err != nil {
 				log.Printf("[OrderReconciler] Error updating order status to UNRESERVED: %v", err)
 			}
@@ -153,7 +172,10 @@ func (r *OrderReconciler) HandleUnreservationOrders(ctx context.Context, getStat
 			// Check result and update order status
 			if len(resp.Results) > 0 && resp.Results[0].Success {
 				log.Printf("[OrderReconciler] Successfully unreserved order %s", status.OrderId)
-				_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED)
+				_, err := r.orderRepo.UpdateOrderStatus(ctx, status.OrderId, sqlc.OrderStatusCANCELED, domain.StatusChange{
+					Actor:  domain.StatusActorReconciler,
+					Reason: "stale order, reserved stock released",
+				})
 				if err != nil {
 					log.Printf("[OrderReconciler] Error updating order status to CANCELED: %v", err)
 				}
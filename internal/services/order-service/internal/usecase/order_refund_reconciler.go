@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	time_utils "github.com/toji-dev/go-shop/internal/pkg/time"
+)
+
+const (
+	// Khoảng chờ tối thiểu giữa hai lần gửi lại yêu cầu hoàn tiền của cùng một đơn
+	CANCELLATION_REFUND_RETRY_DELAY = 5 * time.Minute
+	// Quá số lần này thì dừng gửi lại, đơn nằm lại trong bảng review của ReconcilePayments để người kiểm tra
+	CANCELLATION_REFUND_MAX_ATTEMPTS = 10
+	CANCELLATION_REFUND_BATCH_SIZE   = 100
+)
+
+// RetryCancellationRefunds gửi lại các yêu cầu hoàn tiền bị lỗi ngay sau khi đơn được huỷ
+// (khách huỷ, người bán từ chối hoặc huỷ dòng hàng cuối cùng).
+func (r *OrderReconciler) RetryCancellationRefunds() {
+	ctx := context.Background()
+
+	updatedBefore := time_utils.GetUtcTime().Add(-CANCELLATION_REFUND_RETRY_DELAY)
+	cancellations, err := r.orderRepo.ListCancellationsForRefundRetry(ctx, CANCELLATION_REFUND_MAX_ATTEMPTS, updatedBefore, CANCELLATION_REFUND_BATCH_SIZE)
+	if err != nil {
+		log.Printf("[OrderReconciler] Error fetching cancellations with failed refunds: %v", err)
+		return
+	}
+	if len(cancellations) == 0 {
+		return
+	}
+
+	log.Printf("[OrderReconciler] Retrying refunds of %d canceled orders.", len(cancellations))
+
+	requested := 0
+	for _, cancellation := range cancellations {
+		if r.retryCancellationRefund(ctx, cancellation.OrderID, cancellation.Reason) {
+			requested++
+		}
+	}
+
+	log.Printf("[OrderReconciler] Refund retry finished: resolved=%d failed=%d", requested, len(cancellations)-requested)
+}
+
+// retryCancellationRefund trả về true khi đơn không còn cần gửi lại yêu cầu hoàn tiền.
+func (r *OrderReconciler) retryCancellationRefund(ctx context.Context, orderID string, reason string) bool {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	refunded, err := requestCancellationRefund(ctxWithTimeout, r.orderRepo, r.paymentAdapter, orderID, reason)
+	if err != nil {
+		failed := recordCancellationRefundFailure(ctx, r.orderRepo, orderID, err)
+		if failed != nil && failed.RefundAttempts >= CANCELLATION_REFUND_MAX_ATTEMPTS {
+			log.Printf("CRITICAL: Refund of canceled order %s still failing after %d attempts. Manual intervention required. Error: %v", orderID, failed.RefundAttempts, err)
+		} else {
+			log.Printf("[OrderReconciler] Refund retry of canceled order %s failed: %v", orderID, err)
+		}
+		return false
+	}
+
+	// Không còn khoản thanh toán nào cần hoàn (chưa thanh toán, COD đã huỷ hoặc đã được hoàn bằng cách khác)
+	if refunded == nil {
+		if _, err := r.orderRepo.ClearCancellationRefundFailure(ctx, orderID); err != nil {
+			log.Printf("[OrderReconciler] Error clearing refund failure of order %s: %v", orderID, err)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testCustomerID = "7a1d2c3b-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+
+type fakeCancellationRepository struct {
+	repository.OrderRepository
+	order        *domain.Order
+	cancellation *domain.OrderCancellation
+}
+
+func (f *fakeCancellationRepository) GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error) {
+	if f.order == nil || orderID != f.order.ID {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	order := *f.order
+	return &order, nil
+}
+
+func (f *fakeCancellationRepository) CancelOrder(ctx context.Context, order *domain.Order, canceledBy string, change domain.StatusChange) (*domain.Order, *domain.OrderCancellation, error) {
+	f.cancellation = &domain.OrderCancellation{
+		OrderID:        order.ID,
+		CanceledBy:     canceledBy,
+		Reason:         change.Reason,
+		PreviousStatus: order.Status,
+	}
+	f.order.Status = domain.OrderStatusCANCELED
+	canceled := *f.order
+	cancellation := *f.cancellation
+	return &canceled, &cancellation, nil
+}
+
+func (f *fakeCancellationRepository) MarkCancellationRefundRequested(ctx context.Context, orderID string, refundID string) (*domain.OrderCancellation, error) {
+	f.cancellation.RefundRequested = true
+	f.cancellation.RefundID = &refundID
+	f.cancellation.RefundError = nil
+	cancellation := *f.cancellation
+	return &cancellation, nil
+}
+
+func (f *fakeCancellationRepository) RecordCancellationRefundFailure(ctx context.Context, orderID string, refundErr string) (*domain.OrderCancellation, error) {
+	if f.cancellation == nil || f.cancellation.RefundRequested {
+		return nil, apperror.NewNotFound("Order cancellation", orderID)
+	}
+	f.cancellation.RefundError = &refundErr
+	f.cancellation.RefundAttempts++
+	cancellation := *f.cancellation
+	return &cancellation, nil
+}
+
+func (f *fakeCancellationRepository) ClearCancellationRefundFailure(ctx context.Context, orderID string) (*domain.OrderCancellation, error) {
+	f.cancellation.RefundError = nil
+	cancellation := *f.cancellation
+	return &cancellation, nil
+}
+
+func (f *fakeCancellationRepository) ListCancellationsForRefundRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.OrderCancellation, error) {
+	if f.cancellation == nil || !f.cancellation.NeedsRefundRetry() || f.cancellation.RefundAttempts >= maxAttempts {
+		return []*domain.OrderCancellation{}, nil
+	}
+	cancellation := *f.cancellation
+	return []*domain.OrderCancellation{&cancellation}, nil
+}
+
+type fakePaymentServiceAdapter struct {
+	adapter.PaymentServiceAdapter
+	status      payment_v1.PaymentStatus // PAYMENT_STATUS_UNSPECIFIED: đơn chưa có payment
+	unavailable bool
+	refunds     int
+	canceled    int
+	// paidWhileCanceling mô phỏng khách thanh toán xong ngay trước khi payment PENDING bị huỷ
+	paidWhileCanceling bool
+}
+
+func (f *fakePaymentServiceAdapter) GetPaymentByOrder(ctx context.Context, orderID string) (*payment_v1.GetPaymentByOrderResponse, error) {
+	if f.unavailable {
+		return nil, errors.New("payment-service unavailable")
+	}
+	if f.status == payment_v1.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED {
+		return &payment_v1.GetPaymentByOrderResponse{}, nil
+	}
+	return &payment_v1.GetPaymentByOrderResponse{
+		Exists:  true,
+		Payment: &payment_v1.Payment{OrderId: orderID, PaymentMethod: "E_WALLET", Status: f.status},
+	}, nil
+}
+
+func (f *fakePaymentServiceAdapter) RequestRefund(ctx context.Context, orderID string, reason string) (*payment_v1.RequestRefundResponse, error) {
+	f.refunds++
+	return &payment_v1.RequestRefundResponse{Accepted: true, RefundId: "refund-1"}, nil
+}
+
+func (f *fakePaymentServiceAdapter) CancelPendingPayment(ctx context.Context, orderID string, reason string) (*payment_v1.CancelPendingPaymentResponse, error) {
+	if f.paidWhileCanceling {
+		f.status = payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS
+		return nil, status.Error(codes.FailedPrecondition, "payment is SUCCESS")
+	}
+	f.canceled++
+	f.status = payment_v1.PaymentStatus_PAYMENT_STATUS_FAILED
+	return &payment_v1.CancelPendingPaymentResponse{}, nil
+}
+
+type fakeProductServiceAdapter struct {
+	adapter.ProductServiceAdapter
+}
+
+func (f *fakeProductServiceAdapter) GetOrderReservationStatus(ctx context.Context, req *product_v1.GetOrderReservationStatusRequest) (*product_v1.GetOrderReservationStatusResponse, error) {
+	return &product_v1.GetOrderReservationStatusResponse{}, nil
+}
+
+func TestOrderUsecase_CancelOrder_RecordsRefundFailure(t *testing.T) {
+	repo := &fakeCancellationRepository{
+		order: &domain.Order{ID: testOrderID, OwnerID: testCustomerID, ShopID: testShopID, Status: domain.OrderStatusPROCESSING},
+	}
+	payment := &fakePaymentServiceAdapter{status: payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS, unavailable: true}
+	uc := usecase.NewOrderUsecase(repo, nil, &fakeProductServiceAdapter{}, nil, payment, nil, nil)
+
+	order, _, err := uc.CancelOrder(context.Background(), testCustomerID, testOrderID, dto.CancelOrderRequest{Reason: "changed my mind"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != domain.OrderStatusCANCELED {
+		t.Fatalf("status = %s, want CANCELED", order.Status)
+	}
+
+	// Lỗi hoàn tiền không làm hỏng việc huỷ đơn nhưng được lưu lại để reconciler gửi lại
+	if !repo.cancellation.NeedsRefundRetry() || repo.cancellation.RefundAttempts != 1 {
+		t.Fatalf("cancellation = %+v, want a recorded refund failure", repo.cancellation)
+	}
+
+	payment.unavailable = false
+	usecase.NewOrderReconciler(repo, nil, nil, nil, payment).RetryCancellationRefunds()
+
+	if !repo.cancellation.RefundRequested || repo.cancellation.RefundError != nil {
+		t.Errorf("cancellation = %+v, want refund requested after retry", repo.cancellation)
+	}
+	if payment.refunds != 1 {
+		t.Errorf("refund requests = %d, want 1", payment.refunds)
+	}
+}
+
+func TestOrderUsecase_CancelOrder_CancelsPendingPayment(t *testing.T) {
+	testCases := []struct {
+		name               string
+		paidWhileCanceling bool
+		expectedCanceled   int
+		expectedRefunds    int
+	}{
+		{name: "Pending e-wallet payment is canceled", expectedCanceled: 1},
+		{name: "Payment captured while canceling is refunded", paidWhileCanceling: true, expectedRefunds: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeCancellationRepository{
+				order: &domain.Order{ID: testOrderID, OwnerID: testCustomerID, ShopID: testShopID, Status: domain.OrderStatusPENDINGPAYMENT},
+			}
+			payment := &fakePaymentServiceAdapter{
+				status:             payment_v1.PaymentStatus_PAYMENT_STATUS_PENDING,
+				paidWhileCanceling: tc.paidWhileCanceling,
+			}
+			uc := usecase.NewOrderUsecase(repo, nil, &fakeProductServiceAdapter{}, nil, payment, nil, nil)
+
+			if _, _, err := uc.CancelOrder(context.Background(), testCustomerID, testOrderID, dto.CancelOrderRequest{Reason: "changed my mind"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if payment.canceled != tc.expectedCanceled {
+				t.Errorf("canceled payments = %d, want %d", payment.canceled, tc.expectedCanceled)
+			}
+			if payment.refunds != tc.expectedRefunds {
+				t.Errorf("refund requests = %d, want %d", payment.refunds, tc.expectedRefunds)
+			}
+			if repo.cancellation.NeedsRefundRetry() {
+				t.Errorf("cancellation = %+v, want no refund failure", repo.cancellation)
+			}
+		})
+	}
+}
+
+func TestOrderReconciler_RetryCancellationRefunds(t *testing.T) {
+	testCases := []struct {
+		name              string
+		paymentStatus     payment_v1.PaymentStatus
+		unavailable       bool
+		attempts          int
+		expectedRequested bool
+		expectedRetry     bool
+		expectedAttempts  int
+		expectedRefunds   int
+	}{
+		{
+			name:              "Paid order is refunded",
+			paymentStatus:     payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS,
+			attempts:          1,
+			expectedRequested: true,
+			expectedAttempts:  1,
+			expectedRefunds:   1,
+		},
+		{
+			name:             "Payment-service still unavailable",
+			paymentStatus:    payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS,
+			unavailable:      true,
+			attempts:         1,
+			expectedRetry:    true,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "Nothing to refund clears the failure",
+			paymentStatus:    payment_v1.PaymentStatus_PAYMENT_STATUS_FAILED,
+			attempts:         1,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "Attempts exhausted are not retried",
+			paymentStatus:    payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS,
+			attempts:         usecase.CANCELLATION_REFUND_MAX_ATTEMPTS,
+			expectedRetry:    true,
+			expectedAttempts: usecase.CANCELLATION_REFUND_MAX_ATTEMPTS,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			refundErr := "failed to get payment of order: unavailable"
+			repo := &fakeCancellationRepository{
+				cancellation: &domain.OrderCancellation{
+					OrderID:        testOrderID,
+					Reason:         "changed my mind",
+					RefundError:    &refundErr,
+					RefundAttempts: tc.attempts,
+				},
+			}
+			payment := &fakePaymentServiceAdapter{status: tc.paymentStatus, unavailable: tc.unavailable}
+
+			usecase.NewOrderReconciler(repo, nil, nil, nil, payment).RetryCancellationRefunds()
+
+			cancellation := repo.cancellation
+			if cancellation.RefundRequested != tc.expectedRequested {
+				t.Errorf("refund requested = %v, want %v", cancellation.RefundRequested, tc.expectedRequested)
+			}
+			if cancellation.NeedsRefundRetry() != tc.expectedRetry {
+				t.Errorf("needs retry = %v, want %v", cancellation.NeedsRefundRetry(), tc.expectedRetry)
+			}
+			if cancellation.RefundAttempts != tc.expectedAttempts {
+				t.Errorf("attempts = %d, want %d", cancellation.RefundAttempts, tc.expectedAttempts)
+			}
+			if payment.refunds != tc.expectedRefunds {
+				t.Errorf("refund requests = %d, want %d", payment.refunds, tc.expectedRefunds)
+			}
+		})
+	}
+}
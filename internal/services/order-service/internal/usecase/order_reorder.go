@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Reorder thêm lại các sản phẩm của một đơn cũ vào giỏ hàng của khách. Từng sản phẩm được kiểm tra lại qua
+// product-service: sản phẩm không còn bán hoặc hết hàng bị bỏ qua, sản phẩm còn bán được thêm với giá hiện tại
+// và báo lại nếu giá đã thay đổi. Giỏ hàng không lưu giá nên checkout sau đó luôn dùng giá mới nhất.
+func (u *orderUsecase) Reorder(ctx context.Context, userId string, orderID string) (*domain.ReorderResult, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "Reorder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID != userId {
+		log.Printf("User %s is not allowed to reorder order %s", userId, orderID)
+		return nil, apperror.NewForbidden("You are not allowed to reorder this order")
+	}
+
+	productIDs := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	_, productInfoSpan := tracer.Start(ctx, "GetProductsInfo_gRPC")
+	productsInfo, err := u.productServiceAdapter.GetProductsInfo(ctx, &product_v1.GetProductsInfoRequest{
+		ProductIds: productIDs,
+	})
+	if err != nil {
+		productInfoSpan.SetStatus(codes.Error, err.Error())
+		productInfoSpan.End()
+		return nil, apperror.NewDependencyFailure(fmt.Sprintf("Failed to get product info: %s", err.Error()))
+	}
+	productInfoSpan.End()
+
+	products := make(map[string]*product_v1.ProductInfo, len(productsInfo.GetProducts()))
+	for _, p := range productsInfo.GetProducts() {
+		products[p.GetId()] = p
+	}
+
+	result := &domain.ReorderResult{OrderID: order.ID}
+	cartItems := make([]*cart_v1.CartItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		reorderItem := compareReorderItem(item, products[item.ProductID])
+		if reorderItem.UnavailableReason != "" {
+			result.Unavailable = append(result.Unavailable, reorderItem)
+			continue
+		}
+
+		result.Added = append(result.Added, reorderItem)
+		cartItems = append(cartItems, &cart_v1.CartItem{
+			ProductId: item.ProductID,
+			ShopId:    products[item.ProductID].GetShopId(),
+			Quantity:  int32(reorderItem.Quantity),
+		})
+	}
+
+	span.SetAttributes(
+		attribute.Int("reorder.added_count", len(result.Added)),
+		attribute.Int("reorder.unavailable_count", len(result.Unavailable)),
+	)
+
+	if len(cartItems) == 0 {
+		span.SetStatus(codes.Error, "no item is available")
+		return nil, apperror.New(apperror.CodeConflict, "None of the products in this order are available anymore", apperror.TypeConflict)
+	}
+
+	_, cartSpan := tracer.Start(ctx, "AddCartItems_gRPC")
+	if _, err := u.cartAdapter.AddCartItems(ctx, userId, cartItems); err != nil {
+		cartSpan.SetStatus(codes.Error, err.Error())
+		cartSpan.End()
+		log.Printf("Failed to add items of order %s to cart of user %s: %v", orderID, userId, err)
+		return nil, apperror.NewDependencyFailure(fmt.Sprintf("Failed to add items to cart: %s", err.Error()))
+	}
+	cartSpan.End()
+
+	span.AddEvent("Order items added back to cart")
+	return result, nil
+}
+
+// compareReorderItem đối chiếu một dòng của đơn cũ với sản phẩm hiện tại; product nil nghĩa là sản phẩm không còn tồn tại.
+// Quantity của product-service là tồn kho chưa trừ hàng đang giữ nên checkout vẫn kiểm tra lại khi giữ hàng.
+func compareReorderItem(item domain.OrderItem, product *product_v1.ProductInfo) domain.ReorderItem {
+	reorderItem := domain.ReorderItem{
+		ProductID:         item.ProductID,
+		ProductName:       item.ProductName,
+		RequestedQuantity: item.Quantity,
+		PreviousPrice:     item.Price,
+		CurrentPrice:      money.Zero(item.Price.Currency),
+	}
+
+	if product == nil {
+		reorderItem.UnavailableReason = domain.ReorderReasonProductNotFound
+		return reorderItem
+	}
+
+	reorderItem.ProductName = product.GetName()
+	reorderItem.CurrentPrice = productUnitPrice(product)
+	if product.GetQuantity() <= 0 {
+		reorderItem.UnavailableReason = domain.ReorderReasonOutOfStock
+		return reorderItem
+	}
+
+	reorderItem.Quantity = min(item.Quantity, int(product.GetQuantity()))
+	return reorderItem
+}
@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+	cart_v1 "github.com/toji-dev/go-shop/proto/gen/go/cart/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+)
+
+// fakeReorderCatalog chỉ trả về các sản phẩm còn tồn tại, giống product-service khi một số sản phẩm đã bị xoá.
+type fakeReorderCatalog struct {
+	adapter.ProductServiceAdapter
+	products map[string]*product_v1.ProductInfo
+}
+
+func (f *fakeReorderCatalog) GetProductsInfo(ctx context.Context, req *product_v1.GetProductsInfoRequest) (*product_v1.GetProductsInfoResponse, error) {
+	res := &product_v1.GetProductsInfoResponse{Valid: true}
+	for _, id := range req.GetProductIds() {
+		if product, ok := f.products[id]; ok {
+			res.Products = append(res.Products, product)
+		} else {
+			res.Valid = false
+		}
+	}
+	return res, nil
+}
+
+func newStockedProduct(id string, price money.Money, quantity int32) *product_v1.ProductInfo {
+	product := newTestProduct(id, price)
+	product.Quantity = quantity
+	return product
+}
+
+func newReorderOrder(items ...domain.OrderItem) *domain.Order {
+	return &domain.Order{ID: testOrderID, OwnerID: testCustomerID, ShopID: testShopID, Status: domain.OrderStatusDELIVERED, Items: items}
+}
+
+func TestOrderUsecase_Reorder(t *testing.T) {
+	order := newReorderOrder(
+		domain.OrderItem{ProductID: "product-1", ProductName: "Keyboard", Quantity: 2, Price: money.New(1250, "USD")},
+		domain.OrderItem{ProductID: "product-2", ProductName: "Mouse", Quantity: 3, Price: money.New(750, "USD")},
+		domain.OrderItem{ProductID: "product-3", ProductName: "Monitor", Quantity: 1, Price: money.New(19900, "USD")},
+		domain.OrderItem{ProductID: "product-4", ProductName: "Cable", Quantity: 1, Price: money.New(300, "USD")},
+	)
+	repriced := newStockedProduct("product-2", money.New(800, "USD"), 2)
+	repriced.Name = "Mouse v2"
+	catalog := &fakeReorderCatalog{products: map[string]*product_v1.ProductInfo{
+		"product-1": newStockedProduct("product-1", money.New(1250, "USD"), 10),
+		"product-2": repriced,
+		"product-4": newStockedProduct("product-4", money.New(300, "USD"), 0),
+	}}
+	cart := &fakeCartAdapter{}
+	uc := newReorderUsecase(order, catalog, cart)
+
+	result, err := uc.Reorder(context.Background(), testCustomerID, testOrderID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.OrderID != testOrderID || len(result.Added) != 2 || len(result.Unavailable) != 2 {
+		t.Fatalf("result = %+v, want 2 added and 2 unavailable", result)
+	}
+
+	same := result.Added[0]
+	if same.ProductID != "product-1" || same.Quantity != 2 || same.PriceChanged() {
+		t.Errorf("product-1 = %+v, want 2 at the same price", same)
+	}
+
+	changed := result.Added[1]
+	if changed.ProductID != "product-2" || !changed.PriceChanged() {
+		t.Errorf("product-2 = %+v, want a price change", changed)
+	}
+	if !changed.PreviousPrice.Equal(money.New(750, "USD")) || !changed.CurrentPrice.Equal(money.New(800, "USD")) {
+		t.Errorf("product-2 prices = %s -> %s, want 7.50 -> 8.00 USD", changed.PreviousPrice.Decimal(), changed.CurrentPrice.Decimal())
+	}
+	if changed.RequestedQuantity != 3 || changed.Quantity != 2 {
+		t.Errorf("product-2 quantity = %d of %d, want 2 of 3 limited by stock", changed.Quantity, changed.RequestedQuantity)
+	}
+	if changed.ProductName != "Mouse v2" {
+		t.Errorf("product-2 name = %s, want the current name", changed.ProductName)
+	}
+
+	deleted := result.Unavailable[0]
+	if deleted.ProductID != "product-3" || deleted.UnavailableReason != domain.ReorderReasonProductNotFound {
+		t.Errorf("product-3 = %+v, want %s", deleted, domain.ReorderReasonProductNotFound)
+	}
+	if deleted.ProductName != "Monitor" || !deleted.CurrentPrice.IsZero() || deleted.PriceChanged() {
+		t.Errorf("product-3 = %+v, want the old name, no current price and no price change", deleted)
+	}
+
+	outOfStock := result.Unavailable[1]
+	if outOfStock.ProductID != "product-4" || outOfStock.UnavailableReason != domain.ReorderReasonOutOfStock || outOfStock.Quantity != 0 {
+		t.Errorf("product-4 = %+v, want %s", outOfStock, domain.ReorderReasonOutOfStock)
+	}
+
+	if len(cart.added) != 2 {
+		t.Fatalf("cart items = %v, want only the available products", cart.added)
+	}
+	for i, want := range []*cart_v1.CartItem{
+		{ProductId: "product-1", ShopId: testShopID, Quantity: 2},
+		{ProductId: "product-2", ShopId: testShopID, Quantity: 2},
+	} {
+		got := cart.added[i]
+		if got.GetProductId() != want.GetProductId() || got.GetShopId() != want.GetShopId() || got.GetQuantity() != want.GetQuantity() {
+			t.Errorf("cart item %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestOrderUsecase_Reorder_CurrencyChange(t *testing.T) {
+	order := newReorderOrder(domain.OrderItem{ProductID: "product-1", Quantity: 1, Price: money.New(1250, "USD")})
+	catalog := &fakeReorderCatalog{products: map[string]*product_v1.ProductInfo{
+		"product-1": newStockedProduct("product-1", money.New(1250, "EUR"), 5),
+	}}
+	uc := newReorderUsecase(order, catalog, &fakeCartAdapter{})
+
+	result, err := uc.Reorder(context.Background(), testCustomerID, testOrderID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Cùng số tiền nhưng khác tiền tệ vẫn là đổi giá
+	if len(result.Added) != 1 || !result.Added[0].PriceChanged() {
+		t.Errorf("added = %+v, want a price change", result.Added)
+	}
+}
+
+func TestOrderUsecase_Reorder_Errors(t *testing.T) {
+	catalog := &fakeReorderCatalog{products: map[string]*product_v1.ProductInfo{
+		"product-1": newStockedProduct("product-1", money.New(1250, "USD"), 10),
+		"product-2": newStockedProduct("product-2", money.New(750, "USD"), 0),
+	}}
+	available := []domain.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.New(1250, "USD")}}
+
+	testCases := []struct {
+		name         string
+		userID       string
+		orderID      string
+		items        []domain.OrderItem
+		cart         *fakeCartAdapter
+		expectedType apperror.ErrorType
+	}{
+		{
+			name:    "No product is available",
+			userID:  testCustomerID,
+			orderID: testOrderID,
+			items: []domain.OrderItem{
+				{ProductID: "product-2", Quantity: 1, Price: money.New(750, "USD")},
+				{ProductID: "product-9", Quantity: 1, Price: money.New(500, "USD")},
+			},
+			cart:         &fakeCartAdapter{},
+			expectedType: apperror.TypeConflict,
+		},
+		{
+			name:         "Order of another customer",
+			userID:       testSellerID,
+			orderID:      testOrderID,
+			items:        available,
+			cart:         &fakeCartAdapter{},
+			expectedType: apperror.TypeForbidden,
+		},
+		{
+			name:         "Order not found",
+			userID:       testCustomerID,
+			orderID:      "order-2",
+			items:        available,
+			cart:         &fakeCartAdapter{},
+			expectedType: apperror.TypeNotFound,
+		},
+		{
+			name:         "Cart service failure",
+			userID:       testCustomerID,
+			orderID:      testOrderID,
+			items:        available,
+			cart:         &fakeCartAdapter{addErr: errors.New("cart service unavailable")},
+			expectedType: apperror.TypeDependencyFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := newReorderUsecase(newReorderOrder(tc.items...), catalog, tc.cart)
+
+			result, err := uc.Reorder(context.Background(), tc.userID, tc.orderID)
+
+			if err == nil {
+				t.Fatalf("expected error, got %+v", result)
+			}
+			if got := apperror.GetType(err); got != tc.expectedType {
+				t.Errorf("error type = %v, want %v", got, tc.expectedType)
+			}
+			if len(tc.cart.added) != 0 {
+				t.Errorf("cart items = %v, want none", tc.cart.added)
+			}
+		})
+	}
+}
+
+func newReorderUsecase(order *domain.Order, catalog *fakeReorderCatalog, cart *fakeCartAdapter) usecase.OrderUsecase {
+	return usecase.NewOrderUsecase(&fakeOrderRepository{order: order}, nil, catalog, nil, nil, cart, nil)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	time_utils "github.com/toji-dev/go-shop/internal/pkg/time"
+)
+
+const (
+	// Khoảng chờ tối thiểu giữa hai lần trả lại tồn kho của cùng một đơn
+	STOCK_RELEASE_RETRY_DELAY = 5 * time.Minute
+	// Quá số lần này thì dừng thử lại, dòng lỗi được giữ lại để người kiểm tra
+	STOCK_RELEASE_MAX_ATTEMPTS = 10
+	STOCK_RELEASE_BATCH_SIZE   = 100
+)
+
+// RetryStockReleases trả lại tồn kho của các đơn bị lỗi trả kho ngay sau khi huỷ
+// (khách huỷ, người bán từ chối, hết hạn thanh toán) hoặc sau khi được sửa sang PAYMENT_FAILED.
+func (r *OrderReconciler) RetryStockReleases() {
+	ctx := context.Background()
+
+	updatedBefore := time_utils.GetUtcTime().Add(-STOCK_RELEASE_RETRY_DELAY)
+	failures, err := r.orderRepo.ListStockReleaseFailuresForRetry(ctx, STOCK_RELEASE_MAX_ATTEMPTS, updatedBefore, STOCK_RELEASE_BATCH_SIZE)
+	if err != nil {
+		log.Printf("[OrderReconciler] Error fetching orders with failed stock releases: %v", err)
+		return
+	}
+	if len(failures) == 0 {
+		return
+	}
+
+	log.Printf("[OrderReconciler] Retrying stock releases of %d orders.", len(failures))
+
+	released := 0
+	for _, failure := range failures {
+		if r.retryStockRelease(ctx, failure.OrderID) {
+			released++
+		}
+	}
+
+	log.Printf("[OrderReconciler] Stock release retry finished: released=%d failed=%d", released, len(failures)-released)
+}
+
+// retryStockRelease trả về true khi tồn kho của đơn đã được trả (hoặc đơn không còn giữ hàng).
+func (r *OrderReconciler) retryStockRelease(ctx context.Context, orderID string) bool {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	order, err := r.orderRepo.GetOrderByID(ctxWithTimeout, orderID)
+	if err != nil && apperror.GetType(err) != apperror.TypeNotFound {
+		log.Printf("[OrderReconciler] Error fetching order %s to release its stock: %v", orderID, err)
+		return false
+	}
+
+	if order != nil {
+		if err := releaseReservedStock(ctxWithTimeout, r.productAdapter, order); err != nil {
+			failed := recordStockReleaseFailure(ctx, r.orderRepo, orderID, err)
+			if failed != nil && failed.Attempts >= STOCK_RELEASE_MAX_ATTEMPTS {
+				log.Printf("CRITICAL: Stock release of order %s still failing after %d attempts. Manual intervention required. Error: %v", orderID, failed.Attempts, err)
+			} else {
+				log.Printf("[OrderReconciler] Stock release retry of order %s failed: %v", orderID, err)
+			}
+			return false
+		}
+	}
+
+	if err := r.orderRepo.ClearStockReleaseFailure(ctx, orderID); err != nil {
+		log.Printf("[OrderReconciler] Error clearing stock release failure of order %s: %v", orderID, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+)
+
+// fakeStockReleaseRepository lưu lỗi trả tồn kho của một đơn như bảng order_stock_release_failures.
+type fakeStockReleaseRepository struct {
+	*fakeCancellationRepository
+	failure *domain.StockReleaseFailure
+}
+
+func (f *fakeStockReleaseRepository) RecordStockReleaseFailure(ctx context.Context, orderID string, releaseErr string) (*domain.StockReleaseFailure, error) {
+	if f.failure == nil {
+		f.failure = &domain.StockReleaseFailure{OrderID: orderID}
+	}
+	f.failure.LastError = releaseErr
+	f.failure.Attempts++
+	failure := *f.failure
+	return &failure, nil
+}
+
+func (f *fakeStockReleaseRepository) ClearStockReleaseFailure(ctx context.Context, orderID string) error {
+	f.failure = nil
+	return nil
+}
+
+func (f *fakeStockReleaseRepository) ListStockReleaseFailuresForRetry(ctx context.Context, maxAttempts int, updatedBefore time.Time, limit int) ([]*domain.StockReleaseFailure, error) {
+	if f.failure == nil || f.failure.Attempts >= maxAttempts {
+		return []*domain.StockReleaseFailure{}, nil
+	}
+	failure := *f.failure
+	return []*domain.StockReleaseFailure{&failure}, nil
+}
+
+// fakeReservationAdapter giữ hàng của đơn cho tới khi UnreserveOrders thành công.
+type fakeReservationAdapter struct {
+	adapter.ProductServiceAdapter
+	reserved    bool
+	unavailable bool
+	unreserved  int
+}
+
+func (f *fakeReservationAdapter) GetOrderReservationStatus(ctx context.Context, req *product_v1.GetOrderReservationStatusRequest) (*product_v1.GetOrderReservationStatusResponse, error) {
+	if f.unavailable {
+		return nil, errors.New("product-service unavailable")
+	}
+	if !f.reserved {
+		return &product_v1.GetOrderReservationStatusResponse{Founded: true, Status: product_v1.GetOrderReservationStatusResponse_UNRESERVED.String()}, nil
+	}
+	return &product_v1.GetOrderReservationStatusResponse{Founded: true, Status: product_v1.GetOrderReservationStatusResponse_RESERVED.String()}, nil
+}
+
+func (f *fakeReservationAdapter) UnreserveOrders(ctx context.Context, req *product_v1.UnreserveOrdersRequest) (*product_v1.UnreserveOrdersResponse, error) {
+	f.reserved = false
+	f.unreserved++
+	return &product_v1.UnreserveOrdersResponse{Results: []*product_v1.UnreserveOrderResult{{OrderId: req.GetOrders()[0].GetOrderId(), Success: true}}}, nil
+}
+
+func TestOrderUsecase_CancelOrder_RecordsStockReleaseFailure(t *testing.T) {
+	repo := &fakeStockReleaseRepository{fakeCancellationRepository: &fakeCancellationRepository{
+		order: &domain.Order{
+			ID:      testOrderID,
+			OwnerID: testCustomerID,
+			ShopID:  testShopID,
+			Status:  domain.OrderStatusPENDING,
+			Items:   []domain.OrderItem{{ProductID: "product-1", Quantity: 2}},
+		},
+	}}
+	product := &fakeReservationAdapter{reserved: true, unavailable: true}
+	payment := &fakePaymentServiceAdapter{}
+	uc := usecase.NewOrderUsecase(repo, nil, product, nil, payment, nil, nil)
+
+	if _, _, err := uc.CancelOrder(context.Background(), testCustomerID, testOrderID, dto.CancelOrderRequest{Reason: "changed my mind"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Lỗi trả tồn kho không làm hỏng việc huỷ đơn nhưng được lưu lại để reconciler trả lại
+	if repo.failure == nil || repo.failure.Attempts != 1 {
+		t.Fatalf("failure = %+v, want a recorded stock release failure", repo.failure)
+	}
+
+	reconciler := usecase.NewOrderReconciler(repo, nil, nil, product, payment)
+
+	// product-service vẫn lỗi: tăng số lần thử, tồn kho vẫn đang bị giữ
+	reconciler.RetryStockReleases()
+	if repo.failure == nil || repo.failure.Attempts != 2 {
+		t.Fatalf("failure = %+v, want 2 attempts", repo.failure)
+	}
+
+	product.unavailable = false
+	reconciler.RetryStockReleases()
+
+	if repo.failure != nil {
+		t.Errorf("failure = %+v, want cleared after release", repo.failure)
+	}
+	if product.unreserved != 1 || product.reserved {
+		t.Errorf("unreserve calls = %d, reserved = %v, want stock released once", product.unreserved, product.reserved)
+	}
+
+	// Lần chạy sau không còn gì để trả
+	reconciler.RetryStockReleases()
+	if product.unreserved != 1 {
+		t.Errorf("unreserve calls = %d, want 1", product.unreserved)
+	}
+}
+
+func TestOrderReconciler_RetryStockReleases_AttemptsExhausted(t *testing.T) {
+	repo := &fakeStockReleaseRepository{
+		fakeCancellationRepository: &fakeCancellationRepository{
+			order: &domain.Order{ID: testOrderID, ShopID: testShopID, Status: domain.OrderStatusCANCELED},
+		},
+		failure: &domain.StockReleaseFailure{OrderID: testOrderID, LastError: "unavailable", Attempts: usecase.STOCK_RELEASE_MAX_ATTEMPTS},
+	}
+	product := &fakeReservationAdapter{reserved: true}
+
+	usecase.NewOrderReconciler(repo, nil, nil, product, nil).RetryStockReleases()
+
+	if product.unreserved != 0 || repo.failure == nil {
+		t.Errorf("unreserve calls = %d, failure = %+v, want order left for manual review", product.unreserved, repo.failure)
+	}
+}
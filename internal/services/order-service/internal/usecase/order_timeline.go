@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (u *orderUsecase) GetOrderTimeline(ctx context.Context, userId string, orderID string) (*domain.Order, []*domain.OrderStatusHistory, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetOrderTimeline.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, nil, err
+		}
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID != userId {
+		log.Printf("User %s is not allowed to view timeline of order %s", userId, orderID)
+		return nil, nil, apperror.NewForbidden("You are not allowed to view this order")
+	}
+
+	history, err := u.orderRepo.GetOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to get order timeline: %s", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int("order.timeline_size", len(history)))
+	return order, history, nil
+}
@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+// fakeTimelineRepository trả về lịch sử trạng thái cố định của đơn trong fakeOrderRepository.
+type fakeTimelineRepository struct {
+	*fakeOrderRepository
+	history        []*domain.OrderStatusHistory
+	historyQueried bool
+}
+
+func (f *fakeTimelineRepository) GetOrderStatusHistory(ctx context.Context, orderID string) ([]*domain.OrderStatusHistory, error) {
+	f.historyQueried = true
+	return f.history, nil
+}
+
+func TestOrderUsecase_GetOrderTimeline(t *testing.T) {
+	pending := domain.OrderStatusPENDING
+	history := []*domain.OrderStatusHistory{
+		{ID: 1, OrderID: testOrderID, NewStatus: domain.OrderStatusPENDING, Actor: domain.StatusActorOrderService},
+		{ID: 2, OrderID: testOrderID, OldStatus: &pending, NewStatus: domain.OrderStatusPENDINGPAYMENT, Actor: domain.StatusActorOrderService, Reason: "product stock reserved"},
+	}
+
+	testCases := []struct {
+		name         string
+		userID       string
+		orderID      string
+		expectedType apperror.ErrorType
+		expectError  bool
+	}{
+		{
+			name:    "Success - owner sees every transition",
+			userID:  testCustomerID,
+			orderID: testOrderID,
+		},
+		{
+			name:         "Order of another customer",
+			userID:       testSellerID,
+			orderID:      testOrderID,
+			expectedType: apperror.TypeForbidden,
+			expectError:  true,
+		},
+		{
+			name:         "Order not found",
+			userID:       testCustomerID,
+			orderID:      "order-9",
+			expectedType: apperror.TypeNotFound,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeTimelineRepository{
+				fakeOrderRepository: &fakeOrderRepository{order: &domain.Order{ID: testOrderID, OwnerID: testCustomerID, Status: domain.OrderStatusPENDINGPAYMENT}},
+				history:             history,
+			}
+			uc := usecase.NewOrderUsecase(repo, nil, nil, nil, nil, nil, nil)
+
+			order, timeline, err := uc.GetOrderTimeline(context.Background(), tc.userID, tc.orderID)
+
+			if tc.expectError {
+				if apperror.GetType(err) != tc.expectedType {
+					t.Fatalf("error = %v, want type %v", err, tc.expectedType)
+				}
+				if repo.historyQueried {
+					t.Error("status history was queried before the owner check")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.ID != testOrderID {
+				t.Errorf("order = %s, want %s", order.ID, testOrderID)
+			}
+			if len(timeline) != len(history) || timeline[1].OldStatus == nil || *timeline[1].OldStatus != domain.OrderStatusPENDING {
+				t.Errorf("timeline = %+v, want %+v", timeline, history)
+			}
+		})
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
@@ -18,6 +20,7 @@ import (
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
 	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
 	shop_v1 "github.com/toji-dev/go-shop/proto/gen/go/shop/v1"
+	user_v1 "github.com/toji-dev/go-shop/proto/gen/go/user/v1"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -25,6 +28,20 @@ import (
 
 type OrderUsecase interface {
 	CreateOrder(ctx context.Context, userId string, req dto.CreateOrderRequest) (*domain.Order, error)
+	Checkout(ctx context.Context, userId string, req dto.CheckoutRequest) (*domain.CheckoutResult, error)
+	Reorder(ctx context.Context, userId string, orderID string) (*domain.ReorderResult, error)
+	ListOrdersByOwner(ctx context.Context, userId string, query dto.ListOrdersQuery) (*domain.OrderPage, error)
+	GetOrderByID(ctx context.Context, userId string, orderID string) (*domain.Order, error)
+	CancelOrder(ctx context.Context, userId string, orderID string, req dto.CancelOrderRequest) (*domain.Order, *domain.OrderCancellation, error)
+	GetOrderTimeline(ctx context.Context, userId string, orderID string) (*domain.Order, []*domain.OrderStatusHistory, error)
+	ListShopOrders(ctx context.Context, userId string, shopID string, query dto.ListOrdersQuery) (*domain.OrderPage, error)
+	AcceptShopOrder(ctx context.Context, userId string, shopID string, orderID string) (*domain.Order, error)
+	RejectShopOrder(ctx context.Context, userId string, shopID string, orderID string, req dto.RejectOrderRequest) (*domain.Order, *domain.OrderCancellation, error)
+	ShipShopOrder(ctx context.Context, userId string, shopID string, orderID string) (*domain.Order, error)
+	PackShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string) (*domain.Order, error)
+	ShipShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string) (*domain.Order, error)
+	CancelShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string, req dto.CancelOrderItemRequest) (*domain.Order, *domain.OrderItemCancellation, error)
+	ExportShopOrders(ctx context.Context, userId string, shopID string, query dto.ExportOrdersQuery) (*ShopOrderExport, error)
 	HandleRefundSucceededEvent(ctx context.Context, key, value []byte) error // Deprecated: Use InboxEventUseCase instead
 }
 
@@ -33,13 +50,22 @@ type orderUsecase struct {
 	shopServiceAdapter    adapter.ShopServiceAdapter
 	productServiceAdapter adapter.ProductServiceAdapter
 	userAdapter           adapter.UserServiceAdapter
+	paymentAdapter        adapter.PaymentServiceAdapter
+	cartAdapter           adapter.CartServiceAdapter
+	shippingUsecase       ShippingUseCase
 }
 
-func NewOrderUsecase(orderRepo repository.OrderRepository, shopServiceAdapter adapter.ShopServiceAdapter, productServiceAdapter adapter.ProductServiceAdapter, userAdapter adapter.UserServiceAdapter) OrderUsecase {
-	return &orderUsecase{orderRepo: orderRepo, shopServiceAdapter: shopServiceAdapter, productServiceAdapter: productServiceAdapter, userAdapter: userAdapter}
+func NewOrderUsecase(orderRepo repository.OrderRepository, shopServiceAdapter adapter.ShopServiceAdapter, productServiceAdapter adapter.ProductServiceAdapter, userAdapter adapter.UserServiceAdapter, paymentAdapter adapter.PaymentServiceAdapter, cartAdapter adapter.CartServiceAdapter, shippingUsecase ShippingUseCase) OrderUsecase {
+	return &orderUsecase{orderRepo: orderRepo, shopServiceAdapter: shopServiceAdapter, productServiceAdapter: productServiceAdapter, userAdapter: userAdapter, paymentAdapter: paymentAdapter, cartAdapter: cartAdapter, shippingUsecase: shippingUsecase}
 }
 
 func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.CreateOrderRequest) (*domain.Order, error) {
+	return u.placeOrder(ctx, userId, req, nil)
+}
+
+// placeOrder tạo một đơn hàng cho một shop và giữ hàng bên product-service.
+// checkoutID khác nil khi đơn hàng được tạo từ checkout giỏ hàng.
+func (u *orderUsecase) placeOrder(ctx context.Context, userId string, req dto.CreateOrderRequest, checkoutID *string) (*domain.Order, error) {
 	tracer := otel.Tracer("order-service.usecase")
 	ctx, span := tracer.Start(ctx, "CreateOrder.UseCase")
 	defer span.End()
@@ -53,16 +79,14 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 	// --- STAGE 1: VALIDATION ---
 	// Validate shop, address, and product info before creating anything
 	_, validationSpan := tracer.Start(ctx, "ValidatePrerequisites")
-	if err := u.validatePrerequisites(ctx, &req); err != nil {
+	address, err := u.validatePrerequisites(ctx, userId, &req)
+	if err != nil {
 		validationSpan.SetStatus(codes.Error, err.Error()) // Ghi nhận lỗi vào span
 		validationSpan.End()
 		return nil, err
 	}
 	validationSpan.End()
 
-	log.Printf("Order request: %+v\n", req)
-	log.Printf("User ID: %d", len(req.Items))
-
 	// Validate products in order items
 	productIDs := make([]string, 0, len(req.Items))
 	quantityMap := make(map[string]int32)
@@ -85,60 +109,105 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 		productInfoSpan.End()
 		return nil, apperror.NewBadRequest("One or more products are invalid or unavailable", err)
 	}
+	// Đơn hàng chỉ chứa sản phẩm của shop đặt hàng, shop_id trong giỏ hàng có thể đã cũ
+	for _, p := range productsInfo.Products {
+		if p.ShopId != req.ShopID {
+			err := fmt.Errorf("product %s belongs to shop %s, not %s", p.Id, p.ShopId, req.ShopID)
+			productInfoSpan.SetStatus(codes.Error, err.Error())
+			productInfoSpan.End()
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Product %s does not belong to this shop", p.Id), err)
+		}
+	}
 	productInfoSpan.End()
 
 	// --- STAGE 2: CALCULATION & ORDER CREATION (order_status: PENDING) ---
 	_, calculationSpan := tracer.Start(ctx, "CalculateTotalsAndPromotions")
 	orderID := uuid.New().String()
-	totalAmount := 0.0
+	currency := productUnitPrice(productsInfo.Products[0]).Currency
+	totalAmount := money.Zero(currency)
 	orderItems := make([]domain.OrderItem, len(productsInfo.Products))
+	parcelItems := make([]domain.ShippingParcelItem, len(productsInfo.Products))
 
 	log.Printf("Product info retrieved with %d products", len(productsInfo.Products))
 
 	for i, p := range productsInfo.Products {
-		price := float64(p.Price)
-		totalAmount += price * float64(quantityMap[p.Id])
 		orderItems[i] = domain.OrderItem{
-			ProductID: p.Id,
-			Quantity:  int(quantityMap[p.Id]),
-			Price:     price,
+			ProductID:    p.Id,
+			ProductName:  p.Name,
+			ThumbnailURL: p.ThumbnailUrl,
+			Quantity:     int(quantityMap[p.Id]),
+			Price:        productUnitPrice(p),
+		}
+		totalAmount, err = totalAmount.Add(orderItems[i].LineTotal())
+		if err != nil {
+			calculationSpan.SetStatus(codes.Error, err.Error())
+			calculationSpan.End()
+			return nil, apperror.NewBadRequest("All products of an order must be priced in the same currency", err)
+		}
+		parcelItems[i] = domain.ShippingParcelItem{
+			ProductID:   p.Id,
+			WeightGrams: int(p.WeightGrams),
+			Quantity:    int(quantityMap[p.Id]),
 		}
 	}
 
 	finalPrice := totalAmount
 
-	log.Printf("Total amount calculated: %.2f", totalAmount)
+	log.Printf("Total amount calculated: %s", totalAmount)
 
 	// Validate Promotions
-	discountAmount := 0.0
+	discountAmount := money.Zero(currency)
 	if req.PromotionID != nil && *req.PromotionID != "" {
 		promotionReq := &shop_v1.CalculatePromotionRequest{
 			ShopId:        req.ShopID,
 			UserId:        userId,
 			PromotionCode: *req.PromotionID,
-			TotalAmount:   int32(totalAmount),
+			Subtotal:      totalAmount.ToProto(),
 		}
 
 		promotionRes, err := u.shopServiceAdapter.CalculatePromotion(ctx, promotionReq)
 		if err != nil {
 			log.Printf("Error calculating promotion: %v", err)
+			calculationSpan.SetStatus(codes.Error, err.Error())
+			calculationSpan.End()
 			return nil, apperror.NewInternal(fmt.Sprintf("Failed to calculate promotion: %s", err.Error()))
 		}
 
 		if !promotionRes.Eligible {
+			calculationSpan.End()
 			return nil, apperror.NewBadRequest("Invalid promotion code", errors.New("promotion code is not eligible"))
 		}
 
-		finalPrice -= float64(promotionRes.Discount)
-		discountAmount = float64(promotionRes.Discount)
+		discountAmount = promotionDiscount(promotionRes, currency).Min(totalAmount)
+		finalPrice = money.New(finalPrice.Amount-discountAmount.Amount, currency)
+	}
+
+	log.Printf("Final price after promotion: %s (Discount: %s)", finalPrice, discountAmount)
+
+	shippingQuote, err := u.shippingUsecase.CalculateShippingFee(ctx, req.ShopID, address, parcelItems)
+	if err != nil {
+		calculationSpan.SetStatus(codes.Error, err.Error())
+		calculationSpan.End()
+		return nil, err
+	}
+	// Phí vận chuyển tính theo currency của bảng phí, không quy đổi được sang currency của đơn
+	if !strings.EqualFold(shippingQuote.Currency, currency) {
+		err := fmt.Errorf("%w: shipping rates of shop %s are in %s, order is in %s", money.ErrCurrencyMismatch, req.ShopID, shippingQuote.Currency, currency)
+		calculationSpan.SetStatus(codes.Error, err.Error())
+		calculationSpan.End()
+		return nil, apperror.NewBadRequest("Shipping rates of this shop are not in the currency of the products", err)
 	}
+	shippingFee := money.FromMajor(shippingQuote.ShippingFee, currency)
+	finalPrice = money.New(finalPrice.Amount+shippingFee.Amount, currency)
 
-	log.Printf("Final price after promotion: %.2f (Discount: %.2f)", finalPrice, discountAmount)
+	log.Printf("Final price after shipping: %s (Shipping fee: %s)", finalPrice, shippingFee)
 
 	calculationSpan.SetAttributes(
-		attribute.Float64("order.total_amount", totalAmount),
-		attribute.Float64("order.discount_amount", discountAmount),
-		attribute.Float64("order.final_price", finalPrice),
+		attribute.String("order.currency", currency),
+		attribute.Int64("order.total_amount", totalAmount.Amount),
+		attribute.Int64("order.discount_amount", discountAmount.Amount),
+		attribute.Int64("order.shipping_fee", shippingFee.Amount),
+		attribute.Int64("order.final_price", finalPrice.Amount),
 	)
 	calculationSpan.End()
 
@@ -148,8 +217,10 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 		OwnerID:           userId,
 		ShopID:            req.ShopID,
 		ShippingAddressID: req.ShippingAddressID,
+		ShippingAddress:   toShippingAddressSnapshot(address),
 		PromotionCode:     req.PromotionID,
-		ShippingFee:       0,
+		CheckoutID:        checkoutID,
+		ShippingFee:       shippingFee,
 		DiscountAmount:    discountAmount,
 		TotalAmount:       totalAmount,
 		FinalPrice:        finalPrice,
@@ -187,7 +258,10 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 		reserveSpan.SetStatus(codes.Error, err.Error())
 		reserveSpan.End()
 		log.Printf("CRITICAL: ReserveProducts call failed for order %s. Marking as FAILED. Error: %v", orderID, err)
-		u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusFAILED)
+		u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusFAILED, domain.StatusChange{
+			Actor:  domain.StatusActorOrderService,
+			Reason: fmt.Sprintf("reserve products call failed: %v", err),
+		})
 		return nil, apperror.NewDependencyFailure(fmt.Sprintf("Failed to reserve products: %s", err.Error()))
 	}
 	reserveSpan.End()
@@ -195,7 +269,10 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 	// --- STAGE 4: FINALIZE ORDER (SAGA - COMMIT/ROLLBACK) ---
 	if !reserveResp.Success {
 		log.Printf("Failed to reserve products for order %s. Marking as FAILED.", orderID)
-		u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusFAILED)
+		u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusFAILED, domain.StatusChange{
+			Actor:  domain.StatusActorOrderService,
+			Reason: "product stock reservation rejected",
+		})
 
 		var errorDetails []string
 		for _, status := range reserveResp.ProductStatuses {
@@ -208,7 +285,10 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 		return nil, apperror.NewConflict("Failed to reserve all products", strings.Join(errorDetails, ", "))
 	}
 
-	finalOrder, err := u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusPENDINGPAYMENT)
+	finalOrder, err := u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatusPENDINGPAYMENT, domain.StatusChange{
+		Actor:  domain.StatusActorOrderService,
+		Reason: "product stock reserved",
+	})
 
 	if err != nil {
 		log.Printf("CRITICAL: SAGA failure. Product stock reserved for order %s but failed to update order status. Manual intervention required. Error: %v", orderID, err)
@@ -220,6 +300,136 @@ func (u *orderUsecase) CreateOrder(ctx context.Context, userId string, req dto.C
 	return finalOrder, nil
 }
 
+const (
+	DEFAULT_ORDER_PAGE_SIZE = 20
+	MAX_ORDER_PAGE_SIZE     = 100
+)
+
+func (u *orderUsecase) GetOrderByID(ctx context.Context, userId string, orderID string) (*domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetOrderByID.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID != userId {
+		log.Printf("User %s is not allowed to view order %s", userId, orderID)
+		return nil, apperror.NewForbidden("You are not allowed to view this order")
+	}
+
+	return order, nil
+}
+
+func (u *orderUsecase) ListOrdersByOwner(ctx context.Context, userId string, query dto.ListOrdersQuery) (*domain.OrderPage, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ListOrdersByOwner.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.status_filter", query.Status),
+	)
+
+	filter, err := buildOrderListFilter(userId, query)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	page, err := u.orderRepo.ListOrdersByOwner(ctx, *filter)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list orders: %s", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int("order.result_count", len(page.Orders)))
+	return page, nil
+}
+
+func buildOrderListFilter(userId string, query dto.ListOrdersQuery) (*domain.OrderListFilter, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	filter, err := parseOrderListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	filter.OwnerID = userId
+	return filter, nil
+}
+
+// parseOrderListQuery chuyển query string thành điều kiện lọc, phần chủ sở hữu (khách hàng hoặc shop) do caller gán.
+func parseOrderListQuery(query dto.ListOrdersQuery) (*domain.OrderListFilter, error) {
+	filter := &domain.OrderListFilter{
+		Limit: query.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DEFAULT_ORDER_PAGE_SIZE
+	}
+	if filter.Limit > MAX_ORDER_PAGE_SIZE {
+		filter.Limit = MAX_ORDER_PAGE_SIZE
+	}
+
+	if query.Status != "" {
+		status := domain.OrderStatus(strings.ToUpper(query.Status))
+		if !status.IsValid() {
+			return nil, apperror.NewBadRequest("Invalid order status filter", fmt.Errorf("unknown status %q", query.Status))
+		}
+		filter.Status = &status
+	}
+
+	if query.From != "" {
+		from, err := parseDateParam(query.From)
+		if err != nil {
+			return nil, apperror.NewBadRequest("Invalid 'from' date", err)
+		}
+		filter.CreatedFrom = &from
+	}
+
+	if query.To != "" {
+		to, err := parseDateParam(query.To)
+		if err != nil {
+			return nil, apperror.NewBadRequest("Invalid 'to' date", err)
+		}
+		filter.CreatedTo = &to
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, apperror.NewBadRequest("Invalid date range", errors.New("'from' must be before 'to'"))
+	}
+
+	if query.Cursor != "" {
+		cursor, err := domain.DecodeOrderCursor(query.Cursor)
+		if err != nil {
+			return nil, apperror.NewBadRequest("Invalid cursor", err)
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
+
+// parseDateParam chấp nhận RFC3339 hoặc YYYY-MM-DD (tính từ 00:00 UTC).
+func parseDateParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
 func (u *orderUsecase) HandleRefundSucceededEvent(ctx context.Context, key, value []byte) error {
 	var payload payload.RefundSucceededPayload
 	if err := json.Unmarshal(value, &payload); err != nil {
@@ -235,7 +445,15 @@ func (u *orderUsecase) HandleRefundSucceededEvent(ctx context.Context, key, valu
 	log.Printf("Received RefundSucceeded event for OrderID: %s", payload.OrderID)
 
 	// Cập nhật trạng thái đơn hàng thành REFUNDED
-	_, err := u.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatus(domain.OrderStatusREFUNDED))
+	_, err := u.orderRepo.UpdateOrderStatus(ctx, payload.OrderID, sqlc.OrderStatus(domain.OrderStatusREFUNDED), domain.StatusChange{
+		Actor:  domain.StatusActorKafka,
+		Reason: "refund succeeded event",
+	})
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		// Retry không thay đổi được kết quả nên bỏ qua message
+		log.Printf("ERROR: [POISON PILL] Không thể chuyển OrderID %s sang REFUNDED. Bỏ qua message. Lỗi: %v", payload.OrderID, err)
+		return nil
+	}
 	if err != nil {
 		log.Printf("ERROR: Failed to update order status to REFUNDED for OrderID %s: %v", payload.OrderID, err)
 		return fmt.Errorf("failed to update order status: %w", err)
@@ -245,45 +463,81 @@ func (u *orderUsecase) HandleRefundSucceededEvent(ctx context.Context, key, valu
 	return nil
 }
 
-func (u *orderUsecase) validatePrerequisites(ctx context.Context, req *dto.CreateOrderRequest) error {
+// validatePrerequisites kiểm tra shop, địa chỉ và danh sách sản phẩm, trả về địa chỉ giao hàng để lưu snapshot.
+// Địa chỉ phải thuộc về người đặt hàng, địa chỉ của người khác được coi như không tồn tại.
+func (u *orderUsecase) validatePrerequisites(ctx context.Context, userId string, req *dto.CreateOrderRequest) (*user_v1.Address, error) {
 	isShopExists, err := u.shopServiceAdapter.CheckShopExists(ctx, req.ShopID)
 	if err != nil {
-		return apperror.NewInternal(fmt.Sprintf("Failed to check shop existence: %s", err.Error()))
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to check shop existence: %s", err.Error()))
 	}
 
 	if !isShopExists {
-		return apperror.NewNotFound("Shop", req.ShopID)
+		return nil, apperror.NewNotFound("Shop", req.ShopID)
 	}
 
 	if req.ShippingAddressID == "" {
 		log.Printf("Order creation failed: Shipping address ID is required")
-		return apperror.NewBadRequest("Address cannot be empty", errors.New("shipping_address_id is required"))
+		return nil, apperror.NewBadRequest("Address cannot be empty", errors.New("shipping_address_id is required"))
 	}
 
 	address, err := u.userAdapter.GetAddressById(ctx, req.ShippingAddressID)
 	if err != nil {
 		log.Printf("Error fetching address: %v", err)
-		return apperror.NewInternal(fmt.Sprintf("Failed to get address: %s", err.Error()))
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get address: %s", err.Error()))
 	}
 
-	if address == nil {
-		log.Printf("Shipping address with ID %s not found or deleted with data: %+v", req.ShippingAddressID, address)
-		return apperror.NewNotFound("Shipping address", req.ShippingAddressID)
+	if address == nil || address.GetUserId() != userId {
+		log.Printf("Shipping address with ID %s not found or deleted for user %s", req.ShippingAddressID, userId)
+		return nil, apperror.NewNotFound("Shipping address", req.ShippingAddressID)
 	}
 
 	if len(req.Items) == 0 {
 		log.Printf("Order creation failed: No items provided")
-		return apperror.NewBadRequest("Order must contain at least one item", nil)
+		return nil, apperror.NewBadRequest("Order must contain at least one item", nil)
 	}
 
 	for _, item := range req.Items {
 		if item.ProductID == "" {
-			return apperror.NewBadRequest("Product ID cannot be empty", errors.New("product_id is required"))
+			return nil, apperror.NewBadRequest("Product ID cannot be empty", errors.New("product_id is required"))
 		}
 		if item.Quantity <= 0 {
-			return apperror.NewBadRequest(fmt.Sprintf("Quantity for product %s must be positive", item.ProductID), nil)
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Quantity for product %s must be positive", item.ProductID), nil)
 		}
 	}
 
-	return nil
+	return address, nil
+}
+
+// productUnitPrice đọc đơn giá chính xác của sản phẩm, product-service cũ chỉ gửi price (đã bị làm tròn xuống số nguyên).
+func productUnitPrice(p *product_v1.ProductInfo) money.Money {
+	if price, ok := money.FromProto(p.GetUnitPrice()); ok {
+		return price
+	}
+	return money.FromMajor(float64(p.GetPrice()), p.GetCurrency())
+}
+
+// promotionDiscount đọc số tiền giảm theo currency của đơn, shop-service cũ chỉ gửi discount dạng số thực.
+func promotionDiscount(res *shop_v1.CalculatePromotionResponse, currency string) money.Money {
+	if discount, ok := money.FromProto(res.GetDiscountAmount()); ok {
+		return discount
+	}
+	return money.FromMajor(float64(res.GetDiscount()), currency)
+}
+
+func toShippingAddressSnapshot(address *user_v1.Address) *domain.ShippingAddress {
+	if address == nil {
+		return nil
+	}
+
+	return &domain.ShippingAddress{
+		RecipientName:  address.GetRecipientName(),
+		RecipientPhone: address.GetRecipientPhone(),
+		Street:         address.GetStreet(),
+		Ward:           address.GetWard(),
+		District:       address.GetDistrict(),
+		City:           address.GetCity(),
+		Country:        address.GetCountry(),
+		Lat:            address.GetLat(),
+		Long:           address.GetLong(),
+	}
 }
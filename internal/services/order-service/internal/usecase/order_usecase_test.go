@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	user_v1 "github.com/toji-dev/go-shop/proto/gen/go/user/v1"
+)
+
+const testAddressID = "3e9b8f1a-6c2d-4b7e-8a1f-2d3c4b5a6e7f"
+
+func (f *fakeShopServiceAdapter) CheckShopExists(ctx context.Context, shopID string) (bool, error) {
+	return shopID == testShopID || shopID == testOtherShopID, nil
+}
+
+type fakeUserServiceAdapter struct {
+	adapter.UserServiceAdapter
+}
+
+func (f *fakeUserServiceAdapter) GetAddressById(ctx context.Context, addressID string) (*user_v1.Address, error) {
+	if addressID != testAddressID {
+		return nil, nil
+	}
+	return &user_v1.Address{Id: addressID, UserId: testCustomerID}, nil
+}
+
+// fakeProductCatalog trả về thông tin các sản phẩm có trong products, thiếu sản phẩm nào thì response không hợp lệ.
+type fakeProductCatalog struct {
+	adapter.ProductServiceAdapter
+	products map[string]*product_v1.ProductInfo
+}
+
+func (f *fakeProductCatalog) GetProductsInfo(ctx context.Context, req *product_v1.GetProductsInfoRequest) (*product_v1.GetProductsInfoResponse, error) {
+	res := &product_v1.GetProductsInfoResponse{Valid: true}
+	for _, id := range req.GetProductIds() {
+		product, ok := f.products[id]
+		if !ok {
+			return &product_v1.GetProductsInfoResponse{Valid: false}, nil
+		}
+		res.Products = append(res.Products, product)
+	}
+	return res, nil
+}
+
+type fakeShippingUseCase struct {
+	usecase.ShippingUseCase
+	quote *domain.ShippingQuote
+}
+
+func (f *fakeShippingUseCase) CalculateShippingFee(ctx context.Context, shopID string, destination *user_v1.Address, items []domain.ShippingParcelItem) (*domain.ShippingQuote, error) {
+	quote := *f.quote
+	return &quote, nil
+}
+
+func newTestProduct(id string, price money.Money) *product_v1.ProductInfo {
+	return &product_v1.ProductInfo{
+		Id:        id,
+		ShopId:    testShopID,
+		Name:      "Product " + id,
+		Currency:  price.Currency,
+		UnitPrice: price.ToProto(),
+	}
+}
+
+func TestOrderUsecase_CreateOrder_RejectsShippingCurrencyMismatch(t *testing.T) {
+	catalog := &fakeProductCatalog{products: map[string]*product_v1.ProductInfo{
+		"product-1": newTestProduct("product-1", money.New(250000, "VND")),
+	}}
+	// Bảng phí mặc định tính theo USD, không được cộng thẳng 1.5 USD thành 2 VND vào đơn VND
+	shipping := &fakeShippingUseCase{quote: &domain.ShippingQuote{ShopID: testShopID, Currency: "USD", ZoneFee: 1.5, ShippingFee: 1.5}}
+	uc := usecase.NewOrderUsecase(nil, &fakeShopServiceAdapter{}, catalog, &fakeUserServiceAdapter{}, nil, nil, shipping)
+
+	_, err := uc.CreateOrder(context.Background(), testCustomerID, dto.CreateOrderRequest{
+		ShopID:            testShopID,
+		ShippingAddressID: testAddressID,
+		Items:             []dto.CreateOrderItemRequest{{ProductID: "product-1", Quantity: 1}},
+	})
+
+	if apperror.GetType(err) != apperror.TypeValidation {
+		t.Errorf("error = %v, want bad request", err)
+	}
+}
+
+// fakeOrderListRepository ghi lại điều kiện lọc mà usecase gửi xuống repository.
+type fakeOrderListRepository struct {
+	repository.OrderRepository
+	filter *domain.OrderListFilter
+}
+
+func (f *fakeOrderListRepository) ListOrdersByOwner(ctx context.Context, filter domain.OrderListFilter) (*domain.OrderPage, error) {
+	f.filter = &filter
+	return &domain.OrderPage{}, nil
+}
+
+func TestOrderUsecase_ListOrdersByOwner(t *testing.T) {
+	cursor := domain.OrderCursor{CreatedAt: time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC), ID: "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"}
+	processing := domain.OrderStatusPROCESSING
+
+	testCases := []struct {
+		name           string
+		userID         string
+		query          dto.ListOrdersQuery
+		expectedFilter domain.OrderListFilter
+		expectedType   apperror.ErrorType
+		expectError    bool
+	}{
+		{
+			name:           "Defaults to the first page",
+			userID:         testCustomerID,
+			expectedFilter: domain.OrderListFilter{OwnerID: testCustomerID, Limit: usecase.DEFAULT_ORDER_PAGE_SIZE},
+		},
+		{
+			name:   "Status, date range and cursor",
+			userID: testCustomerID,
+			query: dto.ListOrdersQuery{
+				Status: "processing",
+				From:   "2025-01-01",
+				To:     "2025-02-01T12:00:00+07:00",
+				Cursor: cursor.Encode(),
+				Limit:  10,
+			},
+			expectedFilter: domain.OrderListFilter{
+				OwnerID:     testCustomerID,
+				Status:      &processing,
+				CreatedFrom: timePtr(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				CreatedTo:   timePtr(time.Date(2025, 2, 1, 5, 0, 0, 0, time.UTC)),
+				Cursor:      &cursor,
+				Limit:       10,
+			},
+		},
+		{
+			name:           "Limit is capped",
+			userID:         testCustomerID,
+			query:          dto.ListOrdersQuery{Limit: 1000},
+			expectedFilter: domain.OrderListFilter{OwnerID: testCustomerID, Limit: usecase.MAX_ORDER_PAGE_SIZE},
+		},
+		{
+			name:         "Unknown status",
+			userID:       testCustomerID,
+			query:        dto.ListOrdersQuery{Status: "LOST"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid date",
+			userID:       testCustomerID,
+			query:        dto.ListOrdersQuery{From: "01/02/2025"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "From not before to",
+			userID:       testCustomerID,
+			query:        dto.ListOrdersQuery{From: "2025-02-01", To: "2025-02-01"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid cursor",
+			userID:       testCustomerID,
+			query:        dto.ListOrdersQuery{Cursor: "not-a-cursor"},
+			expectedType: apperror.TypeValidation,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid user ID",
+			userID:       "user-1",
+			expectedType: apperror.TypeUnauthorized,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeOrderListRepository{}
+			uc := usecase.NewOrderUsecase(repo, nil, nil, nil, nil, nil, nil)
+
+			_, err := uc.ListOrdersByOwner(context.Background(), tc.userID, tc.query)
+
+			if tc.expectError {
+				if apperror.GetType(err) != tc.expectedType {
+					t.Fatalf("error = %v, want type %v", err, tc.expectedType)
+				}
+				if repo.filter != nil {
+					t.Errorf("repository was queried with %+v", repo.filter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(normalizeFilterTimes(*repo.filter), normalizeFilterTimes(tc.expectedFilter)) {
+				t.Errorf("filter = %+v, want %+v", *repo.filter, tc.expectedFilter)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// normalizeFilterTimes đưa các mốc thời gian về UTC để so sánh bằng reflect.DeepEqual.
+func normalizeFilterTimes(filter domain.OrderListFilter) domain.OrderListFilter {
+	if filter.CreatedFrom != nil {
+		filter.CreatedFrom = timePtr(filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		filter.CreatedTo = timePtr(filter.CreatedTo.UTC())
+	}
+	if filter.Cursor != nil {
+		cursor := *filter.Cursor
+		cursor.CreatedAt = cursor.CreatedAt.UTC()
+		filter.Cursor = &cursor
+	}
+	return filter
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PaymentWindowUseCase quản lý thời gian chờ thanh toán của từng shop, dùng bởi job huỷ đơn hết hạn thanh toán.
+type PaymentWindowUseCase interface {
+	GetShopPaymentWindow(ctx context.Context, userId string, shopID string) (*domain.ShopPaymentWindow, error)
+	UpdateShopPaymentWindow(ctx context.Context, userId string, shopID string, req dto.UpdatePaymentWindowRequest) (*domain.ShopPaymentWindow, error)
+}
+
+type paymentWindowUseCase struct {
+	paymentWindowRepo  repository.PaymentWindowRepository
+	shopServiceAdapter adapter.ShopServiceAdapter
+	defaultWindow      time.Duration
+}
+
+func NewPaymentWindowUseCase(
+	paymentWindowRepo repository.PaymentWindowRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+	defaultWindow time.Duration,
+) PaymentWindowUseCase {
+	return &paymentWindowUseCase{
+		paymentWindowRepo:  paymentWindowRepo,
+		shopServiceAdapter: shopServiceAdapter,
+		defaultWindow:      defaultWindow,
+	}
+}
+
+func (u *paymentWindowUseCase) GetShopPaymentWindow(ctx context.Context, userId string, shopID string) (*domain.ShopPaymentWindow, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetShopPaymentWindow.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+	)
+
+	if err := u.checkShopOwnership(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	window, err := u.paymentWindowRepo.GetByShopID(ctx, shopID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get payment window: %s", err.Error()))
+	}
+
+	if window == nil {
+		return &domain.ShopPaymentWindow{
+			ShopID:    shopID,
+			Window:    u.defaultWindow,
+			IsDefault: true,
+		}, nil
+	}
+
+	return window, nil
+}
+
+func (u *paymentWindowUseCase) UpdateShopPaymentWindow(ctx context.Context, userId string, shopID string, req dto.UpdatePaymentWindowRequest) (*domain.ShopPaymentWindow, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "UpdateShopPaymentWindow.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.Int("payment_window.minutes", req.WindowMinutes),
+	)
+
+	if err := u.checkShopOwnership(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	window, err := u.paymentWindowRepo.Upsert(ctx, &domain.ShopPaymentWindow{
+		ShopID: shopID,
+		Window: time.Duration(req.WindowMinutes) * time.Minute,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to save payment window: %s", err.Error()))
+	}
+
+	return window, nil
+}
+
+// checkShopOwnership chỉ cho chủ shop xem và sửa thời gian chờ thanh toán của shop.
+func (u *paymentWindowUseCase) checkShopOwnership(ctx context.Context, userId string, shopID string) error {
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, shopID, userId)
+	if err != nil {
+		return apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+	if !isOwner {
+		return apperror.NewForbidden("You are not allowed to manage payment window of this shop")
+	}
+	return nil
+}
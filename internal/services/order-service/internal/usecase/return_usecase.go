@@ -0,0 +1,521 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const defaultShopReturnsLimit = 20
+
+// ReturnUseCase điều phối việc trả hàng sau khi đơn đã giao: khách tạo yêu cầu trong thời hạn cho phép,
+// người bán duyệt / từ chối, và khi người bán nhận lại hàng thì nhập lại kho ở product-service và
+// yêu cầu payment-service hoàn tiền một phần cho các sản phẩm được trả.
+type ReturnUseCase interface {
+	RequestReturn(ctx context.Context, userId string, orderID string, req dto.CreateReturnRequest) (*domain.OrderReturn, error)
+	ListOrderReturns(ctx context.Context, userId string, orderID string) ([]*domain.OrderReturn, error)
+	GetOrderReturn(ctx context.Context, userId string, orderID string, returnID string) (*domain.OrderReturn, error)
+
+	ListShopReturns(ctx context.Context, userId string, shopID string, query dto.ListReturnsQuery) ([]*domain.OrderReturn, error)
+	ApproveReturn(ctx context.Context, userId string, shopID string, returnID string) (*domain.OrderReturn, error)
+	RejectReturn(ctx context.Context, userId string, shopID string, returnID string, req dto.RejectReturnRequest) (*domain.OrderReturn, error)
+	ReceiveReturn(ctx context.Context, userId string, shopID string, returnID string) (*domain.OrderReturn, error)
+}
+
+type returnUseCase struct {
+	returnRepo            repository.ReturnRepository
+	orderRepo             repository.OrderRepository
+	shopServiceAdapter    adapter.ShopServiceAdapter
+	productServiceAdapter adapter.ProductServiceAdapter
+	paymentAdapter        adapter.PaymentServiceAdapter
+	returnWindow          time.Duration
+}
+
+func NewReturnUseCase(
+	returnRepo repository.ReturnRepository,
+	orderRepo repository.OrderRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+	productServiceAdapter adapter.ProductServiceAdapter,
+	paymentAdapter adapter.PaymentServiceAdapter,
+	returnWindow time.Duration,
+) ReturnUseCase {
+	return &returnUseCase{
+		returnRepo:            returnRepo,
+		orderRepo:             orderRepo,
+		shopServiceAdapter:    shopServiceAdapter,
+		productServiceAdapter: productServiceAdapter,
+		paymentAdapter:        paymentAdapter,
+		returnWindow:          returnWindow,
+	}
+}
+
+// RequestReturn tạo yêu cầu trả hàng cho đơn DELIVERED của khách. Giá sản phẩm được lấy từ snapshot của đơn hàng,
+// số tiền hoàn được tính ngay lúc tạo để người bán thấy trước khi duyệt.
+func (u *returnUseCase) RequestReturn(ctx context.Context, userId string, orderID string, req dto.CreateReturnRequest) (*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "RequestReturn.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+		attribute.Int("return.item_count", len(req.Items)),
+	)
+
+	order, err := u.getCustomerOrder(ctx, userId, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if order.Status != domain.OrderStatusDELIVERED {
+		span.SetStatus(codes.Error, "order is not delivered")
+		return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Only delivered orders can be returned, order is %s", order.Status), apperror.TypeConflict)
+	}
+
+	deliveredAt, err := u.getDeliveredAt(ctx, order)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if u.returnWindow > 0 && time.Since(deliveredAt) > u.returnWindow {
+		span.SetStatus(codes.Error, "return window expired")
+		return nil, apperror.New(apperror.CodeConflict, "The return window for this order has expired", apperror.TypeConflict)
+	}
+
+	orderItems := make(map[string]domain.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	returnItems := make([]domain.OrderReturnItem, 0, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	for _, itemReq := range req.Items {
+		orderItem, ok := orderItems[itemReq.OrderItemID]
+		if !ok {
+			span.SetStatus(codes.Error, "order item not found")
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Order item %s does not belong to this order", itemReq.OrderItemID), nil)
+		}
+		if seen[itemReq.OrderItemID] {
+			span.SetStatus(codes.Error, "duplicate order item")
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Order item %s is listed more than once", itemReq.OrderItemID), nil)
+		}
+		seen[itemReq.OrderItemID] = true
+
+		returnItems = append(returnItems, domain.OrderReturnItem{
+			OrderItemID: orderItem.ID,
+			ProductID:   orderItem.ProductID,
+			Quantity:    itemReq.Quantity,
+			UnitPrice:   orderItem.Price,
+		})
+	}
+
+	orderReturn, err := u.returnRepo.CreateReturn(ctx, order, &domain.OrderReturn{
+		OrderID:      order.ID,
+		OwnerID:      order.OwnerID,
+		ShopID:       order.ShopID,
+		Reason:       strings.TrimSpace(req.Reason),
+		RefundAmount: domain.CalculateReturnRefundAmount(order, returnItems),
+		Items:        returnItems,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if errors.Is(err, domain.ErrReturnQuantityExceeded) {
+			return nil, apperror.New(apperror.CodeConflict, "Return quantity exceeds the quantity that can still be returned", apperror.TypeConflict)
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to create return: %s", err.Error()))
+	}
+
+	span.SetAttributes(
+		attribute.String("return.id", orderReturn.ID),
+		attribute.Int64("return.refund_amount", orderReturn.RefundAmount.Amount),
+		attribute.String("return.currency", orderReturn.RefundAmount.Currency),
+	)
+	span.AddEvent("Return requested by customer")
+	return orderReturn, nil
+}
+
+func (u *returnUseCase) ListOrderReturns(ctx context.Context, userId string, orderID string) ([]*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ListOrderReturns.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+	)
+
+	if _, err := u.getCustomerOrder(ctx, userId, orderID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	returns, err := u.returnRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list returns: %s", err.Error()))
+	}
+	return returns, nil
+}
+
+func (u *returnUseCase) GetOrderReturn(ctx context.Context, userId string, orderID string, returnID string) (*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetOrderReturn.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("order.id", orderID),
+		attribute.String("return.id", returnID),
+	)
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	orderReturn, err := u.getReturn(ctx, returnID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// Không lộ yêu cầu trả hàng của khách khác
+	if orderReturn.OrderID != orderID || orderReturn.OwnerID != userId {
+		span.SetStatus(codes.Error, "return not found")
+		return nil, apperror.NewNotFound("Order return", returnID)
+	}
+	return orderReturn, nil
+}
+
+func (u *returnUseCase) ListShopReturns(ctx context.Context, userId string, shopID string, query dto.ListReturnsQuery) ([]*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ListShopReturns.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("return.status_filter", query.Status),
+	)
+
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	var status *domain.ReturnStatus
+	if query.Status != "" {
+		s := domain.ReturnStatus(query.Status)
+		if !s.IsValid() {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("Invalid return status: %s", query.Status), nil)
+		}
+		status = &s
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultShopReturnsLimit
+	}
+
+	returns, err := u.returnRepo.ListByShop(ctx, shopID, status, limit)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list shop returns: %s", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int("return.result_count", len(returns)))
+	return returns, nil
+}
+
+// ApproveReturn: người bán đồng ý để khách gửi trả hàng (REQUESTED -> APPROVED).
+func (u *returnUseCase) ApproveReturn(ctx context.Context, userId string, shopID string, returnID string) (*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ApproveReturn.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("return.id", returnID),
+	)
+
+	orderReturn, err := u.getShopReturn(ctx, userId, shopID, returnID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// Idempotency: duyệt lại một yêu cầu đã duyệt sẽ trả về yêu cầu hiện tại
+	if orderReturn.Status == domain.ReturnStatusApproved {
+		return orderReturn, nil
+	}
+
+	approved, err := u.returnRepo.Approve(ctx, returnID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, toReturnError(err, orderReturn)
+	}
+
+	span.AddEvent("Return approved by seller")
+	return approved, nil
+}
+
+// RejectReturn: người bán từ chối yêu cầu trả hàng (REQUESTED -> REJECTED) kèm ghi chú cho khách.
+func (u *returnUseCase) RejectReturn(ctx context.Context, userId string, shopID string, returnID string, req dto.RejectReturnRequest) (*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "RejectReturn.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("return.id", returnID),
+	)
+
+	orderReturn, err := u.getShopReturn(ctx, userId, shopID, returnID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if orderReturn.Status == domain.ReturnStatusRejected {
+		return orderReturn, nil
+	}
+
+	note := strings.TrimSpace(req.Note)
+	rejected, err := u.returnRepo.Reject(ctx, returnID, &note)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, toReturnError(err, orderReturn)
+	}
+
+	span.AddEvent("Return rejected by seller")
+	return rejected, nil
+}
+
+// ReceiveReturn: người bán xác nhận đã nhận lại hàng (APPROVED -> RECEIVED), sau đó nhập lại kho và yêu cầu
+// hoàn tiền một phần. Hai bước sau được ghi nhận riêng nên gọi lại endpoint này sẽ chỉ thực hiện các bước
+// còn thiếu; lỗi ở các bước này chỉ được log lại vì việc nhận hàng đã thành công.
+func (u *returnUseCase) ReceiveReturn(ctx context.Context, userId string, shopID string, returnID string) (*domain.OrderReturn, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ReceiveReturn.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("return.id", returnID),
+	)
+
+	orderReturn, err := u.getShopReturn(ctx, userId, shopID, returnID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	switch orderReturn.Status {
+	case domain.ReturnStatusApproved:
+		received, err := u.returnRepo.MarkReceived(ctx, returnID)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, toReturnError(err, orderReturn)
+		}
+		orderReturn = received
+		span.AddEvent("Return received by seller")
+	case domain.ReturnStatusReceived, domain.ReturnStatusRefunded:
+		// Đã nhận trước đó, chỉ thực hiện lại các bước còn thiếu
+	default:
+		span.SetStatus(codes.Error, "return is not approved")
+		return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Return cannot be received in status %s", orderReturn.Status), apperror.TypeConflict)
+	}
+
+	if !orderReturn.Restocked {
+		if restocked, err := u.restockReturnedItems(ctx, orderReturn); err != nil {
+			log.Printf("CRITICAL: Return %s received but restock failed. Manual intervention required. Error: %v", orderReturn.ID, err)
+			span.AddEvent("Restock failed")
+		} else {
+			orderReturn = restocked
+		}
+	}
+
+	if orderReturn.RefundID == nil && orderReturn.Status == domain.ReturnStatusReceived {
+		if refunded, err := u.requestReturnRefund(ctx, orderReturn); err != nil {
+			log.Printf("CRITICAL: Return %s received but refund request failed. Manual intervention required. Error: %v", orderReturn.ID, err)
+			span.AddEvent("Refund request failed")
+		} else if refunded != nil {
+			orderReturn = refunded
+		}
+	}
+
+	return orderReturn, nil
+}
+
+// restockReturnedItems nhập lại kho các sản phẩm được trả. Reference của yêu cầu trả hàng giúp product-service
+// bỏ qua các lần gọi lặp lại.
+func (u *returnUseCase) restockReturnedItems(ctx context.Context, orderReturn *domain.OrderReturn) (*domain.OrderReturn, error) {
+	products := make([]*product_v1.RestockProduct, 0, len(orderReturn.Items))
+	for _, item := range orderReturn.Items {
+		products = append(products, &product_v1.RestockProduct{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	resp, err := u.productServiceAdapter.RestockProducts(ctx, &product_v1.RestockProductsRequest{
+		ReferenceId: domain.ReturnReferenceID(orderReturn.ID),
+		OrderId:     orderReturn.OrderID,
+		ShopId:      orderReturn.ShopID,
+		Products:    products,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restock items of return %s: %w", orderReturn.ID, err)
+	}
+	if !resp.GetSuccess() {
+		return nil, fmt.Errorf("product service could not restock items of return %s: %s", orderReturn.ID, resp.GetMessage())
+	}
+
+	log.Printf("Restocked items of return %s (already processed: %t)", orderReturn.ID, resp.GetAlreadyProcessed())
+	return u.returnRepo.MarkRestocked(ctx, orderReturn.ID)
+}
+
+// requestReturnRefund yêu cầu payment-service hoàn số tiền của các sản phẩm được trả.
+// Trả về nil, nil khi đơn hàng không có khoản thanh toán nào đã thành công.
+func (u *returnUseCase) requestReturnRefund(ctx context.Context, orderReturn *domain.OrderReturn) (*domain.OrderReturn, error) {
+	paymentResp, err := u.paymentAdapter.GetPaymentByOrder(ctx, orderReturn.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment of order %s: %w", orderReturn.OrderID, err)
+	}
+
+	if !paymentResp.GetExists() || paymentResp.GetPayment().GetStatus() != payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS {
+		log.Printf("Order %s has no successful payment. Skipping refund of return %s.", orderReturn.OrderID, orderReturn.ID)
+		return nil, nil
+	}
+
+	refundResp, err := u.paymentAdapter.RequestPartialRefund(
+		ctx,
+		orderReturn.OrderID,
+		domain.ReturnReferenceID(orderReturn.ID),
+		orderReturn.RefundAmount,
+		fmt.Sprintf("return %s: %s", orderReturn.ID, orderReturn.Reason),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request refund for return %s: %w", orderReturn.ID, err)
+	}
+
+	if !refundResp.GetAccepted() {
+		return nil, fmt.Errorf("payment service rejected refund for return %s: %s", orderReturn.ID, refundResp.GetMessage())
+	}
+
+	log.Printf("Refund %s of %s requested for return %s", refundResp.GetRefundId(), orderReturn.RefundAmount, orderReturn.ID)
+	return u.returnRepo.SetRefundID(ctx, orderReturn.ID, refundResp.GetRefundId())
+}
+
+// getDeliveredAt lấy thời điểm đơn chuyển sang DELIVERED gần nhất từ lịch sử trạng thái,
+// dùng updated_at của đơn khi lịch sử không có (đơn cũ trước khi có lịch sử trạng thái).
+func (u *returnUseCase) getDeliveredAt(ctx context.Context, order *domain.Order) (time.Time, error) {
+	history, err := u.orderRepo.GetOrderStatusHistory(ctx, order.ID)
+	if err != nil {
+		return time.Time{}, apperror.NewInternal(fmt.Sprintf("Failed to get order status history: %s", err.Error()))
+	}
+
+	deliveredAt := order.UpdatedAt
+	for _, entry := range history {
+		if entry.NewStatus == domain.OrderStatusDELIVERED {
+			deliveredAt = entry.CreatedAt
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339, deliveredAt)
+	if err != nil {
+		return time.Time{}, apperror.NewInternal(fmt.Sprintf("Failed to parse delivered time of order %s: %s", order.ID, err.Error()))
+	}
+	return t, nil
+}
+
+// getCustomerOrder trả về NotFound nếu đơn hàng không thuộc khách để không lộ đơn của người khác.
+func (u *returnUseCase) getCustomerOrder(ctx context.Context, userId string, orderID string) (*domain.Order, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.OwnerID != userId {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	return order, nil
+}
+
+// getShopReturn kiểm tra quyền chủ shop và trả về NotFound nếu yêu cầu trả hàng không thuộc shop.
+func (u *returnUseCase) getShopReturn(ctx context.Context, userId string, shopID string, returnID string) (*domain.OrderReturn, error) {
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		return nil, err
+	}
+
+	orderReturn, err := u.getReturn(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderReturn.ShopID != shopID {
+		return nil, apperror.NewNotFound("Order return", returnID)
+	}
+	return orderReturn, nil
+}
+
+func (u *returnUseCase) getReturn(ctx context.Context, returnID string) (*domain.OrderReturn, error) {
+	orderReturn, err := u.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get return: %s", err.Error()))
+	}
+	return orderReturn, nil
+}
+
+// authorizeShopOwner kiểm tra user là chủ shop thông qua shop-service.
+func (u *returnUseCase) authorizeShopOwner(ctx context.Context, userId string, shopID string) error {
+	if _, err := uuid.Parse(userId); err != nil {
+		return apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, shopID, userId)
+	if err != nil {
+		return apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+
+	if !isOwner {
+		log.Printf("User %s is not the owner of shop %s", userId, shopID)
+		return apperror.NewForbidden("You are not allowed to manage returns of this shop")
+	}
+	return nil
+}
+
+func toReturnError(err error, orderReturn *domain.OrderReturn) error {
+	if errors.Is(err, domain.ErrReturnStatusChanged) {
+		return apperror.New(apperror.CodeConflict, fmt.Sprintf("Return cannot be updated from status %s, please reload and try again", orderReturn.Status), apperror.TypeConflict)
+	}
+	return apperror.NewInternal(fmt.Sprintf("Failed to update return: %s", err.Error()))
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ListShopOrders liệt kê đơn hàng của shop cho chủ shop, dùng cùng bộ lọc và phân trang với danh sách đơn của khách hàng.
+func (u *orderUsecase) ListShopOrders(ctx context.Context, userId string, shopID string, query dto.ListOrdersQuery) (*domain.OrderPage, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ListShopOrders.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.status_filter", query.Status),
+	)
+
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	filter, err := parseOrderListQuery(query)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	filter.ShopID = shopID
+
+	page, err := u.orderRepo.ListOrdersByShop(ctx, *filter)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to list shop orders: %s", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Int("order.result_count", len(page.Orders)))
+	return page, nil
+}
+
+const (
+	ORDER_EXPORT_BATCH_SIZE = 100
+	MAX_ORDER_EXPORT_RANGE  = 366 * 24 * time.Hour
+)
+
+// ShopOrderExport duyệt các đơn hàng cần xuất theo từng trang keyset, chỉ giữ một trang trong bộ nhớ.
+type ShopOrderExport struct {
+	orderRepo repository.OrderRepository
+	filter    domain.OrderListFilter
+}
+
+// ForEach gọi fn cho từng đơn hàng theo thứ tự của danh sách đơn, dừng ở lỗi đầu tiên hoặc khi ctx bị huỷ.
+func (e *ShopOrderExport) ForEach(ctx context.Context, fn func(order *domain.Order) error) error {
+	filter := e.filter
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := e.orderRepo.ListOrdersByShop(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list shop orders: %w", err)
+		}
+		for _, order := range page.Orders {
+			if err := fn(order); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == nil {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// ExportShopOrders kiểm tra quyền chủ shop và bộ lọc trước khi bắt đầu ghi file, để lỗi vẫn trả về được
+// dưới dạng JSON; việc đọc đơn hàng diễn ra trong ShopOrderExport.ForEach.
+func (u *orderUsecase) ExportShopOrders(ctx context.Context, userId string, shopID string, query dto.ExportOrdersQuery) (*ShopOrderExport, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ExportShopOrders.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("export.from", query.From),
+		attribute.String("export.to", query.To),
+	)
+
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	filter, err := parseOrderListQuery(dto.ListOrdersQuery{
+		Status: query.Status,
+		From:   query.From,
+		To:     query.To,
+		Limit:  ORDER_EXPORT_BATCH_SIZE,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if filter.CreatedTo.Sub(*filter.CreatedFrom) > MAX_ORDER_EXPORT_RANGE {
+		span.SetStatus(codes.Error, "export range too large")
+		return nil, apperror.NewBadRequest("Invalid date range", errors.New("export range must not exceed 366 days"))
+	}
+	filter.ShopID = shopID
+
+	return &ShopOrderExport{orderRepo: u.orderRepo, filter: *filter}, nil
+}
+
+// AcceptShopOrder: người bán xác nhận đơn đã thanh toán (PROCESSING -> CONFIRMED).
+func (u *orderUsecase) AcceptShopOrder(ctx context.Context, userId string, shopID string, orderID string) (*domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "AcceptShopOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.transitionShopOrder(ctx, userId, shopID, orderID, domain.OrderStatusCONFIRMED, "accepted by seller")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.AddEvent("Order accepted by seller")
+	return order, nil
+}
+
+// ShipShopOrder: người bán bàn giao đơn đã xác nhận cho đơn vị vận chuyển (CONFIRMED -> SHIPPED).
+func (u *orderUsecase) ShipShopOrder(ctx context.Context, userId string, shopID string, orderID string) (*domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ShipShopOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+	)
+
+	order, err := u.transitionShopOrder(ctx, userId, shopID, orderID, domain.OrderStatusSHIPPED, "handed over to carrier by seller")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.AddEvent("Order marked as shipped by seller")
+	return order, nil
+}
+
+// RejectShopOrder: người bán từ chối đơn chưa giao, đơn được huỷ giống như khách huỷ
+// (trả lại tồn kho và yêu cầu hoàn tiền nếu đã thanh toán).
+func (u *orderUsecase) RejectShopOrder(ctx context.Context, userId string, shopID string, orderID string, req dto.RejectOrderRequest) (*domain.Order, *domain.OrderCancellation, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "RejectShopOrder.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+	)
+
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	order, err := u.getShopOrder(ctx, shopID, orderID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	// Idempotency: từ chối lại một đơn đã huỷ sẽ trả về thông tin huỷ trước đó
+	if order.Status == domain.OrderStatusCANCELED {
+		cancellation, err := u.orderRepo.GetOrderCancellation(ctx, orderID)
+		if err == nil {
+			return order, cancellation, nil
+		}
+	}
+
+	if !order.IsRejectableBySeller() {
+		span.SetStatus(codes.Error, "order is not rejectable")
+		return nil, nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order cannot be rejected in status %s", order.Status), apperror.TypeConflict)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	rejectedOrder, cancellation, err := u.orderRepo.CancelOrder(ctx, order, userId, domain.StatusChange{
+		Actor:  domain.SellerActor(userId),
+		Reason: reason,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if errors.Is(err, domain.ErrOrderStatusChanged) {
+			return nil, nil, apperror.New(apperror.CodeConflict, "Order status has changed, please reload the order and try again", apperror.TypeConflict)
+		}
+		return nil, nil, apperror.NewInternal(fmt.Sprintf("Failed to reject order: %s", err.Error()))
+	}
+
+	releaseOrderStock(ctx, u.orderRepo, u.productServiceAdapter, rejectedOrder)
+
+	if refunded, err := u.requestRefundIfPaid(ctx, rejectedOrder.ID, reason); err != nil {
+		log.Printf("CRITICAL: Order %s rejected by seller but refund request failed. Reconciler will retry. Error: %v", rejectedOrder.ID, err)
+		span.AddEvent("Refund request failed")
+	} else if refunded != nil {
+		cancellation = refunded
+	}
+
+	span.AddEvent("Order rejected by seller")
+	return rejectedOrder, cancellation, nil
+}
+
+// transitionShopOrder kiểm tra quyền chủ shop rồi chuyển đơn hàng của shop sang trạng thái next.
+func (u *orderUsecase) transitionShopOrder(ctx context.Context, userId string, shopID string, orderID string, next domain.OrderStatus, reason string) (*domain.Order, error) {
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		return nil, err
+	}
+
+	order, err := u.getShopOrder(ctx, shopID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Idempotency: người bán bấm lại thao tác trên đơn đã ở trạng thái đích thì trả về đơn hiện tại
+	if order.Status == next {
+		return order, nil
+	}
+	if !order.Status.CanTransitionTo(next) {
+		return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order cannot be moved from %s to %s", order.Status, next), apperror.TypeConflict)
+	}
+
+	updatedOrder, err := u.orderRepo.UpdateOrderStatus(ctx, orderID, sqlc.OrderStatus(next), domain.StatusChange{
+		Actor:  domain.SellerActor(userId),
+		Reason: reason,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidStatusTransition) || errors.Is(err, domain.ErrOrderStatusChanged) {
+			// Request song song đã chuyển đơn sang đúng trạng thái đích trước request này
+			if current, getErr := u.getShopOrder(ctx, shopID, orderID); getErr == nil && current.Status == next {
+				return current, nil
+			}
+			return nil, apperror.New(apperror.CodeConflict, "Order status has changed, please reload the order and try again", apperror.TypeConflict)
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to update order status: %s", err.Error()))
+	}
+	updatedOrder.Items = order.Items
+
+	return updatedOrder, nil
+}
+
+// authorizeShopOwner kiểm tra user là chủ shop thông qua shop-service.
+func (u *orderUsecase) authorizeShopOwner(ctx context.Context, userId string, shopID string) error {
+	if _, err := uuid.Parse(userId); err != nil {
+		return apperror.NewUnauthorized("Invalid user ID format")
+	}
+
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, shopID, userId)
+	if err != nil {
+		return apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+
+	if !isOwner {
+		log.Printf("User %s is not the owner of shop %s", userId, shopID)
+		return apperror.NewForbidden("You are not allowed to manage orders of this shop")
+	}
+	return nil
+}
+
+// getShopOrder trả về NotFound nếu đơn hàng không thuộc shop để không lộ đơn của shop khác.
+func (u *orderUsecase) getShopOrder(ctx context.Context, shopID string, orderID string) (*domain.Order, error) {
+	order, err := u.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		if apperror.GetType(err) == apperror.TypeNotFound {
+			return nil, err
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get order: %s", err.Error()))
+	}
+
+	if order.ShopID != shopID {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	return order, nil
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PackShopOrderItem: người bán đóng gói một dòng hàng (PENDING -> PACKED), đơn PROCESSING được coi là đã xác nhận.
+func (u *orderUsecase) PackShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string) (*domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "PackShopOrderItem.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+		attribute.String("order_item.id", itemID),
+	)
+
+	order, err := u.transitionShopOrderItem(ctx, userId, shopID, orderID, itemID, domain.OrderItemStatusPacked, "item packed by seller")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("order.status", string(order.Status)))
+	span.AddEvent("Order item packed by seller")
+	return order, nil
+}
+
+// ShipShopOrderItem: người bán bàn giao một dòng hàng đã đóng gói cho vận chuyển (PACKED -> SHIPPED),
+// đơn chuyển sang SHIPPED khi mọi dòng chưa bị huỷ đều đã được giao.
+func (u *orderUsecase) ShipShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string) (*domain.Order, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "ShipShopOrderItem.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+		attribute.String("order_item.id", itemID),
+	)
+
+	order, err := u.transitionShopOrderItem(ctx, userId, shopID, orderID, itemID, domain.OrderItemStatusShipped, "item handed over to carrier by seller")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("order.status", string(order.Status)))
+	span.AddEvent("Order item marked as shipped by seller")
+	return order, nil
+}
+
+// CancelShopOrderItem: người bán huỷ một phần hoặc toàn bộ dòng hàng chưa giao khi không đủ hàng. Phần bị huỷ
+// được trả lại kho và hoàn tiền riêng; huỷ dòng cuối cùng thì cả đơn bị huỷ và hoàn tiền như khi từ chối đơn.
+func (u *orderUsecase) CancelShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string, req dto.CancelOrderItemRequest) (*domain.Order, *domain.OrderItemCancellation, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "CancelShopOrderItem.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("order.id", orderID),
+		attribute.String("order_item.id", itemID),
+	)
+
+	order, item, err := u.getShopOrderItem(ctx, userId, shopID, orderID, itemID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+
+	if !item.Status.IsOpen() {
+		span.SetStatus(codes.Error, "order item is not cancelable")
+		return nil, nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order item cannot be canceled in status %s", item.Status), apperror.TypeConflict)
+	}
+
+	// Bỏ trống quantity nghĩa là huỷ toàn bộ phần còn lại của dòng hàng
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = item.RemainingQuantity()
+	}
+	if quantity > item.RemainingQuantity() {
+		span.SetStatus(codes.Error, "cancel quantity exceeded")
+		return nil, nil, apperror.NewBadRequest("Invalid cancel quantity", fmt.Errorf("only %d of item %s can be canceled", item.RemainingQuantity(), item.ID))
+	}
+	span.SetAttributes(attribute.Int("order_item.cancel_quantity", quantity))
+
+	reason := strings.TrimSpace(req.Reason)
+	updatedOrder, cancellation, err := u.orderRepo.CancelOrderItem(ctx, order, &domain.OrderItemCancellation{
+		OrderItemID:  item.ID,
+		ProductID:    item.ProductID,
+		Quantity:     quantity,
+		RefundAmount: domain.CalculateItemCancellationRefundAmount(order, *item, quantity),
+		Reason:       reason,
+		CanceledBy:   userId,
+	}, domain.StatusChange{
+		Actor:  domain.SellerActor(userId),
+		Reason: reason,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, toOrderItemError(err, itemID)
+	}
+	span.SetAttributes(attribute.String("order.status", string(updatedOrder.Status)))
+
+	if restocked, err := u.restockCanceledItem(ctx, updatedOrder, cancellation); err != nil {
+		log.Printf("CRITICAL: Item cancellation %s of order %s recorded but restock failed. Manual intervention required. Error: %v", cancellation.ID, updatedOrder.ID, err)
+		span.AddEvent("Restock failed")
+	} else {
+		cancellation = restocked
+	}
+
+	if updatedOrder.Status == domain.OrderStatusCANCELED {
+		// Dòng cuối cùng bị huỷ: đóng giữ hàng của đơn và hoàn phần tiền còn lại (kể cả phí vận chuyển) theo đơn
+		releaseOrderStock(ctx, u.orderRepo, u.productServiceAdapter, updatedOrder)
+		if _, err := u.requestRefundIfPaid(ctx, updatedOrder.ID, reason); err != nil {
+			log.Printf("CRITICAL: Order %s canceled after its last item was canceled but refund request failed. Reconciler will retry. Error: %v", updatedOrder.ID, err)
+			span.AddEvent("Refund request failed")
+		}
+		span.AddEvent("Order canceled after its last item was canceled")
+		return updatedOrder, cancellation, nil
+	}
+
+	if refunded, err := u.requestItemCancellationRefund(ctx, updatedOrder, cancellation); err != nil {
+		log.Printf("CRITICAL: Item cancellation %s of order %s recorded but refund request failed. Manual intervention required. Error: %v", cancellation.ID, updatedOrder.ID, err)
+		span.AddEvent("Refund request failed")
+	} else if refunded != nil {
+		cancellation = refunded
+	}
+
+	span.AddEvent("Order item canceled by seller")
+	return updatedOrder, cancellation, nil
+}
+
+// transitionShopOrderItem kiểm tra quyền chủ shop rồi chuyển dòng hàng itemID sang trạng thái next,
+// gọi lại với dòng hàng đã ở trạng thái next thì trả về đơn hiện tại.
+func (u *orderUsecase) transitionShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string, next domain.OrderItemStatus, reason string) (*domain.Order, error) {
+	order, item, err := u.getShopOrderItem(ctx, userId, shopID, orderID, itemID)
+	if err != nil {
+		if item != nil && item.Status == next {
+			return order, nil
+		}
+		return nil, err
+	}
+	if item.Status == next {
+		return order, nil
+	}
+
+	if !item.Status.CanTransitionTo(next) {
+		return nil, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order item cannot be moved from %s to %s", item.Status, next), apperror.TypeConflict)
+	}
+
+	updatedOrder, err := u.orderRepo.UpdateOrderItemStatus(ctx, order, itemID, next, domain.StatusChange{
+		Actor:  domain.SellerActor(userId),
+		Reason: reason,
+	})
+	if err != nil {
+		return nil, toOrderItemError(err, itemID)
+	}
+	return updatedOrder, nil
+}
+
+// getShopOrderItem trả về đơn hàng của shop và dòng hàng itemID. Lỗi Conflict khi đơn không còn ở giai đoạn
+// người bán xử lý từng dòng hàng vẫn kèm theo đơn và dòng hàng để caller kiểm tra idempotency.
+func (u *orderUsecase) getShopOrderItem(ctx context.Context, userId string, shopID string, orderID string, itemID string) (*domain.Order, *domain.OrderItem, error) {
+	if err := u.authorizeShopOwner(ctx, userId, shopID); err != nil {
+		return nil, nil, err
+	}
+
+	order, err := u.getShopOrder(ctx, shopID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	item, ok := order.FindItem(itemID)
+	if !ok {
+		return nil, nil, apperror.NewNotFound("Order item", itemID)
+	}
+
+	if !order.IsItemFulfillmentOpen() {
+		return order, item, apperror.New(apperror.CodeConflict, fmt.Sprintf("Order items cannot be updated in status %s", order.Status), apperror.TypeConflict)
+	}
+	return order, item, nil
+}
+
+// restockCanceledItem trả lại kho phần hàng bị huỷ của dòng hàng. Reference của lần huỷ giúp product-service
+// bỏ qua các lần gọi lặp lại.
+func (u *orderUsecase) restockCanceledItem(ctx context.Context, order *domain.Order, cancellation *domain.OrderItemCancellation) (*domain.OrderItemCancellation, error) {
+	resp, err := u.productServiceAdapter.RestockProducts(ctx, &product_v1.RestockProductsRequest{
+		ReferenceId: domain.ItemCancellationReferenceID(cancellation.ID),
+		OrderId:     order.ID,
+		ShopId:      order.ShopID,
+		Products: []*product_v1.RestockProduct{
+			{
+				ProductId: cancellation.ProductID,
+				Quantity:  int32(cancellation.Quantity),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restock item cancellation %s: %w", cancellation.ID, err)
+	}
+	if !resp.GetSuccess() {
+		return nil, fmt.Errorf("product service could not restock item cancellation %s: %s", cancellation.ID, resp.GetMessage())
+	}
+
+	log.Printf("Restocked item cancellation %s of order %s (already processed: %t)", cancellation.ID, order.ID, resp.GetAlreadyProcessed())
+	return u.orderRepo.MarkItemCancellationRestocked(ctx, cancellation.ID)
+}
+
+// requestItemCancellationRefund yêu cầu payment-service hoàn số tiền của phần dòng hàng bị huỷ.
+// Trả về nil, nil khi đơn hàng không có khoản thanh toán nào đã thành công hoặc không có gì để hoàn.
+func (u *orderUsecase) requestItemCancellationRefund(ctx context.Context, order *domain.Order, cancellation *domain.OrderItemCancellation) (*domain.OrderItemCancellation, error) {
+	if !cancellation.RefundAmount.IsPositive() {
+		return nil, nil
+	}
+
+	paymentResp, err := u.paymentAdapter.GetPaymentByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment of order %s: %w", order.ID, err)
+	}
+
+	if !paymentResp.GetExists() || paymentResp.GetPayment().GetStatus() != payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS {
+		return nil, nil
+	}
+
+	refundResp, err := u.paymentAdapter.RequestPartialRefund(
+		ctx,
+		order.ID,
+		domain.ItemCancellationReferenceID(cancellation.ID),
+		cancellation.RefundAmount,
+		fmt.Sprintf("item cancellation %s: %s", cancellation.ID, cancellation.Reason),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request refund for item cancellation %s: %w", cancellation.ID, err)
+	}
+
+	if !refundResp.GetAccepted() {
+		return nil, fmt.Errorf("payment service rejected refund for item cancellation %s: %s", cancellation.ID, refundResp.GetMessage())
+	}
+
+	log.Printf("Refund %s of %s requested for item cancellation %s", refundResp.GetRefundId(), cancellation.RefundAmount, cancellation.ID)
+	return u.orderRepo.SetItemCancellationRefundID(ctx, cancellation.ID, refundResp.GetRefundId())
+}
+
+// toOrderItemError chuyển lỗi của repository khi cập nhật dòng hàng thành lỗi trả về cho người bán.
+func toOrderItemError(err error, itemID string) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderItemNotFound):
+		return apperror.NewNotFound("Order item", itemID)
+	case errors.Is(err, domain.ErrItemCancelQuantityExceeded):
+		return apperror.NewBadRequest("Invalid cancel quantity", err)
+	case errors.Is(err, domain.ErrInvalidItemStatusTransition),
+		errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrOrderStatusChanged):
+		return apperror.New(apperror.CodeConflict, "Order status has changed, please reload the order and try again", apperror.TypeConflict)
+	}
+	if apperror.GetType(err) == apperror.TypeNotFound {
+		return err
+	}
+	return apperror.NewInternal(fmt.Sprintf("Failed to update order item: %s", err.Error()))
+}
@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
+)
+
+const (
+	testSellerID = "5f3c1c1e-2b7a-4d0e-9a3b-6f1e2d3c4b5a"
+	testShopID   = "8c1d2e3f-4a5b-4c6d-9e7f-0a1b2c3d4e5f"
+	testOrderID  = "order-1"
+)
+
+type fakeShopServiceAdapter struct {
+	adapter.ShopServiceAdapter
+}
+
+func (f *fakeShopServiceAdapter) CheckShopOwnership(ctx context.Context, shopID string, userID string) (bool, error) {
+	return shopID == testShopID && userID == testSellerID, nil
+}
+
+type fakeOrderRepository struct {
+	repository.OrderRepository
+	order   *domain.Order
+	updates int
+	// concurrentStatus mô phỏng một request khác đã đổi trạng thái đơn ngay trước lần cập nhật này
+	concurrentStatus domain.OrderStatus
+}
+
+func (f *fakeOrderRepository) GetOrderByID(ctx context.Context, orderID string) (*domain.Order, error) {
+	if orderID != f.order.ID {
+		return nil, apperror.NewNotFound("Order", orderID)
+	}
+	order := *f.order
+	return &order, nil
+}
+
+func (f *fakeOrderRepository) UpdateOrderStatus(ctx context.Context, orderID string, status sqlc.OrderStatus, change domain.StatusChange) (*domain.Order, error) {
+	if f.concurrentStatus != "" {
+		f.order.Status = f.concurrentStatus
+		return nil, domain.ErrOrderStatusChanged
+	}
+	if !f.order.Status.CanTransitionTo(domain.OrderStatus(status)) {
+		return nil, domain.ErrInvalidStatusTransition
+	}
+	f.updates++
+	f.order.Status = domain.OrderStatus(status)
+	order := *f.order
+	return &order, nil
+}
+
+func TestOrderUsecase_SellerTransitions(t *testing.T) {
+	accept := func(uc usecase.OrderUsecase) (*domain.Order, error) {
+		return uc.AcceptShopOrder(context.Background(), testSellerID, testShopID, testOrderID)
+	}
+	ship := func(uc usecase.OrderUsecase) (*domain.Order, error) {
+		return uc.ShipShopOrder(context.Background(), testSellerID, testShopID, testOrderID)
+	}
+
+	testCases := []struct {
+		name             string
+		run              func(uc usecase.OrderUsecase) (*domain.Order, error)
+		status           domain.OrderStatus
+		concurrentStatus domain.OrderStatus
+		expectedStatus   domain.OrderStatus
+		expectedUpdates  int
+		expectedType     apperror.ErrorType
+		expectError      bool
+	}{
+		{
+			name:            "Accept paid order",
+			run:             accept,
+			status:          domain.OrderStatusPROCESSING,
+			expectedStatus:  domain.OrderStatusCONFIRMED,
+			expectedUpdates: 1,
+		},
+		{
+			name:           "Accept again returns the confirmed order",
+			run:            accept,
+			status:         domain.OrderStatusCONFIRMED,
+			expectedStatus: domain.OrderStatusCONFIRMED,
+		},
+		{
+			name:             "Concurrent accept returns the confirmed order",
+			run:              accept,
+			status:           domain.OrderStatusPROCESSING,
+			concurrentStatus: domain.OrderStatusCONFIRMED,
+			expectedStatus:   domain.OrderStatusCONFIRMED,
+		},
+		{
+			name:             "Concurrent cancel conflicts with accept",
+			run:              accept,
+			status:           domain.OrderStatusPROCESSING,
+			concurrentStatus: domain.OrderStatusCANCELED,
+			expectedType:     apperror.TypeConflict,
+			expectError:      true,
+		},
+		{
+			name:         "Accept canceled order",
+			run:          accept,
+			status:       domain.OrderStatusCANCELED,
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+		{
+			name:            "Ship confirmed order",
+			run:             ship,
+			status:          domain.OrderStatusCONFIRMED,
+			expectedStatus:  domain.OrderStatusSHIPPED,
+			expectedUpdates: 1,
+		},
+		{
+			name:           "Ship again returns the shipped order",
+			run:            ship,
+			status:         domain.OrderStatusSHIPPED,
+			expectedStatus: domain.OrderStatusSHIPPED,
+		},
+		{
+			name:         "Ship canceled order",
+			run:          ship,
+			status:       domain.OrderStatusCANCELED,
+			expectedType: apperror.TypeConflict,
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeOrderRepository{
+				order:            &domain.Order{ID: testOrderID, ShopID: testShopID, Status: tc.status},
+				concurrentStatus: tc.concurrentStatus,
+			}
+			uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+
+			order, err := tc.run(uc)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got order in status %s", order.Status)
+				}
+				if got := apperror.GetType(err); got != tc.expectedType {
+					t.Errorf("error type = %v, want %v", got, tc.expectedType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.Status != tc.expectedStatus {
+				t.Errorf("status = %s, want %s", order.Status, tc.expectedStatus)
+			}
+			if repo.updates != tc.expectedUpdates {
+				t.Errorf("status updates = %d, want %d", repo.updates, tc.expectedUpdates)
+			}
+		})
+	}
+}
+
+func TestOrderUsecase_SellerTransitions_OtherShop(t *testing.T) {
+	repo := &fakeOrderRepository{
+		order: &domain.Order{ID: testOrderID, ShopID: "shop-2", Status: domain.OrderStatusCONFIRMED},
+	}
+	uc := usecase.NewOrderUsecase(repo, &fakeShopServiceAdapter{}, nil, nil, nil, nil, nil)
+
+	// Đơn của shop khác không được trả về dù đã ở trạng thái đích
+	if _, err := uc.AcceptShopOrder(context.Background(), testSellerID, testShopID, testOrderID); apperror.GetType(err) != apperror.TypeNotFound {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
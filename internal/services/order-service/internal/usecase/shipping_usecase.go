@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	product_v1 "github.com/toji-dev/go-shop/proto/gen/go/product/v1"
+	user_v1 "github.com/toji-dev/go-shop/proto/gen/go/user/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ShippingUseCase tính phí vận chuyển theo khoảng cách giữa shop và người mua cùng tổng khối lượng kiện hàng.
+type ShippingUseCase interface {
+	QuoteShippingFee(ctx context.Context, userId string, req dto.ShippingQuoteRequest) (*domain.ShippingQuote, error)
+	CalculateShippingFee(ctx context.Context, shopID string, destination *user_v1.Address, items []domain.ShippingParcelItem) (*domain.ShippingQuote, error)
+	GetShopShippingRates(ctx context.Context, shopID string) (*domain.ShippingRateTable, error)
+	UpdateShopShippingRates(ctx context.Context, userId string, shopID string, req dto.UpdateShippingRatesRequest) (*domain.ShippingRateTable, error)
+}
+
+type shippingUseCase struct {
+	shippingRateRepo       repository.ShippingRateRepository
+	shopServiceAdapter     adapter.ShopServiceAdapter
+	productServiceAdapter  adapter.ProductServiceAdapter
+	userAdapter            adapter.UserServiceAdapter
+	defaultRates           domain.ShippingRateTable
+	defaultItemWeightGrams int
+}
+
+func NewShippingUseCase(
+	shippingRateRepo repository.ShippingRateRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+	productServiceAdapter adapter.ProductServiceAdapter,
+	userAdapter adapter.UserServiceAdapter,
+	defaultRates domain.ShippingRateTable,
+	defaultItemWeightGrams int,
+) ShippingUseCase {
+	defaultRates.Normalize()
+	if err := defaultRates.Validate(); err != nil {
+		log.Printf("WARNING: default shipping rates are invalid: %v", err)
+	}
+
+	return &shippingUseCase{
+		shippingRateRepo:       shippingRateRepo,
+		shopServiceAdapter:     shopServiceAdapter,
+		productServiceAdapter:  productServiceAdapter,
+		userAdapter:            userAdapter,
+		defaultRates:           defaultRates,
+		defaultItemWeightGrams: defaultItemWeightGrams,
+	}
+}
+
+func (u *shippingUseCase) QuoteShippingFee(ctx context.Context, userId string, req dto.ShippingQuoteRequest) (*domain.ShippingQuote, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "QuoteShippingFee.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", req.ShopID),
+		attribute.Int("order.item_count", len(req.Items)),
+	)
+
+	isShopExists, err := u.shopServiceAdapter.CheckShopExists(ctx, req.ShopID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to check shop existence: %s", err.Error()))
+	}
+	if !isShopExists {
+		return nil, apperror.NewNotFound("Shop", req.ShopID)
+	}
+
+	address, err := u.userAdapter.GetAddressById(ctx, req.ShippingAddressID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get address: %s", err.Error()))
+	}
+	// Địa chỉ của người dùng khác được báo như không tồn tại để không lộ toạ độ qua phí vận chuyển
+	if address == nil || address.GetUserId() != userId {
+		return nil, apperror.NewNotFound("Shipping address", req.ShippingAddressID)
+	}
+
+	productIDs := make([]string, 0, len(req.Items))
+	quantityMap := make(map[string]int)
+	for _, item := range req.Items {
+		productIDs = append(productIDs, item.ProductID)
+		quantityMap[item.ProductID] = item.Quantity
+	}
+
+	productsInfo, err := u.productServiceAdapter.GetProductsInfo(ctx, &product_v1.GetProductsInfoRequest{
+		ProductIds: productIDs,
+	})
+	if err != nil || !productsInfo.Valid || len(productsInfo.Products) != len(req.Items) {
+		span.SetStatus(codes.Error, "One or more products are invalid")
+		return nil, apperror.NewBadRequest("One or more products are invalid or unavailable", err)
+	}
+
+	items := make([]domain.ShippingParcelItem, 0, len(productsInfo.Products))
+	for _, p := range productsInfo.Products {
+		items = append(items, domain.ShippingParcelItem{
+			ProductID:   p.Id,
+			WeightGrams: int(p.WeightGrams),
+			Quantity:    quantityMap[p.Id],
+		})
+	}
+
+	return u.CalculateShippingFee(ctx, req.ShopID, address, items)
+}
+
+// CalculateShippingFee tính phí giao kiện hàng từ địa chỉ lấy hàng của shop tới destination.
+// Thiếu toạ độ ở một trong hai đầu thì phí được tính theo vùng xa nhất của shop.
+func (u *shippingUseCase) CalculateShippingFee(ctx context.Context, shopID string, destination *user_v1.Address, items []domain.ShippingParcelItem) (*domain.ShippingQuote, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "CalculateShippingFee.UseCase")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("shop.id", shopID))
+
+	rates, err := u.GetShopShippingRates(ctx, shopID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	shopAddress, err := u.shopServiceAdapter.GetShopAddress(ctx, shopID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get shop address: %s", err.Error()))
+	}
+
+	var distanceKm *float64
+	if shopAddress != nil && shopAddress.GetHasCoordinates() && hasCoordinates(destination) {
+		origin := domain.GeoPoint{Lat: shopAddress.GetLat(), Long: shopAddress.GetLong()}
+		target := domain.GeoPoint{Lat: destination.GetLat(), Long: destination.GetLong()}
+		distance := math.Round(origin.DistanceKm(target)*100) / 100
+		distanceKm = &distance
+	} else {
+		log.Printf("Missing coordinates to measure shipping distance for shop %s, using the farthest zone", shopID)
+	}
+
+	totalWeightGrams := domain.TotalParcelWeightGrams(items, u.defaultItemWeightGrams)
+
+	quote, err := rates.Quote(distanceKm, totalWeightGrams)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if errors.Is(err, domain.ErrOutOfShippingRange) || errors.Is(err, domain.ErrParcelTooHeavy) {
+			return nil, apperror.NewBadRequest("Shop cannot deliver this order", err)
+		}
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to calculate shipping fee: %s", err.Error()))
+	}
+
+	if quote.DistanceKm != nil {
+		span.SetAttributes(attribute.Float64("shipping.distance_km", *quote.DistanceKm))
+	}
+	span.SetAttributes(
+		attribute.Int("shipping.weight_grams", quote.TotalWeightGrams),
+		attribute.Float64("shipping.fee", quote.ShippingFee),
+		attribute.String("shipping.currency", quote.Currency),
+	)
+
+	return quote, nil
+}
+
+// GetShopShippingRates trả về bảng phí riêng của shop, hoặc bảng phí mặc định nếu shop chưa cấu hình.
+func (u *shippingUseCase) GetShopShippingRates(ctx context.Context, shopID string) (*domain.ShippingRateTable, error) {
+	rates, err := u.shippingRateRepo.GetByShopID(ctx, shopID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get shipping rates: %s", err.Error()))
+	}
+
+	if rates == nil {
+		defaultRates := u.defaultRates
+		defaultRates.ShopID = shopID
+		defaultRates.IsDefault = true
+		return &defaultRates, nil
+	}
+
+	return rates, nil
+}
+
+func (u *shippingUseCase) UpdateShopShippingRates(ctx context.Context, userId string, shopID string, req dto.UpdateShippingRatesRequest) (*domain.ShippingRateTable, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "UpdateShopShippingRates.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+	)
+
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, shopID, userId)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+	if !isOwner {
+		return nil, apperror.NewForbidden("You are not allowed to manage shipping rates of this shop")
+	}
+
+	rates := &domain.ShippingRateTable{
+		ShopID:      shopID,
+		Currency:    strings.ToUpper(req.Currency),
+		Zones:       make([]domain.ShippingZoneTier, 0, len(req.Zones)),
+		WeightTiers: make([]domain.ShippingWeightTier, 0, len(req.WeightTiers)),
+	}
+	for _, zone := range req.Zones {
+		rates.Zones = append(rates.Zones, domain.ShippingZoneTier{
+			MaxDistanceKm: zone.MaxDistanceKm,
+			BaseFee:       zone.BaseFee,
+		})
+	}
+	for _, tier := range req.WeightTiers {
+		rates.WeightTiers = append(rates.WeightTiers, domain.ShippingWeightTier{
+			MaxWeightGrams: tier.MaxWeightGrams,
+			Surcharge:      tier.Surcharge,
+		})
+	}
+
+	rates.Normalize()
+	if err := rates.Validate(); err != nil {
+		return nil, apperror.NewBadRequest("Invalid shipping rates", err)
+	}
+
+	savedRates, err := u.shippingRateRepo.Upsert(ctx, rates)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to save shipping rates: %s", err.Error()))
+	}
+
+	return savedRates, nil
+}
+
+// hasCoordinates coi (0, 0) là địa chỉ chưa được geocode.
+func hasCoordinates(address *user_v1.Address) bool {
+	return address != nil && (address.GetLat() != 0 || address.GetLong() != 0)
+}
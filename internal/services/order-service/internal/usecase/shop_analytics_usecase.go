@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/pkg/apperror"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/grpc/adapter"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	DEFAULT_ANALYTICS_RANGE_DAYS = 30
+	MAX_ANALYTICS_RANGE_DAYS     = 366
+	DEFAULT_ANALYTICS_TOP        = 10
+)
+
+// ShopAnalyticsUseCase trả về thống kê bán hàng của shop cho chủ shop, đọc từ bảng thống kê theo ngày.
+type ShopAnalyticsUseCase interface {
+	GetShopAnalytics(ctx context.Context, userId string, shopID string, query dto.ShopAnalyticsQuery) (*domain.ShopAnalytics, error)
+}
+
+type shopAnalyticsUseCase struct {
+	shopStatsRepo      repository.ShopStatsRepository
+	shopServiceAdapter adapter.ShopServiceAdapter
+}
+
+func NewShopAnalyticsUseCase(
+	shopStatsRepo repository.ShopStatsRepository,
+	shopServiceAdapter adapter.ShopServiceAdapter,
+) ShopAnalyticsUseCase {
+	return &shopAnalyticsUseCase{
+		shopStatsRepo:      shopStatsRepo,
+		shopServiceAdapter: shopServiceAdapter,
+	}
+}
+
+func (u *shopAnalyticsUseCase) GetShopAnalytics(ctx context.Context, userId string, shopID string, query dto.ShopAnalyticsQuery) (*domain.ShopAnalytics, error) {
+	tracer := otel.Tracer("order-service.usecase")
+	ctx, span := tracer.Start(ctx, "GetShopAnalytics.UseCase")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user.id", userId),
+		attribute.String("shop.id", shopID),
+		attribute.String("analytics.from", query.From),
+		attribute.String("analytics.to", query.To),
+		attribute.String("analytics.granularity", query.Granularity),
+	)
+
+	isOwner, err := u.shopServiceAdapter.CheckShopOwnership(ctx, shopID, userId)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to check shop ownership: %s", err.Error()))
+	}
+	if !isOwner {
+		return nil, apperror.NewForbidden("You are not allowed to view analytics of this shop")
+	}
+
+	from, to, err := parseAnalyticsRange(query.From, query.To, time.Now())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	granularity := domain.StatsGranularityDay
+	if query.Granularity != "" {
+		granularity = domain.StatsGranularity(query.Granularity)
+	}
+	top := query.Top
+	if top <= 0 {
+		top = DEFAULT_ANALYTICS_TOP
+	}
+
+	daily, err := u.shopStatsRepo.GetShopDailyStats(ctx, shopID, from, to)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get shop sales stats: %s", err.Error()))
+	}
+
+	topProducts, err := u.shopStatsRepo.GetShopTopProducts(ctx, shopID, from, to, top)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperror.NewInternal(fmt.Sprintf("Failed to get shop top products: %s", err.Error()))
+	}
+
+	series, totals := domain.BuildShopSalesSeries(daily, from, to, granularity)
+	span.SetAttributes(attribute.Int("analytics.bucket_count", len(series)))
+
+	return &domain.ShopAnalytics{
+		ShopID:      shopID,
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Series:      series,
+		Totals:      totals,
+		TopProducts: topProducts,
+	}, nil
+}
+
+// parseAnalyticsRange đọc khoảng ngày [from, to) theo UTC, mặc định là DEFAULT_ANALYTICS_RANGE_DAYS ngày tính cả hôm nay.
+func parseAnalyticsRange(fromParam string, toParam string, now time.Time) (time.Time, time.Time, error) {
+	to := domain.StatsGranularityDay.BucketStart(now.UTC()).AddDate(0, 0, 1)
+	if toParam != "" {
+		t, err := parseDateParam(toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperror.NewBadRequest("Invalid 'to' date", err)
+		}
+		to = domain.StatsGranularityDay.BucketStart(t.UTC())
+	}
+
+	from := to.AddDate(0, 0, -DEFAULT_ANALYTICS_RANGE_DAYS)
+	if fromParam != "" {
+		t, err := parseDateParam(fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, apperror.NewBadRequest("Invalid 'from' date", err)
+		}
+		from = domain.StatsGranularityDay.BucketStart(t.UTC())
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, apperror.NewBadRequest("Invalid date range", errors.New("'from' must be before 'to'"))
+	}
+	if to.Sub(from) > MAX_ANALYTICS_RANGE_DAYS*24*time.Hour {
+		return time.Time{}, time.Time{}, apperror.NewBadRequest("Invalid date range", fmt.Errorf("date range must not exceed %d days", MAX_ANALYTICS_RANGE_DAYS))
+	}
+	return from, to, nil
+}
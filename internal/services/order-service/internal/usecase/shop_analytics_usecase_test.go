@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAnalyticsRange(t *testing.T) {
+	now := time.Date(2026, 3, 15, 18, 45, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name         string
+		from         string
+		to           string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectError  bool
+	}{
+		{
+			name:         "Default range ends after today",
+			expectedFrom: date(2026, 2, 14),
+			expectedTo:   date(2026, 3, 16),
+		},
+		{
+			name:         "Dates are truncated to days",
+			from:         "2026-03-01T10:00:00Z",
+			to:           "2026-03-10",
+			expectedFrom: date(2026, 3, 1),
+			expectedTo:   date(2026, 3, 10),
+		},
+		{
+			name:         "Offset times are converted to UTC",
+			from:         "2026-03-02T01:00:00+07:00",
+			to:           "2026-03-03",
+			expectedFrom: date(2026, 3, 1),
+			expectedTo:   date(2026, 3, 3),
+		},
+		{
+			name:         "Only to given uses default length",
+			to:           "2026-03-31",
+			expectedFrom: date(2026, 3, 1),
+			expectedTo:   date(2026, 3, 31),
+		},
+		{
+			name:        "Invalid from",
+			from:        "01/03/2026",
+			expectError: true,
+		},
+		{
+			name:        "Invalid to",
+			to:          "yesterday",
+			expectError: true,
+		},
+		{
+			name:        "From not before to",
+			from:        "2026-03-10",
+			to:          "2026-03-10",
+			expectError: true,
+		},
+		{
+			name:        "Range too long",
+			from:        "2025-01-01",
+			to:          "2026-03-01",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := parseAnalyticsRange(tc.from, tc.to, now)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got [%s, %s)", from, to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !from.Equal(tc.expectedFrom) || !to.Equal(tc.expectedTo) {
+				t.Errorf("range = [%s, %s), want [%s, %s)", from, to, tc.expectedFrom, tc.expectedTo)
+			}
+		})
+	}
+}
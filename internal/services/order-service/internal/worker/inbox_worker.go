@@ -131,8 +131,8 @@ func (w *InboxWorker) statsWorker() {
 			if err != nil {
 				log.Printf("[InboxWorker] Error getting inbox stats: %v", err)
 			} else {
-				log.Printf("[InboxStats] Pending: %d, Processed: %d, Failed: %d, Total: %d",
-					stats.PendingCount, stats.ProcessedCount, stats.FailedCount, stats.TotalCount)
+				log.Printf("[InboxStats] Pending: %d, Processed: %d, Failed: %d, Discarded: %d, Parked: %d, Total: %d",
+					stats.PendingCount, stats.ProcessedCount, stats.FailedCount, stats.DiscardedCount, stats.ParkedCount, stats.TotalCount)
 			}
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/toji-dev/go-shop/internal/pkg/constant"
 	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/config"
+	"github.com/toji-dev/go-shop/internal/services/order-service/internal/inbox"
 	"github.com/toji-dev/go-shop/internal/services/order-service/internal/usecase"
 )
 
@@ -14,35 +15,43 @@ type KafkaConsumer struct {
 	config            *config.Config
 	orderUsecase      usecase.OrderUsecase      // Deprecated: for backward compatibility
 	inboxEventUsecase usecase.InboxEventUseCase // New inbox-based processing
+	inboxRegistry     *inbox.Registry
 
-	refundPaymentConsumer kafka_infra.Consumer
+	consumers []kafka_infra.Consumer
 }
 
 func NewKafkaConsumer(
 	cfg *config.Config,
 	orderUsecase usecase.OrderUsecase,
 	inboxEventUsecase usecase.InboxEventUseCase,
+	inboxRegistry *inbox.Registry,
 ) *KafkaConsumer {
 	consumer := &KafkaConsumer{
 		config:            cfg,
 		orderUsecase:      orderUsecase,
 		inboxEventUsecase: inboxEventUsecase,
+		inboxRegistry:     inboxRegistry,
 	}
 	consumer.initKafkaConsumer()
 	return consumer
 }
 
+// initKafkaConsumer tạo một consumer cho mỗi topic có handler đăng ký trong inbox registry.
 func (sc *KafkaConsumer) initKafkaConsumer() {
-	sc.refundPaymentConsumer = kafka_infra.NewConsumer(
-		sc.config.Kafka.Brokers,
-		string(constant.EventTypeRefundSuccessed),
-		string(constant.KafkaConsumerGroupOrderService),
-	)
-	log.Println("Kafka consumer initialized for topic 'payment_events'")
+	for _, topic := range sc.inboxRegistry.Topics() {
+		sc.consumers = append(sc.consumers, kafka_infra.NewConsumer(
+			sc.config.Kafka.Brokers,
+			topic,
+			string(constant.KafkaConsumerGroupOrderService),
+		))
+	}
+	log.Printf("Kafka consumers initialized for topics %v", sc.inboxRegistry.Topics())
 }
 
 func (ks *KafkaConsumer) StartAllKafkaConsumer() {
 	log.Println("Starting Kafka consumer with inbox pattern...")
 	// Use inbox event usecase for idempotent processing
-	ks.refundPaymentConsumer.Start(context.Background(), ks.inboxEventUsecase.HandleIncomingEvent)
+	for _, consumer := range ks.consumers {
+		go consumer.Start(context.Background(), ks.inboxEventUsecase.HandleIncomingEvent)
+	}
 }
@@ -26,15 +26,66 @@ func (s *Scheduler) RegisterJobs() {
 	log.Println("[Scheduler] Registering cron jobs...")
 
 	orderRepo := s.container.GetOrderRepository()
+	reconciliationRepo := s.container.GetReconciliationRepository()
+	shipperCashRepo := s.container.GetShipperCashRepository()
 	productAdapter := s.container.GetProductServiceAdapter()
+	paymentAdapter := s.container.GetPaymentServiceAdapter()
 
-	orderReconciler := usecase.NewOrderReconciler(orderRepo, productAdapter)
+	orderReconciler := usecase.NewOrderReconciler(orderRepo, reconciliationRepo, shipperCashRepo, productAdapter, paymentAdapter)
 
 	_, err := s.cron.AddFunc("@every 5m", orderReconciler.ReconcilePendingOrders)
 	if err != nil {
 		log.Fatalf("[Scheduler] FATAL: Could not register 'ReconcilePendingOrders' job: %v", err)
 	}
 	log.Println("[Scheduler] 'ReconcilePendingOrders' job registered to run every 5 minutes.")
+
+	// Một lần đối soát có thể duyệt nhiều trang, không chạy chồng lên nhau để tránh sửa cùng một đơn hai lần
+	paymentReconcileJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderReconciler.ReconcilePayments))
+	_, err = s.cron.AddJob("@every 10m", paymentReconcileJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'ReconcilePayments' job: %v", err)
+	}
+	log.Println("[Scheduler] 'ReconcilePayments' job registered to run every 10 minutes.")
+
+	cashReconcileJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderReconciler.ReconcileCashCollections))
+	_, err = s.cron.AddJob("@every 5m", cashReconcileJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'ReconcileCashCollections' job: %v", err)
+	}
+	log.Println("[Scheduler] 'ReconcileCashCollections' job registered to run every 5 minutes.")
+
+	refundRetryJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderReconciler.RetryCancellationRefunds))
+	_, err = s.cron.AddJob("@every 1m", refundRetryJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'RetryCancellationRefunds' job: %v", err)
+	}
+	log.Println("[Scheduler] 'RetryCancellationRefunds' job registered to run every minute.")
+
+	stockReleaseRetryJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderReconciler.RetryStockReleases))
+	_, err = s.cron.AddJob("@every 1m", stockReleaseRetryJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'RetryStockReleases' job: %v", err)
+	}
+	log.Println("[Scheduler] 'RetryStockReleases' job registered to run every minute.")
+
+	orderEventUsecase := s.container.GetOrderEventUsecase()
+
+	// Bỏ qua lần chạy mới nếu lần trước chưa xong để không publish trùng event
+	publishJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderEventUsecase.PublishPendingOrderEvents))
+	_, err = s.cron.AddJob("@every 5s", publishJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'PublishPendingOrderEvents' job: %v", err)
+	}
+	log.Println("[Scheduler] 'PublishPendingOrderEvents' job registered to run every 5 seconds.")
+
+	orderExpiryUsecase := s.container.GetOrderExpiryUsecase()
+
+	expiryJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(orderExpiryUsecase.ExpireUnpaidOrders))
+	_, err = s.cron.AddJob("@every 1m", expiryJob)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'ExpireUnpaidOrders' job: %v", err)
+	}
+	log.Println("[Scheduler] 'ExpireUnpaidOrders' job registered to run every minute.")
 }
 
 func (s *Scheduler) Start() {
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
+	"strconv"
 
 	"net/http"
 	_ "net/http/pprof"
@@ -12,8 +14,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
 	dependency_container "github.com/toji-dev/go-shop/internal/services/payment-service/internal/dependency-container"
+	grpc_server "github.com/toji-dev/go-shop/internal/services/payment-service/internal/grpc/server"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/router"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/worker"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -38,6 +43,8 @@ func main() {
 
 	go startMetricsServer()
 
+	go runGrpcServer(cfg, dependencyContainer)
+
 	go func() {
 		log.Println("Starting pprof server on :6065")
 		if err := http.ListenAndServe("0.0.0.0:6065", nil); err != nil {
@@ -54,6 +61,27 @@ func main() {
 	}
 }
 
+func runGrpcServer(cfg *config.Config, dependencyContainer *dependency_container.DependencyContainer) {
+	address := cfg.GRPC.ServiceHost + ":" + strconv.Itoa(cfg.GRPC.ServicePort)
+	log.Printf("Starting gRPC server on %s", address)
+	lis, err := net.Listen("tcp", address)
+
+	if err != nil {
+		log.Fatalf("failed to listen for grpc on port %d: %v", cfg.GRPC.ServicePort, err)
+	}
+
+	s := grpc.NewServer()
+	server := grpc_server.NewPaymentGRPCServer(
+		dependencyContainer.GetPaymentRepository(),
+		dependencyContainer.GetPaymentUseCase(),
+	)
+
+	payment_v1.RegisterPaymentServiceServer(s, server)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("failed to serve gRPC server: %v", err)
+	}
+}
+
 func startMetricsServer() {
 	metricsRouter := gin.New()
 	metricsRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -9,13 +9,15 @@ import (
 )
 
 type Config struct {
-	Server          ServerConfig   `mapstructure:"server"`
-	Database        DatabaseConfig `mapstructure:"database"`
-	Redis           RedisConfig    `mapstructure:"redis"`
-	App             AppConfig      `mapstructure:"app"`
-	Momo            MomoConfig     `mapstructure:"momo"`
-	OrderGrpcConfig GrpcConfig     `mapstructure:"order_grpc"`
-	Kafka           KafkaConfig    `mapstructure:"kafka"`
+	Server          ServerConfig     `mapstructure:"server"`
+	Database        DatabaseConfig   `mapstructure:"database"`
+	Redis           RedisConfig      `mapstructure:"redis"`
+	App             AppConfig        `mapstructure:"app"`
+	Momo            MomoConfig       `mapstructure:"momo"`
+	VNPay           VNPayConfig      `mapstructure:"vnpay"`
+	OrderGrpcConfig GrpcConfig       `mapstructure:"order_grpc"`
+	GRPC            GrpcServerConfig `mapstructure:"grpc"`
+	Kafka           KafkaConfig      `mapstructure:"kafka"`
 }
 
 type ServerConfig struct {
@@ -58,6 +60,11 @@ type GrpcConfig struct {
 	OrderServicePort int    `mapstructure:"order_service_port"`
 }
 
+type GrpcServerConfig struct {
+	ServiceHost string `mapstructure:"service_host"`
+	ServicePort int    `mapstructure:"service_port"`
+}
+
 type ExternalServiceConfig struct {
 	Host string `mapstructure:"host"`
 	Port string `mapstructure:"port"`
@@ -72,6 +79,19 @@ type MomoConfig struct {
 	ApiGetStatusEndpoint string `mapstructure:"api_get_status_endpoint"`
 }
 
+type VNPayConfig struct {
+	TmnCode    string `mapstructure:"tmn_code"`
+	HashSecret string `mapstructure:"hash_secret"`
+	// PayURL là trang thanh toán của VNPay, khách được redirect tới kèm các tham số đã ký
+	PayURL string `mapstructure:"pay_url"`
+	// ApiEndpoint nhận cả querydr và refund, phân biệt bằng vnp_Command
+	ApiEndpoint string `mapstructure:"api_endpoint"`
+	// ServerIP gửi trong vnp_IpAddr của các lệnh server-to-server (querydr, refund)
+	ServerIP string `mapstructure:"server_ip"`
+	// PaymentTimeout là thời gian link thanh toán còn hiệu lực (vnp_ExpireDate)
+	PaymentTimeout time.Duration `mapstructure:"payment_timeout"`
+}
+
 type KafkaConfig struct {
 	Brokers []string `mapstructure:"brokers"`
 }
@@ -117,10 +137,22 @@ func Load() (*Config, error) {
 			ApiRefundEndpoint:    getEnv("MOMO_API_REFUND_ENDPOINT", "https://test-payment.momo.vn/v2/gateway/api/refund"),
 			ApiGetStatusEndpoint: getEnv("MOMO_API_GET_STATUS_ENDPOINT", "https://test-payment.momo.vn/v2/gateway/api/query"),
 		},
+		VNPay: VNPayConfig{
+			TmnCode:        getEnv("VNPAY_TMN_CODE", ""),
+			HashSecret:     getEnv("VNPAY_HASH_SECRET", ""),
+			PayURL:         getEnv("VNPAY_PAY_URL", "https://sandbox.vnpayment.vn/paymentv2/vpcpay.html"),
+			ApiEndpoint:    getEnv("VNPAY_API_ENDPOINT", "https://sandbox.vnpayment.vn/merchant_webapi/api/transaction"),
+			ServerIP:       getEnv("VNPAY_SERVER_IP", "127.0.0.1"),
+			PaymentTimeout: getDurationEnv("VNPAY_PAYMENT_TIMEOUT", 15*time.Minute),
+		},
 		OrderGrpcConfig: GrpcConfig{
 			OrderServiceHost: getEnv("ORDER_SERVICE_GRPC_HOST", "localhost"),
 			OrderServicePort: getIntEnv("ORDER_SERVICE_GRPC_PORT", 50054),
 		},
+		GRPC: GrpcServerConfig{
+			ServiceHost: getEnv("PAYMENT_SERVICE_GRPC_HOST", "localhost"),
+			ServicePort: getIntEnv("PAYMENT_SERVICE_GRPC_PORT", 50055),
+		},
 		Kafka: KafkaConfig{
 			Brokers: getSliceEnv("KAFKA_BROKERS", []string{"localhost:9092"}),
 		},
@@ -3,7 +3,10 @@ package constant
 type PaymentProviderMethod string
 
 const (
-	MomoProviderMethod PaymentProviderMethod = "MOMO"
+	MomoProviderMethod  PaymentProviderMethod = "MOMO"
+	VNPayProviderMethod PaymentProviderMethod = "VNPAY"
+	// Thanh toán khi nhận hàng, không qua cổng thanh toán nên factory không có provider tương ứng
+	CODProviderMethod PaymentProviderMethod = "COD"
 )
 
 type PaymentStatus string
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reference_id định danh một yêu cầu hoàn tiền một phần (ví dụ một yêu cầu trả hàng),
+-- NULL với yêu cầu hoàn toàn bộ đơn hàng.
+ALTER TABLE refund_payments ADD COLUMN reference_id VARCHAR(255);
+
+CREATE UNIQUE INDEX uq_refund_payments_payment_reference
+    ON refund_payments (payment_id, reference_id)
+    WHERE reference_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS uq_refund_payments_payment_reference;
+ALTER TABLE refund_payments DROP COLUMN IF EXISTS reference_id;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- currency của yêu cầu hoàn tiền luôn trùng với payment gốc, lưu lại để đọc số tiền hoàn
+-- theo đúng đơn vị nhỏ nhất mà không phải join payments.
+ALTER TABLE refund_payments ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'VND';
+
+UPDATE refund_payments r
+SET currency = p.currency
+FROM payments p
+WHERE p.id = r.payment_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refund_payments DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
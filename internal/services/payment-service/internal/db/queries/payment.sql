@@ -1,4 +1,6 @@
 -- name: CreatePayment :one
+-- Payment FAILED của đơn được khởi tạo lại thành lần thanh toán mới (đơn quay lại PENDING_PAYMENT sau PAYMENT_FAILED).
+-- Đơn đã có payment ở trạng thái khác thì không có dòng nào được trả về.
 INSERT INTO payments (
     order_id,
     user_id,
@@ -10,7 +12,22 @@ INSERT INTO payments (
     request_id
 ) VALUES (
     $1, $2, $3, $4, $5, $6, 'PENDING', $7
-) RETURNING *;
+)
+ON CONFLICT (order_id) DO UPDATE
+SET
+    user_id = EXCLUDED.user_id,
+    amount = EXCLUDED.amount,
+    currency = EXCLUDED.currency,
+    payment_method = EXCLUDED.payment_method,
+    payment_provider = EXCLUDED.payment_provider,
+    payment_status = 'PENDING',
+    request_id = EXCLUDED.request_id,
+    provider_transaction_id = NULL,
+    provider_refund_id = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+WHERE payments.payment_status = 'FAILED'
+RETURNING *;
 
 -- name: UpdatePaymentStatus :one
 UPDATE payments
@@ -21,10 +38,30 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdatePendingPaymentStatus :one
+-- Chỉ cập nhật payment còn PENDING, kết quả từ cổng thanh toán không ghi đè payment vừa bị huỷ.
+UPDATE payments
+SET
+    payment_status = $2,
+    provider_transaction_id = $3,
+    updated_at = NOW()
+WHERE id = $1 AND payment_status = 'PENDING'
+RETURNING *;
+
 -- name: GetPaymentByOrderID :one
 SELECT * FROM payments
 WHERE order_id = $1;
 
+-- name: GetPaymentByIDForUpdate :one
+-- Khoá payment để tính số tiền còn hoàn được và tạo yêu cầu hoàn trong cùng một transaction.
+SELECT * FROM payments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetPaymentsByOrderIDs :many
+SELECT * FROM payments
+WHERE order_id = ANY(@order_ids::uuid[]);
+
 -- name: UpdatePaymentProviderRefundID :one
 UPDATE payments
 SET
@@ -34,5 +71,26 @@ WHERE id = $1
 RETURNING *;
 
 -- name: GetBatchPendingPayments :many
+-- Payment COD chờ shipper thu tiền, không có cổng thanh toán nào để hỏi trạng thái.
 SELECT * FROM payments
-WHERE payment_status = 'PENDING' AND created_at < NOW() - INTERVAL '15 minutes';
\ No newline at end of file
+WHERE payment_status = 'PENDING' AND payment_method <> 'COD' AND created_at < NOW() - INTERVAL '15 minutes';
+
+-- name: MarkCODPaymentCollected :one
+-- Số tiền thực thu có thể nhỏ hơn số tiền lúc đặt khi người bán đã huỷ một số dòng hàng.
+UPDATE payments
+SET
+    payment_status = 'SUCCESS',
+    amount = @amount,
+    provider_transaction_id = @collection_id,
+    updated_at = NOW()
+WHERE id = @id AND payment_method = 'COD' AND payment_status = 'PENDING'
+RETURNING *;
+
+-- name: FailPendingPayment :one
+-- Đơn bị huỷ trước khi khách thanh toán (đơn COD chưa thu tiền hoặc đơn hết hạn thanh toán), payment không còn gì để thu.
+UPDATE payments
+SET
+    payment_status = 'FAILED',
+    updated_at = NOW()
+WHERE id = @id AND payment_status = 'PENDING'
+RETURNING *;
@@ -5,16 +5,40 @@ INSERT INTO refund_payments (
     amount,
     reason,
     provider_refund_id,
-    refund_status
+    refund_status,
+    reference_id,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetRefundPaymentByID :one
 SELECT * FROM refund_payments WHERE id = $1;
 
+-- name: GetRefundPaymentByPaymentID :one
+-- Yêu cầu hoàn toàn bộ gần nhất của payment (không tính các lần hoàn một phần).
+SELECT * FROM refund_payments
+WHERE payment_id = $1 AND reference_id IS NULL
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetRefundPaymentByReference :one
+SELECT * FROM refund_payments
+WHERE payment_id = $1 AND reference_id = $2;
+
+-- name: SumActiveRefundAmountByPaymentID :one
+-- Tổng tiền đã hoặc đang được hoàn (không tính các yêu cầu thất bại).
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(12, 2) AS total_amount
+FROM refund_payments
+WHERE payment_id = $1 AND refund_status <> 'FAILED';
+
+-- name: SumCompletedRefundAmountByPaymentID :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(12, 2) AS total_amount
+FROM refund_payments
+WHERE payment_id = $1 AND refund_status = 'COMPLETED';
+
 -- name: UpdateRefundPaymentStatus :one
 UPDATE refund_payments SET refund_status = $2 WHERE id = $1 RETURNING *;
 
 -- name: GetBatchRefundPaymentsByStatus :many
-SELECT * FROM refund_payments WHERE refund_status = $1;
\ No newline at end of file
+SELECT * FROM refund_payments WHERE refund_status = $1;
@@ -225,4 +225,6 @@ type RefundPayment struct {
 	RefundStatus     RefundStatus       `json:"refund_status"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	ReferenceID      pgtype.Text        `json:"reference_id"`
+	Currency         string             `json:"currency"`
 }
@@ -23,7 +23,22 @@ INSERT INTO payments (
     request_id
 ) VALUES (
     $1, $2, $3, $4, $5, $6, 'PENDING', $7
-) RETURNING id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id
+)
+ON CONFLICT (order_id) DO UPDATE
+SET
+    user_id = EXCLUDED.user_id,
+    amount = EXCLUDED.amount,
+    currency = EXCLUDED.currency,
+    payment_method = EXCLUDED.payment_method,
+    payment_provider = EXCLUDED.payment_provider,
+    payment_status = 'PENDING',
+    request_id = EXCLUDED.request_id,
+    provider_transaction_id = NULL,
+    provider_refund_id = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+WHERE payments.payment_status = 'FAILED'
+RETURNING id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id
 `
 
 type CreatePaymentParams struct {
@@ -36,6 +51,8 @@ type CreatePaymentParams struct {
 	RequestID       pgtype.Text    `json:"request_id"`
 }
 
+// Payment FAILED của đơn được khởi tạo lại thành lần thanh toán mới (đơn quay lại PENDING_PAYMENT sau PAYMENT_FAILED).
+// Đơn đã có payment ở trạng thái khác thì không có dòng nào được trả về.
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
 	row := q.db.QueryRow(ctx, createPayment,
 		arg.OrderID,
@@ -65,11 +82,43 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 	return i, err
 }
 
+const failPendingPayment = `-- name: FailPendingPayment :one
+UPDATE payments
+SET
+    payment_status = 'FAILED',
+    updated_at = NOW()
+WHERE id = $1 AND payment_status = 'PENDING'
+RETURNING id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id
+`
+
+// Đơn bị huỷ trước khi khách thanh toán (đơn COD chưa thu tiền hoặc đơn hết hạn thanh toán), payment không còn gì để thu.
+func (q *Queries) FailPendingPayment(ctx context.Context, id pgtype.UUID) (Payment, error) {
+	row := q.db.QueryRow(ctx, failPendingPayment, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.PaymentMethod,
+		&i.PaymentProvider,
+		&i.ProviderTransactionID,
+		&i.PaymentStatus,
+		&i.RequestID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProviderRefundID,
+	)
+	return i, err
+}
+
 const getBatchPendingPayments = `-- name: GetBatchPendingPayments :many
 SELECT id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id FROM payments
-WHERE payment_status = 'PENDING' AND created_at < NOW() - INTERVAL '15 minutes'
+WHERE payment_status = 'PENDING' AND payment_method <> 'COD' AND created_at < NOW() - INTERVAL '15 minutes'
 `
 
+// Payment COD chờ shipper thu tiền, không có cổng thanh toán nào để hỏi trạng thái.
 func (q *Queries) GetBatchPendingPayments(ctx context.Context) ([]Payment, error) {
 	rows, err := q.db.Query(ctx, getBatchPendingPayments)
 	if err != nil {
@@ -104,6 +153,34 @@ func (q *Queries) GetBatchPendingPayments(ctx context.Context) ([]Payment, error
 	return items, nil
 }
 
+const getPaymentByIDForUpdate = `-- name: GetPaymentByIDForUpdate :one
+SELECT id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id FROM payments
+WHERE id = $1
+FOR UPDATE
+`
+
+// Khoá payment để tính số tiền còn hoàn được và tạo yêu cầu hoàn trong cùng một transaction.
+func (q *Queries) GetPaymentByIDForUpdate(ctx context.Context, id pgtype.UUID) (Payment, error) {
+	row := q.db.QueryRow(ctx, getPaymentByIDForUpdate, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.PaymentMethod,
+		&i.PaymentProvider,
+		&i.ProviderTransactionID,
+		&i.PaymentStatus,
+		&i.RequestID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProviderRefundID,
+	)
+	return i, err
+}
+
 const getPaymentByOrderID = `-- name: GetPaymentByOrderID :one
 SELECT id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id FROM payments
 WHERE order_id = $1
@@ -130,6 +207,84 @@ func (q *Queries) GetPaymentByOrderID(ctx context.Context, orderID pgtype.UUID)
 	return i, err
 }
 
+const getPaymentsByOrderIDs = `-- name: GetPaymentsByOrderIDs :many
+SELECT id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id FROM payments
+WHERE order_id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetPaymentsByOrderIDs(ctx context.Context, orderIds []pgtype.UUID) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, getPaymentsByOrderIDs, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.UserID,
+			&i.Amount,
+			&i.Currency,
+			&i.PaymentMethod,
+			&i.PaymentProvider,
+			&i.ProviderTransactionID,
+			&i.PaymentStatus,
+			&i.RequestID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProviderRefundID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markCODPaymentCollected = `-- name: MarkCODPaymentCollected :one
+UPDATE payments
+SET
+    payment_status = 'SUCCESS',
+    amount = $1,
+    provider_transaction_id = $2,
+    updated_at = NOW()
+WHERE id = $3 AND payment_method = 'COD' AND payment_status = 'PENDING'
+RETURNING id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id
+`
+
+type MarkCODPaymentCollectedParams struct {
+	Amount       pgtype.Numeric `json:"amount"`
+	CollectionID pgtype.Text    `json:"collection_id"`
+	ID           pgtype.UUID    `json:"id"`
+}
+
+// Số tiền thực thu có thể nhỏ hơn số tiền lúc đặt khi người bán đã huỷ một số dòng hàng.
+func (q *Queries) MarkCODPaymentCollected(ctx context.Context, arg MarkCODPaymentCollectedParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, markCODPaymentCollected, arg.Amount, arg.CollectionID, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.PaymentMethod,
+		&i.PaymentProvider,
+		&i.ProviderTransactionID,
+		&i.PaymentStatus,
+		&i.RequestID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProviderRefundID,
+	)
+	return i, err
+}
+
 const updatePaymentProviderRefundID = `-- name: UpdatePaymentProviderRefundID :one
 UPDATE payments
 SET
@@ -201,3 +356,41 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStat
 	)
 	return i, err
 }
+
+const updatePendingPaymentStatus = `-- name: UpdatePendingPaymentStatus :one
+UPDATE payments
+SET
+    payment_status = $2,
+    provider_transaction_id = $3,
+    updated_at = NOW()
+WHERE id = $1 AND payment_status = 'PENDING'
+RETURNING id, order_id, user_id, amount, currency, payment_method, payment_provider, provider_transaction_id, payment_status, request_id, created_at, updated_at, provider_refund_id
+`
+
+type UpdatePendingPaymentStatusParams struct {
+	ID                    pgtype.UUID   `json:"id"`
+	PaymentStatus         PaymentStatus `json:"payment_status"`
+	ProviderTransactionID pgtype.Text   `json:"provider_transaction_id"`
+}
+
+// Chỉ cập nhật payment còn PENDING, kết quả từ cổng thanh toán không ghi đè payment vừa bị huỷ.
+func (q *Queries) UpdatePendingPaymentStatus(ctx context.Context, arg UpdatePendingPaymentStatusParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, updatePendingPaymentStatus, arg.ID, arg.PaymentStatus, arg.ProviderTransactionID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Currency,
+		&i.PaymentMethod,
+		&i.PaymentProvider,
+		&i.ProviderTransactionID,
+		&i.PaymentStatus,
+		&i.RequestID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProviderRefundID,
+	)
+	return i, err
+}
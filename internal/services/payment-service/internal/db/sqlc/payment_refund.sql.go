@@ -18,10 +18,12 @@ INSERT INTO refund_payments (
     amount,
     reason,
     provider_refund_id,
-    refund_status
+    refund_status,
+    reference_id,
+    currency
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency
 `
 
 type CreateRefundPaymentParams struct {
@@ -31,6 +33,8 @@ type CreateRefundPaymentParams struct {
 	Reason           pgtype.Text    `json:"reason"`
 	ProviderRefundID pgtype.Text    `json:"provider_refund_id"`
 	RefundStatus     RefundStatus   `json:"refund_status"`
+	ReferenceID      pgtype.Text    `json:"reference_id"`
+	Currency         string         `json:"currency"`
 }
 
 func (q *Queries) CreateRefundPayment(ctx context.Context, arg CreateRefundPaymentParams) (RefundPayment, error) {
@@ -41,6 +45,8 @@ func (q *Queries) CreateRefundPayment(ctx context.Context, arg CreateRefundPayme
 		arg.Reason,
 		arg.ProviderRefundID,
 		arg.RefundStatus,
+		arg.ReferenceID,
+		arg.Currency,
 	)
 	var i RefundPayment
 	err := row.Scan(
@@ -53,12 +59,14 @@ func (q *Queries) CreateRefundPayment(ctx context.Context, arg CreateRefundPayme
 		&i.RefundStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReferenceID,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getBatchRefundPaymentsByStatus = `-- name: GetBatchRefundPaymentsByStatus :many
-SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at FROM refund_payments WHERE refund_status = $1
+SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency FROM refund_payments WHERE refund_status = $1
 `
 
 func (q *Queries) GetBatchRefundPaymentsByStatus(ctx context.Context, refundStatus RefundStatus) ([]RefundPayment, error) {
@@ -80,6 +88,8 @@ func (q *Queries) GetBatchRefundPaymentsByStatus(ctx context.Context, refundStat
 			&i.RefundStatus,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ReferenceID,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -92,7 +102,7 @@ func (q *Queries) GetBatchRefundPaymentsByStatus(ctx context.Context, refundStat
 }
 
 const getRefundPaymentByID = `-- name: GetRefundPaymentByID :one
-SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at FROM refund_payments WHERE id = $1
+SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency FROM refund_payments WHERE id = $1
 `
 
 func (q *Queries) GetRefundPaymentByID(ctx context.Context, id pgtype.UUID) (RefundPayment, error) {
@@ -108,12 +118,97 @@ func (q *Queries) GetRefundPaymentByID(ctx context.Context, id pgtype.UUID) (Ref
 		&i.RefundStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReferenceID,
+		&i.Currency,
 	)
 	return i, err
 }
 
+const getRefundPaymentByPaymentID = `-- name: GetRefundPaymentByPaymentID :one
+SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency FROM refund_payments
+WHERE payment_id = $1 AND reference_id IS NULL
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+// Yêu cầu hoàn toàn bộ gần nhất của payment (không tính các lần hoàn một phần).
+func (q *Queries) GetRefundPaymentByPaymentID(ctx context.Context, paymentID pgtype.UUID) (RefundPayment, error) {
+	row := q.db.QueryRow(ctx, getRefundPaymentByPaymentID, paymentID)
+	var i RefundPayment
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.OrderID,
+		&i.Amount,
+		&i.Reason,
+		&i.ProviderRefundID,
+		&i.RefundStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferenceID,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getRefundPaymentByReference = `-- name: GetRefundPaymentByReference :one
+SELECT id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency FROM refund_payments
+WHERE payment_id = $1 AND reference_id = $2
+`
+
+type GetRefundPaymentByReferenceParams struct {
+	PaymentID   pgtype.UUID `json:"payment_id"`
+	ReferenceID pgtype.Text `json:"reference_id"`
+}
+
+func (q *Queries) GetRefundPaymentByReference(ctx context.Context, arg GetRefundPaymentByReferenceParams) (RefundPayment, error) {
+	row := q.db.QueryRow(ctx, getRefundPaymentByReference, arg.PaymentID, arg.ReferenceID)
+	var i RefundPayment
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.OrderID,
+		&i.Amount,
+		&i.Reason,
+		&i.ProviderRefundID,
+		&i.RefundStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReferenceID,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const sumActiveRefundAmountByPaymentID = `-- name: SumActiveRefundAmountByPaymentID :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(12, 2) AS total_amount
+FROM refund_payments
+WHERE payment_id = $1 AND refund_status <> 'FAILED'
+`
+
+// Tổng tiền đã hoặc đang được hoàn (không tính các yêu cầu thất bại).
+func (q *Queries) SumActiveRefundAmountByPaymentID(ctx context.Context, paymentID pgtype.UUID) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, sumActiveRefundAmountByPaymentID, paymentID)
+	var total_amount pgtype.Numeric
+	err := row.Scan(&total_amount)
+	return total_amount, err
+}
+
+const sumCompletedRefundAmountByPaymentID = `-- name: SumCompletedRefundAmountByPaymentID :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(12, 2) AS total_amount
+FROM refund_payments
+WHERE payment_id = $1 AND refund_status = 'COMPLETED'
+`
+
+func (q *Queries) SumCompletedRefundAmountByPaymentID(ctx context.Context, paymentID pgtype.UUID) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, sumCompletedRefundAmountByPaymentID, paymentID)
+	var total_amount pgtype.Numeric
+	err := row.Scan(&total_amount)
+	return total_amount, err
+}
+
 const updateRefundPaymentStatus = `-- name: UpdateRefundPaymentStatus :one
-UPDATE refund_payments SET refund_status = $2 WHERE id = $1 RETURNING id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at
+UPDATE refund_payments SET refund_status = $2 WHERE id = $1 RETURNING id, payment_id, order_id, amount, reason, provider_refund_id, refund_status, created_at, updated_at, reference_id, currency
 `
 
 type UpdateRefundPaymentStatusParams struct {
@@ -134,6 +229,8 @@ func (q *Queries) UpdateRefundPaymentStatus(ctx context.Context, arg UpdateRefun
 		&i.RefundStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ReferenceID,
+		&i.Currency,
 	)
 	return i, err
 }
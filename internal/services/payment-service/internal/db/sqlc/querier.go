@@ -11,17 +11,35 @@ import (
 )
 
 type Querier interface {
+	// Payment FAILED của đơn được khởi tạo lại thành lần thanh toán mới (đơn quay lại PENDING_PAYMENT sau PAYMENT_FAILED).
+	// Đơn đã có payment ở trạng thái khác thì không có dòng nào được trả về.
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
 	CreatePaymentEvent(ctx context.Context, arg CreatePaymentEventParams) (PaymentOutboxEvent, error)
 	CreateRefundPayment(ctx context.Context, arg CreateRefundPaymentParams) (RefundPayment, error)
+	// Đơn bị huỷ trước khi khách thanh toán (đơn COD chưa thu tiền hoặc đơn hết hạn thanh toán), payment không còn gì để thu.
+	FailPendingPayment(ctx context.Context, id pgtype.UUID) (Payment, error)
 	GetBatchPaymentEventsByEventTypeAndStatus(ctx context.Context, arg GetBatchPaymentEventsByEventTypeAndStatusParams) ([]PaymentOutboxEvent, error)
+	// Payment COD chờ shipper thu tiền, không có cổng thanh toán nào để hỏi trạng thái.
 	GetBatchPendingPayments(ctx context.Context) ([]Payment, error)
 	GetBatchRefundPaymentsByStatus(ctx context.Context, refundStatus RefundStatus) ([]RefundPayment, error)
+	// Khoá payment để tính số tiền còn hoàn được và tạo yêu cầu hoàn trong cùng một transaction.
+	GetPaymentByIDForUpdate(ctx context.Context, id pgtype.UUID) (Payment, error)
 	GetPaymentByOrderID(ctx context.Context, orderID pgtype.UUID) (Payment, error)
+	GetPaymentsByOrderIDs(ctx context.Context, orderIds []pgtype.UUID) ([]Payment, error)
 	GetRefundPaymentByID(ctx context.Context, id pgtype.UUID) (RefundPayment, error)
+	// Yêu cầu hoàn toàn bộ gần nhất của payment (không tính các lần hoàn một phần).
+	GetRefundPaymentByPaymentID(ctx context.Context, paymentID pgtype.UUID) (RefundPayment, error)
+	GetRefundPaymentByReference(ctx context.Context, arg GetRefundPaymentByReferenceParams) (RefundPayment, error)
+	// Số tiền thực thu có thể nhỏ hơn số tiền lúc đặt khi người bán đã huỷ một số dòng hàng.
+	MarkCODPaymentCollected(ctx context.Context, arg MarkCODPaymentCollectedParams) (Payment, error)
+	// Tổng tiền đã hoặc đang được hoàn (không tính các yêu cầu thất bại).
+	SumActiveRefundAmountByPaymentID(ctx context.Context, paymentID pgtype.UUID) (pgtype.Numeric, error)
+	SumCompletedRefundAmountByPaymentID(ctx context.Context, paymentID pgtype.UUID) (pgtype.Numeric, error)
 	UpdatePaymentEvent(ctx context.Context, arg UpdatePaymentEventParams) (PaymentOutboxEvent, error)
 	UpdatePaymentProviderRefundID(ctx context.Context, arg UpdatePaymentProviderRefundIDParams) (Payment, error)
 	UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStatusParams) (Payment, error)
+	// Chỉ cập nhật payment còn PENDING, kết quả từ cổng thanh toán không ghi đè payment vừa bị huỷ.
+	UpdatePendingPaymentStatus(ctx context.Context, arg UpdatePendingPaymentStatusParams) (Payment, error)
 	UpdateRefundPaymentStatus(ctx context.Context, arg UpdateRefundPaymentStatusParams) (RefundPayment, error)
 }
 
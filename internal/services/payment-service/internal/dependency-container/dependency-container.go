@@ -6,6 +6,7 @@ import (
 
 	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	redis_infra "github.com/toji-dev/go-shop/internal/pkg/infra/redis-infra"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
 	grpc_adapter "github.com/toji-dev/go-shop/internal/services/payment-service/internal/grpc/adapter"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/handler"
@@ -17,6 +18,7 @@ import (
 type DependencyContainer struct {
 	config           *config.Config
 	postgreSQL       *postgresql_infra.PostgreSQLService
+	redis            *redis_infra.RedisService
 	paymentRepo      repository.PaymentRepository
 	paymentEventRepo repository.PaymentEventRepository
 
@@ -41,6 +43,10 @@ func NewDependencyContainer(cfg *config.Config) *DependencyContainer {
 		log.Fatalf("failed to initialize PostgreSQL: %v", err)
 	}
 
+	if err := container.initRedis(); err != nil {
+		log.Fatalf("failed to initialize Redis: %v", err)
+	}
+
 	container.initKafkaProducer()
 
 	container.initRepositories()
@@ -61,6 +67,18 @@ func (sc *DependencyContainer) initKafkaProducer() {
 	log.Println("Kafka producer initialized")
 }
 
+func (sc *DependencyContainer) initRedis() error {
+	redisService := redis_infra.NewRedisService(sc.config.Redis.Host, sc.config.Redis.Port, sc.config.Redis.Password, sc.config.Redis.DB)
+
+	if err := redisService.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	sc.redis = redisService
+	log.Println("Redis service initialized")
+	return nil
+}
+
 func (sc *DependencyContainer) initPostgreSQL() error {
 	pgConfig := &postgresql_infra.DatabaseConfig{
 		Host:         sc.config.Database.Host,
@@ -96,6 +114,9 @@ func (sc *DependencyContainer) initPaymentProviders() {
 
 	momoProvider := paymentprovider.NewMomoProvider(sc.config.Momo)
 	sc.paymentMethodFactory.RegisterProvider(momoProvider)
+
+	vnpayProvider := paymentprovider.NewVNPayProvider(sc.config.VNPay)
+	sc.paymentMethodFactory.RegisterProvider(vnpayProvider)
 	log.Println("Payment provider factory initialized")
 }
 
@@ -142,6 +163,10 @@ func (sc *DependencyContainer) GetConfig() *config.Config {
 	return sc.config
 }
 
+func (sc *DependencyContainer) GetRedisService() *redis_infra.RedisService {
+	return sc.redis
+}
+
 func (sc *DependencyContainer) GetPaymentRepository() repository.PaymentRepository {
 	return sc.paymentRepo
 }
@@ -3,6 +3,7 @@ package domain
 import (
 	"time"
 
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	. "github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 )
 
@@ -10,8 +11,7 @@ type Payment struct {
 	ID                    string        `json:"id"`
 	OrderID               string        `json:"order_id"`
 	UserID                string        `json:"user_id"`
-	Amount                float64       `json:"amount"`
-	Currency              string        `json:"currency"`
+	Amount                money.Money   `json:"amount"`
 	Method                PaymentMethod `json:"payment_method"`
 	Provider              string        `json:"payment_provider"`
 	ProviderTransactionID *string       `json:"provider_transaction_id"`
@@ -25,9 +25,10 @@ type PaymentRefund struct {
 	ID                string       `json:"id"`
 	PaymentID         string       `json:"payment_id"`
 	OrderID           string       `json:"order_id"`
-	Amount            float64      `json:"amount"`
+	Amount            money.Money  `json:"amount"`
 	Reason            string       `json:"reason"`
 	ProviderPaymentID *string      `json:"provider_refund_id"`
+	ReferenceID       *string      `json:"reference_id,omitempty"` // nil với yêu cầu hoàn toàn bộ
 	RefundStatus      RefundStatus `json:"refund_status"`
 	CreatedAt         time.Time    `json:"created_at"`
 	UpdatedAt         time.Time    `json:"updated_at"`
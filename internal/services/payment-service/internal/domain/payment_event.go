@@ -17,6 +17,8 @@ const (
 	PaymentEventTypePaymentFailed   PaymentEventType = "PAYMENT_FAILED"
 	PaymentEventTypeRefundRequested PaymentEventType = "REFUND_REQUESTED"
 	PaymentEventTypeRefundSuccessed PaymentEventType = "REFUND_SUCCEEDED"
+	// Khách chọn COD: đơn chuyển sang PROCESSING trong khi payment vẫn PENDING tới khi shipper thu tiền
+	PaymentEventTypeCODPlaced PaymentEventType = "COD_PLACED"
 )
 
 type PaymentEvent struct {
@@ -30,3 +32,13 @@ type PaymentEvent struct {
 	CreatedAt   time.Time          `json:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at"`
 }
+
+// RefundSucceededPayload là payload của event REFUND_SUCCEEDED.
+// Partial = true khi payment vẫn còn tiền chưa được hoàn (ví dụ khách chỉ trả lại một số sản phẩm).
+type RefundSucceededPayload struct {
+	RefundID    string  `json:"refund_id"`
+	ReferenceID *string `json:"reference_id,omitempty"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency,omitempty"`
+	Partial     bool    `json:"partial"`
+}
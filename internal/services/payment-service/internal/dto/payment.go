@@ -1,8 +1,11 @@
 package dto
 
+import "github.com/toji-dev/go-shop/internal/pkg/money"
+
 type InitiatePaymentRequest struct {
 	OrderID       string `json:"order_id" binding:"required,uuid"`
 	PaymentMethod string `json:"payment_method" binding:"required,oneof=MOMO VNPAY COD"`
+	ClientIP      string `json:"-"` // Lấy từ request, VNPay yêu cầu IP của khách
 }
 
 type InitiatePaymentResponse struct {
@@ -11,6 +14,13 @@ type InitiatePaymentResponse struct {
 	Message   string `json:"message"`
 }
 
+type PaymentReturnResponse struct {
+	PaymentID     string `json:"payment_id"`
+	OrderID       string `json:"order_id"`
+	ResultStatus  string `json:"result_status"`  // Kết quả cổng thanh toán gắn vào return URL
+	PaymentStatus string `json:"payment_status"` // Trạng thái hiện tại, chỉ đổi khi nhận IPN
+}
+
 type MomoIPNRequest struct {
 	PartnerCode  string `json:"partnerCode"`
 	OrderID      string `json:"orderId"`
@@ -34,6 +44,8 @@ type PaymentRefundRequest struct {
 }
 
 type RefundResult struct {
-	Status  string `json:"status"`            // e.g., "COMPLETED", "FAILED"
-	Message string `json:"message,omitempty"` // Optional message for additional context
+	RefundID string      `json:"refund_id,omitempty"`
+	Status   string      `json:"status"`            // e.g., "COMPLETED", "FAILED"
+	Amount   money.Money `json:"amount"`            // Số tiền được hoàn của yêu cầu này
+	Message  string      `json:"message,omitempty"` // Optional message for additional context
 }
@@ -0,0 +1,255 @@
+package grpc_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/dto"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/usecase"
+	payment_v1 "github.com/toji-dev/go-shop/proto/gen/go/payment/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MAX_ORDERS_PER_LOOKUP giới hạn số đơn hàng trong một lần gọi GetPaymentsByOrders.
+const MAX_ORDERS_PER_LOOKUP = 100
+
+type Server struct {
+	payment_v1.UnimplementedPaymentServiceServer
+	paymentRepo    repository.PaymentRepository
+	paymentUseCase usecase.PaymentUseCase
+}
+
+func NewPaymentGRPCServer(paymentRepo repository.PaymentRepository, paymentUseCase usecase.PaymentUseCase) *Server {
+	return &Server{
+		paymentRepo:    paymentRepo,
+		paymentUseCase: paymentUseCase,
+	}
+}
+
+func (s *Server) GetPaymentByOrder(ctx context.Context, in *payment_v1.GetPaymentByOrderRequest) (*payment_v1.GetPaymentByOrderResponse, error) {
+	orderID := in.GetOrderId()
+
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &payment_v1.GetPaymentByOrderResponse{Exists: false}, nil
+		}
+		log.Printf("Error retrieving payment of order %s: %v", orderID, err)
+		return nil, status.Errorf(codes.Internal, "failed to get payment of order %s: %v", orderID, err)
+	}
+
+	return &payment_v1.GetPaymentByOrderResponse{
+		Exists:  true,
+		Payment: toProtoPayment(payment),
+	}, nil
+}
+
+// GetPaymentsByOrders trả về payment của nhiều đơn hàng, dùng cho việc đối soát trạng thái đơn hàng với thanh toán.
+func (s *Server) GetPaymentsByOrders(ctx context.Context, in *payment_v1.GetPaymentsByOrdersRequest) (*payment_v1.GetPaymentsByOrdersResponse, error) {
+	orderIDs := in.GetOrderIds()
+	if len(orderIDs) > MAX_ORDERS_PER_LOOKUP {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d orders can be looked up at once, got %d", MAX_ORDERS_PER_LOOKUP, len(orderIDs))
+	}
+	for _, orderID := range orderIDs {
+		if _, err := uuid.Parse(orderID); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid order id %q", orderID)
+		}
+	}
+	if len(orderIDs) == 0 {
+		return &payment_v1.GetPaymentsByOrdersResponse{}, nil
+	}
+
+	payments, err := s.paymentRepo.GetPaymentsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		log.Printf("Error retrieving payments of %d orders: %v", len(orderIDs), err)
+		return nil, status.Errorf(codes.Internal, "failed to get payments of orders: %v", err)
+	}
+
+	resp := &payment_v1.GetPaymentsByOrdersResponse{
+		Payments: make([]*payment_v1.Payment, len(payments)),
+	}
+	for i := range payments {
+		resp.Payments[i] = toProtoPayment(&payments[i])
+	}
+	return resp, nil
+}
+
+func (s *Server) RequestRefund(ctx context.Context, in *payment_v1.RequestRefundRequest) (*payment_v1.RequestRefundResponse, error) {
+	orderID := in.GetOrderId()
+
+	var (
+		result *dto.RefundResult
+		err    error
+	)
+	amount, partial, err := s.requestedRefundAmount(ctx, in)
+	if err == nil {
+		if partial {
+			result, err = s.paymentUseCase.RequestPartialRefund(ctx, orderID, in.GetReferenceId(), amount, in.GetReason())
+		} else {
+			result, err = s.paymentUseCase.Refund(ctx, "", orderID, in.GetReason())
+		}
+	}
+	if err != nil {
+		log.Printf("Error requesting refund for order %s: %v", orderID, err)
+		return &payment_v1.RequestRefundResponse{
+			Accepted: false,
+			Message:  err.Error(),
+		}, nil
+	}
+
+	return &payment_v1.RequestRefundResponse{
+		Accepted:     true,
+		RefundId:     result.RefundID,
+		RefundStatus: result.Status,
+		Message:      result.Message,
+		Amount:       result.Amount.Float64(),
+		RefundAmount: result.Amount.ToProto(),
+	}, nil
+}
+
+// ConfirmCashCollected được order-service gọi khi shipper xác nhận đã thu tiền mặt của đơn COD.
+func (s *Server) ConfirmCashCollected(ctx context.Context, in *payment_v1.ConfirmCashCollectedRequest) (*payment_v1.ConfirmCashCollectedResponse, error) {
+	orderID := in.GetOrderId()
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order id %q", orderID)
+	}
+	if in.GetCollectionId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "collection_id is required")
+	}
+	amount, ok := money.FromProto(in.GetCollectedAmount())
+	if !ok || !amount.IsPositive() {
+		return nil, status.Error(codes.InvalidArgument, "collected_amount must be greater than 0")
+	}
+
+	payment, alreadyConfirmed, err := s.paymentUseCase.ConfirmCashCollected(ctx, orderID, in.GetCollectionId(), amount)
+	if err != nil {
+		log.Printf("Error confirming cash collection of order %s: %v", orderID, err)
+		switch {
+		case errors.Is(err, usecase.ErrPaymentNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, usecase.ErrAmountMismatch):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, usecase.ErrNotCODPayment), errors.Is(err, usecase.ErrPaymentNotCollectable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to confirm cash collection of order %s: %v", orderID, err)
+		}
+	}
+
+	return &payment_v1.ConfirmCashCollectedResponse{
+		Payment:          toProtoPayment(payment),
+		AlreadyConfirmed: alreadyConfirmed,
+	}, nil
+}
+
+// CancelCODPayment được order-service gọi khi đơn COD bị huỷ trước khi shipper thu tiền.
+func (s *Server) CancelCODPayment(ctx context.Context, in *payment_v1.CancelCODPaymentRequest) (*payment_v1.CancelCODPaymentResponse, error) {
+	orderID := in.GetOrderId()
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order id %q", orderID)
+	}
+
+	payment, alreadyCanceled, err := s.paymentUseCase.CancelCODPayment(ctx, orderID, in.GetReason())
+	if err != nil {
+		log.Printf("Error canceling COD payment of order %s: %v", orderID, err)
+		switch {
+		case errors.Is(err, usecase.ErrPaymentNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, usecase.ErrNotCODPayment), errors.Is(err, usecase.ErrPaymentNotCancelable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to cancel COD payment of order %s: %v", orderID, err)
+		}
+	}
+
+	return &payment_v1.CancelCODPaymentResponse{
+		Payment:         toProtoPayment(payment),
+		AlreadyCanceled: alreadyCanceled,
+	}, nil
+}
+
+// CancelPendingPayment được order-service gọi trước khi huỷ đơn hết hạn thanh toán.
+func (s *Server) CancelPendingPayment(ctx context.Context, in *payment_v1.CancelPendingPaymentRequest) (*payment_v1.CancelPendingPaymentResponse, error) {
+	orderID := in.GetOrderId()
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order id %q", orderID)
+	}
+
+	payment, alreadyCanceled, err := s.paymentUseCase.CancelPendingPayment(ctx, orderID, in.GetReason())
+	if err != nil {
+		log.Printf("Error canceling pending payment of order %s: %v", orderID, err)
+		switch {
+		case errors.Is(err, usecase.ErrPaymentNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, usecase.ErrPaymentNotCancelable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to cancel pending payment of order %s: %v", orderID, err)
+		}
+	}
+
+	return &payment_v1.CancelPendingPaymentResponse{
+		Payment:         toProtoPayment(payment),
+		AlreadyCanceled: alreadyCanceled,
+	}, nil
+}
+
+// requestedRefundAmount đọc số tiền cần hoàn của yêu cầu, partial = false nghĩa là hoàn toàn bộ.
+// Client cũ chỉ gửi amount (số thực theo tiền tệ của payment) nên cần tiền tệ của payment để quy đổi.
+func (s *Server) requestedRefundAmount(ctx context.Context, in *payment_v1.RequestRefundRequest) (money.Money, bool, error) {
+	if amount, ok := money.FromProto(in.GetRefundAmount()); ok {
+		return amount, amount.IsPositive(), nil
+	}
+	if in.GetAmount() <= 0 {
+		return money.Money{}, false, nil
+	}
+
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, in.GetOrderId())
+	if err != nil {
+		return money.Money{}, false, fmt.Errorf("failed to get payment of order %s: %w", in.GetOrderId(), err)
+	}
+	return money.FromMajor(in.GetAmount(), payment.Amount.Currency), true, nil
+}
+
+func toProtoPayment(payment *domain.Payment) *payment_v1.Payment {
+	return &payment_v1.Payment{
+		Id:            payment.ID,
+		OrderId:       payment.OrderID,
+		UserId:        payment.UserID,
+		Amount:        payment.Amount.Float64(),
+		Currency:      payment.Amount.Currency,
+		Total:         payment.Amount.ToProto(),
+		PaymentMethod: string(payment.Method),
+		Provider:      payment.Provider,
+		Status:        toProtoPaymentStatus(payment.Status),
+		CreatedAt:     payment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     payment.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toProtoPaymentStatus(paymentStatus constant.PaymentStatus) payment_v1.PaymentStatus {
+	switch paymentStatus {
+	case constant.PaymentStatusPending:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_PENDING
+	case constant.PaymentStatusProcessing:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_PROCESSING
+	case constant.PaymentStatusSuccess:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_SUCCESS
+	case constant.PaymentStatusFailed:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_FAILED
+	case constant.PaymentStatusRefunded:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	default:
+		return payment_v1.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"log"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -9,12 +11,14 @@ import (
 	"github.com/toji-dev/go-shop/internal/pkg/response"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/dto"
+	paymentprovider "github.com/toji-dev/go-shop/internal/services/payment-service/internal/payment_provider"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/usecase"
 )
 
 type PaymentHandler interface {
 	InitiatePayment(c *gin.Context)
 	HandleIPN(c *gin.Context)
+	VerifyReturn(c *gin.Context)
 	RefundPayment(c *gin.Context)
 }
 
@@ -33,11 +37,20 @@ func (h *paymentHandler) InitiatePayment(c *gin.Context) {
 		return
 	}
 
+	req.ClientIP = c.ClientIP()
 	userID, _ := c.Get(common_constant.ContextKeyUserID)
 
 	resp, err := h.paymentUseCase.InitiatePayment(c.Request.Context(), userID.(string), req)
 	if err != nil {
 		log.Printf("Error initiating payment: %v", err)
+		switch {
+		case errors.Is(err, paymentprovider.ErrUnsupportedCurrency):
+			response.BadRequest(c, "UNSUPPORTED_CURRENCY", "Payment method does not support the order currency", err.Error())
+			return
+		case errors.Is(err, usecase.ErrPaymentAlreadyExists):
+			response.Conflict(c, "PAYMENT_ALREADY_EXISTS", "Order already has a payment, its payment method cannot be changed")
+			return
+		}
 		response.InternalServerError(c, "PAYMENT_INITIATION_FAILED", err.Error())
 		return
 	}
@@ -55,6 +68,16 @@ func (h *paymentHandler) HandleIPN(c *gin.Context) {
 	provider := constant.PaymentProviderMethod(strings.ToUpper(providerName))
 
 	err := h.paymentUseCase.HandleIPN(c.Request.Context(), provider, c.Request)
+
+	// VNPay chỉ ngừng gửi lại IPN khi nhận được HTTP 200 kèm RspCode
+	if provider == constant.VNPayProviderMethod {
+		if err != nil {
+			log.Printf("Error handling IPN for %s: %v", providerName, err)
+		}
+		c.JSON(http.StatusOK, vnpayIPNAck(err))
+		return
+	}
+
 	if err != nil {
 		log.Printf("Error handling IPN for %s: %v", providerName, err)
 		response.InternalServerError(c, "IPN_HANDLING_FAILED", err.Error())
@@ -63,6 +86,25 @@ func (h *paymentHandler) HandleIPN(c *gin.Context) {
 	response.NoContent(c)
 }
 
+func (h *paymentHandler) VerifyReturn(c *gin.Context) {
+	provider := constant.PaymentProviderMethod(strings.ToUpper(c.Param("provider")))
+
+	resp, err := h.paymentUseCase.VerifyReturn(c.Request.Context(), provider, c.Request.URL.Query())
+	if err != nil {
+		log.Printf("Error verifying payment return for %s: %v", provider, err)
+		switch {
+		case errors.Is(err, paymentprovider.ErrInvalidSignature), errors.Is(err, usecase.ErrAmountMismatch):
+			response.BadRequest(c, "INVALID_PAYMENT_RESULT", "Invalid payment result", err.Error())
+		case errors.Is(err, usecase.ErrPaymentNotFound):
+			response.NotFound(c, "PAYMENT_NOT_FOUND", err.Error())
+		default:
+			response.InternalServerError(c, "PAYMENT_RETURN_VERIFICATION_FAILED", err.Error())
+		}
+		return
+	}
+	response.Success(c, "Payment result verified successfully", resp)
+}
+
 func (h *paymentHandler) RefundPayment(c *gin.Context) {
 	var req dto.PaymentRefundRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -78,3 +120,19 @@ func (h *paymentHandler) RefundPayment(c *gin.Context) {
 	}
 	response.Success(c, "Payment refunded successfully", refundRes)
 }
+
+// vnpayIPNAck chuyển kết quả xử lý IPN sang RspCode theo tài liệu VNPay.
+func vnpayIPNAck(err error) gin.H {
+	switch {
+	case err == nil:
+		return gin.H{"RspCode": "00", "Message": "Confirm Success"}
+	case errors.Is(err, paymentprovider.ErrInvalidSignature):
+		return gin.H{"RspCode": "97", "Message": "Invalid Checksum"}
+	case errors.Is(err, usecase.ErrPaymentNotFound):
+		return gin.H{"RspCode": "01", "Message": "Order not found"}
+	case errors.Is(err, usecase.ErrAmountMismatch):
+		return gin.H{"RspCode": "04", "Message": "Invalid amount"}
+	default:
+		return gin.H{"RspCode": "99", "Message": "Unknown error"}
+	}
+}
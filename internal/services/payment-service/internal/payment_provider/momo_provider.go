@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/db/sqlc"
@@ -22,6 +23,9 @@ import (
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/dto"
 )
 
+// momoCurrency là tiền tệ duy nhất MoMo hỗ trợ, amount của MoMo là số đồng (VND không có đơn vị lẻ).
+const momoCurrency = "VND"
+
 type MomoCreatePaymentRequest struct {
 	PartnerCode string `json:"partnerCode"`
 	RequestID   string `json:"requestId"`
@@ -97,7 +101,16 @@ func (p *momoProvider) GetName() constant.PaymentProviderMethod {
 	return constant.MomoProviderMethod
 }
 
+// SupportsCurrency chỉ nhận VND, amount gửi MoMo là số đồng nên tiền tệ khác sẽ bị tính sai số tiền.
+func (p *momoProvider) SupportsCurrency(currency string) bool {
+	return strings.EqualFold(currency, momoCurrency)
+}
+
 func (p *momoProvider) CreatePayment(ctx context.Context, data PaymentData) (*CreatePaymentResult, error) {
+	if err := CheckCurrency(p, data.Currency); err != nil {
+		return nil, err
+	}
+
 	requestID := data.RequestID
 	uniqueOrderID := fmt.Sprintf("%s_%s", data.OrderID, requestID)
 
@@ -218,7 +231,8 @@ func (p *momoProvider) HandleIPN(r *http.Request) (*domain.Payment, error) {
 	log.Printf("[MOMO IPN] Provider Transaction ID: %s", providerTransID)
 	payment := &domain.Payment{
 		OrderID:               originalOrderID,
-		Amount:                float64(req.Amount),
+		RequestID:             req.RequestID,
+		Amount:                money.New(req.Amount, momoCurrency),
 		ProviderTransactionID: &providerTransID,
 	}
 
@@ -2,12 +2,23 @@ package paymentprovider
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
 )
 
+var (
+	// ErrInvalidSignature được trả về khi chữ ký của dữ liệu nhận từ cổng thanh toán không hợp lệ.
+	ErrInvalidSignature = errors.New("invalid payment provider signature")
+	// ErrUnsupportedCurrency được trả về khi cổng thanh toán không nhận thanh toán bằng tiền tệ của đơn hàng.
+	ErrUnsupportedCurrency = errors.New("currency is not supported by payment provider")
+)
+
 type PaymentStatus string
 
 const (
@@ -30,10 +41,13 @@ func MapResultCodeToStatus(resultCode int) PaymentStatus {
 type PaymentData struct {
 	RequestID   string
 	OrderID     string
-	Amount      int64
+	Amount      int64  // Số tiền theo đơn vị nhỏ nhất của Currency
+	Currency    string // Mã tiền tệ ISO 4217 của Amount
 	OrderInfo   string
 	RedirectURL string
 	IPNURL      string
+	ClientIP    string    // IP của khách, VNPay bắt buộc gửi kèm (vnp_IpAddr)
+	CreatedAt   time.Time // Thời điểm tạo payment, VNPay dùng lại khi tra cứu / hoàn tiền giao dịch
 }
 
 type RefundData struct {
@@ -42,6 +56,9 @@ type RefundData struct {
 	ProviderTransactionID string // ID giao dịch từ nhà cung cấp (Momo transId)
 	Amount                int64
 	Reason                string
+	RequestID             string    // RequestID của payment gốc
+	TransactionDate       time.Time // Thời điểm tạo payment gốc
+	PaymentAmount         int64     // Số tiền đã thanh toán, để phân biệt hoàn toàn bộ hay một phần
 }
 
 type CreatePaymentResult struct {
@@ -80,3 +97,24 @@ type PaymentProvider interface {
 	// GetPaymentStatus lấy trạng thái của một giao dịch thanh toán
 	GetPaymentStatus(ctx context.Context, payment *domain.Payment) (*PaymentStatusResult, error)
 }
+
+// ReturnVerifier được cài bởi các provider redirect khách về kèm kết quả thanh toán đã ký (vd: VNPay vnp_ReturnUrl).
+// Kết quả chỉ dùng để hiển thị cho khách, trạng thái payment vẫn chỉ được cập nhật qua IPN.
+type ReturnVerifier interface {
+	VerifyReturn(query url.Values) (*domain.Payment, error)
+}
+
+// CurrencySupporter được cài bởi các provider chỉ nhận thanh toán bằng một số tiền tệ nhất định (vd: MoMo chỉ nhận VND).
+// Provider không cài interface này được coi là nhận mọi tiền tệ.
+type CurrencySupporter interface {
+	SupportsCurrency(currency string) bool
+}
+
+// CheckCurrency trả về ErrUnsupportedCurrency khi provider không nhận thanh toán bằng currency.
+func CheckCurrency(provider PaymentProvider, currency string) error {
+	supporter, ok := provider.(CurrencySupporter)
+	if ok && !supporter.SupportsCurrency(currency) {
+		return fmt.Errorf("%w: %s does not accept %s", ErrUnsupportedCurrency, provider.GetName(), currency)
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+package paymentprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
+)
+
+const (
+	vnpayVersion   = "2.1.0"
+	vnpayCurrency  = "VND"
+	vnpayLocale    = "vn"
+	vnpayOrderType = "other"
+	// vnp_Amount là số tiền nhân 100 (VNPay không dùng dấu thập phân)
+	vnpayAmountMultiplier = 100
+	vnpayDateLayout       = "20060102150405"
+
+	vnpayCommandPay    = "pay"
+	vnpayCommandQuery  = "querydr"
+	vnpayCommandRefund = "refund"
+
+	vnpayResponseSuccess             = "00" // vnp_ResponseCode: VNPay xử lý yêu cầu thành công
+	vnpayResponseTransactionNotFound = "91" // vnp_ResponseCode: không tìm thấy giao dịch (khách chưa mở trang thanh toán)
+	vnpayTransactionSuccess          = "00" // vnp_TransactionStatus: giao dịch thanh toán thành công
+	vnpayTransactionPending          = "01" // vnp_TransactionStatus: giao dịch chưa hoàn tất
+
+	vnpayRefundFull    = "02"
+	vnpayRefundPartial = "03"
+	vnpayRefundCreator = "payment-service"
+
+	vnpayHTTPTimeout = 30 * time.Second
+)
+
+// vnpayLocation là múi giờ VNPay dùng cho mọi mốc thời gian gửi và nhận (GMT+7).
+var vnpayLocation = time.FixedZone("GMT+7", 7*60*60)
+
+type VNPayQueryRequest struct {
+	RequestID       string `json:"vnp_RequestId"`
+	Version         string `json:"vnp_Version"`
+	Command         string `json:"vnp_Command"`
+	TmnCode         string `json:"vnp_TmnCode"`
+	TxnRef          string `json:"vnp_TxnRef"`
+	OrderInfo       string `json:"vnp_OrderInfo"`
+	TransactionDate string `json:"vnp_TransactionDate"`
+	CreateDate      string `json:"vnp_CreateDate"`
+	IpAddr          string `json:"vnp_IpAddr"`
+	SecureHash      string `json:"vnp_SecureHash"`
+}
+
+type VNPayRefundRequest struct {
+	RequestID       string `json:"vnp_RequestId"`
+	Version         string `json:"vnp_Version"`
+	Command         string `json:"vnp_Command"`
+	TmnCode         string `json:"vnp_TmnCode"`
+	TransactionType string `json:"vnp_TransactionType"`
+	TxnRef          string `json:"vnp_TxnRef"`
+	Amount          int64  `json:"vnp_Amount"`
+	OrderInfo       string `json:"vnp_OrderInfo"`
+	TransactionNo   string `json:"vnp_TransactionNo"`
+	TransactionDate string `json:"vnp_TransactionDate"`
+	CreateBy        string `json:"vnp_CreateBy"`
+	CreateDate      string `json:"vnp_CreateDate"`
+	IpAddr          string `json:"vnp_IpAddr"`
+	SecureHash      string `json:"vnp_SecureHash"`
+}
+
+// VNPayTransactionResponse là phản hồi chung của querydr và refund.
+type VNPayTransactionResponse struct {
+	ResponseID        string `json:"vnp_ResponseId"`
+	Command           string `json:"vnp_Command"`
+	ResponseCode      string `json:"vnp_ResponseCode"`
+	Message           string `json:"vnp_Message"`
+	TmnCode           string `json:"vnp_TmnCode"`
+	TxnRef            string `json:"vnp_TxnRef"`
+	Amount            string `json:"vnp_Amount"`
+	BankCode          string `json:"vnp_BankCode"`
+	PayDate           string `json:"vnp_PayDate"`
+	TransactionNo     string `json:"vnp_TransactionNo"`
+	TransactionType   string `json:"vnp_TransactionType"`
+	TransactionStatus string `json:"vnp_TransactionStatus"`
+	OrderInfo         string `json:"vnp_OrderInfo"`
+	PromotionCode     string `json:"vnp_PromotionCode,omitempty"`
+	PromotionAmount   string `json:"vnp_PromotionAmount,omitempty"`
+	SecureHash        string `json:"vnp_SecureHash"`
+}
+
+// signatureData nối các trường theo thứ tự VNPay quy định, phản hồi querydr có thêm thông tin khuyến mãi.
+func (r *VNPayTransactionResponse) signatureData() string {
+	fields := []string{
+		r.ResponseID,
+		r.Command,
+		r.ResponseCode,
+		r.Message,
+		r.TmnCode,
+		r.TxnRef,
+		r.Amount,
+		r.BankCode,
+		r.PayDate,
+		r.TransactionNo,
+		r.TransactionType,
+		r.TransactionStatus,
+		r.OrderInfo,
+	}
+	if r.Command == vnpayCommandQuery {
+		fields = append(fields, r.PromotionCode, r.PromotionAmount)
+	}
+	return strings.Join(fields, "|")
+}
+
+type vnpayProvider struct {
+	cfg    config.VNPayConfig
+	client *http.Client
+}
+
+func NewVNPayProvider(cfg config.VNPayConfig) PaymentProvider {
+	return &vnpayProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: vnpayHTTPTimeout},
+	}
+}
+
+func (p *vnpayProvider) GetName() constant.PaymentProviderMethod {
+	return constant.VNPayProviderMethod
+}
+
+// SupportsCurrency chỉ nhận VND, vnp_Amount luôn được VNPay hiểu là số đồng nhân 100.
+func (p *vnpayProvider) SupportsCurrency(currency string) bool {
+	return strings.EqualFold(currency, vnpayCurrency)
+}
+
+// CreatePayment không gọi VNPay mà ký các tham số thanh toán vào URL, khách được redirect tới URL này để thanh toán.
+// IPN URL không gửi theo từng giao dịch mà được đăng ký một lần ở trang quản trị merchant của VNPay.
+func (p *vnpayProvider) CreatePayment(ctx context.Context, data PaymentData) (*CreatePaymentResult, error) {
+	if err := CheckCurrency(p, data.Currency); err != nil {
+		return nil, err
+	}
+
+	createdAt := data.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	clientIP := data.ClientIP
+	if clientIP == "" {
+		clientIP = p.cfg.ServerIP
+	}
+
+	params := url.Values{}
+	params.Set("vnp_Version", vnpayVersion)
+	params.Set("vnp_Command", vnpayCommandPay)
+	params.Set("vnp_TmnCode", p.cfg.TmnCode)
+	params.Set("vnp_Amount", strconv.FormatInt(data.Amount*vnpayAmountMultiplier, 10))
+	params.Set("vnp_CurrCode", vnpayCurrency)
+	params.Set("vnp_TxnRef", vnpayTxnRef(data.OrderID, data.RequestID))
+	params.Set("vnp_OrderInfo", data.OrderInfo)
+	params.Set("vnp_OrderType", vnpayOrderType)
+	params.Set("vnp_Locale", vnpayLocale)
+	params.Set("vnp_ReturnUrl", data.RedirectURL)
+	params.Set("vnp_IpAddr", clientIP)
+	params.Set("vnp_CreateDate", formatVNPayDate(createdAt))
+	if p.cfg.PaymentTimeout > 0 {
+		params.Set("vnp_ExpireDate", formatVNPayDate(createdAt.Add(p.cfg.PaymentTimeout)))
+	}
+
+	// Dữ liệu ký chính là query string đã sắp xếp theo tên tham số và URL-encode
+	query := params.Encode()
+	payURL := fmt.Sprintf("%s?%s&vnp_SecureHash=%s", p.cfg.PayURL, query, p.generateSignature(query))
+
+	log.Printf("[VNPAY] Created payment URL for OrderID %s, TxnRef %s", data.OrderID, params.Get("vnp_TxnRef"))
+
+	return &CreatePaymentResult{
+		PayURL: payURL,
+	}, nil
+}
+
+// HandleIPN xác thực IPN VNPay gửi bằng GET, tham số nằm trong query string giống hệt return URL.
+func (p *vnpayProvider) HandleIPN(r *http.Request) (*domain.Payment, error) {
+	payment, err := p.verifyCallback(r.URL.Query())
+	if err != nil {
+		log.Printf("[VNPAY IPN] Error verifying IPN: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[VNPAY IPN] Received IPN: OrderID=%s, Status=%s", payment.OrderID, payment.Status)
+	return payment, nil
+}
+
+// VerifyReturn xác thực các tham số VNPay gắn vào vnp_ReturnUrl khi redirect khách về.
+func (p *vnpayProvider) VerifyReturn(query url.Values) (*domain.Payment, error) {
+	return p.verifyCallback(query)
+}
+
+func (p *vnpayProvider) verifyCallback(query url.Values) (*domain.Payment, error) {
+	signed := url.Values{}
+	for key, values := range query {
+		if !strings.HasPrefix(key, "vnp_") || key == "vnp_SecureHash" || key == "vnp_SecureHashType" {
+			continue
+		}
+		signed[key] = values
+	}
+
+	if !p.verifySignature(signed.Encode(), query.Get("vnp_SecureHash")) {
+		return nil, ErrInvalidSignature
+	}
+
+	if tmnCode := query.Get("vnp_TmnCode"); tmnCode != p.cfg.TmnCode {
+		return nil, fmt.Errorf("unexpected vnp_TmnCode %q", tmnCode)
+	}
+
+	amount, err := parseVNPayAmount(query.Get("vnp_Amount"))
+	if err != nil {
+		return nil, err
+	}
+
+	orderID, requestID := parseVNPayTxnRef(query.Get("vnp_TxnRef"))
+	payment := &domain.Payment{
+		OrderID:   orderID,
+		RequestID: requestID,
+		Amount:    money.New(amount, vnpayCurrency),
+	}
+
+	// vnp_TransactionNo là "0" khi khách huỷ trước khi thanh toán
+	if transactionNo := query.Get("vnp_TransactionNo"); transactionNo != "" && transactionNo != "0" {
+		payment.ProviderTransactionID = &transactionNo
+	}
+
+	if query.Get("vnp_ResponseCode") == vnpayResponseSuccess && query.Get("vnp_TransactionStatus") == vnpayTransactionSuccess {
+		payment.Status = constant.PaymentStatusSuccess
+	} else {
+		payment.Status = constant.PaymentStatusFailed
+	}
+
+	return payment, nil
+}
+
+func (p *vnpayProvider) Refund(ctx context.Context, data RefundData) (*RefundResult, error) {
+	transactionType := vnpayRefundFull
+	if data.PaymentAmount > 0 && data.Amount < data.PaymentAmount {
+		transactionType = vnpayRefundPartial
+	}
+
+	orderInfo := data.Reason
+	if orderInfo == "" {
+		orderInfo = fmt.Sprintf("Hoan_tien_don_hang_%s", data.OrderID)
+	}
+
+	req := &VNPayRefundRequest{
+		RequestID:       newVNPayRequestID(),
+		Version:         vnpayVersion,
+		Command:         vnpayCommandRefund,
+		TmnCode:         p.cfg.TmnCode,
+		TransactionType: transactionType,
+		TxnRef:          vnpayTxnRef(data.OrderID, data.RequestID),
+		Amount:          data.Amount * vnpayAmountMultiplier,
+		OrderInfo:       orderInfo,
+		TransactionNo:   data.ProviderTransactionID,
+		TransactionDate: formatVNPayDate(data.TransactionDate),
+		CreateBy:        vnpayRefundCreator,
+		CreateDate:      formatVNPayDate(time.Now()),
+		IpAddr:          p.cfg.ServerIP,
+	}
+
+	req.SecureHash = p.generateSignature(strings.Join([]string{
+		req.RequestID,
+		req.Version,
+		req.Command,
+		req.TmnCode,
+		req.TransactionType,
+		req.TxnRef,
+		strconv.FormatInt(req.Amount, 10),
+		req.TransactionNo,
+		req.TransactionDate,
+		req.CreateBy,
+		req.CreateDate,
+		req.IpAddr,
+		req.OrderInfo,
+	}, "|"))
+
+	vnpayResp, err := p.callTransactionAPI(ctx, req)
+	if err != nil {
+		log.Printf("[VNPAY REFUND] Error calling refund API for OrderID %s: %v", data.OrderID, err)
+		return nil, err
+	}
+
+	if vnpayResp.ResponseCode != vnpayResponseSuccess {
+		log.Printf("VNPay refund returned an error: %s (code: %s)", vnpayResp.Message, vnpayResp.ResponseCode)
+		return nil, fmt.Errorf("vnpay refund failed with code %s: %s", vnpayResp.ResponseCode, vnpayResp.Message)
+	}
+
+	if !p.verifySignature(vnpayResp.signatureData(), vnpayResp.SecureHash) {
+		return nil, fmt.Errorf("vnpay refund response: %w", ErrInvalidSignature)
+	}
+
+	return &RefundResult{
+		ProviderRefundID: vnpayResp.TransactionNo,
+		Status:           string(sqlc.RefundStatusCOMPLETED),
+	}, nil
+}
+
+func (p *vnpayProvider) GetPaymentStatus(ctx context.Context, payment *domain.Payment) (*PaymentStatusResult, error) {
+	req := &VNPayQueryRequest{
+		RequestID:       newVNPayRequestID(),
+		Version:         vnpayVersion,
+		Command:         vnpayCommandQuery,
+		TmnCode:         p.cfg.TmnCode,
+		TxnRef:          vnpayTxnRef(payment.OrderID, payment.RequestID),
+		OrderInfo:       fmt.Sprintf("Truy_van_don_hang_%s", payment.OrderID),
+		TransactionDate: formatVNPayDate(payment.CreatedAt),
+		CreateDate:      formatVNPayDate(time.Now()),
+		IpAddr:          p.cfg.ServerIP,
+	}
+
+	req.SecureHash = p.generateSignature(strings.Join([]string{
+		req.RequestID,
+		req.Version,
+		req.Command,
+		req.TmnCode,
+		req.TxnRef,
+		req.TransactionDate,
+		req.CreateDate,
+		req.IpAddr,
+		req.OrderInfo,
+	}, "|"))
+
+	vnpayResp, err := p.callTransactionAPI(ctx, req)
+	if err != nil {
+		log.Printf("[VNPAY QUERY] Error calling querydr API for OrderID %s: %v", payment.OrderID, err)
+		return nil, err
+	}
+
+	// Không tìm thấy giao dịch nghĩa là khách chưa từng thanh toán, payment coi như thất bại
+	if vnpayResp.ResponseCode != vnpayResponseSuccess && vnpayResp.ResponseCode != vnpayResponseTransactionNotFound {
+		log.Printf("VNPay query returned an error: %s (code: %s)", vnpayResp.Message, vnpayResp.ResponseCode)
+		return nil, fmt.Errorf("vnpay query returned an error: %s (code: %s)", vnpayResp.Message, vnpayResp.ResponseCode)
+	}
+
+	if !p.verifySignature(vnpayResp.signatureData(), vnpayResp.SecureHash) {
+		return nil, fmt.Errorf("vnpay query response: %w", ErrInvalidSignature)
+	}
+
+	result := &PaymentStatusResult{
+		PartnerCode:   vnpayResp.TmnCode,
+		OrderId:       vnpayResp.TxnRef,
+		RequestId:     payment.RequestID,
+		PayType:       vnpayResp.BankCode,
+		Signature:     vnpayResp.SecureHash,
+		Message:       vnpayResp.Message,
+		ResponseTime:  vnpayResp.PayDate,
+		PaymentStatus: PaymentStatusFailed,
+	}
+	result.ResultCode, _ = strconv.Atoi(vnpayResp.ResponseCode)
+
+	if vnpayResp.ResponseCode == vnpayResponseSuccess {
+		if result.Amount, err = parseVNPayAmount(vnpayResp.Amount); err != nil {
+			return nil, err
+		}
+		result.TransId, _ = strconv.ParseInt(vnpayResp.TransactionNo, 10, 64)
+		result.PaymentStatus = mapVNPayTransactionStatus(vnpayResp.TransactionStatus)
+	}
+
+	return result, nil
+}
+
+func (p *vnpayProvider) callTransactionAPI(ctx context.Context, payload any) (*VNPayTransactionResponse, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal VNPay request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, "POST", p.cfg.ApiEndpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VNPay HTTP request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to VNPay: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VNPay response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vnpay returned HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var vnpayResp VNPayTransactionResponse
+	if err := json.Unmarshal(body, &vnpayResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal VNPay response: %w", err)
+	}
+
+	return &vnpayResp, nil
+}
+
+func (p *vnpayProvider) verifySignature(data, signature string) bool {
+	expectedSignature := p.generateSignature(data)
+	return hmac.Equal([]byte(expectedSignature), []byte(strings.ToLower(signature)))
+}
+
+func (p *vnpayProvider) generateSignature(data string) string {
+	h := hmac.New(sha512.New, []byte(p.cfg.HashSecret))
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func mapVNPayTransactionStatus(status string) PaymentStatus {
+	switch status {
+	case vnpayTransactionSuccess:
+		return PaymentStatusSuccess
+	case vnpayTransactionPending:
+		return PaymentStatusPending
+	default:
+		return PaymentStatusFailed
+	}
+}
+
+// vnpayTxnRef ghép orderID với requestID giống MoMo. Mỗi đơn chỉ có một payment (payments.order_id là UNIQUE) nên
+// requestID không dùng cho việc thanh toán lại mà để đối chiếu IPN / return URL với đúng request_id của payment.
+func vnpayTxnRef(orderID, requestID string) string {
+	return fmt.Sprintf("%s_%s", orderID, requestID)
+}
+
+func parseVNPayTxnRef(txnRef string) (orderID, requestID string) {
+	orderID, requestID, _ = strings.Cut(txnRef, "_")
+	return orderID, requestID
+}
+
+func parseVNPayAmount(value string) (int64, error) {
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || amount%vnpayAmountMultiplier != 0 {
+		return 0, fmt.Errorf("invalid vnp_Amount %q", value)
+	}
+	return amount / vnpayAmountMultiplier, nil
+}
+
+func formatVNPayDate(t time.Time) string {
+	return t.In(vnpayLocation).Format(vnpayDateLayout)
+}
+
+// newVNPayRequestID tạo vnp_RequestId, VNPay giới hạn 32 ký tự và không trùng trong ngày.
+func newVNPayRequestID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
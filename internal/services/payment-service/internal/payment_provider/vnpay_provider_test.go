@@ -0,0 +1,616 @@
+package paymentprovider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
+)
+
+const (
+	testVNPayTmnCode    = "GOSHOP01"
+	testVNPayHashSecret = "SANDBOXSECRETKEY0123456789ABCDEF"
+	testOrderID         = "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"
+	testRequestID       = "c1f0e1d2-3b4a-4c5d-8e9f-0a1b2c3d4e5f"
+)
+
+// vnpaySandboxTransaction là một giao dịch thanh toán đã tạo trên sandbox giả lập.
+type vnpaySandboxTransaction struct {
+	amount        string
+	createDate    string
+	transactionNo string
+	status        string
+}
+
+// vnpaySandbox giả lập trang thanh toán và merchant API của VNPay sandbox, chữ ký của các request
+// được kiểm tra theo thứ tự trường trong tài liệu VNPay chứ không dùng lại code của provider.
+type vnpaySandbox struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	transactions map[string]*vnpaySandboxTransaction
+	refunds      []map[string]any
+	nextTransNo  int
+
+	// responseCode khác rỗng thì merchant API trả về mã lỗi này
+	responseCode string
+	// tamperResponses làm sai chữ ký của phản hồi merchant API
+	tamperResponses bool
+}
+
+func newVNPaySandbox(t *testing.T) *vnpaySandbox {
+	t.Helper()
+
+	sandbox := &vnpaySandbox{
+		transactions: make(map[string]*vnpaySandboxTransaction),
+		nextTransNo:  14422574,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/paymentv2/vpcpay.html", sandbox.handlePay)
+	mux.HandleFunc("/merchant_webapi/api/transaction", sandbox.handleTransactionAPI)
+	sandbox.server = httptest.NewServer(mux)
+	t.Cleanup(sandbox.server.Close)
+
+	return sandbox
+}
+
+func (s *vnpaySandbox) newProvider() *vnpayProvider {
+	return NewVNPayProvider(config.VNPayConfig{
+		TmnCode:        testVNPayTmnCode,
+		HashSecret:     testVNPayHashSecret,
+		PayURL:         s.server.URL + "/paymentv2/vpcpay.html",
+		ApiEndpoint:    s.server.URL + "/merchant_webapi/api/transaction",
+		ServerIP:       "10.0.0.1",
+		PaymentTimeout: 15 * time.Minute,
+	}).(*vnpayProvider)
+}
+
+// handlePay kiểm tra chữ ký của URL thanh toán rồi giả lập khách thanh toán thành công bằng thẻ NCB,
+// redirect về vnp_ReturnUrl kèm kết quả đã ký.
+func (s *vnpaySandbox) handlePay(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	signed := url.Values{}
+	for key, values := range query {
+		if key != "vnp_SecureHash" {
+			signed[key] = values
+		}
+	}
+	if sandboxSign(signed.Encode()) != query.Get("vnp_SecureHash") {
+		http.Error(w, "Sai chữ ký", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.nextTransNo++
+	transaction := &vnpaySandboxTransaction{
+		amount:        query.Get("vnp_Amount"),
+		createDate:    query.Get("vnp_CreateDate"),
+		transactionNo: strconv.Itoa(s.nextTransNo),
+		status:        "00",
+	}
+	s.transactions[query.Get("vnp_TxnRef")] = transaction
+	s.mu.Unlock()
+
+	result := url.Values{}
+	result.Set("vnp_Amount", transaction.amount)
+	result.Set("vnp_BankCode", "NCB")
+	result.Set("vnp_BankTranNo", "VNP"+transaction.transactionNo)
+	result.Set("vnp_CardType", "ATM")
+	result.Set("vnp_OrderInfo", query.Get("vnp_OrderInfo"))
+	result.Set("vnp_PayDate", transaction.createDate)
+	result.Set("vnp_ResponseCode", "00")
+	result.Set("vnp_TmnCode", query.Get("vnp_TmnCode"))
+	result.Set("vnp_TransactionNo", transaction.transactionNo)
+	result.Set("vnp_TransactionStatus", transaction.status)
+	result.Set("vnp_TxnRef", query.Get("vnp_TxnRef"))
+	result.Set("vnp_SecureHash", sandboxSign(result.Encode()))
+
+	http.Redirect(w, r, query.Get("vnp_ReturnUrl")+"?"+result.Encode(), http.StatusFound)
+}
+
+func (s *vnpaySandbox) handleTransactionAPI(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	field := func(name string) string {
+		switch value := req[name].(type) {
+		case string:
+			return value
+		case float64:
+			return strconv.FormatInt(int64(value), 10)
+		default:
+			return ""
+		}
+	}
+
+	var signatureFields []string
+	switch field("vnp_Command") {
+	case "querydr":
+		signatureFields = []string{"vnp_RequestId", "vnp_Version", "vnp_Command", "vnp_TmnCode", "vnp_TxnRef", "vnp_TransactionDate", "vnp_CreateDate", "vnp_IpAddr", "vnp_OrderInfo"}
+	case "refund":
+		signatureFields = []string{"vnp_RequestId", "vnp_Version", "vnp_Command", "vnp_TmnCode", "vnp_TransactionType", "vnp_TxnRef", "vnp_Amount", "vnp_TransactionNo", "vnp_TransactionDate", "vnp_CreateBy", "vnp_CreateDate", "vnp_IpAddr", "vnp_OrderInfo"}
+	default:
+		http.Error(w, "unknown command", http.StatusBadRequest)
+		return
+	}
+
+	values := make([]string, 0, len(signatureFields))
+	for _, name := range signatureFields {
+		values = append(values, field(name))
+	}
+
+	resp := &VNPayTransactionResponse{
+		ResponseID: "resp" + field("vnp_RequestId"),
+		Command:    field("vnp_Command"),
+		TmnCode:    field("vnp_TmnCode"),
+		TxnRef:     field("vnp_TxnRef"),
+		OrderInfo:  field("vnp_OrderInfo"),
+	}
+
+	s.mu.Lock()
+	transaction, found := s.transactions[resp.TxnRef]
+	switch {
+	case sandboxSign(strings.Join(values, "|")) != field("vnp_SecureHash"):
+		resp.ResponseCode, resp.Message = "97", "Checksum khong hop le"
+	case s.responseCode != "":
+		resp.ResponseCode, resp.Message = s.responseCode, "Loi khong xac dinh"
+	case !found:
+		resp.ResponseCode, resp.Message = "91", "Khong tim thay giao dich yeu cau"
+	case transaction.createDate != field("vnp_TransactionDate"):
+		resp.ResponseCode, resp.Message = "91", "Khong tim thay giao dich yeu cau"
+	case resp.Command == "refund":
+		s.refunds = append(s.refunds, req)
+		s.nextTransNo++
+		resp.ResponseCode, resp.Message = "00", "Refund success"
+		resp.Amount = field("vnp_Amount")
+		resp.BankCode = "NCB"
+		resp.PayDate = field("vnp_CreateDate")
+		resp.TransactionNo = strconv.Itoa(s.nextTransNo)
+		resp.TransactionType = field("vnp_TransactionType")
+		resp.TransactionStatus = "05"
+	default:
+		resp.ResponseCode, resp.Message = "00", "QueryDR Success"
+		resp.Amount = transaction.amount
+		resp.BankCode = "NCB"
+		resp.PayDate = transaction.createDate
+		resp.TransactionNo = transaction.transactionNo
+		resp.TransactionType = "01"
+		resp.TransactionStatus = transaction.status
+	}
+	tamper := s.tamperResponses
+	s.mu.Unlock()
+
+	resp.SecureHash = sandboxSign(resp.signatureData())
+	if tamper {
+		resp.Amount = "100"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// pay tạo URL thanh toán và đi theo redirect của sandbox, trả về query VNPay gắn vào return URL.
+func (s *vnpaySandbox) pay(t *testing.T, provider *vnpayProvider, data PaymentData) url.Values {
+	t.Helper()
+
+	result, err := provider.CreatePayment(context.Background(), data)
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(result.PayURL)
+	if err != nil {
+		t.Fatalf("GET pay URL error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("sandbox rejected pay URL with status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect location: %v", err)
+	}
+	return location.Query()
+}
+
+func sandboxSign(data string) string {
+	h := hmac.New(sha512.New, []byte(testVNPayHashSecret))
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func signQuery(query url.Values) url.Values {
+	signed := url.Values{}
+	for key, values := range query {
+		if key != "vnp_SecureHash" {
+			signed[key] = values
+		}
+	}
+	signed.Set("vnp_SecureHash", sandboxSign(signed.Encode()))
+	return signed
+}
+
+func testPaymentData() PaymentData {
+	return PaymentData{
+		RequestID:   testRequestID,
+		OrderID:     testOrderID,
+		Amount:      150000,
+		Currency:    "VND",
+		OrderInfo:   "Thanh_toan_don_hang_" + testOrderID,
+		RedirectURL: "http://localhost:3000/orders/" + testOrderID + "/result",
+		ClientIP:    "113.160.92.202",
+		CreatedAt:   time.Date(2026, 10, 17, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestVNPayProvider_CreatePayment(t *testing.T) {
+	sandbox := newVNPaySandbox(t)
+	provider := sandbox.newProvider()
+
+	result, err := provider.CreatePayment(context.Background(), testPaymentData())
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	payURL, err := url.Parse(result.PayURL)
+	if err != nil {
+		t.Fatalf("invalid pay URL: %v", err)
+	}
+	query := payURL.Query()
+
+	expected := map[string]string{
+		"vnp_Version":    "2.1.0",
+		"vnp_Command":    "pay",
+		"vnp_TmnCode":    testVNPayTmnCode,
+		"vnp_Amount":     "15000000",
+		"vnp_CurrCode":   "VND",
+		"vnp_TxnRef":     testOrderID + "_" + testRequestID,
+		"vnp_IpAddr":     "113.160.92.202",
+		"vnp_CreateDate": "20261017100405",
+		"vnp_ExpireDate": "20261017101905",
+	}
+	for key, want := range expected {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if len(query.Get("vnp_SecureHash")) != sha512.Size*2 {
+		t.Errorf("vnp_SecureHash = %q, want a hex HMAC-SHA512", query.Get("vnp_SecureHash"))
+	}
+}
+
+func TestVNPayProvider_CreatePayment_RejectsNonVND(t *testing.T) {
+	sandbox := newVNPaySandbox(t)
+	provider := sandbox.newProvider()
+
+	data := testPaymentData()
+	data.Amount = 1234
+	data.Currency = "USD"
+
+	result, err := provider.CreatePayment(context.Background(), data)
+	if !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Fatalf("CreatePayment() error = %v, want %v", err, ErrUnsupportedCurrency)
+	}
+	if result != nil {
+		t.Errorf("CreatePayment() result = %+v, want nil", result)
+	}
+	if err := CheckCurrency(provider, "vnd"); err != nil {
+		t.Errorf("CheckCurrency(vnd) error = %v, want nil", err)
+	}
+}
+
+func TestVNPayProvider_VerifyReturnAndIPN(t *testing.T) {
+	sandbox := newVNPaySandbox(t)
+	provider := sandbox.newProvider()
+
+	returnQuery := sandbox.pay(t, provider, testPaymentData())
+
+	assertPayment := func(t *testing.T, payment *domain.Payment) {
+		t.Helper()
+		if payment.OrderID != testOrderID || payment.RequestID != testRequestID {
+			t.Errorf("payment = %s/%s, want %s/%s", payment.OrderID, payment.RequestID, testOrderID, testRequestID)
+		}
+		if payment.Status != constant.PaymentStatusSuccess {
+			t.Errorf("status = %s, want %s", payment.Status, constant.PaymentStatusSuccess)
+		}
+		if payment.Amount.Amount != 150000 || payment.Amount.Currency != "VND" {
+			t.Errorf("amount = %s, want 150000 VND", payment.Amount)
+		}
+		if payment.ProviderTransactionID == nil || *payment.ProviderTransactionID != returnQuery.Get("vnp_TransactionNo") {
+			t.Errorf("provider transaction id = %v, want %s", payment.ProviderTransactionID, returnQuery.Get("vnp_TransactionNo"))
+		}
+	}
+
+	t.Run("return URL", func(t *testing.T) {
+		payment, err := provider.VerifyReturn(returnQuery)
+		if err != nil {
+			t.Fatalf("VerifyReturn() error = %v", err)
+		}
+		assertPayment(t, payment)
+	})
+
+	t.Run("IPN", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/payments/ipn/vnpay?"+returnQuery.Encode(), nil)
+		payment, err := provider.HandleIPN(req)
+		if err != nil {
+			t.Fatalf("HandleIPN() error = %v", err)
+		}
+		assertPayment(t, payment)
+	})
+}
+
+func TestVNPayProvider_VerifyCallbackFailures(t *testing.T) {
+	sandbox := newVNPaySandbox(t)
+	provider := sandbox.newProvider()
+
+	returnQuery := sandbox.pay(t, provider, testPaymentData())
+
+	testCases := []struct {
+		name          string
+		query         func() url.Values
+		expectedErr   error
+		expectedState constant.PaymentStatus
+	}{
+		{
+			name: "Error - Amount tampered after signing",
+			query: func() url.Values {
+				query := url.Values{}
+				for key, values := range returnQuery {
+					query[key] = values
+				}
+				query.Set("vnp_Amount", "100")
+				return query
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Error - Missing secure hash",
+			query: func() url.Values {
+				query := url.Values{}
+				for key, values := range returnQuery {
+					query[key] = values
+				}
+				query.Del("vnp_SecureHash")
+				return query
+			},
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name: "Error - Signed for another merchant",
+			query: func() url.Values {
+				query := signQuery(returnQuery)
+				query.Set("vnp_TmnCode", "OTHER001")
+				return signQuery(query)
+			},
+			expectedErr: errors.New("unexpected vnp_TmnCode"),
+		},
+		{
+			name: "Failed - Customer canceled on VNPay",
+			query: func() url.Values {
+				query := signQuery(returnQuery)
+				query.Set("vnp_ResponseCode", "24")
+				query.Set("vnp_TransactionStatus", "02")
+				query.Set("vnp_TransactionNo", "0")
+				return signQuery(query)
+			},
+			expectedState: constant.PaymentStatusFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payment, err := provider.VerifyReturn(tc.query())
+
+			if tc.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("VerifyReturn() error = nil, want %v", tc.expectedErr)
+				}
+				if errors.Is(tc.expectedErr, ErrInvalidSignature) && !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("VerifyReturn() error = %v, want %v", err, tc.expectedErr)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr.Error()) {
+					t.Fatalf("VerifyReturn() error = %v, want %v", err, tc.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("VerifyReturn() error = %v", err)
+			}
+			if payment.Status != tc.expectedState {
+				t.Errorf("status = %s, want %s", payment.Status, tc.expectedState)
+			}
+			if payment.ProviderTransactionID != nil {
+				t.Errorf("provider transaction id = %s, want nil", *payment.ProviderTransactionID)
+			}
+		})
+	}
+}
+
+func TestVNPayProvider_GetPaymentStatus(t *testing.T) {
+	data := testPaymentData()
+
+	testCases := []struct {
+		name            string
+		setup           func(sandbox *vnpaySandbox, provider *vnpayProvider)
+		expectedStatus  PaymentStatus
+		expectedTransID bool
+		expectedError   string
+	}{
+		{
+			name: "Success - Paid transaction",
+			setup: func(sandbox *vnpaySandbox, provider *vnpayProvider) {
+				sandbox.pay(t, provider, data)
+			},
+			expectedStatus:  PaymentStatusSuccess,
+			expectedTransID: true,
+		},
+		{
+			name: "Pending - Transaction not completed",
+			setup: func(sandbox *vnpaySandbox, provider *vnpayProvider) {
+				sandbox.pay(t, provider, data)
+				sandbox.transactions[vnpayTxnRef(data.OrderID, data.RequestID)].status = "01"
+			},
+			expectedStatus:  PaymentStatusPending,
+			expectedTransID: true,
+		},
+		{
+			name:           "Failed - Customer never opened the payment URL",
+			setup:          func(*vnpaySandbox, *vnpayProvider) {},
+			expectedStatus: PaymentStatusFailed,
+		},
+		{
+			name: "Error - VNPay returns an error code",
+			setup: func(sandbox *vnpaySandbox, provider *vnpayProvider) {
+				sandbox.pay(t, provider, data)
+				sandbox.responseCode = "99"
+			},
+			expectedError: "code: 99",
+		},
+		{
+			name: "Error - Response signature does not match",
+			setup: func(sandbox *vnpaySandbox, provider *vnpayProvider) {
+				sandbox.pay(t, provider, data)
+				sandbox.tamperResponses = true
+			},
+			expectedError: ErrInvalidSignature.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sandbox := newVNPaySandbox(t)
+			provider := sandbox.newProvider()
+			tc.setup(sandbox, provider)
+
+			result, err := provider.GetPaymentStatus(context.Background(), &domain.Payment{
+				OrderID:   data.OrderID,
+				RequestID: data.RequestID,
+				CreatedAt: data.CreatedAt,
+			})
+
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("GetPaymentStatus() error = %v, want %q", err, tc.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetPaymentStatus() error = %v", err)
+			}
+			if result.PaymentStatus != tc.expectedStatus {
+				t.Errorf("status = %s, want %s", result.PaymentStatus, tc.expectedStatus)
+			}
+			if tc.expectedTransID && (result.TransId == 0 || result.Amount != data.Amount) {
+				t.Errorf("result = %+v, want transaction id and amount %d", result, data.Amount)
+			}
+		})
+	}
+}
+
+func TestVNPayProvider_Refund(t *testing.T) {
+	data := testPaymentData()
+
+	testCases := []struct {
+		name                    string
+		amount                  int64
+		transactionDate         time.Time
+		responseCode            string
+		expectedTransactionType string
+		expectedError           string
+	}{
+		{
+			name:                    "Success - Full refund",
+			amount:                  data.Amount,
+			transactionDate:         data.CreatedAt,
+			expectedTransactionType: "02",
+		},
+		{
+			name:                    "Success - Partial refund",
+			amount:                  50000,
+			transactionDate:         data.CreatedAt,
+			expectedTransactionType: "03",
+		},
+		{
+			name:            "Error - Original transaction date does not match",
+			amount:          data.Amount,
+			transactionDate: data.CreatedAt.Add(time.Second),
+			expectedError:   "code 91",
+		},
+		{
+			name:            "Error - VNPay rejects the refund",
+			amount:          data.Amount,
+			transactionDate: data.CreatedAt,
+			responseCode:    "94",
+			expectedError:   "code 94",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sandbox := newVNPaySandbox(t)
+			provider := sandbox.newProvider()
+			returnQuery := sandbox.pay(t, provider, data)
+			sandbox.responseCode = tc.responseCode
+
+			result, err := provider.Refund(context.Background(), RefundData{
+				OrderID:               data.OrderID,
+				ProviderTransactionID: returnQuery.Get("vnp_TransactionNo"),
+				Amount:                tc.amount,
+				Reason:                "Khach huy don",
+				RequestID:             data.RequestID,
+				TransactionDate:       tc.transactionDate,
+				PaymentAmount:         data.Amount,
+			})
+
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("Refund() error = %v, want %q", err, tc.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Refund() error = %v", err)
+			}
+			if result.ProviderRefundID == "" {
+				t.Error("ProviderRefundID is empty")
+			}
+			if len(sandbox.refunds) != 1 {
+				t.Fatalf("sandbox received %d refunds, want 1", len(sandbox.refunds))
+			}
+
+			refund := sandbox.refunds[0]
+			if got := refund["vnp_TransactionType"]; got != tc.expectedTransactionType {
+				t.Errorf("vnp_TransactionType = %v, want %s", got, tc.expectedTransactionType)
+			}
+			if got, _ := refund["vnp_Amount"].(float64); int64(got) != tc.amount*100 {
+				t.Errorf("vnp_Amount = %v, want %d", refund["vnp_Amount"], tc.amount*100)
+			}
+		})
+	}
+}
@@ -2,23 +2,45 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/db/sqlc"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
 )
 
 type PaymentRepository interface {
+	// CreatePayment tạo payment PENDING, hoặc khởi tạo lại payment FAILED của đơn. Trả về pgx.ErrNoRows nếu đơn đã có
+	// payment ở trạng thái khác.
 	CreatePayment(ctx context.Context, params sqlc.CreatePaymentParams) (*domain.Payment, error)
+	// CreateCODPayment tạo payment COD cùng event COD_PLACED trong một transaction, để đơn chắc chắn
+	// được chuyển sang PROCESSING khi payment đã được tạo.
+	CreateCODPayment(ctx context.Context, params sqlc.CreatePaymentParams, payload string) (*domain.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, params sqlc.UpdatePaymentStatusParams) (*domain.Payment, error)
+	// UpdatePendingPaymentStatus cập nhật kết quả thanh toán, trả về pgx.ErrNoRows nếu payment không còn PENDING.
+	UpdatePendingPaymentStatus(ctx context.Context, params sqlc.UpdatePendingPaymentStatusParams) (*domain.Payment, error)
 	GetPaymentByOrderID(ctx context.Context, orderID string) (*domain.Payment, error)
+	// GetPaymentsByOrderIDs trả về payment của các đơn hàng, đơn chưa có payment bị bỏ qua.
+	GetPaymentsByOrderIDs(ctx context.Context, orderIDs []string) ([]domain.Payment, error)
 	CreatePaymentRefund(ctx context.Context, params sqlc.CreateRefundPaymentParams) (*domain.PaymentRefund, error)
 	GetRefundByPaymentID(ctx context.Context, paymentID string) (*domain.PaymentRefund, error)
+	GetRefundByReference(ctx context.Context, paymentID string, referenceID string) (*domain.PaymentRefund, error)
+	// CreateRefundWithinRefundable khoá payment, tính số tiền còn hoàn được (trừ các yêu cầu hoàn chưa thất bại) và tạo
+	// yêu cầu hoàn do build trả về trong cùng một transaction, để các yêu cầu hoàn đồng thời không vượt quá số tiền đã thu.
+	CreateRefundWithinRefundable(ctx context.Context, paymentID string, build func(payment *domain.Payment, refundable money.Money) (sqlc.CreateRefundPaymentParams, error)) (*domain.PaymentRefund, error)
+	GetCompletedRefundedAmount(ctx context.Context, payment *domain.Payment) (money.Money, error)
 	UpdateRefundPaymentStatus(ctx context.Context, params sqlc.UpdateRefundPaymentStatusParams) (*domain.PaymentRefund, error)
 	GetBatchRefundPaymentsByStatus(ctx context.Context, status sqlc.RefundStatus) ([]domain.PaymentRefund, error)
 	GetBatchPendingPayments(ctx context.Context) ([]domain.Payment, error)
+	// MarkCODPaymentCollected chuyển payment COD đang PENDING sang SUCCESS với số tiền thực thu, trả về pgx.ErrNoRows
+	// nếu payment không phải COD hoặc không còn PENDING.
+	MarkCODPaymentCollected(ctx context.Context, paymentID string, collectionID string, amount money.Money) (*domain.Payment, error)
+	// FailPendingPayment chuyển payment đang PENDING sang FAILED, trả về pgx.ErrNoRows nếu payment không còn PENDING.
+	FailPendingPayment(ctx context.Context, paymentID string) (*domain.Payment, error)
 }
 
 type paymentRepository struct {
@@ -45,8 +67,7 @@ func toDomain(p *sqlc.Payment) *domain.Payment {
 		ID:                    converter.PgUUIDToString(p.ID),
 		OrderID:               converter.PgUUIDToString(p.OrderID),
 		UserID:                converter.PgUUIDToString(p.UserID),
-		Amount:                converter.PgNumericToFloat64(p.Amount),
-		Currency:              p.Currency,
+		Amount:                converter.PgNumericToMoney(p.Amount, p.Currency),
 		Method:                constant.PaymentMethod(p.PaymentMethod),
 		Provider:              *converter.PgTextToStringPtr(p.PaymentProvider),
 		ProviderTransactionID: converter.PgTextToStringPtr(p.ProviderTransactionID),
@@ -71,9 +92,10 @@ func toDomainRefund(r *sqlc.RefundPayment) *domain.PaymentRefund {
 		ID:           converter.PgUUIDToString(r.ID),
 		PaymentID:    converter.PgUUIDToString(r.PaymentID),
 		OrderID:      converter.PgUUIDToString(r.OrderID),
-		Amount:       converter.PgNumericToFloat64(r.Amount),
+		Amount:       converter.PgNumericToMoney(r.Amount, r.Currency),
 		RefundStatus: constant.RefundStatus(r.RefundStatus),
 		Reason:       r.Reason.String,
+		ReferenceID:  converter.PgTextToStringPtr(r.ReferenceID),
 		CreatedAt:    r.CreatedAt.Time,
 		UpdatedAt:    r.UpdatedAt.Time,
 	}
@@ -95,6 +117,37 @@ func (r *paymentRepository) CreatePayment(ctx context.Context, params sqlc.Creat
 	return toDomain(&result), nil
 }
 
+func (r *paymentRepository) CreateCODPayment(ctx context.Context, params sqlc.CreatePaymentParams, payload string) (*domain.Payment, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	payment, err := qtx.CreatePayment(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment record: %w", err)
+	}
+
+	_, err = qtx.CreatePaymentEvent(ctx, sqlc.CreatePaymentEventParams{
+		PaymentID:   payment.ID,
+		OrderID:     payment.OrderID,
+		EventType:   string(domain.PaymentEventTypeCODPlaced),
+		Payload:     []byte(payload),
+		EventStatus: sqlc.OutboxEventStatus(domain.PaymentEventStatusPending),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s event: %w", domain.PaymentEventTypeCODPlaced, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomain(&payment), nil
+}
+
 func (r *paymentRepository) UpdatePaymentStatus(ctx context.Context, params sqlc.UpdatePaymentStatusParams) (*domain.Payment, error) {
 	result, err := r.queries.UpdatePaymentStatus(ctx, params)
 	if err != nil {
@@ -103,6 +156,14 @@ func (r *paymentRepository) UpdatePaymentStatus(ctx context.Context, params sqlc
 	return toDomain(&result), nil
 }
 
+func (r *paymentRepository) UpdatePendingPaymentStatus(ctx context.Context, params sqlc.UpdatePendingPaymentStatusParams) (*domain.Payment, error) {
+	result, err := r.queries.UpdatePendingPaymentStatus(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return toDomain(&result), nil
+}
+
 func (r *paymentRepository) GetPaymentByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
 	result, err := r.queries.GetPaymentByOrderID(ctx, converter.StringToPgUUID(orderID))
 	if err != nil {
@@ -111,6 +172,19 @@ func (r *paymentRepository) GetPaymentByOrderID(ctx context.Context, orderID str
 	return toDomain(&result), nil
 }
 
+func (r *paymentRepository) GetPaymentsByOrderIDs(ctx context.Context, orderIDs []string) ([]domain.Payment, error) {
+	ids := make([]pgtype.UUID, len(orderIDs))
+	for i, orderID := range orderIDs {
+		ids[i] = converter.StringToPgUUID(orderID)
+	}
+
+	result, err := r.queries.GetPaymentsByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainPayments(result), nil
+}
+
 func (r *paymentRepository) CreatePaymentRefund(ctx context.Context, params sqlc.CreateRefundPaymentParams) (*domain.PaymentRefund, error) {
 	result, err := r.queries.CreateRefundPayment(ctx, params)
 	if err != nil {
@@ -120,13 +194,72 @@ func (r *paymentRepository) CreatePaymentRefund(ctx context.Context, params sqlc
 }
 
 func (r *paymentRepository) GetRefundByPaymentID(ctx context.Context, paymentID string) (*domain.PaymentRefund, error) {
-	result, err := r.queries.GetRefundPaymentByID(ctx, converter.StringToPgUUID(paymentID))
+	result, err := r.queries.GetRefundPaymentByPaymentID(ctx, converter.StringToPgUUID(paymentID))
+	if err != nil {
+		return nil, err
+	}
+	return toDomainRefund(&result), nil
+}
+
+func (r *paymentRepository) GetRefundByReference(ctx context.Context, paymentID string, referenceID string) (*domain.PaymentRefund, error) {
+	result, err := r.queries.GetRefundPaymentByReference(ctx, sqlc.GetRefundPaymentByReferenceParams{
+		PaymentID:   converter.StringToPgUUID(paymentID),
+		ReferenceID: converter.StringToPgText(&referenceID),
+	})
 	if err != nil {
 		return nil, err
 	}
 	return toDomainRefund(&result), nil
 }
 
+func (r *paymentRepository) CreateRefundWithinRefundable(ctx context.Context, paymentID string, build func(payment *domain.Payment, refundable money.Money) (sqlc.CreateRefundPaymentParams, error)) (*domain.PaymentRefund, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	locked, err := qtx.GetPaymentByIDForUpdate(ctx, converter.StringToPgUUID(paymentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock payment %s: %w", paymentID, err)
+	}
+	payment := toDomain(&locked)
+
+	total, err := qtx.SumActiveRefundAmountByPaymentID(ctx, locked.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunded amount of payment %s: %w", paymentID, err)
+	}
+	refundable, err := payment.Amount.Sub(converter.PgNumericToMoney(total, payment.Amount.Currency))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refundable amount of payment %s: %w", paymentID, err)
+	}
+
+	params, err := build(payment, refundable)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := qtx.CreateRefundPayment(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return toDomainRefund(&refund), nil
+}
+
+func (r *paymentRepository) GetCompletedRefundedAmount(ctx context.Context, payment *domain.Payment) (money.Money, error) {
+	total, err := r.queries.SumCompletedRefundAmountByPaymentID(ctx, converter.StringToPgUUID(payment.ID))
+	if err != nil {
+		return money.Money{}, err
+	}
+	return converter.PgNumericToMoney(total, payment.Amount.Currency), nil
+}
+
 func (r *paymentRepository) UpdateRefundPaymentStatus(ctx context.Context, params sqlc.UpdateRefundPaymentStatusParams) (*domain.PaymentRefund, error) {
 	result, err := r.queries.UpdateRefundPaymentStatus(ctx, params)
 	if err != nil {
@@ -152,3 +285,23 @@ func (r *paymentRepository) GetBatchPendingPayments(ctx context.Context) ([]doma
 
 	return toDomainPayments(results), nil
 }
+
+func (r *paymentRepository) MarkCODPaymentCollected(ctx context.Context, paymentID string, collectionID string, amount money.Money) (*domain.Payment, error) {
+	result, err := r.queries.MarkCODPaymentCollected(ctx, sqlc.MarkCODPaymentCollectedParams{
+		Amount:       converter.MoneyToPgNumeric(amount),
+		CollectionID: converter.StringToPgText(&collectionID),
+		ID:           converter.StringToPgUUID(paymentID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDomain(&result), nil
+}
+
+func (r *paymentRepository) FailPendingPayment(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	result, err := r.queries.FailPendingPayment(ctx, converter.StringToPgUUID(paymentID))
+	if err != nil {
+		return nil, err
+	}
+	return toDomain(&result), nil
+}
@@ -39,16 +39,20 @@ func Init(router *gin.Engine, dependencyContainer *dependency_container.Dependen
 	})
 
 	paymentHandler := dependencyContainer.GetPaymentHandler()
+	idempotency := common_middleware.IdempotencyMiddleware(dependencyContainer.GetRedisService(), common_middleware.IdempotencyConfig{})
 
 	v1 := router.Group("/api/v1")
 	{
 		payments := v1.Group("/payments")
 		payments.POST("/ipn/:provider", paymentHandler.HandleIPN)
+		// VNPay gọi IPN bằng GET, return URL do frontend chuyển tiếp để xác thực kết quả
+		payments.GET("/ipn/:provider", paymentHandler.HandleIPN)
+		payments.GET("/return/:provider", paymentHandler.VerifyReturn)
 
 		payments.Use(middleware.AuthHeaderMiddleware())
 		{
-			payments.POST("/initiate", paymentHandler.InitiatePayment)
-			payments.POST("/refund", paymentHandler.RefundPayment)
+			payments.POST("/initiate", idempotency, paymentHandler.InitiatePayment)
+			payments.POST("/refund", idempotency, paymentHandler.RefundPayment)
 		}
 	}
 }
@@ -26,6 +26,7 @@ const (
 type PaymentEventUseCase interface {
 	HandleSuccessPaymentEventPending()
 	HandleFailedPaymentEventPending()
+	HandleCODPlacedEventPending()
 	HandleRefundPaymentEventPending()
 	PublishRefundSucceededEvents()
 }
@@ -55,61 +56,27 @@ func NewPaymentEventUseCase(
 }
 
 func (uc *paymentEventUseCase) HandleSuccessPaymentEventPending() {
-	ctx := context.Background()
-	log.Println("[PaymentEventWorker] Starting to handle pending payment events...")
-
-	events, err := uc.eventRepo.GetBatchPaymentEventByEventTypeAndStatus(
-		ctx,
-		domain.PaymentEventTypePaymentSuccess,
-		domain.PaymentEventStatusPending,
-		BATCH_SIZE,
-	)
-	if err != nil {
-		log.Printf("[PaymentEventWorker] Error fetching pending events: %v", err)
-		return
-	}
-
-	if len(events) == 0 {
-		log.Println("[PaymentEventWorker] No pending payment events to process.")
-		return
-	}
-
-	log.Printf("[PaymentEventWorker] Found %d pending events to process.", len(events))
-
-	for _, event := range events {
-		err := uc.processUpdateOrderStatus(ctx, event)
+	uc.handleOrderStatusEvents(domain.PaymentEventTypePaymentSuccess)
+}
 
-		if err != nil {
-			log.Printf("[PaymentEventWorker] Error processing event ID %s: %v. Updating retry count.", event.ID, err)
-			event.RetryCount++
-			if event.RetryCount >= MAX_RETRY_COUNT {
-				log.Printf("CRITICAL: [EVENT_FAILURE] Event ID %s for Order ID %s has failed permanently after %d retries. Manual intervention required.", event.ID, event.OrderID, MAX_RETRY_COUNT)
-				event.EventStatus = domain.PaymentEventStatusFailed
-			}
-			_, updateErr := uc.eventRepo.UpdatePaymentEvent(ctx, event)
-			if updateErr != nil {
-				log.Printf("[PaymentEventWorker] CRITICAL: Failed to update event status after processing error for event ID %s: %v", event.ID, updateErr)
-			}
-		} else {
-			event.EventStatus = domain.PaymentEventStatusSent
-			_, updateErr := uc.eventRepo.UpdatePaymentEvent(ctx, event)
-			if updateErr != nil {
-				log.Printf("[PaymentEventWorker] CRITICAL: Failed to update event status after successful processing for event ID %s: %v", event.ID, updateErr)
-			}
-			log.Printf("[PaymentEventWorker] Successfully processed event ID %s for Order ID %s.", event.ID, event.OrderID)
-		}
-	}
+func (uc *paymentEventUseCase) HandleFailedPaymentEventPending() {
+	uc.handleOrderStatusEvents(domain.PaymentEventTypePaymentFailed)
+}
 
-	log.Printf("[PaymentEventWorker] Finished processing batch of %d events.", len(events))
+// HandleCODPlacedEventPending chuyển các đơn vừa chọn COD sang PROCESSING.
+func (uc *paymentEventUseCase) HandleCODPlacedEventPending() {
+	uc.handleOrderStatusEvents(domain.PaymentEventTypeCODPlaced)
 }
 
-func (uc *paymentEventUseCase) HandleFailedPaymentEventPending() {
+// handleOrderStatusEvents xử lý một batch event đang pending thuộc eventType bằng cách cập nhật trạng thái đơn
+// hàng tương ứng ở order-service.
+func (uc *paymentEventUseCase) handleOrderStatusEvents(eventType domain.PaymentEventType) {
 	ctx := context.Background()
-	log.Println("[PaymentEventWorker] Starting to handle pending payment events...")
+	log.Printf("[PaymentEventWorker] Starting to handle pending %s events...", eventType)
 
 	events, err := uc.eventRepo.GetBatchPaymentEventByEventTypeAndStatus(
 		ctx,
-		domain.PaymentEventTypePaymentFailed,
+		eventType,
 		domain.PaymentEventStatusPending,
 		BATCH_SIZE,
 	)
@@ -119,7 +86,7 @@ func (uc *paymentEventUseCase) HandleFailedPaymentEventPending() {
 	}
 
 	if len(events) == 0 {
-		log.Println("[PaymentEventWorker] No pending payment events to process.")
+		log.Printf("[PaymentEventWorker] No pending %s events to process.", eventType)
 		return
 	}
 
@@ -173,17 +140,28 @@ func (uc *paymentEventUseCase) HandleRefundPaymentEventPending() {
 	log.Printf("[PaymentEventWorker] Found %d pending events to process.", len(refunds))
 
 	for _, refund := range refunds {
-		err := uc.processRefundRequest(ctx, &refund)
+		fullyRefunded, err := uc.processRefundRequest(ctx, &refund)
 
 		if err != nil {
 			log.Printf("[PaymentEventWorker] Error processing event ID %s: %v. Updating retry count.", refund.ID, err)
 		} else {
+			payload, marshalErr := json.Marshal(domain.RefundSucceededPayload{
+				RefundID:    refund.ID,
+				ReferenceID: refund.ReferenceID,
+				Amount:      refund.Amount.Float64(),
+				Currency:    refund.Amount.Currency,
+				Partial:     !fullyRefunded,
+			})
+			if marshalErr != nil {
+				log.Printf("[PaymentEventWorker] Failed to marshal refund payload of refund %s: %v", refund.ID, marshalErr)
+			}
+
 			// Add payment event REFUND_SUCCEEDED
 			eventRefundSuccessed := &domain.PaymentEvent{
 				PaymentID:   refund.PaymentID,
 				OrderID:     refund.OrderID,
 				EventType:   string(domain.PaymentEventTypeRefundSuccessed),
-				Payload:     "",
+				Payload:     string(payload),
 				EventStatus: domain.PaymentEventStatusPending,
 				RetryCount:  0,
 			}
@@ -222,11 +200,24 @@ func (uc *paymentEventUseCase) PublishRefundSucceededEvents() {
 
 	for _, event := range events {
 		kafkaPayload := map[string]interface{}{
-			"event_id":   event.ID,
-			"event_type": constant.EventTypeRefundSuccessed,
-			"order_id":   event.OrderID,
-			"payment_id": event.PaymentID,
-			"source":     "payment-service",
+			"event_id":      event.ID,
+			"event_type":    constant.EventTypeRefundSuccessed,
+			"event_version": constant.EventVersionRefundSucceeded,
+			"order_id":      event.OrderID,
+			"payment_id":    event.PaymentID,
+			"source":        "payment-service",
+		}
+
+		// Thông tin hoàn tiền (số tiền, hoàn một phần hay toàn bộ) được lưu trong payload của outbox event
+		var refundPayload domain.RefundSucceededPayload
+		if event.Payload != "" && json.Unmarshal([]byte(event.Payload), &refundPayload) == nil {
+			kafkaPayload["refund_id"] = refundPayload.RefundID
+			kafkaPayload["amount"] = refundPayload.Amount
+			kafkaPayload["currency"] = refundPayload.Currency
+			kafkaPayload["partial"] = refundPayload.Partial
+			if refundPayload.ReferenceID != nil {
+				kafkaPayload["reference_id"] = *refundPayload.ReferenceID
+			}
 		}
 
 		err := uc.kafkaProducer.Publish(ctx, string(constant.EventTypeRefundSuccessed), event.OrderID, kafkaPayload)
@@ -259,10 +250,13 @@ func (uc *paymentEventUseCase) processUpdateOrderStatus(ctx context.Context, eve
 	}
 
 	var newOrderStatus order_v1.OrderStatus
-	switch payload.PaymentStatus {
-	case string(sqlc.PaymentStatusSUCCESS):
+	switch {
+	case domain.PaymentEventType(event.EventType) == domain.PaymentEventTypeCODPlaced:
+		// Payment COD vẫn PENDING tới khi shipper thu tiền nhưng đơn được xử lý ngay
 		newOrderStatus = order_v1.OrderStatus_ORDER_STATUS_PROCESSING
-	case string(sqlc.PaymentStatusFAILED):
+	case payload.PaymentStatus == string(sqlc.PaymentStatusSUCCESS):
+		newOrderStatus = order_v1.OrderStatus_ORDER_STATUS_PROCESSING
+	case payload.PaymentStatus == string(sqlc.PaymentStatusFAILED):
 		newOrderStatus = order_v1.OrderStatus_ORDER_STATUS_PAYMENT_FAILED
 	default:
 		return fmt.Errorf("unhandled payment status in event: %s", payload.PaymentStatus)
@@ -281,31 +275,37 @@ func (uc *paymentEventUseCase) processUpdateOrderStatus(ctx context.Context, eve
 	return nil
 }
 
-func (uc *paymentEventUseCase) processRefundRequest(ctx context.Context, refund *domain.PaymentRefund) error {
-	var payload struct {
-		Reason string `json:"reason"`
-	}
-
-	log.Printf("Processing refund request for OrderID %s with reason: %s", refund.OrderID, payload.Reason)
+// processRefundRequest gọi nhà cung cấp hoàn tiền cho một yêu cầu hoàn (toàn bộ hoặc một phần).
+// Trả về true khi tổng tiền đã hoàn bằng số tiền thanh toán, lúc đó payment chuyển sang REFUNDED.
+func (uc *paymentEventUseCase) processRefundRequest(ctx context.Context, refund *domain.PaymentRefund) (bool, error) {
+	log.Printf("Processing refund request %s for OrderID %s with reason: %s", refund.ID, refund.OrderID, refund.Reason)
 
 	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, refund.OrderID)
 	if err != nil {
 		log.Printf("Failed to get payment by OrderID %s: %v", refund.OrderID, err)
-		return fmt.Errorf("failed to get payment by OrderID: %w", err)
+		return false, fmt.Errorf("failed to get payment by OrderID: %w", err)
 	}
 
 	paymentMethod := payment.Provider
 	paymentProvider, err := uc.providerFactory.GetProvider(payment_constant.PaymentProviderMethod(paymentMethod))
+	if err != nil {
+		return false, fmt.Errorf("failed to get payment provider %s: %w", paymentMethod, err)
+	}
 
-	refundPayment, err := uc.paymentRepo.GetRefundByPaymentID(ctx, payment.ID)
+	if payment.ProviderTransactionID == nil {
+		return false, fmt.Errorf("payment %s has no provider transaction to refund", payment.ID)
+	}
 
 	// Call provider's refund method
 	refundData := paymentprovider.RefundData{
 		PaymentID:             refund.PaymentID,
 		OrderID:               refund.OrderID,
 		ProviderTransactionID: *payment.ProviderTransactionID,
-		Amount:                int64(payment.Amount),
-		Reason:                refundPayment.Reason,
+		Amount:                refund.Amount.Amount,
+		Reason:                refund.Reason,
+		RequestID:             payment.RequestID,
+		TransactionDate:       payment.CreatedAt,
+		PaymentAmount:         payment.Amount.Amount,
 	}
 
 	refundRes, err := paymentProvider.Refund(ctx, refundData)
@@ -313,33 +313,43 @@ func (uc *paymentEventUseCase) processRefundRequest(ctx context.Context, refund
 	if err != nil {
 		log.Printf("Error refunding payment with ID %s: %v", refund.PaymentID, err)
 		updateRefundStatusParams := sqlc.UpdateRefundPaymentStatusParams{
-			ID:           converter.StringToPgUUID(refundPayment.ID),
+			ID:           converter.StringToPgUUID(refund.ID),
 			RefundStatus: sqlc.RefundStatusFAILED,
 		}
 
 		_, err = uc.paymentRepo.UpdateRefundPaymentStatus(ctx, updateRefundStatusParams)
 		if err != nil {
 			log.Printf("Failed to update refund payment status for OrderID %s: %v", refund.OrderID, err)
-			return fmt.Errorf("failed to update refund payment status for OrderID %s: %w", refund.OrderID, err)
+			return false, fmt.Errorf("failed to update refund payment status for OrderID %s: %w", refund.OrderID, err)
 		}
 
-		return fmt.Errorf("failed to refund payment with ID %s: %w", refund.PaymentID, err)
+		return false, fmt.Errorf("failed to refund payment with ID %s: %w", refund.PaymentID, err)
 	}
 
 	if refundRes == nil {
 		log.Printf("Refund result is nil for OrderID %s", refund.OrderID)
-		return fmt.Errorf("refund result is nil for OrderID %s", refund.OrderID)
+		return false, fmt.Errorf("refund result is nil for OrderID %s", refund.OrderID)
 	}
 
 	updateRefundStatusParams := sqlc.UpdateRefundPaymentStatusParams{
-		ID:           converter.StringToPgUUID(refundPayment.ID),
+		ID:           converter.StringToPgUUID(refund.ID),
 		RefundStatus: sqlc.RefundStatusCOMPLETED,
 	}
 
 	_, err = uc.paymentRepo.UpdateRefundPaymentStatus(ctx, updateRefundStatusParams)
 	if err != nil {
 		log.Printf("Failed to update refund payment status for OrderID %s: %v", refund.OrderID, err)
-		return fmt.Errorf("failed to update refund payment status for OrderID %s: %w", refund.OrderID, err)
+		return false, fmt.Errorf("failed to update refund payment status for OrderID %s: %w", refund.OrderID, err)
+	}
+
+	// Hoàn một phần thì payment vẫn giữ trạng thái SUCCESS
+	refundedAmount, err := uc.paymentRepo.GetCompletedRefundedAmount(ctx, payment)
+	if err != nil {
+		return false, fmt.Errorf("failed to get refunded amount of payment %s: %w", payment.ID, err)
+	}
+	if refundedAmount.Amount < payment.Amount.Amount {
+		log.Printf("Partial refund %s for OrderID %s processed successfully (%s/%s refunded).", refund.ID, refund.OrderID, refundedAmount, payment.Amount)
+		return false, nil
 	}
 
 	updatePaymentStatus := sqlc.UpdatePaymentStatusParams{
@@ -352,10 +362,10 @@ func (uc *paymentEventUseCase) processRefundRequest(ctx context.Context, refund
 
 	if err != nil {
 		log.Printf("Failed to update payment status for OrderID %s: %v", refund.OrderID, err)
-		return fmt.Errorf("failed to update payment status for OrderID %s: %w", refund.OrderID, err)
+		return false, fmt.Errorf("failed to update payment status for OrderID %s: %w", refund.OrderID, err)
 	}
 
 	log.Printf("Refund request for OrderID %s processed successfully.", refund.OrderID)
 
-	return nil
+	return true, nil
 }
@@ -2,15 +2,21 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	kafka_infra "github.com/toji-dev/go-shop/internal/pkg/infra/kafka-infra"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/db/sqlc"
@@ -19,13 +25,47 @@ import (
 	grpc_adapter "github.com/toji-dev/go-shop/internal/services/payment-service/internal/grpc/adapter"
 	paymentprovider "github.com/toji-dev/go-shop/internal/services/payment-service/internal/payment_provider"
 	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/repository"
+	commonv1 "github.com/toji-dev/go-shop/proto/gen/go/common/v1"
 	order_v1 "github.com/toji-dev/go-shop/proto/gen/go/order/v1"
 )
 
+var (
+	// ErrPaymentNotFound được trả về khi kết quả từ cổng thanh toán không khớp với payment nào.
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrAmountMismatch được trả về khi số tiền cổng thanh toán báo khác số tiền của payment.
+	ErrAmountMismatch = errors.New("amount mismatch")
+	// ErrNotCODPayment được trả về khi xác nhận thu tiền mặt cho đơn không thanh toán COD.
+	ErrNotCODPayment = errors.New("payment is not cash on delivery")
+	// ErrPaymentNotCollectable được trả về khi payment COD không còn chờ thu tiền (ví dụ đã FAILED).
+	ErrPaymentNotCollectable = errors.New("payment is not awaiting cash collection")
+	// ErrPaymentNotCancelable được trả về khi huỷ payment mà khách đã thanh toán (hoặc shipper đã thu tiền),
+	// đơn cần được hoàn tiền thay vì huỷ.
+	ErrPaymentNotCancelable = errors.New("payment can no longer be canceled")
+	// ErrPaymentAlreadyExists được trả về khi khởi tạo thanh toán cho đơn đã có payment chưa thất bại, mỗi đơn chỉ có
+	// một payment nên không đổi được phương thức thanh toán sau khi đã chọn, trừ khi lần thanh toán trước thất bại.
+	ErrPaymentAlreadyExists = errors.New("order already has a payment")
+)
+
+// lateCaptureRefundReason là lý do hoàn tiền cho khoản khách trả sau khi payment đã bị huỷ.
+const lateCaptureRefundReason = "payment received after the order was canceled"
+
 type PaymentUseCase interface {
 	InitiatePayment(ctx context.Context, userID string, req dto.InitiatePaymentRequest) (*dto.InitiatePaymentResponse, error)
 	HandleIPN(ctx context.Context, providerName constant.PaymentProviderMethod, r *http.Request) error
+	// VerifyReturn kiểm tra kết quả thanh toán provider gắn vào URL khi redirect khách về, không cập nhật payment.
+	VerifyReturn(ctx context.Context, providerName constant.PaymentProviderMethod, query url.Values) (*dto.PaymentReturnResponse, error)
 	Refund(ctx context.Context, paymentID, orderID, reason string) (*dto.RefundResult, error)
+	RequestPartialRefund(ctx context.Context, orderID, referenceID string, amount money.Money, reason string) (*dto.RefundResult, error)
+	// ConfirmCashCollected ghi nhận shipper đã thu tiền mặt của đơn COD, chuyển payment sang SUCCESS với số tiền thực thu
+	// (nhỏ hơn số tiền lúc đặt khi người bán đã huỷ một số dòng hàng).
+	// alreadyConfirmed = true khi payment đã được xác nhận từ trước (order-service gọi lại sau khi mất phản hồi).
+	ConfirmCashCollected(ctx context.Context, orderID, collectionID string, amount money.Money) (payment *domain.Payment, alreadyConfirmed bool, err error)
+	// CancelCODPayment chuyển payment COD chưa thu tiền của đơn bị huỷ sang FAILED.
+	// alreadyCanceled = true khi payment đã FAILED từ trước.
+	CancelCODPayment(ctx context.Context, orderID, reason string) (payment *domain.Payment, alreadyCanceled bool, err error)
+	// CancelPendingPayment chuyển payment chưa thanh toán (mọi phương thức) của đơn hết hạn thanh toán sang FAILED,
+	// trả về ErrPaymentNotCancelable nếu khách đã thanh toán. alreadyCanceled = true khi payment đã FAILED từ trước.
+	CancelPendingPayment(ctx context.Context, orderID, reason string) (payment *domain.Payment, alreadyCanceled bool, err error)
 	HandlePendingPaymentTooLong()
 }
 
@@ -58,6 +98,10 @@ func NewPaymentUsecase(
 }
 
 func (uc *paymentUseCase) InitiatePayment(ctx context.Context, userID string, req dto.InitiatePaymentRequest) (*dto.InitiatePaymentResponse, error) {
+	if constant.PaymentProviderMethod(strings.ToUpper(req.PaymentMethod)) == constant.CODProviderMethod {
+		return uc.initiateCODPayment(ctx, userID, req)
+	}
+
 	// 1. Lấy provider từ factory
 	paymentProvider, err := uc.providerFactory.GetProvider(constant.PaymentProviderMethod(req.PaymentMethod))
 	if err != nil {
@@ -65,28 +109,25 @@ func (uc *paymentUseCase) InitiatePayment(ctx context.Context, userID string, re
 		return nil, err
 	}
 
-	orderRequest := &order_v1.GetOrderRequest{
-		OrderId: req.OrderID,
-	}
-
-	order, err := uc.orderAdapter.GetOrderInfo(ctx, orderRequest)
-
+	order, err := uc.getPayableOrder(ctx, userID, req.OrderID)
 	if err != nil {
-		log.Printf("Error retrieving order info for OrderID %s: %v", req.OrderID, err)
-		return nil, fmt.Errorf("could not retrieve order info: %w", err)
+		return nil, err
 	}
 
-	if order == nil {
-		log.Printf("Order not found for OrderID %s", req.OrderID)
-		return nil, fmt.Errorf("order not found for ID: %s", req.OrderID)
+	if order.GetOrderStatus() != order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT {
+		log.Printf("Order %s is %s, cannot be paid via %s", req.OrderID, order.GetOrderStatus(), paymentProvider.GetName())
+		return nil, fmt.Errorf("order %s is not awaiting payment", req.OrderID)
 	}
 
-	if order.Order.CustomerId != userID {
-		log.Printf("User %s is not authorized to pay for OrderID %s", userID, req.OrderID)
-		return nil, fmt.Errorf("user is not authorized to pay for this order")
+	amount := orderTotal(order)
+	// Kiểm tra trước khi tạo payment, provider chỉ nhận số tiền theo đơn vị nhỏ nhất của tiền tệ nó hỗ trợ
+	if err := paymentprovider.CheckCurrency(paymentProvider, amount.Currency); err != nil {
+		log.Printf("Cannot pay OrderID %s of %s via %s: %v", req.OrderID, amount, paymentProvider.GetName(), err)
+		return nil, err
+	}
+	if err := uc.ensureNoPayment(ctx, req.OrderID); err != nil {
+		return nil, err
 	}
-
-	amount := float64(order.Order.FinalAmount)
 	paymentMethod := strings.ToUpper(req.PaymentMethod)
 
 	requestID := uuid.New().String()
@@ -95,8 +136,8 @@ func (uc *paymentUseCase) InitiatePayment(ctx context.Context, userID string, re
 	params := sqlc.CreatePaymentParams{
 		OrderID:         converter.StringToPgUUID(req.OrderID),
 		UserID:          converter.StringToPgUUID(userID),
-		Amount:          converter.Float64ToPgNumeric(amount),
-		Currency:        "VND",
+		Amount:          converter.MoneyToPgNumeric(amount),
+		Currency:        amount.Currency,
 		PaymentMethod:   sqlc.PaymentMethodEWALLET,
 		PaymentProvider: converter.StringToPgText(&paymentMethod),
 		RequestID:       converter.StringToPgText(&requestID),
@@ -106,17 +147,20 @@ func (uc *paymentUseCase) InitiatePayment(ctx context.Context, userID string, re
 
 	if err != nil {
 		log.Printf("Error creating payment record for OrderID %s: %v", req.OrderID, err)
-		return nil, fmt.Errorf("could not create payment record: %w", err)
+		return nil, toCreatePaymentError(req.OrderID, err)
 	}
 
 	// 4. Gọi provider để tạo link thanh toán
 	paymentData := paymentprovider.PaymentData{
 		RequestID:   requestID,
 		OrderID:     req.OrderID,
-		Amount:      int64(amount),
+		Amount:      amount.Amount,
+		Currency:    amount.Currency,
 		OrderInfo:   fmt.Sprintf("Thanh_toan_don_hang_%s", req.OrderID),
 		IPNURL:      fmt.Sprintf("%s/api/v1/payments/ipn/%s", uc.appConfig.ApiGatewayURL, strings.ToLower(string(paymentProvider.GetName()))),
 		RedirectURL: fmt.Sprintf("%s/orders/%s/result", uc.appConfig.FrontendURL, req.OrderID),
+		ClientIP:    req.ClientIP,
+		CreatedAt:   paymentRecord.CreatedAt,
 	}
 
 	log.Printf("IPN URL: %s", paymentData.IPNURL)
@@ -135,6 +179,110 @@ func (uc *paymentUseCase) InitiatePayment(ctx context.Context, userID string, re
 	}, nil
 }
 
+// initiateCODPayment tạo payment COD ở trạng thái PENDING, không qua cổng thanh toán. Event COD_PLACED được tạo cùng
+// payment để worker chuyển đơn sang PROCESSING, payment chỉ SUCCESS khi shipper xác nhận đã thu tiền.
+func (uc *paymentUseCase) initiateCODPayment(ctx context.Context, userID string, req dto.InitiatePaymentRequest) (*dto.InitiatePaymentResponse, error) {
+	order, err := uc.getPayableOrder(ctx, userID, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.GetOrderStatus() != order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT {
+		log.Printf("Order %s is %s, cannot be paid by cash on delivery", req.OrderID, order.GetOrderStatus())
+		return nil, fmt.Errorf("order %s is not awaiting payment", req.OrderID)
+	}
+	if err := uc.ensureNoPayment(ctx, req.OrderID); err != nil {
+		return nil, err
+	}
+
+	amount := orderTotal(order)
+	provider := string(constant.CODProviderMethod)
+	requestID := uuid.New().String()
+
+	params := sqlc.CreatePaymentParams{
+		OrderID:         converter.StringToPgUUID(req.OrderID),
+		UserID:          converter.StringToPgUUID(userID),
+		Amount:          converter.MoneyToPgNumeric(amount),
+		Currency:        amount.Currency,
+		PaymentMethod:   sqlc.PaymentMethodCOD,
+		PaymentProvider: converter.StringToPgText(&provider),
+		RequestID:       converter.StringToPgText(&requestID),
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"payment_status": string(constant.PaymentStatusPending),
+		"payment_method": string(constant.PaymentMethodCOD),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", domain.PaymentEventTypeCODPlaced, err)
+	}
+
+	paymentRecord, err := uc.paymentRepo.CreateCODPayment(ctx, params, string(payload))
+	if err != nil {
+		log.Printf("Error creating COD payment for OrderID %s: %v", req.OrderID, err)
+		return nil, toCreatePaymentError(req.OrderID, err)
+	}
+
+	return &dto.InitiatePaymentResponse{
+		PaymentID: paymentRecord.ID,
+		Message:   "Cash on delivery selected, pay the shipper when the order arrives.",
+	}, nil
+}
+
+// ensureNoPayment trả về ErrPaymentAlreadyExists khi đơn đã có payment chưa thất bại (payments.order_id là UNIQUE). Payment ví
+// điện tử đang chờ không được thay bằng payment khác vì khách vẫn có thể thanh toán qua link cũ. Payment FAILED được
+// CreatePayment khởi tạo lại để khách thanh toán lại đơn sau PAYMENT_FAILED.
+func (uc *paymentUseCase) ensureNoPayment(ctx context.Context, orderID string) error {
+	existing, err := uc.paymentRepo.GetPaymentByOrderID(ctx, orderID)
+	if err == nil {
+		if existing.Status == constant.PaymentStatusFailed {
+			log.Printf("OrderID %s has failed %s payment %s, retrying it", orderID, existing.Provider, existing.ID)
+			return nil
+		}
+		log.Printf("OrderID %s already has %s payment %s (%s)", orderID, existing.Provider, existing.ID, existing.Status)
+		return fmt.Errorf("%w: %s payment of order %s is %s", ErrPaymentAlreadyExists, existing.Provider, orderID, existing.Status)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+	}
+	return nil
+}
+
+// toCreatePaymentError chuyển lỗi khi hai yêu cầu khởi tạo thanh toán chạy song song thành ErrPaymentAlreadyExists:
+// trùng order_id, hoặc không có dòng nào trả về vì payment FAILED vừa được yêu cầu kia khởi tạo lại.
+func toCreatePaymentError(orderID string, err error) error {
+	var pgErr *pgconn.PgError
+	// 23505: unique_violation
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" || errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: order %s", ErrPaymentAlreadyExists, orderID)
+	}
+	return fmt.Errorf("could not create payment record: %w", err)
+}
+
+// getPayableOrder lấy đơn hàng từ order-service và kiểm tra userID là chủ đơn.
+func (uc *paymentUseCase) getPayableOrder(ctx context.Context, userID, orderID string) (*order_v1.Order, error) {
+	order, err := uc.orderAdapter.GetOrderInfo(ctx, &order_v1.GetOrderRequest{
+		OrderId: orderID,
+	})
+
+	if err != nil {
+		log.Printf("Error retrieving order info for OrderID %s: %v", orderID, err)
+		return nil, fmt.Errorf("could not retrieve order info: %w", err)
+	}
+
+	if order == nil || !order.GetExists() {
+		log.Printf("Order not found for OrderID %s", orderID)
+		return nil, fmt.Errorf("order not found for ID: %s", orderID)
+	}
+
+	if order.Order.CustomerId != userID {
+		log.Printf("User %s is not authorized to pay for OrderID %s", userID, orderID)
+		return nil, fmt.Errorf("user is not authorized to pay for this order")
+	}
+
+	return order.GetOrder(), nil
+}
+
 func (uc *paymentUseCase) HandleIPN(ctx context.Context, provider constant.PaymentProviderMethod, r *http.Request) error {
 	log.Printf("Handling IPN for provider: %s", provider)
 	// 1. Lấy provider từ factory
@@ -156,28 +304,55 @@ func (uc *paymentUseCase) HandleIPN(ctx context.Context, provider constant.Payme
 	// 3. Lấy thông tin payment gốc từ DB
 	originalPayment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, paymentUpdate.OrderID)
 	if err != nil {
-		return fmt.Errorf("original payment record not found for order %s", paymentUpdate.OrderID)
+		return fmt.Errorf("%w: original payment record not found for order %s", ErrPaymentNotFound, paymentUpdate.OrderID)
+	}
+	// IPN chỉ được cập nhật payment tạo qua chính provider này, trong đúng lần khởi tạo thanh toán đó
+	if !strings.EqualFold(originalPayment.Provider, string(paymentProvider.GetName())) {
+		log.Printf("IPN from %s ignored: payment %s of OrderID %s was created via %s", paymentProvider.GetName(), originalPayment.ID, originalPayment.OrderID, originalPayment.Provider)
+		return fmt.Errorf("%w: order %s is not paid via %s", ErrPaymentNotFound, paymentUpdate.OrderID, paymentProvider.GetName())
+	}
+	if originalPayment.RequestID != paymentUpdate.RequestID {
+		log.Printf("IPN ignored: request %s does not match payment %s of OrderID %s", paymentUpdate.RequestID, originalPayment.ID, originalPayment.OrderID)
+		return fmt.Errorf("%w: IPN belongs to another payment attempt of order %s", ErrPaymentNotFound, paymentUpdate.OrderID)
 	}
 
+	return uc.applyPaymentResult(ctx, originalPayment, paymentUpdate)
+}
+
+// applyPaymentResult ghi kết quả thanh toán từ cổng thanh toán vào payment còn PENDING và tạo event cho order-service.
+func (uc *paymentUseCase) applyPaymentResult(ctx context.Context, originalPayment *domain.Payment, paymentUpdate *domain.Payment) error {
 	// 4. Kiểm tra logic nghiệp vụ
-	if originalPayment.Status != constant.PaymentStatusPending {
+	switch {
+	case originalPayment.Status == constant.PaymentStatusPending:
+	case originalPayment.Status == constant.PaymentStatusFailed && paymentUpdate.Status == constant.PaymentStatusSuccess:
+		// Payment đã bị huỷ (đơn hết hạn thanh toán) nhưng khách vẫn trả tiền được qua link cũ
+	default:
 		log.Printf("Payment for OrderID %s already processed. Status: %s. Ignoring IPN.", originalPayment.OrderID, originalPayment.Status)
 		return nil
 	}
-	if originalPayment.Amount != paymentUpdate.Amount {
-		log.Printf("Amount mismatch for OrderID %s. DB: %f, Provider: %f", originalPayment.OrderID, originalPayment.Amount, paymentUpdate.Amount)
-		return fmt.Errorf("amount mismatch")
+	if !originalPayment.Amount.Equal(paymentUpdate.Amount) {
+		log.Printf("Amount mismatch for OrderID %s. DB: %s, Provider: %s", originalPayment.OrderID, originalPayment.Amount, paymentUpdate.Amount)
+		return ErrAmountMismatch
+	}
+	if originalPayment.Status == constant.PaymentStatusFailed {
+		return uc.refundLateCapture(ctx, originalPayment, paymentUpdate.ProviderTransactionID)
 	}
 
-	// 5. Cập nhật trạng thái payment
-	updateParams := sqlc.UpdatePaymentStatusParams{
+	// 5. Cập nhật trạng thái payment, payment có thể vừa bị huỷ do đơn hết hạn thanh toán
+	updateParams := sqlc.UpdatePendingPaymentStatusParams{
 		ID:                    converter.StringToPgUUID(originalPayment.ID),
 		PaymentStatus:         sqlc.PaymentStatus(paymentUpdate.Status),
 		ProviderTransactionID: converter.StringToPgText(paymentUpdate.ProviderTransactionID),
 	}
-	_, err = uc.paymentRepo.UpdatePaymentStatus(ctx, updateParams)
-
+	_, err := uc.paymentRepo.UpdatePendingPaymentStatus(ctx, updateParams)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			current, getErr := uc.paymentRepo.GetPaymentByOrderID(ctx, originalPayment.OrderID)
+			if getErr != nil {
+				return fmt.Errorf("failed to get payment of order %s: %w", originalPayment.OrderID, getErr)
+			}
+			return uc.applyPaymentResult(ctx, current, paymentUpdate)
+		}
 		log.Printf("Error updating payment status for OrderID %s: %v", originalPayment.OrderID, err)
 		return fmt.Errorf("failed to update payment status for order %s: %w", originalPayment.OrderID, err)
 	}
@@ -191,8 +366,8 @@ func (uc *paymentUseCase) HandleIPN(ctx context.Context, provider constant.Payme
 	}
 
 	paymentEventSuccess := &domain.PaymentEvent{
-		PaymentID:   paymentUpdate.ID,
-		OrderID:     paymentUpdate.OrderID,
+		PaymentID:   originalPayment.ID,
+		OrderID:     originalPayment.OrderID,
 		EventType:   eventType,
 		Payload:     "",
 		EventStatus: domain.PaymentEventStatusPending,
@@ -209,38 +384,330 @@ func (uc *paymentUseCase) HandleIPN(ctx context.Context, provider constant.Payme
 	return nil
 }
 
+// refundLateCapture xử lý tiền khách trả cho payment đã bị huỷ: đơn đã bị huỷ và trả lại hàng giữ nên không giao được nữa.
+// Payment được ghi nhận SUCCESS rồi hoàn tiền ngay, không gửi event thanh toán thành công cho order-service.
+// Nếu bước hoàn tiền lỗi, reconciler của order-service sẽ phát hiện đơn đã huỷ có payment thành công chưa được hoàn.
+func (uc *paymentUseCase) refundLateCapture(ctx context.Context, payment *domain.Payment, providerTransactionID *string) error {
+	log.Printf("Payment %s of OrderID %s was paid after it had been canceled, refunding", payment.ID, payment.OrderID)
+
+	_, err := uc.paymentRepo.UpdatePaymentStatus(ctx, sqlc.UpdatePaymentStatusParams{
+		ID:                    converter.StringToPgUUID(payment.ID),
+		PaymentStatus:         sqlc.PaymentStatusSUCCESS,
+		ProviderTransactionID: converter.StringToPgText(providerTransactionID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record late payment %s of order %s: %w", payment.ID, payment.OrderID, err)
+	}
+
+	if _, err := uc.Refund(ctx, payment.ID, payment.OrderID, lateCaptureRefundReason); err != nil {
+		return fmt.Errorf("failed to refund late payment %s of order %s: %w", payment.ID, payment.OrderID, err)
+	}
+	return nil
+}
+
+func (uc *paymentUseCase) VerifyReturn(ctx context.Context, provider constant.PaymentProviderMethod, query url.Values) (*dto.PaymentReturnResponse, error) {
+	paymentProvider, err := uc.providerFactory.GetProvider(provider)
+	if err != nil {
+		log.Printf("Error getting payment provider %s: %v", provider, err)
+		return nil, err
+	}
+
+	verifier, ok := paymentProvider.(paymentprovider.ReturnVerifier)
+	if !ok {
+		return nil, fmt.Errorf("payment provider '%s' does not support return verification", provider)
+	}
+
+	result, err := verifier.VerifyReturn(query)
+	if err != nil {
+		log.Printf("Error verifying return for provider %s: %v", provider, err)
+		return nil, fmt.Errorf("failed to verify return: %w", err)
+	}
+
+	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, result.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: order %s", ErrPaymentNotFound, result.OrderID)
+	}
+	if payment.RequestID != result.RequestID {
+		// Kết quả của một lần khởi tạo thanh toán cũ hơn của cùng đơn
+		return nil, fmt.Errorf("%w: return result belongs to another payment attempt of order %s", ErrPaymentNotFound, result.OrderID)
+	}
+	if !payment.Amount.Equal(result.Amount) {
+		log.Printf("Amount mismatch for OrderID %s. DB: %s, Provider: %s", payment.OrderID, payment.Amount, result.Amount)
+		return nil, ErrAmountMismatch
+	}
+
+	// PaymentStatus có thể vẫn PENDING nếu IPN chưa tới, frontend hiển thị theo ResultStatus
+	return &dto.PaymentReturnResponse{
+		PaymentID:     payment.ID,
+		OrderID:       payment.OrderID,
+		ResultStatus:  string(result.Status),
+		PaymentStatus: string(payment.Status),
+	}, nil
+}
+
 func (uc *paymentUseCase) Refund(ctx context.Context, paymentID, orderID, reason string) (*dto.RefundResult, error) {
-	log.Printf("Refunding payment with ID: %s", paymentID)
+	log.Printf("Refunding payment for OrderID: %s", orderID)
 	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, orderID)
 	if err != nil {
-		log.Printf("Error retrieving payment with ID %s: %v", paymentID, err)
-		return nil, fmt.Errorf("failed to get payment with ID %s: %w", paymentID, err)
+		log.Printf("Error retrieving payment of OrderID %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
 	}
 
-	if payment.Status != constant.PaymentStatusSuccess {
-		log.Printf("Payment with ID %s is not eligible for refund, current status: %s", paymentID, payment.Status)
-		return nil, fmt.Errorf("payment with ID %s is not eligible for refund, current status: %s", paymentID, payment.Status)
+	// paymentID có thể để trống khi được gọi từ order-service (chỉ biết order_id)
+	if paymentID != "" && payment.ID != paymentID {
+		log.Printf("Payment %s does not belong to OrderID %s", paymentID, orderID)
+		return nil, fmt.Errorf("payment %s does not belong to order %s", paymentID, orderID)
 	}
 
-	param := sqlc.CreateRefundPaymentParams{
-		PaymentID: converter.StringToPgUUID(paymentID),
-		OrderID:   converter.StringToPgUUID(orderID),
-		Reason:    converter.StringToPgText(&reason),
+	// Idempotency: nếu đã có yêu cầu hoàn tiền chưa thất bại thì trả về yêu cầu đó
+	existingRefund, err := uc.paymentRepo.GetRefundByPaymentID(ctx, payment.ID)
+	if err == nil && existingRefund.RefundStatus != constant.RefundStatusFailed {
+		log.Printf("Refund %s already exists for payment %s with status %s", existingRefund.ID, payment.ID, existingRefund.RefundStatus)
+		return &dto.RefundResult{
+			RefundID: existingRefund.ID,
+			Status:   string(existingRefund.RefundStatus),
+			Amount:   existingRefund.Amount,
+			Message:  "Refund request already exists.",
+		}, nil
 	}
 
-	_, err = uc.paymentRepo.CreatePaymentRefund(ctx, param)
+	// Hoàn phần còn lại sau các lần hoàn một phần trước đó, tính trên payment đã được khoá
+	refund, err := uc.paymentRepo.CreateRefundWithinRefundable(ctx, payment.ID, func(payment *domain.Payment, refundable money.Money) (sqlc.CreateRefundPaymentParams, error) {
+		if err := checkRefundable(payment); err != nil {
+			return sqlc.CreateRefundPaymentParams{}, err
+		}
+		if !refundable.IsPositive() {
+			return sqlc.CreateRefundPaymentParams{}, fmt.Errorf("payment with ID %s has already been fully refunded", payment.ID)
+		}
+		return sqlc.CreateRefundPaymentParams{
+			PaymentID:    converter.StringToPgUUID(payment.ID),
+			OrderID:      converter.StringToPgUUID(orderID),
+			Amount:       converter.MoneyToPgNumeric(refundable),
+			Reason:       converter.StringToPgText(&reason),
+			RefundStatus: sqlc.RefundStatusPENDING,
+			Currency:     refundable.Currency,
+		}, nil
+	})
+	if err != nil {
+		log.Printf("Error creating refund record for PaymentID %s: %v", payment.ID, err)
+		return nil, fmt.Errorf("failed to create refund record for payment %s: %w", payment.ID, err)
+	}
+
+	return &dto.RefundResult{
+		RefundID: refund.ID,
+		Status:   string(sqlc.RefundStatusPENDING),
+		Amount:   refund.Amount,
+		Message:  "Refund request accepted, processing will take some time.",
+	}, nil
+}
 
+// RequestPartialRefund tạo yêu cầu hoàn một phần số tiền của đơn hàng (ví dụ khi khách trả lại một số sản phẩm).
+// referenceID định danh yêu cầu ở phía bên gọi, gọi lại với cùng referenceID sẽ trả về yêu cầu đã tạo.
+func (uc *paymentUseCase) RequestPartialRefund(ctx context.Context, orderID, referenceID string, amount money.Money, reason string) (*dto.RefundResult, error) {
+	log.Printf("Requesting partial refund of %s for OrderID: %s, reference: %s", amount, orderID, referenceID)
+	if referenceID == "" {
+		return nil, fmt.Errorf("reference_id is required for partial refunds")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("refund amount must be greater than 0")
+	}
+
+	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, orderID)
 	if err != nil {
-		log.Printf("Error creating refund record for PaymentID %s: %v", paymentID, err)
-		return nil, fmt.Errorf("failed to create refund record for payment %s: %w", paymentID, err)
+		log.Printf("Error retrieving payment of OrderID %s: %v", orderID, err)
+		return nil, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+	}
+
+	existingRefund, err := uc.paymentRepo.GetRefundByReference(ctx, payment.ID, referenceID)
+	if err == nil {
+		if existingRefund.RefundStatus != constant.RefundStatusFailed {
+			return &dto.RefundResult{
+				RefundID: existingRefund.ID,
+				Status:   string(existingRefund.RefundStatus),
+				Amount:   existingRefund.Amount,
+				Message:  "Refund request already exists.",
+			}, nil
+		}
+
+		// Lần trước thất bại: đưa yêu cầu về PENDING để worker xử lý lại
+		retried, err := uc.paymentRepo.UpdateRefundPaymentStatus(ctx, sqlc.UpdateRefundPaymentStatusParams{
+			ID:           converter.StringToPgUUID(existingRefund.ID),
+			RefundStatus: sqlc.RefundStatusPENDING,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retry refund %s: %w", existingRefund.ID, err)
+		}
+		return &dto.RefundResult{
+			RefundID: retried.ID,
+			Status:   string(retried.RefundStatus),
+			Amount:   retried.Amount,
+			Message:  "Failed refund request has been queued again.",
+		}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get refund %s of payment %s: %w", referenceID, payment.ID, err)
+	}
+
+	refund, err := uc.paymentRepo.CreateRefundWithinRefundable(ctx, payment.ID, func(payment *domain.Payment, refundable money.Money) (sqlc.CreateRefundPaymentParams, error) {
+		if err := checkRefundable(payment); err != nil {
+			return sqlc.CreateRefundPaymentParams{}, err
+		}
+		cmp, err := amount.Cmp(refundable)
+		if err != nil {
+			return sqlc.CreateRefundPaymentParams{}, fmt.Errorf("refund amount %s does not match payment %s currency: %w", amount, payment.ID, err)
+		}
+		if cmp > 0 {
+			return sqlc.CreateRefundPaymentParams{}, fmt.Errorf("refund amount %s exceeds refundable amount %s of payment %s", amount, refundable, payment.ID)
+		}
+		return sqlc.CreateRefundPaymentParams{
+			PaymentID:    converter.StringToPgUUID(payment.ID),
+			OrderID:      converter.StringToPgUUID(orderID),
+			Amount:       converter.MoneyToPgNumeric(amount),
+			Reason:       converter.StringToPgText(&reason),
+			RefundStatus: sqlc.RefundStatusPENDING,
+			ReferenceID:  converter.StringToPgText(&referenceID),
+			Currency:     amount.Currency,
+		}, nil
+	})
+	if err != nil {
+		log.Printf("Error creating partial refund record for PaymentID %s: %v", payment.ID, err)
+		return nil, fmt.Errorf("failed to create refund record for payment %s: %w", payment.ID, err)
 	}
 
 	return &dto.RefundResult{
-		Status:  string(sqlc.RefundStatusPENDING),
-		Message: "Refund request accepted, processing will take some time.",
+		RefundID: refund.ID,
+		Status:   string(sqlc.RefundStatusPENDING),
+		Amount:   refund.Amount,
+		Message:  "Partial refund request accepted, processing will take some time.",
 	}, nil
 }
 
+func (uc *paymentUseCase) ConfirmCashCollected(ctx context.Context, orderID, collectionID string, amount money.Money) (*domain.Payment, bool, error) {
+	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("%w: order %s", ErrPaymentNotFound, orderID)
+		}
+		return nil, false, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+	}
+
+	if payment.Method != constant.PaymentMethodCOD {
+		return nil, false, fmt.Errorf("%w: order %s is paid by %s", ErrNotCODPayment, orderID, payment.Method)
+	}
+
+	switch payment.Status {
+	case constant.PaymentStatusSuccess, constant.PaymentStatusRefunded:
+		// Payment đã lưu số tiền thực thu của lần xác nhận trước
+		if !payment.Amount.Equal(amount) {
+			log.Printf("Collected amount mismatch for OrderID %s. DB: %s, collected: %s", orderID, payment.Amount, amount)
+			return nil, false, ErrAmountMismatch
+		}
+		return payment, true, nil
+	case constant.PaymentStatusPending:
+	default:
+		return nil, false, fmt.Errorf("%w: payment %s is %s", ErrPaymentNotCollectable, payment.ID, payment.Status)
+	}
+
+	// Người bán huỷ bớt dòng hàng thì số tiền thu được nhỏ hơn số tiền lúc đặt, nhưng không bao giờ lớn hơn
+	if cmp, err := amount.Cmp(payment.Amount); err != nil || cmp > 0 || !amount.IsPositive() {
+		log.Printf("Collected amount mismatch for OrderID %s. DB: %s, collected: %s", orderID, payment.Amount, amount)
+		return nil, false, ErrAmountMismatch
+	}
+
+	collected, err := uc.paymentRepo.MarkCODPaymentCollected(ctx, payment.ID, collectionID, amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Một lần gọi khác vừa xác nhận cùng payment
+			return uc.ConfirmCashCollected(ctx, orderID, collectionID, amount)
+		}
+		log.Printf("Error confirming cash collection of payment %s: %v", payment.ID, err)
+		return nil, false, fmt.Errorf("failed to confirm cash collection of payment %s: %w", payment.ID, err)
+	}
+
+	log.Printf("Cash of %s collected for COD payment %s of OrderID %s (collection %s)", amount, payment.ID, orderID, collectionID)
+	return collected, false, nil
+}
+
+func (uc *paymentUseCase) CancelCODPayment(ctx context.Context, orderID, reason string) (*domain.Payment, bool, error) {
+	return uc.cancelPendingPayment(ctx, orderID, reason, true)
+}
+
+func (uc *paymentUseCase) CancelPendingPayment(ctx context.Context, orderID, reason string) (*domain.Payment, bool, error) {
+	return uc.cancelPendingPayment(ctx, orderID, reason, false)
+}
+
+// cancelPendingPayment chuyển payment PENDING của đơn sang FAILED, codOnly chỉ cho huỷ payment COD.
+func (uc *paymentUseCase) cancelPendingPayment(ctx context.Context, orderID, reason string, codOnly bool) (*domain.Payment, bool, error) {
+	payment, err := uc.paymentRepo.GetPaymentByOrderID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("%w: order %s", ErrPaymentNotFound, orderID)
+		}
+		return nil, false, fmt.Errorf("failed to get payment of order %s: %w", orderID, err)
+	}
+
+	if codOnly && payment.Method != constant.PaymentMethodCOD {
+		return nil, false, fmt.Errorf("%w: order %s is paid by %s", ErrNotCODPayment, orderID, payment.Method)
+	}
+
+	switch payment.Status {
+	case constant.PaymentStatusFailed:
+		return payment, true, nil
+	case constant.PaymentStatusPending:
+	default:
+		return nil, false, fmt.Errorf("%w: payment %s is %s", ErrPaymentNotCancelable, payment.ID, payment.Status)
+	}
+
+	canceled, err := uc.paymentRepo.FailPendingPayment(ctx, payment.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Payment vừa được thanh toán hoặc huỷ bởi một lần gọi khác
+			return uc.cancelPendingPayment(ctx, orderID, reason, codOnly)
+		}
+		log.Printf("Error canceling payment %s: %v", payment.ID, err)
+		return nil, false, fmt.Errorf("failed to cancel payment %s: %w", payment.ID, err)
+	}
+
+	log.Printf("%s payment %s of OrderID %s canceled: %s", payment.Method, payment.ID, orderID, reason)
+	return canceled, false, nil
+}
+
+// checkRefundable kiểm tra payment (đã được khoá) còn ở trạng thái có thể hoàn tiền.
+func checkRefundable(payment *domain.Payment) error {
+	if payment.Status != constant.PaymentStatusSuccess {
+		log.Printf("Payment with ID %s is not eligible for refund, current status: %s", payment.ID, payment.Status)
+		return fmt.Errorf("payment with ID %s is not eligible for refund, current status: %s", payment.ID, payment.Status)
+	}
+	return nil
+}
+
+// orderTotal trả về số tiền khách phải trả của đơn hàng. Order-service cũ chưa gửi total thì dùng final_amount
+// theo tiền tệ của các dòng hàng, đơn cũ không có tiền tệ dùng money.DefaultCurrency giống order-service.
+func orderTotal(order *order_v1.Order) money.Money {
+	if total, ok := money.FromProto(order.GetTotal()); ok {
+		return total
+	}
+	return money.FromMajor(float64(order.GetFinalAmount()), orderCurrency(order))
+}
+
+// orderCurrency trả về tiền tệ của đơn hàng theo các số tiền order-service gửi kèm.
+func orderCurrency(order *order_v1.Order) string {
+	for _, pb := range []*commonv1.Money{order.GetSubtotal(), order.GetShipping(), order.GetDiscount()} {
+		if pb.GetCurrency() != "" {
+			return pb.GetCurrency()
+		}
+	}
+	for _, item := range order.GetItems() {
+		if currency := item.GetUnitPrice().GetCurrency(); currency != "" {
+			return currency
+		}
+		if currency := item.GetCurrency(); currency != "" {
+			return currency
+		}
+	}
+	return money.DefaultCurrency
+}
+
 func (uc *paymentUseCase) HandlePendingPaymentTooLong() {
 	log.Println("Checking for pending payments that have been pending too long...")
 
@@ -279,13 +746,20 @@ func (uc *paymentUseCase) HandlePendingPaymentTooLong() {
 			paymentStatus = sqlc.PaymentStatusFAILED
 		}
 
-		updatePaymentStatus := sqlc.UpdatePaymentStatusParams{
+		updatePaymentStatus := sqlc.UpdatePendingPaymentStatusParams{
 			ID:                    converter.StringToPgUUID(payment.ID),
 			PaymentStatus:         paymentStatus,
 			ProviderTransactionID: converter.StringToPgText(&transId),
 		}
 		// Update the payment status in the database
-		_, err = uc.paymentRepo.UpdatePaymentStatus(ctx, updatePaymentStatus)
+		_, err = uc.paymentRepo.UpdatePendingPaymentStatus(ctx, updatePaymentStatus)
+		if errors.Is(err, pgx.ErrNoRows) && paymentStatus == sqlc.PaymentStatusSUCCESS {
+			// Payment vừa bị huỷ do đơn hết hạn thanh toán
+			if err := uc.refundLateCapture(ctx, &payment, &transId); err != nil {
+				log.Printf("Error refunding late payment ID %s: %v", payment.ID, err)
+			}
+			continue
+		}
 		if err != nil {
 			log.Printf("Error updating payment status for payment ID %s: %v", payment.ID, err)
 			continue
@@ -0,0 +1,771 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/converter"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/config"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/constant"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/db/sqlc"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/domain"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/dto"
+	grpc_adapter "github.com/toji-dev/go-shop/internal/services/payment-service/internal/grpc/adapter"
+	paymentprovider "github.com/toji-dev/go-shop/internal/services/payment-service/internal/payment_provider"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/repository"
+	"github.com/toji-dev/go-shop/internal/services/payment-service/internal/usecase"
+	order_v1 "github.com/toji-dev/go-shop/proto/gen/go/order/v1"
+)
+
+const (
+	testOrderID      = "7b0c3f4e-2b53-4a52-9d8e-0f2d4b1c9a11"
+	testPaymentID    = "c1f0e1d2-3b4a-4c5d-8e9f-0a1b2c3d4e5f"
+	testCollectionID = "0d9c8b7a-6f5e-4d3c-2b1a-0f9e8d7c6b5a"
+	testCustomerID   = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+)
+
+// fakePaymentRepository giữ một payment trong bộ nhớ, các method không dùng tới sẽ panic qua interface nhúng.
+type fakePaymentRepository struct {
+	repository.PaymentRepository
+
+	payment *domain.Payment
+	// collectedBy khác nil thì payment được một lần gọi khác xác nhận ngay trước MarkCODPaymentCollected
+	collectedBy *domain.Payment
+
+	// refunds là các yêu cầu hoàn chưa thất bại của payment
+	refunds []money.Money
+
+	// canceledBy khác nil thì payment bị huỷ ngay trước UpdatePendingPaymentStatus
+	canceledBy *domain.Payment
+
+	markCollectedCalls int
+	failCalls          int
+}
+
+func (r *fakePaymentRepository) UpdatePendingPaymentStatus(ctx context.Context, params sqlc.UpdatePendingPaymentStatusParams) (*domain.Payment, error) {
+	if r.canceledBy != nil {
+		r.payment, r.canceledBy = r.canceledBy, nil
+	}
+	if r.payment.Status != constant.PaymentStatusPending {
+		return nil, pgx.ErrNoRows
+	}
+	return r.UpdatePaymentStatus(ctx, sqlc.UpdatePaymentStatusParams(params))
+}
+
+func (r *fakePaymentRepository) UpdatePaymentStatus(ctx context.Context, params sqlc.UpdatePaymentStatusParams) (*domain.Payment, error) {
+	r.payment.Status = constant.PaymentStatus(params.PaymentStatus)
+	r.payment.ProviderTransactionID = converter.PgTextToStringPtr(params.ProviderTransactionID)
+	payment := *r.payment
+	return &payment, nil
+}
+
+type fakePaymentEventRepository struct {
+	repository.PaymentEventRepository
+
+	events []string
+}
+
+func (r *fakePaymentEventRepository) CreatePaymentEvent(ctx context.Context, paymentEvent *domain.PaymentEvent) (*domain.PaymentEvent, error) {
+	r.events = append(r.events, paymentEvent.EventType)
+	return paymentEvent, nil
+}
+
+// fakeProvider trả về kết quả thanh toán cố định cho mọi IPN.
+type fakeProvider struct {
+	paymentprovider.PaymentProvider
+
+	result *domain.Payment
+}
+
+func (p *fakeProvider) GetName() constant.PaymentProviderMethod {
+	return constant.MomoProviderMethod
+}
+
+func (p *fakeProvider) HandleIPN(r *http.Request) (*domain.Payment, error) {
+	result := *p.result
+	return &result, nil
+}
+
+func (r *fakePaymentRepository) GetRefundByPaymentID(ctx context.Context, paymentID string) (*domain.PaymentRefund, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (r *fakePaymentRepository) GetRefundByReference(ctx context.Context, paymentID string, referenceID string) (*domain.PaymentRefund, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (r *fakePaymentRepository) CreateRefundWithinRefundable(ctx context.Context, paymentID string, build func(payment *domain.Payment, refundable money.Money) (sqlc.CreateRefundPaymentParams, error)) (*domain.PaymentRefund, error) {
+	refundable := r.payment.Amount
+	for _, refunded := range r.refunds {
+		var err error
+		if refundable, err = refundable.Sub(refunded); err != nil {
+			return nil, err
+		}
+	}
+
+	payment := *r.payment
+	params, err := build(&payment, refundable)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := converter.PgNumericToMoney(params.Amount, params.Currency)
+	r.refunds = append(r.refunds, amount)
+	return &domain.PaymentRefund{
+		ID:        fmt.Sprintf("refund-%d", len(r.refunds)),
+		PaymentID: paymentID,
+		OrderID:   r.payment.OrderID,
+		Amount:    amount,
+	}, nil
+}
+
+func (r *fakePaymentRepository) GetPaymentByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	if r.payment == nil || r.payment.OrderID != orderID {
+		return nil, pgx.ErrNoRows
+	}
+	payment := *r.payment
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) MarkCODPaymentCollected(ctx context.Context, paymentID string, collectionID string, amount money.Money) (*domain.Payment, error) {
+	r.markCollectedCalls++
+	if r.collectedBy != nil {
+		r.payment, r.collectedBy = r.collectedBy, nil
+		return nil, pgx.ErrNoRows
+	}
+	if r.payment.Method != constant.PaymentMethodCOD || r.payment.Status != constant.PaymentStatusPending {
+		return nil, pgx.ErrNoRows
+	}
+	r.payment.Status = constant.PaymentStatusSuccess
+	r.payment.Amount = amount
+	r.payment.ProviderTransactionID = &collectionID
+	payment := *r.payment
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) FailPendingPayment(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	r.failCalls++
+	if r.payment.Status != constant.PaymentStatusPending {
+		return nil, pgx.ErrNoRows
+	}
+	r.payment.Status = constant.PaymentStatusFailed
+	payment := *r.payment
+	return &payment, nil
+}
+
+func newCODPayment(status constant.PaymentStatus, amount money.Money) *domain.Payment {
+	return &domain.Payment{
+		ID:       testPaymentID,
+		OrderID:  testOrderID,
+		Amount:   amount,
+		Method:   constant.PaymentMethodCOD,
+		Provider: string(constant.CODProviderMethod),
+		Status:   status,
+	}
+}
+
+func TestPaymentUseCase_ConfirmCashCollected(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		payment                  *domain.Payment
+		collectedBy              *domain.Payment
+		amount                   money.Money
+		expectedError            error
+		expectedAlreadyConfirmed bool
+		expectedAmount           money.Money
+		expectedMarkCalls        int
+	}{
+		{
+			name:              "Success - full amount collected",
+			payment:           newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			amount:            money.New(230000, "VND"),
+			expectedAmount:    money.New(230000, "VND"),
+			expectedMarkCalls: 1,
+		},
+		{
+			name:              "Success - reduced amount after item cancellations",
+			payment:           newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			amount:            money.New(180000, "VND"),
+			expectedAmount:    money.New(180000, "VND"),
+			expectedMarkCalls: 1,
+		},
+		{
+			name:                     "Success - already confirmed",
+			payment:                  newCODPayment(constant.PaymentStatusSuccess, money.New(180000, "VND")),
+			amount:                   money.New(180000, "VND"),
+			expectedAlreadyConfirmed: true,
+			expectedAmount:           money.New(180000, "VND"),
+		},
+		{
+			name:                     "Success - already refunded",
+			payment:                  newCODPayment(constant.PaymentStatusRefunded, money.New(180000, "VND")),
+			amount:                   money.New(180000, "VND"),
+			expectedAlreadyConfirmed: true,
+			expectedAmount:           money.New(180000, "VND"),
+		},
+		{
+			name:                     "Success - confirmed concurrently by another call",
+			payment:                  newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			collectedBy:              newCODPayment(constant.PaymentStatusSuccess, money.New(230000, "VND")),
+			amount:                   money.New(230000, "VND"),
+			expectedAlreadyConfirmed: true,
+			expectedAmount:           money.New(230000, "VND"),
+			expectedMarkCalls:        1,
+		},
+		{
+			name:          "Error - payment not found",
+			amount:        money.New(230000, "VND"),
+			expectedError: usecase.ErrPaymentNotFound,
+		},
+		{
+			name: "Error - not a COD payment",
+			payment: &domain.Payment{
+				ID:       testPaymentID,
+				OrderID:  testOrderID,
+				Amount:   money.New(230000, "VND"),
+				Method:   constant.PaymentMethodEWallet,
+				Provider: string(constant.MomoProviderMethod),
+				Status:   constant.PaymentStatusPending,
+			},
+			amount:        money.New(230000, "VND"),
+			expectedError: usecase.ErrNotCODPayment,
+		},
+		{
+			name:          "Error - more than payment amount",
+			payment:       newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			amount:        money.New(230001, "VND"),
+			expectedError: usecase.ErrAmountMismatch,
+		},
+		{
+			name:          "Error - different currency",
+			payment:       newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			amount:        money.New(230000, "USD"),
+			expectedError: usecase.ErrAmountMismatch,
+		},
+		{
+			name:          "Error - already confirmed with another amount",
+			payment:       newCODPayment(constant.PaymentStatusSuccess, money.New(180000, "VND")),
+			amount:        money.New(230000, "VND"),
+			expectedError: usecase.ErrAmountMismatch,
+		},
+		{
+			name:          "Error - payment canceled",
+			payment:       newCODPayment(constant.PaymentStatusFailed, money.New(230000, "VND")),
+			amount:        money.New(230000, "VND"),
+			expectedError: usecase.ErrPaymentNotCollectable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment, collectedBy: tc.collectedBy}
+			uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+			payment, alreadyConfirmed, err := uc.ConfirmCashCollected(context.Background(), testOrderID, testCollectionID, tc.amount)
+
+			if repo.markCollectedCalls != tc.expectedMarkCalls {
+				t.Errorf("MarkCODPaymentCollected calls = %d, want %d", repo.markCollectedCalls, tc.expectedMarkCalls)
+			}
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if alreadyConfirmed != tc.expectedAlreadyConfirmed {
+				t.Errorf("alreadyConfirmed = %t, want %t", alreadyConfirmed, tc.expectedAlreadyConfirmed)
+			}
+			if !payment.Amount.Equal(tc.expectedAmount) {
+				t.Errorf("payment amount = %s, want %s", payment.Amount, tc.expectedAmount)
+			}
+			if payment.Status != constant.PaymentStatusSuccess && payment.Status != constant.PaymentStatusRefunded {
+				t.Errorf("payment status = %s, want SUCCESS or REFUNDED", payment.Status)
+			}
+		})
+	}
+}
+
+func TestPaymentUseCase_CancelCODPayment(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		payment                 *domain.Payment
+		expectedError           error
+		expectedAlreadyCanceled bool
+		expectedFailCalls       int
+	}{
+		{
+			name:              "Success - pending COD payment",
+			payment:           newCODPayment(constant.PaymentStatusPending, money.New(230000, "VND")),
+			expectedFailCalls: 1,
+		},
+		{
+			name:                    "Success - already canceled",
+			payment:                 newCODPayment(constant.PaymentStatusFailed, money.New(230000, "VND")),
+			expectedAlreadyCanceled: true,
+		},
+		{
+			name:          "Error - cash already collected",
+			payment:       newCODPayment(constant.PaymentStatusSuccess, money.New(230000, "VND")),
+			expectedError: usecase.ErrPaymentNotCancelable,
+		},
+		{
+			name:          "Error - payment not found",
+			expectedError: usecase.ErrPaymentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment}
+			uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+			payment, alreadyCanceled, err := uc.CancelCODPayment(context.Background(), testOrderID, "customer canceled")
+
+			if repo.failCalls != tc.expectedFailCalls {
+				t.Errorf("FailPendingPayment calls = %d, want %d", repo.failCalls, tc.expectedFailCalls)
+			}
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if alreadyCanceled != tc.expectedAlreadyCanceled {
+				t.Errorf("alreadyCanceled = %t, want %t", alreadyCanceled, tc.expectedAlreadyCanceled)
+			}
+			if payment.Status != constant.PaymentStatusFailed {
+				t.Errorf("payment status = %s, want FAILED", payment.Status)
+			}
+		})
+	}
+}
+
+func newEWalletPayment(status constant.PaymentStatus, amount money.Money) *domain.Payment {
+	return &domain.Payment{
+		ID:       testPaymentID,
+		OrderID:  testOrderID,
+		Amount:   amount,
+		Method:   constant.PaymentMethodEWallet,
+		Provider: string(constant.MomoProviderMethod),
+		Status:   status,
+	}
+}
+
+func TestPaymentUseCase_RequestPartialRefund(t *testing.T) {
+	testCases := []struct {
+		name           string
+		payment        *domain.Payment
+		refunds        []money.Money
+		amount         money.Money
+		expectError    bool
+		expectedAmount money.Money
+	}{
+		{
+			name:           "Success - amount within refundable",
+			payment:        newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			refunds:        []money.Money{money.New(70000, "VND")},
+			amount:         money.New(30000, "VND"),
+			expectedAmount: money.New(30000, "VND"),
+		},
+		{
+			name:        "Error - amount exceeds refundable",
+			payment:     newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			refunds:     []money.Money{money.New(70000, "VND")},
+			amount:      money.New(30001, "VND"),
+			expectError: true,
+		},
+		{
+			name:        "Error - different currency",
+			payment:     newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			amount:      money.New(100, "USD"),
+			expectError: true,
+		},
+		{
+			name:        "Error - payment not successful",
+			payment:     newEWalletPayment(constant.PaymentStatusPending, money.New(100000, "VND")),
+			amount:      money.New(30000, "VND"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment, refunds: tc.refunds}
+			uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+			result, err := uc.RequestPartialRefund(context.Background(), testOrderID, "return:1", tc.amount, "returned items")
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error, got refund %+v", result)
+				}
+				if len(repo.refunds) != len(tc.refunds) {
+					t.Errorf("refunds = %d, want %d", len(repo.refunds), len(tc.refunds))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Amount.Equal(tc.expectedAmount) {
+				t.Errorf("refund amount = %s, want %s", result.Amount, tc.expectedAmount)
+			}
+		})
+	}
+}
+
+func TestPaymentUseCase_Refund(t *testing.T) {
+	t.Run("Success - refunds what partial refunds left", func(t *testing.T) {
+		repo := &fakePaymentRepository{
+			payment: newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			refunds: []money.Money{money.New(40000, "VND")},
+		}
+		uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+		result, err := uc.Refund(context.Background(), "", testOrderID, "order canceled")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := money.New(60000, "VND"); !result.Amount.Equal(want) {
+			t.Errorf("refund amount = %s, want %s", result.Amount, want)
+		}
+	})
+
+	t.Run("Error - already fully refunded", func(t *testing.T) {
+		repo := &fakePaymentRepository{
+			payment: newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			refunds: []money.Money{money.New(100000, "VND")},
+		}
+		uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+		if _, err := uc.Refund(context.Background(), "", testOrderID, "order canceled"); err == nil {
+			t.Fatal("expected error for a fully refunded payment")
+		}
+		if len(repo.refunds) != 1 {
+			t.Errorf("refunds = %d, want 1", len(repo.refunds))
+		}
+	})
+}
+
+func TestPaymentUseCase_CancelPendingPayment(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		payment                 *domain.Payment
+		expectedError           error
+		expectedAlreadyCanceled bool
+	}{
+		{
+			name:    "Success - pending e-wallet payment",
+			payment: newEWalletPayment(constant.PaymentStatusPending, money.New(100000, "VND")),
+		},
+		{
+			name:    "Success - pending COD payment",
+			payment: newCODPayment(constant.PaymentStatusPending, money.New(100000, "VND")),
+		},
+		{
+			name:                    "Success - already canceled",
+			payment:                 newEWalletPayment(constant.PaymentStatusFailed, money.New(100000, "VND")),
+			expectedAlreadyCanceled: true,
+		},
+		{
+			name:          "Error - customer already paid",
+			payment:       newEWalletPayment(constant.PaymentStatusSuccess, money.New(100000, "VND")),
+			expectedError: usecase.ErrPaymentNotCancelable,
+		},
+		{
+			name:          "Error - payment not found",
+			expectedError: usecase.ErrPaymentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment}
+			uc := usecase.NewPaymentUsecase(nil, repo, nil, nil, nil, nil)
+
+			payment, alreadyCanceled, err := uc.CancelPendingPayment(context.Background(), testOrderID, "payment window expired")
+
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if alreadyCanceled != tc.expectedAlreadyCanceled {
+				t.Errorf("alreadyCanceled = %t, want %t", alreadyCanceled, tc.expectedAlreadyCanceled)
+			}
+			if payment.Status != constant.PaymentStatusFailed {
+				t.Errorf("payment status = %s, want FAILED", payment.Status)
+			}
+		})
+	}
+}
+
+func TestPaymentUseCase_HandleIPN(t *testing.T) {
+	amount := money.New(100000, "VND")
+	transactionID := "momo-trans-1"
+	newMomoPayment := func(status constant.PaymentStatus) *domain.Payment {
+		payment := newEWalletPayment(status, amount)
+		payment.RequestID = "request-1"
+		return payment
+	}
+
+	testCases := []struct {
+		name           string
+		payment        *domain.Payment
+		canceledBy     *domain.Payment
+		resultStatus   constant.PaymentStatus
+		expectedStatus constant.PaymentStatus
+		expectedEvents []string
+		expectedRefund bool
+	}{
+		{
+			name:           "Success - pending payment is paid",
+			payment:        newMomoPayment(constant.PaymentStatusPending),
+			resultStatus:   constant.PaymentStatusSuccess,
+			expectedStatus: constant.PaymentStatusSuccess,
+			expectedEvents: []string{string(domain.PaymentEventTypePaymentSuccess)},
+		},
+		{
+			name:           "Success - pending payment fails",
+			payment:        newMomoPayment(constant.PaymentStatusPending),
+			resultStatus:   constant.PaymentStatusFailed,
+			expectedStatus: constant.PaymentStatusFailed,
+			expectedEvents: []string{string(domain.PaymentEventTypePaymentFailed)},
+		},
+		{
+			name:           "Paid after cancellation - refunded without success event",
+			payment:        newMomoPayment(constant.PaymentStatusFailed),
+			resultStatus:   constant.PaymentStatusSuccess,
+			expectedStatus: constant.PaymentStatusSuccess,
+			expectedRefund: true,
+		},
+		{
+			name:           "Canceled while handling IPN - refunded without success event",
+			payment:        newMomoPayment(constant.PaymentStatusPending),
+			canceledBy:     newMomoPayment(constant.PaymentStatusFailed),
+			resultStatus:   constant.PaymentStatusSuccess,
+			expectedStatus: constant.PaymentStatusSuccess,
+			expectedRefund: true,
+		},
+		{
+			name:           "Failure for canceled payment is ignored",
+			payment:        newMomoPayment(constant.PaymentStatusFailed),
+			resultStatus:   constant.PaymentStatusFailed,
+			expectedStatus: constant.PaymentStatusFailed,
+		},
+		{
+			name:           "Duplicate success is ignored",
+			payment:        newMomoPayment(constant.PaymentStatusSuccess),
+			resultStatus:   constant.PaymentStatusSuccess,
+			expectedStatus: constant.PaymentStatusSuccess,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment, canceledBy: tc.canceledBy}
+			eventRepo := &fakePaymentEventRepository{}
+			factory := paymentprovider.NewPaymentProviderFactory()
+			factory.RegisterProvider(&fakeProvider{result: &domain.Payment{
+				OrderID:               testOrderID,
+				RequestID:             "request-1",
+				Amount:                amount,
+				Status:                tc.resultStatus,
+				ProviderTransactionID: &transactionID,
+			}})
+			uc := usecase.NewPaymentUsecase(nil, repo, eventRepo, factory, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/ipn", nil)
+			if err := uc.HandleIPN(context.Background(), constant.MomoProviderMethod, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if repo.payment.Status != tc.expectedStatus {
+				t.Errorf("payment status = %s, want %s", repo.payment.Status, tc.expectedStatus)
+			}
+			if fmt.Sprint(eventRepo.events) != fmt.Sprint(tc.expectedEvents) {
+				t.Errorf("events = %v, want %v", eventRepo.events, tc.expectedEvents)
+			}
+			if refunded := len(repo.refunds) == 1 && repo.refunds[0].Equal(amount); refunded != tc.expectedRefund {
+				t.Errorf("refunds = %v, want refund of whole payment = %t", repo.refunds, tc.expectedRefund)
+			}
+		})
+	}
+}
+
+func (r *fakePaymentRepository) CreatePayment(ctx context.Context, params sqlc.CreatePaymentParams) (*domain.Payment, error) {
+	// Giống ON CONFLICT của query: chỉ payment FAILED được khởi tạo lại
+	if r.payment != nil && r.payment.Status != constant.PaymentStatusFailed {
+		return nil, pgx.ErrNoRows
+	}
+	id := testPaymentID
+	if r.payment != nil {
+		id = r.payment.ID
+	}
+	r.payment = &domain.Payment{
+		ID:        id,
+		OrderID:   testOrderID,
+		Amount:    converter.PgNumericToMoney(params.Amount, params.Currency),
+		Method:    constant.PaymentMethod(params.PaymentMethod),
+		Provider:  *converter.PgTextToStringPtr(params.PaymentProvider),
+		Status:    constant.PaymentStatusPending,
+		RequestID: *converter.PgTextToStringPtr(params.RequestID),
+	}
+	payment := *r.payment
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) CreateCODPayment(ctx context.Context, params sqlc.CreatePaymentParams, payload string) (*domain.Payment, error) {
+	return r.CreatePayment(ctx, params)
+}
+
+func (p *fakeProvider) CreatePayment(ctx context.Context, data paymentprovider.PaymentData) (*paymentprovider.CreatePaymentResult, error) {
+	return &paymentprovider.CreatePaymentResult{PayURL: "https://pay.example/" + data.RequestID}, nil
+}
+
+// fakeOrderAdapter trả về đơn của testCustomerID với trạng thái cho trước.
+type fakeOrderAdapter struct {
+	grpc_adapter.OrderServiceAdapter
+
+	status order_v1.OrderStatus
+	total  money.Money
+}
+
+func (a *fakeOrderAdapter) GetOrderInfo(ctx context.Context, req *order_v1.GetOrderRequest) (*order_v1.GetOrderResponse, error) {
+	return &order_v1.GetOrderResponse{
+		Exists: true,
+		Order: &order_v1.Order{
+			Id:          req.GetOrderId(),
+			CustomerId:  testCustomerID,
+			OrderStatus: a.status,
+			Total:       a.total.ToProto(),
+		},
+	}, nil
+}
+
+func TestPaymentUseCase_InitiatePayment(t *testing.T) {
+	amount := money.New(100000, "VND")
+	failedPayment := func() *domain.Payment {
+		payment := newEWalletPayment(constant.PaymentStatusFailed, amount)
+		payment.RequestID = "request-1"
+		return payment
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		payment        *domain.Payment
+		orderStatus    order_v1.OrderStatus
+		expectedError  error
+		expectError    bool
+		expectedMethod constant.PaymentMethod
+	}{
+		{
+			name:           "Success - first e-wallet payment",
+			method:         string(constant.MomoProviderMethod),
+			orderStatus:    order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+			expectedMethod: constant.PaymentMethodEWallet,
+		},
+		{
+			name:           "Success - failed payment is retried via e-wallet",
+			method:         string(constant.MomoProviderMethod),
+			payment:        failedPayment(),
+			orderStatus:    order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+			expectedMethod: constant.PaymentMethodEWallet,
+		},
+		{
+			name:           "Success - failed payment is retried via COD",
+			method:         string(constant.CODProviderMethod),
+			payment:        failedPayment(),
+			orderStatus:    order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+			expectedMethod: constant.PaymentMethodCOD,
+		},
+		{
+			name:          "Pending payment is not replaced",
+			method:        string(constant.MomoProviderMethod),
+			payment:       newEWalletPayment(constant.PaymentStatusPending, amount),
+			orderStatus:   order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+			expectedError: usecase.ErrPaymentAlreadyExists,
+		},
+		{
+			name:          "Successful payment is not replaced",
+			method:        string(constant.CODProviderMethod),
+			payment:       newEWalletPayment(constant.PaymentStatusSuccess, amount),
+			orderStatus:   order_v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+			expectedError: usecase.ErrPaymentAlreadyExists,
+		},
+		{
+			name:        "E-wallet payment of order not awaiting payment",
+			method:      string(constant.MomoProviderMethod),
+			orderStatus: order_v1.OrderStatus_ORDER_STATUS_CANCELED,
+			expectError: true,
+		},
+		{
+			name:        "E-wallet retry of failed payment after the order was canceled",
+			method:      string(constant.MomoProviderMethod),
+			payment:     failedPayment(),
+			orderStatus: order_v1.OrderStatus_ORDER_STATUS_CANCELED,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakePaymentRepository{payment: tc.payment}
+			factory := paymentprovider.NewPaymentProviderFactory()
+			factory.RegisterProvider(&fakeProvider{})
+			orderAdapter := &fakeOrderAdapter{status: tc.orderStatus, total: amount}
+			uc := usecase.NewPaymentUsecase(&config.AppConfig{}, repo, &fakePaymentEventRepository{}, factory, orderAdapter, nil)
+
+			res, err := uc.InitiatePayment(context.Background(), testCustomerID, dto.InitiatePaymentRequest{
+				OrderID:       testOrderID,
+				PaymentMethod: tc.method,
+			})
+
+			if tc.expectedError != nil || tc.expectError {
+				if err == nil || tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+					t.Fatalf("error = %v, want %v", err, tc.expectedError)
+				}
+				if tc.payment == nil && repo.payment != nil {
+					t.Errorf("payment %+v was created", repo.payment)
+				}
+				if tc.payment != nil && repo.payment.Status != tc.payment.Status {
+					t.Errorf("payment status = %s, want %s", repo.payment.Status, tc.payment.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.PaymentID != testPaymentID {
+				t.Errorf("payment ID = %s, want %s", res.PaymentID, testPaymentID)
+			}
+			if repo.payment.Status != constant.PaymentStatusPending || repo.payment.Method != tc.expectedMethod {
+				t.Errorf("payment = %s %s, want PENDING %s", repo.payment.Method, repo.payment.Status, tc.expectedMethod)
+			}
+			if !repo.payment.Amount.Equal(amount) {
+				t.Errorf("payment amount = %s, want %s", repo.payment.Amount, amount)
+			}
+			// Lần thanh toán mới có request ID riêng, IPN của lần thất bại trước không cập nhật được payment
+			if tc.payment != nil && repo.payment.RequestID == tc.payment.RequestID {
+				t.Errorf("request ID %s of the failed payment was reused", repo.payment.RequestID)
+			}
+		})
+	}
+}
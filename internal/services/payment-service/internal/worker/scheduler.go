@@ -65,6 +65,13 @@ func (s *Scheduler) RegisterJobs() {
 		log.Fatalf("[Scheduler] FATAL: Could not register 'HandlePendingPaymentTooLong' job: %v", err)
 	}
 	log.Println("[Scheduler] 'HandlePendingPaymentTooLong' job registered to run every 1 minute.")
+
+	// Job 6: Chuyển các đơn vừa chọn COD sang PROCESSING
+	_, err = s.cron.AddFunc("@every 1m", paymentEventUseCase.HandleCODPlacedEventPending)
+	if err != nil {
+		log.Fatalf("[Scheduler] FATAL: Could not register 'HandleCODPlacedEventPending' job: %v", err)
+	}
+	log.Println("[Scheduler] 'HandleCODPlacedEventPending' job registered to run every 1 minute.")
 }
 
 // Start khởi động scheduler để bắt đầu chạy các công việc.
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Khối lượng sản phẩm (gram), order-service dùng để tính phí vận chuyển theo bậc khối lượng.
+-- 0 nghĩa là seller chưa khai báo, order-service sẽ dùng khối lượng mặc định.
+ALTER TABLE products ADD COLUMN weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS weight_grams;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mỗi lần nhập lại hàng (ví dụ hàng khách trả về) được ghi lại theo reference_id
+-- để order-service có thể gọi lại RestockProducts mà không cộng tồn kho hai lần.
+CREATE TABLE stock_restocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference_id VARCHAR(255) NOT NULL UNIQUE,
+    order_id UUID NOT NULL,
+    shop_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_restocks;
+-- +goose StatementEnd
@@ -9,10 +9,11 @@ INSERT INTO products (
     currency,
     quantity,
     reserve_quantity,
-    product_status
+    product_status,
+    weight_grams
 )
 VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -41,6 +42,7 @@ SET
   quantity = $7,
   thumbnail_url = $8,
   product_status = $9,
+  weight_grams = $10,
   updated_at = NOW()
 WHERE id = $1 AND delete_at IS NULL
 RETURNING *;
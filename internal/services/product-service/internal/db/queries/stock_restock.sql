@@ -0,0 +1,22 @@
+-- name: CreateStockRestock :execrows
+-- Trả về 0 dòng nếu reference_id đã được nhập kho trước đó.
+INSERT INTO stock_restocks (
+    reference_id,
+    order_id,
+    shop_id
+)
+VALUES ($1, $2, $3)
+ON CONFLICT (reference_id) DO NOTHING;
+
+-- name: RestockReservedProducts :exec
+-- Hàng đã bán vẫn nằm trong reserve_quantity, nhập lại kho là giải phóng phần giữ chỗ tương ứng.
+UPDATE products
+SET
+    reserve_quantity = GREATEST(products.reserve_quantity - p.quantity, 0),
+    updated_at = NOW()
+FROM (
+    SELECT
+        CAST(unnest(@product_ids::uuid[]) AS uuid) AS id,
+        CAST(unnest(@quantities::int[]) AS integer) AS quantity
+) AS p
+WHERE products.id = p.id AND products.shop_id = @shop_id;
@@ -134,4 +134,13 @@ type Product struct {
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 	DeleteAt           pgtype.Timestamptz `json:"delete_at"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	WeightGrams        int32              `json:"weight_grams"`
+}
+
+type StockRestock struct {
+	ID          pgtype.UUID        `json:"id"`
+	ReferenceID string             `json:"reference_id"`
+	OrderID     pgtype.UUID        `json:"order_id"`
+	ShopID      pgtype.UUID        `json:"shop_id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
@@ -57,12 +57,13 @@ INSERT INTO products (
     currency,
     quantity,
     reserve_quantity,
-    product_status
+    product_status,
+    weight_grams
 )
 VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at
+RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams
 `
 
 type CreateProductParams struct {
@@ -76,6 +77,7 @@ type CreateProductParams struct {
 	Quantity           int32          `json:"quantity"`
 	ReserveQuantity    int32          `json:"reserve_quantity"`
 	ProductStatus      ProductStatus  `json:"product_status"`
+	WeightGrams        int32          `json:"weight_grams"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
@@ -90,6 +92,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.Quantity,
 		arg.ReserveQuantity,
 		arg.ProductStatus,
+		arg.WeightGrams,
 	)
 	var i Product
 	err := row.Scan(
@@ -110,12 +113,13 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.CreatedAt,
 		&i.DeleteAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
 	)
 	return i, err
 }
 
 const getListProductsByShop = `-- name: GetListProductsByShop :many
-SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at FROM products
+SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams FROM products
 WHERE shop_id = $1 AND delete_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -154,6 +158,7 @@ func (q *Queries) GetListProductsByShop(ctx context.Context, arg GetListProducts
 			&i.CreatedAt,
 			&i.DeleteAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
 		); err != nil {
 			return nil, err
 		}
@@ -166,7 +171,7 @@ func (q *Queries) GetListProductsByShop(ctx context.Context, arg GetListProducts
 }
 
 const getProductByID = `-- name: GetProductByID :one
-SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at FROM products
+SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams FROM products
 WHERE id = $1 AND delete_at IS NULL
 `
 
@@ -191,12 +196,13 @@ func (q *Queries) GetProductByID(ctx context.Context, id pgtype.UUID) (Product,
 		&i.CreatedAt,
 		&i.DeleteAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
 	)
 	return i, err
 }
 
 const getProductsByIDs = `-- name: GetProductsByIDs :many
-SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at FROM products
+SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams FROM products
 WHERE id = ANY($1::uuid[]) AND delete_at IS NULL
 `
 
@@ -227,6 +233,7 @@ func (q *Queries) GetProductsByIDs(ctx context.Context, productIds []pgtype.UUID
 			&i.CreatedAt,
 			&i.DeleteAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
 		); err != nil {
 			return nil, err
 		}
@@ -239,7 +246,7 @@ func (q *Queries) GetProductsByIDs(ctx context.Context, productIds []pgtype.UUID
 }
 
 const getProductsByIDsForUpdate = `-- name: GetProductsByIDsForUpdate :many
-SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at FROM products
+SELECT id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams FROM products
 WHERE id = ANY($1::uuid[]) AND delete_at IS NULL
 FOR UPDATE
 `
@@ -271,6 +278,7 @@ func (q *Queries) GetProductsByIDsForUpdate(ctx context.Context, productIds []pg
 			&i.CreatedAt,
 			&i.DeleteAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
 		); err != nil {
 			return nil, err
 		}
@@ -332,9 +340,10 @@ SET
   quantity = $7,
   thumbnail_url = $8,
   product_status = $9,
+  weight_grams = $10,
   updated_at = NOW()
 WHERE id = $1 AND delete_at IS NULL
-RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at
+RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams
 `
 
 type UpdateProductParams struct {
@@ -347,6 +356,7 @@ type UpdateProductParams struct {
 	Quantity           int32          `json:"quantity"`
 	ThumbnailUrl       pgtype.Text    `json:"thumbnail_url"`
 	ProductStatus      ProductStatus  `json:"product_status"`
+	WeightGrams        int32          `json:"weight_grams"`
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
@@ -360,6 +370,7 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		arg.Quantity,
 		arg.ThumbnailUrl,
 		arg.ProductStatus,
+		arg.WeightGrams,
 	)
 	var i Product
 	err := row.Scan(
@@ -380,6 +391,7 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.CreatedAt,
 		&i.DeleteAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
 	)
 	return i, err
 }
@@ -391,7 +403,7 @@ SET
     reserve_quantity = $3,
     updated_at = NOW()
 WHERE id = $1 AND delete_at IS NULL
-RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at
+RETURNING id, shop_id, product_name, thumbnail_url, product_description, category_id, price, currency, quantity, reserve_quantity, product_status, sold_count, rating_avg, total_reviews, created_at, delete_at, updated_at, weight_grams
 `
 
 type UpdateProductStockParams struct {
@@ -421,6 +433,7 @@ func (q *Queries) UpdateProductStock(ctx context.Context, arg UpdateProductStock
 		&i.CreatedAt,
 		&i.DeleteAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
 	)
 	return i, err
 }
@@ -14,6 +14,8 @@ type Querier interface {
 	BulkUpdateProductReserveStock(ctx context.Context, arg BulkUpdateProductReserveStockParams) error
 	CountProductsByShop(ctx context.Context, shopID pgtype.UUID) (int64, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	// Trả về 0 dòng nếu reference_id đã được nhập kho trước đó.
+	CreateStockRestock(ctx context.Context, arg CreateStockRestockParams) (int64, error)
 	GetListProductsByShop(ctx context.Context, arg GetListProductsByShopParams) ([]Product, error)
 	GetProductByID(ctx context.Context, id pgtype.UUID) (Product, error)
 	GetProductsByIDs(ctx context.Context, productIds []pgtype.UUID) ([]Product, error)
@@ -22,6 +24,8 @@ type Querier interface {
 	GetReservationStatusOfOrders(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetReservationStatusOfOrdersRow, error)
 	IsOrderReserved(ctx context.Context, orderID pgtype.UUID) (bool, error)
 	ReserveOrder(ctx context.Context, arg ReserveOrderParams) error
+	// Hàng đã bán vẫn nằm trong reserve_quantity, nhập lại kho là giải phóng phần giữ chỗ tương ứng.
+	RestockReservedProducts(ctx context.Context, arg RestockReservedProductsParams) error
 	SoftDeleteProduct(ctx context.Context, id pgtype.UUID) error
 	UnreserveProducts(ctx context.Context, arg UnreserveProductsParams) error
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
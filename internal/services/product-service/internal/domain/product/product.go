@@ -33,15 +33,19 @@ type Product struct {
 	soldCount       int
 	ratingAvg       float64
 	totalReviews    int
+	weightGrams     int // Khối lượng (gram), 0 nghĩa là chưa khai báo
 	createdAt       time.Time
 	updatedAt       time.Time
 	deletedAt       *time.Time // Nullable field for soft deletion
 }
 
-func NewProduct(shopID, name, thumbnailURL, description string, categoryID uuid.UUID, price Price, quantity int) (*Product, error) {
+func NewProduct(shopID, name, thumbnailURL, description string, categoryID uuid.UUID, price Price, quantity int, weightGrams int) (*Product, error) {
 	if name == "" {
 		return nil, errors.New("product name cannot be empty")
 	}
+	if weightGrams < 0 {
+		return nil, errors.New("weight cannot be negative")
+	}
 	return &Product{
 		id:              uuid.New(),
 		shopID:          uuid.MustParse(shopID),
@@ -56,6 +60,7 @@ func NewProduct(shopID, name, thumbnailURL, description string, categoryID uuid.
 		soldCount:       0,
 		ratingAvg:       0.0,
 		totalReviews:    0,
+		weightGrams:     weightGrams,
 		createdAt:       time_utils.GetUtcTime(),
 		updatedAt:       time_utils.GetUtcTime(),
 		deletedAt:       nil,
@@ -67,6 +72,7 @@ func Reconstitute(
 	name, description, thumbnailURL string,
 	price Price,
 	quantity int,
+	weightGrams int,
 	status ProductStatus,
 	createdAt, updatedAt time.Time,
 ) (*Product, error) {
@@ -79,6 +85,7 @@ func Reconstitute(
 		price:        price,
 		quantity:     quantity,
 		thumbnailURL: thumbnailURL,
+		weightGrams:  weightGrams,
 		status:       status,
 		createdAt:    createdAt,
 		updatedAt:    updatedAt,
@@ -135,6 +142,15 @@ func (p *Product) UpdateQuantity(newQuantity int) error {
 	return nil
 }
 
+func (p *Product) ChangeWeight(weightGrams int) error {
+	if weightGrams < 0 {
+		return errors.New("weight cannot be negative")
+	}
+	p.weightGrams = weightGrams
+	p.setUpdatedAt()
+	return nil
+}
+
 func (p *Product) Deactivate() {
 	p.status = ProductStatusInactive
 	p.updatedAt = time_utils.GetUtcTime()
@@ -169,6 +185,7 @@ func (p *Product) ThumbnailURL() *string { return &p.thumbnailURL }
 func (p *Product) CategoryID() uuid.UUID { return p.categoryID }
 func (p *Product) Price() Price          { return p.price }
 func (p *Product) Quantity() int         { return p.quantity }
+func (p *Product) WeightGrams() int      { return p.weightGrams }
 func (p *Product) Status() ProductStatus { return p.status }
 func (p *Product) CreatedAt() time.Time  { return p.createdAt }
 func (p *Product) DeletedAt() *time.Time { return p.deletedAt }
@@ -2,6 +2,8 @@ package product
 
 import (
 	"errors"
+
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 )
 
 type Price struct {
@@ -26,3 +28,8 @@ func (p Price) GetAmount() float64 {
 func (p Price) GetCurrency() string {
 	return p.currency
 }
+
+// Money trả về giá theo đơn vị tiền nhỏ nhất. Giá lưu NUMERIC(12,2) nên làm tròn tới đơn vị nhỏ nhất không mất dữ liệu.
+func (p Price) Money() money.Money {
+	return money.FromMajor(p.amount, p.currency)
+}
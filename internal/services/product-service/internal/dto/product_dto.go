@@ -13,6 +13,7 @@ type CreateProductRequest struct {
 	Currency     string  `json:"currency" binding:"required"`
 	ThumbnailURL string  `json:"thumbnail_url" binding:"required,url"`
 	Quantity     int     `json:"quantity" binding:"required,gte=0"`
+	WeightGrams  int     `json:"weight_grams" binding:"omitempty,gte=0"`
 }
 
 type GetProductsByShopQuery struct {
@@ -30,6 +31,7 @@ type UpdateProductRequest struct {
 	Currency     string  `json:"currency" binding:"required"`
 	ThumbnailURL string  `json:"thumbnail_url" binding:"required,url"`
 	Quantity     int     `json:"quantity" binding:"required,gte=0"`
+	WeightGrams  *int    `json:"weight_grams" binding:"omitempty,gte=0"` // Không gửi thì giữ nguyên khối lượng hiện tại
 }
 
 type DeleteProductRequest struct {
@@ -45,6 +47,7 @@ type ProductResponse struct {
 	Price        float64   `json:"price"`
 	Currency     string    `json:"currency"`
 	Quantity     int       `json:"quantity"`
+	WeightGrams  int       `json:"weight_grams"`
 	ThumbnailURL string    `json:"thumbnail_url"`
 	Status       string    `json:"status"`
 	CreatedAt    time.Time `json:"created_at"`
@@ -38,11 +38,15 @@ func (s *Server) GetProductInfo(ctx context.Context, req *product_v1.GetProductI
 	}
 
 	productInfo := &product_v1.ProductInfo{
-		Id:       product.ID().String(),
-		ShopId:   product.ShopID().String(),
-		Price:    int32(product.Price().GetAmount()),
-		Currency: product.Price().GetCurrency(),
-		Quantity: int32(product.Quantity()),
+		Id:           product.ID().String(),
+		ShopId:       product.ShopID().String(),
+		Price:        int32(product.Price().GetAmount()),
+		Currency:     product.Price().GetCurrency(),
+		UnitPrice:    product.Price().Money().ToProto(),
+		Quantity:     int32(product.Quantity()),
+		Name:         product.Name(),
+		ThumbnailUrl: *product.ThumbnailURL(),
+		WeightGrams:  int32(product.WeightGrams()),
 	}
 
 	return &product_v1.GetProductInfoResponse{
@@ -58,23 +62,28 @@ func (s *Server) GetProductsInfo(ctx context.Context, req *product_v1.GetProduct
 		return &product_v1.GetProductsInfoResponse{Valid: false}, err
 	}
 
-	if len(products) != len(req.ProductIds) {
+	// Vẫn trả về các sản phẩm tìm thấy khi thiếu sản phẩm để client biết chính xác sản phẩm nào không còn
+	valid := len(products) == len(req.ProductIds)
+	if !valid {
 		log.Printf("Mismatch count: requested %d, found %d", len(req.ProductIds), len(products))
-		return &product_v1.GetProductsInfoResponse{Valid: false}, nil // Some products not found
 	}
 
 	var productInfos []*product_v1.ProductInfo
 	for _, p := range products {
 		productInfos = append(productInfos, &product_v1.ProductInfo{
-			Id:       p.ID().String(),
-			ShopId:   p.ShopID().String(),
-			Price:    int32(p.Price().GetAmount()), // Consider using float or a proper money type
-			Currency: p.Price().GetCurrency(),
-			Quantity: int32(p.Quantity()),
+			Id:           p.ID().String(),
+			ShopId:       p.ShopID().String(),
+			Price:        int32(p.Price().GetAmount()), // Deprecated: client mới đọc UnitPrice
+			Currency:     p.Price().GetCurrency(),
+			UnitPrice:    p.Price().Money().ToProto(),
+			Quantity:     int32(p.Quantity()),
+			Name:         p.Name(),
+			ThumbnailUrl: *p.ThumbnailURL(),
+			WeightGrams:  int32(p.WeightGrams()),
 		})
 	}
 
-	return &product_v1.GetProductsInfoResponse{Valid: true, Products: productInfos}, nil
+	return &product_v1.GetProductsInfoResponse{Valid: valid, Products: productInfos}, nil
 }
 
 func (s *Server) ReserveProducts(ctx context.Context, req *product_v1.ReserveProductsRequest) (*product_v1.ReserveProductsResponse, error) {
@@ -139,7 +148,7 @@ func (s *Server) GetOrdersReservationStatus(ctx context.Context, req *product_v1
 	}, nil
 }
 
-func (s *Server) UnReserveOrders(ctx context.Context, req *product_v1.UnreserveOrdersRequest) (*product_v1.UnreserveOrdersResponse, error) {
+func (s *Server) UnreserveOrders(ctx context.Context, req *product_v1.UnreserveOrdersRequest) (*product_v1.UnreserveOrdersResponse, error) {
 	log.Printf("[ProductService] Unreserving orders: %+v", req)
 
 	var results []*product_v1.UnreserveOrderResult
@@ -164,3 +173,25 @@ func (s *Server) UnReserveOrders(ctx context.Context, req *product_v1.UnreserveO
 		Results: results,
 	}, nil
 }
+
+func (s *Server) RestockProducts(ctx context.Context, req *product_v1.RestockProductsRequest) (*product_v1.RestockProductsResponse, error) {
+	log.Printf("[ProductService] RestockProducts called for order ID: %s, reference: %s", req.OrderId, req.ReferenceId)
+
+	if req.ReferenceId == "" || len(req.Products) == 0 {
+		return &product_v1.RestockProductsResponse{
+			Success: false,
+			Message: "reference_id and products are required",
+		}, nil
+	}
+
+	alreadyProcessed, err := s.productRepo.RestockProducts(ctx, req.ReferenceId, req.OrderId, req.ShopId, req.Products)
+	if err != nil {
+		log.Printf("[ProductService] Error restocking products for order %s: %v", req.OrderId, err)
+		return &product_v1.RestockProductsResponse{Success: false, Message: err.Error()}, err
+	}
+
+	return &product_v1.RestockProductsResponse{
+		Success:          true,
+		AlreadyProcessed: alreadyProcessed,
+	}, nil
+}
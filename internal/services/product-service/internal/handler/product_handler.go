@@ -202,6 +202,7 @@ func toProductResponse(p *product.Product) dto.ProductResponse {
 		Price:        p.Price().GetAmount(),
 		Currency:     p.Price().GetCurrency(),
 		Quantity:     p.Quantity(),
+		WeightGrams:  p.WeightGrams(),
 		ThumbnailURL: *p.ThumbnailURL(),
 		Status:       string(p.Status()),
 		CreatedAt:    p.CreatedAt(),
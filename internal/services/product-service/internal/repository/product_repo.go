@@ -28,6 +28,8 @@ type ProductRepository interface {
 	GetReservationStatusOfOrder(ctx context.Context, orderID string) (*product_v1.GetOrderReservationStatusResponse, error)
 	GetReservationStatusOfOrders(ctx context.Context, orderIDs []string) ([]*product_v1.GetOrderReservationStatusResponse, error)
 	UnreserveStock(ctx context.Context, orderId string, items []*product_v1.UnreserveProduct) error
+	// RestockProducts trả về alreadyProcessed = true nếu referenceID đã được nhập kho trước đó.
+	RestockProducts(ctx context.Context, referenceID, orderID, shopID string, items []*product_v1.RestockProduct) (bool, error)
 }
 
 type pgProductRepository struct {
@@ -59,6 +61,7 @@ func (r *pgProductRepository) Save(ctx context.Context, p *product.Product) (*pr
 		Quantity:           int32(p.Quantity()),
 		ReserveQuantity:    0,
 		ProductStatus:      sqlc.ProductStatus(p.Status()),
+		WeightGrams:        int32(p.WeightGrams()),
 	}
 
 	product, err := r.queries.CreateProduct(ctx, params)
@@ -154,6 +157,7 @@ func (r *pgProductRepository) Update(ctx context.Context, p *product.Product) er
 		Quantity:           int32(p.Quantity()),
 		ThumbnailUrl:       converter.StringToPgText(p.ThumbnailURL()),
 		ProductStatus:      sqlc.ProductStatus(p.Status()),
+		WeightGrams:        int32(p.WeightGrams()),
 	}
 
 	_, err := r.queries.UpdateProduct(ctx, params)
@@ -198,6 +202,7 @@ func toDomain(p *sqlc.Product) (*product.Product, error) {
 		p.ThumbnailUrl.String,
 		price,
 		int(p.Quantity),
+		int(p.WeightGrams),
 		product.ProductStatus(p.ProductStatus),
 		p.CreatedAt.Time,
 		p.UpdatedAt.Time,
@@ -495,3 +500,56 @@ func (r *pgProductRepository) UnreserveStock(ctx context.Context, orderId string
 
 	return nil
 }
+
+func (r *pgProductRepository) RestockProducts(ctx context.Context, referenceID, orderID, shopID string, items []*product_v1.RestockProduct) (bool, error) {
+	tx, err := r.db.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction for restocking: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	// Bước 1: ghi nhận reference, nếu đã tồn tại thì lần nhập kho trước đã thành công
+	inserted, err := qtx.CreateStockRestock(ctx, sqlc.CreateStockRestockParams{
+		ReferenceID: referenceID,
+		OrderID:     converter.StringToPgUUID(orderID),
+		ShopID:      converter.StringToPgUUID(shopID),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record restock %s: %w", referenceID, err)
+	}
+	if inserted == 0 {
+		log.Printf("Restock %s of order %s has already been processed", referenceID, orderID)
+		return true, nil
+	}
+
+	// Bước 2: giải phóng phần giữ chỗ của số hàng được trả về
+	restockParams := sqlc.RestockReservedProductsParams{
+		ProductIds: make([]pgtype.UUID, len(items)),
+		Quantities: make([]int32, len(items)),
+		ShopID:     converter.StringToPgUUID(shopID),
+	}
+	for i, item := range items {
+		productUUID, err := uuid.Parse(item.ProductId)
+		if err != nil {
+			return false, fmt.Errorf("invalid product ID format: %w", err)
+		}
+		if item.Quantity <= 0 {
+			return false, fmt.Errorf("invalid restock quantity %d for product %s", item.Quantity, item.ProductId)
+		}
+		restockParams.ProductIds[i] = converter.UUIDToPgUUID(productUUID)
+		restockParams.Quantities[i] = item.Quantity
+	}
+
+	if err := qtx.RestockReservedProducts(ctx, restockParams); err != nil {
+		return false, fmt.Errorf("failed to restock products: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction after restocking order %s: %w", orderID, err)
+	}
+
+	log.Printf("Restocked %d products of order %s (reference %s)", len(items), orderID, referenceID)
+	return false, nil
+}
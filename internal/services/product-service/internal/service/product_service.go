@@ -82,6 +82,7 @@ func (s *ProductService) CreateProduct(ctx *gin.Context, req *dto.CreateProductR
 		categoryID,
 		price,
 		req.Quantity,
+		req.WeightGrams,
 	)
 
 	if err != nil {
@@ -204,6 +205,11 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id string, req dto.U
 	if err := existingProduct.UpdateQuantity(req.Quantity); err != nil {
 		return nil, err
 	}
+	if req.WeightGrams != nil {
+		if err := existingProduct.ChangeWeight(*req.WeightGrams); err != nil {
+			return nil, err
+		}
+	}
 
 	// 4. Lưu lại Aggregate đã thay đổi
 	if err := s.productRepo.Update(ctx, existingProduct); err != nil {
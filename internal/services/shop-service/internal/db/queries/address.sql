@@ -12,4 +12,14 @@ INSERT INTO addresses (
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id;
\ No newline at end of file
+RETURNING id;
+
+-- name: GetShopAddressByID :one
+SELECT * FROM addresses
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetShopAddressesByShopIDs :many
+-- Địa chỉ lấy hàng hiện tại của nhiều shop, shop chưa có địa chỉ thì không có trong kết quả.
+SELECT a.* FROM shops s
+JOIN addresses a ON a.id = s.address_id
+WHERE s.id = ANY(@shop_ids::uuid[]) AND a.deleted_at IS NULL;
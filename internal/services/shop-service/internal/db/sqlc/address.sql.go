@@ -56,3 +56,68 @@ func (q *Queries) CreateShopAddress(ctx context.Context, arg CreateShopAddressPa
 	err := row.Scan(&id)
 	return id, err
 }
+
+const getShopAddressByID = `-- name: GetShopAddressByID :one
+SELECT id, shop_id, street, ward, district, city, country, lat, long, deleted_at, created_at, updated_at FROM addresses
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetShopAddressByID(ctx context.Context, id pgtype.UUID) (Address, error) {
+	row := q.db.QueryRow(ctx, getShopAddressByID, id)
+	var i Address
+	err := row.Scan(
+		&i.ID,
+		&i.ShopID,
+		&i.Street,
+		&i.Ward,
+		&i.District,
+		&i.City,
+		&i.Country,
+		&i.Lat,
+		&i.Long,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getShopAddressesByShopIDs = `-- name: GetShopAddressesByShopIDs :many
+SELECT a.id, a.shop_id, a.street, a.ward, a.district, a.city, a.country, a.lat, a.long, a.deleted_at, a.created_at, a.updated_at FROM shops s
+JOIN addresses a ON a.id = s.address_id
+WHERE s.id = ANY($1::uuid[]) AND a.deleted_at IS NULL
+`
+
+// Địa chỉ lấy hàng hiện tại của nhiều shop, shop chưa có địa chỉ thì không có trong kết quả.
+func (q *Queries) GetShopAddressesByShopIDs(ctx context.Context, shopIds []pgtype.UUID) ([]Address, error) {
+	rows, err := q.db.Query(ctx, getShopAddressesByShopIDs, shopIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Address{}
+	for rows.Next() {
+		var i Address
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShopID,
+			&i.Street,
+			&i.Ward,
+			&i.District,
+			&i.City,
+			&i.Country,
+			&i.Lat,
+			&i.Long,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
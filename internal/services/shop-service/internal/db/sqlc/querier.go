@@ -19,6 +19,9 @@ type Querier interface {
 	GetAllPromotionsByStatus(ctx context.Context, promotionStatus NullPromotionStatus) ([]ShopPromotion, error)
 	GetPromotionByID(ctx context.Context, id pgtype.UUID) (ShopPromotion, error)
 	GetPromotionsByShopID(ctx context.Context, shopID pgtype.UUID) ([]ShopPromotion, error)
+	GetShopAddressByID(ctx context.Context, id pgtype.UUID) (Address, error)
+	// Địa chỉ lấy hàng hiện tại của nhiều shop, shop chưa có địa chỉ thì không có trong kết quả.
+	GetShopAddressesByShopIDs(ctx context.Context, shopIds []pgtype.UUID) ([]Address, error)
 	GetShopByID(ctx context.Context, id pgtype.UUID) (Shop, error)
 	GetShopsByOwnerID(ctx context.Context, ownerID pgtype.UUID) ([]Shop, error)
 	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (ShopPromotion, error)
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Address represents the pickup address of a shop
+type Address struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ShopID    uuid.UUID `json:"shop_id" db:"shop_id"`
+	Street    string    `json:"street" db:"street"`
+	Ward      string    `json:"ward" db:"ward"`
+	District  string    `json:"district" db:"district"`
+	City      string    `json:"city" db:"city"`
+	Country   string    `json:"country" db:"country"`
+	Lat       *float64  `json:"lat,omitempty" db:"lat"`
+	Long      *float64  `json:"long,omitempty" db:"long"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HasCoordinates checks if the address has been geocoded
+func (a *Address) HasCoordinates() bool {
+	return a.Lat != nil && a.Long != nil
+}
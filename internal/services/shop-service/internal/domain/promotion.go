@@ -2,9 +2,11 @@ package domain
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
 )
 
 type PromotionType string
@@ -39,28 +41,33 @@ type Promotion struct {
 	UpdatedAt         time.Time       `json:"updated_at"`
 }
 
-func (p *Promotion) CalculateDiscount(amount float64) (float64, error) {
+// CalculateDiscount tính số tiền giảm trên subtotal theo đơn vị tiền nhỏ nhất.
+// Giảm theo phần trăm được làm tròn tới đơn vị nhỏ nhất, và không bao giờ vượt quá MaxDiscountAmount hay subtotal.
+func (p *Promotion) CalculateDiscount(subtotal money.Money) (money.Money, error) {
+	zero := money.Zero(subtotal.Currency)
 	if p.PromotionStatus != PromotionStatusActive {
-		return 0, fmt.Errorf("promotion is not active")
+		return zero, fmt.Errorf("promotion is not active")
 	}
-	if amount < p.MinPurchaseAmount {
-		return 0, fmt.Errorf("amount is less than minimum purchase amount")
+	if subtotal.Amount < money.FromMajor(p.MinPurchaseAmount, subtotal.Currency).Amount {
+		return zero, fmt.Errorf("amount is less than minimum purchase amount")
 	}
 
 	if p.StartTime.After(time.Now()) || p.EndTime.Before(time.Now()) {
-		return 0, fmt.Errorf("promotion is not active")
+		return zero, fmt.Errorf("promotion is not active")
 	}
 
-	discount := 0.0
+	discount := zero
 	switch p.PromotionType {
 	case PromotionTypePercentage:
-		discount = amount * (p.DiscountValue / 100)
+		// discount_value là DECIMAL(10,2) nên quy về phần vạn để nhân chính xác.
+		basisPoints := int64(math.Round(p.DiscountValue * 100))
+		discount = subtotal.MulRatio(basisPoints, 10000)
 	case PromotionTypeValue:
-		discount = p.DiscountValue
+		discount = money.FromMajor(p.DiscountValue, subtotal.Currency)
 	}
 
-	if p.MaxDiscountAmount != nil && discount > *p.MaxDiscountAmount {
-		return *p.MaxDiscountAmount, nil
+	if p.MaxDiscountAmount != nil {
+		discount = discount.Min(money.FromMajor(*p.MaxDiscountAmount, subtotal.Currency))
 	}
-	return discount, nil
+	return discount.Min(subtotal), nil
 }
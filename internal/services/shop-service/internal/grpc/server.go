@@ -2,10 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/toji-dev/go-shop/internal/pkg/money"
+	"github.com/toji-dev/go-shop/internal/services/shop-service/internal/domain"
 	promotionRepo "github.com/toji-dev/go-shop/internal/services/shop-service/internal/repository/promotion"
 	shopRepo "github.com/toji-dev/go-shop/internal/services/shop-service/internal/repository/shop"
 	shop_v1 "github.com/toji-dev/go-shop/proto/gen/go/shop/v1"
@@ -81,8 +85,9 @@ func (s *Server) CheckShopExists(ctx context.Context, req *shop_v1.CheckShopExis
 }
 
 func (s *Server) CalculatePromotion(ctx context.Context, req *shop_v1.CalculatePromotionRequest) (*shop_v1.CalculatePromotionResponse, error) {
-	log.Printf("Received CalculatePromotion request for ShopID: %s, UserID: %s, PromotionCode: %s, TotalAmount: %d",
-		req.GetShopId(), req.GetUserId(), req.GetPromotionCode(), req.GetTotalAmount())
+	subtotal := requestSubtotal(req)
+	log.Printf("Received CalculatePromotion request for ShopID: %s, UserID: %s, PromotionCode: %s, Subtotal: %s",
+		req.GetShopId(), req.GetUserId(), req.GetPromotionCode(), subtotal)
 
 	promotion, err := s.promotionRepo.GetByID(ctx, req.GetPromotionCode())
 	if err != nil {
@@ -90,14 +95,158 @@ func (s *Server) CalculatePromotion(ctx context.Context, req *shop_v1.CalculateP
 		return nil, fmt.Errorf("error retrieving promotions: %s", err)
 	}
 
-	discount, err := promotion.CalculateDiscount(float64(req.GetTotalAmount()))
+	discount, err := promotion.CalculateDiscount(subtotal)
 	if err != nil {
 		log.Printf("Error calculating discount for promotion %s: %v", req.GetPromotionCode(), err)
 		return nil, fmt.Errorf("error calculating discount: %s", err)
 	}
 
 	return &shop_v1.CalculatePromotionResponse{
-		Eligible: true,
-		Discount: float32(discount),
+		Eligible:       true,
+		Discount:       float32(discount.Float64()),
+		DiscountAmount: discount.ToProto(),
 	}, nil
 }
+
+// requestSubtotal đọc subtotal dạng Money, client cũ chỉ gửi total_amount (số nguyên theo đơn vị tiền) thì quy đổi sang Money.
+func requestSubtotal(req *shop_v1.CalculatePromotionRequest) money.Money {
+	if subtotal, ok := money.FromProto(req.GetSubtotal()); ok {
+		return subtotal
+	}
+	return money.FromMajor(float64(req.GetTotalAmount()), "")
+}
+
+// GetShopAddress trả về địa chỉ lấy hàng của shop, dùng để tính phí vận chuyển theo khoảng cách.
+// Shop hoặc địa chỉ không tồn tại thì trả về found = false.
+func (s *Server) GetShopAddress(ctx context.Context, req *shop_v1.GetShopAddressRequest) (*shop_v1.GetShopAddressResponse, error) {
+	log.Printf("Received GetShopAddress request for ShopID: %s", req.GetShopId())
+
+	shopID, err := uuid.Parse(req.GetShopId())
+	if err != nil {
+		log.Printf("Invalid ShopID format: %s", req.GetShopId())
+		return nil, fmt.Errorf("invalid shop ID format: %w", err)
+	}
+
+	shopInfo, err := s.shopRepo.GetShopByID(ctx, shopID.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &shop_v1.GetShopAddressResponse{Found: false}, nil
+		}
+		log.Printf("Error retrieving shop with ID %s: %v", shopID, err)
+		return nil, fmt.Errorf("error retrieving shop: %w", err)
+	}
+
+	address, err := s.shopRepo.GetShopAddress(ctx, shopInfo.AddressID.String())
+	if err != nil {
+		log.Printf("Error retrieving address of shop %s: %v", shopID, err)
+		return nil, fmt.Errorf("error retrieving shop address: %w", err)
+	}
+
+	if address == nil {
+		log.Printf("Address of shop %s not found", shopID)
+		return &shop_v1.GetShopAddressResponse{Found: false}, nil
+	}
+
+	return &shop_v1.GetShopAddressResponse{
+		Found:   true,
+		Address: toProtoShopAddress(address),
+	}, nil
+}
+
+// GetShopAddresses trả về địa chỉ lấy hàng của nhiều shop trong một lần gọi, dùng khi tìm đơn cho shipper.
+// Shop không tồn tại hoặc chưa có địa chỉ thì bị bỏ qua.
+func (s *Server) GetShopAddresses(ctx context.Context, req *shop_v1.GetShopAddressesRequest) (*shop_v1.GetShopAddressesResponse, error) {
+	log.Printf("Received GetShopAddresses request for %d shops", len(req.GetShopIds()))
+
+	shopIDs := make([]string, 0, len(req.GetShopIds()))
+	for _, id := range req.GetShopIds() {
+		shopID, err := uuid.Parse(id)
+		if err != nil {
+			log.Printf("Invalid ShopID format: %s", id)
+			return nil, fmt.Errorf("invalid shop ID format: %w", err)
+		}
+		shopIDs = append(shopIDs, shopID.String())
+	}
+	if len(shopIDs) == 0 {
+		return &shop_v1.GetShopAddressesResponse{}, nil
+	}
+
+	addresses, err := s.shopRepo.GetShopAddresses(ctx, shopIDs)
+	if err != nil {
+		log.Printf("Error retrieving addresses of %d shops: %v", len(shopIDs), err)
+		return nil, fmt.Errorf("error retrieving shop addresses: %w", err)
+	}
+
+	result := make([]*shop_v1.ShopPickupAddress, len(addresses))
+	for i, address := range addresses {
+		result[i] = &shop_v1.ShopPickupAddress{
+			ShopId:  address.ShopID.String(),
+			Address: toProtoShopAddress(address),
+		}
+	}
+
+	return &shop_v1.GetShopAddressesResponse{Addresses: result}, nil
+}
+
+// GetShopInfo trả về thông tin liên hệ và địa chỉ của shop, dùng cho hoá đơn của đơn hàng.
+// Shop không tồn tại thì trả về found = false, shop chưa có địa chỉ thì address để trống.
+func (s *Server) GetShopInfo(ctx context.Context, req *shop_v1.GetShopInfoRequest) (*shop_v1.GetShopInfoResponse, error) {
+	log.Printf("Received GetShopInfo request for ShopID: %s", req.GetShopId())
+
+	shopID, err := uuid.Parse(req.GetShopId())
+	if err != nil {
+		log.Printf("Invalid ShopID format: %s", req.GetShopId())
+		return nil, fmt.Errorf("invalid shop ID format: %w", err)
+	}
+
+	shopInfo, err := s.shopRepo.GetShopByID(ctx, shopID.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &shop_v1.GetShopInfoResponse{Found: false}, nil
+		}
+		log.Printf("Error retrieving shop with ID %s: %v", shopID, err)
+		return nil, fmt.Errorf("error retrieving shop: %w", err)
+	}
+
+	if shopInfo == nil {
+		return &shop_v1.GetShopInfoResponse{Found: false}, nil
+	}
+
+	shop := &shop_v1.ShopInfo{
+		ShopId:   shopInfo.ID.String(),
+		ShopName: shopInfo.ShopName,
+		Phone:    shopInfo.Phone,
+		Email:    shopInfo.Email,
+	}
+
+	address, err := s.shopRepo.GetShopAddress(ctx, shopInfo.AddressID.String())
+	if err != nil {
+		log.Printf("Error retrieving address of shop %s: %v", shopID, err)
+		return nil, fmt.Errorf("error retrieving shop address: %w", err)
+	}
+
+	if address != nil {
+		shop.Address = toProtoShopAddress(address)
+	}
+
+	return &shop_v1.GetShopInfoResponse{
+		Found: true,
+		Shop:  shop,
+	}, nil
+}
+
+func toProtoShopAddress(address *domain.Address) *shop_v1.ShopAddress {
+	shopAddress := &shop_v1.ShopAddress{
+		Street:         address.Street,
+		Ward:           address.Ward,
+		District:       address.District,
+		City:           address.City,
+		Country:        address.Country,
+		HasCoordinates: address.HasCoordinates(),
+	}
+	if address.HasCoordinates() {
+		shopAddress.Lat = *address.Lat
+		shopAddress.Long = *address.Long
+	}
+	return shopAddress
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/toji-dev/go-shop/internal/pkg/converter"
 	postgresql_infra "github.com/toji-dev/go-shop/internal/pkg/infra/postgreql-infra"
@@ -133,6 +135,56 @@ func (r *PostgresShopRepository) GetShopsByOwnerID(ctx context.Context, ownerID
 	return result, nil
 }
 
+// GetShopAddress retrieves the pickup address of a shop, returns nil if the address does not exist
+func (r *PostgresShopRepository) GetShopAddress(ctx context.Context, addressID string) (*domain.Address, error) {
+	address, err := r.queries.GetShopAddressByID(ctx, converter.StringToPgUUID(addressID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		log.Println("Error fetching shop address by ID:", err)
+		return nil, fmt.Errorf("failed to get shop address by ID: %w", err)
+	}
+
+	return toDomainAddress(&address), nil
+}
+
+// GetShopAddresses retrieves the pickup addresses of several shops in one query
+func (r *PostgresShopRepository) GetShopAddresses(ctx context.Context, shopIDs []string) ([]*domain.Address, error) {
+	ids := make([]pgtype.UUID, len(shopIDs))
+	for i, shopID := range shopIDs {
+		ids[i] = converter.StringToPgUUID(shopID)
+	}
+
+	addresses, err := r.queries.GetShopAddressesByShopIDs(ctx, ids)
+	if err != nil {
+		log.Println("Error fetching shop addresses by shop IDs:", err)
+		return nil, fmt.Errorf("failed to get shop addresses by shop IDs: %w", err)
+	}
+
+	result := make([]*domain.Address, len(addresses))
+	for i := range addresses {
+		result[i] = toDomainAddress(&addresses[i])
+	}
+	return result, nil
+}
+
+func toDomainAddress(address *sqlc.Address) *domain.Address {
+	return &domain.Address{
+		ID:        converter.PgUUIDToUUID(address.ID),
+		ShopID:    converter.PgUUIDToUUID(address.ShopID),
+		Street:    address.Street,
+		Ward:      address.Ward.String,
+		District:  address.District.String,
+		City:      address.City.String,
+		Country:   address.Country.String,
+		Lat:       converter.PgFloat8ToFloat64Ptr(address.Lat),
+		Long:      converter.PgFloat8ToFloat64Ptr(address.Long),
+		CreatedAt: address.CreatedAt.Time,
+		UpdatedAt: address.UpdatedAt.Time,
+	}
+}
+
 // Update updates an existing shop
 func (r *PostgresShopRepository) Update(ctx context.Context, shop *domain.Shop) error {
 	var rating pgtype.Numeric
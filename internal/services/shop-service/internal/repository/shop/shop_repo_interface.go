@@ -11,6 +11,9 @@ type ShopRepository interface {
 	Create(ctx context.Context, shop *domain.Shop) error
 	GetShopByID(ctx context.Context, shopID string) (*domain.Shop, error)
 	GetShopsByOwnerID(ctx context.Context, ownerID string) ([]*domain.Shop, error)
+	GetShopAddress(ctx context.Context, addressID string) (*domain.Address, error)
+	// GetShopAddresses returns the pickup addresses of the given shops, shops without an address are omitted
+	GetShopAddresses(ctx context.Context, shopIDs []string) ([]*domain.Address, error)
 	Update(ctx context.Context, shop *domain.Shop) error
 	Delete(ctx context.Context, shopID string) error
 }
@@ -132,7 +132,7 @@ func runGrpcServer(cfg *config.Config, serviceContainer *container.ServiceContai
 		),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
-	grpcServer := user_grpc.NewUserGRPCServer(serviceContainer.GetAddressRepo())
+	grpcServer := user_grpc.NewUserGRPCServer(serviceContainer.GetAddressRepo(), serviceContainer.GetUserProfileRepo())
 	user_v1.RegisterUserServiceServer(s, grpcServer)
 	log.Printf("gRPC server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
@@ -13,11 +13,13 @@ import (
 type Server struct {
 	user_v1.UnimplementedUserServiceServer
 	addressRepo repository.AddressRepository
+	profileRepo repository.UserProfileRepository
 }
 
-func NewUserGRPCServer(addressRepo repository.AddressRepository) *Server {
+func NewUserGRPCServer(addressRepo repository.AddressRepository, profileRepo repository.UserProfileRepository) *Server {
 	return &Server{
 		addressRepo: addressRepo,
+		profileRepo: profileRepo,
 	}
 }
 
@@ -49,6 +51,15 @@ func (s *Server) GetAddressById(ctx context.Context, req *user_v1.GetAddressRequ
 		UpdatedAt: timestamppb.New(address.UpdatedAt),
 	}
 
+	// Người nhận là chủ địa chỉ, order-service lưu lại tên và số điện thoại vào snapshot của đơn hàng
+	profile, err := s.profileRepo.GetUserProfileByID(ctx, address.UserID)
+	if err != nil {
+		log.Printf("Error fetching profile of address owner %s: %v", address.UserID, err)
+		return nil, err
+	}
+	addressProto.RecipientName = profile.FullName
+	addressProto.RecipientPhone = profile.Phone
+
 	return &user_v1.GetAddressResponse{
 		Address: addressProto,
 	}, nil
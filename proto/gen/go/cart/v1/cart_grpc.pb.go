@@ -23,6 +23,8 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CartServiceClient interface {
 	GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error)
+	RemoveCartItems(ctx context.Context, in *RemoveCartItemsRequest, opts ...grpc.CallOption) (*RemoveCartItemsResponse, error)
+	AddCartItems(ctx context.Context, in *AddCartItemsRequest, opts ...grpc.CallOption) (*AddCartItemsResponse, error)
 }
 
 type cartServiceClient struct {
@@ -42,11 +44,31 @@ func (c *cartServiceClient) GetCart(ctx context.Context, in *GetCartRequest, opt
 	return out, nil
 }
 
+func (c *cartServiceClient) RemoveCartItems(ctx context.Context, in *RemoveCartItemsRequest, opts ...grpc.CallOption) (*RemoveCartItemsResponse, error) {
+	out := new(RemoveCartItemsResponse)
+	err := c.cc.Invoke(ctx, "/goshop.cart.v1.CartService/RemoveCartItems", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) AddCartItems(ctx context.Context, in *AddCartItemsRequest, opts ...grpc.CallOption) (*AddCartItemsResponse, error) {
+	out := new(AddCartItemsResponse)
+	err := c.cc.Invoke(ctx, "/goshop.cart.v1.CartService/AddCartItems", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CartServiceServer is the server API for CartService service.
 // All implementations must embed UnimplementedCartServiceServer
 // for forward compatibility
 type CartServiceServer interface {
 	GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error)
+	RemoveCartItems(context.Context, *RemoveCartItemsRequest) (*RemoveCartItemsResponse, error)
+	AddCartItems(context.Context, *AddCartItemsRequest) (*AddCartItemsResponse, error)
 	mustEmbedUnimplementedCartServiceServer()
 }
 
@@ -57,6 +79,12 @@ type UnimplementedCartServiceServer struct {
 func (UnimplementedCartServiceServer) GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCart not implemented")
 }
+func (UnimplementedCartServiceServer) RemoveCartItems(context.Context, *RemoveCartItemsRequest) (*RemoveCartItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCartItems not implemented")
+}
+func (UnimplementedCartServiceServer) AddCartItems(context.Context, *AddCartItemsRequest) (*AddCartItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCartItems not implemented")
+}
 func (UnimplementedCartServiceServer) mustEmbedUnimplementedCartServiceServer() {}
 
 // UnsafeCartServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +116,42 @@ func _CartService_GetCart_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_RemoveCartItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCartItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).RemoveCartItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.cart.v1.CartService/RemoveCartItems",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).RemoveCartItems(ctx, req.(*RemoveCartItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_AddCartItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCartItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).AddCartItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.cart.v1.CartService/AddCartItems",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).AddCartItems(ctx, req.(*AddCartItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CartService_ServiceDesc is the grpc.ServiceDesc for CartService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +163,14 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCart",
 			Handler:    _CartService_GetCart_Handler,
 		},
+		{
+			MethodName: "RemoveCartItems",
+			Handler:    _CartService_RemoveCartItems_Handler,
+		},
+		{
+			MethodName: "AddCartItems",
+			Handler:    _CartService_AddCartItems_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cart/v1/cart.proto",
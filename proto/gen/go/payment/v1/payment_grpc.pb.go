@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v6.31.1
+// source: payment/v1/payment.proto
+
+package payment_v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PaymentServiceClient is the client API for PaymentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PaymentServiceClient interface {
+	GetPaymentByOrder(ctx context.Context, in *GetPaymentByOrderRequest, opts ...grpc.CallOption) (*GetPaymentByOrderResponse, error)
+	// Tra cứu payment của nhiều đơn hàng trong một lần gọi, đơn chưa có payment không có trong kết quả.
+	GetPaymentsByOrders(ctx context.Context, in *GetPaymentsByOrdersRequest, opts ...grpc.CallOption) (*GetPaymentsByOrdersResponse, error)
+	RequestRefund(ctx context.Context, in *RequestRefundRequest, opts ...grpc.CallOption) (*RequestRefundResponse, error)
+	// Ghi nhận shipper đã thu tiền mặt của đơn COD, chuyển payment sang SUCCESS. Gọi lại cho cùng đơn không lỗi.
+	ConfirmCashCollected(ctx context.Context, in *ConfirmCashCollectedRequest, opts ...grpc.CallOption) (*ConfirmCashCollectedResponse, error)
+	// Chuyển payment COD chưa thu tiền của đơn bị huỷ sang FAILED. Gọi lại cho cùng đơn không lỗi.
+	CancelCODPayment(ctx context.Context, in *CancelCODPaymentRequest, opts ...grpc.CallOption) (*CancelCODPaymentResponse, error)
+	// Chuyển payment chưa thanh toán (mọi phương thức) của đơn hết hạn thanh toán sang FAILED, trả về FAILED_PRECONDITION
+	// nếu khách đã thanh toán. Kết quả thanh toán đến sau đó được hoàn tiền. Gọi lại cho cùng đơn không lỗi.
+	CancelPendingPayment(ctx context.Context, in *CancelPendingPaymentRequest, opts ...grpc.CallOption) (*CancelPendingPaymentResponse, error)
+}
+
+type paymentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentServiceClient(cc grpc.ClientConnInterface) PaymentServiceClient {
+	return &paymentServiceClient{cc}
+}
+
+func (c *paymentServiceClient) GetPaymentByOrder(ctx context.Context, in *GetPaymentByOrderRequest, opts ...grpc.CallOption) (*GetPaymentByOrderResponse, error) {
+	out := new(GetPaymentByOrderResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/GetPaymentByOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetPaymentsByOrders(ctx context.Context, in *GetPaymentsByOrdersRequest, opts ...grpc.CallOption) (*GetPaymentsByOrdersResponse, error) {
+	out := new(GetPaymentsByOrdersResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/GetPaymentsByOrders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RequestRefund(ctx context.Context, in *RequestRefundRequest, opts ...grpc.CallOption) (*RequestRefundResponse, error) {
+	out := new(RequestRefundResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/RequestRefund", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ConfirmCashCollected(ctx context.Context, in *ConfirmCashCollectedRequest, opts ...grpc.CallOption) (*ConfirmCashCollectedResponse, error) {
+	out := new(ConfirmCashCollectedResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/ConfirmCashCollected", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CancelCODPayment(ctx context.Context, in *CancelCODPaymentRequest, opts ...grpc.CallOption) (*CancelCODPaymentResponse, error) {
+	out := new(CancelCODPaymentResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/CancelCODPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CancelPendingPayment(ctx context.Context, in *CancelPendingPaymentRequest, opts ...grpc.CallOption) (*CancelPendingPaymentResponse, error) {
+	out := new(CancelPendingPaymentResponse)
+	err := c.cc.Invoke(ctx, "/goshop.payment.v1.PaymentService/CancelPendingPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentServiceServer is the server API for PaymentService service.
+// All implementations must embed UnimplementedPaymentServiceServer
+// for forward compatibility
+type PaymentServiceServer interface {
+	GetPaymentByOrder(context.Context, *GetPaymentByOrderRequest) (*GetPaymentByOrderResponse, error)
+	// Tra cứu payment của nhiều đơn hàng trong một lần gọi, đơn chưa có payment không có trong kết quả.
+	GetPaymentsByOrders(context.Context, *GetPaymentsByOrdersRequest) (*GetPaymentsByOrdersResponse, error)
+	RequestRefund(context.Context, *RequestRefundRequest) (*RequestRefundResponse, error)
+	// Ghi nhận shipper đã thu tiền mặt của đơn COD, chuyển payment sang SUCCESS. Gọi lại cho cùng đơn không lỗi.
+	ConfirmCashCollected(context.Context, *ConfirmCashCollectedRequest) (*ConfirmCashCollectedResponse, error)
+	// Chuyển payment COD chưa thu tiền của đơn bị huỷ sang FAILED. Gọi lại cho cùng đơn không lỗi.
+	CancelCODPayment(context.Context, *CancelCODPaymentRequest) (*CancelCODPaymentResponse, error)
+	// Chuyển payment chưa thanh toán (mọi phương thức) của đơn hết hạn thanh toán sang FAILED, trả về FAILED_PRECONDITION
+	// nếu khách đã thanh toán. Kết quả thanh toán đến sau đó được hoàn tiền. Gọi lại cho cùng đơn không lỗi.
+	CancelPendingPayment(context.Context, *CancelPendingPaymentRequest) (*CancelPendingPaymentResponse, error)
+	mustEmbedUnimplementedPaymentServiceServer()
+}
+
+// UnimplementedPaymentServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPaymentServiceServer struct {
+}
+
+func (UnimplementedPaymentServiceServer) GetPaymentByOrder(context.Context, *GetPaymentByOrderRequest) (*GetPaymentByOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaymentByOrder not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetPaymentsByOrders(context.Context, *GetPaymentsByOrdersRequest) (*GetPaymentsByOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaymentsByOrders not implemented")
+}
+func (UnimplementedPaymentServiceServer) RequestRefund(context.Context, *RequestRefundRequest) (*RequestRefundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestRefund not implemented")
+}
+func (UnimplementedPaymentServiceServer) ConfirmCashCollected(context.Context, *ConfirmCashCollectedRequest) (*ConfirmCashCollectedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmCashCollected not implemented")
+}
+func (UnimplementedPaymentServiceServer) CancelCODPayment(context.Context, *CancelCODPaymentRequest) (*CancelCODPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCODPayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) CancelPendingPayment(context.Context, *CancelPendingPaymentRequest) (*CancelPendingPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPendingPayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
+
+// UnsafePaymentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentServiceServer will
+// result in compilation errors.
+type UnsafePaymentServiceServer interface {
+	mustEmbedUnimplementedPaymentServiceServer()
+}
+
+func RegisterPaymentServiceServer(s grpc.ServiceRegistrar, srv PaymentServiceServer) {
+	s.RegisterService(&PaymentService_ServiceDesc, srv)
+}
+
+func _PaymentService_GetPaymentByOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentByOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetPaymentByOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/GetPaymentByOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetPaymentByOrder(ctx, req.(*GetPaymentByOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetPaymentsByOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentsByOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetPaymentsByOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/GetPaymentsByOrders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetPaymentsByOrders(ctx, req.(*GetPaymentsByOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RequestRefund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RequestRefund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/RequestRefund",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RequestRefund(ctx, req.(*RequestRefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ConfirmCashCollected_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmCashCollectedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ConfirmCashCollected(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/ConfirmCashCollected",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ConfirmCashCollected(ctx, req.(*ConfirmCashCollectedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CancelCODPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCODPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CancelCODPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/CancelCODPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CancelCODPayment(ctx, req.(*CancelCODPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CancelPendingPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPendingPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CancelPendingPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.payment.v1.PaymentService/CancelPendingPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CancelPendingPayment(ctx, req.(*CancelPendingPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goshop.payment.v1.PaymentService",
+	HandlerType: (*PaymentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPaymentByOrder",
+			Handler:    _PaymentService_GetPaymentByOrder_Handler,
+		},
+		{
+			MethodName: "GetPaymentsByOrders",
+			Handler:    _PaymentService_GetPaymentsByOrders_Handler,
+		},
+		{
+			MethodName: "RequestRefund",
+			Handler:    _PaymentService_RequestRefund_Handler,
+		},
+		{
+			MethodName: "ConfirmCashCollected",
+			Handler:    _PaymentService_ConfirmCashCollected_Handler,
+		},
+		{
+			MethodName: "CancelCODPayment",
+			Handler:    _PaymentService_CancelCODPayment_Handler,
+		},
+		{
+			MethodName: "CancelPendingPayment",
+			Handler:    _PaymentService_CancelPendingPayment_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "payment/v1/payment.proto",
+}
@@ -28,6 +28,7 @@ type ProductServiceClient interface {
 	GetOrderReservationStatus(ctx context.Context, in *GetOrderReservationStatusRequest, opts ...grpc.CallOption) (*GetOrderReservationStatusResponse, error)
 	GetOrdersReservationStatus(ctx context.Context, in *GetOrdersReservationStatusRequest, opts ...grpc.CallOption) (*GetOrdersReservationStatusResponse, error)
 	UnreserveOrders(ctx context.Context, in *UnreserveOrdersRequest, opts ...grpc.CallOption) (*UnreserveOrdersResponse, error)
+	RestockProducts(ctx context.Context, in *RestockProductsRequest, opts ...grpc.CallOption) (*RestockProductsResponse, error)
 }
 
 type productServiceClient struct {
@@ -92,6 +93,15 @@ func (c *productServiceClient) UnreserveOrders(ctx context.Context, in *Unreserv
 	return out, nil
 }
 
+func (c *productServiceClient) RestockProducts(ctx context.Context, in *RestockProductsRequest, opts ...grpc.CallOption) (*RestockProductsResponse, error) {
+	out := new(RestockProductsResponse)
+	err := c.cc.Invoke(ctx, "/goshop.product.v1.ProductService/RestockProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility
@@ -102,6 +112,7 @@ type ProductServiceServer interface {
 	GetOrderReservationStatus(context.Context, *GetOrderReservationStatusRequest) (*GetOrderReservationStatusResponse, error)
 	GetOrdersReservationStatus(context.Context, *GetOrdersReservationStatusRequest) (*GetOrdersReservationStatusResponse, error)
 	UnreserveOrders(context.Context, *UnreserveOrdersRequest) (*UnreserveOrdersResponse, error)
+	RestockProducts(context.Context, *RestockProductsRequest) (*RestockProductsResponse, error)
 	mustEmbedUnimplementedProductServiceServer()
 }
 
@@ -127,6 +138,9 @@ func (UnimplementedProductServiceServer) GetOrdersReservationStatus(context.Cont
 func (UnimplementedProductServiceServer) UnreserveOrders(context.Context, *UnreserveOrdersRequest) (*UnreserveOrdersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnreserveOrders not implemented")
 }
+func (UnimplementedProductServiceServer) RestockProducts(context.Context, *RestockProductsRequest) (*RestockProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestockProducts not implemented")
+}
 func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 
 // UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -248,6 +262,24 @@ func _ProductService_UnreserveOrders_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_RestockProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestockProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RestockProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.product.v1.ProductService/RestockProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RestockProducts(ctx, req.(*RestockProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -279,6 +311,10 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnreserveOrders",
 			Handler:    _ProductService_UnreserveOrders_Handler,
 		},
+		{
+			MethodName: "RestockProducts",
+			Handler:    _ProductService_RestockProducts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "product/v1/product.proto",
@@ -25,6 +25,9 @@ type ShopServiceClient interface {
 	CheckShopOwnership(ctx context.Context, in *CheckShopOwnershipRequest, opts ...grpc.CallOption) (*CheckShopOwnershipResponse, error)
 	CheckShopExists(ctx context.Context, in *CheckShopExistsRequest, opts ...grpc.CallOption) (*CheckShopExistsResponse, error)
 	CalculatePromotion(ctx context.Context, in *CalculatePromotionRequest, opts ...grpc.CallOption) (*CalculatePromotionResponse, error)
+	GetShopAddress(ctx context.Context, in *GetShopAddressRequest, opts ...grpc.CallOption) (*GetShopAddressResponse, error)
+	GetShopAddresses(ctx context.Context, in *GetShopAddressesRequest, opts ...grpc.CallOption) (*GetShopAddressesResponse, error)
+	GetShopInfo(ctx context.Context, in *GetShopInfoRequest, opts ...grpc.CallOption) (*GetShopInfoResponse, error)
 }
 
 type shopServiceClient struct {
@@ -62,6 +65,33 @@ func (c *shopServiceClient) CalculatePromotion(ctx context.Context, in *Calculat
 	return out, nil
 }
 
+func (c *shopServiceClient) GetShopAddress(ctx context.Context, in *GetShopAddressRequest, opts ...grpc.CallOption) (*GetShopAddressResponse, error) {
+	out := new(GetShopAddressResponse)
+	err := c.cc.Invoke(ctx, "/goshop.shop.v1.ShopService/GetShopAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shopServiceClient) GetShopAddresses(ctx context.Context, in *GetShopAddressesRequest, opts ...grpc.CallOption) (*GetShopAddressesResponse, error) {
+	out := new(GetShopAddressesResponse)
+	err := c.cc.Invoke(ctx, "/goshop.shop.v1.ShopService/GetShopAddresses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shopServiceClient) GetShopInfo(ctx context.Context, in *GetShopInfoRequest, opts ...grpc.CallOption) (*GetShopInfoResponse, error) {
+	out := new(GetShopInfoResponse)
+	err := c.cc.Invoke(ctx, "/goshop.shop.v1.ShopService/GetShopInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShopServiceServer is the server API for ShopService service.
 // All implementations must embed UnimplementedShopServiceServer
 // for forward compatibility
@@ -69,6 +99,9 @@ type ShopServiceServer interface {
 	CheckShopOwnership(context.Context, *CheckShopOwnershipRequest) (*CheckShopOwnershipResponse, error)
 	CheckShopExists(context.Context, *CheckShopExistsRequest) (*CheckShopExistsResponse, error)
 	CalculatePromotion(context.Context, *CalculatePromotionRequest) (*CalculatePromotionResponse, error)
+	GetShopAddress(context.Context, *GetShopAddressRequest) (*GetShopAddressResponse, error)
+	GetShopAddresses(context.Context, *GetShopAddressesRequest) (*GetShopAddressesResponse, error)
+	GetShopInfo(context.Context, *GetShopInfoRequest) (*GetShopInfoResponse, error)
 	mustEmbedUnimplementedShopServiceServer()
 }
 
@@ -85,6 +118,15 @@ func (UnimplementedShopServiceServer) CheckShopExists(context.Context, *CheckSho
 func (UnimplementedShopServiceServer) CalculatePromotion(context.Context, *CalculatePromotionRequest) (*CalculatePromotionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CalculatePromotion not implemented")
 }
+func (UnimplementedShopServiceServer) GetShopAddress(context.Context, *GetShopAddressRequest) (*GetShopAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShopAddress not implemented")
+}
+func (UnimplementedShopServiceServer) GetShopAddresses(context.Context, *GetShopAddressesRequest) (*GetShopAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShopAddresses not implemented")
+}
+func (UnimplementedShopServiceServer) GetShopInfo(context.Context, *GetShopInfoRequest) (*GetShopInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShopInfo not implemented")
+}
 func (UnimplementedShopServiceServer) mustEmbedUnimplementedShopServiceServer() {}
 
 // UnsafeShopServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +194,60 @@ func _ShopService_CalculatePromotion_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShopService_GetShopAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShopAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShopServiceServer).GetShopAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.shop.v1.ShopService/GetShopAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShopServiceServer).GetShopAddress(ctx, req.(*GetShopAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShopService_GetShopAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShopAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShopServiceServer).GetShopAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.shop.v1.ShopService/GetShopAddresses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShopServiceServer).GetShopAddresses(ctx, req.(*GetShopAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShopService_GetShopInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetShopInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShopServiceServer).GetShopInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/goshop.shop.v1.ShopService/GetShopInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShopServiceServer).GetShopInfo(ctx, req.(*GetShopInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShopService_ServiceDesc is the grpc.ServiceDesc for ShopService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -171,6 +267,18 @@ var ShopService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CalculatePromotion",
 			Handler:    _ShopService_CalculatePromotion_Handler,
 		},
+		{
+			MethodName: "GetShopAddress",
+			Handler:    _ShopService_GetShopAddress_Handler,
+		},
+		{
+			MethodName: "GetShopAddresses",
+			Handler:    _ShopService_GetShopAddresses_Handler,
+		},
+		{
+			MethodName: "GetShopInfo",
+			Handler:    _ShopService_GetShopInfo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "shop/v1/shop.proto",